@@ -0,0 +1,268 @@
+// Пакет graphql предоставляет GraphQL API для хранилища задач.
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"skillfactory/30.8.1/pkg/storage"
+	"strconv"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+// Schema - схема GraphQL API.
+// Отметки времени передаются как Float (Unix-время в секундах),
+// так как Int в GraphQL ограничен 32 битами.
+const Schema = `
+	schema {
+		query: Query
+		mutation: Mutation
+	}
+
+	type Query {
+		task(id: ID!): Task
+		tasks(authorId: ID, labelId: ID, limit: Int, offset: Int): [Task!]!
+	}
+
+	type Mutation {
+		createTask(title: String!, content: String!): Task!
+		updateTask(id: ID!, title: String, content: String): Task!
+		deleteTask(id: ID!): Boolean!
+	}
+
+	type Task {
+		id: ID!
+		opened: Float!
+		closed: Float!
+		authorId: ID!
+		assignedId: ID!
+		title: String!
+		content: String!
+		status: Int!
+		priority: Int!
+		version: Int!
+	}
+`
+
+// NewSchema разбирает схему и связывает её с хранилищем задач.
+func NewSchema(store storage.Interface) (*graphql.Schema, error) {
+	return graphql.ParseSchema(Schema, &Resolver{store: store})
+}
+
+// Resolver - корневой обработчик запросов и мутаций.
+type Resolver struct {
+	store storage.Interface
+}
+
+// viewerKey - ключ контекста для ID текущего пользователя.
+type viewerKey struct{}
+
+// WithViewer возвращает контекст с ID текущего пользователя.
+func WithViewer(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, viewerKey{}, userID)
+}
+
+// viewerFrom возвращает ID текущего пользователя из контекста.
+func viewerFrom(ctx context.Context) (int, bool) {
+	id, ok := ctx.Value(viewerKey{}).(int)
+	return id, ok
+}
+
+// Middleware берёт ID текущего пользователя из заголовка X-User-ID
+// и сохраняет его в контексте запроса. Мутация createTask назначает
+// этого пользователя автором задачи.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := r.Header.Get("X-User-ID")
+		if h == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		id, err := strconv.Atoi(h)
+		if err != nil {
+			http.Error(w, "invalid X-User-ID", http.StatusBadRequest)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithViewer(r.Context(), id)))
+	})
+}
+
+// Task возвращает задачу по ID или null, если задачи нет.
+func (r *Resolver) Task(ctx context.Context, args struct{ ID graphql.ID }) (*taskResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := r.store.TaskById(ctx, id)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &taskResolver{t: *t}, nil
+}
+
+// tasksArgs - аргументы запроса tasks.
+type tasksArgs struct {
+	AuthorID *graphql.ID
+	LabelID  *graphql.ID
+	Limit    *int32
+	Offset   *int32
+}
+
+// Tasks возвращает задачи, при необходимости отфильтрованные
+// по автору или метке. Limit и Offset применяются после фильтрации.
+func (r *Resolver) Tasks(ctx context.Context, args tasksArgs) ([]*taskResolver, error) {
+	var (
+		tasks []storage.Task
+		err   error
+	)
+	switch {
+	case args.AuthorID != nil:
+		var id int
+		id, err = parseID(*args.AuthorID)
+		if err != nil {
+			return nil, err
+		}
+		tasks, err = r.store.TasksByAuthor(ctx, id)
+	case args.LabelID != nil:
+		var id int
+		id, err = parseID(*args.LabelID)
+		if err != nil {
+			return nil, err
+		}
+		tasks, err = r.store.TasksByLabel(ctx, id)
+	default:
+		tasks, err = r.store.Tasks(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if args.Offset != nil {
+		off := min(max(int(*args.Offset), 0), len(tasks))
+		tasks = tasks[off:]
+	}
+	if args.Limit != nil {
+		tasks = tasks[:min(max(int(*args.Limit), 0), len(tasks))]
+	}
+
+	res := make([]*taskResolver, 0, len(tasks))
+	for _, t := range tasks {
+		res = append(res, &taskResolver{t: t})
+	}
+	return res, nil
+}
+
+// CreateTask создаёт задачу. Если в контексте есть текущий
+// пользователь, он назначается автором задачи.
+func (r *Resolver) CreateTask(ctx context.Context, args struct{ Title, Content string }) (*taskResolver, error) {
+	id, err := r.store.AddTask(ctx, storage.Task{Title: args.Title, Content: args.Content})
+	if err != nil {
+		return nil, err
+	}
+	t, err := r.store.TaskById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// AddTask не записывает автора, поэтому он назначается отдельно.
+	if viewer, ok := viewerFrom(ctx); ok {
+		t.AuthorID = viewer
+		err = r.store.UpdateTask(ctx, *t)
+		if err != nil {
+			return nil, err
+		}
+		t, err = r.store.TaskById(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &taskResolver{t: *t}, nil
+}
+
+// updateTaskArgs - аргументы мутации updateTask.
+// Незаданные поля не изменяются.
+type updateTaskArgs struct {
+	ID      graphql.ID
+	Title   *string
+	Content *string
+}
+
+// UpdateTask изменяет заголовок и (или) текст задачи.
+func (r *Resolver) UpdateTask(ctx context.Context, args updateTaskArgs) (*taskResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := r.store.TaskById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if args.Title != nil {
+		t.Title = *args.Title
+	}
+	if args.Content != nil {
+		t.Content = *args.Content
+	}
+	err = r.store.UpdateTask(ctx, *t)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err = r.store.TaskById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &taskResolver{t: *t}, nil
+}
+
+// DeleteTask удаляет задачу.
+func (r *Resolver) DeleteTask(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	err = r.store.DeleteTask(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// parseID преобразует ID GraphQL в числовой ID хранилища.
+func parseID(id graphql.ID) (int, error) {
+	n, err := strconv.Atoi(string(id))
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid id %q", storage.ErrInvalidArgument, id)
+	}
+	return n, nil
+}
+
+// formatID преобразует числовой ID хранилища в ID GraphQL.
+func formatID(id int) graphql.ID {
+	return graphql.ID(strconv.Itoa(id))
+}
+
+// taskResolver - обработчик полей типа Task.
+type taskResolver struct {
+	t storage.Task
+}
+
+func (r *taskResolver) ID() graphql.ID         { return formatID(r.t.ID) }
+func (r *taskResolver) Opened() float64        { return float64(r.t.Opened) }
+func (r *taskResolver) Closed() float64        { return float64(r.t.Closed) }
+func (r *taskResolver) AuthorID() graphql.ID   { return formatID(r.t.AuthorID) }
+func (r *taskResolver) AssignedID() graphql.ID { return formatID(r.t.AssignedID) }
+func (r *taskResolver) Title() string          { return r.t.Title }
+func (r *taskResolver) Content() string        { return r.t.Content }
+func (r *taskResolver) Status() int32          { return int32(r.t.Status) }
+func (r *taskResolver) Priority() int32        { return int32(r.t.Priority) }
+func (r *taskResolver) Version() int32         { return int32(r.t.Version) }
@@ -0,0 +1,177 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"skillfactory/30.8.1/pkg/storage/memory"
+	"strings"
+	"testing"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+)
+
+// gqlTask - задача в ответе GraphQL.
+type gqlTask struct {
+	ID       string
+	AuthorID string
+	Title    string
+	Content  string
+	Version  int
+}
+
+// exec выполняет запрос и декодирует данные ответа в v.
+// Ошибки выполнения запроса возвращаются.
+func exec(t *testing.T, ctx context.Context, s *graphql.Schema, query string, vars map[string]any, v any) []error {
+	t.Helper()
+	resp := s.Exec(ctx, query, "", vars)
+	var errs []error
+	for _, e := range resp.Errors {
+		errs = append(errs, e)
+	}
+	if len(errs) == 0 && v != nil {
+		if err := json.Unmarshal(resp.Data, v); err != nil {
+			t.Fatalf("decode %s: %v", resp.Data, err)
+		}
+	}
+	return errs
+}
+
+// mustExec выполняет запрос, который должен завершиться без ошибок.
+func mustExec(t *testing.T, ctx context.Context, s *graphql.Schema, query string, vars map[string]any, v any) {
+	t.Helper()
+	if errs := exec(t, ctx, s, query, vars, v); errs != nil {
+		t.Fatalf("query %q errors = %v", query, errs)
+	}
+}
+
+func TestSchema(t *testing.T) {
+	ctx := context.Background()
+	s := graphql.MustParseSchema(Schema, &Resolver{store: memory.New()})
+
+	var created struct{ CreateTask gqlTask }
+	for _, title := range []string{"first", "second", "third"} {
+		mustExec(t, ctx, s, `
+			mutation($title: String!) {
+				createTask(title: $title, content: "content") { id title content authorId }
+			}
+		`, map[string]any{"title": title}, &created)
+	}
+	if created.CreateTask.Title != "third" || created.CreateTask.Content != "content" ||
+		created.CreateTask.AuthorID != "0" {
+		t.Errorf("createTask = %+v, want third task without author", created.CreateTask)
+	}
+	id := created.CreateTask.ID
+
+	var got struct{ Task *gqlTask }
+	mustExec(t, ctx, s, `query($id: ID!) { task(id: $id) { id title } }`, map[string]any{"id": id}, &got)
+	if got.Task == nil || got.Task.ID != id || got.Task.Title != "third" {
+		t.Errorf("task(%s) = %+v, want third task", id, got.Task)
+	}
+
+	var list struct{ Tasks []gqlTask }
+	mustExec(t, ctx, s, `{ tasks(limit: 1, offset: 1) { title } }`, nil, &list)
+	if len(list.Tasks) != 1 || list.Tasks[0].Title != "second" {
+		t.Errorf("tasks(limit: 1, offset: 1) = %+v, want [second]", list.Tasks)
+	}
+	mustExec(t, ctx, s, `{ tasks(offset: 10) { title } }`, nil, &list)
+	if len(list.Tasks) != 0 {
+		t.Errorf("tasks(offset: 10) = %+v, want none", list.Tasks)
+	}
+	mustExec(t, ctx, s, `{ tasks(authorId: 0) { title } }`, nil, &list)
+	if len(list.Tasks) != 3 {
+		t.Errorf("tasks(authorId: 0) = %+v, want 3 tasks", list.Tasks)
+	}
+	mustExec(t, ctx, s, `{ tasks(labelId: 1) { title } }`, nil, &list)
+	if len(list.Tasks) != 0 {
+		t.Errorf("tasks(labelId: 1) = %+v, want none", list.Tasks)
+	}
+
+	// незаданные поля не изменяются
+	var updated struct{ UpdateTask gqlTask }
+	mustExec(t, ctx, s, `mutation($id: ID!) { updateTask(id: $id, title: "updated") { title content version } }`,
+		map[string]any{"id": id}, &updated)
+	if updated.UpdateTask.Title != "updated" || updated.UpdateTask.Content != "content" {
+		t.Errorf("updateTask = %+v, want updated title and same content", updated.UpdateTask)
+	}
+
+	var deleted struct{ DeleteTask bool }
+	mustExec(t, ctx, s, `mutation($id: ID!) { deleteTask(id: $id) }`, map[string]any{"id": id}, &deleted)
+	if !deleted.DeleteTask {
+		t.Errorf("deleteTask = false, want true")
+	}
+
+	// отсутствующая задача возвращается как null
+	mustExec(t, ctx, s, `query($id: ID!) { task(id: $id) { id } }`, map[string]any{"id": id}, &got)
+	if got.Task != nil {
+		t.Errorf("task(%s) after deleteTask = %+v, want null", id, got.Task)
+	}
+	if errs := exec(t, ctx, s, `mutation($id: ID!) { deleteTask(id: $id) }`, map[string]any{"id": id}, nil); errs == nil {
+		t.Error("deleteTask of deleted task error = nil, want error")
+	}
+	if errs := exec(t, ctx, s, `{ task(id: "abc") { id } }`, nil, nil); errs == nil {
+		t.Error("task(id: abc) error = nil, want error")
+	}
+}
+
+func TestCreateTaskViewer(t *testing.T) {
+	s := graphql.MustParseSchema(Schema, &Resolver{store: memory.New()})
+
+	var created struct{ CreateTask gqlTask }
+	mustExec(t, WithViewer(context.Background(), 5), s,
+		`mutation { createTask(title: "title", content: "content") { authorId } }`, nil, &created)
+	if created.CreateTask.AuthorID != "5" {
+		t.Errorf("createTask authorId = %s, want 5", created.CreateTask.AuthorID)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	s := graphql.MustParseSchema(Schema, &Resolver{store: memory.New()})
+	h := Middleware(&relay.Handler{Schema: s})
+
+	tests := []struct {
+		name       string
+		userID     string
+		wantCode   int
+		wantAuthor string
+	}{
+		{"viewer", "7", http.StatusOK, "7"},
+		{"anonymous", "", http.StatusOK, "0"},
+		{"invalid", "abc", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"query": "mutation { createTask(title: \"t\", content: \"c\") { authorId } }"}`
+			req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+			if tt.userID != "" {
+				req.Header.Set("X-User-ID", tt.userID)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var resp struct {
+				Data struct{ CreateTask gqlTask }
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if got := resp.Data.CreateTask.AuthorID; got != tt.wantAuthor {
+				t.Errorf("createTask authorId = %s, want %s", got, tt.wantAuthor)
+			}
+		})
+	}
+}
+
+func TestNewSchema(t *testing.T) {
+	if _, err := NewSchema(memory.New()); err != nil {
+		t.Errorf("NewSchema() error = %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+// Пакет grpc реализует gRPC-сервис задач TaskService (proto/tasks.proto).
+package grpc
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/api/grpc/pb"
+	"skillfactory/30.8.1/pkg/storage"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Server - реализация pb.TaskServiceServer поверх хранилища задач.
+type Server struct {
+	pb.UnimplementedTaskServiceServer
+	store storage.Interface
+}
+
+// Конструктор, принимает хранилище задач.
+func New(store storage.Interface) *Server {
+	s := Server{store: store}
+	return &s
+}
+
+// GetTask возвращает задачу по ID.
+func (s *Server) GetTask(ctx context.Context, req *pb.GetTaskRequest) (*pb.Task, error) {
+	t, err := s.store.TaskById(ctx, int(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProto(*t), nil
+}
+
+// ListTasks возвращает задачи, при необходимости
+// отфильтрованные по автору или метке.
+func (s *Server) ListTasks(ctx context.Context, req *pb.ListTasksRequest) (*pb.ListTasksResponse, error) {
+	var (
+		tasks []storage.Task
+		err   error
+	)
+	switch {
+	case req.AuthorId != nil:
+		tasks, err = s.store.TasksByAuthor(ctx, int(req.GetAuthorId()))
+	case req.LabelId != nil:
+		tasks, err = s.store.TasksByLabel(ctx, int(req.GetLabelId()))
+	default:
+		tasks, err = s.store.Tasks(ctx)
+	}
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := pb.ListTasksResponse{Tasks: make([]*pb.Task, 0, len(tasks))}
+	for _, t := range tasks {
+		resp.Tasks = append(resp.Tasks, toProto(t))
+	}
+	return &resp, nil
+}
+
+// CreateTask создаёт задачу и возвращает её ID.
+func (s *Server) CreateTask(ctx context.Context, req *pb.CreateTaskRequest) (*pb.CreateTaskResponse, error) {
+	if req.GetTask() == nil {
+		return nil, status.Error(codes.InvalidArgument, "task is required")
+	}
+
+	id, err := s.store.AddTask(ctx, fromProto(req.GetTask()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.CreateTaskResponse{Id: int64(id)}, nil
+}
+
+// UpdateTask изменяет задачу и возвращает её новое состояние.
+func (s *Server) UpdateTask(ctx context.Context, req *pb.UpdateTaskRequest) (*pb.UpdateTaskResponse, error) {
+	if req.GetTask() == nil {
+		return nil, status.Error(codes.InvalidArgument, "task is required")
+	}
+
+	t := fromProto(req.GetTask())
+	err := s.store.UpdateTask(ctx, t)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	updated, err := s.store.TaskById(ctx, t.ID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.UpdateTaskResponse{Task: toProto(*updated)}, nil
+}
+
+// DeleteTask удаляет задачу.
+func (s *Server) DeleteTask(ctx context.Context, req *pb.DeleteTaskRequest) (*emptypb.Empty, error) {
+	err := s.store.DeleteTask(ctx, int(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// toStatus преобразует ошибку хранилища в ошибку gRPC с соответствующим кодом.
+func toStatus(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, storage.ErrConflict):
+		code = codes.AlreadyExists
+	case errors.Is(err, storage.ErrVersionConflict), errors.Is(err, storage.ErrLocked):
+		code = codes.Aborted
+	case errors.Is(err, storage.ErrInvalidArgument):
+		code = codes.InvalidArgument
+	case errors.Is(err, storage.ErrForbidden):
+		code = codes.PermissionDenied
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	}
+	return status.Error(code, err.Error())
+}
+
+// toProto преобразует задачу хранилища в сообщение gRPC.
+func toProto(t storage.Task) *pb.Task {
+	p := pb.Task{
+		Id:         int64(t.ID),
+		Opened:     t.Opened,
+		Closed:     t.Closed,
+		AuthorId:   int64(t.AuthorID),
+		AssignedId: int64(t.AssignedID),
+		Title:      t.Title,
+		Content:    t.Content,
+		Status:     int32(t.Status),
+		Priority:   int32(t.Priority),
+		DueAt:      t.DueAt,
+		Metadata:   t.Metadata,
+		Version:    int64(t.Version),
+	}
+	if t.ProjectID != nil {
+		id := int64(*t.ProjectID)
+		p.ProjectId = &id
+	}
+	return &p
+}
+
+// fromProto преобразует сообщение gRPC в задачу хранилища.
+func fromProto(p *pb.Task) storage.Task {
+	t := storage.Task{
+		ID:         int(p.GetId()),
+		Opened:     p.GetOpened(),
+		Closed:     p.GetClosed(),
+		AuthorID:   int(p.GetAuthorId()),
+		AssignedID: int(p.GetAssignedId()),
+		Title:      p.GetTitle(),
+		Content:    p.GetContent(),
+		Status:     storage.Status(p.GetStatus()),
+		Priority:   storage.Priority(p.GetPriority()),
+		DueAt:      p.DueAt,
+		Metadata:   p.GetMetadata(),
+		Version:    int(p.GetVersion()),
+	}
+	if p.ProjectId != nil {
+		id := int(p.GetProjectId())
+		t.ProjectID = &id
+	}
+	return t
+}
@@ -0,0 +1,211 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"skillfactory/30.8.1/pkg/api/grpc/pb"
+	"skillfactory/30.8.1/pkg/storage"
+	"skillfactory/30.8.1/pkg/storage/memory"
+	"skillfactory/30.8.1/pkg/storage/mock"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newClient запускает сервер поверх хранилища store в памяти
+// процесса и возвращает подключённого к нему клиента.
+func newClient(t *testing.T, store storage.Interface) pb.TaskServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	pb.RegisterTaskServiceServer(srv, New(store))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewTaskServiceClient(conn)
+}
+
+func TestServer(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, memory.New())
+
+	list, err := c.ListTasks(ctx, &pb.ListTasksRequest{})
+	if err != nil {
+		t.Fatalf("ListTasks() error = %v", err)
+	}
+	if len(list.GetTasks()) != 0 {
+		t.Fatalf("ListTasks() on empty store = %v, want none", list.GetTasks())
+	}
+
+	created, err := c.CreateTask(ctx, &pb.CreateTaskRequest{Task: &pb.Task{
+		Title:    "title",
+		Content:  "content",
+		Metadata: map[string]string{"env": "prod"},
+	}})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+	id := created.GetId()
+
+	task, err := c.GetTask(ctx, &pb.GetTaskRequest{Id: id})
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	if task.GetId() != id || task.GetTitle() != "title" || task.GetContent() != "content" ||
+		task.GetMetadata()["env"] != "prod" || task.ProjectId != nil {
+		t.Errorf("GetTask() = %v, want created task", task)
+	}
+
+	task.Title = "updated"
+	updated, err := c.UpdateTask(ctx, &pb.UpdateTaskRequest{Task: task})
+	if err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if updated.GetTask().GetTitle() != "updated" || updated.GetTask().GetVersion() <= task.GetVersion() {
+		t.Errorf("UpdateTask() = %v, want updated task with new version", updated.GetTask())
+	}
+
+	// изменение устаревшей версии задачи
+	_, err = c.UpdateTask(ctx, &pb.UpdateTaskRequest{Task: task})
+	if status.Code(err) != codes.Aborted {
+		t.Errorf("UpdateTask() with stale version code = %v, want Aborted", status.Code(err))
+	}
+
+	list, err = c.ListTasks(ctx, &pb.ListTasksRequest{})
+	if err != nil {
+		t.Fatalf("ListTasks() error = %v", err)
+	}
+	if len(list.GetTasks()) != 1 || list.GetTasks()[0].GetTitle() != "updated" {
+		t.Errorf("ListTasks() = %v, want updated task", list.GetTasks())
+	}
+
+	if _, err = c.DeleteTask(ctx, &pb.DeleteTaskRequest{Id: id}); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	_, err = c.GetTask(ctx, &pb.GetTaskRequest{Id: id})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("GetTask() after DeleteTask code = %v, want NotFound", status.Code(err))
+	}
+	_, err = c.DeleteTask(ctx, &pb.DeleteTaskRequest{Id: id})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("DeleteTask() again code = %v, want NotFound", status.Code(err))
+	}
+}
+
+func TestListTasksFilter(t *testing.T) {
+	ctx := context.Background()
+	var author, label int
+	c := newClient(t, &mock.Mock{
+		TasksByAuthorFunc: func(ctx context.Context, authorId int) ([]storage.Task, error) {
+			author = authorId
+			return []storage.Task{{ID: 1}}, nil
+		},
+		TasksByLabelFunc: func(ctx context.Context, labelId int) ([]storage.Task, error) {
+			label = labelId
+			return []storage.Task{{ID: 2}}, nil
+		},
+	})
+
+	authorID, labelID := int64(7), int64(9)
+	tests := []struct {
+		req    *pb.ListTasksRequest
+		wantID int64
+	}{
+		{&pb.ListTasksRequest{AuthorId: &authorID}, 1},
+		{&pb.ListTasksRequest{LabelId: &labelID}, 2},
+	}
+	for _, tt := range tests {
+		resp, err := c.ListTasks(ctx, tt.req)
+		if err != nil {
+			t.Fatalf("ListTasks(%v) error = %v", tt.req, err)
+		}
+		if len(resp.GetTasks()) != 1 || resp.GetTasks()[0].GetId() != tt.wantID {
+			t.Errorf("ListTasks(%v) = %v, want task %d", tt.req, resp.GetTasks(), tt.wantID)
+		}
+	}
+	if author != 7 || label != 9 {
+		t.Errorf("filters passed to storage = author %d, label %d, want 7 and 9", author, label)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{"not found", storage.ErrNotFound, codes.NotFound},
+		{"conflict", storage.ErrConflict, codes.AlreadyExists},
+		{"version conflict", storage.ErrVersionConflict, codes.Aborted},
+		{"invalid argument", storage.ErrInvalidArgument, codes.InvalidArgument},
+		{"forbidden", storage.ErrForbidden, codes.PermissionDenied},
+		{"internal", errors.New("db unavailable"), codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newClient(t, &mock.Mock{
+				AddTaskFunc: func(ctx context.Context, task storage.Task) (int, error) {
+					return 0, tt.err
+				},
+			})
+			_, err := c.CreateTask(ctx, &pb.CreateTaskRequest{Task: &pb.Task{Title: "task"}})
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("CreateTask() code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	c := newClient(t, &mock.Mock{})
+	if _, err := c.CreateTask(ctx, &pb.CreateTaskRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("CreateTask() without task code = %v, want InvalidArgument", status.Code(err))
+	}
+	if _, err := c.UpdateTask(ctx, &pb.UpdateTaskRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("UpdateTask() without task code = %v, want InvalidArgument", status.Code(err))
+	}
+}
+
+// Преобразование задачи в сообщение gRPC и обратно не теряет данных.
+func TestProtoRoundTrip(t *testing.T) {
+	due := int64(1700000000)
+	project := 3
+	want := storage.Task{
+		ID:         1,
+		Opened:     100,
+		Closed:     200,
+		AuthorID:   2,
+		AssignedID: 3,
+		Title:      "title",
+		Content:    "content",
+		Status:     storage.StatusDone,
+		Priority:   storage.PriorityHigh,
+		DueAt:      &due,
+		ProjectID:  &project,
+		Metadata:   map[string]string{"k": "v"},
+		Version:    4,
+	}
+	got := fromProto(toProto(want))
+	if got.ID != want.ID || got.Opened != want.Opened || got.Closed != want.Closed ||
+		got.AuthorID != want.AuthorID || got.AssignedID != want.AssignedID ||
+		got.Title != want.Title || got.Content != want.Content ||
+		got.Status != want.Status || got.Priority != want.Priority ||
+		*got.DueAt != due || *got.ProjectID != project ||
+		got.Metadata["k"] != "v" || got.Version != want.Version {
+		t.Errorf("fromProto(toProto()) = %+v, want %+v", got, want)
+	}
+}
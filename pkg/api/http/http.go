@@ -0,0 +1,212 @@
+// Пакет http предоставляет REST API для хранилища задач.
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"skillfactory/30.8.1/pkg/storage"
+	"strconv"
+)
+
+// Handler - обработчик HTTP-запросов к хранилищу задач.
+//
+// Маршруты:
+//
+//	GET    /tasks                  - все задачи
+//	GET    /tasks?author_id=N      - задачи автора
+//	GET    /tasks?label_id=N       - задачи с меткой
+//	GET    /tasks/{id}             - задача по ID
+//	POST   /tasks                  - создание задачи
+//	PUT    /tasks/{id}             - изменение задачи
+//	DELETE /tasks/{id}             - удаление задачи
+type Handler struct {
+	store storage.Interface
+	mux   *http.ServeMux
+}
+
+// Конструктор, принимает хранилище задач.
+func New(store storage.Interface) *Handler {
+	h := Handler{
+		store: store,
+		mux:   http.NewServeMux(),
+	}
+	h.mux.HandleFunc("GET /tasks", h.tasks)
+	h.mux.HandleFunc("GET /tasks/{id}", h.task)
+	h.mux.HandleFunc("POST /tasks", h.addTask)
+	h.mux.HandleFunc("PUT /tasks/{id}", h.updateTask)
+	h.mux.HandleFunc("DELETE /tasks/{id}", h.deleteTask)
+	return &h
+}
+
+// ServeHTTP передаёт запрос обработчику маршрута.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// errorResponse - тело ответа с ошибкой.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// tasks возвращает список задач, при необходимости
+// отфильтрованный по автору или метке.
+func (h *Handler) tasks(w http.ResponseWriter, r *http.Request) {
+	var (
+		tasks []storage.Task
+		err   error
+	)
+	q := r.URL.Query()
+	switch {
+	case q.Has("author_id"):
+		var id int
+		id, err = strconv.Atoi(q.Get("author_id"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid author_id")
+			return
+		}
+		tasks, err = h.store.TasksByAuthor(r.Context(), id)
+	case q.Has("label_id"):
+		var id int
+		id, err = strconv.Atoi(q.Get("label_id"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid label_id")
+			return
+		}
+		tasks, err = h.store.TasksByLabel(r.Context(), id)
+	default:
+		tasks, err = h.store.Tasks(r.Context())
+	}
+	if err != nil {
+		writeStorageError(w, err)
+		return
+	}
+
+	// пустой список кодируется как [], а не null
+	if tasks == nil {
+		tasks = []storage.Task{}
+	}
+	writeJSON(w, http.StatusOK, tasks)
+}
+
+// task возвращает задачу по ID.
+func (h *Handler) task(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	t, err := h.store.TaskById(r.Context(), id)
+	if err != nil {
+		writeStorageError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, t)
+}
+
+// addTask создаёт задачу и возвращает её.
+func (h *Handler) addTask(w http.ResponseWriter, r *http.Request) {
+	var t storage.Task
+	err := json.NewDecoder(r.Body).Decode(&t)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	id, err := h.store.AddTask(r.Context(), t)
+	if err != nil {
+		writeStorageError(w, err)
+		return
+	}
+	created, err := h.store.TaskById(r.Context(), id)
+	if err != nil {
+		writeStorageError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// updateTask изменяет задачу. ID задачи берётся из пути,
+// а не из тела запроса.
+func (h *Handler) updateTask(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var t storage.Task
+	err := json.NewDecoder(r.Body).Decode(&t)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	t.ID = id
+
+	err = h.store.UpdateTask(r.Context(), t)
+	if err != nil {
+		writeStorageError(w, err)
+		return
+	}
+	updated, err := h.store.TaskById(r.Context(), id)
+	if err != nil {
+		writeStorageError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// deleteTask удаляет задачу.
+func (h *Handler) deleteTask(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.store.DeleteTask(r.Context(), id)
+	if err != nil {
+		writeStorageError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// pathID возвращает ID задачи из пути запроса.
+// Если ID некорректен, отвечает 400 и возвращает false.
+func pathID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid task id")
+		return 0, false
+	}
+	return id, true
+}
+
+// writeStorageError отвечает кодом, соответствующим ошибке хранилища.
+func writeStorageError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, storage.ErrConflict), errors.Is(err, storage.ErrVersionConflict):
+		code = http.StatusConflict
+	case errors.Is(err, storage.ErrInvalidArgument):
+		code = http.StatusBadRequest
+	case errors.Is(err, storage.ErrForbidden):
+		code = http.StatusForbidden
+	case errors.Is(err, storage.ErrLocked):
+		code = http.StatusLocked
+	}
+	writeError(w, code, err.Error())
+}
+
+// writeError отвечает кодом code и описанием ошибки в JSON.
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, errorResponse{Error: msg})
+}
+
+// writeJSON отвечает кодом code и значением v в JSON.
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
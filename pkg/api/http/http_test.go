@@ -0,0 +1,219 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"skillfactory/30.8.1/pkg/storage"
+	"skillfactory/30.8.1/pkg/storage/memory"
+	"skillfactory/30.8.1/pkg/storage/mock"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// serve выполняет запрос к обработчику. Тело body, если задано,
+// кодируется в JSON, а строка передаётся как есть.
+func serve(t *testing.T, h http.Handler, method, target string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	var buf bytes.Buffer
+	switch b := body.(type) {
+	case nil:
+	case string:
+		buf.WriteString(b)
+	default:
+		if err := json.NewEncoder(&buf).Encode(b); err != nil {
+			t.Fatalf("encode request body: %v", err)
+		}
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, target, &buf))
+
+	if rec.Code != http.StatusNoContent {
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("%s %s Content-Type = %q, want application/json", method, target, ct)
+		}
+	}
+	return rec
+}
+
+// decode декодирует тело ответа в v.
+func decode(t *testing.T, rec *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	if err := json.NewDecoder(rec.Body).Decode(v); err != nil {
+		t.Fatalf("decode response body %q: %v", rec.Body.String(), err)
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	h := New(memory.New())
+
+	rec := serve(t, h, http.MethodGet, "/tasks", nil)
+	if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != "[]" {
+		t.Fatalf("GET /tasks on empty store = %d %q, want 200 []", rec.Code, rec.Body.String())
+	}
+
+	rec = serve(t, h, http.MethodPost, "/tasks", storage.Task{Title: "title", Content: "content"})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST /tasks = %d %s, want 201", rec.Code, rec.Body.String())
+	}
+	var created storage.Task
+	decode(t, rec, &created)
+	if created.ID == 0 || created.Title != "title" || created.Content != "content" {
+		t.Errorf("POST /tasks = %+v, want created task", created)
+	}
+	target := "/tasks/" + strconv.Itoa(created.ID)
+
+	rec = serve(t, h, http.MethodGet, target, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s = %d, want 200", target, rec.Code)
+	}
+	var got storage.Task
+	decode(t, rec, &got)
+	if got.ID != created.ID || got.Title != "title" {
+		t.Errorf("GET %s = %+v, want %+v", target, got, created)
+	}
+
+	// ID задачи берётся из пути, а не из тела запроса
+	update := created
+	update.ID = 0
+	update.Title = "updated"
+	rec = serve(t, h, http.MethodPut, target, update)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PUT %s = %d %s, want 200", target, rec.Code, rec.Body.String())
+	}
+	decode(t, rec, &got)
+	if got.ID != created.ID || got.Title != "updated" {
+		t.Errorf("PUT %s = %+v, want updated task", target, got)
+	}
+
+	// изменение устаревшей версии задачи
+	rec = serve(t, h, http.MethodPut, target, update)
+	if rec.Code != http.StatusConflict {
+		t.Errorf("PUT %s with stale version = %d, want 409", target, rec.Code)
+	}
+
+	rec = serve(t, h, http.MethodGet, "/tasks", nil)
+	var tasks []storage.Task
+	decode(t, rec, &tasks)
+	if len(tasks) != 1 || tasks[0].Title != "updated" {
+		t.Errorf("GET /tasks = %+v, want updated task", tasks)
+	}
+
+	rec = serve(t, h, http.MethodDelete, target, nil)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("DELETE %s = %d, want 204", target, rec.Code)
+	}
+
+	rec = serve(t, h, http.MethodGet, target, nil)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("GET %s after DELETE = %d, want 404", target, rec.Code)
+	}
+	var e errorResponse
+	decode(t, rec, &e)
+	if e.Error == "" {
+		t.Errorf("GET %s after DELETE error body is empty", target)
+	}
+	rec = serve(t, h, http.MethodDelete, target, nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("DELETE %s again = %d, want 404", target, rec.Code)
+	}
+}
+
+func TestTasksFilter(t *testing.T) {
+	var author, label int
+	h := New(&mock.Mock{
+		TasksByAuthorFunc: func(ctx context.Context, authorId int) ([]storage.Task, error) {
+			author = authorId
+			return []storage.Task{{ID: 1}}, nil
+		},
+		TasksByLabelFunc: func(ctx context.Context, labelId int) ([]storage.Task, error) {
+			label = labelId
+			return []storage.Task{{ID: 2}}, nil
+		},
+	})
+
+	tests := []struct {
+		target   string
+		wantCode int
+		wantID   int
+	}{
+		{"/tasks?author_id=7", http.StatusOK, 1},
+		{"/tasks?label_id=9", http.StatusOK, 2},
+		{"/tasks?author_id=x", http.StatusBadRequest, 0},
+		{"/tasks?label_id=", http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		rec := serve(t, h, http.MethodGet, tt.target, nil)
+		if rec.Code != tt.wantCode {
+			t.Errorf("GET %s = %d, want %d", tt.target, rec.Code, tt.wantCode)
+			continue
+		}
+		if tt.wantCode != http.StatusOK {
+			continue
+		}
+		var tasks []storage.Task
+		decode(t, rec, &tasks)
+		if len(tasks) != 1 || tasks[0].ID != tt.wantID {
+			t.Errorf("GET %s = %+v, want task %d", tt.target, tasks, tt.wantID)
+		}
+	}
+	if author != 7 || label != 9 {
+		t.Errorf("filters passed to storage = author %d, label %d, want 7 and 9", author, label)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		method   string
+		target   string
+		body     any
+		wantCode int
+	}{
+		{"not found", storage.ErrNotFound, http.MethodGet, "/tasks/1", nil, http.StatusNotFound},
+		{"conflict", storage.ErrConflict, http.MethodPost, "/tasks", storage.Task{}, http.StatusConflict},
+		{"version conflict", storage.ErrVersionConflict, http.MethodPut, "/tasks/1", storage.Task{}, http.StatusConflict},
+		{"invalid argument", storage.ErrInvalidArgument, http.MethodPost, "/tasks", storage.Task{}, http.StatusBadRequest},
+		{"wrapped not found", errors.Join(errors.New("tx"), storage.ErrNotFound), http.MethodDelete, "/tasks/1", nil, http.StatusNotFound},
+		{"internal", errors.New("db unavailable"), http.MethodGet, "/tasks", nil, http.StatusInternalServerError},
+		{"invalid id", nil, http.MethodGet, "/tasks/abc", nil, http.StatusBadRequest},
+		{"invalid body", nil, http.MethodPost, "/tasks", "{", http.StatusBadRequest},
+		{"invalid update body", nil, http.MethodPut, "/tasks/1", "[]", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(&mock.Mock{
+				TasksFunc: func(ctx context.Context) ([]storage.Task, error) {
+					return nil, tt.err
+				},
+				TaskByIdFunc: func(ctx context.Context, taskId int) (*storage.Task, error) {
+					return nil, tt.err
+				},
+				AddTaskFunc: func(ctx context.Context, task storage.Task) (int, error) {
+					return 0, tt.err
+				},
+				UpdateTaskFunc: func(ctx context.Context, task storage.Task) error {
+					return tt.err
+				},
+				DeleteTaskFunc: func(ctx context.Context, taskId int) error {
+					return tt.err
+				},
+			})
+
+			rec := serve(t, h, tt.method, tt.target, tt.body)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("%s %s = %d, want %d", tt.method, tt.target, rec.Code, tt.wantCode)
+			}
+			var e errorResponse
+			decode(t, rec, &e)
+			if e.Error == "" {
+				t.Errorf("%s %s error body is empty", tt.method, tt.target)
+			}
+		})
+	}
+}
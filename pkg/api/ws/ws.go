@@ -0,0 +1,211 @@
+// Пакет ws рассылает клиентам WebSocket события изменения задач.
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"skillfactory/30.8.1/pkg/storage/notify"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// Размер очереди сообщений клиента. Клиент, не успевающий
+	// читать сообщения, отключается.
+	sendBuffer = 64
+
+	// Время ожидания записи сообщения клиенту.
+	writeWait = 10 * time.Second
+
+	// Интервал проверки соединения и время ожидания ответа на неё.
+	pingPeriod = 30 * time.Second
+	pongWait   = 2 * pingPeriod
+)
+
+// client - подключённый клиент.
+type client struct {
+	conn *websocket.Conn
+	send chan []byte
+}
+
+// Hub хранит подключённых клиентов и рассылает им события.
+type Hub struct {
+	mu      sync.Mutex
+	clients map[*client]struct{}
+}
+
+// Конструктор.
+func NewHub() *Hub {
+	h := Hub{clients: make(map[*client]struct{})}
+	return &h
+}
+
+// Run рассылает клиентам события из канала events в формате JSON
+// до отмены ctx или закрытия канала. События можно получать
+// от notify.Notifier или из любой другой шины событий.
+// После завершения все клиенты отключаются.
+func (h *Hub) Run(ctx context.Context, events <-chan notify.TaskEvent) error {
+	defer h.closeAll()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+			msg, err := json.Marshal(e)
+			if err != nil {
+				return err
+			}
+			h.broadcast(msg)
+		}
+	}
+}
+
+// Clients возвращает количество подключённых клиентов.
+func (h *Hub) Clients() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.clients)
+}
+
+// broadcast ставит сообщение в очередь каждого клиента.
+// Клиенты с переполненной очередью отключаются.
+func (h *Hub) broadcast(msg []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for c := range h.clients {
+		select {
+		case c.send <- msg:
+		default:
+			h.remove(c)
+		}
+	}
+}
+
+// register добавляет клиента.
+func (h *Hub) register(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.clients[c] = struct{}{}
+}
+
+// unregister удаляет клиента, если он ещё не удалён.
+func (h *Hub) unregister(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(c)
+}
+
+// remove удаляет клиента и закрывает его очередь, что завершает
+// запись клиенту. Вызывается под h.mu.
+func (h *Hub) remove(c *client) {
+	if _, ok := h.clients[c]; !ok {
+		return
+	}
+	delete(h.clients, c)
+	close(c.send)
+}
+
+// closeAll отключает всех клиентов.
+func (h *Hub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for c := range h.clients {
+		h.remove(c)
+	}
+}
+
+// Handler подключает клиентов WebSocket к Hub.
+// Предназначен для маршрута GET /ws.
+type Handler struct {
+	hub      *Hub
+	upgrader websocket.Upgrader
+}
+
+// Конструктор, принимает Hub для рассылки событий.
+func NewHandler(hub *Hub) *Handler {
+	h := Handler{hub: hub}
+	return &h
+}
+
+// ServeHTTP устанавливает соединение WebSocket и регистрирует клиента.
+// Сообщения от клиента не ожидаются и отбрасываются.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	// при ошибке Upgrade сам отвечает клиенту
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	c := &client{conn: conn, send: make(chan []byte, sendBuffer)}
+	h.hub.register(c)
+
+	go c.writeLoop()
+	c.readLoop()
+	h.hub.unregister(c)
+}
+
+// readLoop читает сообщения клиента до разрыва соединения.
+// Чтение нужно для обработки управляющих сообщений (pong, close).
+func (c *client) readLoop() {
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, _, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+	}
+}
+
+// writeLoop отправляет клиенту сообщения из очереди и проверяет
+// соединение. Завершается и закрывает соединение, когда очередь
+// закрыта или запись не удалась.
+func (c *client) writeLoop() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case msg, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, nil)
+				return
+			}
+			err := c.conn.WriteMessage(websocket.TextMessage, msg)
+			if err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			err := c.conn.WriteMessage(websocket.PingMessage, nil)
+			if err != nil {
+				return
+			}
+		}
+	}
+}
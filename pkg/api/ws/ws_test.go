@@ -0,0 +1,153 @@
+package ws
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"skillfactory/30.8.1/pkg/storage/notify"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// startHub запускает Hub с каналом событий и сервер с обработчиком /ws.
+// Возвращает адрес WebSocket, канал событий и канал результата Run.
+func startHub(t *testing.T, hub *Hub) (string, chan notify.TaskEvent, <-chan error) {
+	t.Helper()
+	events := make(chan notify.TaskEvent)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- hub.Run(ctx, events) }()
+
+	srv := httptest.NewServer(NewHandler(hub))
+	t.Cleanup(func() {
+		cancel()
+		srv.Close()
+	})
+	return "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws", events, done
+}
+
+// dial подключает клиента.
+func dial(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// waitClients ожидает, пока к Hub подключится n клиентов.
+func waitClients(t *testing.T, hub *Hub, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for hub.Clients() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Clients() = %d, want %d", hub.Clients(), n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// readEvent читает событие, отправленное клиенту.
+func readEvent(t *testing.T, conn *websocket.Conn) notify.TaskEvent {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var e notify.TaskEvent
+	if err := conn.ReadJSON(&e); err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	return e
+}
+
+func TestBroadcast(t *testing.T) {
+	hub := NewHub()
+	url, events, _ := startHub(t, hub)
+
+	first, second := dial(t, url), dial(t, url)
+	waitClients(t, hub, 2)
+
+	want := notify.TaskEvent{Op: "INSERT", TaskID: 1}
+	events <- want
+	for i, conn := range []*websocket.Conn{first, second} {
+		if got := readEvent(t, conn); got != want {
+			t.Errorf("client %d event = %+v, want %+v", i+1, got, want)
+		}
+	}
+
+	// отключившийся клиент удаляется, остальные продолжают получать события
+	first.Close()
+	waitClients(t, hub, 1)
+
+	want = notify.TaskEvent{Op: "DELETE", TaskID: 1}
+	events <- want
+	if got := readEvent(t, second); got != want {
+		t.Errorf("event after disconnect = %+v, want %+v", got, want)
+	}
+}
+
+// Клиент, не читающий сообщения, отключается при переполнении
+// очереди и не мешает рассылке остальным.
+func TestSlowClient(t *testing.T) {
+	hub := NewHub()
+	c := &client{send: make(chan []byte, 1)}
+	hub.register(c)
+
+	hub.broadcast([]byte("1"))
+	hub.broadcast([]byte("2"))
+	if hub.Clients() != 0 {
+		t.Fatalf("Clients() = %d, want slow client removed", hub.Clients())
+	}
+	// очередь закрыта, а повторное удаление ничего не делает
+	hub.unregister(c)
+	if msg, ok := <-c.send; !ok || string(msg) != "1" {
+		t.Errorf("queued message = %q, %v, want 1", msg, ok)
+	}
+	if _, ok := <-c.send; ok {
+		t.Error("client queue is not closed")
+	}
+}
+
+// При закрытии канала событий Run завершается и отключает клиентов.
+func TestRunClosed(t *testing.T) {
+	hub := NewHub()
+	url, events, done := startHub(t, hub)
+
+	conn := dial(t, url)
+	waitClients(t, hub, 1)
+	close(events)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run() error = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not return after events channel closed")
+	}
+	if hub.Clients() != 0 {
+		t.Errorf("Clients() after Run = %d, want 0", hub.Clients())
+	}
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, _, err := conn.ReadMessage()
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) {
+		t.Errorf("ReadMessage() after Run error = %v, want close message", err)
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewHandler(NewHub()).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/ws", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST /ws = %d, want 405", rec.Code)
+	}
+	if allow := rec.Header().Get("Allow"); allow != http.MethodGet {
+		t.Errorf("Allow = %q, want GET", allow)
+	}
+}
@@ -0,0 +1,100 @@
+// Пакет events реализует шину событий внутри процесса.
+package events
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// Типы событий, публикуемых storage/evented.
+const (
+	TaskCreated    = "task.created"
+	TaskUpdated    = "task.updated"
+	TaskAssigned   = "task.assigned"
+	TaskDeleted    = "task.deleted"
+	TaskArchived   = "task.archived"
+	TaskUnarchived = "task.unarchived"
+	CommentAdded   = "comment.added"
+	CommentUpdated = "comment.updated"
+	CommentDeleted = "comment.deleted"
+)
+
+// AllEvents - тип подписки, получающей события всех типов.
+const AllEvents = "*"
+
+// Event - событие предметной области.
+type Event struct {
+	Type    string
+	TaskID  int
+	UserID  int
+	Payload interface{}
+}
+
+// Bus рассылает опубликованные события подписчикам.
+// Методы безопасны для одновременного использования.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]func(Event) // тип события -> обработчики
+}
+
+// Конструктор, создаёт шину без подписчиков.
+func NewBus() *Bus {
+	b := Bus{
+		handlers: make(map[string][]func(Event)),
+	}
+	return &b
+}
+
+// Subscribe подписывает handler на события типа eventType
+// (или на все события, если eventType равен AllEvents).
+func (b *Bus) Subscribe(eventType string, handler func(Event)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// Unsubscribe отменяет подписку handler на события типа eventType.
+// Функции в Go нельзя сравнивать, поэтому обработчики сравниваются
+// по адресу кода: замыкания, созданные одним литералом, неразличимы,
+// и отменяется первая подходящая подписка.
+func (b *Bus) Unsubscribe(eventType string, handler func(Event)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ptr := reflect.ValueOf(handler).Pointer()
+	hs := b.handlers[eventType]
+	for i, h := range hs {
+		if reflect.ValueOf(h).Pointer() == ptr {
+			// новый слайс, чтобы не изменять копию, которую обходит Publish
+			b.handlers[eventType] = append(hs[:i:i], hs[i+1:]...)
+			break
+		}
+	}
+	if len(b.handlers[eventType]) == 0 {
+		delete(b.handlers, eventType)
+	}
+}
+
+// Publish синхронно вызывает обработчики события e в порядке подписки.
+// Обработчики не должны блокироваться надолго: длительную работу
+// следует выполнять в отдельной горутине.
+// Возвращает ошибку, если ctx отменён до рассылки.
+func (b *Bus) Publish(ctx context.Context, e Event) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	b.mu.RLock()
+	hs := b.handlers[e.Type]
+	all := b.handlers[AllEvents]
+	b.mu.RUnlock()
+
+	for _, h := range hs {
+		h(e)
+	}
+	for _, h := range all {
+		h(e)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestPublish(t *testing.T) {
+	b := NewBus()
+	var got []string
+	b.Subscribe(TaskCreated, func(e Event) { got = append(got, "created") })
+	b.Subscribe(TaskCreated, func(e Event) { got = append(got, "created 2") })
+	b.Subscribe(TaskDeleted, func(e Event) { got = append(got, "deleted") })
+	b.Subscribe(AllEvents, func(e Event) { got = append(got, "all") })
+
+	err := b.Publish(context.Background(), Event{Type: TaskCreated, TaskID: 1})
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	// обработчики типа в порядке подписки, затем подписчики на все события
+	if want := []string{"created", "created 2", "all"}; !slices.Equal(got, want) {
+		t.Errorf("handlers called = %v, want %v", got, want)
+	}
+
+	got = nil
+	err = b.Publish(context.Background(), Event{Type: TaskUpdated})
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if want := []string{"all"}; !slices.Equal(got, want) {
+		t.Errorf("handlers called = %v, want %v", got, want)
+	}
+}
+
+func TestPublishCanceled(t *testing.T) {
+	b := NewBus()
+	called := false
+	b.Subscribe(TaskCreated, func(e Event) { called = true })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := b.Publish(ctx, Event{Type: TaskCreated})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Publish() error = %v, want context.Canceled", err)
+	}
+	if called {
+		t.Error("handler called for canceled context")
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	b := NewBus()
+	var got []string
+	first := func(e Event) { got = append(got, "first") }
+	second := func(e Event) { got = append(got, "second") }
+	b.Subscribe(TaskCreated, first)
+	b.Subscribe(TaskCreated, second)
+
+	b.Unsubscribe(TaskCreated, first)
+	// неизвестный тип и обработчик не подписанный на тип
+	b.Unsubscribe(TaskDeleted, second)
+
+	err := b.Publish(context.Background(), Event{Type: TaskCreated})
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if want := []string{"second"}; !slices.Equal(got, want) {
+		t.Errorf("handlers called = %v, want %v", got, want)
+	}
+
+	b.Unsubscribe(TaskCreated, second)
+	if len(b.handlers) != 0 {
+		t.Errorf("handlers = %v, want none", b.handlers)
+	}
+}
+
+// Обработчик может отменить свою подписку во время рассылки.
+func TestUnsubscribeInHandler(t *testing.T) {
+	b := NewBus()
+	calls := 0
+	var h func(Event)
+	h = func(e Event) {
+		calls++
+		b.Unsubscribe(TaskCreated, h)
+	}
+	b.Subscribe(TaskCreated, h)
+
+	for range 2 {
+		err := b.Publish(context.Background(), Event{Type: TaskCreated})
+		if err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("handler calls = %d, want 1", calls)
+	}
+}
+
+// Подписка и публикация из разных горутин (проверяется с -race).
+func TestConcurrent(t *testing.T) {
+	b := NewBus()
+	var mu sync.Mutex
+	calls := 0
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			h := func(e Event) {
+				mu.Lock()
+				calls++
+				mu.Unlock()
+			}
+			b.Subscribe(TaskCreated, h)
+			b.Unsubscribe(TaskDeleted, h)
+		}()
+		go func() {
+			defer wg.Done()
+			_ = b.Publish(context.Background(), Event{Type: TaskCreated})
+		}()
+	}
+	wg.Wait()
+
+	calls = 0
+	err := b.Publish(context.Background(), Event{Type: TaskCreated})
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if calls != 10 {
+		t.Errorf("handler calls = %d, want 10", calls)
+	}
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Ключ API имеет вид "<префикс>.<секрет>". Префикс хранится открыто
+// и служит для поиска ключа, а секрет - только в виде хеша bcrypt.
+const (
+	apiKeyPrefixBytes = 8
+	apiKeySecretBytes = 24
+)
+
+// GenerateAPIKey создаёт новый ключ API. Возвращает ключ целиком,
+// его префикс и хеш секрета для хранения.
+// Используется реализациями CreateAPIKey.
+func GenerateAPIKey() (raw, prefix string, hash []byte, err error) {
+	b := make([]byte, apiKeyPrefixBytes+apiKeySecretBytes)
+	_, err = rand.Read(b)
+	if err != nil {
+		return "", "", nil, err
+	}
+	prefix = hex.EncodeToString(b[:apiKeyPrefixBytes])
+	secret := hex.EncodeToString(b[apiKeyPrefixBytes:])
+
+	hash, err = bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return "", "", nil, err
+	}
+	return prefix + "." + secret, prefix, hash, nil
+}
+
+// ParseAPIKey разделяет ключ API на префикс и секрет.
+func ParseAPIKey(raw string) (prefix, secret string, err error) {
+	prefix, secret, ok := strings.Cut(raw, ".")
+	if !ok || len(prefix) != 2*apiKeyPrefixBytes || secret == "" {
+		return "", "", fmt.Errorf("%w: malformed API key", ErrInvalidArgument)
+	}
+	return prefix, secret, nil
+}
+
+// CheckAPIKeySecret проверяет секрет ключа по хешу.
+// При несовпадении возвращает ErrNotFound, чтобы не раскрывать,
+// что ключ с таким префиксом существует.
+func CheckAPIKeySecret(hash []byte, secret string) error {
+	err := bcrypt.CompareHashAndPassword(hash, []byte(secret))
+	if err != nil {
+		return ErrNotFound
+	}
+	return nil
+}
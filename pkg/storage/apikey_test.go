@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAPIKey(t *testing.T) {
+	raw, prefix, hash, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatalf("GenerateAPIKey() error = %v", err)
+	}
+	if strings.Contains(string(hash), raw) {
+		t.Errorf("hash %q contains raw key", hash)
+	}
+
+	gotPrefix, secret, err := ParseAPIKey(raw)
+	if err != nil {
+		t.Fatalf("ParseAPIKey() error = %v", err)
+	}
+	if gotPrefix != prefix {
+		t.Errorf("ParseAPIKey() prefix = %q, want %q", gotPrefix, prefix)
+	}
+	if err := CheckAPIKeySecret(hash, secret); err != nil {
+		t.Errorf("CheckAPIKeySecret() error = %v", err)
+	}
+	if err := CheckAPIKeySecret(hash, secret+"x"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("CheckAPIKeySecret() with wrong secret error = %v, want ErrNotFound", err)
+	}
+
+	// ключи уникальны
+	other, _, _, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatalf("GenerateAPIKey() error = %v", err)
+	}
+	if other == raw {
+		t.Errorf("GenerateAPIKey() returned the same key twice")
+	}
+}
+
+func TestParseAPIKeyMalformed(t *testing.T) {
+	prefix := strings.Repeat("a", 2*apiKeyPrefixBytes)
+	for _, raw := range []string{"", "key", prefix, prefix + ".", "abc.secret"} {
+		if _, _, err := ParseAPIKey(raw); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("ParseAPIKey(%q) error = %v, want ErrInvalidArgument", raw, err)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"strconv"
+)
+
+// AuditedStorage оборачивает storage.Interface и при каждом вызове
+// UpdateTask записывает в журнал изменённые поля задачи.
+// Остальные методы передаются обёрнутому хранилищу без изменений.
+//
+// Чтение старой версии, обновление и запись в журнал выполняются
+// отдельными вызовами, поэтому при параллельных изменениях одной
+// задачи журнал может быть неточным.
+type AuditedStorage struct {
+	storage.Interface
+}
+
+// Конструктор, принимает оборачиваемое хранилище.
+func New(inner storage.Interface) *AuditedStorage {
+	a := AuditedStorage{
+		Interface: inner,
+	}
+	return &a
+}
+
+type userKey struct{}
+
+// WithUser возвращает контекст, изменения в котором записываются
+// в журнал от имени пользователя userID. Без него используется
+// пользователь по умолчанию с ID 0.
+func WithUser(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, userKey{}, userID)
+}
+
+func userFrom(ctx context.Context) int {
+	id, _ := ctx.Value(userKey{}).(int)
+	return id
+}
+
+// UpdateTask обновляет задачу и записывает в журнал изменённые поля.
+func (a *AuditedStorage) UpdateTask(ctx context.Context, task storage.Task) error {
+	old, err := a.Interface.TaskById(ctx, task.ID)
+	if errors.Is(err, storage.ErrNotFound) {
+		// обновлять нечего, журнал не ведётся
+		return a.Interface.UpdateTask(ctx, task)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = a.Interface.UpdateTask(ctx, task)
+	if err != nil {
+		return err
+	}
+
+	userID := userFrom(ctx)
+	for _, c := range changes(*old, task) {
+		err = a.Interface.RecordChange(ctx, storage.AuditEntry{
+			TaskID:   task.ID,
+			UserID:   userID,
+			Field:    c.field,
+			OldValue: c.old,
+			NewValue: c.new,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// change - изменение одного поля задачи.
+type change struct {
+	field    string
+	old, new string
+}
+
+// changes сравнивает поля задачи, которые перезаписывает UpdateTask,
+// и возвращает отличающиеся. Имена полей совпадают с именами столбцов.
+func changes(old, new storage.Task) []change {
+	// UpdateTask заменяет незаданный приоритет на storage.PriorityLow
+	if new.Priority == 0 {
+		new.Priority = storage.PriorityLow
+	}
+	fields := []change{
+		{"opened", fmtInt64(old.Opened), fmtInt64(new.Opened)},
+		{"closed", fmtInt64(old.Closed), fmtInt64(new.Closed)},
+		{"author_id", strconv.Itoa(old.AuthorID), strconv.Itoa(new.AuthorID)},
+		{"assigned_id", strconv.Itoa(old.AssignedID), strconv.Itoa(new.AssignedID)},
+		{"title", old.Title, new.Title},
+		{"content", old.Content, new.Content},
+		{"due_at", fmtInt64Ptr(old.DueAt), fmtInt64Ptr(new.DueAt)},
+		{"project_id", fmtIntPtr(old.ProjectID), fmtIntPtr(new.ProjectID)},
+		{"metadata", fmtMap(old.Metadata), fmtMap(new.Metadata)},
+		{"external_system", fmtStringPtr(old.ExternalSystem), fmtStringPtr(new.ExternalSystem)},
+		{"external_id", fmtStringPtr(old.ExternalID), fmtStringPtr(new.ExternalID)},
+		{"tags", fmtTags(old.Tags), fmtTags(new.Tags)},
+		{"priority", strconv.Itoa(int(old.Priority)), strconv.Itoa(int(new.Priority))},
+	}
+
+	var res []change
+	for _, c := range fields {
+		if c.old != c.new {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+func fmtInt64(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
+
+// Отсутствующее значение записывается пустой строкой.
+func fmtInt64Ptr(v *int64) string {
+	if v == nil {
+		return ""
+	}
+	return fmtInt64(*v)
+}
+
+func fmtIntPtr(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+func fmtStringPtr(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+// Теги записываются JSON-массивом в исходном порядке.
+// Отсутствующие теги и пустой список не различаются.
+func fmtTags(tags []string) string {
+	if len(tags) == 0 {
+		return "[]"
+	}
+	b, _ := json.Marshal(tags)
+	return string(b)
+}
+
+// Атрибуты записываются в JSON с ключами по алфавиту.
+// Отсутствующие атрибуты и пустой набор не различаются.
+func fmtMap(m map[string]string) string {
+	if len(m) == 0 {
+		return "{}"
+	}
+	b, _ := json.Marshal(m)
+	return string(b)
+}
@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"skillfactory/30.8.1/pkg/storage"
+	"testing"
+)
+
+func TestChanges(t *testing.T) {
+	due := int64(100)
+	project := 3
+	system, external := "jira", "PRJ-42"
+	old := storage.Task{ID: 1, Title: "title", Content: "content", Metadata: map[string]string{}, Priority: storage.PriorityLow}
+
+	tests := []struct {
+		name   string
+		modify func(t *storage.Task)
+		want   []change
+	}{
+		{"no changes", func(t *storage.Task) {}, nil},
+		{"title", func(t *storage.Task) { t.Title = "new" }, []change{{"title", "title", "new"}}},
+		{"assignee and due date", func(t *storage.Task) {
+			t.AssignedID = 2
+			t.DueAt = &due
+		}, []change{{"assigned_id", "0", "2"}, {"due_at", "", "100"}}},
+		{"project", func(t *storage.Task) { t.ProjectID = &project }, []change{{"project_id", "", "3"}}},
+		{"metadata", func(t *storage.Task) { t.Metadata = map[string]string{"b": "2", "a": "1"} },
+			[]change{{"metadata", "{}", `{"a":"1","b":"2"}`}}},
+		// пустой набор атрибутов и их отсутствие не различаются
+		{"nil metadata", func(t *storage.Task) { t.Metadata = nil }, nil},
+		{"external reference", func(t *storage.Task) {
+			t.ExternalSystem = &system
+			t.ExternalID = &external
+		}, []change{{"external_system", "", "jira"}, {"external_id", "", "PRJ-42"}}},
+		{"tags", func(t *storage.Task) { t.Tags = []string{"b", "a"} }, []change{{"tags", "[]", `["b","a"]`}}},
+		{"empty tags", func(t *storage.Task) { t.Tags = []string{} }, nil},
+		{"priority", func(t *storage.Task) { t.Priority = storage.PriorityHigh }, []change{{"priority", "1", "3"}}},
+		// UpdateTask сохраняет незаданный приоритет как PriorityLow
+		{"zero priority", func(t *storage.Task) { t.Priority = 0 }, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			new := old
+			tt.modify(&new)
+			got := changes(old, new)
+			if len(got) != len(tt.want) {
+				t.Fatalf("changes() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("changes()[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
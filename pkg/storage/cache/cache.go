@@ -0,0 +1,429 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Cache оборачивает storage.Interface и кэширует результаты
+// TaskById, TasksByAuthor и TasksByLabel на время ttl.
+// Методы, изменяющие задачи и метки, сбрасывают затронутые записи кэша.
+// Остальные методы передаются обёрнутому хранилищу без изменений.
+//
+// Кэш может быть двухуровневым (см. NewTwoLevel): при промахе
+// в памяти процесса (L1) запрашивается Redis (L2), общий для процессов.
+// Ошибки Redis не возвращаются вызывающему коду, а считаются промахом.
+//
+// Изменения в обход Cache (другими процессами или напрямую
+// через обёрнутое хранилище) становятся видны не позже чем через ttl.
+type Cache struct {
+	storage.Interface
+	ttl     time.Duration
+	entries sync.Map              // L1: ключ вида "TaskById:42" -> *entry
+	l2      redis.UniversalClient // nil - только L1
+}
+
+// entry - закэшированный результат.
+type entry struct {
+	value   any
+	expires time.Time
+}
+
+// Префиксы ключей списков задач.
+const (
+	byAuthor = "TasksByAuthor:"
+	byLabel  = "TasksByLabel:"
+)
+
+// Ключ Redis с номером поколения списков задач. Сброс списков
+// увеличивает номер, и ключи прежнего поколения больше не читаются,
+// а удаляются Redis по истечении ttl.
+const listsGenKey = "TasksBy:gen"
+
+// Конструктор, принимает оборачиваемое хранилище и время жизни записей.
+func New(inner storage.Interface, ttl time.Duration) *Cache {
+	c := Cache{
+		Interface: inner,
+		ttl:       ttl,
+	}
+	return &c
+}
+
+// NewTwoLevel создаёт двухуровневый кэш с Redis l2 в качестве второго уровня.
+// Значения хранятся в Redis в JSON.
+func NewTwoLevel(inner storage.Interface, l2 redis.UniversalClient, ttl time.Duration) *Cache {
+	c := New(inner, ttl)
+	c.l2 = l2
+	return c
+}
+
+// get возвращает значение из L1, если оно есть и не устарело.
+func (c *Cache) get(key string) (any, bool) {
+	v, ok := c.entries.Load(key)
+	if !ok {
+		return nil, false
+	}
+	// записи хранятся по указателю: CompareAndDelete сравнивает значения,
+	// а списки задач несравнимы
+	e := v.(*entry)
+	if time.Now().After(e.expires) {
+		c.entries.CompareAndDelete(key, v)
+		return nil, false
+	}
+	return e.value, true
+}
+
+// set сохраняет значение в L1.
+func (c *Cache) set(key string, value any) {
+	c.entries.Store(key, &entry{value: value, expires: time.Now().Add(c.ttl)})
+}
+
+// l2Key возвращает ключ Redis. Ключи списков задач
+// дополняются номером поколения.
+func (c *Cache) l2Key(ctx context.Context, key string) (string, error) {
+	if !isList(key) {
+		return key, nil
+	}
+	gen, err := c.l2.Get(ctx, listsGenKey).Result()
+	if err == redis.Nil {
+		gen = "0"
+	} else if err != nil {
+		return "", err
+	}
+	return key + "#" + gen, nil
+}
+
+// getL2 загружает значение из Redis в v.
+func (c *Cache) getL2(ctx context.Context, key string, v any) bool {
+	if c.l2 == nil {
+		return false
+	}
+	k, err := c.l2Key(ctx, key)
+	if err != nil {
+		return false
+	}
+	b, err := c.l2.Get(ctx, k).Bytes()
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(b, v) == nil
+}
+
+// setL2 сохраняет значение в Redis.
+func (c *Cache) setL2(ctx context.Context, key string, v any) {
+	if c.l2 == nil {
+		return
+	}
+	k, err := c.l2Key(ctx, key)
+	if err != nil {
+		return
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	c.l2.Set(ctx, k, b, c.ttl)
+}
+
+// lookup возвращает значение по ключу из L1, затем из L2,
+// а при промахе загружает его из хранилища и сохраняет на обоих уровнях.
+func lookup[T any](ctx context.Context, c *Cache, key string, load func() (T, error)) (T, error) {
+	if v, ok := c.get(key); ok {
+		return v.(T), nil
+	}
+
+	var v T
+	if c.getL2(ctx, key, &v) {
+		c.set(key, v)
+		return v, nil
+	}
+
+	v, err := load()
+	if err != nil {
+		return v, err
+	}
+	c.set(key, v)
+	c.setL2(ctx, key, v)
+	return v, nil
+}
+
+// invalidateTask сбрасывает задачу taskID и все списки задач,
+// так как изменённая задача может входить в любой из них.
+func (c *Cache) invalidateTask(ctx context.Context, taskID int) {
+	c.invalidateTasks(ctx, []int{taskID})
+}
+
+// invalidateLists сбрасывает все списки задач.
+func (c *Cache) invalidateLists(ctx context.Context) {
+	c.entries.Range(func(k, _ any) bool {
+		if isList(k.(string)) {
+			c.entries.Delete(k)
+		}
+		return true
+	})
+	if c.l2 != nil {
+		c.l2.Incr(ctx, listsGenKey)
+	}
+}
+
+func isList(key string) bool {
+	return strings.HasPrefix(key, byAuthor) || strings.HasPrefix(key, byLabel)
+}
+
+func taskKey(taskID int) string {
+	return "TaskById:" + strconv.Itoa(taskID)
+}
+
+// TaskById возвращает задачу из кэша или из хранилища.
+func (c *Cache) TaskById(ctx context.Context, taskId int) (*storage.Task, error) {
+	t, err := lookup(ctx, c, taskKey(taskId), func() (storage.Task, error) {
+		t, err := c.Interface.TaskById(ctx, taskId)
+		if err != nil {
+			return storage.Task{}, err
+		}
+		return *t, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// TasksByAuthor возвращает задачи автора из кэша или из хранилища.
+func (c *Cache) TasksByAuthor(ctx context.Context, authorId int) ([]storage.Task, error) {
+	tasks, err := lookup(ctx, c, byAuthor+strconv.Itoa(authorId), func() ([]storage.Task, error) {
+		return c.Interface.TasksByAuthor(ctx, authorId)
+	})
+	// копия, чтобы вызывающий код не мог изменить кэш
+	return slices.Clone(tasks), err
+}
+
+// TasksByLabel возвращает задачи с меткой из кэша или из хранилища.
+func (c *Cache) TasksByLabel(ctx context.Context, labelId int) ([]storage.Task, error) {
+	tasks, err := lookup(ctx, c, byLabel+strconv.Itoa(labelId), func() ([]storage.Task, error) {
+		return c.Interface.TasksByLabel(ctx, labelId)
+	})
+	// копия, чтобы вызывающий код не мог изменить кэш
+	return slices.Clone(tasks), err
+}
+
+// AddTask создаёт задачу и сбрасывает списки задач.
+func (c *Cache) AddTask(ctx context.Context, task storage.Task) (int, error) {
+	id, err := c.Interface.AddTask(ctx, task)
+	c.invalidateLists(ctx)
+	return id, err
+}
+
+// AddTasks создаёт задачи и сбрасывает списки задач.
+func (c *Cache) AddTasks(ctx context.Context, tasks []storage.Task) ([]int, error) {
+	ids, err := c.Interface.AddTasks(ctx, tasks)
+	c.invalidateLists(ctx)
+	return ids, err
+}
+
+// UpdateTask изменяет задачу и сбрасывает её в кэше.
+func (c *Cache) UpdateTask(ctx context.Context, task storage.Task) error {
+	err := c.Interface.UpdateTask(ctx, task)
+	c.invalidateTask(ctx, task.ID)
+	return err
+}
+
+// DeleteTask удаляет задачу и сбрасывает её в кэше.
+func (c *Cache) DeleteTask(ctx context.Context, taskId int) error {
+	err := c.Interface.DeleteTask(ctx, taskId)
+	c.invalidateTask(ctx, taskId)
+	return err
+}
+
+// AssignLabel назначает задаче метку и сбрасывает списки задач.
+func (c *Cache) AssignLabel(ctx context.Context, taskID, labelID int) error {
+	err := c.Interface.AssignLabel(ctx, taskID, labelID)
+	c.invalidateLists(ctx)
+	return err
+}
+
+// RemoveLabel снимает с задачи метку и сбрасывает списки задач.
+func (c *Cache) RemoveLabel(ctx context.Context, taskID, labelID int) error {
+	err := c.Interface.RemoveLabel(ctx, taskID, labelID)
+	c.invalidateLists(ctx)
+	return err
+}
+
+// invalidateTasks сбрасывает задачи taskIDs и все списки задач.
+func (c *Cache) invalidateTasks(ctx context.Context, taskIDs []int) {
+	for _, id := range taskIDs {
+		key := taskKey(id)
+		c.entries.Delete(key)
+		if c.l2 != nil {
+			c.l2.Del(ctx, key)
+		}
+	}
+	c.invalidateLists(ctx)
+}
+
+// taskIDs возвращает ID задач.
+func taskIDs(tasks []storage.Task) []int {
+	ids := make([]int, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.ID
+	}
+	return ids
+}
+
+// AddTaskWithLabels создаёт задачу с метками и сбрасывает списки задач.
+func (c *Cache) AddTaskWithLabels(ctx context.Context, t storage.Task, labelIDs []int) (int, error) {
+	id, err := c.Interface.AddTaskWithLabels(ctx, t, labelIDs)
+	c.invalidateLists(ctx)
+	return id, err
+}
+
+// AddTasksBatch создаёт задачи и сбрасывает списки задач.
+func (c *Cache) AddTasksBatch(ctx context.Context, tasks []storage.Task) ([]int, error) {
+	ids, err := c.Interface.AddTasksBatch(ctx, tasks)
+	c.invalidateLists(ctx)
+	return ids, err
+}
+
+// ImportTasks создаёт задачи и сбрасывает списки задач.
+func (c *Cache) ImportTasks(ctx context.Context, tasks []storage.Task) error {
+	err := c.Interface.ImportTasks(ctx, tasks)
+	c.invalidateLists(ctx)
+	return err
+}
+
+// UpsertTask создаёт или изменяет задачу и сбрасывает её в кэше.
+func (c *Cache) UpsertTask(ctx context.Context, t storage.Task) (int, error) {
+	id, err := c.Interface.UpsertTask(ctx, t)
+	if t.ID != 0 {
+		c.invalidateTask(ctx, t.ID)
+	} else {
+		c.invalidateLists(ctx)
+	}
+	return id, err
+}
+
+// AssignTask назначает исполнителя и сбрасывает задачу в кэше.
+func (c *Cache) AssignTask(ctx context.Context, taskID, userID int) error {
+	err := c.Interface.AssignTask(ctx, taskID, userID)
+	c.invalidateTask(ctx, taskID)
+	return err
+}
+
+// UpdateTasksAssignee назначает исполнителя задачам и сбрасывает их в кэше.
+func (c *Cache) UpdateTasksAssignee(ctx context.Context, taskIDs []int, assigneeID int) (int64, error) {
+	n, err := c.Interface.UpdateTasksAssignee(ctx, taskIDs, assigneeID)
+	c.invalidateTasks(ctx, taskIDs)
+	return n, err
+}
+
+// UpdateTasksStatus изменяет статус задач и сбрасывает их в кэше.
+func (c *Cache) UpdateTasksStatus(ctx context.Context, taskIDs []int, status storage.Status) (int64, error) {
+	n, err := c.Interface.UpdateTasksStatus(ctx, taskIDs, status)
+	c.invalidateTasks(ctx, taskIDs)
+	return n, err
+}
+
+// PartialUpdateTask изменяет задачу и сбрасывает её в кэше.
+func (c *Cache) PartialUpdateTask(ctx context.Context, taskID int, patch storage.TaskPatch) error {
+	err := c.Interface.PartialUpdateTask(ctx, taskID, patch)
+	c.invalidateTask(ctx, taskID)
+	return err
+}
+
+// UpdateTaskStatus изменяет статус задачи и сбрасывает её в кэше.
+func (c *Cache) UpdateTaskStatus(ctx context.Context, taskID int, s storage.Status) error {
+	err := c.Interface.UpdateTaskStatus(ctx, taskID, s)
+	c.invalidateTask(ctx, taskID)
+	return err
+}
+
+// UpdateTaskRank изменяет ранг задачи и сбрасывает её в кэше.
+func (c *Cache) UpdateTaskRank(ctx context.Context, taskID int, rank float64) error {
+	err := c.Interface.UpdateTaskRank(ctx, taskID, rank)
+	c.invalidateTask(ctx, taskID)
+	return err
+}
+
+// MarkTaskStale помечает задачу устаревшей и сбрасывает её в кэше.
+func (c *Cache) MarkTaskStale(ctx context.Context, taskID int) error {
+	err := c.Interface.MarkTaskStale(ctx, taskID)
+	c.invalidateTask(ctx, taskID)
+	return err
+}
+
+// DeleteTasks удаляет задачи и сбрасывает их в кэше.
+func (c *Cache) DeleteTasks(ctx context.Context, taskIDs []int) error {
+	err := c.Interface.DeleteTasks(ctx, taskIDs)
+	c.invalidateTasks(ctx, taskIDs)
+	return err
+}
+
+// UndeleteTask восстанавливает задачу и сбрасывает её в кэше.
+func (c *Cache) UndeleteTask(ctx context.Context, taskID int) error {
+	err := c.Interface.UndeleteTask(ctx, taskID)
+	c.invalidateTask(ctx, taskID)
+	return err
+}
+
+// ArchiveTask переносит задачу в архив и сбрасывает её в кэше.
+func (c *Cache) ArchiveTask(ctx context.Context, taskID int) error {
+	err := c.Interface.ArchiveTask(ctx, taskID)
+	c.invalidateTask(ctx, taskID)
+	return err
+}
+
+// UnarchiveTask возвращает задачу из архива и сбрасывает её в кэше.
+func (c *Cache) UnarchiveTask(ctx context.Context, taskID int) error {
+	err := c.Interface.UnarchiveTask(ctx, taskID)
+	c.invalidateTask(ctx, taskID)
+	return err
+}
+
+// SpawnRecurringTask создаёт копию повторяющейся задачи
+// и сбрасывает списки задач.
+func (c *Cache) SpawnRecurringTask(ctx context.Context, rule storage.RecurrenceRule) (int, error) {
+	id, err := c.Interface.SpawnRecurringTask(ctx, rule)
+	c.invalidateLists(ctx)
+	return id, err
+}
+
+// EraseUser обезличивает пользователя и сбрасывает в кэше задачи,
+// в которых он был автором или исполнителем.
+func (c *Cache) EraseUser(ctx context.Context, userID int) error {
+	authored, _ := c.Interface.TasksByAuthor(ctx, userID)
+	assigned, _ := c.Interface.TasksByAssignee(ctx, userID)
+	err := c.Interface.EraseUser(ctx, userID)
+	c.invalidateTasks(ctx, append(taskIDs(authored), taskIDs(assigned)...))
+	return err
+}
+
+// DeleteProject удаляет проект и сбрасывает в кэше его задачи,
+// которые удаляются или отвязываются от проекта.
+func (c *Cache) DeleteProject(ctx context.Context, projectID int, cascadeDelete bool) error {
+	tasks, _ := c.Interface.TasksByProject(ctx, projectID)
+	err := c.Interface.DeleteProject(ctx, projectID, cascadeDelete)
+	c.invalidateTasks(ctx, taskIDs(tasks))
+	return err
+}
+
+// UpdateLabel изменяет метку и сбрасывает списки задач.
+func (c *Cache) UpdateLabel(ctx context.Context, l storage.Label) error {
+	err := c.Interface.UpdateLabel(ctx, l)
+	c.invalidateLists(ctx)
+	return err
+}
+
+// DeleteLabel удаляет метку и сбрасывает списки задач.
+func (c *Cache) DeleteLabel(ctx context.Context, labelID int) error {
+	err := c.Interface.DeleteLabel(ctx, labelID)
+	c.invalidateLists(ctx)
+	return err
+}
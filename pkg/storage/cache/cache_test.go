@@ -0,0 +1,323 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"skillfactory/30.8.1/pkg/storage/mock"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newInner возвращает заглушку, которая возвращает задачу 1
+// и список из неё для любых автора, метки и проекта.
+func newInner() *mock.Mock {
+	tasks := func() []storage.Task { return []storage.Task{{ID: 1, Title: "task"}} }
+	return &mock.Mock{
+		TaskByIdFunc: func(ctx context.Context, taskId int) (*storage.Task, error) {
+			return &storage.Task{ID: taskId, Title: "task"}, nil
+		},
+		TasksByAuthorFunc: func(ctx context.Context, authorId int) ([]storage.Task, error) {
+			return tasks(), nil
+		},
+		TasksByLabelFunc: func(ctx context.Context, labelId int) ([]storage.Task, error) {
+			return tasks(), nil
+		},
+		TasksByProjectFunc: func(ctx context.Context, projectID int) ([]storage.Task, error) {
+			return tasks(), nil
+		},
+	}
+}
+
+// read читает задачу 1 и списки задач автора 1 и метки 1.
+func read(t *testing.T, c *Cache) {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := c.TaskById(ctx, 1); err != nil {
+		t.Fatalf("TaskById() error = %v", err)
+	}
+	if _, err := c.TasksByAuthor(ctx, 1); err != nil {
+		t.Fatalf("TasksByAuthor() error = %v", err)
+	}
+	if _, err := c.TasksByLabel(ctx, 1); err != nil {
+		t.Fatalf("TasksByLabel() error = %v", err)
+	}
+}
+
+func TestHit(t *testing.T) {
+	inner := newInner()
+	c := New(inner, time.Minute)
+
+	read(t, c)
+	read(t, c)
+	for _, method := range []string{"TaskById", "TasksByAuthor", "TasksByLabel"} {
+		if n := len(inner.CallsTo(method)); n != 1 {
+			t.Errorf("inner %s() called %d times, want 1", method, n)
+		}
+	}
+
+	// другой ключ загружается отдельно
+	if _, err := c.TaskById(context.Background(), 2); err != nil {
+		t.Fatalf("TaskById() error = %v", err)
+	}
+	if n := len(inner.CallsTo("TaskById")); n != 2 {
+		t.Errorf("inner TaskById() called %d times, want 2", n)
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	inner := newInner()
+	c := New(inner, 10*time.Millisecond)
+
+	read(t, c)
+	time.Sleep(20 * time.Millisecond)
+	read(t, c)
+	if n := len(inner.CallsTo("TaskById")); n != 2 {
+		t.Errorf("inner TaskById() called %d times after ttl, want 2", n)
+	}
+}
+
+// Ошибки хранилища не кэшируются.
+func TestErrorNotCached(t *testing.T) {
+	inner := &mock.Mock{
+		TaskByIdFunc: func(ctx context.Context, taskId int) (*storage.Task, error) {
+			return nil, storage.ErrNotFound
+		},
+	}
+	c := New(inner, time.Minute)
+	for range 2 {
+		if _, err := c.TaskById(context.Background(), 1); !errors.Is(err, storage.ErrNotFound) {
+			t.Fatalf("TaskById() error = %v, want ErrNotFound", err)
+		}
+	}
+	if n := len(inner.CallsTo("TaskById")); n != 2 {
+		t.Errorf("inner TaskById() called %d times, want 2", n)
+	}
+}
+
+// Изменение возвращённых значений не изменяет кэш.
+func TestCopies(t *testing.T) {
+	ctx := context.Background()
+	c := New(newInner(), time.Minute)
+
+	task, _ := c.TaskById(ctx, 1)
+	task.Title = "changed"
+	tasks, _ := c.TasksByAuthor(ctx, 1)
+	tasks[0].Title = "changed"
+
+	if task, _ = c.TaskById(ctx, 1); task.Title != "task" {
+		t.Errorf("TaskById() after caller change = %q, want task", task.Title)
+	}
+	if tasks, _ = c.TasksByAuthor(ctx, 1); tasks[0].Title != "task" {
+		t.Errorf("TasksByAuthor() after caller change = %q, want task", tasks[0].Title)
+	}
+}
+
+// Каждый изменяющий метод сбрасывает списки задач,
+// а изменяющий задачу 1 - и саму задачу.
+func TestInvalidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		write    func(ctx context.Context, c *Cache) error
+		dropTask bool
+	}{
+		{"AddTask", func(ctx context.Context, c *Cache) error {
+			_, err := c.AddTask(ctx, storage.Task{})
+			return err
+		}, false},
+		{"AddTasks", func(ctx context.Context, c *Cache) error {
+			_, err := c.AddTasks(ctx, []storage.Task{{}})
+			return err
+		}, false},
+		{"AddTaskWithLabels", func(ctx context.Context, c *Cache) error {
+			_, err := c.AddTaskWithLabels(ctx, storage.Task{}, []int{1})
+			return err
+		}, false},
+		{"AddTasksBatch", func(ctx context.Context, c *Cache) error {
+			_, err := c.AddTasksBatch(ctx, []storage.Task{{}})
+			return err
+		}, false},
+		{"ImportTasks", func(ctx context.Context, c *Cache) error {
+			return c.ImportTasks(ctx, []storage.Task{{}})
+		}, false},
+		{"UpsertTask new", func(ctx context.Context, c *Cache) error {
+			_, err := c.UpsertTask(ctx, storage.Task{})
+			return err
+		}, false},
+		{"UpsertTask existing", func(ctx context.Context, c *Cache) error {
+			_, err := c.UpsertTask(ctx, storage.Task{ID: 1})
+			return err
+		}, true},
+		{"UpdateTask", func(ctx context.Context, c *Cache) error {
+			return c.UpdateTask(ctx, storage.Task{ID: 1})
+		}, true},
+		{"PartialUpdateTask", func(ctx context.Context, c *Cache) error {
+			return c.PartialUpdateTask(ctx, 1, storage.TaskPatch{})
+		}, true},
+		{"UpdateTaskStatus", func(ctx context.Context, c *Cache) error {
+			return c.UpdateTaskStatus(ctx, 1, storage.StatusDone)
+		}, true},
+		{"UpdateTaskRank", func(ctx context.Context, c *Cache) error {
+			return c.UpdateTaskRank(ctx, 1, 1.5)
+		}, true},
+		{"UpdateTasksAssignee", func(ctx context.Context, c *Cache) error {
+			_, err := c.UpdateTasksAssignee(ctx, []int{1}, 2)
+			return err
+		}, true},
+		{"UpdateTasksStatus", func(ctx context.Context, c *Cache) error {
+			_, err := c.UpdateTasksStatus(ctx, []int{1}, storage.StatusDone)
+			return err
+		}, true},
+		{"AssignTask", func(ctx context.Context, c *Cache) error {
+			return c.AssignTask(ctx, 1, 2)
+		}, true},
+		{"MarkTaskStale", func(ctx context.Context, c *Cache) error {
+			return c.MarkTaskStale(ctx, 1)
+		}, true},
+		{"DeleteTask", func(ctx context.Context, c *Cache) error {
+			return c.DeleteTask(ctx, 1)
+		}, true},
+		{"DeleteTasks", func(ctx context.Context, c *Cache) error {
+			return c.DeleteTasks(ctx, []int{1})
+		}, true},
+		{"UndeleteTask", func(ctx context.Context, c *Cache) error {
+			return c.UndeleteTask(ctx, 1)
+		}, true},
+		{"ArchiveTask", func(ctx context.Context, c *Cache) error {
+			return c.ArchiveTask(ctx, 1)
+		}, true},
+		{"UnarchiveTask", func(ctx context.Context, c *Cache) error {
+			return c.UnarchiveTask(ctx, 1)
+		}, true},
+		{"SpawnRecurringTask", func(ctx context.Context, c *Cache) error {
+			_, err := c.SpawnRecurringTask(ctx, storage.RecurrenceRule{TaskID: 1})
+			return err
+		}, false},
+		{"AssignLabel", func(ctx context.Context, c *Cache) error {
+			return c.AssignLabel(ctx, 1, 1)
+		}, false},
+		{"RemoveLabel", func(ctx context.Context, c *Cache) error {
+			return c.RemoveLabel(ctx, 1, 1)
+		}, false},
+		{"UpdateLabel", func(ctx context.Context, c *Cache) error {
+			return c.UpdateLabel(ctx, storage.Label{ID: 1})
+		}, false},
+		{"DeleteLabel", func(ctx context.Context, c *Cache) error {
+			return c.DeleteLabel(ctx, 1)
+		}, false},
+		// задача 1 - задача автора и проекта в заглушке
+		{"EraseUser", func(ctx context.Context, c *Cache) error {
+			return c.EraseUser(ctx, 1)
+		}, true},
+		{"DeleteProject", func(ctx context.Context, c *Cache) error {
+			return c.DeleteProject(ctx, 1, true)
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := newInner()
+			c := New(inner, time.Minute)
+			read(t, c)
+
+			if err := tt.write(context.Background(), c); err != nil {
+				t.Fatalf("%s() error = %v", tt.name, err)
+			}
+			inner.Calls = nil
+			read(t, c)
+
+			wantTask := 0
+			if tt.dropTask {
+				wantTask = 1
+			}
+			if n := len(inner.CallsTo("TaskById")); n != wantTask {
+				t.Errorf("inner TaskById() called %d times after %s, want %d", n, tt.name, wantTask)
+			}
+			for _, method := range []string{"TasksByAuthor", "TasksByLabel"} {
+				if n := len(inner.CallsTo(method)); n != 1 {
+					t.Errorf("inner %s() called %d times after %s, want 1", method, n, tt.name)
+				}
+			}
+		})
+	}
+}
+
+// newRedis запускает тестовый Redis и возвращает его и клиента к нему.
+func newRedis(t *testing.T) (*miniredis.Miniredis, redis.UniversalClient) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return mr, client
+}
+
+func TestTwoLevel(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newRedis(t)
+	inner := newInner()
+
+	read(t, NewTwoLevel(inner, client, time.Minute))
+
+	// после промаха значение сохраняется в Redis в JSON на время ttl
+	b, err := mr.Get("TaskById:1")
+	if err != nil {
+		t.Fatalf("Redis GET TaskById:1 error = %v", err)
+	}
+	var task storage.Task
+	if err := json.Unmarshal([]byte(b), &task); err != nil || task.ID != 1 {
+		t.Errorf("Redis TaskById:1 = %s, want task 1 in JSON", b)
+	}
+	if ttl := mr.TTL("TaskById:1"); ttl != time.Minute {
+		t.Errorf("Redis TTL = %v, want %v", ttl, time.Minute)
+	}
+	if !mr.Exists("TasksByAuthor:1#0") || !mr.Exists("TasksByLabel:1#0") {
+		t.Errorf("Redis keys = %v, want task lists", mr.Keys())
+	}
+
+	// другой процесс с пустым L1 получает значения из Redis
+	read(t, NewTwoLevel(inner, client, time.Minute))
+	for _, method := range []string{"TaskById", "TasksByAuthor", "TasksByLabel"} {
+		if n := len(inner.CallsTo(method)); n != 1 {
+			t.Errorf("inner %s() called %d times, want 1", method, n)
+		}
+	}
+
+	// изменение задачи удаляет её из Redis и сбрасывает списки
+	if err := NewTwoLevel(inner, client, time.Minute).UpdateTask(ctx, storage.Task{ID: 1}); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if mr.Exists("TaskById:1") {
+		t.Error("Redis TaskById:1 exists after UpdateTask")
+	}
+	inner.Calls = nil
+	read(t, NewTwoLevel(inner, client, time.Minute))
+	for _, method := range []string{"TaskById", "TasksByAuthor", "TasksByLabel"} {
+		if n := len(inner.CallsTo(method)); n != 1 {
+			t.Errorf("inner %s() called %d times after UpdateTask, want 1", method, n)
+		}
+	}
+
+	if err := NewTwoLevel(inner, client, time.Minute).DeleteTask(ctx, 1); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	if mr.Exists("TaskById:1") {
+		t.Error("Redis TaskById:1 exists after DeleteTask")
+	}
+}
+
+// Недоступный Redis считается промахом.
+func TestTwoLevelUnavailable(t *testing.T) {
+	mr, client := newRedis(t)
+	mr.Close()
+	inner := newInner()
+	c := NewTwoLevel(inner, client, time.Minute)
+
+	read(t, c)
+	read(t, c)
+	if n := len(inner.CallsTo("TaskById")); n != 1 {
+		t.Errorf("inner TaskById() called %d times, want 1", n)
+	}
+}
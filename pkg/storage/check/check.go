@@ -0,0 +1,14 @@
+// Пакет check помогает проверить при компиляции, что тип
+// реализует storage.Interface.
+package check
+
+import "skillfactory/30.8.1/pkg/storage"
+
+// CheckCompatible возвращает v без изменений. Вызов с типом, которому
+// не хватает методов storage.Interface, не компилируется, и ошибка
+// указывает на место вызова и на отсутствующий метод:
+//
+//	var _ = check.CheckCompatible(mystore.New())
+func CheckCompatible[T storage.Interface](v T) storage.Interface {
+	return v
+}
@@ -0,0 +1,25 @@
+package check_test
+
+import (
+	"skillfactory/30.8.1/pkg/storage"
+	"skillfactory/30.8.1/pkg/storage/check"
+	"skillfactory/30.8.1/pkg/storage/memory"
+	"skillfactory/30.8.1/pkg/storage/mock"
+	"skillfactory/30.8.1/pkg/storage/postgres"
+	"testing"
+)
+
+// Реализации хранилища проверяются при компиляции теста.
+var (
+	_ = check.CheckCompatible(memory.New())
+	_ = check.CheckCompatible(&mock.Mock{})
+	_ = check.CheckCompatible((*postgres.Storage)(nil))
+)
+
+func TestCheckCompatible(t *testing.T) {
+	m := memory.New()
+	got := check.CheckCompatible(m)
+	if got != storage.Interface(m) {
+		t.Errorf("CheckCompatible() = %p, want its argument %p", got, m)
+	}
+}
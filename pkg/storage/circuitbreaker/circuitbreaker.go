@@ -0,0 +1,1510 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen возвращается без обращения к хранилищу,
+// пока цепь разомкнута.
+var ErrCircuitOpen = errors.New("circuitbreaker: circuit is open")
+
+// State - состояние предохранителя.
+type State int
+
+// Состояния предохранителя.
+const (
+	StateClosed   State = iota // вызовы выполняются
+	StateOpen                  // вызовы отклоняются
+	StateHalfOpen              // выполняется пробный вызов
+)
+
+// String возвращает название состояния.
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// Config задаёт параметры предохранителя.
+type Config struct {
+	// Количество ошибок подряд, после которого цепь размыкается.
+	FailureThreshold int
+	// Количество успешных пробных вызовов, после которого цепь замыкается.
+	SuccessThreshold int
+	// Время, в течение которого цепь остаётся разомкнутой.
+	OpenDuration time.Duration
+}
+
+// Breaker оборачивает storage.Interface и прекращает обращения к хранилищу
+// при устойчивых сбоях. После FailureThreshold ошибок подряд цепь
+// размыкается, и все вызовы возвращают ErrCircuitOpen. По истечении
+// OpenDuration пропускается один пробный вызов: успех приближает
+// замыкание цепи, ошибка снова размыкает её.
+//
+// Сбоями считаются только ошибки хранилища и соединения с ним.
+// Ошибки предметной области (storage.ErrNotFound, storage.ErrConflict,
+// storage.ErrInvalidArgument, storage.ErrVersionConflict, storage.ErrLocked,
+// storage.ErrForbidden, storage.ErrTxClosed), отмена контекста вызывающим
+// кодом и ошибки функции обратного вызова TasksIter сбоями не считаются.
+type Breaker struct {
+	inner storage.Interface
+	cfg   Config
+
+	mu        sync.Mutex
+	state     State
+	failures  int       // ошибки подряд в замкнутом состоянии
+	successes int       // успешные пробные вызовы
+	probing   bool      // выполняется пробный вызов
+	openedAt  time.Time // время размыкания цепи
+}
+
+// Конструктор, принимает оборачиваемое хранилище и параметры предохранителя.
+func New(inner storage.Interface, cfg Config) *Breaker {
+	b := Breaker{
+		inner: inner,
+		cfg:   cfg,
+	}
+	return &b
+}
+
+// State возвращает текущее состояние предохранителя.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// isFailure сообщает, считается ли ошибка вызова с контекстом ctx
+// сбоем хранилища.
+func isFailure(ctx context.Context, err error) bool {
+	if errors.Is(err, context.Canceled) && ctx.Err() != nil {
+		// вызов отменил сам вызывающий код
+		return false
+	}
+	return err != nil &&
+		!errors.Is(err, storage.ErrNotFound) &&
+		!errors.Is(err, storage.ErrConflict) &&
+		!errors.Is(err, storage.ErrInvalidArgument) &&
+		!errors.Is(err, storage.ErrVersionConflict) &&
+		!errors.Is(err, storage.ErrLocked) &&
+		!errors.Is(err, storage.ErrForbidden) &&
+		!errors.Is(err, storage.ErrTxClosed)
+}
+
+// allow сообщает, можно ли выполнить вызов.
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if time.Since(b.openedAt) < b.cfg.OpenDuration {
+			return false
+		}
+		b.state = StateHalfOpen
+		b.successes = 0
+		b.probing = true
+		return true
+	case StateHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	}
+	return true
+}
+
+// record учитывает результат вызова и меняет состояние.
+func (b *Breaker) record(ctx context.Context, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	failed := isFailure(ctx, err)
+	switch b.state {
+	case StateClosed:
+		if !failed {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.cfg.FailureThreshold {
+			b.open()
+		}
+	case StateHalfOpen:
+		b.probing = false
+		if failed {
+			b.open()
+			return
+		}
+		b.successes++
+		if b.successes >= b.cfg.SuccessThreshold {
+			b.state = StateClosed
+			b.failures = 0
+		}
+	}
+}
+
+// open размыкает цепь. Вызывается под блокировкой.
+func (b *Breaker) open() {
+	b.state = StateOpen
+	b.openedAt = time.Now()
+	b.failures = 0
+}
+
+// do выполняет fn, если цепь не разомкнута.
+func (b *Breaker) do(ctx context.Context, fn func() error) error {
+	if !b.allow() {
+		return ErrCircuitOpen
+	}
+	err := fn()
+	b.record(ctx, err)
+	return err
+}
+
+// Tasks выполняет вызов Tasks, если цепь не разомкнута.
+func (b *Breaker) Tasks(ctx context.Context) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.Tasks(ctx)
+		return err
+	})
+	return res, err
+}
+
+// TasksIter выполняет вызов TasksIter, если цепь не разомкнута.
+// Ошибка, возвращённая fn, относится к вызывающему коду
+// и учитывается как успешный вызов хранилища.
+func (b *Breaker) TasksIter(ctx context.Context, fn func(t storage.Task) error) error {
+	if !b.allow() {
+		return ErrCircuitOpen
+	}
+	var fnErr error
+	err := b.inner.TasksIter(ctx, func(t storage.Task) error {
+		fnErr = fn(t)
+		return fnErr
+	})
+	if fnErr != nil && errors.Is(err, fnErr) {
+		b.record(ctx, nil)
+	} else {
+		b.record(ctx, err)
+	}
+	return err
+}
+
+// TasksList выполняет вызов TasksList, если цепь не разомкнута.
+func (b *Breaker) TasksList(ctx context.Context, opts storage.ListOptions) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TasksList(ctx, opts)
+		return err
+	})
+	return res, err
+}
+
+// TotalCount выполняет вызов TotalCount, если цепь не разомкнута.
+func (b *Breaker) TotalCount(ctx context.Context) (int, error) {
+	var res int
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TotalCount(ctx)
+		return err
+	})
+	return res, err
+}
+
+// TasksAfter выполняет вызов TasksAfter, если цепь не разомкнута.
+func (b *Breaker) TasksAfter(ctx context.Context, afterID int, limit int) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TasksAfter(ctx, afterID, limit)
+		return err
+	})
+	return res, err
+}
+
+// TaskCount выполняет вызов TaskCount, если цепь не разомкнута.
+func (b *Breaker) TaskCount(ctx context.Context) (int, error) {
+	var res int
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TaskCount(ctx)
+		return err
+	})
+	return res, err
+}
+
+// TaskCountByAuthor выполняет вызов TaskCountByAuthor, если цепь не разомкнута.
+func (b *Breaker) TaskCountByAuthor(ctx context.Context, authorID int) (int, error) {
+	var res int
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TaskCountByAuthor(ctx, authorID)
+		return err
+	})
+	return res, err
+}
+
+// TaskCountByLabel выполняет вызов TaskCountByLabel, если цепь не разомкнута.
+func (b *Breaker) TaskCountByLabel(ctx context.Context, labelID int) (int, error) {
+	var res int
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TaskCountByLabel(ctx, labelID)
+		return err
+	})
+	return res, err
+}
+
+// TaskById выполняет вызов TaskById, если цепь не разомкнута.
+func (b *Breaker) TaskById(ctx context.Context, taskId int) (*storage.Task, error) {
+	var res *storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TaskById(ctx, taskId)
+		return err
+	})
+	return res, err
+}
+
+// TasksByAuthor выполняет вызов TasksByAuthor, если цепь не разомкнута.
+func (b *Breaker) TasksByAuthor(ctx context.Context, authorId int) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TasksByAuthor(ctx, authorId)
+		return err
+	})
+	return res, err
+}
+
+// TasksByAssignee выполняет вызов TasksByAssignee, если цепь не разомкнута.
+func (b *Breaker) TasksByAssignee(ctx context.Context, assigneeID int) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TasksByAssignee(ctx, assigneeID)
+		return err
+	})
+	return res, err
+}
+
+// TasksByLabel выполняет вызов TasksByLabel, если цепь не разомкнута.
+func (b *Breaker) TasksByLabel(ctx context.Context, labelId int) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TasksByLabel(ctx, labelId)
+		return err
+	})
+	return res, err
+}
+
+// TasksWithLabels выполняет вызов TasksWithLabels, если цепь не разомкнута.
+func (b *Breaker) TasksWithLabels(ctx context.Context) ([]storage.TaskWithLabels, error) {
+	var res []storage.TaskWithLabels
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TasksWithLabels(ctx)
+		return err
+	})
+	return res, err
+}
+
+// TasksWithUsers выполняет вызов TasksWithUsers, если цепь не разомкнута.
+func (b *Breaker) TasksWithUsers(ctx context.Context) ([]storage.TaskWithUsers, error) {
+	var res []storage.TaskWithUsers
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TasksWithUsers(ctx)
+		return err
+	})
+	return res, err
+}
+
+// FilterTasks выполняет вызов FilterTasks, если цепь не разомкнута.
+func (b *Breaker) FilterTasks(ctx context.Context, f storage.TaskFilter) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.FilterTasks(ctx, f)
+		return err
+	})
+	return res, err
+}
+
+// TasksByStatus выполняет вызов TasksByStatus, если цепь не разомкнута.
+func (b *Breaker) TasksByStatus(ctx context.Context, s storage.Status) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TasksByStatus(ctx, s)
+		return err
+	})
+	return res, err
+}
+
+// TasksByPriority выполняет вызов TasksByPriority, если цепь не разомкнута.
+func (b *Breaker) TasksByPriority(ctx context.Context, p storage.Priority) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TasksByPriority(ctx, p)
+		return err
+	})
+	return res, err
+}
+
+// TasksOrderedByPriority выполняет вызов TasksOrderedByPriority, если цепь не разомкнута.
+func (b *Breaker) TasksOrderedByPriority(ctx context.Context) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TasksOrderedByPriority(ctx)
+		return err
+	})
+	return res, err
+}
+
+// TasksOverdue выполняет вызов TasksOverdue, если цепь не разомкнута.
+func (b *Breaker) TasksOverdue(ctx context.Context, now int64) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TasksOverdue(ctx, now)
+		return err
+	})
+	return res, err
+}
+
+// TasksDueBetween выполняет вызов TasksDueBetween, если цепь не разомкнута.
+func (b *Breaker) TasksDueBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TasksDueBetween(ctx, from, to)
+		return err
+	})
+	return res, err
+}
+
+// AddTask выполняет вызов AddTask, если цепь не разомкнута.
+func (b *Breaker) AddTask(ctx context.Context, task storage.Task) (int, error) {
+	var res int
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.AddTask(ctx, task)
+		return err
+	})
+	return res, err
+}
+
+// AddTaskWithLabels выполняет вызов AddTaskWithLabels, если цепь не разомкнута.
+func (b *Breaker) AddTaskWithLabels(ctx context.Context, t storage.Task, labelIDs []int) (int, error) {
+	var res int
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.AddTaskWithLabels(ctx, t, labelIDs)
+		return err
+	})
+	return res, err
+}
+
+// AddTasks выполняет вызов AddTasks, если цепь не разомкнута.
+func (b *Breaker) AddTasks(ctx context.Context, tasks []storage.Task) ([]int, error) {
+	var res []int
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.AddTasks(ctx, tasks)
+		return err
+	})
+	return res, err
+}
+
+// AddTasksBatch выполняет вызов AddTasksBatch, если цепь не разомкнута.
+func (b *Breaker) AddTasksBatch(ctx context.Context, tasks []storage.Task) ([]int, error) {
+	var res []int
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.AddTasksBatch(ctx, tasks)
+		return err
+	})
+	return res, err
+}
+
+// ImportTasks выполняет вызов ImportTasks, если цепь не разомкнута.
+func (b *Breaker) ImportTasks(ctx context.Context, tasks []storage.Task) error {
+	return b.do(ctx, func() error {
+		return b.inner.ImportTasks(ctx, tasks)
+	})
+}
+
+// UpdateTask выполняет вызов UpdateTask, если цепь не разомкнута.
+func (b *Breaker) UpdateTask(ctx context.Context, task storage.Task) error {
+	return b.do(ctx, func() error {
+		return b.inner.UpdateTask(ctx, task)
+	})
+}
+
+// UpsertTask выполняет вызов UpsertTask, если цепь не разомкнута.
+func (b *Breaker) UpsertTask(ctx context.Context, t storage.Task) (int, error) {
+	var res int
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.UpsertTask(ctx, t)
+		return err
+	})
+	return res, err
+}
+
+// PartialUpdateTask выполняет вызов PartialUpdateTask, если цепь не разомкнута.
+func (b *Breaker) PartialUpdateTask(ctx context.Context, taskID int, patch storage.TaskPatch) error {
+	return b.do(ctx, func() error {
+		return b.inner.PartialUpdateTask(ctx, taskID, patch)
+	})
+}
+
+// UpdateTaskStatus выполняет вызов UpdateTaskStatus, если цепь не разомкнута.
+func (b *Breaker) UpdateTaskStatus(ctx context.Context, taskID int, s storage.Status) error {
+	return b.do(ctx, func() error {
+		return b.inner.UpdateTaskStatus(ctx, taskID, s)
+	})
+}
+
+// DeleteTask выполняет вызов DeleteTask, если цепь не разомкнута.
+func (b *Breaker) DeleteTask(ctx context.Context, taskId int) error {
+	return b.do(ctx, func() error {
+		return b.inner.DeleteTask(ctx, taskId)
+	})
+}
+
+// DeleteTasks выполняет вызов DeleteTasks, если цепь не разомкнута.
+func (b *Breaker) DeleteTasks(ctx context.Context, taskIDs []int) error {
+	return b.do(ctx, func() error {
+		return b.inner.DeleteTasks(ctx, taskIDs)
+	})
+}
+
+// TasksIncludingDeleted выполняет вызов TasksIncludingDeleted, если цепь не разомкнута.
+func (b *Breaker) TasksIncludingDeleted(ctx context.Context) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TasksIncludingDeleted(ctx)
+		return err
+	})
+	return res, err
+}
+
+// UndeleteTask выполняет вызов UndeleteTask, если цепь не разомкнута.
+func (b *Breaker) UndeleteTask(ctx context.Context, taskID int) error {
+	return b.do(ctx, func() error {
+		return b.inner.UndeleteTask(ctx, taskID)
+	})
+}
+
+// AddUser выполняет вызов AddUser, если цепь не разомкнута.
+func (b *Breaker) AddUser(ctx context.Context, u storage.User) (int, error) {
+	var res int
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.AddUser(ctx, u)
+		return err
+	})
+	return res, err
+}
+
+// Users выполняет вызов Users, если цепь не разомкнута.
+func (b *Breaker) Users(ctx context.Context) ([]storage.User, error) {
+	var res []storage.User
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.Users(ctx)
+		return err
+	})
+	return res, err
+}
+
+// UserByID выполняет вызов UserByID, если цепь не разомкнута.
+func (b *Breaker) UserByID(ctx context.Context, userID int) (*storage.User, error) {
+	var res *storage.User
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.UserByID(ctx, userID)
+		return err
+	})
+	return res, err
+}
+
+// UpdateUser выполняет вызов UpdateUser, если цепь не разомкнута.
+func (b *Breaker) UpdateUser(ctx context.Context, u storage.User) error {
+	return b.do(ctx, func() error {
+		return b.inner.UpdateUser(ctx, u)
+	})
+}
+
+// DeleteUser выполняет вызов DeleteUser, если цепь не разомкнута.
+func (b *Breaker) DeleteUser(ctx context.Context, userID int) error {
+	return b.do(ctx, func() error {
+		return b.inner.DeleteUser(ctx, userID)
+	})
+}
+
+// AddLabel выполняет вызов AddLabel, если цепь не разомкнута.
+func (b *Breaker) AddLabel(ctx context.Context, l storage.Label) (int, error) {
+	var res int
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.AddLabel(ctx, l)
+		return err
+	})
+	return res, err
+}
+
+// Labels выполняет вызов Labels, если цепь не разомкнута.
+func (b *Breaker) Labels(ctx context.Context) ([]storage.Label, error) {
+	var res []storage.Label
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.Labels(ctx)
+		return err
+	})
+	return res, err
+}
+
+// LabelByID выполняет вызов LabelByID, если цепь не разомкнута.
+func (b *Breaker) LabelByID(ctx context.Context, labelID int) (*storage.Label, error) {
+	var res *storage.Label
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.LabelByID(ctx, labelID)
+		return err
+	})
+	return res, err
+}
+
+// UpdateLabel выполняет вызов UpdateLabel, если цепь не разомкнута.
+func (b *Breaker) UpdateLabel(ctx context.Context, l storage.Label) error {
+	return b.do(ctx, func() error {
+		return b.inner.UpdateLabel(ctx, l)
+	})
+}
+
+// DeleteLabel выполняет вызов DeleteLabel, если цепь не разомкнута.
+func (b *Breaker) DeleteLabel(ctx context.Context, labelID int) error {
+	return b.do(ctx, func() error {
+		return b.inner.DeleteLabel(ctx, labelID)
+	})
+}
+
+// AssignLabel выполняет вызов AssignLabel, если цепь не разомкнута.
+func (b *Breaker) AssignLabel(ctx context.Context, taskID, labelID int) error {
+	return b.do(ctx, func() error {
+		return b.inner.AssignLabel(ctx, taskID, labelID)
+	})
+}
+
+// RemoveLabel выполняет вызов RemoveLabel, если цепь не разомкнута.
+func (b *Breaker) RemoveLabel(ctx context.Context, taskID, labelID int) error {
+	return b.do(ctx, func() error {
+		return b.inner.RemoveLabel(ctx, taskID, labelID)
+	})
+}
+
+// AddComment выполняет вызов AddComment, если цепь не разомкнута.
+func (b *Breaker) AddComment(ctx context.Context, c storage.Comment) (int, error) {
+	var res int
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.AddComment(ctx, c)
+		return err
+	})
+	return res, err
+}
+
+// CommentsByTask выполняет вызов CommentsByTask, если цепь не разомкнута.
+func (b *Breaker) CommentsByTask(ctx context.Context, taskID int) ([]storage.Comment, error) {
+	var res []storage.Comment
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.CommentsByTask(ctx, taskID)
+		return err
+	})
+	return res, err
+}
+
+// UpdateComment выполняет вызов UpdateComment, если цепь не разомкнута.
+func (b *Breaker) UpdateComment(ctx context.Context, c storage.Comment) error {
+	return b.do(ctx, func() error {
+		return b.inner.UpdateComment(ctx, c)
+	})
+}
+
+// DeleteComment выполняет вызов DeleteComment, если цепь не разомкнута.
+func (b *Breaker) DeleteComment(ctx context.Context, commentID int) error {
+	return b.do(ctx, func() error {
+		return b.inner.DeleteComment(ctx, commentID)
+	})
+}
+
+// LogTime выполняет вызов LogTime, если цепь не разомкнута.
+func (b *Breaker) LogTime(ctx context.Context, e storage.TimeEntry) (int, error) {
+	var res int
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.LogTime(ctx, e)
+		return err
+	})
+	return res, err
+}
+
+// TimeEntriesByTask выполняет вызов TimeEntriesByTask, если цепь не разомкнута.
+func (b *Breaker) TimeEntriesByTask(ctx context.Context, taskID int) ([]storage.TimeEntry, error) {
+	var res []storage.TimeEntry
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TimeEntriesByTask(ctx, taskID)
+		return err
+	})
+	return res, err
+}
+
+// TimeEntriesByUser выполняет вызов TimeEntriesByUser, если цепь не разомкнута.
+func (b *Breaker) TimeEntriesByUser(ctx context.Context, userID int) ([]storage.TimeEntry, error) {
+	var res []storage.TimeEntry
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TimeEntriesByUser(ctx, userID)
+		return err
+	})
+	return res, err
+}
+
+// TotalMinutesByTask выполняет вызов TotalMinutesByTask, если цепь не разомкнута.
+func (b *Breaker) TotalMinutesByTask(ctx context.Context, taskID int) (int, error) {
+	var res int
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TotalMinutesByTask(ctx, taskID)
+		return err
+	})
+	return res, err
+}
+
+// AddChecklistItem выполняет вызов AddChecklistItem, если цепь не разомкнута.
+func (b *Breaker) AddChecklistItem(ctx context.Context, item storage.ChecklistItem) (int, error) {
+	var res int
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.AddChecklistItem(ctx, item)
+		return err
+	})
+	return res, err
+}
+
+// ChecklistByTask выполняет вызов ChecklistByTask, если цепь не разомкнута.
+func (b *Breaker) ChecklistByTask(ctx context.Context, taskID int) ([]storage.ChecklistItem, error) {
+	var res []storage.ChecklistItem
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.ChecklistByTask(ctx, taskID)
+		return err
+	})
+	return res, err
+}
+
+// UpdateChecklistItem выполняет вызов UpdateChecklistItem, если цепь не разомкнута.
+func (b *Breaker) UpdateChecklistItem(ctx context.Context, item storage.ChecklistItem) error {
+	return b.do(ctx, func() error {
+		return b.inner.UpdateChecklistItem(ctx, item)
+	})
+}
+
+// DeleteChecklistItem выполняет вызов DeleteChecklistItem, если цепь не разомкнута.
+func (b *Breaker) DeleteChecklistItem(ctx context.Context, itemID int) error {
+	return b.do(ctx, func() error {
+		return b.inner.DeleteChecklistItem(ctx, itemID)
+	})
+}
+
+// ReorderChecklist выполняет вызов ReorderChecklist, если цепь не разомкнута.
+func (b *Breaker) ReorderChecklist(ctx context.Context, taskID int, orderedIDs []int) error {
+	return b.do(ctx, func() error {
+		return b.inner.ReorderChecklist(ctx, taskID, orderedIDs)
+	})
+}
+
+// AddProject выполняет вызов AddProject, если цепь не разомкнута.
+func (b *Breaker) AddProject(ctx context.Context, p storage.Project) (int, error) {
+	var res int
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.AddProject(ctx, p)
+		return err
+	})
+	return res, err
+}
+
+// Projects выполняет вызов Projects, если цепь не разомкнута.
+func (b *Breaker) Projects(ctx context.Context) ([]storage.Project, error) {
+	var res []storage.Project
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.Projects(ctx)
+		return err
+	})
+	return res, err
+}
+
+// ProjectByID выполняет вызов ProjectByID, если цепь не разомкнута.
+func (b *Breaker) ProjectByID(ctx context.Context, projectID int) (*storage.Project, error) {
+	var res *storage.Project
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.ProjectByID(ctx, projectID)
+		return err
+	})
+	return res, err
+}
+
+// UpdateProject выполняет вызов UpdateProject, если цепь не разомкнута.
+func (b *Breaker) UpdateProject(ctx context.Context, p storage.Project) error {
+	return b.do(ctx, func() error {
+		return b.inner.UpdateProject(ctx, p)
+	})
+}
+
+// DeleteProject выполняет вызов DeleteProject, если цепь не разомкнута.
+func (b *Breaker) DeleteProject(ctx context.Context, projectID int, cascadeDelete bool) error {
+	return b.do(ctx, func() error {
+		return b.inner.DeleteProject(ctx, projectID, cascadeDelete)
+	})
+}
+
+// TasksByProject выполняет вызов TasksByProject, если цепь не разомкнута.
+func (b *Breaker) TasksByProject(ctx context.Context, projectID int) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TasksByProject(ctx, projectID)
+		return err
+	})
+	return res, err
+}
+
+// AddDependency выполняет вызов AddDependency, если цепь не разомкнута.
+func (b *Breaker) AddDependency(ctx context.Context, taskID, dependsOnID int) error {
+	return b.do(ctx, func() error {
+		return b.inner.AddDependency(ctx, taskID, dependsOnID)
+	})
+}
+
+// RemoveDependency выполняет вызов RemoveDependency, если цепь не разомкнута.
+func (b *Breaker) RemoveDependency(ctx context.Context, taskID, dependsOnID int) error {
+	return b.do(ctx, func() error {
+		return b.inner.RemoveDependency(ctx, taskID, dependsOnID)
+	})
+}
+
+// DependenciesOf выполняет вызов DependenciesOf, если цепь не разомкнута.
+func (b *Breaker) DependenciesOf(ctx context.Context, taskID int) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.DependenciesOf(ctx, taskID)
+		return err
+	})
+	return res, err
+}
+
+// BlockedBy выполняет вызов BlockedBy, если цепь не разомкнута.
+func (b *Breaker) BlockedBy(ctx context.Context, taskID int) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.BlockedBy(ctx, taskID)
+		return err
+	})
+	return res, err
+}
+
+// BeginTx выполняет вызов BeginTx, если цепь не разомкнута.
+func (b *Breaker) BeginTx(ctx context.Context) (storage.TxStorage, error) {
+	var res storage.TxStorage
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.BeginTx(ctx)
+		return err
+	})
+	return res, err
+}
+
+// AuditLog выполняет вызов AuditLog, если цепь не разомкнута.
+func (b *Breaker) AuditLog(ctx context.Context, taskID int) ([]storage.AuditEntry, error) {
+	var res []storage.AuditEntry
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.AuditLog(ctx, taskID)
+		return err
+	})
+	return res, err
+}
+
+// RecordChange выполняет вызов RecordChange, если цепь не разомкнута.
+func (b *Breaker) RecordChange(ctx context.Context, e storage.AuditEntry) error {
+	return b.do(ctx, func() error {
+		return b.inner.RecordChange(ctx, e)
+	})
+}
+
+// SearchTasks выполняет вызов SearchTasks, если цепь не разомкнута.
+func (b *Breaker) SearchTasks(ctx context.Context, query string, limit int) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.SearchTasks(ctx, query, limit)
+		return err
+	})
+	return res, err
+}
+
+// TasksByMetadataKey выполняет вызов TasksByMetadataKey, если цепь не разомкнута.
+func (b *Breaker) TasksByMetadataKey(ctx context.Context, key, value string) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TasksByMetadataKey(ctx, key, value)
+		return err
+	})
+	return res, err
+}
+
+// AssignmentHistory выполняет вызов AssignmentHistory, если цепь не разомкнута.
+func (b *Breaker) AssignmentHistory(ctx context.Context, taskID int) ([]storage.AssignmentEvent, error) {
+	var res []storage.AssignmentEvent
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.AssignmentHistory(ctx, taskID)
+		return err
+	})
+	return res, err
+}
+
+// RecordAssignment выполняет вызов RecordAssignment, если цепь не разомкнута.
+func (b *Breaker) RecordAssignment(ctx context.Context, e storage.AssignmentEvent) error {
+	return b.do(ctx, func() error {
+		return b.inner.RecordAssignment(ctx, e)
+	})
+}
+
+// AddRecurrenceRule выполняет вызов AddRecurrenceRule, если цепь не разомкнута.
+func (b *Breaker) AddRecurrenceRule(ctx context.Context, rule storage.RecurrenceRule) error {
+	return b.do(ctx, func() error {
+		return b.inner.AddRecurrenceRule(ctx, rule)
+	})
+}
+
+// RecurrenceRuleByTask выполняет вызов RecurrenceRuleByTask, если цепь не разомкнута.
+func (b *Breaker) RecurrenceRuleByTask(ctx context.Context, taskID int) (*storage.RecurrenceRule, error) {
+	var res *storage.RecurrenceRule
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.RecurrenceRuleByTask(ctx, taskID)
+		return err
+	})
+	return res, err
+}
+
+// UpdateRecurrenceRule выполняет вызов UpdateRecurrenceRule, если цепь не разомкнута.
+func (b *Breaker) UpdateRecurrenceRule(ctx context.Context, rule storage.RecurrenceRule) error {
+	return b.do(ctx, func() error {
+		return b.inner.UpdateRecurrenceRule(ctx, rule)
+	})
+}
+
+// DeleteRecurrenceRule выполняет вызов DeleteRecurrenceRule, если цепь не разомкнута.
+func (b *Breaker) DeleteRecurrenceRule(ctx context.Context, taskID int) error {
+	return b.do(ctx, func() error {
+		return b.inner.DeleteRecurrenceRule(ctx, taskID)
+	})
+}
+
+// DueRecurrences выполняет вызов DueRecurrences, если цепь не разомкнута.
+func (b *Breaker) DueRecurrences(ctx context.Context, now int64) ([]storage.RecurrenceRule, error) {
+	var res []storage.RecurrenceRule
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.DueRecurrences(ctx, now)
+		return err
+	})
+	return res, err
+}
+
+// SpawnRecurringTask выполняет вызов SpawnRecurringTask, если цепь не разомкнута.
+func (b *Breaker) SpawnRecurringTask(ctx context.Context, rule storage.RecurrenceRule) (int, error) {
+	var res int
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.SpawnRecurringTask(ctx, rule)
+		return err
+	})
+	return res, err
+}
+
+// ArchiveTask выполняет вызов ArchiveTask, если цепь не разомкнута.
+func (b *Breaker) ArchiveTask(ctx context.Context, taskID int) error {
+	return b.do(ctx, func() error {
+		return b.inner.ArchiveTask(ctx, taskID)
+	})
+}
+
+// ArchivedTasks выполняет вызов ArchivedTasks, если цепь не разомкнута.
+func (b *Breaker) ArchivedTasks(ctx context.Context) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.ArchivedTasks(ctx)
+		return err
+	})
+	return res, err
+}
+
+// UnarchiveTask выполняет вызов UnarchiveTask, если цепь не разомкнута.
+func (b *Breaker) UnarchiveTask(ctx context.Context, taskID int) error {
+	return b.do(ctx, func() error {
+		return b.inner.UnarchiveTask(ctx, taskID)
+	})
+}
+
+// TasksByLabels выполняет вызов TasksByLabels, если цепь не разомкнута.
+func (b *Breaker) TasksByLabels(ctx context.Context, labelIDs []int, mode storage.LabelFilterMode) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TasksByLabels(ctx, labelIDs, mode)
+		return err
+	})
+	return res, err
+}
+
+// TasksOpenedBetween выполняет вызов TasksOpenedBetween, если цепь не разомкнута.
+func (b *Breaker) TasksOpenedBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TasksOpenedBetween(ctx, from, to)
+		return err
+	})
+	return res, err
+}
+
+// TasksClosedBetween выполняет вызов TasksClosedBetween, если цепь не разомкнута.
+func (b *Breaker) TasksClosedBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TasksClosedBetween(ctx, from, to)
+		return err
+	})
+	return res, err
+}
+
+// AddSprint выполняет вызов AddSprint, если цепь не разомкнута.
+func (b *Breaker) AddSprint(ctx context.Context, sp storage.Sprint) (int, error) {
+	var res int
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.AddSprint(ctx, sp)
+		return err
+	})
+	return res, err
+}
+
+// SprintByID выполняет вызов SprintByID, если цепь не разомкнута.
+func (b *Breaker) SprintByID(ctx context.Context, sprintID int) (*storage.Sprint, error) {
+	var res *storage.Sprint
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.SprintByID(ctx, sprintID)
+		return err
+	})
+	return res, err
+}
+
+// Sprints выполняет вызов Sprints, если цепь не разомкнута.
+func (b *Breaker) Sprints(ctx context.Context) ([]storage.Sprint, error) {
+	var res []storage.Sprint
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.Sprints(ctx)
+		return err
+	})
+	return res, err
+}
+
+// UpdateSprint выполняет вызов UpdateSprint, если цепь не разомкнута.
+func (b *Breaker) UpdateSprint(ctx context.Context, sp storage.Sprint) error {
+	return b.do(ctx, func() error {
+		return b.inner.UpdateSprint(ctx, sp)
+	})
+}
+
+// DeleteSprint выполняет вызов DeleteSprint, если цепь не разомкнута.
+func (b *Breaker) DeleteSprint(ctx context.Context, sprintID int) error {
+	return b.do(ctx, func() error {
+		return b.inner.DeleteSprint(ctx, sprintID)
+	})
+}
+
+// AssignTaskToSprint выполняет вызов AssignTaskToSprint, если цепь не разомкнута.
+func (b *Breaker) AssignTaskToSprint(ctx context.Context, taskID, sprintID int) error {
+	return b.do(ctx, func() error {
+		return b.inner.AssignTaskToSprint(ctx, taskID, sprintID)
+	})
+}
+
+// RemoveTaskFromSprint выполняет вызов RemoveTaskFromSprint, если цепь не разомкнута.
+func (b *Breaker) RemoveTaskFromSprint(ctx context.Context, taskID, sprintID int) error {
+	return b.do(ctx, func() error {
+		return b.inner.RemoveTaskFromSprint(ctx, taskID, sprintID)
+	})
+}
+
+// TasksBySprint выполняет вызов TasksBySprint, если цепь не разомкнута.
+func (b *Breaker) TasksBySprint(ctx context.Context, sprintID int) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TasksBySprint(ctx, sprintID)
+		return err
+	})
+	return res, err
+}
+
+// SprintVelocity выполняет вызов SprintVelocity, если цепь не разомкнута.
+func (b *Breaker) SprintVelocity(ctx context.Context, sprintID int) (int, error) {
+	var res int
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.SprintVelocity(ctx, sprintID)
+		return err
+	})
+	return res, err
+}
+
+// BurndownData выполняет вызов BurndownData, если цепь не разомкнута.
+func (b *Breaker) BurndownData(ctx context.Context, sprintID int, buckets int) ([]storage.BurndownPoint, error) {
+	var res []storage.BurndownPoint
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.BurndownData(ctx, sprintID, buckets)
+		return err
+	})
+	return res, err
+}
+
+// UserWorkloads выполняет вызов UserWorkloads, если цепь не разомкнута.
+func (b *Breaker) UserWorkloads(ctx context.Context) ([]storage.UserWorkload, error) {
+	var res []storage.UserWorkload
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.UserWorkloads(ctx)
+		return err
+	})
+	return res, err
+}
+
+// UserWorkload выполняет вызов UserWorkload, если цепь не разомкнута.
+func (b *Breaker) UserWorkload(ctx context.Context, userID int) (*storage.UserWorkload, error) {
+	var res *storage.UserWorkload
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.UserWorkload(ctx, userID)
+		return err
+	})
+	return res, err
+}
+
+// TaskStats выполняет вызов TaskStats, если цепь не разомкнута.
+func (b *Breaker) TaskStats(ctx context.Context) (*storage.TaskStats, error) {
+	var res *storage.TaskStats
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TaskStats(ctx)
+		return err
+	})
+	return res, err
+}
+
+// LabelStats выполняет вызов LabelStats, если цепь не разомкнута.
+func (b *Breaker) LabelStats(ctx context.Context) ([]storage.LabelStat, error) {
+	var res []storage.LabelStat
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.LabelStats(ctx)
+		return err
+	})
+	return res, err
+}
+
+// TopLabels выполняет вызов TopLabels, если цепь не разомкнута.
+func (b *Breaker) TopLabels(ctx context.Context, n int) ([]storage.LabelStat, error) {
+	var res []storage.LabelStat
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TopLabels(ctx, n)
+		return err
+	})
+	return res, err
+}
+
+// RecordActivity выполняет вызов RecordActivity, если цепь не разомкнута.
+func (b *Breaker) RecordActivity(ctx context.Context, e storage.ActivityEvent) error {
+	return b.do(ctx, func() error {
+		return b.inner.RecordActivity(ctx, e)
+	})
+}
+
+// ActivityFeed выполняет вызов ActivityFeed, если цепь не разомкнута.
+func (b *Breaker) ActivityFeed(ctx context.Context, taskID int, limit int) ([]storage.ActivityEvent, error) {
+	var res []storage.ActivityEvent
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.ActivityFeed(ctx, taskID, limit)
+		return err
+	})
+	return res, err
+}
+
+// GlobalActivityFeed выполняет вызов GlobalActivityFeed, если цепь не разомкнута.
+func (b *Breaker) GlobalActivityFeed(ctx context.Context, limit int) ([]storage.ActivityEvent, error) {
+	var res []storage.ActivityEvent
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.GlobalActivityFeed(ctx, limit)
+		return err
+	})
+	return res, err
+}
+
+// AppendEvents выполняет вызов AppendEvents, если цепь не разомкнута.
+func (b *Breaker) AppendEvents(ctx context.Context, streamID string, expectedSeq int64, events []storage.Event) error {
+	return b.do(ctx, func() error {
+		return b.inner.AppendEvents(ctx, streamID, expectedSeq, events)
+	})
+}
+
+// LoadEvents выполняет вызов LoadEvents, если цепь не разомкнута.
+func (b *Breaker) LoadEvents(ctx context.Context, streamID string, fromSeq int64) ([]storage.Event, error) {
+	var res []storage.Event
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.LoadEvents(ctx, streamID, fromSeq)
+		return err
+	})
+	return res, err
+}
+
+// AppendOutbox выполняет вызов AppendOutbox, если цепь не разомкнута.
+func (b *Breaker) AppendOutbox(ctx context.Context, tx storage.TxStorage, msgs []storage.OutboxMessage) error {
+	return b.do(ctx, func() error {
+		return b.inner.AppendOutbox(ctx, tx, msgs)
+	})
+}
+
+// PendingOutbox выполняет вызов PendingOutbox, если цепь не разомкнута.
+func (b *Breaker) PendingOutbox(ctx context.Context, limit int) ([]storage.OutboxMessage, error) {
+	var res []storage.OutboxMessage
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.PendingOutbox(ctx, limit)
+		return err
+	})
+	return res, err
+}
+
+// MarkOutboxSent выполняет вызов MarkOutboxSent, если цепь не разомкнута.
+func (b *Breaker) MarkOutboxSent(ctx context.Context, ids []int64) error {
+	return b.do(ctx, func() error {
+		return b.inner.MarkOutboxSent(ctx, ids)
+	})
+}
+
+// TasksClosedInRange выполняет вызов TasksClosedInRange, если цепь не разомкнута.
+func (b *Breaker) TasksClosedInRange(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TasksClosedInRange(ctx, from, to)
+		return err
+	})
+	return res, err
+}
+
+// TasksUnassigned выполняет вызов TasksUnassigned, если цепь не разомкнута.
+func (b *Breaker) TasksUnassigned(ctx context.Context) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TasksUnassigned(ctx)
+		return err
+	})
+	return res, err
+}
+
+// AssignTask выполняет вызов AssignTask, если цепь не разомкнута.
+func (b *Breaker) AssignTask(ctx context.Context, taskID, userID int) error {
+	return b.do(ctx, func() error {
+		return b.inner.AssignTask(ctx, taskID, userID)
+	})
+}
+
+// TaskByExternalID выполняет вызов TaskByExternalID, если цепь не разомкнута.
+func (b *Breaker) TaskByExternalID(ctx context.Context, system, externalID string) (*storage.Task, error) {
+	var res *storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TaskByExternalID(ctx, system, externalID)
+		return err
+	})
+	return res, err
+}
+
+// SetNotificationPreference выполняет вызов SetNotificationPreference, если цепь не разомкнута.
+func (b *Breaker) SetNotificationPreference(ctx context.Context, p storage.NotificationPreference) error {
+	return b.do(ctx, func() error {
+		return b.inner.SetNotificationPreference(ctx, p)
+	})
+}
+
+// GetNotificationPreference выполняет вызов GetNotificationPreference, если цепь не разомкнута.
+func (b *Breaker) GetNotificationPreference(ctx context.Context, userID int) (*storage.NotificationPreference, error) {
+	var res *storage.NotificationPreference
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.GetNotificationPreference(ctx, userID)
+		return err
+	})
+	return res, err
+}
+
+// UsersSubscribedToTask выполняет вызов UsersSubscribedToTask, если цепь не разомкнута.
+func (b *Breaker) UsersSubscribedToTask(ctx context.Context, taskID int, event string) ([]storage.User, error) {
+	var res []storage.User
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.UsersSubscribedToTask(ctx, taskID, event)
+		return err
+	})
+	return res, err
+}
+
+// WatchTask выполняет вызов WatchTask, если цепь не разомкнута.
+func (b *Breaker) WatchTask(ctx context.Context, userID, taskID int) error {
+	return b.do(ctx, func() error {
+		return b.inner.WatchTask(ctx, userID, taskID)
+	})
+}
+
+// UnwatchTask выполняет вызов UnwatchTask, если цепь не разомкнута.
+func (b *Breaker) UnwatchTask(ctx context.Context, userID, taskID int) error {
+	return b.do(ctx, func() error {
+		return b.inner.UnwatchTask(ctx, userID, taskID)
+	})
+}
+
+// WatchersOfTask выполняет вызов WatchersOfTask, если цепь не разомкнута.
+func (b *Breaker) WatchersOfTask(ctx context.Context, taskID int) ([]storage.User, error) {
+	var res []storage.User
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.WatchersOfTask(ctx, taskID)
+		return err
+	})
+	return res, err
+}
+
+// CreateAPIKey выполняет вызов CreateAPIKey, если цепь не разомкнута.
+func (b *Breaker) CreateAPIKey(ctx context.Context, k storage.APIKey) (string, error) {
+	var res string
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.CreateAPIKey(ctx, k)
+		return err
+	})
+	return res, err
+}
+
+// APIKeyByKey выполняет вызов APIKeyByKey, если цепь не разомкнута.
+func (b *Breaker) APIKeyByKey(ctx context.Context, raw string) (*storage.APIKey, error) {
+	var res *storage.APIKey
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.APIKeyByKey(ctx, raw)
+		return err
+	})
+	return res, err
+}
+
+// RevokeAPIKey выполняет вызов RevokeAPIKey, если цепь не разомкнута.
+func (b *Breaker) RevokeAPIKey(ctx context.Context, keyID int) error {
+	return b.do(ctx, func() error {
+		return b.inner.RevokeAPIKey(ctx, keyID)
+	})
+}
+
+// APIKeysByUser выполняет вызов APIKeysByUser, если цепь не разомкнута.
+func (b *Breaker) APIKeysByUser(ctx context.Context, userID int) ([]storage.APIKey, error) {
+	var res []storage.APIKey
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.APIKeysByUser(ctx, userID)
+		return err
+	})
+	return res, err
+}
+
+// AssignRole выполняет вызов AssignRole, если цепь не разомкнута.
+func (b *Breaker) AssignRole(ctx context.Context, userID int, role storage.Role) error {
+	return b.do(ctx, func() error {
+		return b.inner.AssignRole(ctx, userID, role)
+	})
+}
+
+// RoleOfUser выполняет вызов RoleOfUser, если цепь не разомкнута.
+func (b *Breaker) RoleOfUser(ctx context.Context, userID int) (storage.Role, error) {
+	var res storage.Role
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.RoleOfUser(ctx, userID)
+		return err
+	})
+	return res, err
+}
+
+// UsersWithRole выполняет вызов UsersWithRole, если цепь не разомкнута.
+func (b *Breaker) UsersWithRole(ctx context.Context, role storage.Role) ([]storage.User, error) {
+	var res []storage.User
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.UsersWithRole(ctx, role)
+		return err
+	})
+	return res, err
+}
+
+// ExportUserData выполняет вызов ExportUserData, если цепь не разомкнута.
+func (b *Breaker) ExportUserData(ctx context.Context, userID int) (*storage.UserDataExport, error) {
+	var res *storage.UserDataExport
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.ExportUserData(ctx, userID)
+		return err
+	})
+	return res, err
+}
+
+// EraseUser выполняет вызов EraseUser, если цепь не разомкнута.
+func (b *Breaker) EraseUser(ctx context.Context, userID int) error {
+	return b.do(ctx, func() error {
+		return b.inner.EraseUser(ctx, userID)
+	})
+}
+
+// StaleTasks выполняет вызов StaleTasks, если цепь не разомкнута.
+func (b *Breaker) StaleTasks(ctx context.Context, staleAfterSeconds int64, now int64) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.StaleTasks(ctx, staleAfterSeconds, now)
+		return err
+	})
+	return res, err
+}
+
+// MarkTaskStale выполняет вызов MarkTaskStale, если цепь не разомкнута.
+func (b *Breaker) MarkTaskStale(ctx context.Context, taskID int) error {
+	return b.do(ctx, func() error {
+		return b.inner.MarkTaskStale(ctx, taskID)
+	})
+}
+
+// SimilarTasks выполняет вызов SimilarTasks, если цепь не разомкнута.
+func (b *Breaker) SimilarTasks(ctx context.Context, title string, threshold float64) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.SimilarTasks(ctx, title, threshold)
+		return err
+	})
+	return res, err
+}
+
+// AddTaskLink выполняет вызов AddTaskLink, если цепь не разомкнута.
+func (b *Breaker) AddTaskLink(ctx context.Context, l storage.TaskLinkRecord) (int, error) {
+	var res int
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.AddTaskLink(ctx, l)
+		return err
+	})
+	return res, err
+}
+
+// TaskLinks выполняет вызов TaskLinks, если цепь не разомкнута.
+func (b *Breaker) TaskLinks(ctx context.Context, taskID int) ([]storage.TaskLinkRecord, error) {
+	var res []storage.TaskLinkRecord
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TaskLinks(ctx, taskID)
+		return err
+	})
+	return res, err
+}
+
+// DeleteTaskLink выполняет вызов DeleteTaskLink, если цепь не разомкнута.
+func (b *Breaker) DeleteTaskLink(ctx context.Context, linkID int) error {
+	return b.do(ctx, func() error {
+		return b.inner.DeleteTaskLink(ctx, linkID)
+	})
+}
+
+// RegisterWebhook выполняет вызов RegisterWebhook, если цепь не разомкнута.
+func (b *Breaker) RegisterWebhook(ctx context.Context, w storage.Webhook) (int, error) {
+	var res int
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.RegisterWebhook(ctx, w)
+		return err
+	})
+	return res, err
+}
+
+// Webhooks выполняет вызов Webhooks, если цепь не разомкнута.
+func (b *Breaker) Webhooks(ctx context.Context) ([]storage.Webhook, error) {
+	var res []storage.Webhook
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.Webhooks(ctx)
+		return err
+	})
+	return res, err
+}
+
+// WebhookByID выполняет вызов WebhookByID, если цепь не разомкнута.
+func (b *Breaker) WebhookByID(ctx context.Context, webhookID int) (*storage.Webhook, error) {
+	var res *storage.Webhook
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.WebhookByID(ctx, webhookID)
+		return err
+	})
+	return res, err
+}
+
+// UpdateWebhook выполняет вызов UpdateWebhook, если цепь не разомкнута.
+func (b *Breaker) UpdateWebhook(ctx context.Context, w storage.Webhook) error {
+	return b.do(ctx, func() error {
+		return b.inner.UpdateWebhook(ctx, w)
+	})
+}
+
+// DeleteWebhook выполняет вызов DeleteWebhook, если цепь не разомкнута.
+func (b *Breaker) DeleteWebhook(ctx context.Context, webhookID int) error {
+	return b.do(ctx, func() error {
+		return b.inner.DeleteWebhook(ctx, webhookID)
+	})
+}
+
+// LockTask выполняет вызов LockTask, если цепь не разомкнута.
+func (b *Breaker) LockTask(ctx context.Context, taskID int) error {
+	return b.do(ctx, func() error {
+		return b.inner.LockTask(ctx, taskID)
+	})
+}
+
+// UnlockTask выполняет вызов UnlockTask, если цепь не разомкнута.
+func (b *Breaker) UnlockTask(ctx context.Context, taskID int) error {
+	return b.do(ctx, func() error {
+		return b.inner.UnlockTask(ctx, taskID)
+	})
+}
+
+// UpdateTasksAssignee выполняет вызов UpdateTasksAssignee, если цепь не разомкнута.
+func (b *Breaker) UpdateTasksAssignee(ctx context.Context, taskIDs []int, assigneeID int) (int64, error) {
+	var res int64
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.UpdateTasksAssignee(ctx, taskIDs, assigneeID)
+		return err
+	})
+	return res, err
+}
+
+// UpdateTasksStatus выполняет вызов UpdateTasksStatus, если цепь не разомкнута.
+func (b *Breaker) UpdateTasksStatus(ctx context.Context, taskIDs []int, status storage.Status) (int64, error) {
+	var res int64
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.UpdateTasksStatus(ctx, taskIDs, status)
+		return err
+	})
+	return res, err
+}
+
+// TasksByTag выполняет вызов TasksByTag, если цепь не разомкнута.
+func (b *Breaker) TasksByTag(ctx context.Context, tag string) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TasksByTag(ctx, tag)
+		return err
+	})
+	return res, err
+}
+
+// AllTags выполняет вызов AllTags, если цепь не разомкнута.
+func (b *Breaker) AllTags(ctx context.Context) ([]string, error) {
+	var res []string
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.AllTags(ctx)
+		return err
+	})
+	return res, err
+}
+
+// TasksByRank выполняет вызов TasksByRank, если цепь не разомкнута.
+func (b *Breaker) TasksByRank(ctx context.Context) ([]storage.Task, error) {
+	var res []storage.Task
+	err := b.do(ctx, func() (err error) {
+		res, err = b.inner.TasksByRank(ctx)
+		return err
+	})
+	return res, err
+}
+
+// UpdateTaskRank выполняет вызов UpdateTaskRank, если цепь не разомкнута.
+func (b *Breaker) UpdateTaskRank(ctx context.Context, taskID int, rank float64) error {
+	return b.do(ctx, func() error {
+		return b.inner.UpdateTaskRank(ctx, taskID, rank)
+	})
+}
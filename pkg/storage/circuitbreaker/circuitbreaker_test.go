@@ -0,0 +1,141 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+	"skillfactory/30.8.1/pkg/storage/mock"
+	"testing"
+	"time"
+)
+
+var errDB = errors.New("connection refused")
+
+// newTestBreaker возвращает предохранитель над заглушкой, TaskCount которой
+// возвращает ошибку *fail, и счётчик обращений к заглушке.
+func newTestBreaker(cfg Config, fail *error) (*Breaker, *int) {
+	calls := 0
+	inner := &mock.Mock{
+		TaskCountFunc: func(ctx context.Context) (int, error) {
+			calls++
+			return 0, *fail
+		},
+	}
+	return New(inner, cfg), &calls
+}
+
+func TestTransitions(t *testing.T) {
+	ctx := context.Background()
+	var fail error
+	b, calls := newTestBreaker(Config{
+		FailureThreshold: 2,
+		SuccessThreshold: 2,
+		OpenDuration:     20 * time.Millisecond,
+	}, &fail)
+
+	check := func(want State) {
+		t.Helper()
+		if got := b.State(); got != want {
+			t.Fatalf("State() = %v, want %v", got, want)
+		}
+	}
+
+	// успешный вызов сбрасывает счётчик ошибок
+	fail = errDB
+	b.TaskCount(ctx)
+	fail = nil
+	b.TaskCount(ctx)
+	fail = errDB
+	b.TaskCount(ctx)
+	check(StateClosed)
+
+	b.TaskCount(ctx)
+	check(StateOpen)
+
+	n := *calls
+	_, err := b.TaskCount(ctx)
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("TaskCount() error = %v, want ErrCircuitOpen", err)
+	}
+	if *calls != n {
+		t.Fatal("open breaker called the inner storage")
+	}
+
+	// ошибка пробного вызова снова размыкает цепь
+	time.Sleep(30 * time.Millisecond)
+	b.TaskCount(ctx)
+	check(StateOpen)
+
+	time.Sleep(30 * time.Millisecond)
+	fail = nil
+	_, err = b.TaskCount(ctx)
+	if err != nil {
+		t.Fatalf("TaskCount() error = %v", err)
+	}
+	check(StateHalfOpen)
+
+	b.TaskCount(ctx)
+	check(StateClosed)
+}
+
+func TestIsFailure(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{"success", context.Background(), nil, false},
+		{"database error", context.Background(), errDB, true},
+		{"not found", context.Background(), storage.ErrNotFound, false},
+		{"wrapped conflict", context.Background(), fmt.Errorf("add: %w", storage.ErrConflict), false},
+		{"invalid argument", context.Background(), storage.ErrInvalidArgument, false},
+		{"version conflict", context.Background(), storage.ErrVersionConflict, false},
+		{"locked", context.Background(), storage.ErrLocked, false},
+		{"forbidden", context.Background(), storage.ErrForbidden, false},
+		{"tx closed", context.Background(), storage.ErrTxClosed, false},
+		{"canceled by caller", canceled, context.Canceled, false},
+		{"canceled by storage", context.Background(), context.Canceled, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isFailure(tt.ctx, tt.err); got != tt.want {
+				t.Errorf("isFailure() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Ошибки предметной области не размыкают цепь.
+func TestDomainErrors(t *testing.T) {
+	fail := storage.ErrNotFound
+	b, _ := newTestBreaker(Config{FailureThreshold: 1, SuccessThreshold: 1, OpenDuration: time.Hour}, &fail)
+	for i := 0; i < 3; i++ {
+		b.TaskCount(context.Background())
+	}
+	if got := b.State(); got != StateClosed {
+		t.Errorf("State() = %v, want %v", got, StateClosed)
+	}
+}
+
+// Ошибка функции обратного вызова TasksIter не считается сбоем.
+func TestTasksIterCallbackError(t *testing.T) {
+	errStop := errors.New("stop")
+	inner := &mock.Mock{
+		TasksIterFunc: func(ctx context.Context, fn func(storage.Task) error) error {
+			return fn(storage.Task{ID: 1})
+		},
+	}
+	b := New(inner, Config{FailureThreshold: 1, SuccessThreshold: 1, OpenDuration: time.Hour})
+	err := b.TasksIter(context.Background(), func(storage.Task) error { return errStop })
+	if !errors.Is(err, errStop) {
+		t.Fatalf("TasksIter() error = %v, want %v", err, errStop)
+	}
+	if got := b.State(); got != StateClosed {
+		t.Errorf("State() = %v, want %v", got, StateClosed)
+	}
+}
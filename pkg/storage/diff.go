@@ -0,0 +1,50 @@
+package storage
+
+import "reflect"
+
+// FieldChange - изменение поля задачи.
+type FieldChange struct {
+	Field    string // имя поля структуры Task
+	OldValue interface{}
+	NewValue interface{}
+}
+
+// Diff сравнивает все экспортируемые поля задач, кроме ID,
+// и возвращает отличающиеся в порядке объявления полей.
+// Указатели сравниваются по значениям, на которые они указывают,
+// а пустые и nil-отображения (Metadata) считаются равными.
+func Diff(old, new Task) []FieldChange {
+	ov := reflect.ValueOf(old)
+	nv := reflect.ValueOf(new)
+	typ := ov.Type()
+
+	var changes []FieldChange
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if !f.IsExported() || f.Name == "ID" {
+			continue
+		}
+		o, n := ov.Field(i), nv.Field(i)
+		if equalValues(o, n) {
+			continue
+		}
+		changes = append(changes, FieldChange{
+			Field:    f.Name,
+			OldValue: o.Interface(),
+			NewValue: n.Interface(),
+		})
+	}
+	return changes
+}
+
+// equalValues сравнивает значения полей для Diff. Пустые и nil
+// отображения и срезы считаются равными.
+func equalValues(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Map, reflect.Slice:
+		if a.Len() == 0 && b.Len() == 0 {
+			return true
+		}
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
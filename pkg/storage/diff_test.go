@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"database/sql"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+// fullTask возвращает задачу, все поля которой отличаются от полей
+// fullTask(!alt) (и от нулевых значений).
+func fullTask(alt bool) Task {
+	n := 1
+	if alt {
+		n = 2
+	}
+	s := string(rune('a' + n))
+	return Task{
+		ID:             n,
+		Opened:         int64(n),
+		Closed:         int64(n),
+		AuthorID:       n,
+		AssignedID:     n,
+		Title:          s,
+		Content:        s,
+		DeletedAt:      sql.NullTime{Time: time.Unix(int64(n), 0), Valid: true},
+		Status:         Status(n),
+		Priority:       Priority(n),
+		DueAt:          ptr(int64(n)),
+		ProjectID:      ptr(n),
+		Metadata:       map[string]string{"k": s},
+		IdempotencyKey: ptr(s),
+		Version:        n,
+		ExternalSystem: ptr(s),
+		ExternalID:     ptr(s),
+		Stale:          alt,
+		Tags:           []string{s},
+		Rank:           float64(n),
+	}
+}
+
+func TestDiff(t *testing.T) {
+	// указатели и отображения сравниваются по значениям
+	same := fullTask(false)
+	same.DueAt = ptr(*same.DueAt)
+	same.Metadata = map[string]string{"k": same.Metadata["k"]}
+	if got := Diff(fullTask(false), same); len(got) != 0 {
+		t.Errorf("Diff(identical) = %v, want none", got)
+	}
+	if got := Diff(Task{Metadata: map[string]string{}}, Task{}); len(got) != 0 {
+		t.Errorf("Diff(empty and nil Metadata) = %v, want none", got)
+	}
+	if got := Diff(Task{Tags: []string{}}, Task{}); len(got) != 0 {
+		t.Errorf("Diff(empty and nil Tags) = %v, want none", got)
+	}
+	// ID не сравнивается
+	if got := Diff(Task{ID: 1}, Task{ID: 2}); len(got) != 0 {
+		t.Errorf("Diff(different ID) = %v, want none", got)
+	}
+
+	got := Diff(Task{Title: "old"}, Task{Title: "new"})
+	want := []FieldChange{{Field: "Title", OldValue: "old", NewValue: "new"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff(one change) = %v, want %v", got, want)
+	}
+
+	got = Diff(Task{}, Task{DueAt: ptr(int64(5))})
+	if len(got) != 1 || got[0].Field != "DueAt" || got[0].OldValue.(*int64) != nil || *got[0].NewValue.(*int64) != 5 {
+		t.Errorf("Diff(DueAt set) = %v, want one DueAt change", got)
+	}
+}
+
+// Изменение всех полей возвращает все поля, кроме ID, в порядке объявления.
+func TestDiffAllFields(t *testing.T) {
+	old, new := fullTask(false), fullTask(true)
+	got := Diff(old, new)
+
+	var fields []string
+	for _, c := range got {
+		fields = append(fields, c.Field)
+	}
+	var want []string
+	typ := reflect.TypeOf(Task{})
+	for i := 0; i < typ.NumField(); i++ {
+		if name := typ.Field(i).Name; name != "ID" {
+			want = append(want, name)
+		}
+	}
+	if !slices.Equal(fields, want) {
+		t.Fatalf("Diff() fields = %v, want %v", fields, want)
+	}
+
+	ov, nv := reflect.ValueOf(old), reflect.ValueOf(new)
+	for _, c := range got {
+		if !reflect.DeepEqual(c.OldValue, ov.FieldByName(c.Field).Interface()) ||
+			!reflect.DeepEqual(c.NewValue, nv.FieldByName(c.Field).Interface()) {
+			t.Errorf("Diff() %s = %v -> %v, want %v -> %v", c.Field, c.OldValue, c.NewValue,
+				ov.FieldByName(c.Field), nv.FieldByName(c.Field))
+		}
+	}
+}
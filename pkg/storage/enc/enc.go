@@ -0,0 +1,454 @@
+// Пакет enc реализует шифрование содержимого задач (Task.Content)
+// на уровне полей.
+package enc
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"skillfactory/30.8.1/pkg/storage"
+)
+
+// Encryptor шифрует и расшифровывает произвольные данные.
+type Encryptor interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// ErrDecrypt возвращается, если данные не удалось расшифровать:
+// они повреждены или зашифрованы другим ключом.
+var ErrDecrypt = errors.New("decryption failed")
+
+// AESGCM - Encryptor на основе AES-GCM. Случайный nonce
+// записывается перед шифротекстом.
+type AESGCM struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCM создаёт Encryptor с 256-битным ключом.
+func NewAESGCM(key []byte) (*AESGCM, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("%w: key must be 32 bytes, got %d", storage.ErrInvalidArgument, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &AESGCM{aead: aead}, nil
+}
+
+// Encrypt шифрует plaintext.
+func (e *AESGCM) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, e.aead.NonceSize(), e.aead.NonceSize()+len(plaintext)+e.aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return e.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Decrypt расшифровывает результат Encrypt.
+func (e *AESGCM) Decrypt(ciphertext []byte) ([]byte, error) {
+	n := e.aead.NonceSize()
+	if len(ciphertext) < n {
+		return nil, ErrDecrypt
+	}
+	plaintext, err := e.aead.Open(nil, ciphertext[:n], ciphertext[n:], nil)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+// EncryptedStorage оборачивает storage.Interface и хранит Task.Content
+// в зашифрованном виде (шифротекст в base64). Остальные поля хранятся
+// открыто. Пустое содержимое не шифруется.
+//
+// Поиск по содержимому (SearchTasks) в обёрнутом хранилище видит только
+// шифротекст. Транзакции (BeginTx) оборачиваются так же, как хранилище;
+// методы, не возвращающие задачи, передаются обёрнутому хранилищу
+// без изменений.
+type EncryptedStorage struct {
+	storage.Interface
+	enc Encryptor
+}
+
+// Конструктор, принимает оборачиваемое хранилище и 256-битный ключ.
+func New(inner storage.Interface, key []byte) (*EncryptedStorage, error) {
+	e, err := NewAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return NewWithEncryptor(inner, e), nil
+}
+
+// NewWithEncryptor создаёт обёртку с произвольным Encryptor.
+func NewWithEncryptor(inner storage.Interface, e Encryptor) *EncryptedStorage {
+	s := EncryptedStorage{
+		Interface: inner,
+		enc:       e,
+	}
+	return &s
+}
+
+// encrypt возвращает зашифрованное содержимое.
+func (s *EncryptedStorage) encrypt(content string) (string, error) {
+	if content == "" {
+		return "", nil
+	}
+	b, err := s.enc.Encrypt([]byte(content))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// decrypt возвращает расшифрованное содержимое.
+func (s *EncryptedStorage) decrypt(content string) (string, error) {
+	if content == "" {
+		return "", nil
+	}
+	b, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	b, err = s.enc.Decrypt(b)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// encryptTasks возвращает копию задач с зашифрованным содержимым.
+func (s *EncryptedStorage) encryptTasks(tasks []storage.Task) ([]storage.Task, error) {
+	out := make([]storage.Task, len(tasks))
+	for i, t := range tasks {
+		c, err := s.encrypt(t.Content)
+		if err != nil {
+			return nil, err
+		}
+		t.Content = c
+		out[i] = t
+	}
+	return out, nil
+}
+
+// decryptTasks расшифровывает содержимое задач на месте.
+func (s *EncryptedStorage) decryptTasks(tasks []storage.Task, err error) ([]storage.Task, error) {
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		if tasks[i].Content, err = s.decrypt(tasks[i].Content); err != nil {
+			return nil, err
+		}
+	}
+	return tasks, nil
+}
+
+// decryptTask расшифровывает содержимое одной задачи.
+func (s *EncryptedStorage) decryptTask(t *storage.Task, err error) (*storage.Task, error) {
+	if err != nil {
+		return nil, err
+	}
+	if t.Content, err = s.decrypt(t.Content); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// AddTask шифрует содержимое и передаёт вызов обёрнутому хранилищу.
+func (s *EncryptedStorage) AddTask(ctx context.Context, t storage.Task) (int, error) {
+	c, err := s.encrypt(t.Content)
+	if err != nil {
+		return 0, err
+	}
+	t.Content = c
+	return s.Interface.AddTask(ctx, t)
+}
+
+// AddTaskWithLabels шифрует содержимое и передаёт вызов обёрнутому хранилищу.
+func (s *EncryptedStorage) AddTaskWithLabels(ctx context.Context, t storage.Task, labelIDs []int) (int, error) {
+	c, err := s.encrypt(t.Content)
+	if err != nil {
+		return 0, err
+	}
+	t.Content = c
+	return s.Interface.AddTaskWithLabels(ctx, t, labelIDs)
+}
+
+// AddTasks шифрует содержимое и передаёт вызов обёрнутому хранилищу.
+func (s *EncryptedStorage) AddTasks(ctx context.Context, tasks []storage.Task) ([]int, error) {
+	tasks, err := s.encryptTasks(tasks)
+	if err != nil {
+		return nil, err
+	}
+	return s.Interface.AddTasks(ctx, tasks)
+}
+
+// AddTasksBatch шифрует содержимое и передаёт вызов обёрнутому хранилищу.
+func (s *EncryptedStorage) AddTasksBatch(ctx context.Context, tasks []storage.Task) ([]int, error) {
+	tasks, err := s.encryptTasks(tasks)
+	if err != nil {
+		return nil, err
+	}
+	return s.Interface.AddTasksBatch(ctx, tasks)
+}
+
+// ImportTasks шифрует содержимое и передаёт вызов обёрнутому хранилищу.
+func (s *EncryptedStorage) ImportTasks(ctx context.Context, tasks []storage.Task) error {
+	tasks, err := s.encryptTasks(tasks)
+	if err != nil {
+		return err
+	}
+	return s.Interface.ImportTasks(ctx, tasks)
+}
+
+// UpdateTask шифрует содержимое и передаёт вызов обёрнутому хранилищу.
+func (s *EncryptedStorage) UpdateTask(ctx context.Context, t storage.Task) error {
+	c, err := s.encrypt(t.Content)
+	if err != nil {
+		return err
+	}
+	t.Content = c
+	return s.Interface.UpdateTask(ctx, t)
+}
+
+// UpsertTask шифрует содержимое и передаёт вызов обёрнутому хранилищу.
+func (s *EncryptedStorage) UpsertTask(ctx context.Context, t storage.Task) (int, error) {
+	c, err := s.encrypt(t.Content)
+	if err != nil {
+		return 0, err
+	}
+	t.Content = c
+	return s.Interface.UpsertTask(ctx, t)
+}
+
+// PartialUpdateTask шифрует содержимое и передаёт вызов обёрнутому хранилищу.
+func (s *EncryptedStorage) PartialUpdateTask(ctx context.Context, taskID int, patch storage.TaskPatch) error {
+	if patch.Content != nil {
+		c, err := s.encrypt(*patch.Content)
+		if err != nil {
+			return err
+		}
+		patch.Content = &c
+	}
+	return s.Interface.PartialUpdateTask(ctx, taskID, patch)
+}
+
+// Tasks расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) Tasks(ctx context.Context) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.Tasks(ctx))
+}
+
+// TasksIter расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TasksIter(ctx context.Context, fn func(t storage.Task) error) error {
+	return s.Interface.TasksIter(ctx, func(t storage.Task) error {
+		var err error
+		if t.Content, err = s.decrypt(t.Content); err != nil {
+			return err
+		}
+		return fn(t)
+	})
+}
+
+// TasksList расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TasksList(ctx context.Context, opts storage.ListOptions) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.TasksList(ctx, opts))
+}
+
+// TasksAfter расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TasksAfter(ctx context.Context, afterID int, limit int) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.TasksAfter(ctx, afterID, limit))
+}
+
+// TaskById расшифровывает содержимое задачи, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TaskById(ctx context.Context, taskId int) (*storage.Task, error) {
+	return s.decryptTask(s.Interface.TaskById(ctx, taskId))
+}
+
+// TasksByAuthor расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TasksByAuthor(ctx context.Context, authorId int) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.TasksByAuthor(ctx, authorId))
+}
+
+// TasksByAssignee расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TasksByAssignee(ctx context.Context, assigneeID int) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.TasksByAssignee(ctx, assigneeID))
+}
+
+// TasksUnassigned расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TasksUnassigned(ctx context.Context) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.TasksUnassigned(ctx))
+}
+
+// TasksByLabel расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TasksByLabel(ctx context.Context, labelId int) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.TasksByLabel(ctx, labelId))
+}
+
+// TasksByLabels расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TasksByLabels(ctx context.Context, labelIDs []int, mode storage.LabelFilterMode) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.TasksByLabels(ctx, labelIDs, mode))
+}
+
+// TasksWithLabels расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TasksWithLabels(ctx context.Context) ([]storage.TaskWithLabels, error) {
+	tasks, err := s.Interface.TasksWithLabels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		if tasks[i].Content, err = s.decrypt(tasks[i].Content); err != nil {
+			return nil, err
+		}
+	}
+	return tasks, nil
+}
+
+// TasksWithUsers расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TasksWithUsers(ctx context.Context) ([]storage.TaskWithUsers, error) {
+	tasks, err := s.Interface.TasksWithUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		if tasks[i].Content, err = s.decrypt(tasks[i].Content); err != nil {
+			return nil, err
+		}
+	}
+	return tasks, nil
+}
+
+// FilterTasks расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) FilterTasks(ctx context.Context, f storage.TaskFilter) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.FilterTasks(ctx, f))
+}
+
+// TasksByStatus расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TasksByStatus(ctx context.Context, st storage.Status) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.TasksByStatus(ctx, st))
+}
+
+// TasksByPriority расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TasksByPriority(ctx context.Context, p storage.Priority) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.TasksByPriority(ctx, p))
+}
+
+// TasksOrderedByPriority расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TasksOrderedByPriority(ctx context.Context) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.TasksOrderedByPriority(ctx))
+}
+
+// TasksOverdue расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TasksOverdue(ctx context.Context, now int64) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.TasksOverdue(ctx, now))
+}
+
+// StaleTasks расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) StaleTasks(ctx context.Context, staleAfterSeconds int64, now int64) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.StaleTasks(ctx, staleAfterSeconds, now))
+}
+
+// TasksDueBetween расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TasksDueBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.TasksDueBetween(ctx, from, to))
+}
+
+// TasksOpenedBetween расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TasksOpenedBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.TasksOpenedBetween(ctx, from, to))
+}
+
+// TasksClosedBetween расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TasksClosedBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.TasksClosedBetween(ctx, from, to))
+}
+
+// TasksClosedInRange расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TasksClosedInRange(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.TasksClosedInRange(ctx, from, to))
+}
+
+// SearchTasks расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) SearchTasks(ctx context.Context, query string, limit int) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.SearchTasks(ctx, query, limit))
+}
+
+// SimilarTasks расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) SimilarTasks(ctx context.Context, title string, threshold float64) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.SimilarTasks(ctx, title, threshold))
+}
+
+// TasksByMetadataKey расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TasksByMetadataKey(ctx context.Context, key, value string) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.TasksByMetadataKey(ctx, key, value))
+}
+
+// TaskByExternalID расшифровывает содержимое задачи, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TaskByExternalID(ctx context.Context, system, externalID string) (*storage.Task, error) {
+	return s.decryptTask(s.Interface.TaskByExternalID(ctx, system, externalID))
+}
+
+// TasksByTag расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TasksByTag(ctx context.Context, tag string) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.TasksByTag(ctx, tag))
+}
+
+// TasksByRank расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TasksByRank(ctx context.Context) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.TasksByRank(ctx))
+}
+
+// TasksIncludingDeleted расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TasksIncludingDeleted(ctx context.Context) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.TasksIncludingDeleted(ctx))
+}
+
+// ArchivedTasks расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) ArchivedTasks(ctx context.Context) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.ArchivedTasks(ctx))
+}
+
+// TasksByProject расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TasksByProject(ctx context.Context, projectID int) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.TasksByProject(ctx, projectID))
+}
+
+// TasksBySprint расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) TasksBySprint(ctx context.Context, sprintID int) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.TasksBySprint(ctx, sprintID))
+}
+
+// DependenciesOf расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) DependenciesOf(ctx context.Context, taskID int) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.DependenciesOf(ctx, taskID))
+}
+
+// BlockedBy расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) BlockedBy(ctx context.Context, taskID int) ([]storage.Task, error) {
+	return s.decryptTasks(s.Interface.BlockedBy(ctx, taskID))
+}
+
+// ExportUserData расшифровывает содержимое задач, полученных из обёрнутого хранилища.
+func (s *EncryptedStorage) ExportUserData(ctx context.Context, userID int) (*storage.UserDataExport, error) {
+	export, err := s.Interface.ExportUserData(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if export.AuthoredTasks, err = s.decryptTasks(export.AuthoredTasks, nil); err != nil {
+		return nil, err
+	}
+	if export.AssignedTasks, err = s.decryptTasks(export.AssignedTasks, nil); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
@@ -0,0 +1,224 @@
+package enc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"skillfactory/30.8.1/pkg/storage"
+	"skillfactory/30.8.1/pkg/storage/memory"
+	"skillfactory/30.8.1/pkg/storage/postgres"
+	"testing"
+)
+
+var (
+	key      = bytes.Repeat([]byte{1}, 32)
+	otherKey = bytes.Repeat([]byte{2}, 32)
+)
+
+func TestNewAESGCMKeySize(t *testing.T) {
+	for _, n := range []int{0, 16, 24, 31, 33} {
+		_, err := NewAESGCM(make([]byte, n))
+		if !errors.Is(err, storage.ErrInvalidArgument) {
+			t.Errorf("NewAESGCM(%d bytes) error = %v, want ErrInvalidArgument", n, err)
+		}
+	}
+}
+
+func TestAESGCM(t *testing.T) {
+	e, err := NewAESGCM(key)
+	if err != nil {
+		t.Fatalf("NewAESGCM() error = %v", err)
+	}
+	plaintext := []byte("secret")
+
+	a, err := e.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	b, err := e.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	// nonce случайный, поэтому шифротексты различаются
+	if bytes.Equal(a, b) {
+		t.Error("Encrypt() returned the same ciphertext twice")
+	}
+	if bytes.Contains(a, plaintext) {
+		t.Errorf("Encrypt() = %q, contains plaintext", a)
+	}
+
+	got, err := e.Decrypt(a)
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("Decrypt() = %q, want %q", got, plaintext)
+	}
+
+	// повреждённые и слишком короткие данные
+	a[len(a)-1] ^= 1
+	if _, err := e.Decrypt(a); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("Decrypt(corrupted) error = %v, want ErrDecrypt", err)
+	}
+	if _, err := e.Decrypt([]byte{1, 2, 3}); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("Decrypt(short) error = %v, want ErrDecrypt", err)
+	}
+}
+
+// testEncrypted проверяет, что обёрнутое хранилище видит шифротекст,
+// а чтение через EncryptedStorage возвращает исходное содержимое.
+func testEncrypted(t *testing.T, inner storage.Interface) {
+	ctx := context.Background()
+	s, err := New(inner, key)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	const content = "card number 4111 1111 1111 1111"
+	id, err := s.AddTask(ctx, storage.Task{Title: "encrypted", Content: content})
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+
+	raw, err := inner.TaskById(ctx, id)
+	if err != nil {
+		t.Fatalf("inner.TaskById() error = %v", err)
+	}
+	if raw.Content == content || raw.Content == "" {
+		t.Errorf("stored Content = %q, want ciphertext", raw.Content)
+	}
+	if raw.Title != "encrypted" {
+		t.Errorf("stored Title = %q, want plaintext", raw.Title)
+	}
+
+	task, err := s.TaskById(ctx, id)
+	if err != nil {
+		t.Fatalf("TaskById() error = %v", err)
+	}
+	if task.Content != content {
+		t.Errorf("TaskById().Content = %q, want %q", task.Content, content)
+	}
+	tasks, err := s.Tasks(ctx)
+	if err != nil {
+		t.Fatalf("Tasks() error = %v", err)
+	}
+	for _, task := range tasks {
+		if task.ID == id && task.Content != content {
+			t.Errorf("Tasks() Content = %q, want %q", task.Content, content)
+		}
+	}
+
+	// изменение содержимого тоже шифруется
+	task.Content = "updated"
+	err = s.UpdateTask(ctx, *task)
+	if err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	raw, err = inner.TaskById(ctx, id)
+	if err != nil {
+		t.Fatalf("inner.TaskById() error = %v", err)
+	}
+	if raw.Content == "updated" {
+		t.Error("UpdateTask() stored plaintext content")
+	}
+
+	// хранилище с другим ключом не может прочитать задачу
+	wrong, err := New(inner, otherKey)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	_, err = wrong.TaskById(ctx, id)
+	if !errors.Is(err, ErrDecrypt) {
+		t.Errorf("TaskById() with wrong key error = %v, want ErrDecrypt", err)
+	}
+}
+
+func TestEncryptedStorage(t *testing.T) {
+	testEncrypted(t, memory.New())
+}
+
+// Пустое содержимое хранится как есть.
+func TestEncryptedStorageEmptyContent(t *testing.T) {
+	ctx := context.Background()
+	inner := memory.New()
+	s, err := New(inner, key)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	id, err := s.AddTask(ctx, storage.Task{Title: "empty"})
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	raw, err := inner.TaskById(ctx, id)
+	if err != nil {
+		t.Fatalf("inner.TaskById() error = %v", err)
+	}
+	if raw.Content != "" {
+		t.Errorf("stored Content = %q, want empty", raw.Content)
+	}
+}
+
+// Задачи, записанные в транзакции, шифруются и читаются после Commit.
+func TestEncryptedStorageTx(t *testing.T) {
+	ctx := context.Background()
+	inner := memory.New()
+	s, err := New(inner, key)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	const content = "secret content"
+	tx, err := s.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx() error = %v", err)
+	}
+	id, err := tx.AddTask(ctx, storage.Task{Title: "tx", Content: content})
+	if err != nil {
+		t.Fatalf("tx.AddTask() error = %v", err)
+	}
+	task, err := tx.TaskById(ctx, id)
+	if err != nil {
+		t.Fatalf("tx.TaskById() error = %v", err)
+	}
+	if task.Content != content {
+		t.Errorf("tx.TaskById().Content = %q, want %q", task.Content, content)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	raw, err := inner.TaskById(ctx, id)
+	if err != nil {
+		t.Fatalf("inner.TaskById() error = %v", err)
+	}
+	if raw.Content == content {
+		t.Error("tx.AddTask() stored plaintext content")
+	}
+	task, err = s.TaskById(ctx, id)
+	if err != nil {
+		t.Fatalf("TaskById() error = %v", err)
+	}
+	if task.Content != content {
+		t.Errorf("TaskById().Content = %q, want %q", task.Content, content)
+	}
+}
+
+// Тесту нужна БД из переменной окружения TEST_DATABASE_URL.
+func TestEncryptedStoragePostgres(t *testing.T) {
+	constr := os.Getenv("TEST_DATABASE_URL")
+	if constr == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	err := postgres.Migrate(context.Background(), constr)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	s, err := postgres.New(constr)
+	if err != nil {
+		t.Fatalf("postgres.New() error = %v", err)
+	}
+	defer s.Close()
+
+	testEncrypted(t, s)
+}
@@ -0,0 +1,51 @@
+package enc
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+)
+
+// Tx - транзакция обёрнутого хранилища, которая, как и EncryptedStorage,
+// шифрует содержимое задач при записи и расшифровывает при чтении.
+type Tx struct {
+	*EncryptedStorage
+	tx storage.TxStorage
+}
+
+// BeginTx начинает транзакцию в обёрнутом хранилище.
+func (s *EncryptedStorage) BeginTx(ctx context.Context) (storage.TxStorage, error) {
+	tx, err := s.Interface.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t := Tx{
+		EncryptedStorage: NewWithEncryptor(tx, s.enc),
+		tx:               tx,
+	}
+	return &t, nil
+}
+
+// Commit фиксирует транзакцию.
+func (t *Tx) Commit(ctx context.Context) error {
+	return t.tx.Commit(ctx)
+}
+
+// Rollback откатывает транзакцию.
+func (t *Tx) Rollback(ctx context.Context) error {
+	return t.tx.Rollback(ctx)
+}
+
+// Savepoint создаёт точку сохранения name.
+func (t *Tx) Savepoint(ctx context.Context, name string) error {
+	return t.tx.Savepoint(ctx, name)
+}
+
+// RollbackToSavepoint откатывает транзакцию к точке сохранения name.
+func (t *Tx) RollbackToSavepoint(ctx context.Context, name string) error {
+	return t.tx.RollbackToSavepoint(ctx, name)
+}
+
+// ReleaseSavepoint удаляет точку сохранения name.
+func (t *Tx) ReleaseSavepoint(ctx context.Context, name string) error {
+	return t.tx.ReleaseSavepoint(ctx, name)
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotFound возвращается, если запрошенная запись отсутствует.
+	ErrNotFound = errors.New("storage: record not found")
+	// ErrConflict возвращается при нарушении ограничения уникальности.
+	ErrConflict = errors.New("storage: unique constraint violated")
+	// ErrInvalidArgument возвращается при некорректных входных данных.
+	ErrInvalidArgument = errors.New("storage: invalid argument")
+	// ErrVersionConflict возвращается, если задача была изменена
+	// после того, как вызывающий код прочитал её версию.
+	ErrVersionConflict = errors.New("storage: version conflict")
+	// ErrTxClosed возвращается при обращении к завершённой транзакции.
+	ErrTxClosed = errors.New("storage: transaction is closed")
+	// ErrForbidden возвращается, если у пользователя нет прав на операцию.
+	ErrForbidden = errors.New("storage: permission denied")
+	// ErrLocked возвращается, если задача заблокирована для изменения
+	// другим соединением (см. LockTask).
+	ErrLocked = errors.New("storage: task is locked")
+)
+
+// IndexedError - ошибка обработки элемента партии с его индексом
+// во входном слайсе.
+type IndexedError struct {
+	Index int
+	Err   error
+}
+
+func (e IndexedError) Error() string {
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+func (e IndexedError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError объединяет ошибки отдельных элементов партии.
+// Проверка errors.Is проходит по ошибкам всех элементов.
+type BatchError struct {
+	// Ошибки в порядке следования элементов.
+	Errors []IndexedError
+	// Общее количество элементов в партии.
+	Total int
+}
+
+func (e *BatchError) Error() string {
+	if len(e.Errors) == e.Total {
+		return fmt.Sprintf("storage: all %d batch items failed, first: %v", e.Total, e.Errors[0])
+	}
+	return fmt.Sprintf("storage: %d of %d batch items failed, first: %v", len(e.Errors), e.Total, e.Errors[0])
+}
+
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, ie := range e.Errors {
+		errs[i] = ie
+	}
+	return errs
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBatchError(t *testing.T) {
+	errDB := errors.New("connection reset")
+	tests := []struct {
+		name    string
+		err     *BatchError
+		wantMsg string
+	}{
+		{
+			name: "partial failure",
+			err: &BatchError{
+				Errors: []IndexedError{{Index: 1, Err: ErrConflict}, {Index: 2, Err: errDB}},
+				Total:  3,
+			},
+			wantMsg: "2 of 3 batch items failed, first: item 1: " + ErrConflict.Error(),
+		},
+		{
+			name: "total failure",
+			err: &BatchError{
+				Errors: []IndexedError{{Index: 0, Err: ErrConflict}, {Index: 1, Err: errDB}},
+				Total:  2,
+			},
+			wantMsg: "all 2 batch items failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if msg := tt.err.Error(); !strings.Contains(msg, tt.wantMsg) {
+				t.Errorf("Error() = %q, want it to contain %q", msg, tt.wantMsg)
+			}
+			// errors.Is проходит по ошибкам всех элементов
+			var err error = tt.err
+			if !errors.Is(err, ErrConflict) || !errors.Is(err, errDB) {
+				t.Errorf("errors.Is() does not match item errors of %v", err)
+			}
+			if errors.Is(err, ErrNotFound) {
+				t.Errorf("errors.Is(%v, ErrNotFound) = true", err)
+			}
+			var ie IndexedError
+			if !errors.As(err, &ie) || ie.Index != tt.err.Errors[0].Index {
+				t.Errorf("errors.As() = %+v, want first item error", ie)
+			}
+		})
+	}
+}
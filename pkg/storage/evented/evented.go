@@ -0,0 +1,285 @@
+package evented
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/events"
+	"skillfactory/30.8.1/pkg/storage"
+)
+
+// EventedStorage оборачивает storage.Interface и публикует в шину
+// событие после каждого успешного изменения задач и комментариев.
+// Ошибка публикации не возвращается: изменение уже сохранено.
+//
+// ImportTasks не публикует событий, так как не возвращает ID задач.
+// Изменения в транзакциях (BeginTx) и прочие методы передаются
+// обёрнутому хранилищу без изменений.
+type EventedStorage struct {
+	storage.Interface
+	bus *events.Bus
+}
+
+// Конструктор, принимает оборачиваемое хранилище и шину событий.
+func New(inner storage.Interface, bus *events.Bus) *EventedStorage {
+	s := EventedStorage{
+		Interface: inner,
+		bus:       bus,
+	}
+	return &s
+}
+
+// publish публикует событие в шину.
+func (s *EventedStorage) publish(ctx context.Context, eventType string, taskID, userID int, payload interface{}) {
+	_ = s.bus.Publish(ctx, events.Event{
+		Type:    eventType,
+		TaskID:  taskID,
+		UserID:  userID,
+		Payload: payload,
+	})
+}
+
+// AddTask создаёт задачу и публикует events.TaskCreated.
+func (s *EventedStorage) AddTask(ctx context.Context, t storage.Task) (int, error) {
+	id, err := s.Interface.AddTask(ctx, t)
+	if err != nil {
+		return id, err
+	}
+	t.ID = id
+	s.publish(ctx, events.TaskCreated, id, t.AuthorID, t)
+	return id, nil
+}
+
+// AddTaskWithLabels создаёт задачу и публикует events.TaskCreated.
+func (s *EventedStorage) AddTaskWithLabels(ctx context.Context, t storage.Task, labelIDs []int) (int, error) {
+	id, err := s.Interface.AddTaskWithLabels(ctx, t, labelIDs)
+	if err != nil {
+		return id, err
+	}
+	t.ID = id
+	s.publish(ctx, events.TaskCreated, id, t.AuthorID, t)
+	return id, nil
+}
+
+// AddTasks создаёт задачи и публикует events.TaskCreated для каждой.
+func (s *EventedStorage) AddTasks(ctx context.Context, tasks []storage.Task) ([]int, error) {
+	ids, err := s.Interface.AddTasks(ctx, tasks)
+	if err != nil {
+		return ids, err
+	}
+	s.publishCreated(ctx, tasks, ids)
+	return ids, nil
+}
+
+// AddTasksBatch создаёт задачи и публикует events.TaskCreated для каждой.
+func (s *EventedStorage) AddTasksBatch(ctx context.Context, tasks []storage.Task) ([]int, error) {
+	ids, err := s.Interface.AddTasksBatch(ctx, tasks)
+	if err != nil {
+		return ids, err
+	}
+	s.publishCreated(ctx, tasks, ids)
+	return ids, nil
+}
+
+// publishCreated публикует events.TaskCreated для созданных задач.
+func (s *EventedStorage) publishCreated(ctx context.Context, tasks []storage.Task, ids []int) {
+	for i, id := range ids {
+		t := tasks[i]
+		t.ID = id
+		s.publish(ctx, events.TaskCreated, id, t.AuthorID, t)
+	}
+}
+
+// UpdateTask изменяет задачу и публикует events.TaskUpdated.
+func (s *EventedStorage) UpdateTask(ctx context.Context, t storage.Task) error {
+	err := s.Interface.UpdateTask(ctx, t)
+	if err != nil {
+		return err
+	}
+	s.publish(ctx, events.TaskUpdated, t.ID, t.AuthorID, t)
+	return nil
+}
+
+// AssignTask назначает исполнителя и публикует events.TaskAssigned.
+func (s *EventedStorage) AssignTask(ctx context.Context, taskID, userID int) error {
+	err := s.Interface.AssignTask(ctx, taskID, userID)
+	if err != nil {
+		return err
+	}
+	s.publish(ctx, events.TaskAssigned, taskID, userID, nil)
+	return nil
+}
+
+// UpdateTasksAssignee назначает исполнителя задачам и публикует
+// events.TaskAssigned для каждого ID из taskIDs.
+func (s *EventedStorage) UpdateTasksAssignee(ctx context.Context, taskIDs []int, assigneeID int) (int64, error) {
+	n, err := s.Interface.UpdateTasksAssignee(ctx, taskIDs, assigneeID)
+	if err != nil {
+		return n, err
+	}
+	for _, id := range taskIDs {
+		s.publish(ctx, events.TaskAssigned, id, assigneeID, nil)
+	}
+	return n, nil
+}
+
+// UpdateTasksStatus изменяет статус задач и публикует
+// events.TaskUpdated для каждого ID из taskIDs.
+func (s *EventedStorage) UpdateTasksStatus(ctx context.Context, taskIDs []int, st storage.Status) (int64, error) {
+	n, err := s.Interface.UpdateTasksStatus(ctx, taskIDs, st)
+	if err != nil {
+		return n, err
+	}
+	for _, id := range taskIDs {
+		s.publish(ctx, events.TaskUpdated, id, 0, st)
+	}
+	return n, nil
+}
+
+// UpsertTask создаёт или изменяет задачу и публикует
+// events.TaskCreated или events.TaskUpdated.
+func (s *EventedStorage) UpsertTask(ctx context.Context, t storage.Task) (int, error) {
+	id, err := s.Interface.UpsertTask(ctx, t)
+	if err != nil {
+		return id, err
+	}
+	eventType := events.TaskUpdated
+	if t.ID == 0 {
+		eventType = events.TaskCreated
+	}
+	t.ID = id
+	s.publish(ctx, eventType, id, t.AuthorID, t)
+	return id, nil
+}
+
+// PartialUpdateTask изменяет задачу и публикует events.TaskUpdated.
+func (s *EventedStorage) PartialUpdateTask(ctx context.Context, taskID int, patch storage.TaskPatch) error {
+	err := s.Interface.PartialUpdateTask(ctx, taskID, patch)
+	if err != nil {
+		return err
+	}
+	s.publish(ctx, events.TaskUpdated, taskID, 0, patch)
+	return nil
+}
+
+// UpdateTaskStatus изменяет статус задачи и публикует events.TaskUpdated.
+func (s *EventedStorage) UpdateTaskStatus(ctx context.Context, taskID int, st storage.Status) error {
+	err := s.Interface.UpdateTaskStatus(ctx, taskID, st)
+	if err != nil {
+		return err
+	}
+	s.publish(ctx, events.TaskUpdated, taskID, 0, st)
+	return nil
+}
+
+// MarkTaskStale отмечает задачу и публикует events.TaskUpdated.
+func (s *EventedStorage) MarkTaskStale(ctx context.Context, taskID int) error {
+	err := s.Interface.MarkTaskStale(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	s.publish(ctx, events.TaskUpdated, taskID, 0, nil)
+	return nil
+}
+
+// AssignLabel назначает метку и публикует events.TaskUpdated.
+func (s *EventedStorage) AssignLabel(ctx context.Context, taskID, labelID int) error {
+	err := s.Interface.AssignLabel(ctx, taskID, labelID)
+	if err != nil {
+		return err
+	}
+	s.publish(ctx, events.TaskUpdated, taskID, 0, nil)
+	return nil
+}
+
+// RemoveLabel снимает метку и публикует events.TaskUpdated.
+func (s *EventedStorage) RemoveLabel(ctx context.Context, taskID, labelID int) error {
+	err := s.Interface.RemoveLabel(ctx, taskID, labelID)
+	if err != nil {
+		return err
+	}
+	s.publish(ctx, events.TaskUpdated, taskID, 0, nil)
+	return nil
+}
+
+// DeleteTask удаляет задачу и публикует events.TaskDeleted.
+func (s *EventedStorage) DeleteTask(ctx context.Context, taskID int) error {
+	err := s.Interface.DeleteTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	s.publish(ctx, events.TaskDeleted, taskID, 0, nil)
+	return nil
+}
+
+// DeleteTasks удаляет задачи и публикует events.TaskDeleted для каждой.
+func (s *EventedStorage) DeleteTasks(ctx context.Context, taskIDs []int) error {
+	err := s.Interface.DeleteTasks(ctx, taskIDs)
+	if err != nil {
+		return err
+	}
+	for _, id := range taskIDs {
+		s.publish(ctx, events.TaskDeleted, id, 0, nil)
+	}
+	return nil
+}
+
+// UndeleteTask восстанавливает задачу и публикует events.TaskUpdated.
+func (s *EventedStorage) UndeleteTask(ctx context.Context, taskID int) error {
+	err := s.Interface.UndeleteTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	s.publish(ctx, events.TaskUpdated, taskID, 0, nil)
+	return nil
+}
+
+// ArchiveTask архивирует задачу и публикует events.TaskArchived.
+func (s *EventedStorage) ArchiveTask(ctx context.Context, taskID int) error {
+	err := s.Interface.ArchiveTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	s.publish(ctx, events.TaskArchived, taskID, 0, nil)
+	return nil
+}
+
+// UnarchiveTask возвращает задачу из архива и публикует events.TaskUnarchived.
+func (s *EventedStorage) UnarchiveTask(ctx context.Context, taskID int) error {
+	err := s.Interface.UnarchiveTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	s.publish(ctx, events.TaskUnarchived, taskID, 0, nil)
+	return nil
+}
+
+// AddComment добавляет комментарий и публикует events.CommentAdded.
+func (s *EventedStorage) AddComment(ctx context.Context, c storage.Comment) (int, error) {
+	id, err := s.Interface.AddComment(ctx, c)
+	if err != nil {
+		return id, err
+	}
+	c.ID = id
+	s.publish(ctx, events.CommentAdded, c.TaskID, c.AuthorID, c)
+	return id, nil
+}
+
+// UpdateComment изменяет комментарий и публикует events.CommentUpdated.
+func (s *EventedStorage) UpdateComment(ctx context.Context, c storage.Comment) error {
+	err := s.Interface.UpdateComment(ctx, c)
+	if err != nil {
+		return err
+	}
+	s.publish(ctx, events.CommentUpdated, c.TaskID, c.AuthorID, c)
+	return nil
+}
+
+// DeleteComment удаляет комментарий и публикует events.CommentDeleted.
+// ID удалённого комментария передаётся в Payload.
+func (s *EventedStorage) DeleteComment(ctx context.Context, commentID int) error {
+	err := s.Interface.DeleteComment(ctx, commentID)
+	if err != nil {
+		return err
+	}
+	s.publish(ctx, events.CommentDeleted, 0, 0, commentID)
+	return nil
+}
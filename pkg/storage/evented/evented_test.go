@@ -0,0 +1,132 @@
+package evented
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/events"
+	"skillfactory/30.8.1/pkg/storage"
+	"skillfactory/30.8.1/pkg/storage/memory"
+	"skillfactory/30.8.1/pkg/storage/mock"
+	"testing"
+	"time"
+)
+
+func TestTaskCreated(t *testing.T) {
+	bus := events.NewBus()
+	ch := make(chan events.Event, 10)
+	bus.Subscribe(events.TaskCreated, func(e events.Event) { ch <- e })
+	s := New(memory.New(), bus)
+
+	id, err := s.AddTask(context.Background(), storage.Task{Title: "task", AuthorID: 3})
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+
+	select {
+	case e := <-ch:
+		if e.TaskID != id || e.UserID != 3 {
+			t.Errorf("event = %+v, want TaskID %d, UserID 3", e, id)
+		}
+		if task, ok := e.Payload.(storage.Task); !ok || task.ID != id || task.Title != "task" {
+			t.Errorf("event Payload = %+v, want the created task", e.Payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler was not called")
+	}
+	select {
+	case e := <-ch:
+		t.Errorf("unexpected second event %+v", e)
+	default:
+	}
+}
+
+// Каждое изменение публикует событие своего типа.
+func TestEvents(t *testing.T) {
+	ctx := context.Background()
+	bus := events.NewBus()
+	var got []events.Event
+	bus.Subscribe(events.AllEvents, func(e events.Event) { got = append(got, e) })
+	s := New(memory.New(), bus)
+
+	id, err := s.AddTask(ctx, storage.Task{Title: "task"})
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	user, err := s.AddUser(ctx, storage.User{Name: "user"})
+	if err != nil {
+		t.Fatalf("AddUser() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		call   func() error
+		want   string
+		taskID int
+	}{
+		{"UpdateTask", func() error { return s.UpdateTask(ctx, storage.Task{ID: id, Title: "updated"}) }, events.TaskUpdated, id},
+		{"AssignTask", func() error { return s.AssignTask(ctx, id, user) }, events.TaskAssigned, id},
+		{"UpdateTaskStatus", func() error { return s.UpdateTaskStatus(ctx, id, storage.StatusInProgress) }, events.TaskUpdated, id},
+		{"AddComment", func() error {
+			_, err := s.AddComment(ctx, storage.Comment{TaskID: id, AuthorID: user, Body: "comment"})
+			return err
+		}, events.CommentAdded, id},
+		{"ArchiveTask", func() error { return s.ArchiveTask(ctx, id) }, events.TaskArchived, id},
+		{"UnarchiveTask", func() error { return s.UnarchiveTask(ctx, id) }, events.TaskUnarchived, id},
+		{"DeleteTask", func() error { return s.DeleteTask(ctx, id) }, events.TaskDeleted, id},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			if err := tt.call(); err != nil {
+				t.Fatalf("%s() error = %v", tt.name, err)
+			}
+			if len(got) != 1 || got[0].Type != tt.want || got[0].TaskID != tt.taskID {
+				t.Errorf("events = %+v, want one %s for task %d", got, tt.want, tt.taskID)
+			}
+		})
+	}
+}
+
+// После ошибки изменения событие не публикуется.
+func TestNoEventOnError(t *testing.T) {
+	ctx := context.Background()
+	errDB := errors.New("db error")
+	inner := &mock.Mock{
+		AddTaskFunc: func(ctx context.Context, t storage.Task) (int, error) {
+			return 0, errDB
+		},
+		DeleteTaskFunc: func(ctx context.Context, taskID int) error {
+			return storage.ErrNotFound
+		},
+	}
+	bus := events.NewBus()
+	calls := 0
+	bus.Subscribe(events.AllEvents, func(e events.Event) { calls++ })
+	s := New(inner, bus)
+
+	if _, err := s.AddTask(ctx, storage.Task{}); !errors.Is(err, errDB) {
+		t.Errorf("AddTask() error = %v, want %v", err, errDB)
+	}
+	if err := s.DeleteTask(ctx, 1); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("DeleteTask() error = %v, want ErrNotFound", err)
+	}
+	if calls != 0 {
+		t.Errorf("events published = %d, want 0", calls)
+	}
+}
+
+// Пакетное создание публикует событие для каждой задачи.
+func TestAddTasks(t *testing.T) {
+	bus := events.NewBus()
+	var got []int
+	bus.Subscribe(events.TaskCreated, func(e events.Event) { got = append(got, e.TaskID) })
+	s := New(memory.New(), bus)
+
+	ids, err := s.AddTasks(context.Background(), []storage.Task{{Title: "a"}, {Title: "b"}})
+	if err != nil {
+		t.Fatalf("AddTasks() error = %v", err)
+	}
+	if len(got) != 2 || got[0] != ids[0] || got[1] != ids[1] {
+		t.Errorf("created events = %v, want %v", got, ids)
+	}
+}
@@ -0,0 +1,1385 @@
+package logmw
+
+import (
+	"context"
+	"log/slog"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Middleware оборачивает storage.Interface и журналирует каждый вызов:
+// имя метода, аргументы, длительность и ошибку.
+// Успешные вызовы пишутся с уровнем DEBUG, неудачные - с уровнем ERROR.
+type Middleware struct {
+	inner          storage.Interface
+	logger         *slog.Logger
+	logContent     bool
+	recordActivity bool
+}
+
+// Option задаёт необязательный параметр Middleware.
+type Option func(*Middleware)
+
+// WithContentLogging включает вывод содержания задач в журнал.
+// По умолчанию поле Content не журналируется.
+func WithContentLogging(enabled bool) Option {
+	return func(m *Middleware) {
+		m.logContent = enabled
+	}
+}
+
+// WithActivity включает запись события в ленту активности
+// (RecordActivity) после каждой успешной операции записи.
+// Вид события совпадает с именем метода.
+func WithActivity(enabled bool) Option {
+	return func(m *Middleware) {
+		m.recordActivity = enabled
+	}
+}
+
+// Конструктор, принимает оборачиваемое хранилище и журнал.
+func New(inner storage.Interface, logger *slog.Logger, opts ...Option) *Middleware {
+	m := Middleware{
+		inner:  inner,
+		logger: logger,
+	}
+	for _, opt := range opts {
+		opt(&m)
+	}
+	return &m
+}
+
+// log записывает в журнал сведения о вызове метода.
+func (m *Middleware) log(ctx context.Context, method string, start time.Time, err error, attrs ...slog.Attr) {
+	attrs = append(attrs,
+		slog.String("method", method),
+		slog.Duration("duration", time.Since(start)),
+	)
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		m.logger.LogAttrs(ctx, slog.LevelError, "storage call failed", attrs...)
+		return
+	}
+	m.logger.LogAttrs(ctx, slog.LevelDebug, "storage call", attrs...)
+}
+
+// activity записывает в ленту активности событие успешной операции записи.
+// Ошибка записи события журналируется и не возвращается вызывающему коду.
+func (m *Middleware) activity(ctx context.Context, kind string, taskID int, err error) {
+	if !m.recordActivity || err != nil {
+		return
+	}
+	err = m.inner.RecordActivity(ctx, storage.ActivityEvent{
+		Kind:   kind,
+		TaskID: taskID,
+	})
+	if err != nil {
+		m.logger.LogAttrs(ctx, slog.LevelWarn, "storage activity not recorded",
+			slog.String("method", kind),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// webhookAttr представляет веб-хук в виде группы атрибутов без секрета.
+func webhookAttr(key string, w storage.Webhook) slog.Attr {
+	return slog.Group(key,
+		slog.Int("id", w.ID),
+		slog.String("url", w.URL),
+		slog.Any("events", w.Events),
+		slog.Bool("active", w.Active),
+	)
+}
+
+// taskAttr представляет задачу в виде группы атрибутов.
+// Содержание задачи включается только при WithContentLogging(true).
+func (m *Middleware) taskAttr(key string, t storage.Task) slog.Attr {
+	attrs := []any{
+		slog.Int("id", t.ID),
+		slog.Int("author_id", t.AuthorID),
+		slog.Int("assigned_id", t.AssignedID),
+		slog.String("title", t.Title),
+	}
+	if m.logContent {
+		attrs = append(attrs, slog.String("content", t.Content))
+	}
+	return slog.Group(key, attrs...)
+}
+
+// patchAttr представляет изменение задачи в виде атрибута,
+// скрывая содержание задачи, если оно не должно попадать в журнал.
+func (m *Middleware) patchAttr(key string, p storage.TaskPatch) slog.Attr {
+	if !m.logContent {
+		p.Content = nil
+	}
+	return slog.Any(key, p)
+}
+
+// Tasks логирует вызов Tasks.
+func (m *Middleware) Tasks(ctx context.Context) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.Tasks(ctx)
+	m.log(ctx, "Tasks", start, err)
+	return res, err
+}
+
+// TasksIter логирует вызов TasksIter.
+func (m *Middleware) TasksIter(ctx context.Context, fn func(t storage.Task) error) error {
+	start := time.Now()
+	err := m.inner.TasksIter(ctx, fn)
+	m.log(ctx, "TasksIter", start, err)
+	return err
+}
+
+// TasksList логирует вызов TasksList.
+func (m *Middleware) TasksList(ctx context.Context, opts storage.ListOptions) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksList(ctx, opts)
+	m.log(ctx, "TasksList", start, err, slog.Any("opts", opts))
+	return res, err
+}
+
+// TotalCount логирует вызов TotalCount.
+func (m *Middleware) TotalCount(ctx context.Context) (int, error) {
+	start := time.Now()
+	res, err := m.inner.TotalCount(ctx)
+	m.log(ctx, "TotalCount", start, err)
+	return res, err
+}
+
+// TasksAfter логирует вызов TasksAfter.
+func (m *Middleware) TasksAfter(ctx context.Context, afterID int, limit int) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksAfter(ctx, afterID, limit)
+	m.log(ctx, "TasksAfter", start, err, slog.Int("afterID", afterID), slog.Int("limit", limit))
+	return res, err
+}
+
+// TaskCount логирует вызов TaskCount.
+func (m *Middleware) TaskCount(ctx context.Context) (int, error) {
+	start := time.Now()
+	res, err := m.inner.TaskCount(ctx)
+	m.log(ctx, "TaskCount", start, err)
+	return res, err
+}
+
+// TaskCountByAuthor логирует вызов TaskCountByAuthor.
+func (m *Middleware) TaskCountByAuthor(ctx context.Context, authorID int) (int, error) {
+	start := time.Now()
+	res, err := m.inner.TaskCountByAuthor(ctx, authorID)
+	m.log(ctx, "TaskCountByAuthor", start, err, slog.Int("authorID", authorID))
+	return res, err
+}
+
+// TaskCountByLabel логирует вызов TaskCountByLabel.
+func (m *Middleware) TaskCountByLabel(ctx context.Context, labelID int) (int, error) {
+	start := time.Now()
+	res, err := m.inner.TaskCountByLabel(ctx, labelID)
+	m.log(ctx, "TaskCountByLabel", start, err, slog.Int("labelID", labelID))
+	return res, err
+}
+
+// TaskById логирует вызов TaskById.
+func (m *Middleware) TaskById(ctx context.Context, taskId int) (*storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TaskById(ctx, taskId)
+	m.log(ctx, "TaskById", start, err, slog.Int("taskId", taskId))
+	return res, err
+}
+
+// TasksByAuthor логирует вызов TasksByAuthor.
+func (m *Middleware) TasksByAuthor(ctx context.Context, authorId int) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksByAuthor(ctx, authorId)
+	m.log(ctx, "TasksByAuthor", start, err, slog.Int("authorId", authorId))
+	return res, err
+}
+
+// TasksByAssignee логирует вызов TasksByAssignee.
+func (m *Middleware) TasksByAssignee(ctx context.Context, assigneeID int) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksByAssignee(ctx, assigneeID)
+	m.log(ctx, "TasksByAssignee", start, err, slog.Int("assigneeID", assigneeID))
+	return res, err
+}
+
+// TasksByLabel логирует вызов TasksByLabel.
+func (m *Middleware) TasksByLabel(ctx context.Context, labelId int) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksByLabel(ctx, labelId)
+	m.log(ctx, "TasksByLabel", start, err, slog.Int("labelId", labelId))
+	return res, err
+}
+
+// FilterTasks логирует вызов FilterTasks.
+func (m *Middleware) FilterTasks(ctx context.Context, f storage.TaskFilter) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.FilterTasks(ctx, f)
+	m.log(ctx, "FilterTasks", start, err, slog.Any("f", f))
+	return res, err
+}
+
+// AddTask логирует вызов AddTask.
+func (m *Middleware) AddTask(ctx context.Context, task storage.Task) (int, error) {
+	start := time.Now()
+	res, err := m.inner.AddTask(ctx, task)
+	m.log(ctx, "AddTask", start, err, m.taskAttr("task", task))
+	m.activity(ctx, "AddTask", res, err)
+	return res, err
+}
+
+// AddTasks логирует вызов AddTasks.
+func (m *Middleware) AddTasks(ctx context.Context, tasks []storage.Task) ([]int, error) {
+	start := time.Now()
+	res, err := m.inner.AddTasks(ctx, tasks)
+	m.log(ctx, "AddTasks", start, err, slog.Int("tasks", len(tasks)))
+	m.activity(ctx, "AddTasks", 0, err)
+	return res, err
+}
+
+// AddTasksBatch логирует вызов AddTasksBatch.
+func (m *Middleware) AddTasksBatch(ctx context.Context, tasks []storage.Task) ([]int, error) {
+	start := time.Now()
+	res, err := m.inner.AddTasksBatch(ctx, tasks)
+	m.log(ctx, "AddTasksBatch", start, err, slog.Int("tasks", len(tasks)))
+	m.activity(ctx, "AddTasksBatch", 0, err)
+	return res, err
+}
+
+// UpdateTask логирует вызов UpdateTask.
+func (m *Middleware) UpdateTask(ctx context.Context, task storage.Task) error {
+	start := time.Now()
+	err := m.inner.UpdateTask(ctx, task)
+	m.log(ctx, "UpdateTask", start, err, m.taskAttr("task", task))
+	m.activity(ctx, "UpdateTask", task.ID, err)
+	return err
+}
+
+// UpsertTask логирует вызов UpsertTask.
+func (m *Middleware) UpsertTask(ctx context.Context, t storage.Task) (int, error) {
+	start := time.Now()
+	res, err := m.inner.UpsertTask(ctx, t)
+	m.log(ctx, "UpsertTask", start, err, m.taskAttr("t", t))
+	m.activity(ctx, "UpsertTask", res, err)
+	return res, err
+}
+
+// PartialUpdateTask логирует вызов PartialUpdateTask.
+func (m *Middleware) PartialUpdateTask(ctx context.Context, taskID int, patch storage.TaskPatch) error {
+	start := time.Now()
+	err := m.inner.PartialUpdateTask(ctx, taskID, patch)
+	m.log(ctx, "PartialUpdateTask", start, err, slog.Int("taskID", taskID), m.patchAttr("patch", patch))
+	m.activity(ctx, "PartialUpdateTask", taskID, err)
+	return err
+}
+
+// DeleteTask логирует вызов DeleteTask.
+func (m *Middleware) DeleteTask(ctx context.Context, taskId int) error {
+	start := time.Now()
+	err := m.inner.DeleteTask(ctx, taskId)
+	m.log(ctx, "DeleteTask", start, err, slog.Int("taskId", taskId))
+	m.activity(ctx, "DeleteTask", taskId, err)
+	return err
+}
+
+// DeleteTasks логирует вызов DeleteTasks.
+func (m *Middleware) DeleteTasks(ctx context.Context, taskIDs []int) error {
+	start := time.Now()
+	err := m.inner.DeleteTasks(ctx, taskIDs)
+	m.log(ctx, "DeleteTasks", start, err, slog.Any("taskIDs", taskIDs))
+	m.activity(ctx, "DeleteTasks", 0, err)
+	return err
+}
+
+// TasksIncludingDeleted логирует вызов TasksIncludingDeleted.
+func (m *Middleware) TasksIncludingDeleted(ctx context.Context) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksIncludingDeleted(ctx)
+	m.log(ctx, "TasksIncludingDeleted", start, err)
+	return res, err
+}
+
+// UndeleteTask логирует вызов UndeleteTask.
+func (m *Middleware) UndeleteTask(ctx context.Context, taskID int) error {
+	start := time.Now()
+	err := m.inner.UndeleteTask(ctx, taskID)
+	m.log(ctx, "UndeleteTask", start, err, slog.Int("taskID", taskID))
+	m.activity(ctx, "UndeleteTask", taskID, err)
+	return err
+}
+
+// AddUser логирует вызов AddUser.
+func (m *Middleware) AddUser(ctx context.Context, u storage.User) (int, error) {
+	start := time.Now()
+	res, err := m.inner.AddUser(ctx, u)
+	m.log(ctx, "AddUser", start, err, slog.Any("u", u))
+	m.activity(ctx, "AddUser", 0, err)
+	return res, err
+}
+
+// Users логирует вызов Users.
+func (m *Middleware) Users(ctx context.Context) ([]storage.User, error) {
+	start := time.Now()
+	res, err := m.inner.Users(ctx)
+	m.log(ctx, "Users", start, err)
+	return res, err
+}
+
+// UserByID логирует вызов UserByID.
+func (m *Middleware) UserByID(ctx context.Context, userID int) (*storage.User, error) {
+	start := time.Now()
+	res, err := m.inner.UserByID(ctx, userID)
+	m.log(ctx, "UserByID", start, err, slog.Int("userID", userID))
+	return res, err
+}
+
+// UpdateUser логирует вызов UpdateUser.
+func (m *Middleware) UpdateUser(ctx context.Context, u storage.User) error {
+	start := time.Now()
+	err := m.inner.UpdateUser(ctx, u)
+	m.log(ctx, "UpdateUser", start, err, slog.Any("u", u))
+	m.activity(ctx, "UpdateUser", 0, err)
+	return err
+}
+
+// DeleteUser логирует вызов DeleteUser.
+func (m *Middleware) DeleteUser(ctx context.Context, userID int) error {
+	start := time.Now()
+	err := m.inner.DeleteUser(ctx, userID)
+	m.log(ctx, "DeleteUser", start, err, slog.Int("userID", userID))
+	m.activity(ctx, "DeleteUser", 0, err)
+	return err
+}
+
+// AddLabel логирует вызов AddLabel.
+func (m *Middleware) AddLabel(ctx context.Context, l storage.Label) (int, error) {
+	start := time.Now()
+	res, err := m.inner.AddLabel(ctx, l)
+	m.log(ctx, "AddLabel", start, err, slog.Any("l", l))
+	m.activity(ctx, "AddLabel", 0, err)
+	return res, err
+}
+
+// Labels логирует вызов Labels.
+func (m *Middleware) Labels(ctx context.Context) ([]storage.Label, error) {
+	start := time.Now()
+	res, err := m.inner.Labels(ctx)
+	m.log(ctx, "Labels", start, err)
+	return res, err
+}
+
+// LabelByID логирует вызов LabelByID.
+func (m *Middleware) LabelByID(ctx context.Context, labelID int) (*storage.Label, error) {
+	start := time.Now()
+	res, err := m.inner.LabelByID(ctx, labelID)
+	m.log(ctx, "LabelByID", start, err, slog.Int("labelID", labelID))
+	return res, err
+}
+
+// UpdateLabel логирует вызов UpdateLabel.
+func (m *Middleware) UpdateLabel(ctx context.Context, l storage.Label) error {
+	start := time.Now()
+	err := m.inner.UpdateLabel(ctx, l)
+	m.log(ctx, "UpdateLabel", start, err, slog.Any("l", l))
+	m.activity(ctx, "UpdateLabel", 0, err)
+	return err
+}
+
+// DeleteLabel логирует вызов DeleteLabel.
+func (m *Middleware) DeleteLabel(ctx context.Context, labelID int) error {
+	start := time.Now()
+	err := m.inner.DeleteLabel(ctx, labelID)
+	m.log(ctx, "DeleteLabel", start, err, slog.Int("labelID", labelID))
+	m.activity(ctx, "DeleteLabel", 0, err)
+	return err
+}
+
+// AssignLabel логирует вызов AssignLabel.
+func (m *Middleware) AssignLabel(ctx context.Context, taskID, labelID int) error {
+	start := time.Now()
+	err := m.inner.AssignLabel(ctx, taskID, labelID)
+	m.log(ctx, "AssignLabel", start, err, slog.Int("taskID", taskID), slog.Int("labelID", labelID))
+	m.activity(ctx, "AssignLabel", taskID, err)
+	return err
+}
+
+// RemoveLabel логирует вызов RemoveLabel.
+func (m *Middleware) RemoveLabel(ctx context.Context, taskID, labelID int) error {
+	start := time.Now()
+	err := m.inner.RemoveLabel(ctx, taskID, labelID)
+	m.log(ctx, "RemoveLabel", start, err, slog.Int("taskID", taskID), slog.Int("labelID", labelID))
+	m.activity(ctx, "RemoveLabel", taskID, err)
+	return err
+}
+
+// TasksByStatus логирует вызов TasksByStatus.
+func (m *Middleware) TasksByStatus(ctx context.Context, s storage.Status) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksByStatus(ctx, s)
+	m.log(ctx, "TasksByStatus", start, err, slog.Any("s", s))
+	return res, err
+}
+
+// UpdateTaskStatus логирует вызов UpdateTaskStatus.
+func (m *Middleware) UpdateTaskStatus(ctx context.Context, taskID int, s storage.Status) error {
+	start := time.Now()
+	err := m.inner.UpdateTaskStatus(ctx, taskID, s)
+	m.log(ctx, "UpdateTaskStatus", start, err, slog.Int("taskID", taskID), slog.Any("s", s))
+	m.activity(ctx, "UpdateTaskStatus", taskID, err)
+	return err
+}
+
+// TasksByPriority логирует вызов TasksByPriority.
+func (m *Middleware) TasksByPriority(ctx context.Context, p storage.Priority) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksByPriority(ctx, p)
+	m.log(ctx, "TasksByPriority", start, err, slog.Any("p", p))
+	return res, err
+}
+
+// TasksOrderedByPriority логирует вызов TasksOrderedByPriority.
+func (m *Middleware) TasksOrderedByPriority(ctx context.Context) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksOrderedByPriority(ctx)
+	m.log(ctx, "TasksOrderedByPriority", start, err)
+	return res, err
+}
+
+// TasksOverdue логирует вызов TasksOverdue.
+func (m *Middleware) TasksOverdue(ctx context.Context, now int64) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksOverdue(ctx, now)
+	m.log(ctx, "TasksOverdue", start, err, slog.Int64("now", now))
+	return res, err
+}
+
+// TasksDueBetween логирует вызов TasksDueBetween.
+func (m *Middleware) TasksDueBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksDueBetween(ctx, from, to)
+	m.log(ctx, "TasksDueBetween", start, err, slog.Int64("from", from), slog.Int64("to", to))
+	return res, err
+}
+
+// AddComment логирует вызов AddComment.
+func (m *Middleware) AddComment(ctx context.Context, c storage.Comment) (int, error) {
+	start := time.Now()
+	res, err := m.inner.AddComment(ctx, c)
+	m.log(ctx, "AddComment", start, err, slog.Any("c", c))
+	m.activity(ctx, "AddComment", c.TaskID, err)
+	return res, err
+}
+
+// CommentsByTask логирует вызов CommentsByTask.
+func (m *Middleware) CommentsByTask(ctx context.Context, taskID int) ([]storage.Comment, error) {
+	start := time.Now()
+	res, err := m.inner.CommentsByTask(ctx, taskID)
+	m.log(ctx, "CommentsByTask", start, err, slog.Int("taskID", taskID))
+	return res, err
+}
+
+// UpdateComment логирует вызов UpdateComment.
+func (m *Middleware) UpdateComment(ctx context.Context, c storage.Comment) error {
+	start := time.Now()
+	err := m.inner.UpdateComment(ctx, c)
+	m.log(ctx, "UpdateComment", start, err, slog.Any("c", c))
+	m.activity(ctx, "UpdateComment", c.TaskID, err)
+	return err
+}
+
+// DeleteComment логирует вызов DeleteComment.
+func (m *Middleware) DeleteComment(ctx context.Context, commentID int) error {
+	start := time.Now()
+	err := m.inner.DeleteComment(ctx, commentID)
+	m.log(ctx, "DeleteComment", start, err, slog.Int("commentID", commentID))
+	m.activity(ctx, "DeleteComment", 0, err)
+	return err
+}
+
+// LogTime логирует вызов LogTime.
+func (m *Middleware) LogTime(ctx context.Context, e storage.TimeEntry) (int, error) {
+	start := time.Now()
+	res, err := m.inner.LogTime(ctx, e)
+	m.log(ctx, "LogTime", start, err, slog.Any("e", e))
+	m.activity(ctx, "LogTime", e.TaskID, err)
+	return res, err
+}
+
+// TimeEntriesByTask логирует вызов TimeEntriesByTask.
+func (m *Middleware) TimeEntriesByTask(ctx context.Context, taskID int) ([]storage.TimeEntry, error) {
+	start := time.Now()
+	res, err := m.inner.TimeEntriesByTask(ctx, taskID)
+	m.log(ctx, "TimeEntriesByTask", start, err, slog.Int("taskID", taskID))
+	return res, err
+}
+
+// TimeEntriesByUser логирует вызов TimeEntriesByUser.
+func (m *Middleware) TimeEntriesByUser(ctx context.Context, userID int) ([]storage.TimeEntry, error) {
+	start := time.Now()
+	res, err := m.inner.TimeEntriesByUser(ctx, userID)
+	m.log(ctx, "TimeEntriesByUser", start, err, slog.Int("userID", userID))
+	return res, err
+}
+
+// TotalMinutesByTask логирует вызов TotalMinutesByTask.
+func (m *Middleware) TotalMinutesByTask(ctx context.Context, taskID int) (int, error) {
+	start := time.Now()
+	res, err := m.inner.TotalMinutesByTask(ctx, taskID)
+	m.log(ctx, "TotalMinutesByTask", start, err, slog.Int("taskID", taskID))
+	return res, err
+}
+
+// AddChecklistItem логирует вызов AddChecklistItem.
+func (m *Middleware) AddChecklistItem(ctx context.Context, item storage.ChecklistItem) (int, error) {
+	start := time.Now()
+	res, err := m.inner.AddChecklistItem(ctx, item)
+	m.log(ctx, "AddChecklistItem", start, err, slog.Any("item", item))
+	m.activity(ctx, "AddChecklistItem", item.TaskID, err)
+	return res, err
+}
+
+// ChecklistByTask логирует вызов ChecklistByTask.
+func (m *Middleware) ChecklistByTask(ctx context.Context, taskID int) ([]storage.ChecklistItem, error) {
+	start := time.Now()
+	res, err := m.inner.ChecklistByTask(ctx, taskID)
+	m.log(ctx, "ChecklistByTask", start, err, slog.Int("taskID", taskID))
+	return res, err
+}
+
+// UpdateChecklistItem логирует вызов UpdateChecklistItem.
+func (m *Middleware) UpdateChecklistItem(ctx context.Context, item storage.ChecklistItem) error {
+	start := time.Now()
+	err := m.inner.UpdateChecklistItem(ctx, item)
+	m.log(ctx, "UpdateChecklistItem", start, err, slog.Any("item", item))
+	m.activity(ctx, "UpdateChecklistItem", item.TaskID, err)
+	return err
+}
+
+// DeleteChecklistItem логирует вызов DeleteChecklistItem.
+func (m *Middleware) DeleteChecklistItem(ctx context.Context, itemID int) error {
+	start := time.Now()
+	err := m.inner.DeleteChecklistItem(ctx, itemID)
+	m.log(ctx, "DeleteChecklistItem", start, err, slog.Int("itemID", itemID))
+	m.activity(ctx, "DeleteChecklistItem", 0, err)
+	return err
+}
+
+// ReorderChecklist логирует вызов ReorderChecklist.
+func (m *Middleware) ReorderChecklist(ctx context.Context, taskID int, orderedIDs []int) error {
+	start := time.Now()
+	err := m.inner.ReorderChecklist(ctx, taskID, orderedIDs)
+	m.log(ctx, "ReorderChecklist", start, err, slog.Int("taskID", taskID), slog.Any("orderedIDs", orderedIDs))
+	m.activity(ctx, "ReorderChecklist", taskID, err)
+	return err
+}
+
+// AddProject логирует вызов AddProject.
+func (m *Middleware) AddProject(ctx context.Context, p storage.Project) (int, error) {
+	start := time.Now()
+	res, err := m.inner.AddProject(ctx, p)
+	m.log(ctx, "AddProject", start, err, slog.Any("p", p))
+	m.activity(ctx, "AddProject", 0, err)
+	return res, err
+}
+
+// Projects логирует вызов Projects.
+func (m *Middleware) Projects(ctx context.Context) ([]storage.Project, error) {
+	start := time.Now()
+	res, err := m.inner.Projects(ctx)
+	m.log(ctx, "Projects", start, err)
+	return res, err
+}
+
+// ProjectByID логирует вызов ProjectByID.
+func (m *Middleware) ProjectByID(ctx context.Context, projectID int) (*storage.Project, error) {
+	start := time.Now()
+	res, err := m.inner.ProjectByID(ctx, projectID)
+	m.log(ctx, "ProjectByID", start, err, slog.Int("projectID", projectID))
+	return res, err
+}
+
+// UpdateProject логирует вызов UpdateProject.
+func (m *Middleware) UpdateProject(ctx context.Context, p storage.Project) error {
+	start := time.Now()
+	err := m.inner.UpdateProject(ctx, p)
+	m.log(ctx, "UpdateProject", start, err, slog.Any("p", p))
+	m.activity(ctx, "UpdateProject", 0, err)
+	return err
+}
+
+// DeleteProject логирует вызов DeleteProject.
+func (m *Middleware) DeleteProject(ctx context.Context, projectID int, cascadeDelete bool) error {
+	start := time.Now()
+	err := m.inner.DeleteProject(ctx, projectID, cascadeDelete)
+	m.log(ctx, "DeleteProject", start, err, slog.Int("projectID", projectID), slog.Bool("cascadeDelete", cascadeDelete))
+	m.activity(ctx, "DeleteProject", 0, err)
+	return err
+}
+
+// TasksByProject логирует вызов TasksByProject.
+func (m *Middleware) TasksByProject(ctx context.Context, projectID int) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksByProject(ctx, projectID)
+	m.log(ctx, "TasksByProject", start, err, slog.Int("projectID", projectID))
+	return res, err
+}
+
+// AddDependency логирует вызов AddDependency.
+func (m *Middleware) AddDependency(ctx context.Context, taskID, dependsOnID int) error {
+	start := time.Now()
+	err := m.inner.AddDependency(ctx, taskID, dependsOnID)
+	m.log(ctx, "AddDependency", start, err, slog.Int("taskID", taskID), slog.Int("dependsOnID", dependsOnID))
+	m.activity(ctx, "AddDependency", taskID, err)
+	return err
+}
+
+// RemoveDependency логирует вызов RemoveDependency.
+func (m *Middleware) RemoveDependency(ctx context.Context, taskID, dependsOnID int) error {
+	start := time.Now()
+	err := m.inner.RemoveDependency(ctx, taskID, dependsOnID)
+	m.log(ctx, "RemoveDependency", start, err, slog.Int("taskID", taskID), slog.Int("dependsOnID", dependsOnID))
+	m.activity(ctx, "RemoveDependency", taskID, err)
+	return err
+}
+
+// DependenciesOf логирует вызов DependenciesOf.
+func (m *Middleware) DependenciesOf(ctx context.Context, taskID int) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.DependenciesOf(ctx, taskID)
+	m.log(ctx, "DependenciesOf", start, err, slog.Int("taskID", taskID))
+	return res, err
+}
+
+// BlockedBy логирует вызов BlockedBy.
+func (m *Middleware) BlockedBy(ctx context.Context, taskID int) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.BlockedBy(ctx, taskID)
+	m.log(ctx, "BlockedBy", start, err, slog.Int("taskID", taskID))
+	return res, err
+}
+
+// AddTaskWithLabels логирует вызов AddTaskWithLabels.
+func (m *Middleware) AddTaskWithLabels(ctx context.Context, t storage.Task, labelIDs []int) (int, error) {
+	start := time.Now()
+	res, err := m.inner.AddTaskWithLabels(ctx, t, labelIDs)
+	m.log(ctx, "AddTaskWithLabels", start, err, m.taskAttr("t", t), slog.Any("labelIDs", labelIDs))
+	m.activity(ctx, "AddTaskWithLabels", res, err)
+	return res, err
+}
+
+// ImportTasks логирует вызов ImportTasks.
+func (m *Middleware) ImportTasks(ctx context.Context, tasks []storage.Task) error {
+	start := time.Now()
+	err := m.inner.ImportTasks(ctx, tasks)
+	m.log(ctx, "ImportTasks", start, err, slog.Int("tasks", len(tasks)))
+	m.activity(ctx, "ImportTasks", 0, err)
+	return err
+}
+
+// TasksWithLabels логирует вызов TasksWithLabels.
+func (m *Middleware) TasksWithLabels(ctx context.Context) ([]storage.TaskWithLabels, error) {
+	start := time.Now()
+	res, err := m.inner.TasksWithLabels(ctx)
+	m.log(ctx, "TasksWithLabels", start, err)
+	return res, err
+}
+
+// TasksWithUsers логирует вызов TasksWithUsers.
+func (m *Middleware) TasksWithUsers(ctx context.Context) ([]storage.TaskWithUsers, error) {
+	start := time.Now()
+	res, err := m.inner.TasksWithUsers(ctx)
+	m.log(ctx, "TasksWithUsers", start, err)
+	return res, err
+}
+
+// BeginTx логирует вызов BeginTx.
+func (m *Middleware) BeginTx(ctx context.Context) (storage.TxStorage, error) {
+	start := time.Now()
+	res, err := m.inner.BeginTx(ctx)
+	m.log(ctx, "BeginTx", start, err)
+	return res, err
+}
+
+// AuditLog логирует вызов AuditLog.
+func (m *Middleware) AuditLog(ctx context.Context, taskID int) ([]storage.AuditEntry, error) {
+	start := time.Now()
+	res, err := m.inner.AuditLog(ctx, taskID)
+	m.log(ctx, "AuditLog", start, err, slog.Int("taskID", taskID))
+	return res, err
+}
+
+// RecordChange логирует вызов RecordChange.
+func (m *Middleware) RecordChange(ctx context.Context, e storage.AuditEntry) error {
+	start := time.Now()
+	err := m.inner.RecordChange(ctx, e)
+	m.log(ctx, "RecordChange", start, err, slog.Any("e", e))
+	return err
+}
+
+// SearchTasks логирует вызов SearchTasks.
+func (m *Middleware) SearchTasks(ctx context.Context, query string, limit int) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.SearchTasks(ctx, query, limit)
+	m.log(ctx, "SearchTasks", start, err, slog.String("query", query), slog.Int("limit", limit))
+	return res, err
+}
+
+// TasksByMetadataKey логирует вызов TasksByMetadataKey.
+func (m *Middleware) TasksByMetadataKey(ctx context.Context, key, value string) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksByMetadataKey(ctx, key, value)
+	m.log(ctx, "TasksByMetadataKey", start, err, slog.String("key", key), slog.String("value", value))
+	return res, err
+}
+
+// AssignmentHistory логирует вызов AssignmentHistory.
+func (m *Middleware) AssignmentHistory(ctx context.Context, taskID int) ([]storage.AssignmentEvent, error) {
+	start := time.Now()
+	res, err := m.inner.AssignmentHistory(ctx, taskID)
+	m.log(ctx, "AssignmentHistory", start, err, slog.Int("taskID", taskID))
+	return res, err
+}
+
+// RecordAssignment логирует вызов RecordAssignment.
+func (m *Middleware) RecordAssignment(ctx context.Context, e storage.AssignmentEvent) error {
+	start := time.Now()
+	err := m.inner.RecordAssignment(ctx, e)
+	m.log(ctx, "RecordAssignment", start, err, slog.Any("e", e))
+	return err
+}
+
+// AddRecurrenceRule логирует вызов AddRecurrenceRule.
+func (m *Middleware) AddRecurrenceRule(ctx context.Context, rule storage.RecurrenceRule) error {
+	start := time.Now()
+	err := m.inner.AddRecurrenceRule(ctx, rule)
+	m.log(ctx, "AddRecurrenceRule", start, err, slog.Any("rule", rule))
+	m.activity(ctx, "AddRecurrenceRule", rule.TaskID, err)
+	return err
+}
+
+// RecurrenceRuleByTask логирует вызов RecurrenceRuleByTask.
+func (m *Middleware) RecurrenceRuleByTask(ctx context.Context, taskID int) (*storage.RecurrenceRule, error) {
+	start := time.Now()
+	res, err := m.inner.RecurrenceRuleByTask(ctx, taskID)
+	m.log(ctx, "RecurrenceRuleByTask", start, err, slog.Int("taskID", taskID))
+	return res, err
+}
+
+// UpdateRecurrenceRule логирует вызов UpdateRecurrenceRule.
+func (m *Middleware) UpdateRecurrenceRule(ctx context.Context, rule storage.RecurrenceRule) error {
+	start := time.Now()
+	err := m.inner.UpdateRecurrenceRule(ctx, rule)
+	m.log(ctx, "UpdateRecurrenceRule", start, err, slog.Any("rule", rule))
+	m.activity(ctx, "UpdateRecurrenceRule", rule.TaskID, err)
+	return err
+}
+
+// DeleteRecurrenceRule логирует вызов DeleteRecurrenceRule.
+func (m *Middleware) DeleteRecurrenceRule(ctx context.Context, taskID int) error {
+	start := time.Now()
+	err := m.inner.DeleteRecurrenceRule(ctx, taskID)
+	m.log(ctx, "DeleteRecurrenceRule", start, err, slog.Int("taskID", taskID))
+	m.activity(ctx, "DeleteRecurrenceRule", taskID, err)
+	return err
+}
+
+// DueRecurrences логирует вызов DueRecurrences.
+func (m *Middleware) DueRecurrences(ctx context.Context, now int64) ([]storage.RecurrenceRule, error) {
+	start := time.Now()
+	res, err := m.inner.DueRecurrences(ctx, now)
+	m.log(ctx, "DueRecurrences", start, err, slog.Int64("now", now))
+	return res, err
+}
+
+// SpawnRecurringTask логирует вызов SpawnRecurringTask.
+func (m *Middleware) SpawnRecurringTask(ctx context.Context, rule storage.RecurrenceRule) (int, error) {
+	start := time.Now()
+	res, err := m.inner.SpawnRecurringTask(ctx, rule)
+	m.log(ctx, "SpawnRecurringTask", start, err, slog.Any("rule", rule))
+	m.activity(ctx, "SpawnRecurringTask", res, err)
+	return res, err
+}
+
+// ArchiveTask логирует вызов ArchiveTask.
+func (m *Middleware) ArchiveTask(ctx context.Context, taskID int) error {
+	start := time.Now()
+	err := m.inner.ArchiveTask(ctx, taskID)
+	m.log(ctx, "ArchiveTask", start, err, slog.Int("taskID", taskID))
+	m.activity(ctx, "ArchiveTask", taskID, err)
+	return err
+}
+
+// ArchivedTasks логирует вызов ArchivedTasks.
+func (m *Middleware) ArchivedTasks(ctx context.Context) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.ArchivedTasks(ctx)
+	m.log(ctx, "ArchivedTasks", start, err)
+	return res, err
+}
+
+// UnarchiveTask логирует вызов UnarchiveTask.
+func (m *Middleware) UnarchiveTask(ctx context.Context, taskID int) error {
+	start := time.Now()
+	err := m.inner.UnarchiveTask(ctx, taskID)
+	m.log(ctx, "UnarchiveTask", start, err, slog.Int("taskID", taskID))
+	m.activity(ctx, "UnarchiveTask", taskID, err)
+	return err
+}
+
+// TasksByLabels логирует вызов TasksByLabels.
+func (m *Middleware) TasksByLabels(ctx context.Context, labelIDs []int, mode storage.LabelFilterMode) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksByLabels(ctx, labelIDs, mode)
+	m.log(ctx, "TasksByLabels", start, err, slog.Any("labelIDs", labelIDs), slog.Any("mode", mode))
+	return res, err
+}
+
+// TasksOpenedBetween логирует вызов TasksOpenedBetween.
+func (m *Middleware) TasksOpenedBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksOpenedBetween(ctx, from, to)
+	m.log(ctx, "TasksOpenedBetween", start, err, slog.Int64("from", from), slog.Int64("to", to))
+	return res, err
+}
+
+// TasksClosedBetween логирует вызов TasksClosedBetween.
+func (m *Middleware) TasksClosedBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksClosedBetween(ctx, from, to)
+	m.log(ctx, "TasksClosedBetween", start, err, slog.Int64("from", from), slog.Int64("to", to))
+	return res, err
+}
+
+// AddSprint логирует вызов AddSprint.
+func (m *Middleware) AddSprint(ctx context.Context, sp storage.Sprint) (int, error) {
+	start := time.Now()
+	res, err := m.inner.AddSprint(ctx, sp)
+	m.log(ctx, "AddSprint", start, err, slog.Any("sp", sp))
+	m.activity(ctx, "AddSprint", 0, err)
+	return res, err
+}
+
+// SprintByID логирует вызов SprintByID.
+func (m *Middleware) SprintByID(ctx context.Context, sprintID int) (*storage.Sprint, error) {
+	start := time.Now()
+	res, err := m.inner.SprintByID(ctx, sprintID)
+	m.log(ctx, "SprintByID", start, err, slog.Int("sprintID", sprintID))
+	return res, err
+}
+
+// Sprints логирует вызов Sprints.
+func (m *Middleware) Sprints(ctx context.Context) ([]storage.Sprint, error) {
+	start := time.Now()
+	res, err := m.inner.Sprints(ctx)
+	m.log(ctx, "Sprints", start, err)
+	return res, err
+}
+
+// UpdateSprint логирует вызов UpdateSprint.
+func (m *Middleware) UpdateSprint(ctx context.Context, sp storage.Sprint) error {
+	start := time.Now()
+	err := m.inner.UpdateSprint(ctx, sp)
+	m.log(ctx, "UpdateSprint", start, err, slog.Any("sp", sp))
+	m.activity(ctx, "UpdateSprint", 0, err)
+	return err
+}
+
+// DeleteSprint логирует вызов DeleteSprint.
+func (m *Middleware) DeleteSprint(ctx context.Context, sprintID int) error {
+	start := time.Now()
+	err := m.inner.DeleteSprint(ctx, sprintID)
+	m.log(ctx, "DeleteSprint", start, err, slog.Int("sprintID", sprintID))
+	m.activity(ctx, "DeleteSprint", 0, err)
+	return err
+}
+
+// AssignTaskToSprint логирует вызов AssignTaskToSprint.
+func (m *Middleware) AssignTaskToSprint(ctx context.Context, taskID, sprintID int) error {
+	start := time.Now()
+	err := m.inner.AssignTaskToSprint(ctx, taskID, sprintID)
+	m.log(ctx, "AssignTaskToSprint", start, err, slog.Int("taskID", taskID), slog.Int("sprintID", sprintID))
+	m.activity(ctx, "AssignTaskToSprint", taskID, err)
+	return err
+}
+
+// RemoveTaskFromSprint логирует вызов RemoveTaskFromSprint.
+func (m *Middleware) RemoveTaskFromSprint(ctx context.Context, taskID, sprintID int) error {
+	start := time.Now()
+	err := m.inner.RemoveTaskFromSprint(ctx, taskID, sprintID)
+	m.log(ctx, "RemoveTaskFromSprint", start, err, slog.Int("taskID", taskID), slog.Int("sprintID", sprintID))
+	m.activity(ctx, "RemoveTaskFromSprint", taskID, err)
+	return err
+}
+
+// TasksBySprint логирует вызов TasksBySprint.
+func (m *Middleware) TasksBySprint(ctx context.Context, sprintID int) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksBySprint(ctx, sprintID)
+	m.log(ctx, "TasksBySprint", start, err, slog.Int("sprintID", sprintID))
+	return res, err
+}
+
+// SprintVelocity логирует вызов SprintVelocity.
+func (m *Middleware) SprintVelocity(ctx context.Context, sprintID int) (int, error) {
+	start := time.Now()
+	res, err := m.inner.SprintVelocity(ctx, sprintID)
+	m.log(ctx, "SprintVelocity", start, err, slog.Int("sprintID", sprintID))
+	return res, err
+}
+
+// BurndownData логирует вызов BurndownData.
+func (m *Middleware) BurndownData(ctx context.Context, sprintID int, buckets int) ([]storage.BurndownPoint, error) {
+	start := time.Now()
+	res, err := m.inner.BurndownData(ctx, sprintID, buckets)
+	m.log(ctx, "BurndownData", start, err, slog.Int("sprintID", sprintID), slog.Int("buckets", buckets))
+	return res, err
+}
+
+// UserWorkloads логирует вызов UserWorkloads.
+func (m *Middleware) UserWorkloads(ctx context.Context) ([]storage.UserWorkload, error) {
+	start := time.Now()
+	res, err := m.inner.UserWorkloads(ctx)
+	m.log(ctx, "UserWorkloads", start, err)
+	return res, err
+}
+
+// UserWorkload логирует вызов UserWorkload.
+func (m *Middleware) UserWorkload(ctx context.Context, userID int) (*storage.UserWorkload, error) {
+	start := time.Now()
+	res, err := m.inner.UserWorkload(ctx, userID)
+	m.log(ctx, "UserWorkload", start, err, slog.Int("userID", userID))
+	return res, err
+}
+
+// TaskStats логирует вызов TaskStats.
+func (m *Middleware) TaskStats(ctx context.Context) (*storage.TaskStats, error) {
+	start := time.Now()
+	res, err := m.inner.TaskStats(ctx)
+	m.log(ctx, "TaskStats", start, err)
+	return res, err
+}
+
+// LabelStats логирует вызов LabelStats.
+func (m *Middleware) LabelStats(ctx context.Context) ([]storage.LabelStat, error) {
+	start := time.Now()
+	res, err := m.inner.LabelStats(ctx)
+	m.log(ctx, "LabelStats", start, err)
+	return res, err
+}
+
+// TopLabels логирует вызов TopLabels.
+func (m *Middleware) TopLabels(ctx context.Context, n int) ([]storage.LabelStat, error) {
+	start := time.Now()
+	res, err := m.inner.TopLabels(ctx, n)
+	m.log(ctx, "TopLabels", start, err, slog.Int("n", n))
+	return res, err
+}
+
+// RecordActivity логирует вызов RecordActivity.
+func (m *Middleware) RecordActivity(ctx context.Context, e storage.ActivityEvent) error {
+	start := time.Now()
+	err := m.inner.RecordActivity(ctx, e)
+	m.log(ctx, "RecordActivity", start, err, slog.Any("e", e))
+	return err
+}
+
+// ActivityFeed логирует вызов ActivityFeed.
+func (m *Middleware) ActivityFeed(ctx context.Context, taskID int, limit int) ([]storage.ActivityEvent, error) {
+	start := time.Now()
+	res, err := m.inner.ActivityFeed(ctx, taskID, limit)
+	m.log(ctx, "ActivityFeed", start, err, slog.Int("taskID", taskID), slog.Int("limit", limit))
+	return res, err
+}
+
+// GlobalActivityFeed логирует вызов GlobalActivityFeed.
+func (m *Middleware) GlobalActivityFeed(ctx context.Context, limit int) ([]storage.ActivityEvent, error) {
+	start := time.Now()
+	res, err := m.inner.GlobalActivityFeed(ctx, limit)
+	m.log(ctx, "GlobalActivityFeed", start, err, slog.Int("limit", limit))
+	return res, err
+}
+
+// AppendEvents логирует вызов AppendEvents.
+func (m *Middleware) AppendEvents(ctx context.Context, streamID string, expectedSeq int64, events []storage.Event) error {
+	start := time.Now()
+	err := m.inner.AppendEvents(ctx, streamID, expectedSeq, events)
+	m.log(ctx, "AppendEvents", start, err, slog.String("streamID", streamID), slog.Int64("expectedSeq", expectedSeq), slog.Int("count", len(events)), slog.Any("types", eventTypes(events)))
+	return err
+}
+
+// eventTypes возвращает типы событий без повторов в порядке
+// первого появления. Содержимое событий в журнал не попадает.
+func eventTypes(events []storage.Event) []string {
+	var types []string
+	for _, e := range events {
+		if !slices.Contains(types, e.EventType) {
+			types = append(types, e.EventType)
+		}
+	}
+	return types
+}
+
+// LoadEvents логирует вызов LoadEvents.
+func (m *Middleware) LoadEvents(ctx context.Context, streamID string, fromSeq int64) ([]storage.Event, error) {
+	start := time.Now()
+	res, err := m.inner.LoadEvents(ctx, streamID, fromSeq)
+	m.log(ctx, "LoadEvents", start, err, slog.String("streamID", streamID), slog.Int64("fromSeq", fromSeq))
+	return res, err
+}
+
+// AppendOutbox логирует вызов AppendOutbox.
+func (m *Middleware) AppendOutbox(ctx context.Context, tx storage.TxStorage, msgs []storage.OutboxMessage) error {
+	start := time.Now()
+	err := m.inner.AppendOutbox(ctx, tx, msgs)
+	m.log(ctx, "AppendOutbox", start, err, slog.Int("count", len(msgs)), slog.Any("topics", outboxTopics(msgs)))
+	return err
+}
+
+// outboxTopics возвращает темы сообщений без повторов в порядке
+// первого появления. Содержимое сообщений в журнал не попадает.
+func outboxTopics(msgs []storage.OutboxMessage) []string {
+	var topics []string
+	for _, msg := range msgs {
+		if !slices.Contains(topics, msg.Topic) {
+			topics = append(topics, msg.Topic)
+		}
+	}
+	return topics
+}
+
+// PendingOutbox логирует вызов PendingOutbox.
+func (m *Middleware) PendingOutbox(ctx context.Context, limit int) ([]storage.OutboxMessage, error) {
+	start := time.Now()
+	res, err := m.inner.PendingOutbox(ctx, limit)
+	m.log(ctx, "PendingOutbox", start, err, slog.Int("limit", limit))
+	return res, err
+}
+
+// MarkOutboxSent логирует вызов MarkOutboxSent.
+func (m *Middleware) MarkOutboxSent(ctx context.Context, ids []int64) error {
+	start := time.Now()
+	err := m.inner.MarkOutboxSent(ctx, ids)
+	m.log(ctx, "MarkOutboxSent", start, err, slog.Any("ids", ids))
+	return err
+}
+
+// TasksClosedInRange логирует вызов TasksClosedInRange.
+func (m *Middleware) TasksClosedInRange(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksClosedInRange(ctx, from, to)
+	m.log(ctx, "TasksClosedInRange", start, err, slog.Int64("from", from), slog.Int64("to", to))
+	return res, err
+}
+
+// TasksUnassigned логирует вызов TasksUnassigned.
+func (m *Middleware) TasksUnassigned(ctx context.Context) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksUnassigned(ctx)
+	m.log(ctx, "TasksUnassigned", start, err)
+	return res, err
+}
+
+// AssignTask логирует вызов AssignTask.
+func (m *Middleware) AssignTask(ctx context.Context, taskID, userID int) error {
+	start := time.Now()
+	err := m.inner.AssignTask(ctx, taskID, userID)
+	m.log(ctx, "AssignTask", start, err, slog.Int("taskID", taskID), slog.Int("userID", userID))
+	m.activity(ctx, "AssignTask", taskID, err)
+	return err
+}
+
+// TaskByExternalID логирует вызов TaskByExternalID.
+func (m *Middleware) TaskByExternalID(ctx context.Context, system, externalID string) (*storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TaskByExternalID(ctx, system, externalID)
+	m.log(ctx, "TaskByExternalID", start, err, slog.String("system", system), slog.String("externalID", externalID))
+	return res, err
+}
+
+// SetNotificationPreference логирует вызов SetNotificationPreference.
+func (m *Middleware) SetNotificationPreference(ctx context.Context, p storage.NotificationPreference) error {
+	start := time.Now()
+	err := m.inner.SetNotificationPreference(ctx, p)
+	m.log(ctx, "SetNotificationPreference", start, err, slog.Any("p", p))
+	return err
+}
+
+// GetNotificationPreference логирует вызов GetNotificationPreference.
+func (m *Middleware) GetNotificationPreference(ctx context.Context, userID int) (*storage.NotificationPreference, error) {
+	start := time.Now()
+	res, err := m.inner.GetNotificationPreference(ctx, userID)
+	m.log(ctx, "GetNotificationPreference", start, err, slog.Int("userID", userID))
+	return res, err
+}
+
+// UsersSubscribedToTask логирует вызов UsersSubscribedToTask.
+func (m *Middleware) UsersSubscribedToTask(ctx context.Context, taskID int, event string) ([]storage.User, error) {
+	start := time.Now()
+	res, err := m.inner.UsersSubscribedToTask(ctx, taskID, event)
+	m.log(ctx, "UsersSubscribedToTask", start, err, slog.Int("taskID", taskID), slog.String("event", event))
+	return res, err
+}
+
+// WatchTask логирует вызов WatchTask.
+func (m *Middleware) WatchTask(ctx context.Context, userID, taskID int) error {
+	start := time.Now()
+	err := m.inner.WatchTask(ctx, userID, taskID)
+	m.log(ctx, "WatchTask", start, err, slog.Int("userID", userID), slog.Int("taskID", taskID))
+	return err
+}
+
+// UnwatchTask логирует вызов UnwatchTask.
+func (m *Middleware) UnwatchTask(ctx context.Context, userID, taskID int) error {
+	start := time.Now()
+	err := m.inner.UnwatchTask(ctx, userID, taskID)
+	m.log(ctx, "UnwatchTask", start, err, slog.Int("userID", userID), slog.Int("taskID", taskID))
+	return err
+}
+
+// WatchersOfTask логирует вызов WatchersOfTask.
+func (m *Middleware) WatchersOfTask(ctx context.Context, taskID int) ([]storage.User, error) {
+	start := time.Now()
+	res, err := m.inner.WatchersOfTask(ctx, taskID)
+	m.log(ctx, "WatchersOfTask", start, err, slog.Int("taskID", taskID))
+	return res, err
+}
+
+// CreateAPIKey логирует вызов CreateAPIKey.
+func (m *Middleware) CreateAPIKey(ctx context.Context, k storage.APIKey) (string, error) {
+	start := time.Now()
+	res, err := m.inner.CreateAPIKey(ctx, k)
+	m.log(ctx, "CreateAPIKey", start, err, slog.Any("k", k))
+	return res, err
+}
+
+// APIKeyByKey логирует вызов APIKeyByKey.
+// В журнал попадает только открытый префикс ключа.
+func (m *Middleware) APIKeyByKey(ctx context.Context, raw string) (*storage.APIKey, error) {
+	start := time.Now()
+	res, err := m.inner.APIKeyByKey(ctx, raw)
+	// без разделителя весь ключ мог бы оказаться секретом
+	var attrs []slog.Attr
+	if prefix, _, found := strings.Cut(raw, "."); found {
+		attrs = append(attrs, slog.String("prefix", prefix))
+	}
+	m.log(ctx, "APIKeyByKey", start, err, attrs...)
+	return res, err
+}
+
+// RevokeAPIKey логирует вызов RevokeAPIKey.
+func (m *Middleware) RevokeAPIKey(ctx context.Context, keyID int) error {
+	start := time.Now()
+	err := m.inner.RevokeAPIKey(ctx, keyID)
+	m.log(ctx, "RevokeAPIKey", start, err, slog.Int("keyID", keyID))
+	return err
+}
+
+// APIKeysByUser логирует вызов APIKeysByUser.
+func (m *Middleware) APIKeysByUser(ctx context.Context, userID int) ([]storage.APIKey, error) {
+	start := time.Now()
+	res, err := m.inner.APIKeysByUser(ctx, userID)
+	m.log(ctx, "APIKeysByUser", start, err, slog.Int("userID", userID))
+	return res, err
+}
+
+// AssignRole логирует вызов AssignRole.
+func (m *Middleware) AssignRole(ctx context.Context, userID int, role storage.Role) error {
+	start := time.Now()
+	err := m.inner.AssignRole(ctx, userID, role)
+	m.log(ctx, "AssignRole", start, err, slog.Int("userID", userID), slog.Any("role", role))
+	m.activity(ctx, "AssignRole", 0, err)
+	return err
+}
+
+// RoleOfUser логирует вызов RoleOfUser.
+func (m *Middleware) RoleOfUser(ctx context.Context, userID int) (storage.Role, error) {
+	start := time.Now()
+	res, err := m.inner.RoleOfUser(ctx, userID)
+	m.log(ctx, "RoleOfUser", start, err, slog.Int("userID", userID))
+	return res, err
+}
+
+// UsersWithRole логирует вызов UsersWithRole.
+func (m *Middleware) UsersWithRole(ctx context.Context, role storage.Role) ([]storage.User, error) {
+	start := time.Now()
+	res, err := m.inner.UsersWithRole(ctx, role)
+	m.log(ctx, "UsersWithRole", start, err, slog.Any("role", role))
+	return res, err
+}
+
+// ExportUserData логирует вызов ExportUserData.
+func (m *Middleware) ExportUserData(ctx context.Context, userID int) (*storage.UserDataExport, error) {
+	start := time.Now()
+	res, err := m.inner.ExportUserData(ctx, userID)
+	m.log(ctx, "ExportUserData", start, err, slog.Int("userID", userID))
+	return res, err
+}
+
+// EraseUser логирует вызов EraseUser.
+func (m *Middleware) EraseUser(ctx context.Context, userID int) error {
+	start := time.Now()
+	err := m.inner.EraseUser(ctx, userID)
+	m.log(ctx, "EraseUser", start, err, slog.Int("userID", userID))
+	return err
+}
+
+// StaleTasks логирует вызов StaleTasks.
+func (m *Middleware) StaleTasks(ctx context.Context, staleAfterSeconds int64, now int64) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.StaleTasks(ctx, staleAfterSeconds, now)
+	m.log(ctx, "StaleTasks", start, err, slog.Int64("staleAfterSeconds", staleAfterSeconds), slog.Int64("now", now))
+	return res, err
+}
+
+// MarkTaskStale логирует вызов MarkTaskStale.
+func (m *Middleware) MarkTaskStale(ctx context.Context, taskID int) error {
+	start := time.Now()
+	err := m.inner.MarkTaskStale(ctx, taskID)
+	m.log(ctx, "MarkTaskStale", start, err, slog.Int("taskID", taskID))
+	return err
+}
+
+// SimilarTasks логирует вызов SimilarTasks.
+func (m *Middleware) SimilarTasks(ctx context.Context, title string, threshold float64) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.SimilarTasks(ctx, title, threshold)
+	m.log(ctx, "SimilarTasks", start, err, slog.String("title", title), slog.Float64("threshold", threshold))
+	return res, err
+}
+
+// AddTaskLink логирует вызов AddTaskLink.
+func (m *Middleware) AddTaskLink(ctx context.Context, l storage.TaskLinkRecord) (int, error) {
+	start := time.Now()
+	res, err := m.inner.AddTaskLink(ctx, l)
+	m.log(ctx, "AddTaskLink", start, err, slog.Any("l", l))
+	m.activity(ctx, "AddTaskLink", 0, err)
+	return res, err
+}
+
+// TaskLinks логирует вызов TaskLinks.
+func (m *Middleware) TaskLinks(ctx context.Context, taskID int) ([]storage.TaskLinkRecord, error) {
+	start := time.Now()
+	res, err := m.inner.TaskLinks(ctx, taskID)
+	m.log(ctx, "TaskLinks", start, err, slog.Int("taskID", taskID))
+	return res, err
+}
+
+// DeleteTaskLink логирует вызов DeleteTaskLink.
+func (m *Middleware) DeleteTaskLink(ctx context.Context, linkID int) error {
+	start := time.Now()
+	err := m.inner.DeleteTaskLink(ctx, linkID)
+	m.log(ctx, "DeleteTaskLink", start, err, slog.Int("linkID", linkID))
+	m.activity(ctx, "DeleteTaskLink", 0, err)
+	return err
+}
+
+// RegisterWebhook логирует вызов RegisterWebhook.
+func (m *Middleware) RegisterWebhook(ctx context.Context, w storage.Webhook) (int, error) {
+	start := time.Now()
+	res, err := m.inner.RegisterWebhook(ctx, w)
+	m.log(ctx, "RegisterWebhook", start, err, webhookAttr("w", w))
+	return res, err
+}
+
+// Webhooks логирует вызов Webhooks.
+func (m *Middleware) Webhooks(ctx context.Context) ([]storage.Webhook, error) {
+	start := time.Now()
+	res, err := m.inner.Webhooks(ctx)
+	m.log(ctx, "Webhooks", start, err)
+	return res, err
+}
+
+// WebhookByID логирует вызов WebhookByID.
+func (m *Middleware) WebhookByID(ctx context.Context, webhookID int) (*storage.Webhook, error) {
+	start := time.Now()
+	res, err := m.inner.WebhookByID(ctx, webhookID)
+	m.log(ctx, "WebhookByID", start, err, slog.Int("webhookID", webhookID))
+	return res, err
+}
+
+// UpdateWebhook логирует вызов UpdateWebhook.
+func (m *Middleware) UpdateWebhook(ctx context.Context, w storage.Webhook) error {
+	start := time.Now()
+	err := m.inner.UpdateWebhook(ctx, w)
+	m.log(ctx, "UpdateWebhook", start, err, webhookAttr("w", w))
+	m.activity(ctx, "UpdateWebhook", 0, err)
+	return err
+}
+
+// DeleteWebhook логирует вызов DeleteWebhook.
+func (m *Middleware) DeleteWebhook(ctx context.Context, webhookID int) error {
+	start := time.Now()
+	err := m.inner.DeleteWebhook(ctx, webhookID)
+	m.log(ctx, "DeleteWebhook", start, err, slog.Int("webhookID", webhookID))
+	m.activity(ctx, "DeleteWebhook", 0, err)
+	return err
+}
+
+// LockTask логирует вызов LockTask.
+func (m *Middleware) LockTask(ctx context.Context, taskID int) error {
+	start := time.Now()
+	err := m.inner.LockTask(ctx, taskID)
+	m.log(ctx, "LockTask", start, err, slog.Int("taskID", taskID))
+	return err
+}
+
+// UnlockTask логирует вызов UnlockTask.
+func (m *Middleware) UnlockTask(ctx context.Context, taskID int) error {
+	start := time.Now()
+	err := m.inner.UnlockTask(ctx, taskID)
+	m.log(ctx, "UnlockTask", start, err, slog.Int("taskID", taskID))
+	return err
+}
+
+// UpdateTasksAssignee логирует вызов UpdateTasksAssignee.
+func (m *Middleware) UpdateTasksAssignee(ctx context.Context, taskIDs []int, assigneeID int) (int64, error) {
+	start := time.Now()
+	res, err := m.inner.UpdateTasksAssignee(ctx, taskIDs, assigneeID)
+	m.log(ctx, "UpdateTasksAssignee", start, err, slog.Any("taskIDs", taskIDs), slog.Int("assigneeID", assigneeID))
+	m.activity(ctx, "UpdateTasksAssignee", 0, err)
+	return res, err
+}
+
+// UpdateTasksStatus логирует вызов UpdateTasksStatus.
+func (m *Middleware) UpdateTasksStatus(ctx context.Context, taskIDs []int, status storage.Status) (int64, error) {
+	start := time.Now()
+	res, err := m.inner.UpdateTasksStatus(ctx, taskIDs, status)
+	m.log(ctx, "UpdateTasksStatus", start, err, slog.Any("taskIDs", taskIDs), slog.Any("status", status))
+	m.activity(ctx, "UpdateTasksStatus", 0, err)
+	return res, err
+}
+
+// TasksByTag логирует вызов TasksByTag.
+func (m *Middleware) TasksByTag(ctx context.Context, tag string) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksByTag(ctx, tag)
+	m.log(ctx, "TasksByTag", start, err, slog.String("tag", tag))
+	return res, err
+}
+
+// AllTags логирует вызов AllTags.
+func (m *Middleware) AllTags(ctx context.Context) ([]string, error) {
+	start := time.Now()
+	res, err := m.inner.AllTags(ctx)
+	m.log(ctx, "AllTags", start, err)
+	return res, err
+}
+
+// TasksByRank логирует вызов TasksByRank.
+func (m *Middleware) TasksByRank(ctx context.Context) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksByRank(ctx)
+	m.log(ctx, "TasksByRank", start, err)
+	return res, err
+}
+
+// UpdateTaskRank логирует вызов UpdateTaskRank.
+func (m *Middleware) UpdateTaskRank(ctx context.Context, taskID int, rank float64) error {
+	start := time.Now()
+	err := m.inner.UpdateTaskRank(ctx, taskID, rank)
+	m.log(ctx, "UpdateTaskRank", start, err, slog.Int("taskID", taskID), slog.Float64("rank", rank))
+	m.activity(ctx, "UpdateTaskRank", taskID, err)
+	return err
+}
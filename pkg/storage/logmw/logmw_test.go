@@ -0,0 +1,160 @@
+package logmw
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"skillfactory/30.8.1/pkg/storage"
+	"skillfactory/30.8.1/pkg/storage/mock"
+	"strings"
+	"testing"
+)
+
+// newTestMiddleware возвращает Middleware, пишущий журнал в формате JSON
+// в буфер, и функцию, возвращающую записанные записи.
+func newTestMiddleware(t *testing.T, inner storage.Interface, opts ...Option) (*Middleware, func() []map[string]any) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	records := func() []map[string]any {
+		var recs []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var rec map[string]any
+			err := json.Unmarshal([]byte(line), &rec)
+			if err != nil {
+				t.Fatalf("parse log record %q: %v", line, err)
+			}
+			recs = append(recs, rec)
+		}
+		return recs
+	}
+	return New(inner, logger, opts...), records
+}
+
+func TestLogLevels(t *testing.T) {
+	ctx := context.Background()
+	m, records := newTestMiddleware(t, &mock.Mock{
+		DeleteTaskFunc: func(ctx context.Context, taskId int) error {
+			return storage.ErrNotFound
+		},
+	})
+
+	_, err := m.TaskById(ctx, 1)
+	if err != nil {
+		t.Fatalf("TaskById() error = %v", err)
+	}
+	err = m.DeleteTask(ctx, 2)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("DeleteTask() error = %v, want ErrNotFound", err)
+	}
+
+	recs := records()
+	if len(recs) != 2 {
+		t.Fatalf("got %d log records, want 2", len(recs))
+	}
+	if recs[0]["level"] != "DEBUG" || recs[0]["method"] != "TaskById" || recs[0]["taskId"] != 1.0 {
+		t.Errorf("success record = %v, want DEBUG TaskById with taskId", recs[0])
+	}
+	if recs[1]["level"] != "ERROR" || recs[1]["method"] != "DeleteTask" || recs[1]["error"] != storage.ErrNotFound.Error() {
+		t.Errorf("failure record = %v, want ERROR DeleteTask with error", recs[1])
+	}
+	if _, ok := recs[0]["duration"]; !ok {
+		t.Errorf("record %v has no duration", recs[0])
+	}
+}
+
+func TestContentLogging(t *testing.T) {
+	task := storage.Task{Title: "title", Content: "secret"}
+	for _, enabled := range []bool{false, true} {
+		m, records := newTestMiddleware(t, &mock.Mock{}, WithContentLogging(enabled))
+		_, err := m.AddTask(context.Background(), task)
+		if err != nil {
+			t.Fatalf("AddTask() error = %v", err)
+		}
+
+		logged := records()[0]["task"].(map[string]any)
+		if logged["title"] != "title" {
+			t.Errorf("logged task = %v, want title", logged)
+		}
+		if _, ok := logged["content"]; ok != enabled {
+			t.Errorf("content logging %v: logged task = %v", enabled, logged)
+		}
+	}
+}
+
+// Содержимое событий в журнал не попадает, только их число и типы.
+func TestAppendEventsLogging(t *testing.T) {
+	m, records := newTestMiddleware(t, &mock.Mock{})
+	events := []storage.Event{
+		{EventType: "created", Payload: []byte(`"secret"`)},
+		{EventType: "renamed", Payload: []byte(`"secret"`)},
+		{EventType: "created", Payload: []byte(`"secret"`)},
+	}
+	err := m.AppendEvents(context.Background(), "task-1", 0, events)
+	if err != nil {
+		t.Fatalf("AppendEvents() error = %v", err)
+	}
+
+	rec := records()[0]
+	if _, ok := rec["events"]; ok {
+		t.Errorf("record %v contains events", rec)
+	}
+	types, _ := json.Marshal(rec["types"])
+	if rec["count"] != 3.0 || string(types) != `["created","renamed"]` {
+		t.Errorf("record = %v, want count 3 and types [created renamed]", rec)
+	}
+}
+
+func TestActivity(t *testing.T) {
+	ctx := context.Background()
+	var recorded []storage.ActivityEvent
+	inner := &mock.Mock{
+		AddTaskFunc: func(ctx context.Context, t storage.Task) (int, error) {
+			return 5, nil
+		},
+		RecordActivityFunc: func(ctx context.Context, e storage.ActivityEvent) error {
+			recorded = append(recorded, e)
+			return nil
+		},
+	}
+
+	// без WithActivity события не записываются
+	m, _ := newTestMiddleware(t, inner)
+	m.AddTask(ctx, storage.Task{})
+	if len(recorded) != 0 {
+		t.Fatalf("recorded %+v without WithActivity", recorded)
+	}
+
+	m, _ = newTestMiddleware(t, inner, WithActivity(true))
+	m.AddTask(ctx, storage.Task{})
+	m.TaskById(ctx, 5)
+	inner.UpdateTaskFunc = func(ctx context.Context, t storage.Task) error {
+		return storage.ErrVersionConflict
+	}
+	m.UpdateTask(ctx, storage.Task{ID: 5})
+	if len(recorded) != 1 || recorded[0].Kind != "AddTask" || recorded[0].TaskID != 5 {
+		t.Errorf("recorded %+v, want only AddTask of task 5", recorded)
+	}
+}
+
+// Ошибка записи события журналируется и не возвращается.
+func TestActivityError(t *testing.T) {
+	m, records := newTestMiddleware(t, &mock.Mock{
+		RecordActivityFunc: func(ctx context.Context, e storage.ActivityEvent) error {
+			return errors.New("disk full")
+		},
+	}, WithActivity(true))
+
+	err := m.DeleteTask(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	recs := records()
+	if len(recs) != 2 || recs[1]["level"] != "WARN" || recs[1]["method"] != "DeleteTask" || recs[1]["error"] != "disk full" {
+		t.Errorf("log records = %v, want WARN about unrecorded DeleteTask activity", recs)
+	}
+}
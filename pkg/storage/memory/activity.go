@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+	"sort"
+	"time"
+)
+
+// RecordActivity добавляет событие в ленту активности.
+// Если время события не задано, используется текущее.
+func (s *Storage) RecordActivity(ctx context.Context, e storage.ActivityEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e.ID = len(s.activity) + 1
+	if e.OccurredAt == 0 {
+		e.OccurredAt = time.Now().Unix()
+	}
+	s.activity = append(s.activity, e)
+	return nil
+}
+
+// ActivityFeed возвращает не более limit последних событий задачи,
+// начиная с самых новых.
+func (s *Storage) ActivityFeed(ctx context.Context, taskID int, limit int) ([]storage.ActivityEvent, error) {
+	return s.activityFeed(limit, func(e storage.ActivityEvent) bool { return e.TaskID == taskID })
+}
+
+// GlobalActivityFeed возвращает не более limit последних событий
+// по всем задачам, начиная с самых новых.
+func (s *Storage) GlobalActivityFeed(ctx context.Context, limit int) ([]storage.ActivityEvent, error) {
+	return s.activityFeed(limit, func(storage.ActivityEvent) bool { return true })
+}
+
+// activityFeed возвращает не более limit последних событий,
+// удовлетворяющих условию.
+func (s *Storage) activityFeed(limit int, match func(e storage.ActivityEvent) bool) ([]storage.ActivityEvent, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("%w: limit must be positive", storage.ErrInvalidArgument)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var events []storage.ActivityEvent
+	for _, e := range s.activity {
+		if match(e) {
+			events = append(events, e)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].OccurredAt != events[j].OccurredAt {
+			return events[i].OccurredAt > events[j].OccurredAt
+		}
+		return events[i].ID > events[j].ID
+	})
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+	"sort"
+	"time"
+)
+
+// apiKey - хранимый ключ API с хешем секрета.
+type apiKey struct {
+	storage.APIKey
+	hash    []byte
+	revoked bool
+}
+
+// CreateAPIKey создаёт ключ API пользователя k.UserID и возвращает его.
+// Ключ возвращается только здесь, хранится его хеш.
+func (s *Storage) CreateAPIKey(ctx context.Context, k storage.APIKey) (string, error) {
+	// хеширование медленное, поэтому выполняется без блокировки
+	raw, prefix, hash, err := storage.GenerateAPIKey()
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[k.UserID]; !ok {
+		return "", fmt.Errorf("%w: user %d", storage.ErrNotFound, k.UserID)
+	}
+	s.lastAPIKeyID++
+	k.ID = s.lastAPIKeyID
+	k.Key = prefix
+	k.CreatedAt = time.Now().Unix()
+	k.LastUsedAt = nil
+	s.apiKeys[k.ID] = apiKey{APIKey: k, hash: hash}
+	return raw, nil
+}
+
+// APIKeyByKey проверяет ключ API и возвращает его описание,
+// отмечая время использования. Для неизвестного, отозванного
+// или истёкшего ключа возвращает ErrNotFound.
+func (s *Storage) APIKeyByKey(ctx context.Context, raw string) (*storage.APIKey, error) {
+	prefix, secret, err := storage.ParseAPIKey(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().Unix()
+	for id, k := range s.apiKeys {
+		if k.Key != prefix || k.revoked || (k.ExpiresAt != nil && *k.ExpiresAt <= now) {
+			continue
+		}
+		err = storage.CheckAPIKeySecret(k.hash, secret)
+		if err != nil {
+			return nil, err
+		}
+		k.LastUsedAt = &now
+		s.apiKeys[id] = k
+		res := k.APIKey
+		return &res, nil
+	}
+	return nil, storage.ErrNotFound
+}
+
+// RevokeAPIKey отзывает ключ API.
+func (s *Storage) RevokeAPIKey(ctx context.Context, keyID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k, ok := s.apiKeys[keyID]
+	if !ok || k.revoked {
+		return storage.ErrNotFound
+	}
+	k.revoked = true
+	s.apiKeys[keyID] = k
+	return nil
+}
+
+// APIKeysByUser возвращает неотозванные ключи API пользователя.
+func (s *Storage) APIKeysByUser(ctx context.Context, userID int) ([]storage.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var keys []storage.APIKey
+	for _, k := range s.apiKeys {
+		if k.UserID == userID && !k.revoked {
+			keys = append(keys, k.APIKey)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys, nil
+}
@@ -0,0 +1,207 @@
+package memory
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"sort"
+)
+
+// ArchiveTask переносит задачу в архив.
+// Связанные записи задачи переносятся вместе с ней, как в postgres.Storage.
+func (s *Storage) ArchiveTask(ctx context.Context, taskID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tasks[taskID]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	s.archive[taskID] = t
+	s.archiveRelations[taskID] = s.detachTask(taskID)
+	return nil
+}
+
+// ArchivedTasks возвращает задачи из архива.
+func (s *Storage) ArchivedTasks(ctx context.Context) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var tasks []storage.Task
+	for _, t := range s.archive {
+		tasks = append(tasks, t)
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	return tasks, nil
+}
+
+// UnarchiveTask возвращает задачу из архива с прежним ID
+// вместе со связанными записями.
+func (s *Storage) UnarchiveTask(ctx context.Context, taskID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.archive[taskID]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	s.tasks[taskID] = t
+	s.attachTask(taskID, s.archiveRelations[taskID])
+	delete(s.archive, taskID)
+	delete(s.archiveRelations, taskID)
+	return nil
+}
+
+// relations содержит записи, связанные с задачей в архиве.
+// После создания не изменяется.
+type relations struct {
+	labels       []int
+	comments     []storage.Comment
+	timeEntries  []storage.TimeEntry
+	checklist    []storage.ChecklistItem
+	dependencies []storage.TaskLink
+	taskLinks    []storage.TaskLinkRecord
+	recurrence   *storage.RecurrenceRule
+	sprints      []int
+	watchers     []int
+	auditLog     []storage.AuditEntry
+	assignments  []storage.AssignmentEvent
+}
+
+// detachTask удаляет задачу вместе с метками и связанными записями,
+// аналогично ON DELETE CASCADE в postgres, и возвращает удалённые записи.
+// Вызывается под блокировкой.
+func (s *Storage) detachTask(taskID int) relations {
+	var rel relations
+	delete(s.tasks, taskID)
+	for id := range s.taskLabels[taskID] {
+		rel.labels = append(rel.labels, id)
+	}
+	delete(s.taskLabels, taskID)
+	if r, ok := s.recurrences[taskID]; ok {
+		rel.recurrence = &r
+		delete(s.recurrences, taskID)
+	}
+	for id := range s.watchers[taskID] {
+		rel.watchers = append(rel.watchers, id)
+	}
+	delete(s.watchers, taskID)
+	for sprintID, set := range s.sprintTasks {
+		if set[taskID] {
+			rel.sprints = append(rel.sprints, sprintID)
+			delete(set, taskID)
+		}
+	}
+	for id, c := range s.comments {
+		if c.TaskID == taskID {
+			rel.comments = append(rel.comments, c)
+			delete(s.comments, id)
+		}
+	}
+	for id, e := range s.timeEntries {
+		if e.TaskID == taskID {
+			rel.timeEntries = append(rel.timeEntries, e)
+			delete(s.timeEntries, id)
+		}
+	}
+	for id, item := range s.checklist {
+		if item.TaskID == taskID {
+			rel.checklist = append(rel.checklist, item)
+			delete(s.checklist, id)
+		}
+	}
+	for link := range s.dependencies {
+		if link.TaskID == taskID || link.DependsOnID == taskID {
+			rel.dependencies = append(rel.dependencies, link)
+			delete(s.dependencies, link)
+		}
+	}
+	for id, l := range s.taskLinks {
+		if l.TaskID == taskID || l.LinkedTaskID == taskID {
+			rel.taskLinks = append(rel.taskLinks, l)
+			delete(s.taskLinks, id)
+		}
+	}
+	s.auditLog = slices.DeleteFunc(s.auditLog, func(e storage.AuditEntry) bool {
+		if e.TaskID == taskID {
+			rel.auditLog = append(rel.auditLog, e)
+			return true
+		}
+		return false
+	})
+	s.assignments = slices.DeleteFunc(s.assignments, func(e storage.AssignmentEvent) bool {
+		if e.TaskID == taskID {
+			rel.assignments = append(rel.assignments, e)
+			return true
+		}
+		return false
+	})
+	return rel
+}
+
+// attachTask возвращает связанные записи задачи taskID. Записи, ссылающиеся
+// на удалённые за это время метки, спринты, пользователей или задачи,
+// не восстанавливаются, как в postgres.Storage. Вызывается под блокировкой.
+func (s *Storage) attachTask(taskID int, rel relations) {
+	for _, id := range rel.labels {
+		if _, ok := s.labels[id]; !ok {
+			continue
+		}
+		if s.taskLabels[taskID] == nil {
+			s.taskLabels[taskID] = make(map[int]bool)
+		}
+		s.taskLabels[taskID][id] = true
+	}
+	if rel.recurrence != nil {
+		s.recurrences[taskID] = *rel.recurrence
+	}
+	for _, id := range rel.watchers {
+		if _, ok := s.users[id]; !ok {
+			continue
+		}
+		if s.watchers[taskID] == nil {
+			s.watchers[taskID] = make(map[int]bool)
+		}
+		s.watchers[taskID][id] = true
+	}
+	for _, id := range rel.sprints {
+		if _, ok := s.sprints[id]; !ok {
+			continue
+		}
+		if s.sprintTasks[id] == nil {
+			s.sprintTasks[id] = make(map[int]bool)
+		}
+		s.sprintTasks[id][taskID] = true
+	}
+	for _, c := range rel.comments {
+		s.comments[c.ID] = c
+	}
+	for _, e := range rel.timeEntries {
+		s.timeEntries[e.ID] = e
+	}
+	for _, item := range rel.checklist {
+		s.checklist[item.ID] = item
+	}
+	for _, link := range rel.dependencies {
+		if s.taskExists(link.TaskID) && s.taskExists(link.DependsOnID) {
+			s.dependencies[link] = true
+		}
+	}
+	for _, l := range rel.taskLinks {
+		if s.taskExists(l.TaskID) && s.taskExists(l.LinkedTaskID) {
+			s.taskLinks[l.ID] = l
+		}
+	}
+	// журналы хранятся в порядке добавления, то есть по возрастанию ID
+	s.auditLog = append(s.auditLog, rel.auditLog...)
+	slices.SortStableFunc(s.auditLog, func(a, b storage.AuditEntry) int { return a.ID - b.ID })
+	s.assignments = append(s.assignments, rel.assignments...)
+	slices.SortStableFunc(s.assignments, func(a, b storage.AssignmentEvent) int { return a.ID - b.ID })
+}
+
+// taskExists сообщает, есть ли задача среди актуальных.
+// Вызывается под блокировкой.
+func (s *Storage) taskExists(taskID int) bool {
+	_, ok := s.tasks[taskID]
+	return ok
+}
@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"time"
+)
+
+// AssignmentHistory возвращает историю назначения задачи в порядке изменений.
+func (s *Storage) AssignmentHistory(ctx context.Context, taskID int) ([]storage.AssignmentEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var events []storage.AssignmentEvent
+	for _, e := range s.assignments {
+		if e.TaskID == taskID {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+// RecordAssignment добавляет запись в историю назначения.
+// Если время изменения не задано, используется текущее.
+func (s *Storage) RecordAssignment(ctx context.Context, e storage.AssignmentEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recordAssignment(e)
+	return nil
+}
+
+// recordAssignment добавляет запись в историю назначения.
+// Вызывается под блокировкой.
+func (s *Storage) recordAssignment(e storage.AssignmentEvent) {
+	s.lastAssignmentID++
+	e.ID = s.lastAssignmentID
+	if e.ChangedAt == 0 {
+		e.ChangedAt = time.Now().Unix()
+	}
+	s.assignments = append(s.assignments, e)
+}
+
+// trackAssignment записывает смену исполнителя задачи,
+// как это делает триггер в postgres. Вызывается под блокировкой.
+func (s *Storage) trackAssignment(old, t storage.Task) {
+	if old.AssignedID == t.AssignedID {
+		return
+	}
+	from, to := old.AssignedID, t.AssignedID
+	s.recordAssignment(storage.AssignmentEvent{
+		TaskID:     t.ID,
+		FromUserID: &from,
+		ToUserID:   &to,
+	})
+}
@@ -0,0 +1,36 @@
+package memory
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"time"
+)
+
+// AuditLog возвращает журнал изменений задачи в порядке записи.
+func (s *Storage) AuditLog(ctx context.Context, taskID int) ([]storage.AuditEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var entries []storage.AuditEntry
+	for _, e := range s.auditLog {
+		if e.TaskID == taskID {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+// RecordChange добавляет запись в журнал изменений.
+// Если время изменения не задано, используется текущее.
+func (s *Storage) RecordChange(ctx context.Context, e storage.AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastAuditID++
+	e.ID = s.lastAuditID
+	if e.ChangedAt == 0 {
+		e.ChangedAt = time.Now().Unix()
+	}
+	s.auditLog = append(s.auditLog, e)
+	return nil
+}
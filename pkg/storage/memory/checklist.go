@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"sort"
+)
+
+// AddChecklistItem добавляет пункт в чек-лист задачи и возвращает его id.
+func (s *Storage) AddChecklistItem(ctx context.Context, item storage.ChecklistItem) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastChecklistItemID++
+	item.ID = s.lastChecklistItemID
+	s.checklist[item.ID] = item
+	return item.ID, nil
+}
+
+// ChecklistByTask возвращает пункты чек-листа задачи в порядке их позиций.
+func (s *Storage) ChecklistByTask(ctx context.Context, taskID int) ([]storage.ChecklistItem, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var items []storage.ChecklistItem
+	for _, item := range s.checklist {
+		if item.TaskID == taskID {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Position != items[j].Position {
+			return items[i].Position < items[j].Position
+		}
+		return items[i].ID < items[j].ID
+	})
+	return items, nil
+}
+
+// UpdateChecklistItem обновляет текст, отметку о выполнении и позицию пункта.
+func (s *Storage) UpdateChecklistItem(ctx context.Context, item storage.ChecklistItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if old, ok := s.checklist[item.ID]; ok {
+		item.TaskID = old.TaskID
+		s.checklist[item.ID] = item
+	}
+	return nil
+}
+
+// DeleteChecklistItem удаляет пункт чек-листа по ID.
+func (s *Storage) DeleteChecklistItem(ctx context.Context, itemID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.checklist, itemID)
+	return nil
+}
+
+// ReorderChecklist задаёт порядок пунктов чек-листа задачи:
+// позиция пункта равна его индексу в orderedIDs.
+func (s *Storage) ReorderChecklist(ctx context.Context, taskID int, orderedIDs []int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for pos, id := range orderedIDs {
+		item, ok := s.checklist[id]
+		if !ok || item.TaskID != taskID {
+			continue
+		}
+		item.Position = pos
+		s.checklist[id] = item
+	}
+	return nil
+}
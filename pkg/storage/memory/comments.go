@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+	"sort"
+	"time"
+)
+
+// AddComment создаёт комментарий к задаче и возвращает его id.
+func (s *Storage) AddComment(ctx context.Context, c storage.Comment) (int, error) {
+	if c.TaskID == 0 || c.AuthorID == 0 {
+		return 0, fmt.Errorf("%w: comment task and author are required", storage.ErrInvalidArgument)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastCommentID++
+	c.ID = s.lastCommentID
+	c.CreatedAt = time.Now().Unix()
+	s.comments[c.ID] = c
+	return c.ID, nil
+}
+
+// CommentsByTask возвращает комментарии к задаче в порядке создания.
+func (s *Storage) CommentsByTask(ctx context.Context, taskID int) ([]storage.Comment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var comments []storage.Comment
+	for _, c := range s.comments {
+		if c.TaskID == taskID {
+			comments = append(comments, c)
+		}
+	}
+	sort.Slice(comments, func(i, j int) bool { return comments[i].ID < comments[j].ID })
+	return comments, nil
+}
+
+// UpdateComment обновляет текст комментария.
+func (s *Storage) UpdateComment(ctx context.Context, c storage.Comment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if old, ok := s.comments[c.ID]; ok {
+		old.Body = c.Body
+		s.comments[c.ID] = old
+	}
+	return nil
+}
+
+// DeleteComment удаляет комментарий по ID.
+func (s *Storage) DeleteComment(ctx context.Context, commentID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.comments, commentID)
+	return nil
+}
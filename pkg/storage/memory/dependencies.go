@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+)
+
+// AddDependency отмечает, что задача taskID зависит от задачи dependsOnID.
+func (s *Storage) AddDependency(ctx context.Context, taskID, dependsOnID int) error {
+	if taskID == dependsOnID {
+		return fmt.Errorf("%w: task %d cannot depend on itself", storage.ErrInvalidArgument, taskID)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.dependencies[storage.TaskLink{TaskID: dependsOnID, DependsOnID: taskID}] {
+		return fmt.Errorf("%w: task %d already depends on task %d", storage.ErrInvalidArgument, dependsOnID, taskID)
+	}
+	link := storage.TaskLink{TaskID: taskID, DependsOnID: dependsOnID}
+	if s.dependencies[link] {
+		return storage.ErrConflict
+	}
+	s.dependencies[link] = true
+	return nil
+}
+
+// RemoveDependency удаляет зависимость задачи taskID от задачи dependsOnID.
+func (s *Storage) RemoveDependency(ctx context.Context, taskID, dependsOnID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.dependencies, storage.TaskLink{TaskID: taskID, DependsOnID: dependsOnID})
+	return nil
+}
+
+// DependenciesOf возвращает задачи, от которых зависит задача taskID.
+func (s *Storage) DependenciesOf(ctx context.Context, taskID int) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.selectTasks(func(t storage.Task) bool {
+		return s.dependencies[storage.TaskLink{TaskID: taskID, DependsOnID: t.ID}]
+	}), nil
+}
+
+// BlockedBy возвращает задачи, заблокированные задачей taskID,
+// то есть задачи, которые от неё зависят.
+func (s *Storage) BlockedBy(ctx context.Context, taskID int) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.selectTasks(func(t storage.Task) bool {
+		return s.dependencies[storage.TaskLink{TaskID: t.ID, DependsOnID: taskID}]
+	}), nil
+}
@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"time"
+)
+
+// AppendEvents добавляет события в конец потока streamID.
+// expectedSeq - номер последнего события потока, известный вызывающему
+// коду (0 для нового потока). Если поток успел измениться, события
+// не добавляются и возвращается storage.ErrVersionConflict.
+func (s *Storage) AppendEvents(ctx context.Context, streamID string, expectedSeq int64, events []storage.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stream := s.events[streamID]
+	if int64(len(stream)) != expectedSeq {
+		return storage.ErrVersionConflict
+	}
+	now := time.Now().Unix()
+	for _, e := range events {
+		s.lastEventID++
+		e.ID = s.lastEventID
+		e.StreamID = streamID
+		e.SequenceNo = int64(len(stream)) + 1
+		if e.OccurredAt == 0 {
+			e.OccurredAt = now
+		}
+		stream = append(stream, e)
+	}
+	s.events[streamID] = stream
+	return nil
+}
+
+// LoadEvents возвращает события потока streamID, начиная с номера fromSeq,
+// в порядке номеров.
+func (s *Storage) LoadEvents(ctx context.Context, streamID string, fromSeq int64) ([]storage.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var events []storage.Event
+	for _, e := range s.events[streamID] {
+		if e.SequenceNo >= fromSeq {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
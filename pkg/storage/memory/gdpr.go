@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"sort"
+)
+
+// Имя, которое получает пользователь после EraseUser.
+const erasedUserName = "Deleted User"
+
+// ExportUserData возвращает персональные данные пользователя:
+// его задачи (как автора и как исполнителя), комментарии и учёт времени.
+func (s *Storage) ExportUserData(ctx context.Context, userID int) (*storage.UserDataExport, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	u, ok := s.users[userID]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	export := storage.UserDataExport{
+		User:          u,
+		AuthoredTasks: s.selectTasks(func(t storage.Task) bool { return t.AuthorID == userID }),
+		AssignedTasks: s.selectTasks(func(t storage.Task) bool { return t.AssignedID == userID }),
+	}
+	for _, c := range s.comments {
+		if c.AuthorID == userID {
+			export.Comments = append(export.Comments, c)
+		}
+	}
+	sort.Slice(export.Comments, func(i, j int) bool { return export.Comments[i].ID < export.Comments[j].ID })
+	for _, e := range s.timeEntries {
+		if e.UserID == userID {
+			export.TimeEntries = append(export.TimeEntries, e)
+		}
+	}
+	sort.Slice(export.TimeEntries, func(i, j int) bool { return export.TimeEntries[i].ID < export.TimeEntries[j].ID })
+	return &export, nil
+}
+
+// EraseUser обезличивает пользователя: заменяет имя на "Deleted User",
+// передаёт его задачи пользователю по умолчанию (ID 0), удаляет его
+// комментарии, учёт времени, подписки на задачи, настройки уведомлений
+// и ключи API.
+func (s *Storage) EraseUser(ctx context.Context, userID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[userID]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	u.Name = erasedUserName
+	s.users[userID] = u
+
+	for _, t := range s.tasks {
+		if t.AuthorID != userID && t.AssignedID != userID {
+			continue
+		}
+		old := t
+		if t.AuthorID == userID {
+			t.AuthorID = 0
+		}
+		if t.AssignedID == userID {
+			t.AssignedID = 0
+		}
+		s.replaceTask(old, t)
+	}
+	for id, c := range s.comments {
+		if c.AuthorID == userID {
+			delete(s.comments, id)
+		}
+	}
+	for id, e := range s.timeEntries {
+		if e.UserID == userID {
+			delete(s.timeEntries, id)
+		}
+	}
+	for _, set := range s.watchers {
+		delete(set, userID)
+	}
+	delete(s.notifyPrefs, userID)
+	for id, k := range s.apiKeys {
+		if k.UserID == userID {
+			delete(s.apiKeys, id)
+		}
+	}
+	return nil
+}
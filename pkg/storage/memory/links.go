@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+	"sort"
+)
+
+// AddTaskLink связывает задачу l.TaskID с задачей l.LinkedTaskID
+// и возвращает ID связи. Обратная связь не создаётся.
+func (s *Storage) AddTaskLink(ctx context.Context, l storage.TaskLinkRecord) (int, error) {
+	switch l.Type {
+	case storage.LinkRelated, storage.LinkDuplicates, storage.LinkBlocks:
+	default:
+		return 0, fmt.Errorf("%w: unknown link type %q", storage.ErrInvalidArgument, l.Type)
+	}
+	if l.TaskID == l.LinkedTaskID {
+		return 0, fmt.Errorf("%w: task cannot be linked to itself", storage.ErrInvalidArgument)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, old := range s.taskLinks {
+		if old.TaskID == l.TaskID && old.LinkedTaskID == l.LinkedTaskID && old.Type == l.Type {
+			return 0, storage.ErrConflict
+		}
+	}
+	s.lastTaskLinkID++
+	l.ID = s.lastTaskLinkID
+	s.taskLinks[l.ID] = l
+	return l.ID, nil
+}
+
+// TaskLinks возвращает связи задачи с другими задачами.
+func (s *Storage) TaskLinks(ctx context.Context, taskID int) ([]storage.TaskLinkRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var links []storage.TaskLinkRecord
+	for _, l := range s.taskLinks {
+		if l.TaskID == taskID {
+			links = append(links, l)
+		}
+	}
+	sort.Slice(links, func(i, j int) bool { return links[i].ID < links[j].ID })
+	return links, nil
+}
+
+// DeleteTaskLink удаляет связь по ID.
+func (s *Storage) DeleteTaskLink(ctx context.Context, linkID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.taskLinks[linkID]; !ok {
+		return storage.ErrNotFound
+	}
+	delete(s.taskLinks, linkID)
+	return nil
+}
@@ -0,0 +1,1260 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Хранилище данных в памяти процесса.
+// Предназначено для тестов и локальной разработки.
+type Storage struct {
+	mu sync.RWMutex
+	data
+}
+
+// data - содержимое хранилища. Выделено в отдельную структуру,
+// чтобы транзакция могла работать с копией данных.
+type data struct {
+	tasks       map[int]storage.Task
+	users       map[int]storage.User
+	labels      map[int]storage.Label
+	taskLabels  map[int]map[int]bool // ID задачи -> множество ID меток
+	comments    map[int]storage.Comment
+	timeEntries map[int]storage.TimeEntry
+	checklist   map[int]storage.ChecklistItem
+	projects    map[int]storage.Project
+	sprints     map[int]storage.Sprint
+	sprintTasks map[int]map[int]bool // ID спринта -> множество ID задач
+	watchers    map[int]map[int]bool // ID задачи -> множество ID наблюдателей
+
+	dependencies     map[storage.TaskLink]bool
+	recurrences      map[int]storage.RecurrenceRule         // ID задачи -> правило
+	archive          map[int]storage.Task                   // архивные задачи
+	archiveRelations map[int]relations                      // ID архивной задачи -> её связанные записи
+	notifyPrefs      map[int]storage.NotificationPreference // ID пользователя -> настройки
+	apiKeys          map[int]apiKey
+	taskLinks        map[int]storage.TaskLinkRecord
+	webhooks         map[int]storage.Webhook
+	lockedTasks      map[int]bool // ID заблокированных задач (см. LockTask)
+
+	auditLog    []storage.AuditEntry      // записи в порядке добавления
+	assignments []storage.AssignmentEvent // записи в порядке добавления
+	activity    []storage.ActivityEvent   // записи в порядке добавления
+
+	events      map[string][]storage.Event // ID потока -> события по порядку
+	lastEventID int64
+
+	outbox       []storage.OutboxMessage // сообщения в порядке записи
+	lastOutboxID int64
+
+	// последние выданные ID, аналог последовательностей SERIAL
+	lastTaskID          int
+	lastUserID          int
+	lastLabelID         int
+	lastCommentID       int
+	lastTimeEntryID     int
+	lastChecklistItemID int
+	lastProjectID       int
+	lastSprintID        int
+	lastAPIKeyID        int
+	lastTaskLinkID      int
+	lastWebhookID       int
+	lastAuditID         int
+	lastAssignmentID    int
+}
+
+// Конструктор, создаёт пустое хранилище.
+func New() *Storage {
+	s := Storage{}
+	s.reset()
+	return &s
+}
+
+// Reset удаляет все данные из хранилища.
+func (s *Storage) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reset()
+}
+
+// clone возвращает независимую копию данных.
+// Вызывается под блокировкой.
+func (d *data) clone() data {
+	c := *d
+	c.tasks = maps.Clone(d.tasks)
+	c.users = maps.Clone(d.users)
+	c.labels = maps.Clone(d.labels)
+	c.taskLabels = cloneSets(d.taskLabels)
+	c.comments = maps.Clone(d.comments)
+	c.timeEntries = maps.Clone(d.timeEntries)
+	c.checklist = maps.Clone(d.checklist)
+	c.projects = maps.Clone(d.projects)
+	c.sprints = maps.Clone(d.sprints)
+	c.sprintTasks = cloneSets(d.sprintTasks)
+	c.watchers = cloneSets(d.watchers)
+	c.dependencies = maps.Clone(d.dependencies)
+	c.recurrences = maps.Clone(d.recurrences)
+	c.archive = maps.Clone(d.archive)
+	c.archiveRelations = maps.Clone(d.archiveRelations)
+	c.notifyPrefs = maps.Clone(d.notifyPrefs)
+	c.apiKeys = maps.Clone(d.apiKeys)
+	c.taskLinks = maps.Clone(d.taskLinks)
+	c.webhooks = maps.Clone(d.webhooks)
+	c.lockedTasks = maps.Clone(d.lockedTasks)
+	c.auditLog = slices.Clone(d.auditLog)
+	c.assignments = slices.Clone(d.assignments)
+	c.activity = slices.Clone(d.activity)
+	c.events = make(map[string][]storage.Event, len(d.events))
+	for id, stream := range d.events {
+		c.events[id] = slices.Clone(stream)
+	}
+	c.outbox = slices.Clone(d.outbox)
+	return c
+}
+
+// cloneSets возвращает копию отображения ID в множества ID.
+func cloneSets(m map[int]map[int]bool) map[int]map[int]bool {
+	c := make(map[int]map[int]bool, len(m))
+	for id, set := range m {
+		c[id] = maps.Clone(set)
+	}
+	return c
+}
+
+func (s *Storage) reset() {
+	s.tasks = make(map[int]storage.Task)
+	s.users = make(map[int]storage.User)
+	s.labels = make(map[int]storage.Label)
+	s.taskLabels = make(map[int]map[int]bool)
+	s.comments = make(map[int]storage.Comment)
+	s.timeEntries = make(map[int]storage.TimeEntry)
+	s.checklist = make(map[int]storage.ChecklistItem)
+	s.projects = make(map[int]storage.Project)
+	s.sprints = make(map[int]storage.Sprint)
+	s.sprintTasks = make(map[int]map[int]bool)
+	s.watchers = make(map[int]map[int]bool)
+	s.dependencies = make(map[storage.TaskLink]bool)
+	s.recurrences = make(map[int]storage.RecurrenceRule)
+	s.archive = make(map[int]storage.Task)
+	s.archiveRelations = make(map[int]relations)
+	s.notifyPrefs = make(map[int]storage.NotificationPreference)
+	s.apiKeys = make(map[int]apiKey)
+	s.taskLinks = make(map[int]storage.TaskLinkRecord)
+	s.webhooks = make(map[int]storage.Webhook)
+	s.lockedTasks = make(map[int]bool)
+	s.auditLog = nil
+	s.assignments = nil
+	s.activity = nil
+	s.events = make(map[string][]storage.Event)
+	s.lastEventID = 0
+	s.outbox = nil
+	s.lastOutboxID = 0
+	s.lastTaskID = 0
+	s.lastUserID = 0
+	s.lastLabelID = 0
+	s.lastCommentID = 0
+	s.lastTimeEntryID = 0
+	s.lastChecklistItemID = 0
+	s.lastProjectID = 0
+	s.lastSprintID = 0
+	s.lastAPIKeyID = 0
+	s.lastTaskLinkID = 0
+	s.lastWebhookID = 0
+	s.lastAuditID = 0
+	s.lastAssignmentID = 0
+}
+
+// selectTasks возвращает неудалённые задачи, удовлетворяющие условию,
+// упорядоченные по ID. Вызывается под блокировкой.
+func (s *Storage) selectTasks(match func(t storage.Task) bool) []storage.Task {
+	var tasks []storage.Task
+	for _, t := range s.tasks {
+		if t.DeletedAt.Valid || !match(t) {
+			continue
+		}
+		tasks = append(tasks, t)
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	return tasks
+}
+
+// countTasks возвращает количество неудалённых задач, удовлетворяющих условию.
+// Вызывается под блокировкой.
+func (s *Storage) countTasks(match func(t storage.Task) bool) int {
+	n := 0
+	for _, t := range s.tasks {
+		if !t.DeletedAt.Valid && match(t) {
+			n++
+		}
+	}
+	return n
+}
+
+func all(storage.Task) bool { return true }
+
+// Tasks возвращает список задач.
+//
+// Deprecated: используйте TasksList для постраничной выборки.
+func (s *Storage) Tasks(ctx context.Context) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.selectTasks(all), nil
+}
+
+// TasksIter вызывает fn для каждой неудалённой задачи в порядке ID.
+// Если fn возвращает ошибку, перебор прекращается и ошибка возвращается.
+// Перебирается снимок задач, сделанный под блокировкой,
+// поэтому fn может обращаться к хранилищу.
+func (s *Storage) TasksIter(ctx context.Context, fn func(t storage.Task) error) error {
+	s.mu.RLock()
+	tasks := s.selectTasks(all)
+	s.mu.RUnlock()
+
+	for _, t := range tasks {
+		err := fn(t)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TasksIncludingDeleted возвращает список задач вместе с удалёнными.
+func (s *Storage) TasksIncludingDeleted(ctx context.Context) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var tasks []storage.Task
+	for _, t := range s.tasks {
+		tasks = append(tasks, t)
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	return tasks, nil
+}
+
+// orderFuncs сопоставляет столбцу сортировки функцию сравнения задач.
+var orderFuncs = map[string]func(a, b storage.Task) bool{
+	"id":          func(a, b storage.Task) bool { return a.ID < b.ID },
+	"opened":      func(a, b storage.Task) bool { return a.Opened < b.Opened },
+	"closed":      func(a, b storage.Task) bool { return a.Closed < b.Closed },
+	"author_id":   func(a, b storage.Task) bool { return a.AuthorID < b.AuthorID },
+	"assigned_id": func(a, b storage.Task) bool { return a.AssignedID < b.AssignedID },
+	"title":       func(a, b storage.Task) bool { return a.Title < b.Title },
+	"priority":    func(a, b storage.Task) bool { return a.Priority < b.Priority },
+}
+
+// sortColumns сопоставляет полю сортировки столбец из orderFuncs.
+var sortColumns = map[storage.SortField]string{
+	storage.SortByID:       "id",
+	storage.SortByOpened:   "opened",
+	storage.SortByPriority: "priority",
+	storage.SortByTitle:    "title",
+}
+
+// TasksList возвращает страницу задач в соответствии с параметрами выборки.
+func (s *Storage) TasksList(ctx context.Context, opts storage.ListOptions) ([]storage.Task, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	orderBy := opts.OrderBy
+	if orderBy == "" {
+		orderBy = "id"
+	}
+	if opts.Sort.Field != "" {
+		col, ok := sortColumns[opts.Sort.Field]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown sort field %q", storage.ErrInvalidArgument, opts.Sort.Field)
+		}
+		orderBy = col
+	}
+	less, ok := orderFuncs[orderBy]
+	if !ok {
+		return nil, fmt.Errorf("storage: unknown order column %q", orderBy)
+	}
+	if opts.Sort.Dir == storage.SortDesc {
+		asc := less
+		less = func(a, b storage.Task) bool { return asc(b, a) }
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// задачи уже упорядочены по ID, поэтому устойчивая сортировка
+	// сохраняет его как вторичный порядок
+	tasks := s.selectTasks(all)
+	sort.SliceStable(tasks, func(i, j int) bool { return less(tasks[i], tasks[j]) })
+	return page(tasks, opts.Offset, opts.Limit), nil
+}
+
+// page возвращает не более limit элементов, начиная с offset.
+func page(tasks []storage.Task, offset, limit int) []storage.Task {
+	if offset >= len(tasks) {
+		return nil
+	}
+	tasks = tasks[offset:]
+	if limit < len(tasks) {
+		tasks = tasks[:limit]
+	}
+	return tasks
+}
+
+// TotalCount возвращает общее количество задач.
+func (s *Storage) TotalCount(ctx context.Context) (int, error) {
+	return s.TaskCount(ctx)
+}
+
+// TasksAfter возвращает не более limit задач с ID больше afterID.
+func (s *Storage) TasksAfter(ctx context.Context, afterID int, limit int) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tasks := s.selectTasks(func(t storage.Task) bool { return t.ID > afterID })
+	return page(tasks, 0, limit), nil
+}
+
+// TaskCount возвращает количество задач.
+func (s *Storage) TaskCount(ctx context.Context) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.countTasks(all), nil
+}
+
+// TaskCountByAuthor возвращает количество задач автора.
+func (s *Storage) TaskCountByAuthor(ctx context.Context, authorID int) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.countTasks(func(t storage.Task) bool { return t.AuthorID == authorID }), nil
+}
+
+// TaskCountByLabel возвращает количество задач с меткой.
+func (s *Storage) TaskCountByLabel(ctx context.Context, labelID int) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.countTasks(func(t storage.Task) bool { return s.taskLabels[t.ID][labelID] }), nil
+}
+
+// TaskById возвращает задачу по её ID.
+func (s *Storage) TaskById(ctx context.Context, taskId int) (*storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.tasks[taskId]
+	if !ok || t.DeletedAt.Valid {
+		return nil, storage.ErrNotFound
+	}
+	return &t, nil
+}
+
+// TasksByAuthor возвращает слайс задач по ID автора.
+func (s *Storage) TasksByAuthor(ctx context.Context, authorId int) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.selectTasks(func(t storage.Task) bool { return t.AuthorID == authorId }), nil
+}
+
+// TasksByAssignee возвращает слайс задач по ID исполнителя.
+func (s *Storage) TasksByAssignee(ctx context.Context, assigneeID int) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.selectTasks(func(t storage.Task) bool { return t.AssignedID == assigneeID }), nil
+}
+
+// TasksUnassigned возвращает задачи без исполнителя (assigned_id = 0).
+func (s *Storage) TasksUnassigned(ctx context.Context) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.selectTasks(func(t storage.Task) bool { return t.AssignedID == 0 }), nil
+}
+
+// TasksByLabel возвращает слайс задач по ID метки.
+func (s *Storage) TasksByLabel(ctx context.Context, labelId int) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.selectTasks(func(t storage.Task) bool { return s.taskLabels[t.ID][labelId] }), nil
+}
+
+// TasksByLabels возвращает задачи, имеющие хотя бы одну из меток
+// (storage.LabelFilterAny) или все метки (storage.LabelFilterAll).
+func (s *Storage) TasksByLabels(ctx context.Context, labelIDs []int, mode storage.LabelFilterMode) ([]storage.Task, error) {
+	if len(labelIDs) == 0 {
+		return nil, fmt.Errorf("%w: no labels given", storage.ErrInvalidArgument)
+	}
+
+	var match func(t storage.Task) bool
+	switch mode {
+	case storage.LabelFilterAny:
+		match = func(t storage.Task) bool {
+			return slices.ContainsFunc(labelIDs, func(id int) bool { return s.taskLabels[t.ID][id] })
+		}
+	case storage.LabelFilterAll:
+		match = func(t storage.Task) bool {
+			for _, id := range labelIDs {
+				if !s.taskLabels[t.ID][id] {
+					return false
+				}
+			}
+			return true
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown label filter mode %d", storage.ErrInvalidArgument, mode)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.selectTasks(match), nil
+}
+
+// TasksWithLabels возвращает список задач вместе с их метками.
+func (s *Storage) TasksWithLabels(ctx context.Context) ([]storage.TaskWithLabels, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var tasks []storage.TaskWithLabels
+	for _, t := range s.selectTasks(all) {
+		twl := storage.TaskWithLabels{Task: t}
+		for labelID := range s.taskLabels[t.ID] {
+			if l, ok := s.labels[labelID]; ok {
+				twl.Labels = append(twl.Labels, l)
+			}
+		}
+		sort.Slice(twl.Labels, func(i, j int) bool { return twl.Labels[i].ID < twl.Labels[j].ID })
+		tasks = append(tasks, twl)
+	}
+	return tasks, nil
+}
+
+// TasksWithUsers возвращает список задач вместе с авторами и исполнителями.
+func (s *Storage) TasksWithUsers(ctx context.Context) ([]storage.TaskWithUsers, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var tasks []storage.TaskWithUsers
+	for _, t := range s.selectTasks(all) {
+		tasks = append(tasks, storage.TaskWithUsers{
+			Task:     t,
+			Author:   s.userOrNil(t.AuthorID),
+			Assignee: s.userOrNil(t.AssignedID),
+		})
+	}
+	return tasks, nil
+}
+
+// userOrNil возвращает пользователя по ID или nil, если пользователь
+// не назначен. Вызывается под блокировкой.
+func (s *Storage) userOrNil(id int) *storage.User {
+	u, ok := s.users[id]
+	if id == 0 || !ok {
+		return nil
+	}
+	return &u
+}
+
+// FilterTasks возвращает слайс задач, удовлетворяющих фильтру.
+func (s *Storage) FilterTasks(ctx context.Context, f storage.TaskFilter) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	title := strings.ToLower(f.TitleContains)
+	return s.selectTasks(func(t storage.Task) bool {
+		switch {
+		case f.AuthorID != nil && t.AuthorID != *f.AuthorID,
+			f.AssignedID != nil && t.AssignedID != *f.AssignedID,
+			f.LabelID != nil && !s.taskLabels[t.ID][*f.LabelID],
+			f.OpenedAfter != nil && t.Opened <= *f.OpenedAfter,
+			f.OpenedBefore != nil && t.Opened >= *f.OpenedBefore,
+			f.ClosedAfter != nil && t.Closed <= *f.ClosedAfter,
+			f.ClosedBefore != nil && t.Closed >= *f.ClosedBefore,
+			title != "" && !strings.Contains(strings.ToLower(t.Title), title),
+			f.Priority != nil && t.Priority != *f.Priority:
+			return false
+		}
+		return true
+	}), nil
+}
+
+// TasksByStatus возвращает слайс задач с указанным статусом.
+func (s *Storage) TasksByStatus(ctx context.Context, status storage.Status) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.selectTasks(func(t storage.Task) bool { return t.Status == status }), nil
+}
+
+// TasksByPriority возвращает слайс задач с указанным приоритетом.
+func (s *Storage) TasksByPriority(ctx context.Context, p storage.Priority) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.selectTasks(func(t storage.Task) bool { return t.Priority == p }), nil
+}
+
+// TasksOrderedByPriority возвращает список задач,
+// начиная с задач с наивысшим приоритетом.
+func (s *Storage) TasksOrderedByPriority(ctx context.Context) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tasks := s.selectTasks(all)
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].Priority > tasks[j].Priority })
+	return tasks, nil
+}
+
+// sortByDueAt упорядочивает задачи со сроком выполнения по его наступлению.
+func sortByDueAt(tasks []storage.Task) {
+	sort.SliceStable(tasks, func(i, j int) bool { return *tasks[i].DueAt < *tasks[j].DueAt })
+}
+
+// TasksOverdue возвращает незакрытые задачи, срок выполнения которых
+// истёк к моменту now, в порядке наступления срока.
+func (s *Storage) TasksOverdue(ctx context.Context, now int64) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tasks := s.selectTasks(func(t storage.Task) bool {
+		return t.DueAt != nil && *t.DueAt < now && t.Closed == 0
+	})
+	sortByDueAt(tasks)
+	return tasks, nil
+}
+
+// TasksByTag возвращает задачи с тегом tag.
+func (s *Storage) TasksByTag(ctx context.Context, tag string) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.selectTasks(func(t storage.Task) bool {
+		return slices.Contains(t.Tags, tag)
+	}), nil
+}
+
+// TasksByRank возвращает задачи в порядке возрастания ранга.
+func (s *Storage) TasksByRank(ctx context.Context) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tasks := s.selectTasks(all)
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].Rank < tasks[j].Rank })
+	return tasks, nil
+}
+
+// UpdateTaskRank изменяет ранг задачи.
+func (s *Storage) UpdateTaskRank(ctx context.Context, taskID int, rank float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tasks[taskID]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	t.Rank = rank
+	s.replaceTask(s.tasks[taskID], t)
+	return nil
+}
+
+// AllTags возвращает все теги задач по алфавиту без повторов.
+func (s *Storage) AllTags(ctx context.Context) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var tags []string
+	for _, t := range s.selectTasks(all) {
+		tags = append(tags, t.Tags...)
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags), nil
+}
+
+// StaleTasks возвращает незакрытые задачи, открытые более
+// staleAfterSeconds секунд к моменту now, в порядке открытия.
+func (s *Storage) StaleTasks(ctx context.Context, staleAfterSeconds int64, now int64) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tasks := s.selectTasks(func(t storage.Task) bool {
+		return now-t.Opened > staleAfterSeconds && t.Closed == 0
+	})
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].Opened < tasks[j].Opened })
+	return tasks, nil
+}
+
+// MarkTaskStale отмечает задачу как "зависшую".
+func (s *Storage) MarkTaskStale(ctx context.Context, taskID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tasks[taskID]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	t.Stale = true
+	s.replaceTask(s.tasks[taskID], t)
+	return nil
+}
+
+// TasksDueBetween возвращает задачи со сроком выполнения
+// в интервале [from, to] в порядке наступления срока.
+func (s *Storage) TasksDueBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tasks := s.selectTasks(func(t storage.Task) bool {
+		return t.DueAt != nil && *t.DueAt >= from && *t.DueAt <= to
+	})
+	sortByDueAt(tasks)
+	return tasks, nil
+}
+
+// TasksOpenedBetween возвращает задачи, открытые в интервале [from, to].
+func (s *Storage) TasksOpenedBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.selectTasks(func(t storage.Task) bool {
+		return t.Opened >= from && t.Opened <= to
+	}), nil
+}
+
+// TasksClosedBetween возвращает задачи, закрытые в интервале [from, to].
+func (s *Storage) TasksClosedBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.selectTasks(func(t storage.Task) bool {
+		return t.Closed >= from && t.Closed <= to
+	}), nil
+}
+
+// TaskByExternalID возвращает задачу по ссылке во внешней системе.
+// Удалённые задачи не возвращаются.
+func (s *Storage) TaskByExternalID(ctx context.Context, system, externalID string) (*storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, t := range s.tasks {
+		if !t.DeletedAt.Valid && t.ExternalSystem != nil && t.ExternalID != nil &&
+			*t.ExternalSystem == system && *t.ExternalID == externalID {
+			return &t, nil
+		}
+	}
+	return nil, storage.ErrNotFound
+}
+
+// TasksClosedInRange возвращает задачи, закрытые в интервале [from, to],
+// в порядке закрытия. Незакрытые задачи (closed = 0)
+// не возвращаются при любом интервале.
+func (s *Storage) TasksClosedInRange(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tasks := s.selectTasks(func(t storage.Task) bool {
+		return t.Closed != 0 && t.Closed >= from && t.Closed <= to
+	})
+	// задачи уже упорядочены по ID, стабильная сортировка сохраняет этот порядок
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].Closed < tasks[j].Closed })
+	return tasks, nil
+}
+
+// TasksByMetadataKey возвращает задачи, у которых атрибут key равен value.
+func (s *Storage) TasksByMetadataKey(ctx context.Context, key, value string) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.selectTasks(func(t storage.Task) bool {
+		v, ok := t.Metadata[key]
+		return ok && v == value
+	}), nil
+}
+
+// insertTask сохраняет новую задачу и возвращает её ID.
+// Вызывается под блокировкой.
+func (s *Storage) insertTask(t storage.Task) int {
+	s.lastTaskID++
+	t.ID = s.lastTaskID
+	t.Version = 0
+	if t.Opened == 0 {
+		t.Opened = time.Now().Unix()
+	}
+	if t.Priority == 0 {
+		t.Priority = storage.PriorityLow
+	}
+	t.Rank = float64(time.Now().UnixMilli())
+	s.tasks[t.ID] = t
+	return t.ID
+}
+
+// newTask возвращает задачу только с теми полями, которые
+// сохраняет AddTask в postgres.Storage: заголовком, содержанием,
+// приоритетом, сроком выполнения, проектом, атрибутами,
+// ключом идемпотентности, ссылкой во внешней системе и тегами.
+func newTask(t storage.Task) storage.Task {
+	return storage.Task{
+		Title:          t.Title,
+		Content:        t.Content,
+		Priority:       t.Priority,
+		DueAt:          t.DueAt,
+		ProjectID:      t.ProjectID,
+		Metadata:       maps.Clone(t.Metadata),
+		IdempotencyKey: t.IdempotencyKey,
+		ExternalSystem: t.ExternalSystem,
+		ExternalID:     t.ExternalID,
+		Tags:           slices.Clone(t.Tags),
+	}
+}
+
+// externalTaken проверяет, есть ли у другой задачи (не taskID)
+// та же ссылка во внешней системе, что и у t. Вызывается под блокировкой.
+func (s *Storage) externalTaken(t storage.Task, taskID int) bool {
+	if t.ExternalSystem == nil || t.ExternalID == nil {
+		return false
+	}
+	for _, old := range s.tasks {
+		if old.ID != taskID && old.ExternalSystem != nil && old.ExternalID != nil &&
+			*old.ExternalSystem == *t.ExternalSystem && *old.ExternalID == *t.ExternalID {
+			return true
+		}
+	}
+	return false
+}
+
+// AddTask создаёт новую задачу и возвращает её id.
+// Если задан ключ идемпотентности и задача с таким ключом уже есть,
+// новая задача не создаётся и возвращается id существующей.
+func (s *Storage) AddTask(ctx context.Context, t storage.Task) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id, ok := s.taskByIdempotencyKey(t.IdempotencyKey); ok {
+		return id, nil
+	}
+	if err := s.checkNewTask(t); err != nil {
+		return 0, err
+	}
+	return s.insertTask(newTask(t)), nil
+}
+
+// taskByIdempotencyKey возвращает ID задачи с ключом идемпотентности key,
+// если ключ задан и такая задача есть. Вызывается под блокировкой.
+func (s *Storage) taskByIdempotencyKey(key *string) (int, bool) {
+	if key == nil {
+		return 0, false
+	}
+	for _, old := range s.tasks {
+		if old.IdempotencyKey != nil && *old.IdempotencyKey == *key {
+			return old.ID, true
+		}
+	}
+	return 0, false
+}
+
+// checkNewTask проверяет, что задачу t можно создать: её ссылка
+// во внешней системе не занята другой задачей. Вызывается под блокировкой.
+func (s *Storage) checkNewTask(t storage.Task) error {
+	if s.externalTaken(t, 0) {
+		return storage.ErrConflict
+	}
+	return nil
+}
+
+// AddTaskWithLabels создаёт новую задачу, назначает ей метки
+// и возвращает её id. Как и AddTask, при повторном ключе идемпотентности
+// возвращает id существующей задачи, не изменяя её метки.
+func (s *Storage) AddTaskWithLabels(ctx context.Context, t storage.Task, labelIDs []int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id, ok := s.taskByIdempotencyKey(t.IdempotencyKey); ok {
+		return id, nil
+	}
+	if err := s.checkNewTask(t); err != nil {
+		return 0, err
+	}
+	id := s.insertTask(newTask(t))
+	for _, labelID := range labelIDs {
+		if s.taskLabels[id] == nil {
+			s.taskLabels[id] = make(map[int]bool)
+		}
+		s.taskLabels[id][labelID] = true
+	}
+	return id, nil
+}
+
+// AddTasks создаёт новые задачи и возвращает слайс ID созданых задач.
+func (s *Storage) AddTasks(ctx context.Context, tasks []storage.Task) ([]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ids []int
+	for _, t := range tasks {
+		ids = append(ids, s.insertTask(storage.Task{Title: t.Title, Content: t.Content}))
+	}
+	return ids, nil
+}
+
+// AddTasksBatch создаёт новые задачи и возвращает слайс ID созданых задач.
+func (s *Storage) AddTasksBatch(ctx context.Context, tasks []storage.Task) ([]int, error) {
+	return s.AddTasks(ctx, tasks)
+}
+
+// ImportTasks создаёт задачи.
+func (s *Storage) ImportTasks(ctx context.Context, tasks []storage.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, t := range tasks {
+		s.insertTask(newTask(t))
+	}
+	return nil
+}
+
+// UpdateTask обновляет задачу принимая в качестве агрумента экземпляр структуры Task.
+func (s *Storage) UpdateTask(ctx context.Context, task storage.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.tasks[task.ID]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	if old.Version != task.Version {
+		return storage.ErrVersionConflict
+	}
+	if s.externalTaken(task, task.ID) {
+		return storage.ErrConflict
+	}
+	if task.Priority == 0 {
+		task.Priority = storage.PriorityLow
+	}
+	task.DeletedAt = old.DeletedAt
+	task.Stale = old.Stale
+	task.Rank = old.Rank
+	task.Metadata = maps.Clone(task.Metadata)
+	task.Tags = slices.Clone(task.Tags)
+	s.replaceTask(old, task)
+	return nil
+}
+
+// replaceTask сохраняет изменённую задачу вместо old. Как и триггеры
+// в postgres, увеличивает версию и записывает смену исполнителя.
+// Вызывается под блокировкой.
+func (s *Storage) replaceTask(old, t storage.Task) {
+	t.Version = old.Version + 1
+	s.trackAssignment(old, t)
+	s.tasks[t.ID] = t
+}
+
+// UpsertTask создаёт задачу или обновляет существующую с тем же ID.
+func (s *Storage) UpsertTask(ctx context.Context, t storage.Task) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t.Metadata = maps.Clone(t.Metadata)
+	t.Tags = slices.Clone(t.Tags)
+	if t.Priority == 0 {
+		t.Priority = storage.PriorityLow
+	}
+	// нулевой ранг: ранг по умолчанию для новой задачи и прежний для существующей
+	rank := t.Rank
+	if t.ID == 0 {
+		id := s.insertTask(t)
+		if rank != 0 {
+			nt := s.tasks[id]
+			nt.Rank = rank
+			s.tasks[id] = nt
+		}
+		return id, nil
+	}
+	if old, ok := s.tasks[t.ID]; ok {
+		t.DeletedAt = old.DeletedAt
+		t.Stale = old.Stale
+		if rank == 0 {
+			t.Rank = old.Rank
+		}
+		s.replaceTask(old, t)
+	} else {
+		t.Version = 0
+		if rank == 0 {
+			t.Rank = float64(time.Now().UnixMilli())
+		}
+		s.tasks[t.ID] = t
+	}
+	if t.ID > s.lastTaskID {
+		s.lastTaskID = t.ID
+	}
+	return t.ID, nil
+}
+
+// PartialUpdateTask обновляет только те поля задачи, которые заданы в patch.
+func (s *Storage) PartialUpdateTask(ctx context.Context, taskID int, patch storage.TaskPatch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tasks[taskID]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	if patch.Opened != nil {
+		t.Opened = *patch.Opened
+	}
+	if patch.Closed != nil {
+		t.Closed = *patch.Closed
+	}
+	if patch.AuthorID != nil {
+		t.AuthorID = *patch.AuthorID
+	}
+	if patch.AssignedID != nil {
+		t.AssignedID = *patch.AssignedID
+	}
+	if patch.Title != nil {
+		t.Title = *patch.Title
+	}
+	if patch.Content != nil {
+		t.Content = *patch.Content
+	}
+	if patch.Priority != nil {
+		t.Priority = *patch.Priority
+	}
+	s.replaceTask(s.tasks[taskID], t)
+	return nil
+}
+
+// UpdateTaskStatus изменяет статус задачи.
+func (s *Storage) UpdateTaskStatus(ctx context.Context, taskID int, status storage.Status) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tasks[taskID]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	t.Status = status
+	s.replaceTask(s.tasks[taskID], t)
+	return nil
+}
+
+// AssignTask назначает задаче исполнителя.
+func (s *Storage) AssignTask(ctx context.Context, taskID, userID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tasks[taskID]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	t.AssignedID = userID
+	s.replaceTask(s.tasks[taskID], t)
+	return nil
+}
+
+// UpdateTasksAssignee назначает исполнителя задачам
+// и возвращает количество изменённых задач.
+func (s *Storage) UpdateTasksAssignee(ctx context.Context, taskIDs []int, assigneeID int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.updateTasks(taskIDs, func(t *storage.Task) { t.AssignedID = assigneeID }), nil
+}
+
+// UpdateTasksStatus изменяет статус задач
+// и возвращает количество изменённых задач.
+func (s *Storage) UpdateTasksStatus(ctx context.Context, taskIDs []int, status storage.Status) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.updateTasks(taskIDs, func(t *storage.Task) { t.Status = status }), nil
+}
+
+// updateTasks применяет update к каждой существующей задаче из taskIDs
+// (повторяющиеся ID учитываются один раз) и возвращает количество
+// изменённых задач. Вызывается под блокировкой.
+func (s *Storage) updateTasks(taskIDs []int, update func(t *storage.Task)) int64 {
+	var n int64
+	seen := make(map[int]bool, len(taskIDs))
+	for _, id := range taskIDs {
+		t, ok := s.tasks[id]
+		if !ok || seen[id] {
+			continue
+		}
+		seen[id] = true
+		update(&t)
+		s.replaceTask(s.tasks[id], t)
+		n++
+	}
+	return n
+}
+
+// setDeleted устанавливает или снимает отметку об удалении задачи
+// и сообщает, изменилось ли что-нибудь: отсутствующая задача и задача,
+// уже находящаяся в нужном состоянии, не затрагиваются.
+// Вызывается под блокировкой.
+func (s *Storage) setDeleted(taskID int, deleted bool) bool {
+	t, ok := s.tasks[taskID]
+	if !ok || t.DeletedAt.Valid == deleted {
+		return false
+	}
+	t.DeletedAt.Valid = deleted
+	t.DeletedAt.Time = time.Time{}
+	if deleted {
+		t.DeletedAt.Time = time.Now()
+	}
+	s.replaceTask(s.tasks[taskID], t)
+	return true
+}
+
+// DeleteTask помечает задачу как удалённую.
+func (s *Storage) DeleteTask(ctx context.Context, taskId int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.setDeleted(taskId, true) {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+// DeleteTasks помечает задачи как удалённые.
+func (s *Storage) DeleteTasks(ctx context.Context, taskIDs []int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(taskIDs) == 0 {
+		return nil
+	}
+	var changed bool
+	for _, id := range taskIDs {
+		if s.setDeleted(id, true) {
+			changed = true
+		}
+	}
+	if !changed {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+// UndeleteTask восстанавливает удалённую задачу.
+func (s *Storage) UndeleteTask(ctx context.Context, taskID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.setDeleted(taskID, false) {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+// AddUser создаёт нового пользователя и возвращает его id.
+func (s *Storage) AddUser(ctx context.Context, u storage.User) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastUserID++
+	u.ID = s.lastUserID
+	if u.UserRole == 0 {
+		u.UserRole = storage.RoleViewer
+	}
+	s.users[u.ID] = u
+	return u.ID, nil
+}
+
+// Users возвращает список пользователей.
+func (s *Storage) Users(ctx context.Context) ([]storage.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var users []storage.User
+	for _, u := range s.users {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users, nil
+}
+
+// UserByID возвращает пользователя по его ID.
+func (s *Storage) UserByID(ctx context.Context, userID int) (*storage.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	u, ok := s.users[userID]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return &u, nil
+}
+
+// UpdateUser обновляет данные пользователя.
+func (s *Storage) UpdateUser(ctx context.Context, u storage.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.users[u.ID]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	// роль изменяется только через AssignRole
+	u.UserRole = old.UserRole
+	s.users[u.ID] = u
+	return nil
+}
+
+// AssignRole назначает пользователю роль.
+func (s *Storage) AssignRole(ctx context.Context, userID int, role storage.Role) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[userID]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	u.UserRole = role
+	s.users[userID] = u
+	return nil
+}
+
+// RoleOfUser возвращает роль пользователя.
+func (s *Storage) RoleOfUser(ctx context.Context, userID int) (storage.Role, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	u, ok := s.users[userID]
+	if !ok {
+		return 0, storage.ErrNotFound
+	}
+	return u.UserRole, nil
+}
+
+// UsersWithRole возвращает пользователей с ролью role.
+func (s *Storage) UsersWithRole(ctx context.Context, role storage.Role) ([]storage.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var users []storage.User
+	for _, u := range s.users {
+		if u.UserRole == role {
+			users = append(users, u)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users, nil
+}
+
+// DeleteUser удаляет пользователя по ID.
+func (s *Storage) DeleteUser(ctx context.Context, userID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[userID]; !ok {
+		return storage.ErrNotFound
+	}
+	delete(s.users, userID)
+	delete(s.notifyPrefs, userID)
+	for _, set := range s.watchers {
+		delete(set, userID)
+	}
+	for id, k := range s.apiKeys {
+		if k.UserID == userID {
+			delete(s.apiKeys, id)
+		}
+	}
+	return nil
+}
+
+// labelNameTaken сообщает, занято ли название метки другой меткой.
+// Вызывается под блокировкой.
+func (s *Storage) labelNameTaken(name string, exceptID int) bool {
+	for _, l := range s.labels {
+		if l.Name == name && l.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
+
+// AddLabel создаёт новую метку и возвращает её id.
+func (s *Storage) AddLabel(ctx context.Context, l storage.Label) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.labelNameTaken(l.Name, 0) {
+		return 0, storage.ErrConflict
+	}
+	s.lastLabelID++
+	l.ID = s.lastLabelID
+	s.labels[l.ID] = l
+	return l.ID, nil
+}
+
+// Labels возвращает список меток.
+func (s *Storage) Labels(ctx context.Context) ([]storage.Label, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var labels []storage.Label
+	for _, l := range s.labels {
+		labels = append(labels, l)
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].ID < labels[j].ID })
+	return labels, nil
+}
+
+// LabelByID возвращает метку по её ID.
+func (s *Storage) LabelByID(ctx context.Context, labelID int) (*storage.Label, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	l, ok := s.labels[labelID]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return &l, nil
+}
+
+// UpdateLabel обновляет название метки.
+func (s *Storage) UpdateLabel(ctx context.Context, l storage.Label) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.labels[l.ID]; !ok {
+		return storage.ErrNotFound
+	}
+	if s.labelNameTaken(l.Name, l.ID) {
+		return storage.ErrConflict
+	}
+	s.labels[l.ID] = l
+	return nil
+}
+
+// DeleteLabel удаляет метку по ID вместе с её связями с задачами.
+func (s *Storage) DeleteLabel(ctx context.Context, labelID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.labels[labelID]; !ok {
+		return storage.ErrNotFound
+	}
+	delete(s.labels, labelID)
+	for _, labels := range s.taskLabels {
+		delete(labels, labelID)
+	}
+	return nil
+}
+
+// AssignLabel назначает метку задаче.
+func (s *Storage) AssignLabel(ctx context.Context, taskID, labelID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.taskLabels[taskID] == nil {
+		s.taskLabels[taskID] = make(map[int]bool)
+	}
+	s.taskLabels[taskID][labelID] = true
+	return nil
+}
+
+// RemoveLabel снимает метку с задачи.
+func (s *Storage) RemoveLabel(ctx context.Context, taskID, labelID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.taskLabels[taskID], labelID)
+	return nil
+}
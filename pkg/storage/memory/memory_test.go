@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"skillfactory/30.8.1/pkg/storage/storagetest"
+	"testing"
+)
+
+func TestStorage(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Interface {
+		return New()
+	})
+}
+
+// Значения из документации pg_trgm.
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"word", "two words", 4.0 / 11},
+		{"Word", "word", 1},
+		{"cat", "dog", 0},
+		{"", "", 0},
+	}
+	for _, tt := range tests {
+		got := similarity(trigrams(tt.a), trigrams(tt.b))
+		if got != tt.want {
+			t.Errorf("similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	want := []string{"  w", " wo", "wor", "ord", "rd "}
+	got := trigrams("Word!")
+	if len(got) != len(want) {
+		t.Errorf("trigrams() = %v, want %v", got, want)
+	}
+	for _, tr := range want {
+		if !got[tr] {
+			t.Errorf("trigrams() = %v, missing %q", got, tr)
+		}
+	}
+}
+
+func TestReset(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	_, err := s.AddTask(ctx, storage.Task{Title: "task"})
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+
+	s.Reset()
+	tasks, err := s.Tasks(ctx)
+	if err != nil {
+		t.Fatalf("Tasks() error = %v", err)
+	}
+	if len(tasks) != 0 {
+		t.Errorf("Tasks() after Reset = %v, want none", tasks)
+	}
+	// как и RESTART IDENTITY, Reset начинает нумерацию заново
+	id, err := s.AddTask(ctx, storage.Task{Title: "task"})
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	if id != 1 {
+		t.Errorf("AddTask() after Reset = %d, want 1", id)
+	}
+}
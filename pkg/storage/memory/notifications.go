@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"sort"
+)
+
+// SetNotificationPreference создаёт или заменяет настройки
+// уведомлений пользователя.
+func (s *Storage) SetNotificationPreference(ctx context.Context, p storage.NotificationPreference) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.notifyPrefs[p.UserID] = p
+	return nil
+}
+
+// GetNotificationPreference возвращает настройки уведомлений пользователя.
+func (s *Storage) GetNotificationPreference(ctx context.Context, userID int) (*storage.NotificationPreference, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	p, ok := s.notifyPrefs[userID]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return &p, nil
+}
+
+// UsersSubscribedToTask возвращает пользователей, связанных с задачей
+// (автора, исполнителя и наблюдателей), у которых включены уведомления о событии event.
+func (s *Storage) UsersSubscribedToTask(ctx context.Context, taskID int, event string) ([]storage.User, error) {
+	if _, err := (storage.NotificationPreference{}).Enabled(event); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.tasks[taskID]
+	if !ok {
+		return nil, nil
+	}
+	related := map[int]bool{t.AuthorID: true, t.AssignedID: true}
+	for id := range s.watchers[taskID] {
+		related[id] = true
+	}
+
+	var users []storage.User
+	for id := range related {
+		u, ok := s.users[id]
+		if !ok {
+			continue
+		}
+		p, ok := s.notifyPrefs[id]
+		if !ok {
+			continue
+		}
+		if enabled, _ := p.Enabled(event); enabled {
+			users = append(users, u)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users, nil
+}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"time"
+)
+
+// AppendOutbox записывает исходящие сообщения в транзакции tx,
+// поэтому при её откате сообщения тоже отменяются.
+// Если tx равен nil, сообщения записываются без внешней транзакции.
+func (s *Storage) AppendOutbox(ctx context.Context, tx storage.TxStorage, msgs []storage.OutboxMessage) error {
+	if tx != nil {
+		return tx.AppendOutbox(ctx, nil, msgs)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().Unix()
+	for _, m := range msgs {
+		s.lastOutboxID++
+		s.outbox = append(s.outbox, storage.OutboxMessage{
+			ID:        s.lastOutboxID,
+			Topic:     m.Topic,
+			Payload:   m.Payload,
+			CreatedAt: now,
+		})
+	}
+	return nil
+}
+
+// PendingOutbox возвращает не более limit неотправленных сообщений
+// в порядке записи.
+func (s *Storage) PendingOutbox(ctx context.Context, limit int) ([]storage.OutboxMessage, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("%w: limit must be positive", storage.ErrInvalidArgument)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var msgs []storage.OutboxMessage
+	for _, m := range s.outbox {
+		if m.SentAt == nil {
+			msgs = append(msgs, m)
+		}
+		if len(msgs) == limit {
+			break
+		}
+	}
+	return msgs, nil
+}
+
+// MarkOutboxSent отмечает сообщения как отправленные.
+func (s *Storage) MarkOutboxSent(ctx context.Context, ids []int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().Unix()
+	for i, m := range s.outbox {
+		if m.SentAt == nil && slices.Contains(ids, m.ID) {
+			s.outbox[i].SentAt = &now
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+	"sort"
+)
+
+// AddProject создаёт новый проект и возвращает его id.
+func (s *Storage) AddProject(ctx context.Context, p storage.Project) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastProjectID++
+	p.ID = s.lastProjectID
+	s.projects[p.ID] = p
+	return p.ID, nil
+}
+
+// Projects возвращает список проектов.
+func (s *Storage) Projects(ctx context.Context) ([]storage.Project, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var projects []storage.Project
+	for _, p := range s.projects {
+		projects = append(projects, p)
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].ID < projects[j].ID })
+	return projects, nil
+}
+
+// ProjectByID возвращает проект по его ID.
+func (s *Storage) ProjectByID(ctx context.Context, projectID int) (*storage.Project, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	p, ok := s.projects[projectID]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return &p, nil
+}
+
+// UpdateProject обновляет данные проекта.
+func (s *Storage) UpdateProject(ctx context.Context, p storage.Project) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.projects[p.ID]; ok {
+		s.projects[p.ID] = p
+	}
+	return nil
+}
+
+// DeleteProject удаляет проект по ID.
+// Если cascadeDelete равен true, задачи проекта открепляются от него,
+// иначе при наличии задач возвращается storage.ErrConflict.
+func (s *Storage) DeleteProject(ctx context.Context, projectID int, cascadeDelete bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, t := range s.tasks {
+		if t.ProjectID == nil || *t.ProjectID != projectID {
+			continue
+		}
+		if !cascadeDelete {
+			return fmt.Errorf("%w: project %d has tasks", storage.ErrConflict, projectID)
+		}
+		t.ProjectID = nil
+		s.replaceTask(s.tasks[id], t)
+	}
+	// спринты удаляются вместе с проектом
+	for id, sp := range s.sprints {
+		if sp.ProjectID == projectID {
+			delete(s.sprints, id)
+			delete(s.sprintTasks, id)
+		}
+	}
+	delete(s.projects, projectID)
+	return nil
+}
+
+// TasksByProject возвращает слайс задач проекта.
+func (s *Storage) TasksByProject(ctx context.Context, projectID int) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.selectTasks(func(t storage.Task) bool {
+		return t.ProjectID != nil && *t.ProjectID == projectID
+	}), nil
+}
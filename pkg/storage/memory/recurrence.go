@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+	"sort"
+	"time"
+)
+
+// Количество секунд в сутках.
+const secondsPerDay = 24 * 60 * 60
+
+// AddRecurrenceRule создаёт правило повторения задачи.
+// У задачи может быть только одно правило.
+func (s *Storage) AddRecurrenceRule(ctx context.Context, r storage.RecurrenceRule) error {
+	if r.IntervalDays <= 0 {
+		return fmt.Errorf("%w: recurrence interval must be positive", storage.ErrInvalidArgument)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tasks[r.TaskID]; !ok {
+		return fmt.Errorf("%w: task %d", storage.ErrNotFound, r.TaskID)
+	}
+	if _, ok := s.recurrences[r.TaskID]; ok {
+		return fmt.Errorf("%w: recurrence rule for task %d already exists", storage.ErrConflict, r.TaskID)
+	}
+	s.recurrences[r.TaskID] = r
+	return nil
+}
+
+// RecurrenceRuleByTask возвращает правило повторения задачи.
+func (s *Storage) RecurrenceRuleByTask(ctx context.Context, taskID int) (*storage.RecurrenceRule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.recurrences[taskID]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return &r, nil
+}
+
+// UpdateRecurrenceRule обновляет правило повторения задачи.
+func (s *Storage) UpdateRecurrenceRule(ctx context.Context, r storage.RecurrenceRule) error {
+	if r.IntervalDays <= 0 {
+		return fmt.Errorf("%w: recurrence interval must be positive", storage.ErrInvalidArgument)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.recurrences[r.TaskID]; ok {
+		s.recurrences[r.TaskID] = r
+	}
+	return nil
+}
+
+// DeleteRecurrenceRule удаляет правило повторения задачи.
+func (s *Storage) DeleteRecurrenceRule(ctx context.Context, taskID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.recurrences, taskID)
+	return nil
+}
+
+// DueRecurrences возвращает правила, по которым к моменту now
+// пора создать копию задачи, в порядке наступления срока.
+func (s *Storage) DueRecurrences(ctx context.Context, now int64) ([]storage.RecurrenceRule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var rules []storage.RecurrenceRule
+	for _, r := range s.recurrences {
+		if r.NextDue <= now {
+			rules = append(rules, r)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].NextDue != rules[j].NextDue {
+			return rules[i].NextDue < rules[j].NextDue
+		}
+		return rules[i].TaskID < rules[j].TaskID
+	})
+	return rules, nil
+}
+
+// SpawnRecurringTask создаёт копию задачи-шаблона со сроком rule.NextDue
+// и сдвигает срок следующей копии на интервал правила.
+//
+// Если правило было изменено после чтения, возвращается storage.ErrConflict.
+func (s *Storage) SpawnRecurringTask(ctx context.Context, rule storage.RecurrenceRule) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tasks[rule.TaskID]
+	if !ok || t.DeletedAt.Valid {
+		return 0, storage.ErrNotFound
+	}
+	cur, ok := s.recurrences[rule.TaskID]
+	if !ok || cur.NextDue != rule.NextDue {
+		return 0, fmt.Errorf("%w: recurrence rule for task %d has changed", storage.ErrConflict, rule.TaskID)
+	}
+
+	next := newTask(t)
+	due := rule.NextDue
+	next.DueAt = &due
+	id := s.insertTask(next)
+
+	cur.NextDue += int64(cur.IntervalDays) * secondsPerDay
+	cur.LastCreated = time.Now().Unix()
+	s.recurrences[rule.TaskID] = cur
+	return id, nil
+}
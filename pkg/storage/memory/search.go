@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SearchTasks возвращает не более limit задач, в заголовке или тексте
+// которых встречаются все слова запроса. В отличие от postgres
+// поиск выполняется без учёта словоформ и ранжирования.
+func (s *Storage) SearchTasks(ctx context.Context, query string, limit int) ([]storage.Task, error) {
+	words := strings.Fields(strings.ToLower(query))
+	if len(words) == 0 {
+		return nil, fmt.Errorf("%w: search query is empty", storage.ErrInvalidArgument)
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("%w: limit must be positive", storage.ErrInvalidArgument)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tasks := s.selectTasks(func(t storage.Task) bool {
+		text := strings.ToLower(t.Title + " " + t.Content)
+		for _, w := range words {
+			if !strings.Contains(text, w) {
+				return false
+			}
+		}
+		return true
+	})
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+	return tasks, nil
+}
+
+// similarTasksLimit - максимальное количество задач, возвращаемых SimilarTasks.
+const similarTasksLimit = 10
+
+// SimilarTasks возвращает не более 10 задач, сходство заголовка которых
+// с title больше threshold, в порядке убывания сходства.
+// Сходство вычисляется так же, как функция similarity расширения pg_trgm.
+func (s *Storage) SimilarTasks(ctx context.Context, title string, threshold float64) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	query := trigrams(title)
+	score := make(map[int]float64)
+	tasks := s.selectTasks(func(t storage.Task) bool {
+		score[t.ID] = similarity(query, trigrams(t.Title))
+		return score[t.ID] > threshold
+	})
+	sort.SliceStable(tasks, func(i, j int) bool { return score[tasks[i].ID] > score[tasks[j].ID] })
+	if len(tasks) > similarTasksLimit {
+		tasks = tasks[:similarTasksLimit]
+	}
+	return tasks, nil
+}
+
+// trigrams возвращает множество триграмм строки по правилам pg_trgm:
+// строка приводится к нижнему регистру и делится на слова из букв и цифр,
+// каждое слово дополняется двумя пробелами в начале и одним в конце.
+func trigrams(s string) map[string]bool {
+	set := make(map[string]bool)
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		r := []rune("  " + w + " ")
+		for i := 0; i+3 <= len(r); i++ {
+			set[string(r[i:i+3])] = true
+		}
+	}
+	return set
+}
+
+// similarity возвращает отношение количества общих триграмм
+// к количеству всех различных триграмм двух множеств.
+func similarity(a, b map[string]bool) float64 {
+	common := 0
+	for t := range a {
+		if b[t] {
+			common++
+		}
+	}
+	total := len(a) + len(b) - common
+	if total == 0 {
+		return 0
+	}
+	return float64(common) / float64(total)
+}
@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+	"sort"
+)
+
+// AddSprint создаёт новый спринт и возвращает его id.
+func (s *Storage) AddSprint(ctx context.Context, sp storage.Sprint) (int, error) {
+	if sp.EndAt < sp.StartAt {
+		return 0, fmt.Errorf("%w: sprint ends before it starts", storage.ErrInvalidArgument)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastSprintID++
+	sp.ID = s.lastSprintID
+	s.sprints[sp.ID] = sp
+	return sp.ID, nil
+}
+
+// SprintByID возвращает спринт по его ID.
+func (s *Storage) SprintByID(ctx context.Context, sprintID int) (*storage.Sprint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sp, ok := s.sprints[sprintID]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return &sp, nil
+}
+
+// Sprints возвращает список спринтов.
+func (s *Storage) Sprints(ctx context.Context) ([]storage.Sprint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var sprints []storage.Sprint
+	for _, sp := range s.sprints {
+		sprints = append(sprints, sp)
+	}
+	sort.Slice(sprints, func(i, j int) bool { return sprints[i].ID < sprints[j].ID })
+	return sprints, nil
+}
+
+// UpdateSprint обновляет данные спринта.
+func (s *Storage) UpdateSprint(ctx context.Context, sp storage.Sprint) error {
+	if sp.EndAt < sp.StartAt {
+		return fmt.Errorf("%w: sprint ends before it starts", storage.ErrInvalidArgument)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.sprints[sp.ID]; ok {
+		s.sprints[sp.ID] = sp
+	}
+	return nil
+}
+
+// DeleteSprint удаляет спринт по ID. Задачи спринта не удаляются.
+func (s *Storage) DeleteSprint(ctx context.Context, sprintID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sprints, sprintID)
+	delete(s.sprintTasks, sprintID)
+	return nil
+}
+
+// AssignTaskToSprint включает задачу в спринт.
+func (s *Storage) AssignTaskToSprint(ctx context.Context, taskID, sprintID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.sprints[sprintID]; !ok {
+		return fmt.Errorf("%w: sprint %d", storage.ErrNotFound, sprintID)
+	}
+	if s.sprintTasks[sprintID] == nil {
+		s.sprintTasks[sprintID] = make(map[int]bool)
+	}
+	s.sprintTasks[sprintID][taskID] = true
+	return nil
+}
+
+// RemoveTaskFromSprint исключает задачу из спринта.
+func (s *Storage) RemoveTaskFromSprint(ctx context.Context, taskID, sprintID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sprintTasks[sprintID], taskID)
+	return nil
+}
+
+// TasksBySprint возвращает слайс задач спринта.
+func (s *Storage) TasksBySprint(ctx context.Context, sprintID int) ([]storage.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.selectTasks(func(t storage.Task) bool { return s.sprintTasks[sprintID][t.ID] }), nil
+}
+
+// SprintVelocity возвращает количество закрытых задач спринта.
+func (s *Storage) SprintVelocity(ctx context.Context, sprintID int) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.countTasks(func(t storage.Task) bool {
+		return s.sprintTasks[sprintID][t.ID] && t.Closed != 0
+	}), nil
+}
+
+// BurndownData делит время спринта на buckets равных интервалов и для конца
+// каждого интервала возвращает количество задач спринта, которые к этому
+// моменту были открыты, но ещё не закрыты.
+func (s *Storage) BurndownData(ctx context.Context, sprintID int, buckets int) ([]storage.BurndownPoint, error) {
+	if buckets <= 0 {
+		return nil, fmt.Errorf("%w: buckets must be positive", storage.ErrInvalidArgument)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sp, ok := s.sprints[sprintID]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+
+	points := make([]storage.BurndownPoint, buckets)
+	for i := range points {
+		at := sp.StartAt + (sp.EndAt-sp.StartAt)*int64(i+1)/int64(buckets)
+		points[i] = storage.BurndownPoint{
+			At: at,
+			Remaining: s.countTasks(func(t storage.Task) bool {
+				return s.sprintTasks[sprintID][t.ID] &&
+					t.Opened <= at && (t.Closed == 0 || t.Closed > at)
+			}),
+		}
+	}
+	return points, nil
+}
@@ -0,0 +1,119 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+	"sort"
+	"time"
+)
+
+// UserWorkloads возвращает загрузку всех пользователей.
+func (s *Storage) UserWorkloads(ctx context.Context) ([]storage.UserWorkload, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var workloads []storage.UserWorkload
+	for _, u := range s.users {
+		workloads = append(workloads, s.userWorkload(u))
+	}
+	sort.Slice(workloads, func(i, j int) bool { return workloads[i].ID < workloads[j].ID })
+	return workloads, nil
+}
+
+// UserWorkload возвращает загрузку пользователя.
+func (s *Storage) UserWorkload(ctx context.Context, userID int) (*storage.UserWorkload, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	u, ok := s.users[userID]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	w := s.userWorkload(u)
+	return &w, nil
+}
+
+// userWorkload подсчитывает задачи, назначенные пользователю.
+// Вызывается под блокировкой.
+func (s *Storage) userWorkload(u storage.User) storage.UserWorkload {
+	w := storage.UserWorkload{User: u}
+	for _, t := range s.selectTasks(func(t storage.Task) bool { return t.AssignedID == u.ID }) {
+		w.TotalTaskCount++
+		if t.Closed == 0 {
+			w.OpenTaskCount++
+		}
+	}
+	return w
+}
+
+// TaskStats возвращает сводную статистику по задачам.
+func (s *Storage) TaskStats(ctx context.Context) (*storage.TaskStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var (
+		st     storage.TaskStats
+		ageSum int64
+	)
+	now := time.Now().Unix()
+	for _, t := range s.selectTasks(all) {
+		st.Total++
+		if t.Closed != 0 {
+			st.Closed++
+			continue
+		}
+		st.Open++
+		ageSum += now - t.Opened
+		if t.DueAt != nil && *t.DueAt < now {
+			st.Overdue++
+		}
+	}
+	if st.Open > 0 {
+		st.AvgAgeSeconds = float64(ageSum) / float64(st.Open)
+	}
+	return &st, nil
+}
+
+// LabelStats возвращает все метки с количеством задач
+// в порядке убывания количества.
+func (s *Storage) LabelStats(ctx context.Context) ([]storage.LabelStat, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.labelStats(), nil
+}
+
+// TopLabels возвращает n наиболее используемых меток.
+func (s *Storage) TopLabels(ctx context.Context, n int) ([]storage.LabelStat, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: n must be positive", storage.ErrInvalidArgument)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stats := s.labelStats()
+	if len(stats) > n {
+		stats = stats[:n]
+	}
+	return stats, nil
+}
+
+// labelStats подсчитывает неудалённые задачи для каждой метки.
+// Вызывается под блокировкой.
+func (s *Storage) labelStats() []storage.LabelStat {
+	var stats []storage.LabelStat
+	for _, l := range s.labels {
+		stats = append(stats, storage.LabelStat{
+			Label:     l,
+			TaskCount: s.countTasks(func(t storage.Task) bool { return s.taskLabels[t.ID][l.ID] }),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].TaskCount != stats[j].TaskCount {
+			return stats[i].TaskCount > stats[j].TaskCount
+		}
+		return stats[i].ID < stats[j].ID
+	})
+	return stats
+}
@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+)
+
+// LockTask блокирует задачу до UnlockTask. В отличие от postgres
+// блокировка не мешает изменять задачу, а только запрещает
+// повторную блокировку.
+//
+// Возвращает storage.ErrLocked, если задача уже заблокирована,
+// и storage.ErrNotFound, если задачи нет.
+func (s *Storage) LockTask(ctx context.Context, taskID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tasks[taskID]; !ok {
+		return storage.ErrNotFound
+	}
+	if s.lockedTasks[taskID] {
+		return storage.ErrLocked
+	}
+	s.lockedTasks[taskID] = true
+	return nil
+}
+
+// UnlockTask снимает блокировку, установленную LockTask.
+func (s *Storage) UnlockTask(ctx context.Context, taskID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.lockedTasks[taskID] {
+		return fmt.Errorf("%w: task %d is not locked", storage.ErrNotFound, taskID)
+	}
+	delete(s.lockedTasks, taskID)
+	return nil
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"sort"
+	"time"
+)
+
+// LogTime сохраняет запись о затраченном времени и возвращает её id.
+func (s *Storage) LogTime(ctx context.Context, e storage.TimeEntry) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastTimeEntryID++
+	e.ID = s.lastTimeEntryID
+	e.LoggedAt = time.Now().Unix()
+	s.timeEntries[e.ID] = e
+	return e.ID, nil
+}
+
+// selectTimeEntries возвращает записи о затраченном времени,
+// удовлетворяющие условию, упорядоченные по ID.
+// Вызывается под блокировкой.
+func (s *Storage) selectTimeEntries(match func(e storage.TimeEntry) bool) []storage.TimeEntry {
+	var entries []storage.TimeEntry
+	for _, e := range s.timeEntries {
+		if match(e) {
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	return entries
+}
+
+// TimeEntriesByTask возвращает записи о затраченном времени по задаче.
+func (s *Storage) TimeEntriesByTask(ctx context.Context, taskID int) ([]storage.TimeEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.selectTimeEntries(func(e storage.TimeEntry) bool { return e.TaskID == taskID }), nil
+}
+
+// TimeEntriesByUser возвращает записи о затраченном времени пользователя.
+func (s *Storage) TimeEntriesByUser(ctx context.Context, userID int) ([]storage.TimeEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.selectTimeEntries(func(e storage.TimeEntry) bool { return e.UserID == userID }), nil
+}
+
+// TotalMinutesByTask возвращает суммарное время в минутах,
+// затраченное на задачу.
+func (s *Storage) TotalMinutesByTask(ctx context.Context, taskID int) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	n := 0
+	for _, e := range s.timeEntries {
+		if e.TaskID == taskID {
+			n += e.Minutes
+		}
+	}
+	return n, nil
+}
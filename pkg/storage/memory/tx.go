@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+)
+
+// Tx - транзакция над хранилищем в памяти. Операции выполняются
+// над копией данных, которая при Commit заменяет содержимое
+// исходного хранилища.
+//
+// На время транзакции исходное хранилище заблокировано на запись
+// и чтение, поэтому обращаться к нему до Commit или Rollback
+// из той же горутины нельзя. После завершения транзакции методы Tx
+// работают с её копией данных, которая больше никуда не применяется.
+type Tx struct {
+	*Storage
+	parent *Storage
+	closed bool
+
+	savepoints map[string]data
+}
+
+// BeginTx начинает транзакцию.
+func (s *Storage) BeginTx(ctx context.Context) (storage.TxStorage, error) {
+	s.mu.Lock()
+	t := Tx{
+		Storage: &Storage{data: s.data.clone()},
+		parent:  s,
+
+		savepoints: make(map[string]data),
+	}
+	return &t, nil
+}
+
+// Commit применяет изменения транзакции к исходному хранилищу.
+func (t *Tx) Commit(ctx context.Context) error {
+	if t.closed {
+		return storage.ErrTxClosed
+	}
+	t.closed = true
+	t.Storage.mu.Lock()
+	t.parent.data = t.Storage.data.clone()
+	t.Storage.mu.Unlock()
+	t.parent.mu.Unlock()
+	return nil
+}
+
+// Rollback отменяет изменения транзакции.
+func (t *Tx) Rollback(ctx context.Context) error {
+	if t.closed {
+		return storage.ErrTxClosed
+	}
+	t.closed = true
+	t.parent.mu.Unlock()
+	return nil
+}
+
+// Savepoint создаёт точку сохранения с указанным именем.
+// Точка с тем же именем перезаписывается.
+func (t *Tx) Savepoint(ctx context.Context, name string) error {
+	if t.closed {
+		return storage.ErrTxClosed
+	}
+	t.Storage.mu.RLock()
+	defer t.Storage.mu.RUnlock()
+	t.savepoints[name] = t.Storage.data.clone()
+	return nil
+}
+
+// RollbackToSavepoint отменяет изменения, сделанные после
+// создания точки сохранения. Сама точка сохранения остаётся.
+func (t *Tx) RollbackToSavepoint(ctx context.Context, name string) error {
+	if t.closed {
+		return storage.ErrTxClosed
+	}
+	sp, ok := t.savepoints[name]
+	if !ok {
+		return fmt.Errorf("%w: savepoint %q does not exist", storage.ErrNotFound, name)
+	}
+	t.Storage.mu.Lock()
+	defer t.Storage.mu.Unlock()
+	t.Storage.data = sp.clone()
+	return nil
+}
+
+// ReleaseSavepoint удаляет точку сохранения, сохраняя изменения.
+func (t *Tx) ReleaseSavepoint(ctx context.Context, name string) error {
+	if t.closed {
+		return storage.ErrTxClosed
+	}
+	if _, ok := t.savepoints[name]; !ok {
+		return fmt.Errorf("%w: savepoint %q does not exist", storage.ErrNotFound, name)
+	}
+	delete(t.savepoints, name)
+	return nil
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+	"sort"
+)
+
+// WatchTask подписывает пользователя на изменения задачи.
+// Если пользователь уже наблюдает за задачей, ошибка не возвращается.
+func (s *Storage) WatchTask(ctx context.Context, userID, taskID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[userID]; !ok {
+		return fmt.Errorf("%w: user %d", storage.ErrNotFound, userID)
+	}
+	if _, ok := s.tasks[taskID]; !ok {
+		return fmt.Errorf("%w: task %d", storage.ErrNotFound, taskID)
+	}
+	if s.watchers[taskID] == nil {
+		s.watchers[taskID] = make(map[int]bool)
+	}
+	s.watchers[taskID][userID] = true
+	return nil
+}
+
+// UnwatchTask отменяет подписку пользователя на изменения задачи.
+// Если пользователь не наблюдает за задачей, ошибка не возвращается.
+func (s *Storage) UnwatchTask(ctx context.Context, userID, taskID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.watchers[taskID], userID)
+	return nil
+}
+
+// WatchersOfTask возвращает наблюдателей задачи.
+func (s *Storage) WatchersOfTask(ctx context.Context, taskID int) ([]storage.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var users []storage.User
+	for id := range s.watchers[taskID] {
+		if u, ok := s.users[id]; ok {
+			users = append(users, u)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users, nil
+}
@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"sort"
+)
+
+// RegisterWebhook сохраняет веб-хук и возвращает его ID.
+func (s *Storage) RegisterWebhook(ctx context.Context, w storage.Webhook) (int, error) {
+	err := w.Validate()
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastWebhookID++
+	w.ID = s.lastWebhookID
+	w.Events = slices.Clone(w.Events)
+	s.webhooks[w.ID] = w
+	return w.ID, nil
+}
+
+// Webhooks возвращает все веб-хуки.
+func (s *Storage) Webhooks(ctx context.Context) ([]storage.Webhook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var webhooks []storage.Webhook
+	for _, w := range s.webhooks {
+		w.Events = slices.Clone(w.Events)
+		webhooks = append(webhooks, w)
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].ID < webhooks[j].ID })
+	return webhooks, nil
+}
+
+// WebhookByID возвращает веб-хук по ID.
+func (s *Storage) WebhookByID(ctx context.Context, webhookID int) (*storage.Webhook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	w, ok := s.webhooks[webhookID]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	w.Events = slices.Clone(w.Events)
+	return &w, nil
+}
+
+// UpdateWebhook изменяет веб-хук.
+func (s *Storage) UpdateWebhook(ctx context.Context, w storage.Webhook) error {
+	err := w.Validate()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.webhooks[w.ID]; !ok {
+		return storage.ErrNotFound
+	}
+	w.Events = slices.Clone(w.Events)
+	s.webhooks[w.ID] = w
+	return nil
+}
+
+// DeleteWebhook удаляет веб-хук.
+func (s *Storage) DeleteWebhook(ctx context.Context, webhookID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.webhooks[webhookID]; !ok {
+		return storage.ErrNotFound
+	}
+	delete(s.webhooks, webhookID)
+	return nil
+}
@@ -0,0 +1,1524 @@
+package mock
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"sync"
+)
+
+// Call описывает вызов метода хранилища.
+type Call struct {
+	Method string
+	Args   []any // аргументы вызова без контекста
+}
+
+// Mock - ручная заглушка storage.Interface для модульных тестов.
+// Каждый метод записывает вызов в Calls и делегирует работу
+// соответствующему полю-функции. Если поле не задано,
+// метод возвращает нулевые значения.
+type Mock struct {
+	mu    sync.Mutex
+	Calls []Call
+
+	TasksFunc                     func(ctx context.Context) ([]storage.Task, error)
+	TasksIterFunc                 func(ctx context.Context, fn func(t storage.Task) error) error
+	TasksListFunc                 func(ctx context.Context, opts storage.ListOptions) ([]storage.Task, error)
+	TotalCountFunc                func(ctx context.Context) (int, error)
+	TasksAfterFunc                func(ctx context.Context, afterID int, limit int) ([]storage.Task, error)
+	TaskCountFunc                 func(ctx context.Context) (int, error)
+	TaskCountByAuthorFunc         func(ctx context.Context, authorID int) (int, error)
+	TaskCountByLabelFunc          func(ctx context.Context, labelID int) (int, error)
+	TaskByIdFunc                  func(ctx context.Context, taskId int) (*storage.Task, error)
+	TasksByAuthorFunc             func(ctx context.Context, authorId int) ([]storage.Task, error)
+	TasksByAssigneeFunc           func(ctx context.Context, assigneeID int) ([]storage.Task, error)
+	TasksByLabelFunc              func(ctx context.Context, labelId int) ([]storage.Task, error)
+	FilterTasksFunc               func(ctx context.Context, f storage.TaskFilter) ([]storage.Task, error)
+	AddTaskFunc                   func(ctx context.Context, task storage.Task) (int, error)
+	AddTasksFunc                  func(ctx context.Context, tasks []storage.Task) ([]int, error)
+	AddTasksBatchFunc             func(ctx context.Context, tasks []storage.Task) ([]int, error)
+	UpdateTaskFunc                func(ctx context.Context, task storage.Task) error
+	UpsertTaskFunc                func(ctx context.Context, t storage.Task) (int, error)
+	PartialUpdateTaskFunc         func(ctx context.Context, taskID int, patch storage.TaskPatch) error
+	DeleteTaskFunc                func(ctx context.Context, taskId int) error
+	DeleteTasksFunc               func(ctx context.Context, taskIDs []int) error
+	TasksIncludingDeletedFunc     func(ctx context.Context) ([]storage.Task, error)
+	UndeleteTaskFunc              func(ctx context.Context, taskID int) error
+	AddUserFunc                   func(ctx context.Context, u storage.User) (int, error)
+	UsersFunc                     func(ctx context.Context) ([]storage.User, error)
+	UserByIDFunc                  func(ctx context.Context, userID int) (*storage.User, error)
+	UpdateUserFunc                func(ctx context.Context, u storage.User) error
+	DeleteUserFunc                func(ctx context.Context, userID int) error
+	AddLabelFunc                  func(ctx context.Context, l storage.Label) (int, error)
+	LabelsFunc                    func(ctx context.Context) ([]storage.Label, error)
+	LabelByIDFunc                 func(ctx context.Context, labelID int) (*storage.Label, error)
+	UpdateLabelFunc               func(ctx context.Context, l storage.Label) error
+	DeleteLabelFunc               func(ctx context.Context, labelID int) error
+	AssignLabelFunc               func(ctx context.Context, taskID, labelID int) error
+	RemoveLabelFunc               func(ctx context.Context, taskID, labelID int) error
+	TasksByStatusFunc             func(ctx context.Context, s storage.Status) ([]storage.Task, error)
+	UpdateTaskStatusFunc          func(ctx context.Context, taskID int, s storage.Status) error
+	TasksByPriorityFunc           func(ctx context.Context, p storage.Priority) ([]storage.Task, error)
+	TasksOrderedByPriorityFunc    func(ctx context.Context) ([]storage.Task, error)
+	TasksOverdueFunc              func(ctx context.Context, now int64) ([]storage.Task, error)
+	TasksDueBetweenFunc           func(ctx context.Context, from, to int64) ([]storage.Task, error)
+	AddCommentFunc                func(ctx context.Context, c storage.Comment) (int, error)
+	CommentsByTaskFunc            func(ctx context.Context, taskID int) ([]storage.Comment, error)
+	UpdateCommentFunc             func(ctx context.Context, c storage.Comment) error
+	DeleteCommentFunc             func(ctx context.Context, commentID int) error
+	LogTimeFunc                   func(ctx context.Context, e storage.TimeEntry) (int, error)
+	TimeEntriesByTaskFunc         func(ctx context.Context, taskID int) ([]storage.TimeEntry, error)
+	TimeEntriesByUserFunc         func(ctx context.Context, userID int) ([]storage.TimeEntry, error)
+	TotalMinutesByTaskFunc        func(ctx context.Context, taskID int) (int, error)
+	AddChecklistItemFunc          func(ctx context.Context, item storage.ChecklistItem) (int, error)
+	ChecklistByTaskFunc           func(ctx context.Context, taskID int) ([]storage.ChecklistItem, error)
+	UpdateChecklistItemFunc       func(ctx context.Context, item storage.ChecklistItem) error
+	DeleteChecklistItemFunc       func(ctx context.Context, itemID int) error
+	ReorderChecklistFunc          func(ctx context.Context, taskID int, orderedIDs []int) error
+	AddProjectFunc                func(ctx context.Context, p storage.Project) (int, error)
+	ProjectsFunc                  func(ctx context.Context) ([]storage.Project, error)
+	ProjectByIDFunc               func(ctx context.Context, projectID int) (*storage.Project, error)
+	UpdateProjectFunc             func(ctx context.Context, p storage.Project) error
+	DeleteProjectFunc             func(ctx context.Context, projectID int, cascadeDelete bool) error
+	TasksByProjectFunc            func(ctx context.Context, projectID int) ([]storage.Task, error)
+	AddDependencyFunc             func(ctx context.Context, taskID, dependsOnID int) error
+	RemoveDependencyFunc          func(ctx context.Context, taskID, dependsOnID int) error
+	DependenciesOfFunc            func(ctx context.Context, taskID int) ([]storage.Task, error)
+	BlockedByFunc                 func(ctx context.Context, taskID int) ([]storage.Task, error)
+	AddTaskWithLabelsFunc         func(ctx context.Context, t storage.Task, labelIDs []int) (int, error)
+	ImportTasksFunc               func(ctx context.Context, tasks []storage.Task) error
+	TasksWithLabelsFunc           func(ctx context.Context) ([]storage.TaskWithLabels, error)
+	TasksWithUsersFunc            func(ctx context.Context) ([]storage.TaskWithUsers, error)
+	BeginTxFunc                   func(ctx context.Context) (storage.TxStorage, error)
+	AuditLogFunc                  func(ctx context.Context, taskID int) ([]storage.AuditEntry, error)
+	RecordChangeFunc              func(ctx context.Context, e storage.AuditEntry) error
+	SearchTasksFunc               func(ctx context.Context, query string, limit int) ([]storage.Task, error)
+	TasksByMetadataKeyFunc        func(ctx context.Context, key, value string) ([]storage.Task, error)
+	AssignmentHistoryFunc         func(ctx context.Context, taskID int) ([]storage.AssignmentEvent, error)
+	RecordAssignmentFunc          func(ctx context.Context, e storage.AssignmentEvent) error
+	AddRecurrenceRuleFunc         func(ctx context.Context, rule storage.RecurrenceRule) error
+	RecurrenceRuleByTaskFunc      func(ctx context.Context, taskID int) (*storage.RecurrenceRule, error)
+	UpdateRecurrenceRuleFunc      func(ctx context.Context, rule storage.RecurrenceRule) error
+	DeleteRecurrenceRuleFunc      func(ctx context.Context, taskID int) error
+	DueRecurrencesFunc            func(ctx context.Context, now int64) ([]storage.RecurrenceRule, error)
+	SpawnRecurringTaskFunc        func(ctx context.Context, rule storage.RecurrenceRule) (int, error)
+	ArchiveTaskFunc               func(ctx context.Context, taskID int) error
+	ArchivedTasksFunc             func(ctx context.Context) ([]storage.Task, error)
+	UnarchiveTaskFunc             func(ctx context.Context, taskID int) error
+	TasksByLabelsFunc             func(ctx context.Context, labelIDs []int, mode storage.LabelFilterMode) ([]storage.Task, error)
+	TasksOpenedBetweenFunc        func(ctx context.Context, from, to int64) ([]storage.Task, error)
+	TasksClosedBetweenFunc        func(ctx context.Context, from, to int64) ([]storage.Task, error)
+	AddSprintFunc                 func(ctx context.Context, sp storage.Sprint) (int, error)
+	SprintByIDFunc                func(ctx context.Context, sprintID int) (*storage.Sprint, error)
+	SprintsFunc                   func(ctx context.Context) ([]storage.Sprint, error)
+	UpdateSprintFunc              func(ctx context.Context, sp storage.Sprint) error
+	DeleteSprintFunc              func(ctx context.Context, sprintID int) error
+	AssignTaskToSprintFunc        func(ctx context.Context, taskID, sprintID int) error
+	RemoveTaskFromSprintFunc      func(ctx context.Context, taskID, sprintID int) error
+	TasksBySprintFunc             func(ctx context.Context, sprintID int) ([]storage.Task, error)
+	SprintVelocityFunc            func(ctx context.Context, sprintID int) (int, error)
+	BurndownDataFunc              func(ctx context.Context, sprintID int, buckets int) ([]storage.BurndownPoint, error)
+	UserWorkloadsFunc             func(ctx context.Context) ([]storage.UserWorkload, error)
+	UserWorkloadFunc              func(ctx context.Context, userID int) (*storage.UserWorkload, error)
+	TaskStatsFunc                 func(ctx context.Context) (*storage.TaskStats, error)
+	LabelStatsFunc                func(ctx context.Context) ([]storage.LabelStat, error)
+	TopLabelsFunc                 func(ctx context.Context, n int) ([]storage.LabelStat, error)
+	RecordActivityFunc            func(ctx context.Context, e storage.ActivityEvent) error
+	ActivityFeedFunc              func(ctx context.Context, taskID int, limit int) ([]storage.ActivityEvent, error)
+	GlobalActivityFeedFunc        func(ctx context.Context, limit int) ([]storage.ActivityEvent, error)
+	AppendEventsFunc              func(ctx context.Context, streamID string, expectedSeq int64, events []storage.Event) error
+	LoadEventsFunc                func(ctx context.Context, streamID string, fromSeq int64) ([]storage.Event, error)
+	AppendOutboxFunc              func(ctx context.Context, tx storage.TxStorage, msgs []storage.OutboxMessage) error
+	PendingOutboxFunc             func(ctx context.Context, limit int) ([]storage.OutboxMessage, error)
+	MarkOutboxSentFunc            func(ctx context.Context, ids []int64) error
+	TasksClosedInRangeFunc        func(ctx context.Context, from, to int64) ([]storage.Task, error)
+	TasksUnassignedFunc           func(ctx context.Context) ([]storage.Task, error)
+	AssignTaskFunc                func(ctx context.Context, taskID, userID int) error
+	TaskByExternalIDFunc          func(ctx context.Context, system, externalID string) (*storage.Task, error)
+	SetNotificationPreferenceFunc func(ctx context.Context, p storage.NotificationPreference) error
+	GetNotificationPreferenceFunc func(ctx context.Context, userID int) (*storage.NotificationPreference, error)
+	UsersSubscribedToTaskFunc     func(ctx context.Context, taskID int, event string) ([]storage.User, error)
+	WatchTaskFunc                 func(ctx context.Context, userID, taskID int) error
+	UnwatchTaskFunc               func(ctx context.Context, userID, taskID int) error
+	WatchersOfTaskFunc            func(ctx context.Context, taskID int) ([]storage.User, error)
+	CreateAPIKeyFunc              func(ctx context.Context, k storage.APIKey) (string, error)
+	APIKeyByKeyFunc               func(ctx context.Context, raw string) (*storage.APIKey, error)
+	RevokeAPIKeyFunc              func(ctx context.Context, keyID int) error
+	APIKeysByUserFunc             func(ctx context.Context, userID int) ([]storage.APIKey, error)
+	AssignRoleFunc                func(ctx context.Context, userID int, role storage.Role) error
+	RoleOfUserFunc                func(ctx context.Context, userID int) (storage.Role, error)
+	UsersWithRoleFunc             func(ctx context.Context, role storage.Role) ([]storage.User, error)
+	ExportUserDataFunc            func(ctx context.Context, userID int) (*storage.UserDataExport, error)
+	EraseUserFunc                 func(ctx context.Context, userID int) error
+	StaleTasksFunc                func(ctx context.Context, staleAfterSeconds int64, now int64) ([]storage.Task, error)
+	MarkTaskStaleFunc             func(ctx context.Context, taskID int) error
+	SimilarTasksFunc              func(ctx context.Context, title string, threshold float64) ([]storage.Task, error)
+	AddTaskLinkFunc               func(ctx context.Context, l storage.TaskLinkRecord) (int, error)
+	TaskLinksFunc                 func(ctx context.Context, taskID int) ([]storage.TaskLinkRecord, error)
+	DeleteTaskLinkFunc            func(ctx context.Context, linkID int) error
+	RegisterWebhookFunc           func(ctx context.Context, w storage.Webhook) (int, error)
+	WebhooksFunc                  func(ctx context.Context) ([]storage.Webhook, error)
+	WebhookByIDFunc               func(ctx context.Context, webhookID int) (*storage.Webhook, error)
+	UpdateWebhookFunc             func(ctx context.Context, w storage.Webhook) error
+	DeleteWebhookFunc             func(ctx context.Context, webhookID int) error
+	LockTaskFunc                  func(ctx context.Context, taskID int) error
+	UnlockTaskFunc                func(ctx context.Context, taskID int) error
+	UpdateTasksAssigneeFunc       func(ctx context.Context, taskIDs []int, assigneeID int) (int64, error)
+	UpdateTasksStatusFunc         func(ctx context.Context, taskIDs []int, status storage.Status) (int64, error)
+	TasksByTagFunc                func(ctx context.Context, tag string) ([]storage.Task, error)
+	AllTagsFunc                   func(ctx context.Context) ([]string, error)
+	TasksByRankFunc               func(ctx context.Context) ([]storage.Task, error)
+	UpdateTaskRankFunc            func(ctx context.Context, taskID int, rank float64) error
+}
+
+// record сохраняет вызов метода.
+func (m *Mock) record(method string, args ...any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Calls = append(m.Calls, Call{Method: method, Args: args})
+}
+
+// CallsTo возвращает вызовы указанного метода.
+func (m *Mock) CallsTo(method string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var calls []Call
+	for _, c := range m.Calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Tasks вызывает TasksFunc.
+func (m *Mock) Tasks(ctx context.Context) ([]storage.Task, error) {
+	m.record("Tasks")
+	if m.TasksFunc != nil {
+		return m.TasksFunc(ctx)
+	}
+	return nil, nil
+}
+
+// TasksIter вызывает TasksIterFunc.
+func (m *Mock) TasksIter(ctx context.Context, fn func(t storage.Task) error) error {
+	m.record("TasksIter")
+	if m.TasksIterFunc != nil {
+		return m.TasksIterFunc(ctx, fn)
+	}
+	return nil
+}
+
+// TasksList вызывает TasksListFunc.
+func (m *Mock) TasksList(ctx context.Context, opts storage.ListOptions) ([]storage.Task, error) {
+	m.record("TasksList", opts)
+	if m.TasksListFunc != nil {
+		return m.TasksListFunc(ctx, opts)
+	}
+	return nil, nil
+}
+
+// TotalCount вызывает TotalCountFunc.
+func (m *Mock) TotalCount(ctx context.Context) (int, error) {
+	m.record("TotalCount")
+	if m.TotalCountFunc != nil {
+		return m.TotalCountFunc(ctx)
+	}
+	return 0, nil
+}
+
+// TasksAfter вызывает TasksAfterFunc.
+func (m *Mock) TasksAfter(ctx context.Context, afterID int, limit int) ([]storage.Task, error) {
+	m.record("TasksAfter", afterID, limit)
+	if m.TasksAfterFunc != nil {
+		return m.TasksAfterFunc(ctx, afterID, limit)
+	}
+	return nil, nil
+}
+
+// TaskCount вызывает TaskCountFunc.
+func (m *Mock) TaskCount(ctx context.Context) (int, error) {
+	m.record("TaskCount")
+	if m.TaskCountFunc != nil {
+		return m.TaskCountFunc(ctx)
+	}
+	return 0, nil
+}
+
+// TaskCountByAuthor вызывает TaskCountByAuthorFunc.
+func (m *Mock) TaskCountByAuthor(ctx context.Context, authorID int) (int, error) {
+	m.record("TaskCountByAuthor", authorID)
+	if m.TaskCountByAuthorFunc != nil {
+		return m.TaskCountByAuthorFunc(ctx, authorID)
+	}
+	return 0, nil
+}
+
+// TaskCountByLabel вызывает TaskCountByLabelFunc.
+func (m *Mock) TaskCountByLabel(ctx context.Context, labelID int) (int, error) {
+	m.record("TaskCountByLabel", labelID)
+	if m.TaskCountByLabelFunc != nil {
+		return m.TaskCountByLabelFunc(ctx, labelID)
+	}
+	return 0, nil
+}
+
+// TaskById вызывает TaskByIdFunc.
+func (m *Mock) TaskById(ctx context.Context, taskId int) (*storage.Task, error) {
+	m.record("TaskById", taskId)
+	if m.TaskByIdFunc != nil {
+		return m.TaskByIdFunc(ctx, taskId)
+	}
+	return nil, nil
+}
+
+// TasksByAuthor вызывает TasksByAuthorFunc.
+func (m *Mock) TasksByAuthor(ctx context.Context, authorId int) ([]storage.Task, error) {
+	m.record("TasksByAuthor", authorId)
+	if m.TasksByAuthorFunc != nil {
+		return m.TasksByAuthorFunc(ctx, authorId)
+	}
+	return nil, nil
+}
+
+// TasksByAssignee вызывает TasksByAssigneeFunc.
+func (m *Mock) TasksByAssignee(ctx context.Context, assigneeID int) ([]storage.Task, error) {
+	m.record("TasksByAssignee", assigneeID)
+	if m.TasksByAssigneeFunc != nil {
+		return m.TasksByAssigneeFunc(ctx, assigneeID)
+	}
+	return nil, nil
+}
+
+// TasksByLabel вызывает TasksByLabelFunc.
+func (m *Mock) TasksByLabel(ctx context.Context, labelId int) ([]storage.Task, error) {
+	m.record("TasksByLabel", labelId)
+	if m.TasksByLabelFunc != nil {
+		return m.TasksByLabelFunc(ctx, labelId)
+	}
+	return nil, nil
+}
+
+// FilterTasks вызывает FilterTasksFunc.
+func (m *Mock) FilterTasks(ctx context.Context, f storage.TaskFilter) ([]storage.Task, error) {
+	m.record("FilterTasks", f)
+	if m.FilterTasksFunc != nil {
+		return m.FilterTasksFunc(ctx, f)
+	}
+	return nil, nil
+}
+
+// AddTask вызывает AddTaskFunc.
+func (m *Mock) AddTask(ctx context.Context, task storage.Task) (int, error) {
+	m.record("AddTask", task)
+	if m.AddTaskFunc != nil {
+		return m.AddTaskFunc(ctx, task)
+	}
+	return 0, nil
+}
+
+// AddTasks вызывает AddTasksFunc.
+func (m *Mock) AddTasks(ctx context.Context, tasks []storage.Task) ([]int, error) {
+	m.record("AddTasks", tasks)
+	if m.AddTasksFunc != nil {
+		return m.AddTasksFunc(ctx, tasks)
+	}
+	return nil, nil
+}
+
+// AddTasksBatch вызывает AddTasksBatchFunc.
+func (m *Mock) AddTasksBatch(ctx context.Context, tasks []storage.Task) ([]int, error) {
+	m.record("AddTasksBatch", tasks)
+	if m.AddTasksBatchFunc != nil {
+		return m.AddTasksBatchFunc(ctx, tasks)
+	}
+	return nil, nil
+}
+
+// UpdateTask вызывает UpdateTaskFunc.
+func (m *Mock) UpdateTask(ctx context.Context, task storage.Task) error {
+	m.record("UpdateTask", task)
+	if m.UpdateTaskFunc != nil {
+		return m.UpdateTaskFunc(ctx, task)
+	}
+	return nil
+}
+
+// UpsertTask вызывает UpsertTaskFunc.
+func (m *Mock) UpsertTask(ctx context.Context, t storage.Task) (int, error) {
+	m.record("UpsertTask", t)
+	if m.UpsertTaskFunc != nil {
+		return m.UpsertTaskFunc(ctx, t)
+	}
+	return 0, nil
+}
+
+// PartialUpdateTask вызывает PartialUpdateTaskFunc.
+func (m *Mock) PartialUpdateTask(ctx context.Context, taskID int, patch storage.TaskPatch) error {
+	m.record("PartialUpdateTask", taskID, patch)
+	if m.PartialUpdateTaskFunc != nil {
+		return m.PartialUpdateTaskFunc(ctx, taskID, patch)
+	}
+	return nil
+}
+
+// DeleteTask вызывает DeleteTaskFunc.
+func (m *Mock) DeleteTask(ctx context.Context, taskId int) error {
+	m.record("DeleteTask", taskId)
+	if m.DeleteTaskFunc != nil {
+		return m.DeleteTaskFunc(ctx, taskId)
+	}
+	return nil
+}
+
+// DeleteTasks вызывает DeleteTasksFunc.
+func (m *Mock) DeleteTasks(ctx context.Context, taskIDs []int) error {
+	m.record("DeleteTasks", taskIDs)
+	if m.DeleteTasksFunc != nil {
+		return m.DeleteTasksFunc(ctx, taskIDs)
+	}
+	return nil
+}
+
+// TasksIncludingDeleted вызывает TasksIncludingDeletedFunc.
+func (m *Mock) TasksIncludingDeleted(ctx context.Context) ([]storage.Task, error) {
+	m.record("TasksIncludingDeleted")
+	if m.TasksIncludingDeletedFunc != nil {
+		return m.TasksIncludingDeletedFunc(ctx)
+	}
+	return nil, nil
+}
+
+// UndeleteTask вызывает UndeleteTaskFunc.
+func (m *Mock) UndeleteTask(ctx context.Context, taskID int) error {
+	m.record("UndeleteTask", taskID)
+	if m.UndeleteTaskFunc != nil {
+		return m.UndeleteTaskFunc(ctx, taskID)
+	}
+	return nil
+}
+
+// AddUser вызывает AddUserFunc.
+func (m *Mock) AddUser(ctx context.Context, u storage.User) (int, error) {
+	m.record("AddUser", u)
+	if m.AddUserFunc != nil {
+		return m.AddUserFunc(ctx, u)
+	}
+	return 0, nil
+}
+
+// Users вызывает UsersFunc.
+func (m *Mock) Users(ctx context.Context) ([]storage.User, error) {
+	m.record("Users")
+	if m.UsersFunc != nil {
+		return m.UsersFunc(ctx)
+	}
+	return nil, nil
+}
+
+// UserByID вызывает UserByIDFunc.
+func (m *Mock) UserByID(ctx context.Context, userID int) (*storage.User, error) {
+	m.record("UserByID", userID)
+	if m.UserByIDFunc != nil {
+		return m.UserByIDFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+// UpdateUser вызывает UpdateUserFunc.
+func (m *Mock) UpdateUser(ctx context.Context, u storage.User) error {
+	m.record("UpdateUser", u)
+	if m.UpdateUserFunc != nil {
+		return m.UpdateUserFunc(ctx, u)
+	}
+	return nil
+}
+
+// DeleteUser вызывает DeleteUserFunc.
+func (m *Mock) DeleteUser(ctx context.Context, userID int) error {
+	m.record("DeleteUser", userID)
+	if m.DeleteUserFunc != nil {
+		return m.DeleteUserFunc(ctx, userID)
+	}
+	return nil
+}
+
+// AddLabel вызывает AddLabelFunc.
+func (m *Mock) AddLabel(ctx context.Context, l storage.Label) (int, error) {
+	m.record("AddLabel", l)
+	if m.AddLabelFunc != nil {
+		return m.AddLabelFunc(ctx, l)
+	}
+	return 0, nil
+}
+
+// Labels вызывает LabelsFunc.
+func (m *Mock) Labels(ctx context.Context) ([]storage.Label, error) {
+	m.record("Labels")
+	if m.LabelsFunc != nil {
+		return m.LabelsFunc(ctx)
+	}
+	return nil, nil
+}
+
+// LabelByID вызывает LabelByIDFunc.
+func (m *Mock) LabelByID(ctx context.Context, labelID int) (*storage.Label, error) {
+	m.record("LabelByID", labelID)
+	if m.LabelByIDFunc != nil {
+		return m.LabelByIDFunc(ctx, labelID)
+	}
+	return nil, nil
+}
+
+// UpdateLabel вызывает UpdateLabelFunc.
+func (m *Mock) UpdateLabel(ctx context.Context, l storage.Label) error {
+	m.record("UpdateLabel", l)
+	if m.UpdateLabelFunc != nil {
+		return m.UpdateLabelFunc(ctx, l)
+	}
+	return nil
+}
+
+// DeleteLabel вызывает DeleteLabelFunc.
+func (m *Mock) DeleteLabel(ctx context.Context, labelID int) error {
+	m.record("DeleteLabel", labelID)
+	if m.DeleteLabelFunc != nil {
+		return m.DeleteLabelFunc(ctx, labelID)
+	}
+	return nil
+}
+
+// AssignLabel вызывает AssignLabelFunc.
+func (m *Mock) AssignLabel(ctx context.Context, taskID, labelID int) error {
+	m.record("AssignLabel", taskID, labelID)
+	if m.AssignLabelFunc != nil {
+		return m.AssignLabelFunc(ctx, taskID, labelID)
+	}
+	return nil
+}
+
+// RemoveLabel вызывает RemoveLabelFunc.
+func (m *Mock) RemoveLabel(ctx context.Context, taskID, labelID int) error {
+	m.record("RemoveLabel", taskID, labelID)
+	if m.RemoveLabelFunc != nil {
+		return m.RemoveLabelFunc(ctx, taskID, labelID)
+	}
+	return nil
+}
+
+// TasksByStatus вызывает TasksByStatusFunc.
+func (m *Mock) TasksByStatus(ctx context.Context, s storage.Status) ([]storage.Task, error) {
+	m.record("TasksByStatus", s)
+	if m.TasksByStatusFunc != nil {
+		return m.TasksByStatusFunc(ctx, s)
+	}
+	return nil, nil
+}
+
+// UpdateTaskStatus вызывает UpdateTaskStatusFunc.
+func (m *Mock) UpdateTaskStatus(ctx context.Context, taskID int, s storage.Status) error {
+	m.record("UpdateTaskStatus", taskID, s)
+	if m.UpdateTaskStatusFunc != nil {
+		return m.UpdateTaskStatusFunc(ctx, taskID, s)
+	}
+	return nil
+}
+
+// TasksByPriority вызывает TasksByPriorityFunc.
+func (m *Mock) TasksByPriority(ctx context.Context, p storage.Priority) ([]storage.Task, error) {
+	m.record("TasksByPriority", p)
+	if m.TasksByPriorityFunc != nil {
+		return m.TasksByPriorityFunc(ctx, p)
+	}
+	return nil, nil
+}
+
+// TasksOrderedByPriority вызывает TasksOrderedByPriorityFunc.
+func (m *Mock) TasksOrderedByPriority(ctx context.Context) ([]storage.Task, error) {
+	m.record("TasksOrderedByPriority")
+	if m.TasksOrderedByPriorityFunc != nil {
+		return m.TasksOrderedByPriorityFunc(ctx)
+	}
+	return nil, nil
+}
+
+// TasksOverdue вызывает TasksOverdueFunc.
+func (m *Mock) TasksOverdue(ctx context.Context, now int64) ([]storage.Task, error) {
+	m.record("TasksOverdue", now)
+	if m.TasksOverdueFunc != nil {
+		return m.TasksOverdueFunc(ctx, now)
+	}
+	return nil, nil
+}
+
+// TasksDueBetween вызывает TasksDueBetweenFunc.
+func (m *Mock) TasksDueBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	m.record("TasksDueBetween", from, to)
+	if m.TasksDueBetweenFunc != nil {
+		return m.TasksDueBetweenFunc(ctx, from, to)
+	}
+	return nil, nil
+}
+
+// AddComment вызывает AddCommentFunc.
+func (m *Mock) AddComment(ctx context.Context, c storage.Comment) (int, error) {
+	m.record("AddComment", c)
+	if m.AddCommentFunc != nil {
+		return m.AddCommentFunc(ctx, c)
+	}
+	return 0, nil
+}
+
+// CommentsByTask вызывает CommentsByTaskFunc.
+func (m *Mock) CommentsByTask(ctx context.Context, taskID int) ([]storage.Comment, error) {
+	m.record("CommentsByTask", taskID)
+	if m.CommentsByTaskFunc != nil {
+		return m.CommentsByTaskFunc(ctx, taskID)
+	}
+	return nil, nil
+}
+
+// UpdateComment вызывает UpdateCommentFunc.
+func (m *Mock) UpdateComment(ctx context.Context, c storage.Comment) error {
+	m.record("UpdateComment", c)
+	if m.UpdateCommentFunc != nil {
+		return m.UpdateCommentFunc(ctx, c)
+	}
+	return nil
+}
+
+// DeleteComment вызывает DeleteCommentFunc.
+func (m *Mock) DeleteComment(ctx context.Context, commentID int) error {
+	m.record("DeleteComment", commentID)
+	if m.DeleteCommentFunc != nil {
+		return m.DeleteCommentFunc(ctx, commentID)
+	}
+	return nil
+}
+
+// LogTime вызывает LogTimeFunc.
+func (m *Mock) LogTime(ctx context.Context, e storage.TimeEntry) (int, error) {
+	m.record("LogTime", e)
+	if m.LogTimeFunc != nil {
+		return m.LogTimeFunc(ctx, e)
+	}
+	return 0, nil
+}
+
+// TimeEntriesByTask вызывает TimeEntriesByTaskFunc.
+func (m *Mock) TimeEntriesByTask(ctx context.Context, taskID int) ([]storage.TimeEntry, error) {
+	m.record("TimeEntriesByTask", taskID)
+	if m.TimeEntriesByTaskFunc != nil {
+		return m.TimeEntriesByTaskFunc(ctx, taskID)
+	}
+	return nil, nil
+}
+
+// TimeEntriesByUser вызывает TimeEntriesByUserFunc.
+func (m *Mock) TimeEntriesByUser(ctx context.Context, userID int) ([]storage.TimeEntry, error) {
+	m.record("TimeEntriesByUser", userID)
+	if m.TimeEntriesByUserFunc != nil {
+		return m.TimeEntriesByUserFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+// TotalMinutesByTask вызывает TotalMinutesByTaskFunc.
+func (m *Mock) TotalMinutesByTask(ctx context.Context, taskID int) (int, error) {
+	m.record("TotalMinutesByTask", taskID)
+	if m.TotalMinutesByTaskFunc != nil {
+		return m.TotalMinutesByTaskFunc(ctx, taskID)
+	}
+	return 0, nil
+}
+
+// AddChecklistItem вызывает AddChecklistItemFunc.
+func (m *Mock) AddChecklistItem(ctx context.Context, item storage.ChecklistItem) (int, error) {
+	m.record("AddChecklistItem", item)
+	if m.AddChecklistItemFunc != nil {
+		return m.AddChecklistItemFunc(ctx, item)
+	}
+	return 0, nil
+}
+
+// ChecklistByTask вызывает ChecklistByTaskFunc.
+func (m *Mock) ChecklistByTask(ctx context.Context, taskID int) ([]storage.ChecklistItem, error) {
+	m.record("ChecklistByTask", taskID)
+	if m.ChecklistByTaskFunc != nil {
+		return m.ChecklistByTaskFunc(ctx, taskID)
+	}
+	return nil, nil
+}
+
+// UpdateChecklistItem вызывает UpdateChecklistItemFunc.
+func (m *Mock) UpdateChecklistItem(ctx context.Context, item storage.ChecklistItem) error {
+	m.record("UpdateChecklistItem", item)
+	if m.UpdateChecklistItemFunc != nil {
+		return m.UpdateChecklistItemFunc(ctx, item)
+	}
+	return nil
+}
+
+// DeleteChecklistItem вызывает DeleteChecklistItemFunc.
+func (m *Mock) DeleteChecklistItem(ctx context.Context, itemID int) error {
+	m.record("DeleteChecklistItem", itemID)
+	if m.DeleteChecklistItemFunc != nil {
+		return m.DeleteChecklistItemFunc(ctx, itemID)
+	}
+	return nil
+}
+
+// ReorderChecklist вызывает ReorderChecklistFunc.
+func (m *Mock) ReorderChecklist(ctx context.Context, taskID int, orderedIDs []int) error {
+	m.record("ReorderChecklist", taskID, orderedIDs)
+	if m.ReorderChecklistFunc != nil {
+		return m.ReorderChecklistFunc(ctx, taskID, orderedIDs)
+	}
+	return nil
+}
+
+// AddProject вызывает AddProjectFunc.
+func (m *Mock) AddProject(ctx context.Context, p storage.Project) (int, error) {
+	m.record("AddProject", p)
+	if m.AddProjectFunc != nil {
+		return m.AddProjectFunc(ctx, p)
+	}
+	return 0, nil
+}
+
+// Projects вызывает ProjectsFunc.
+func (m *Mock) Projects(ctx context.Context) ([]storage.Project, error) {
+	m.record("Projects")
+	if m.ProjectsFunc != nil {
+		return m.ProjectsFunc(ctx)
+	}
+	return nil, nil
+}
+
+// ProjectByID вызывает ProjectByIDFunc.
+func (m *Mock) ProjectByID(ctx context.Context, projectID int) (*storage.Project, error) {
+	m.record("ProjectByID", projectID)
+	if m.ProjectByIDFunc != nil {
+		return m.ProjectByIDFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+
+// UpdateProject вызывает UpdateProjectFunc.
+func (m *Mock) UpdateProject(ctx context.Context, p storage.Project) error {
+	m.record("UpdateProject", p)
+	if m.UpdateProjectFunc != nil {
+		return m.UpdateProjectFunc(ctx, p)
+	}
+	return nil
+}
+
+// DeleteProject вызывает DeleteProjectFunc.
+func (m *Mock) DeleteProject(ctx context.Context, projectID int, cascadeDelete bool) error {
+	m.record("DeleteProject", projectID, cascadeDelete)
+	if m.DeleteProjectFunc != nil {
+		return m.DeleteProjectFunc(ctx, projectID, cascadeDelete)
+	}
+	return nil
+}
+
+// TasksByProject вызывает TasksByProjectFunc.
+func (m *Mock) TasksByProject(ctx context.Context, projectID int) ([]storage.Task, error) {
+	m.record("TasksByProject", projectID)
+	if m.TasksByProjectFunc != nil {
+		return m.TasksByProjectFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+
+// AddDependency вызывает AddDependencyFunc.
+func (m *Mock) AddDependency(ctx context.Context, taskID, dependsOnID int) error {
+	m.record("AddDependency", taskID, dependsOnID)
+	if m.AddDependencyFunc != nil {
+		return m.AddDependencyFunc(ctx, taskID, dependsOnID)
+	}
+	return nil
+}
+
+// RemoveDependency вызывает RemoveDependencyFunc.
+func (m *Mock) RemoveDependency(ctx context.Context, taskID, dependsOnID int) error {
+	m.record("RemoveDependency", taskID, dependsOnID)
+	if m.RemoveDependencyFunc != nil {
+		return m.RemoveDependencyFunc(ctx, taskID, dependsOnID)
+	}
+	return nil
+}
+
+// DependenciesOf вызывает DependenciesOfFunc.
+func (m *Mock) DependenciesOf(ctx context.Context, taskID int) ([]storage.Task, error) {
+	m.record("DependenciesOf", taskID)
+	if m.DependenciesOfFunc != nil {
+		return m.DependenciesOfFunc(ctx, taskID)
+	}
+	return nil, nil
+}
+
+// BlockedBy вызывает BlockedByFunc.
+func (m *Mock) BlockedBy(ctx context.Context, taskID int) ([]storage.Task, error) {
+	m.record("BlockedBy", taskID)
+	if m.BlockedByFunc != nil {
+		return m.BlockedByFunc(ctx, taskID)
+	}
+	return nil, nil
+}
+
+// AddTaskWithLabels вызывает AddTaskWithLabelsFunc.
+func (m *Mock) AddTaskWithLabels(ctx context.Context, t storage.Task, labelIDs []int) (int, error) {
+	m.record("AddTaskWithLabels", t, labelIDs)
+	if m.AddTaskWithLabelsFunc != nil {
+		return m.AddTaskWithLabelsFunc(ctx, t, labelIDs)
+	}
+	return 0, nil
+}
+
+// ImportTasks вызывает ImportTasksFunc.
+func (m *Mock) ImportTasks(ctx context.Context, tasks []storage.Task) error {
+	m.record("ImportTasks", tasks)
+	if m.ImportTasksFunc != nil {
+		return m.ImportTasksFunc(ctx, tasks)
+	}
+	return nil
+}
+
+// TasksWithLabels вызывает TasksWithLabelsFunc.
+func (m *Mock) TasksWithLabels(ctx context.Context) ([]storage.TaskWithLabels, error) {
+	m.record("TasksWithLabels")
+	if m.TasksWithLabelsFunc != nil {
+		return m.TasksWithLabelsFunc(ctx)
+	}
+	return nil, nil
+}
+
+// TasksWithUsers вызывает TasksWithUsersFunc.
+func (m *Mock) TasksWithUsers(ctx context.Context) ([]storage.TaskWithUsers, error) {
+	m.record("TasksWithUsers")
+	if m.TasksWithUsersFunc != nil {
+		return m.TasksWithUsersFunc(ctx)
+	}
+	return nil, nil
+}
+
+// BeginTx вызывает BeginTxFunc.
+func (m *Mock) BeginTx(ctx context.Context) (storage.TxStorage, error) {
+	m.record("BeginTx")
+	if m.BeginTxFunc != nil {
+		return m.BeginTxFunc(ctx)
+	}
+	return nil, nil
+}
+
+// AuditLog вызывает AuditLogFunc.
+func (m *Mock) AuditLog(ctx context.Context, taskID int) ([]storage.AuditEntry, error) {
+	m.record("AuditLog", taskID)
+	if m.AuditLogFunc != nil {
+		return m.AuditLogFunc(ctx, taskID)
+	}
+	return nil, nil
+}
+
+// RecordChange вызывает RecordChangeFunc.
+func (m *Mock) RecordChange(ctx context.Context, e storage.AuditEntry) error {
+	m.record("RecordChange", e)
+	if m.RecordChangeFunc != nil {
+		return m.RecordChangeFunc(ctx, e)
+	}
+	return nil
+}
+
+// SearchTasks вызывает SearchTasksFunc.
+func (m *Mock) SearchTasks(ctx context.Context, query string, limit int) ([]storage.Task, error) {
+	m.record("SearchTasks", query, limit)
+	if m.SearchTasksFunc != nil {
+		return m.SearchTasksFunc(ctx, query, limit)
+	}
+	return nil, nil
+}
+
+// TasksByMetadataKey вызывает TasksByMetadataKeyFunc.
+func (m *Mock) TasksByMetadataKey(ctx context.Context, key, value string) ([]storage.Task, error) {
+	m.record("TasksByMetadataKey", key, value)
+	if m.TasksByMetadataKeyFunc != nil {
+		return m.TasksByMetadataKeyFunc(ctx, key, value)
+	}
+	return nil, nil
+}
+
+// AssignmentHistory вызывает AssignmentHistoryFunc.
+func (m *Mock) AssignmentHistory(ctx context.Context, taskID int) ([]storage.AssignmentEvent, error) {
+	m.record("AssignmentHistory", taskID)
+	if m.AssignmentHistoryFunc != nil {
+		return m.AssignmentHistoryFunc(ctx, taskID)
+	}
+	return nil, nil
+}
+
+// RecordAssignment вызывает RecordAssignmentFunc.
+func (m *Mock) RecordAssignment(ctx context.Context, e storage.AssignmentEvent) error {
+	m.record("RecordAssignment", e)
+	if m.RecordAssignmentFunc != nil {
+		return m.RecordAssignmentFunc(ctx, e)
+	}
+	return nil
+}
+
+// AddRecurrenceRule вызывает AddRecurrenceRuleFunc.
+func (m *Mock) AddRecurrenceRule(ctx context.Context, rule storage.RecurrenceRule) error {
+	m.record("AddRecurrenceRule", rule)
+	if m.AddRecurrenceRuleFunc != nil {
+		return m.AddRecurrenceRuleFunc(ctx, rule)
+	}
+	return nil
+}
+
+// RecurrenceRuleByTask вызывает RecurrenceRuleByTaskFunc.
+func (m *Mock) RecurrenceRuleByTask(ctx context.Context, taskID int) (*storage.RecurrenceRule, error) {
+	m.record("RecurrenceRuleByTask", taskID)
+	if m.RecurrenceRuleByTaskFunc != nil {
+		return m.RecurrenceRuleByTaskFunc(ctx, taskID)
+	}
+	return nil, nil
+}
+
+// UpdateRecurrenceRule вызывает UpdateRecurrenceRuleFunc.
+func (m *Mock) UpdateRecurrenceRule(ctx context.Context, rule storage.RecurrenceRule) error {
+	m.record("UpdateRecurrenceRule", rule)
+	if m.UpdateRecurrenceRuleFunc != nil {
+		return m.UpdateRecurrenceRuleFunc(ctx, rule)
+	}
+	return nil
+}
+
+// DeleteRecurrenceRule вызывает DeleteRecurrenceRuleFunc.
+func (m *Mock) DeleteRecurrenceRule(ctx context.Context, taskID int) error {
+	m.record("DeleteRecurrenceRule", taskID)
+	if m.DeleteRecurrenceRuleFunc != nil {
+		return m.DeleteRecurrenceRuleFunc(ctx, taskID)
+	}
+	return nil
+}
+
+// DueRecurrences вызывает DueRecurrencesFunc.
+func (m *Mock) DueRecurrences(ctx context.Context, now int64) ([]storage.RecurrenceRule, error) {
+	m.record("DueRecurrences", now)
+	if m.DueRecurrencesFunc != nil {
+		return m.DueRecurrencesFunc(ctx, now)
+	}
+	return nil, nil
+}
+
+// SpawnRecurringTask вызывает SpawnRecurringTaskFunc.
+func (m *Mock) SpawnRecurringTask(ctx context.Context, rule storage.RecurrenceRule) (int, error) {
+	m.record("SpawnRecurringTask", rule)
+	if m.SpawnRecurringTaskFunc != nil {
+		return m.SpawnRecurringTaskFunc(ctx, rule)
+	}
+	return 0, nil
+}
+
+// ArchiveTask вызывает ArchiveTaskFunc.
+func (m *Mock) ArchiveTask(ctx context.Context, taskID int) error {
+	m.record("ArchiveTask", taskID)
+	if m.ArchiveTaskFunc != nil {
+		return m.ArchiveTaskFunc(ctx, taskID)
+	}
+	return nil
+}
+
+// ArchivedTasks вызывает ArchivedTasksFunc.
+func (m *Mock) ArchivedTasks(ctx context.Context) ([]storage.Task, error) {
+	m.record("ArchivedTasks")
+	if m.ArchivedTasksFunc != nil {
+		return m.ArchivedTasksFunc(ctx)
+	}
+	return nil, nil
+}
+
+// UnarchiveTask вызывает UnarchiveTaskFunc.
+func (m *Mock) UnarchiveTask(ctx context.Context, taskID int) error {
+	m.record("UnarchiveTask", taskID)
+	if m.UnarchiveTaskFunc != nil {
+		return m.UnarchiveTaskFunc(ctx, taskID)
+	}
+	return nil
+}
+
+// TasksByLabels вызывает TasksByLabelsFunc.
+func (m *Mock) TasksByLabels(ctx context.Context, labelIDs []int, mode storage.LabelFilterMode) ([]storage.Task, error) {
+	m.record("TasksByLabels", labelIDs, mode)
+	if m.TasksByLabelsFunc != nil {
+		return m.TasksByLabelsFunc(ctx, labelIDs, mode)
+	}
+	return nil, nil
+}
+
+// TasksOpenedBetween вызывает TasksOpenedBetweenFunc.
+func (m *Mock) TasksOpenedBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	m.record("TasksOpenedBetween", from, to)
+	if m.TasksOpenedBetweenFunc != nil {
+		return m.TasksOpenedBetweenFunc(ctx, from, to)
+	}
+	return nil, nil
+}
+
+// TasksClosedBetween вызывает TasksClosedBetweenFunc.
+func (m *Mock) TasksClosedBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	m.record("TasksClosedBetween", from, to)
+	if m.TasksClosedBetweenFunc != nil {
+		return m.TasksClosedBetweenFunc(ctx, from, to)
+	}
+	return nil, nil
+}
+
+// AddSprint вызывает AddSprintFunc.
+func (m *Mock) AddSprint(ctx context.Context, sp storage.Sprint) (int, error) {
+	m.record("AddSprint", sp)
+	if m.AddSprintFunc != nil {
+		return m.AddSprintFunc(ctx, sp)
+	}
+	return 0, nil
+}
+
+// SprintByID вызывает SprintByIDFunc.
+func (m *Mock) SprintByID(ctx context.Context, sprintID int) (*storage.Sprint, error) {
+	m.record("SprintByID", sprintID)
+	if m.SprintByIDFunc != nil {
+		return m.SprintByIDFunc(ctx, sprintID)
+	}
+	return nil, nil
+}
+
+// Sprints вызывает SprintsFunc.
+func (m *Mock) Sprints(ctx context.Context) ([]storage.Sprint, error) {
+	m.record("Sprints")
+	if m.SprintsFunc != nil {
+		return m.SprintsFunc(ctx)
+	}
+	return nil, nil
+}
+
+// UpdateSprint вызывает UpdateSprintFunc.
+func (m *Mock) UpdateSprint(ctx context.Context, sp storage.Sprint) error {
+	m.record("UpdateSprint", sp)
+	if m.UpdateSprintFunc != nil {
+		return m.UpdateSprintFunc(ctx, sp)
+	}
+	return nil
+}
+
+// DeleteSprint вызывает DeleteSprintFunc.
+func (m *Mock) DeleteSprint(ctx context.Context, sprintID int) error {
+	m.record("DeleteSprint", sprintID)
+	if m.DeleteSprintFunc != nil {
+		return m.DeleteSprintFunc(ctx, sprintID)
+	}
+	return nil
+}
+
+// AssignTaskToSprint вызывает AssignTaskToSprintFunc.
+func (m *Mock) AssignTaskToSprint(ctx context.Context, taskID, sprintID int) error {
+	m.record("AssignTaskToSprint", taskID, sprintID)
+	if m.AssignTaskToSprintFunc != nil {
+		return m.AssignTaskToSprintFunc(ctx, taskID, sprintID)
+	}
+	return nil
+}
+
+// RemoveTaskFromSprint вызывает RemoveTaskFromSprintFunc.
+func (m *Mock) RemoveTaskFromSprint(ctx context.Context, taskID, sprintID int) error {
+	m.record("RemoveTaskFromSprint", taskID, sprintID)
+	if m.RemoveTaskFromSprintFunc != nil {
+		return m.RemoveTaskFromSprintFunc(ctx, taskID, sprintID)
+	}
+	return nil
+}
+
+// TasksBySprint вызывает TasksBySprintFunc.
+func (m *Mock) TasksBySprint(ctx context.Context, sprintID int) ([]storage.Task, error) {
+	m.record("TasksBySprint", sprintID)
+	if m.TasksBySprintFunc != nil {
+		return m.TasksBySprintFunc(ctx, sprintID)
+	}
+	return nil, nil
+}
+
+// SprintVelocity вызывает SprintVelocityFunc.
+func (m *Mock) SprintVelocity(ctx context.Context, sprintID int) (int, error) {
+	m.record("SprintVelocity", sprintID)
+	if m.SprintVelocityFunc != nil {
+		return m.SprintVelocityFunc(ctx, sprintID)
+	}
+	return 0, nil
+}
+
+// BurndownData вызывает BurndownDataFunc.
+func (m *Mock) BurndownData(ctx context.Context, sprintID int, buckets int) ([]storage.BurndownPoint, error) {
+	m.record("BurndownData", sprintID, buckets)
+	if m.BurndownDataFunc != nil {
+		return m.BurndownDataFunc(ctx, sprintID, buckets)
+	}
+	return nil, nil
+}
+
+// UserWorkloads вызывает UserWorkloadsFunc.
+func (m *Mock) UserWorkloads(ctx context.Context) ([]storage.UserWorkload, error) {
+	m.record("UserWorkloads")
+	if m.UserWorkloadsFunc != nil {
+		return m.UserWorkloadsFunc(ctx)
+	}
+	return nil, nil
+}
+
+// UserWorkload вызывает UserWorkloadFunc.
+func (m *Mock) UserWorkload(ctx context.Context, userID int) (*storage.UserWorkload, error) {
+	m.record("UserWorkload", userID)
+	if m.UserWorkloadFunc != nil {
+		return m.UserWorkloadFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+// TaskStats вызывает TaskStatsFunc.
+func (m *Mock) TaskStats(ctx context.Context) (*storage.TaskStats, error) {
+	m.record("TaskStats")
+	if m.TaskStatsFunc != nil {
+		return m.TaskStatsFunc(ctx)
+	}
+	return nil, nil
+}
+
+// LabelStats вызывает LabelStatsFunc.
+func (m *Mock) LabelStats(ctx context.Context) ([]storage.LabelStat, error) {
+	m.record("LabelStats")
+	if m.LabelStatsFunc != nil {
+		return m.LabelStatsFunc(ctx)
+	}
+	return nil, nil
+}
+
+// TopLabels вызывает TopLabelsFunc.
+func (m *Mock) TopLabels(ctx context.Context, n int) ([]storage.LabelStat, error) {
+	m.record("TopLabels", n)
+	if m.TopLabelsFunc != nil {
+		return m.TopLabelsFunc(ctx, n)
+	}
+	return nil, nil
+}
+
+// RecordActivity вызывает RecordActivityFunc.
+func (m *Mock) RecordActivity(ctx context.Context, e storage.ActivityEvent) error {
+	m.record("RecordActivity", e)
+	if m.RecordActivityFunc != nil {
+		return m.RecordActivityFunc(ctx, e)
+	}
+	return nil
+}
+
+// ActivityFeed вызывает ActivityFeedFunc.
+func (m *Mock) ActivityFeed(ctx context.Context, taskID int, limit int) ([]storage.ActivityEvent, error) {
+	m.record("ActivityFeed", taskID, limit)
+	if m.ActivityFeedFunc != nil {
+		return m.ActivityFeedFunc(ctx, taskID, limit)
+	}
+	return nil, nil
+}
+
+// GlobalActivityFeed вызывает GlobalActivityFeedFunc.
+func (m *Mock) GlobalActivityFeed(ctx context.Context, limit int) ([]storage.ActivityEvent, error) {
+	m.record("GlobalActivityFeed", limit)
+	if m.GlobalActivityFeedFunc != nil {
+		return m.GlobalActivityFeedFunc(ctx, limit)
+	}
+	return nil, nil
+}
+
+// AppendEvents вызывает AppendEventsFunc.
+func (m *Mock) AppendEvents(ctx context.Context, streamID string, expectedSeq int64, events []storage.Event) error {
+	m.record("AppendEvents", streamID, expectedSeq, events)
+	if m.AppendEventsFunc != nil {
+		return m.AppendEventsFunc(ctx, streamID, expectedSeq, events)
+	}
+	return nil
+}
+
+// LoadEvents вызывает LoadEventsFunc.
+func (m *Mock) LoadEvents(ctx context.Context, streamID string, fromSeq int64) ([]storage.Event, error) {
+	m.record("LoadEvents", streamID, fromSeq)
+	if m.LoadEventsFunc != nil {
+		return m.LoadEventsFunc(ctx, streamID, fromSeq)
+	}
+	return nil, nil
+}
+
+// AppendOutbox вызывает AppendOutboxFunc.
+func (m *Mock) AppendOutbox(ctx context.Context, tx storage.TxStorage, msgs []storage.OutboxMessage) error {
+	m.record("AppendOutbox", tx, msgs)
+	if m.AppendOutboxFunc != nil {
+		return m.AppendOutboxFunc(ctx, tx, msgs)
+	}
+	return nil
+}
+
+// PendingOutbox вызывает PendingOutboxFunc.
+func (m *Mock) PendingOutbox(ctx context.Context, limit int) ([]storage.OutboxMessage, error) {
+	m.record("PendingOutbox", limit)
+	if m.PendingOutboxFunc != nil {
+		return m.PendingOutboxFunc(ctx, limit)
+	}
+	return nil, nil
+}
+
+// MarkOutboxSent вызывает MarkOutboxSentFunc.
+func (m *Mock) MarkOutboxSent(ctx context.Context, ids []int64) error {
+	m.record("MarkOutboxSent", ids)
+	if m.MarkOutboxSentFunc != nil {
+		return m.MarkOutboxSentFunc(ctx, ids)
+	}
+	return nil
+}
+
+// TasksClosedInRange вызывает TasksClosedInRangeFunc.
+func (m *Mock) TasksClosedInRange(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	m.record("TasksClosedInRange", from, to)
+	if m.TasksClosedInRangeFunc != nil {
+		return m.TasksClosedInRangeFunc(ctx, from, to)
+	}
+	return nil, nil
+}
+
+// TasksUnassigned вызывает TasksUnassignedFunc.
+func (m *Mock) TasksUnassigned(ctx context.Context) ([]storage.Task, error) {
+	m.record("TasksUnassigned")
+	if m.TasksUnassignedFunc != nil {
+		return m.TasksUnassignedFunc(ctx)
+	}
+	return nil, nil
+}
+
+// AssignTask вызывает AssignTaskFunc.
+func (m *Mock) AssignTask(ctx context.Context, taskID, userID int) error {
+	m.record("AssignTask", taskID, userID)
+	if m.AssignTaskFunc != nil {
+		return m.AssignTaskFunc(ctx, taskID, userID)
+	}
+	return nil
+}
+
+// TaskByExternalID вызывает TaskByExternalIDFunc.
+func (m *Mock) TaskByExternalID(ctx context.Context, system, externalID string) (*storage.Task, error) {
+	m.record("TaskByExternalID", system, externalID)
+	if m.TaskByExternalIDFunc != nil {
+		return m.TaskByExternalIDFunc(ctx, system, externalID)
+	}
+	return nil, nil
+}
+
+// SetNotificationPreference вызывает SetNotificationPreferenceFunc.
+func (m *Mock) SetNotificationPreference(ctx context.Context, p storage.NotificationPreference) error {
+	m.record("SetNotificationPreference", p)
+	if m.SetNotificationPreferenceFunc != nil {
+		return m.SetNotificationPreferenceFunc(ctx, p)
+	}
+	return nil
+}
+
+// GetNotificationPreference вызывает GetNotificationPreferenceFunc.
+func (m *Mock) GetNotificationPreference(ctx context.Context, userID int) (*storage.NotificationPreference, error) {
+	m.record("GetNotificationPreference", userID)
+	if m.GetNotificationPreferenceFunc != nil {
+		return m.GetNotificationPreferenceFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+// UsersSubscribedToTask вызывает UsersSubscribedToTaskFunc.
+func (m *Mock) UsersSubscribedToTask(ctx context.Context, taskID int, event string) ([]storage.User, error) {
+	m.record("UsersSubscribedToTask", taskID, event)
+	if m.UsersSubscribedToTaskFunc != nil {
+		return m.UsersSubscribedToTaskFunc(ctx, taskID, event)
+	}
+	return nil, nil
+}
+
+// WatchTask вызывает WatchTaskFunc.
+func (m *Mock) WatchTask(ctx context.Context, userID, taskID int) error {
+	m.record("WatchTask", userID, taskID)
+	if m.WatchTaskFunc != nil {
+		return m.WatchTaskFunc(ctx, userID, taskID)
+	}
+	return nil
+}
+
+// UnwatchTask вызывает UnwatchTaskFunc.
+func (m *Mock) UnwatchTask(ctx context.Context, userID, taskID int) error {
+	m.record("UnwatchTask", userID, taskID)
+	if m.UnwatchTaskFunc != nil {
+		return m.UnwatchTaskFunc(ctx, userID, taskID)
+	}
+	return nil
+}
+
+// WatchersOfTask вызывает WatchersOfTaskFunc.
+func (m *Mock) WatchersOfTask(ctx context.Context, taskID int) ([]storage.User, error) {
+	m.record("WatchersOfTask", taskID)
+	if m.WatchersOfTaskFunc != nil {
+		return m.WatchersOfTaskFunc(ctx, taskID)
+	}
+	return nil, nil
+}
+
+// CreateAPIKey вызывает CreateAPIKeyFunc.
+func (m *Mock) CreateAPIKey(ctx context.Context, k storage.APIKey) (string, error) {
+	m.record("CreateAPIKey", k)
+	if m.CreateAPIKeyFunc != nil {
+		return m.CreateAPIKeyFunc(ctx, k)
+	}
+	return "", nil
+}
+
+// APIKeyByKey вызывает APIKeyByKeyFunc.
+func (m *Mock) APIKeyByKey(ctx context.Context, raw string) (*storage.APIKey, error) {
+	m.record("APIKeyByKey", raw)
+	if m.APIKeyByKeyFunc != nil {
+		return m.APIKeyByKeyFunc(ctx, raw)
+	}
+	return nil, nil
+}
+
+// RevokeAPIKey вызывает RevokeAPIKeyFunc.
+func (m *Mock) RevokeAPIKey(ctx context.Context, keyID int) error {
+	m.record("RevokeAPIKey", keyID)
+	if m.RevokeAPIKeyFunc != nil {
+		return m.RevokeAPIKeyFunc(ctx, keyID)
+	}
+	return nil
+}
+
+// APIKeysByUser вызывает APIKeysByUserFunc.
+func (m *Mock) APIKeysByUser(ctx context.Context, userID int) ([]storage.APIKey, error) {
+	m.record("APIKeysByUser", userID)
+	if m.APIKeysByUserFunc != nil {
+		return m.APIKeysByUserFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+// AssignRole вызывает AssignRoleFunc.
+func (m *Mock) AssignRole(ctx context.Context, userID int, role storage.Role) error {
+	m.record("AssignRole", userID, role)
+	if m.AssignRoleFunc != nil {
+		return m.AssignRoleFunc(ctx, userID, role)
+	}
+	return nil
+}
+
+// RoleOfUser вызывает RoleOfUserFunc.
+func (m *Mock) RoleOfUser(ctx context.Context, userID int) (storage.Role, error) {
+	m.record("RoleOfUser", userID)
+	if m.RoleOfUserFunc != nil {
+		return m.RoleOfUserFunc(ctx, userID)
+	}
+	return 0, nil
+}
+
+// UsersWithRole вызывает UsersWithRoleFunc.
+func (m *Mock) UsersWithRole(ctx context.Context, role storage.Role) ([]storage.User, error) {
+	m.record("UsersWithRole", role)
+	if m.UsersWithRoleFunc != nil {
+		return m.UsersWithRoleFunc(ctx, role)
+	}
+	return nil, nil
+}
+
+// ExportUserData вызывает ExportUserDataFunc.
+func (m *Mock) ExportUserData(ctx context.Context, userID int) (*storage.UserDataExport, error) {
+	m.record("ExportUserData", userID)
+	if m.ExportUserDataFunc != nil {
+		return m.ExportUserDataFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+// EraseUser вызывает EraseUserFunc.
+func (m *Mock) EraseUser(ctx context.Context, userID int) error {
+	m.record("EraseUser", userID)
+	if m.EraseUserFunc != nil {
+		return m.EraseUserFunc(ctx, userID)
+	}
+	return nil
+}
+
+// StaleTasks вызывает StaleTasksFunc.
+func (m *Mock) StaleTasks(ctx context.Context, staleAfterSeconds int64, now int64) ([]storage.Task, error) {
+	m.record("StaleTasks", staleAfterSeconds, now)
+	if m.StaleTasksFunc != nil {
+		return m.StaleTasksFunc(ctx, staleAfterSeconds, now)
+	}
+	return nil, nil
+}
+
+// MarkTaskStale вызывает MarkTaskStaleFunc.
+func (m *Mock) MarkTaskStale(ctx context.Context, taskID int) error {
+	m.record("MarkTaskStale", taskID)
+	if m.MarkTaskStaleFunc != nil {
+		return m.MarkTaskStaleFunc(ctx, taskID)
+	}
+	return nil
+}
+
+// SimilarTasks вызывает SimilarTasksFunc.
+func (m *Mock) SimilarTasks(ctx context.Context, title string, threshold float64) ([]storage.Task, error) {
+	m.record("SimilarTasks", title, threshold)
+	if m.SimilarTasksFunc != nil {
+		return m.SimilarTasksFunc(ctx, title, threshold)
+	}
+	return nil, nil
+}
+
+// AddTaskLink вызывает AddTaskLinkFunc.
+func (m *Mock) AddTaskLink(ctx context.Context, l storage.TaskLinkRecord) (int, error) {
+	m.record("AddTaskLink", l)
+	if m.AddTaskLinkFunc != nil {
+		return m.AddTaskLinkFunc(ctx, l)
+	}
+	return 0, nil
+}
+
+// TaskLinks вызывает TaskLinksFunc.
+func (m *Mock) TaskLinks(ctx context.Context, taskID int) ([]storage.TaskLinkRecord, error) {
+	m.record("TaskLinks", taskID)
+	if m.TaskLinksFunc != nil {
+		return m.TaskLinksFunc(ctx, taskID)
+	}
+	return nil, nil
+}
+
+// DeleteTaskLink вызывает DeleteTaskLinkFunc.
+func (m *Mock) DeleteTaskLink(ctx context.Context, linkID int) error {
+	m.record("DeleteTaskLink", linkID)
+	if m.DeleteTaskLinkFunc != nil {
+		return m.DeleteTaskLinkFunc(ctx, linkID)
+	}
+	return nil
+}
+
+// RegisterWebhook вызывает RegisterWebhookFunc.
+func (m *Mock) RegisterWebhook(ctx context.Context, w storage.Webhook) (int, error) {
+	m.record("RegisterWebhook", w)
+	if m.RegisterWebhookFunc != nil {
+		return m.RegisterWebhookFunc(ctx, w)
+	}
+	return 0, nil
+}
+
+// Webhooks вызывает WebhooksFunc.
+func (m *Mock) Webhooks(ctx context.Context) ([]storage.Webhook, error) {
+	m.record("Webhooks")
+	if m.WebhooksFunc != nil {
+		return m.WebhooksFunc(ctx)
+	}
+	return nil, nil
+}
+
+// WebhookByID вызывает WebhookByIDFunc.
+func (m *Mock) WebhookByID(ctx context.Context, webhookID int) (*storage.Webhook, error) {
+	m.record("WebhookByID", webhookID)
+	if m.WebhookByIDFunc != nil {
+		return m.WebhookByIDFunc(ctx, webhookID)
+	}
+	return nil, nil
+}
+
+// UpdateWebhook вызывает UpdateWebhookFunc.
+func (m *Mock) UpdateWebhook(ctx context.Context, w storage.Webhook) error {
+	m.record("UpdateWebhook", w)
+	if m.UpdateWebhookFunc != nil {
+		return m.UpdateWebhookFunc(ctx, w)
+	}
+	return nil
+}
+
+// DeleteWebhook вызывает DeleteWebhookFunc.
+func (m *Mock) DeleteWebhook(ctx context.Context, webhookID int) error {
+	m.record("DeleteWebhook", webhookID)
+	if m.DeleteWebhookFunc != nil {
+		return m.DeleteWebhookFunc(ctx, webhookID)
+	}
+	return nil
+}
+
+// LockTask вызывает LockTaskFunc.
+func (m *Mock) LockTask(ctx context.Context, taskID int) error {
+	m.record("LockTask", taskID)
+	if m.LockTaskFunc != nil {
+		return m.LockTaskFunc(ctx, taskID)
+	}
+	return nil
+}
+
+// UnlockTask вызывает UnlockTaskFunc.
+func (m *Mock) UnlockTask(ctx context.Context, taskID int) error {
+	m.record("UnlockTask", taskID)
+	if m.UnlockTaskFunc != nil {
+		return m.UnlockTaskFunc(ctx, taskID)
+	}
+	return nil
+}
+
+// UpdateTasksAssignee вызывает UpdateTasksAssigneeFunc.
+func (m *Mock) UpdateTasksAssignee(ctx context.Context, taskIDs []int, assigneeID int) (int64, error) {
+	m.record("UpdateTasksAssignee", taskIDs, assigneeID)
+	if m.UpdateTasksAssigneeFunc != nil {
+		return m.UpdateTasksAssigneeFunc(ctx, taskIDs, assigneeID)
+	}
+	return 0, nil
+}
+
+// UpdateTasksStatus вызывает UpdateTasksStatusFunc.
+func (m *Mock) UpdateTasksStatus(ctx context.Context, taskIDs []int, status storage.Status) (int64, error) {
+	m.record("UpdateTasksStatus", taskIDs, status)
+	if m.UpdateTasksStatusFunc != nil {
+		return m.UpdateTasksStatusFunc(ctx, taskIDs, status)
+	}
+	return 0, nil
+}
+
+// TasksByTag вызывает TasksByTagFunc.
+func (m *Mock) TasksByTag(ctx context.Context, tag string) ([]storage.Task, error) {
+	m.record("TasksByTag", tag)
+	if m.TasksByTagFunc != nil {
+		return m.TasksByTagFunc(ctx, tag)
+	}
+	return nil, nil
+}
+
+// AllTags вызывает AllTagsFunc.
+func (m *Mock) AllTags(ctx context.Context) ([]string, error) {
+	m.record("AllTags")
+	if m.AllTagsFunc != nil {
+		return m.AllTagsFunc(ctx)
+	}
+	return nil, nil
+}
+
+// TasksByRank вызывает TasksByRankFunc.
+func (m *Mock) TasksByRank(ctx context.Context) ([]storage.Task, error) {
+	m.record("TasksByRank")
+	if m.TasksByRankFunc != nil {
+		return m.TasksByRankFunc(ctx)
+	}
+	return nil, nil
+}
+
+// UpdateTaskRank вызывает UpdateTaskRankFunc.
+func (m *Mock) UpdateTaskRank(ctx context.Context, taskID int, rank float64) error {
+	m.record("UpdateTaskRank", taskID, rank)
+	if m.UpdateTaskRankFunc != nil {
+		return m.UpdateTaskRankFunc(ctx, taskID, rank)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"skillfactory/30.8.1/pkg/storage"
+	"testing"
+)
+
+// У каждого метода storage.Interface должно быть поле-функция.
+func TestMockFuncFields(t *testing.T) {
+	iface := reflect.TypeOf((*storage.Interface)(nil)).Elem()
+	mock := reflect.TypeOf(Mock{})
+	for i := range iface.NumMethod() {
+		method := iface.Method(i)
+		field, ok := mock.FieldByName(method.Name + "Func")
+		if !ok {
+			t.Errorf("Mock has no field %sFunc", method.Name)
+			continue
+		}
+		// тип поля совпадает с сигнатурой метода интерфейса
+		if field.Type != method.Type {
+			t.Errorf("%sFunc type = %v, want %v", method.Name, field.Type, method.Type)
+		}
+	}
+}
+
+func TestMockDelegates(t *testing.T) {
+	ctx := context.Background()
+	want := errors.New("failed")
+	m := &Mock{
+		TaskByIdFunc: func(ctx context.Context, taskId int) (*storage.Task, error) {
+			return &storage.Task{ID: taskId}, nil
+		},
+		DeleteTaskFunc: func(ctx context.Context, taskId int) error { return want },
+	}
+
+	task, err := m.TaskById(ctx, 7)
+	if err != nil || task.ID != 7 {
+		t.Errorf("TaskById() = %+v, %v, want task 7", task, err)
+	}
+	err = m.DeleteTask(ctx, 7)
+	if !errors.Is(err, want) {
+		t.Errorf("DeleteTask() error = %v, want %v", err, want)
+	}
+	// без поля-функции возвращаются нулевые значения
+	id, err := m.AddTask(ctx, storage.Task{Title: "title"})
+	if id != 0 || err != nil {
+		t.Errorf("AddTask() = %d, %v, want zero values", id, err)
+	}
+
+	calls := m.CallsTo("TaskById")
+	if len(calls) != 1 || !reflect.DeepEqual(calls[0].Args, []any{7}) {
+		t.Errorf("CallsTo(TaskById) = %+v, want one call with argument 7", calls)
+	}
+	if len(m.Calls) != 3 {
+		t.Errorf("Calls = %+v, want 3 calls", m.Calls)
+	}
+}
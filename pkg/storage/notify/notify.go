@@ -0,0 +1,157 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Канал PostgreSQL, в который триггер на таблице tasks
+// отправляет уведомления (см. миграцию 0012_task_events.sql).
+const channel = "task_events"
+
+// ErrClosed возвращается при подписке на закрытый Notifier.
+var ErrClosed = errors.New("notify: notifier is closed")
+
+// Операции над задачей.
+const (
+	OpInsert = "INSERT"
+	OpUpdate = "UPDATE"
+	OpDelete = "DELETE"
+)
+
+// TaskEvent - событие изменения задачи.
+// Мягкое удаление задачи приходит как OpUpdate.
+type TaskEvent struct {
+	Op     string `json:"op"`
+	TaskID int    `json:"id"`
+}
+
+// Notifier получает уведомления PostgreSQL об изменении задач
+// и рассылает их подписчикам. Для прослушивания канала используется
+// отдельное соединение, не входящее в пул хранилища.
+type Notifier struct {
+	conn   *pgx.Conn
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu     sync.Mutex
+	subs   map[chan<- TaskEvent]struct{}
+	closed bool
+	err    error // ошибка, на которой остановилось прослушивание
+}
+
+// Конструктор, принимает строку подключения к БД.
+// Подключается к БД и начинает прослушивание канала.
+func New(ctx context.Context, constr string) (*Notifier, error) {
+	conn, err := pgx.Connect(ctx, constr)
+	if err != nil {
+		return nil, err
+	}
+	_, err = conn.Exec(ctx, "LISTEN "+channel)
+	if err != nil {
+		conn.Close(ctx)
+		return nil, err
+	}
+
+	listenCtx, cancel := context.WithCancel(context.Background())
+	n := Notifier{
+		conn:   conn,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		subs:   make(map[chan<- TaskEvent]struct{}),
+	}
+	go n.listen(listenCtx)
+	return &n, nil
+}
+
+// Subscribe регистрирует канал для получения событий.
+// Подписка отменяется вызовом Unsubscribe или при отмене ctx.
+//
+// События отправляются без ожидания: если канал заполнен,
+// событие для этого подписчика теряется.
+func (n *Notifier) Subscribe(ctx context.Context, ch chan<- TaskEvent) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.closed {
+		return ErrClosed
+	}
+	n.subs[ch] = struct{}{}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			n.Unsubscribe(ch)
+		case <-n.done:
+		}
+	}()
+	return nil
+}
+
+// Unsubscribe отменяет подписку канала. Канал не закрывается.
+func (n *Notifier) Unsubscribe(ch chan<- TaskEvent) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.subs, ch)
+}
+
+// Err возвращает ошибку, из-за которой прекратилось прослушивание,
+// или nil, если Notifier работает или был закрыт вызовом Close.
+func (n *Notifier) Err() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.err
+}
+
+// Close прекращает прослушивание и закрывает соединение.
+func (n *Notifier) Close(ctx context.Context) error {
+	n.mu.Lock()
+	n.closed = true
+	n.mu.Unlock()
+
+	n.cancel()
+	<-n.done
+	return n.conn.Close(ctx)
+}
+
+// listen ожидает уведомления и рассылает их подписчикам,
+// пока не будет отменён ctx или не произойдёт ошибка соединения.
+func (n *Notifier) listen(ctx context.Context) {
+	defer close(n.done)
+
+	for {
+		msg, err := n.conn.WaitForNotification(ctx)
+		if err != nil {
+			n.mu.Lock()
+			n.closed = true
+			if ctx.Err() == nil {
+				n.err = err
+			}
+			n.mu.Unlock()
+			return
+		}
+
+		var ev TaskEvent
+		if json.Unmarshal([]byte(msg.Payload), &ev) != nil {
+			continue
+		}
+		n.dispatch(ev)
+	}
+}
+
+// dispatch отправляет событие всем подписчикам.
+func (n *Notifier) dispatch(ev TaskEvent) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for ch := range n.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
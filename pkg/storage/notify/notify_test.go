@@ -0,0 +1,132 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"os"
+	"skillfactory/30.8.1/pkg/storage"
+	"skillfactory/30.8.1/pkg/storage/postgres"
+	"testing"
+	"time"
+)
+
+// newTestNotifier возвращает Notifier без соединения с БД.
+func newTestNotifier() *Notifier {
+	return &Notifier{
+		done: make(chan struct{}),
+		subs: make(map[chan<- TaskEvent]struct{}),
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	n := newTestNotifier()
+	ctx := context.Background()
+
+	a := make(chan TaskEvent, 1)
+	b := make(chan TaskEvent) // без буфера: события теряются
+	for _, ch := range []chan TaskEvent{a, b} {
+		err := n.Subscribe(ctx, ch)
+		if err != nil {
+			t.Fatalf("Subscribe() error = %v", err)
+		}
+	}
+
+	ev := TaskEvent{Op: OpInsert, TaskID: 1}
+	n.dispatch(ev)
+	if got := <-a; got != ev {
+		t.Errorf("received %+v, want %+v", got, ev)
+	}
+
+	n.Unsubscribe(a)
+	n.dispatch(TaskEvent{Op: OpDelete, TaskID: 1})
+	select {
+	case got := <-a:
+		t.Errorf("unsubscribed channel received %+v", got)
+	default:
+	}
+}
+
+// Подписка отменяется вместе с контекстом.
+func TestSubscribeContext(t *testing.T) {
+	n := newTestNotifier()
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan TaskEvent, 1)
+	err := n.Subscribe(ctx, ch)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	cancel()
+	deadline := time.Now().Add(time.Second)
+	for {
+		n.mu.Lock()
+		_, ok := n.subs[ch]
+		n.mu.Unlock()
+		if !ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("subscription is active after its context was canceled")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSubscribeClosed(t *testing.T) {
+	n := newTestNotifier()
+	n.closed = true
+	err := n.Subscribe(context.Background(), make(chan TaskEvent))
+	if !errors.Is(err, ErrClosed) {
+		t.Errorf("Subscribe() error = %v, want ErrClosed", err)
+	}
+}
+
+// Изменение задачи приходит подписчику как событие.
+// Тесту нужна БД из переменной окружения TEST_DATABASE_URL.
+func TestNotifier(t *testing.T) {
+	constr := os.Getenv("TEST_DATABASE_URL")
+	if constr == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+
+	err := postgres.Migrate(ctx, constr)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	s, err := postgres.New(constr)
+	if err != nil {
+		t.Fatalf("postgres.New() error = %v", err)
+	}
+	defer s.Close()
+
+	n, err := New(ctx, constr)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer n.Close(ctx)
+
+	ch := make(chan TaskEvent, 10)
+	err = n.Subscribe(ctx, ch)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	id, err := s.AddTask(ctx, storage.Task{Title: "notify test"})
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+
+	want := TaskEvent{Op: OpInsert, TaskID: id}
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case ev := <-ch:
+			if ev == want {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("no %+v event within timeout", want)
+		}
+	}
+}
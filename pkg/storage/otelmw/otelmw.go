@@ -0,0 +1,1225 @@
+package otelmw
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware оборачивает storage.Interface и создаёт для каждого вызова
+// дочерний span с именем "storage.<Метод>". Целочисленные аргументы
+// (ID задач, авторов, меток) записываются в атрибуты span.
+type Middleware struct {
+	inner  storage.Interface
+	tracer trace.Tracer
+}
+
+// Конструктор, принимает оборачиваемое хранилище и трассировщик.
+func New(inner storage.Interface, tracer trace.Tracer) *Middleware {
+	m := Middleware{
+		inner:  inner,
+		tracer: tracer,
+	}
+	return &m
+}
+
+// start начинает span для вызова метода хранилища.
+func (m *Middleware) start(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return m.tracer.Start(ctx, "storage."+method, trace.WithAttributes(attrs...))
+}
+
+// end завершает span, отмечая его ошибкой, если вызов завершился неудачно.
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Tasks трассирует вызов Tasks.
+func (m *Middleware) Tasks(ctx context.Context) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "Tasks")
+	res, err := m.inner.Tasks(ctx)
+	end(span, err)
+	return res, err
+}
+
+// TasksIter трассирует вызов TasksIter.
+func (m *Middleware) TasksIter(ctx context.Context, fn func(t storage.Task) error) error {
+	ctx, span := m.start(ctx, "TasksIter")
+	err := m.inner.TasksIter(ctx, fn)
+	end(span, err)
+	return err
+}
+
+// TasksList трассирует вызов TasksList.
+func (m *Middleware) TasksList(ctx context.Context, opts storage.ListOptions) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "TasksList")
+	res, err := m.inner.TasksList(ctx, opts)
+	end(span, err)
+	return res, err
+}
+
+// TotalCount трассирует вызов TotalCount.
+func (m *Middleware) TotalCount(ctx context.Context) (int, error) {
+	ctx, span := m.start(ctx, "TotalCount")
+	res, err := m.inner.TotalCount(ctx)
+	end(span, err)
+	return res, err
+}
+
+// TasksAfter трассирует вызов TasksAfter.
+func (m *Middleware) TasksAfter(ctx context.Context, afterID int, limit int) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "TasksAfter", attribute.Int("afterID", afterID), attribute.Int("limit", limit))
+	res, err := m.inner.TasksAfter(ctx, afterID, limit)
+	end(span, err)
+	return res, err
+}
+
+// TaskCount трассирует вызов TaskCount.
+func (m *Middleware) TaskCount(ctx context.Context) (int, error) {
+	ctx, span := m.start(ctx, "TaskCount")
+	res, err := m.inner.TaskCount(ctx)
+	end(span, err)
+	return res, err
+}
+
+// TaskCountByAuthor трассирует вызов TaskCountByAuthor.
+func (m *Middleware) TaskCountByAuthor(ctx context.Context, authorID int) (int, error) {
+	ctx, span := m.start(ctx, "TaskCountByAuthor", attribute.Int("authorID", authorID))
+	res, err := m.inner.TaskCountByAuthor(ctx, authorID)
+	end(span, err)
+	return res, err
+}
+
+// TaskCountByLabel трассирует вызов TaskCountByLabel.
+func (m *Middleware) TaskCountByLabel(ctx context.Context, labelID int) (int, error) {
+	ctx, span := m.start(ctx, "TaskCountByLabel", attribute.Int("labelID", labelID))
+	res, err := m.inner.TaskCountByLabel(ctx, labelID)
+	end(span, err)
+	return res, err
+}
+
+// TaskById трассирует вызов TaskById.
+func (m *Middleware) TaskById(ctx context.Context, taskId int) (*storage.Task, error) {
+	ctx, span := m.start(ctx, "TaskById", attribute.Int("taskID", taskId))
+	res, err := m.inner.TaskById(ctx, taskId)
+	end(span, err)
+	return res, err
+}
+
+// TasksByAuthor трассирует вызов TasksByAuthor.
+func (m *Middleware) TasksByAuthor(ctx context.Context, authorId int) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "TasksByAuthor", attribute.Int("authorID", authorId))
+	res, err := m.inner.TasksByAuthor(ctx, authorId)
+	end(span, err)
+	return res, err
+}
+
+// TasksByAssignee трассирует вызов TasksByAssignee.
+func (m *Middleware) TasksByAssignee(ctx context.Context, assigneeID int) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "TasksByAssignee", attribute.Int("assigneeID", assigneeID))
+	res, err := m.inner.TasksByAssignee(ctx, assigneeID)
+	end(span, err)
+	return res, err
+}
+
+// TasksByLabel трассирует вызов TasksByLabel.
+func (m *Middleware) TasksByLabel(ctx context.Context, labelId int) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "TasksByLabel", attribute.Int("labelID", labelId))
+	res, err := m.inner.TasksByLabel(ctx, labelId)
+	end(span, err)
+	return res, err
+}
+
+// FilterTasks трассирует вызов FilterTasks.
+func (m *Middleware) FilterTasks(ctx context.Context, f storage.TaskFilter) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "FilterTasks")
+	res, err := m.inner.FilterTasks(ctx, f)
+	end(span, err)
+	return res, err
+}
+
+// AddTask трассирует вызов AddTask.
+func (m *Middleware) AddTask(ctx context.Context, task storage.Task) (int, error) {
+	ctx, span := m.start(ctx, "AddTask")
+	res, err := m.inner.AddTask(ctx, task)
+	end(span, err)
+	return res, err
+}
+
+// AddTasks трассирует вызов AddTasks.
+func (m *Middleware) AddTasks(ctx context.Context, tasks []storage.Task) ([]int, error) {
+	ctx, span := m.start(ctx, "AddTasks")
+	res, err := m.inner.AddTasks(ctx, tasks)
+	end(span, err)
+	return res, err
+}
+
+// AddTasksBatch трассирует вызов AddTasksBatch.
+func (m *Middleware) AddTasksBatch(ctx context.Context, tasks []storage.Task) ([]int, error) {
+	ctx, span := m.start(ctx, "AddTasksBatch")
+	res, err := m.inner.AddTasksBatch(ctx, tasks)
+	end(span, err)
+	return res, err
+}
+
+// UpdateTask трассирует вызов UpdateTask.
+func (m *Middleware) UpdateTask(ctx context.Context, task storage.Task) error {
+	ctx, span := m.start(ctx, "UpdateTask")
+	err := m.inner.UpdateTask(ctx, task)
+	end(span, err)
+	return err
+}
+
+// UpsertTask трассирует вызов UpsertTask.
+func (m *Middleware) UpsertTask(ctx context.Context, t storage.Task) (int, error) {
+	ctx, span := m.start(ctx, "UpsertTask")
+	res, err := m.inner.UpsertTask(ctx, t)
+	end(span, err)
+	return res, err
+}
+
+// PartialUpdateTask трассирует вызов PartialUpdateTask.
+func (m *Middleware) PartialUpdateTask(ctx context.Context, taskID int, patch storage.TaskPatch) error {
+	ctx, span := m.start(ctx, "PartialUpdateTask", attribute.Int("taskID", taskID))
+	err := m.inner.PartialUpdateTask(ctx, taskID, patch)
+	end(span, err)
+	return err
+}
+
+// DeleteTask трассирует вызов DeleteTask.
+func (m *Middleware) DeleteTask(ctx context.Context, taskId int) error {
+	ctx, span := m.start(ctx, "DeleteTask", attribute.Int("taskID", taskId))
+	err := m.inner.DeleteTask(ctx, taskId)
+	end(span, err)
+	return err
+}
+
+// DeleteTasks трассирует вызов DeleteTasks.
+func (m *Middleware) DeleteTasks(ctx context.Context, taskIDs []int) error {
+	ctx, span := m.start(ctx, "DeleteTasks")
+	err := m.inner.DeleteTasks(ctx, taskIDs)
+	end(span, err)
+	return err
+}
+
+// TasksIncludingDeleted трассирует вызов TasksIncludingDeleted.
+func (m *Middleware) TasksIncludingDeleted(ctx context.Context) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "TasksIncludingDeleted")
+	res, err := m.inner.TasksIncludingDeleted(ctx)
+	end(span, err)
+	return res, err
+}
+
+// UndeleteTask трассирует вызов UndeleteTask.
+func (m *Middleware) UndeleteTask(ctx context.Context, taskID int) error {
+	ctx, span := m.start(ctx, "UndeleteTask", attribute.Int("taskID", taskID))
+	err := m.inner.UndeleteTask(ctx, taskID)
+	end(span, err)
+	return err
+}
+
+// AddUser трассирует вызов AddUser.
+func (m *Middleware) AddUser(ctx context.Context, u storage.User) (int, error) {
+	ctx, span := m.start(ctx, "AddUser")
+	res, err := m.inner.AddUser(ctx, u)
+	end(span, err)
+	return res, err
+}
+
+// Users трассирует вызов Users.
+func (m *Middleware) Users(ctx context.Context) ([]storage.User, error) {
+	ctx, span := m.start(ctx, "Users")
+	res, err := m.inner.Users(ctx)
+	end(span, err)
+	return res, err
+}
+
+// UserByID трассирует вызов UserByID.
+func (m *Middleware) UserByID(ctx context.Context, userID int) (*storage.User, error) {
+	ctx, span := m.start(ctx, "UserByID", attribute.Int("userID", userID))
+	res, err := m.inner.UserByID(ctx, userID)
+	end(span, err)
+	return res, err
+}
+
+// UpdateUser трассирует вызов UpdateUser.
+func (m *Middleware) UpdateUser(ctx context.Context, u storage.User) error {
+	ctx, span := m.start(ctx, "UpdateUser")
+	err := m.inner.UpdateUser(ctx, u)
+	end(span, err)
+	return err
+}
+
+// DeleteUser трассирует вызов DeleteUser.
+func (m *Middleware) DeleteUser(ctx context.Context, userID int) error {
+	ctx, span := m.start(ctx, "DeleteUser", attribute.Int("userID", userID))
+	err := m.inner.DeleteUser(ctx, userID)
+	end(span, err)
+	return err
+}
+
+// AddLabel трассирует вызов AddLabel.
+func (m *Middleware) AddLabel(ctx context.Context, l storage.Label) (int, error) {
+	ctx, span := m.start(ctx, "AddLabel")
+	res, err := m.inner.AddLabel(ctx, l)
+	end(span, err)
+	return res, err
+}
+
+// Labels трассирует вызов Labels.
+func (m *Middleware) Labels(ctx context.Context) ([]storage.Label, error) {
+	ctx, span := m.start(ctx, "Labels")
+	res, err := m.inner.Labels(ctx)
+	end(span, err)
+	return res, err
+}
+
+// LabelByID трассирует вызов LabelByID.
+func (m *Middleware) LabelByID(ctx context.Context, labelID int) (*storage.Label, error) {
+	ctx, span := m.start(ctx, "LabelByID", attribute.Int("labelID", labelID))
+	res, err := m.inner.LabelByID(ctx, labelID)
+	end(span, err)
+	return res, err
+}
+
+// UpdateLabel трассирует вызов UpdateLabel.
+func (m *Middleware) UpdateLabel(ctx context.Context, l storage.Label) error {
+	ctx, span := m.start(ctx, "UpdateLabel")
+	err := m.inner.UpdateLabel(ctx, l)
+	end(span, err)
+	return err
+}
+
+// DeleteLabel трассирует вызов DeleteLabel.
+func (m *Middleware) DeleteLabel(ctx context.Context, labelID int) error {
+	ctx, span := m.start(ctx, "DeleteLabel", attribute.Int("labelID", labelID))
+	err := m.inner.DeleteLabel(ctx, labelID)
+	end(span, err)
+	return err
+}
+
+// AssignLabel трассирует вызов AssignLabel.
+func (m *Middleware) AssignLabel(ctx context.Context, taskID, labelID int) error {
+	ctx, span := m.start(ctx, "AssignLabel", attribute.Int("taskID", taskID), attribute.Int("labelID", labelID))
+	err := m.inner.AssignLabel(ctx, taskID, labelID)
+	end(span, err)
+	return err
+}
+
+// RemoveLabel трассирует вызов RemoveLabel.
+func (m *Middleware) RemoveLabel(ctx context.Context, taskID, labelID int) error {
+	ctx, span := m.start(ctx, "RemoveLabel", attribute.Int("taskID", taskID), attribute.Int("labelID", labelID))
+	err := m.inner.RemoveLabel(ctx, taskID, labelID)
+	end(span, err)
+	return err
+}
+
+// TasksByStatus трассирует вызов TasksByStatus.
+func (m *Middleware) TasksByStatus(ctx context.Context, s storage.Status) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "TasksByStatus")
+	res, err := m.inner.TasksByStatus(ctx, s)
+	end(span, err)
+	return res, err
+}
+
+// UpdateTaskStatus трассирует вызов UpdateTaskStatus.
+func (m *Middleware) UpdateTaskStatus(ctx context.Context, taskID int, s storage.Status) error {
+	ctx, span := m.start(ctx, "UpdateTaskStatus", attribute.Int("taskID", taskID))
+	err := m.inner.UpdateTaskStatus(ctx, taskID, s)
+	end(span, err)
+	return err
+}
+
+// TasksByPriority трассирует вызов TasksByPriority.
+func (m *Middleware) TasksByPriority(ctx context.Context, p storage.Priority) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "TasksByPriority")
+	res, err := m.inner.TasksByPriority(ctx, p)
+	end(span, err)
+	return res, err
+}
+
+// TasksOrderedByPriority трассирует вызов TasksOrderedByPriority.
+func (m *Middleware) TasksOrderedByPriority(ctx context.Context) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "TasksOrderedByPriority")
+	res, err := m.inner.TasksOrderedByPriority(ctx)
+	end(span, err)
+	return res, err
+}
+
+// TasksOverdue трассирует вызов TasksOverdue.
+func (m *Middleware) TasksOverdue(ctx context.Context, now int64) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "TasksOverdue", attribute.Int64("now", now))
+	res, err := m.inner.TasksOverdue(ctx, now)
+	end(span, err)
+	return res, err
+}
+
+// TasksDueBetween трассирует вызов TasksDueBetween.
+func (m *Middleware) TasksDueBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "TasksDueBetween", attribute.Int64("from", from), attribute.Int64("to", to))
+	res, err := m.inner.TasksDueBetween(ctx, from, to)
+	end(span, err)
+	return res, err
+}
+
+// AddComment трассирует вызов AddComment.
+func (m *Middleware) AddComment(ctx context.Context, c storage.Comment) (int, error) {
+	ctx, span := m.start(ctx, "AddComment")
+	res, err := m.inner.AddComment(ctx, c)
+	end(span, err)
+	return res, err
+}
+
+// CommentsByTask трассирует вызов CommentsByTask.
+func (m *Middleware) CommentsByTask(ctx context.Context, taskID int) ([]storage.Comment, error) {
+	ctx, span := m.start(ctx, "CommentsByTask", attribute.Int("taskID", taskID))
+	res, err := m.inner.CommentsByTask(ctx, taskID)
+	end(span, err)
+	return res, err
+}
+
+// UpdateComment трассирует вызов UpdateComment.
+func (m *Middleware) UpdateComment(ctx context.Context, c storage.Comment) error {
+	ctx, span := m.start(ctx, "UpdateComment")
+	err := m.inner.UpdateComment(ctx, c)
+	end(span, err)
+	return err
+}
+
+// DeleteComment трассирует вызов DeleteComment.
+func (m *Middleware) DeleteComment(ctx context.Context, commentID int) error {
+	ctx, span := m.start(ctx, "DeleteComment", attribute.Int("commentID", commentID))
+	err := m.inner.DeleteComment(ctx, commentID)
+	end(span, err)
+	return err
+}
+
+// LogTime трассирует вызов LogTime.
+func (m *Middleware) LogTime(ctx context.Context, e storage.TimeEntry) (int, error) {
+	ctx, span := m.start(ctx, "LogTime")
+	res, err := m.inner.LogTime(ctx, e)
+	end(span, err)
+	return res, err
+}
+
+// TimeEntriesByTask трассирует вызов TimeEntriesByTask.
+func (m *Middleware) TimeEntriesByTask(ctx context.Context, taskID int) ([]storage.TimeEntry, error) {
+	ctx, span := m.start(ctx, "TimeEntriesByTask", attribute.Int("taskID", taskID))
+	res, err := m.inner.TimeEntriesByTask(ctx, taskID)
+	end(span, err)
+	return res, err
+}
+
+// TimeEntriesByUser трассирует вызов TimeEntriesByUser.
+func (m *Middleware) TimeEntriesByUser(ctx context.Context, userID int) ([]storage.TimeEntry, error) {
+	ctx, span := m.start(ctx, "TimeEntriesByUser", attribute.Int("userID", userID))
+	res, err := m.inner.TimeEntriesByUser(ctx, userID)
+	end(span, err)
+	return res, err
+}
+
+// TotalMinutesByTask трассирует вызов TotalMinutesByTask.
+func (m *Middleware) TotalMinutesByTask(ctx context.Context, taskID int) (int, error) {
+	ctx, span := m.start(ctx, "TotalMinutesByTask", attribute.Int("taskID", taskID))
+	res, err := m.inner.TotalMinutesByTask(ctx, taskID)
+	end(span, err)
+	return res, err
+}
+
+// AddChecklistItem трассирует вызов AddChecklistItem.
+func (m *Middleware) AddChecklistItem(ctx context.Context, item storage.ChecklistItem) (int, error) {
+	ctx, span := m.start(ctx, "AddChecklistItem")
+	res, err := m.inner.AddChecklistItem(ctx, item)
+	end(span, err)
+	return res, err
+}
+
+// ChecklistByTask трассирует вызов ChecklistByTask.
+func (m *Middleware) ChecklistByTask(ctx context.Context, taskID int) ([]storage.ChecklistItem, error) {
+	ctx, span := m.start(ctx, "ChecklistByTask", attribute.Int("taskID", taskID))
+	res, err := m.inner.ChecklistByTask(ctx, taskID)
+	end(span, err)
+	return res, err
+}
+
+// UpdateChecklistItem трассирует вызов UpdateChecklistItem.
+func (m *Middleware) UpdateChecklistItem(ctx context.Context, item storage.ChecklistItem) error {
+	ctx, span := m.start(ctx, "UpdateChecklistItem")
+	err := m.inner.UpdateChecklistItem(ctx, item)
+	end(span, err)
+	return err
+}
+
+// DeleteChecklistItem трассирует вызов DeleteChecklistItem.
+func (m *Middleware) DeleteChecklistItem(ctx context.Context, itemID int) error {
+	ctx, span := m.start(ctx, "DeleteChecklistItem", attribute.Int("itemID", itemID))
+	err := m.inner.DeleteChecklistItem(ctx, itemID)
+	end(span, err)
+	return err
+}
+
+// ReorderChecklist трассирует вызов ReorderChecklist.
+func (m *Middleware) ReorderChecklist(ctx context.Context, taskID int, orderedIDs []int) error {
+	ctx, span := m.start(ctx, "ReorderChecklist", attribute.Int("taskID", taskID))
+	err := m.inner.ReorderChecklist(ctx, taskID, orderedIDs)
+	end(span, err)
+	return err
+}
+
+// AddProject трассирует вызов AddProject.
+func (m *Middleware) AddProject(ctx context.Context, p storage.Project) (int, error) {
+	ctx, span := m.start(ctx, "AddProject")
+	res, err := m.inner.AddProject(ctx, p)
+	end(span, err)
+	return res, err
+}
+
+// Projects трассирует вызов Projects.
+func (m *Middleware) Projects(ctx context.Context) ([]storage.Project, error) {
+	ctx, span := m.start(ctx, "Projects")
+	res, err := m.inner.Projects(ctx)
+	end(span, err)
+	return res, err
+}
+
+// ProjectByID трассирует вызов ProjectByID.
+func (m *Middleware) ProjectByID(ctx context.Context, projectID int) (*storage.Project, error) {
+	ctx, span := m.start(ctx, "ProjectByID", attribute.Int("projectID", projectID))
+	res, err := m.inner.ProjectByID(ctx, projectID)
+	end(span, err)
+	return res, err
+}
+
+// UpdateProject трассирует вызов UpdateProject.
+func (m *Middleware) UpdateProject(ctx context.Context, p storage.Project) error {
+	ctx, span := m.start(ctx, "UpdateProject")
+	err := m.inner.UpdateProject(ctx, p)
+	end(span, err)
+	return err
+}
+
+// DeleteProject трассирует вызов DeleteProject.
+func (m *Middleware) DeleteProject(ctx context.Context, projectID int, cascadeDelete bool) error {
+	ctx, span := m.start(ctx, "DeleteProject", attribute.Int("projectID", projectID))
+	err := m.inner.DeleteProject(ctx, projectID, cascadeDelete)
+	end(span, err)
+	return err
+}
+
+// TasksByProject трассирует вызов TasksByProject.
+func (m *Middleware) TasksByProject(ctx context.Context, projectID int) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "TasksByProject", attribute.Int("projectID", projectID))
+	res, err := m.inner.TasksByProject(ctx, projectID)
+	end(span, err)
+	return res, err
+}
+
+// AddDependency трассирует вызов AddDependency.
+func (m *Middleware) AddDependency(ctx context.Context, taskID, dependsOnID int) error {
+	ctx, span := m.start(ctx, "AddDependency", attribute.Int("taskID", taskID), attribute.Int("dependsOnID", dependsOnID))
+	err := m.inner.AddDependency(ctx, taskID, dependsOnID)
+	end(span, err)
+	return err
+}
+
+// RemoveDependency трассирует вызов RemoveDependency.
+func (m *Middleware) RemoveDependency(ctx context.Context, taskID, dependsOnID int) error {
+	ctx, span := m.start(ctx, "RemoveDependency", attribute.Int("taskID", taskID), attribute.Int("dependsOnID", dependsOnID))
+	err := m.inner.RemoveDependency(ctx, taskID, dependsOnID)
+	end(span, err)
+	return err
+}
+
+// DependenciesOf трассирует вызов DependenciesOf.
+func (m *Middleware) DependenciesOf(ctx context.Context, taskID int) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "DependenciesOf", attribute.Int("taskID", taskID))
+	res, err := m.inner.DependenciesOf(ctx, taskID)
+	end(span, err)
+	return res, err
+}
+
+// BlockedBy трассирует вызов BlockedBy.
+func (m *Middleware) BlockedBy(ctx context.Context, taskID int) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "BlockedBy", attribute.Int("taskID", taskID))
+	res, err := m.inner.BlockedBy(ctx, taskID)
+	end(span, err)
+	return res, err
+}
+
+// AddTaskWithLabels трассирует вызов AddTaskWithLabels.
+func (m *Middleware) AddTaskWithLabels(ctx context.Context, t storage.Task, labelIDs []int) (int, error) {
+	ctx, span := m.start(ctx, "AddTaskWithLabels")
+	res, err := m.inner.AddTaskWithLabels(ctx, t, labelIDs)
+	end(span, err)
+	return res, err
+}
+
+// ImportTasks трассирует вызов ImportTasks.
+func (m *Middleware) ImportTasks(ctx context.Context, tasks []storage.Task) error {
+	ctx, span := m.start(ctx, "ImportTasks")
+	err := m.inner.ImportTasks(ctx, tasks)
+	end(span, err)
+	return err
+}
+
+// TasksWithLabels трассирует вызов TasksWithLabels.
+func (m *Middleware) TasksWithLabels(ctx context.Context) ([]storage.TaskWithLabels, error) {
+	ctx, span := m.start(ctx, "TasksWithLabels")
+	res, err := m.inner.TasksWithLabels(ctx)
+	end(span, err)
+	return res, err
+}
+
+// TasksWithUsers трассирует вызов TasksWithUsers.
+func (m *Middleware) TasksWithUsers(ctx context.Context) ([]storage.TaskWithUsers, error) {
+	ctx, span := m.start(ctx, "TasksWithUsers")
+	res, err := m.inner.TasksWithUsers(ctx)
+	end(span, err)
+	return res, err
+}
+
+// BeginTx трассирует вызов BeginTx.
+func (m *Middleware) BeginTx(ctx context.Context) (storage.TxStorage, error) {
+	ctx, span := m.start(ctx, "BeginTx")
+	res, err := m.inner.BeginTx(ctx)
+	end(span, err)
+	return res, err
+}
+
+// AuditLog трассирует вызов AuditLog.
+func (m *Middleware) AuditLog(ctx context.Context, taskID int) ([]storage.AuditEntry, error) {
+	ctx, span := m.start(ctx, "AuditLog", attribute.Int("taskID", taskID))
+	res, err := m.inner.AuditLog(ctx, taskID)
+	end(span, err)
+	return res, err
+}
+
+// RecordChange трассирует вызов RecordChange.
+func (m *Middleware) RecordChange(ctx context.Context, e storage.AuditEntry) error {
+	ctx, span := m.start(ctx, "RecordChange")
+	err := m.inner.RecordChange(ctx, e)
+	end(span, err)
+	return err
+}
+
+// SearchTasks трассирует вызов SearchTasks.
+func (m *Middleware) SearchTasks(ctx context.Context, query string, limit int) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "SearchTasks", attribute.Int("limit", limit))
+	res, err := m.inner.SearchTasks(ctx, query, limit)
+	end(span, err)
+	return res, err
+}
+
+// TasksByMetadataKey трассирует вызов TasksByMetadataKey.
+func (m *Middleware) TasksByMetadataKey(ctx context.Context, key, value string) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "TasksByMetadataKey")
+	res, err := m.inner.TasksByMetadataKey(ctx, key, value)
+	end(span, err)
+	return res, err
+}
+
+// AssignmentHistory трассирует вызов AssignmentHistory.
+func (m *Middleware) AssignmentHistory(ctx context.Context, taskID int) ([]storage.AssignmentEvent, error) {
+	ctx, span := m.start(ctx, "AssignmentHistory", attribute.Int("taskID", taskID))
+	res, err := m.inner.AssignmentHistory(ctx, taskID)
+	end(span, err)
+	return res, err
+}
+
+// RecordAssignment трассирует вызов RecordAssignment.
+func (m *Middleware) RecordAssignment(ctx context.Context, e storage.AssignmentEvent) error {
+	ctx, span := m.start(ctx, "RecordAssignment")
+	err := m.inner.RecordAssignment(ctx, e)
+	end(span, err)
+	return err
+}
+
+// AddRecurrenceRule трассирует вызов AddRecurrenceRule.
+func (m *Middleware) AddRecurrenceRule(ctx context.Context, rule storage.RecurrenceRule) error {
+	ctx, span := m.start(ctx, "AddRecurrenceRule")
+	err := m.inner.AddRecurrenceRule(ctx, rule)
+	end(span, err)
+	return err
+}
+
+// RecurrenceRuleByTask трассирует вызов RecurrenceRuleByTask.
+func (m *Middleware) RecurrenceRuleByTask(ctx context.Context, taskID int) (*storage.RecurrenceRule, error) {
+	ctx, span := m.start(ctx, "RecurrenceRuleByTask", attribute.Int("taskID", taskID))
+	res, err := m.inner.RecurrenceRuleByTask(ctx, taskID)
+	end(span, err)
+	return res, err
+}
+
+// UpdateRecurrenceRule трассирует вызов UpdateRecurrenceRule.
+func (m *Middleware) UpdateRecurrenceRule(ctx context.Context, rule storage.RecurrenceRule) error {
+	ctx, span := m.start(ctx, "UpdateRecurrenceRule")
+	err := m.inner.UpdateRecurrenceRule(ctx, rule)
+	end(span, err)
+	return err
+}
+
+// DeleteRecurrenceRule трассирует вызов DeleteRecurrenceRule.
+func (m *Middleware) DeleteRecurrenceRule(ctx context.Context, taskID int) error {
+	ctx, span := m.start(ctx, "DeleteRecurrenceRule", attribute.Int("taskID", taskID))
+	err := m.inner.DeleteRecurrenceRule(ctx, taskID)
+	end(span, err)
+	return err
+}
+
+// DueRecurrences трассирует вызов DueRecurrences.
+func (m *Middleware) DueRecurrences(ctx context.Context, now int64) ([]storage.RecurrenceRule, error) {
+	ctx, span := m.start(ctx, "DueRecurrences", attribute.Int64("now", now))
+	res, err := m.inner.DueRecurrences(ctx, now)
+	end(span, err)
+	return res, err
+}
+
+// SpawnRecurringTask трассирует вызов SpawnRecurringTask.
+func (m *Middleware) SpawnRecurringTask(ctx context.Context, rule storage.RecurrenceRule) (int, error) {
+	ctx, span := m.start(ctx, "SpawnRecurringTask")
+	res, err := m.inner.SpawnRecurringTask(ctx, rule)
+	end(span, err)
+	return res, err
+}
+
+// ArchiveTask трассирует вызов ArchiveTask.
+func (m *Middleware) ArchiveTask(ctx context.Context, taskID int) error {
+	ctx, span := m.start(ctx, "ArchiveTask", attribute.Int("taskID", taskID))
+	err := m.inner.ArchiveTask(ctx, taskID)
+	end(span, err)
+	return err
+}
+
+// ArchivedTasks трассирует вызов ArchivedTasks.
+func (m *Middleware) ArchivedTasks(ctx context.Context) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "ArchivedTasks")
+	res, err := m.inner.ArchivedTasks(ctx)
+	end(span, err)
+	return res, err
+}
+
+// UnarchiveTask трассирует вызов UnarchiveTask.
+func (m *Middleware) UnarchiveTask(ctx context.Context, taskID int) error {
+	ctx, span := m.start(ctx, "UnarchiveTask", attribute.Int("taskID", taskID))
+	err := m.inner.UnarchiveTask(ctx, taskID)
+	end(span, err)
+	return err
+}
+
+// TasksByLabels трассирует вызов TasksByLabels.
+func (m *Middleware) TasksByLabels(ctx context.Context, labelIDs []int, mode storage.LabelFilterMode) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "TasksByLabels")
+	res, err := m.inner.TasksByLabels(ctx, labelIDs, mode)
+	end(span, err)
+	return res, err
+}
+
+// TasksOpenedBetween трассирует вызов TasksOpenedBetween.
+func (m *Middleware) TasksOpenedBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "TasksOpenedBetween", attribute.Int64("from", from), attribute.Int64("to", to))
+	res, err := m.inner.TasksOpenedBetween(ctx, from, to)
+	end(span, err)
+	return res, err
+}
+
+// TasksClosedBetween трассирует вызов TasksClosedBetween.
+func (m *Middleware) TasksClosedBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "TasksClosedBetween", attribute.Int64("from", from), attribute.Int64("to", to))
+	res, err := m.inner.TasksClosedBetween(ctx, from, to)
+	end(span, err)
+	return res, err
+}
+
+// AddSprint трассирует вызов AddSprint.
+func (m *Middleware) AddSprint(ctx context.Context, sp storage.Sprint) (int, error) {
+	ctx, span := m.start(ctx, "AddSprint")
+	res, err := m.inner.AddSprint(ctx, sp)
+	end(span, err)
+	return res, err
+}
+
+// SprintByID трассирует вызов SprintByID.
+func (m *Middleware) SprintByID(ctx context.Context, sprintID int) (*storage.Sprint, error) {
+	ctx, span := m.start(ctx, "SprintByID", attribute.Int("sprintID", sprintID))
+	res, err := m.inner.SprintByID(ctx, sprintID)
+	end(span, err)
+	return res, err
+}
+
+// Sprints трассирует вызов Sprints.
+func (m *Middleware) Sprints(ctx context.Context) ([]storage.Sprint, error) {
+	ctx, span := m.start(ctx, "Sprints")
+	res, err := m.inner.Sprints(ctx)
+	end(span, err)
+	return res, err
+}
+
+// UpdateSprint трассирует вызов UpdateSprint.
+func (m *Middleware) UpdateSprint(ctx context.Context, sp storage.Sprint) error {
+	ctx, span := m.start(ctx, "UpdateSprint")
+	err := m.inner.UpdateSprint(ctx, sp)
+	end(span, err)
+	return err
+}
+
+// DeleteSprint трассирует вызов DeleteSprint.
+func (m *Middleware) DeleteSprint(ctx context.Context, sprintID int) error {
+	ctx, span := m.start(ctx, "DeleteSprint", attribute.Int("sprintID", sprintID))
+	err := m.inner.DeleteSprint(ctx, sprintID)
+	end(span, err)
+	return err
+}
+
+// AssignTaskToSprint трассирует вызов AssignTaskToSprint.
+func (m *Middleware) AssignTaskToSprint(ctx context.Context, taskID, sprintID int) error {
+	ctx, span := m.start(ctx, "AssignTaskToSprint", attribute.Int("taskID", taskID), attribute.Int("sprintID", sprintID))
+	err := m.inner.AssignTaskToSprint(ctx, taskID, sprintID)
+	end(span, err)
+	return err
+}
+
+// RemoveTaskFromSprint трассирует вызов RemoveTaskFromSprint.
+func (m *Middleware) RemoveTaskFromSprint(ctx context.Context, taskID, sprintID int) error {
+	ctx, span := m.start(ctx, "RemoveTaskFromSprint", attribute.Int("taskID", taskID), attribute.Int("sprintID", sprintID))
+	err := m.inner.RemoveTaskFromSprint(ctx, taskID, sprintID)
+	end(span, err)
+	return err
+}
+
+// TasksBySprint трассирует вызов TasksBySprint.
+func (m *Middleware) TasksBySprint(ctx context.Context, sprintID int) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "TasksBySprint", attribute.Int("sprintID", sprintID))
+	res, err := m.inner.TasksBySprint(ctx, sprintID)
+	end(span, err)
+	return res, err
+}
+
+// SprintVelocity трассирует вызов SprintVelocity.
+func (m *Middleware) SprintVelocity(ctx context.Context, sprintID int) (int, error) {
+	ctx, span := m.start(ctx, "SprintVelocity", attribute.Int("sprintID", sprintID))
+	res, err := m.inner.SprintVelocity(ctx, sprintID)
+	end(span, err)
+	return res, err
+}
+
+// BurndownData трассирует вызов BurndownData.
+func (m *Middleware) BurndownData(ctx context.Context, sprintID int, buckets int) ([]storage.BurndownPoint, error) {
+	ctx, span := m.start(ctx, "BurndownData", attribute.Int("sprintID", sprintID), attribute.Int("buckets", buckets))
+	res, err := m.inner.BurndownData(ctx, sprintID, buckets)
+	end(span, err)
+	return res, err
+}
+
+// UserWorkloads трассирует вызов UserWorkloads.
+func (m *Middleware) UserWorkloads(ctx context.Context) ([]storage.UserWorkload, error) {
+	ctx, span := m.start(ctx, "UserWorkloads")
+	res, err := m.inner.UserWorkloads(ctx)
+	end(span, err)
+	return res, err
+}
+
+// UserWorkload трассирует вызов UserWorkload.
+func (m *Middleware) UserWorkload(ctx context.Context, userID int) (*storage.UserWorkload, error) {
+	ctx, span := m.start(ctx, "UserWorkload", attribute.Int("userID", userID))
+	res, err := m.inner.UserWorkload(ctx, userID)
+	end(span, err)
+	return res, err
+}
+
+// TaskStats трассирует вызов TaskStats.
+func (m *Middleware) TaskStats(ctx context.Context) (*storage.TaskStats, error) {
+	ctx, span := m.start(ctx, "TaskStats")
+	res, err := m.inner.TaskStats(ctx)
+	end(span, err)
+	return res, err
+}
+
+// LabelStats трассирует вызов LabelStats.
+func (m *Middleware) LabelStats(ctx context.Context) ([]storage.LabelStat, error) {
+	ctx, span := m.start(ctx, "LabelStats")
+	res, err := m.inner.LabelStats(ctx)
+	end(span, err)
+	return res, err
+}
+
+// TopLabels трассирует вызов TopLabels.
+func (m *Middleware) TopLabels(ctx context.Context, n int) ([]storage.LabelStat, error) {
+	ctx, span := m.start(ctx, "TopLabels", attribute.Int("n", n))
+	res, err := m.inner.TopLabels(ctx, n)
+	end(span, err)
+	return res, err
+}
+
+// RecordActivity трассирует вызов RecordActivity.
+func (m *Middleware) RecordActivity(ctx context.Context, e storage.ActivityEvent) error {
+	ctx, span := m.start(ctx, "RecordActivity")
+	err := m.inner.RecordActivity(ctx, e)
+	end(span, err)
+	return err
+}
+
+// ActivityFeed трассирует вызов ActivityFeed.
+func (m *Middleware) ActivityFeed(ctx context.Context, taskID int, limit int) ([]storage.ActivityEvent, error) {
+	ctx, span := m.start(ctx, "ActivityFeed", attribute.Int("taskID", taskID), attribute.Int("limit", limit))
+	res, err := m.inner.ActivityFeed(ctx, taskID, limit)
+	end(span, err)
+	return res, err
+}
+
+// GlobalActivityFeed трассирует вызов GlobalActivityFeed.
+func (m *Middleware) GlobalActivityFeed(ctx context.Context, limit int) ([]storage.ActivityEvent, error) {
+	ctx, span := m.start(ctx, "GlobalActivityFeed", attribute.Int("limit", limit))
+	res, err := m.inner.GlobalActivityFeed(ctx, limit)
+	end(span, err)
+	return res, err
+}
+
+// AppendEvents трассирует вызов AppendEvents.
+func (m *Middleware) AppendEvents(ctx context.Context, streamID string, expectedSeq int64, events []storage.Event) error {
+	ctx, span := m.start(ctx, "AppendEvents", attribute.Int64("expectedSeq", expectedSeq))
+	err := m.inner.AppendEvents(ctx, streamID, expectedSeq, events)
+	end(span, err)
+	return err
+}
+
+// LoadEvents трассирует вызов LoadEvents.
+func (m *Middleware) LoadEvents(ctx context.Context, streamID string, fromSeq int64) ([]storage.Event, error) {
+	ctx, span := m.start(ctx, "LoadEvents", attribute.Int64("fromSeq", fromSeq))
+	res, err := m.inner.LoadEvents(ctx, streamID, fromSeq)
+	end(span, err)
+	return res, err
+}
+
+// AppendOutbox трассирует вызов AppendOutbox.
+func (m *Middleware) AppendOutbox(ctx context.Context, tx storage.TxStorage, msgs []storage.OutboxMessage) error {
+	ctx, span := m.start(ctx, "AppendOutbox")
+	err := m.inner.AppendOutbox(ctx, tx, msgs)
+	end(span, err)
+	return err
+}
+
+// PendingOutbox трассирует вызов PendingOutbox.
+func (m *Middleware) PendingOutbox(ctx context.Context, limit int) ([]storage.OutboxMessage, error) {
+	ctx, span := m.start(ctx, "PendingOutbox", attribute.Int("limit", limit))
+	res, err := m.inner.PendingOutbox(ctx, limit)
+	end(span, err)
+	return res, err
+}
+
+// MarkOutboxSent трассирует вызов MarkOutboxSent.
+func (m *Middleware) MarkOutboxSent(ctx context.Context, ids []int64) error {
+	ctx, span := m.start(ctx, "MarkOutboxSent")
+	err := m.inner.MarkOutboxSent(ctx, ids)
+	end(span, err)
+	return err
+}
+
+// TasksClosedInRange трассирует вызов TasksClosedInRange.
+func (m *Middleware) TasksClosedInRange(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "TasksClosedInRange", attribute.Int64("from", from), attribute.Int64("to", to))
+	res, err := m.inner.TasksClosedInRange(ctx, from, to)
+	end(span, err)
+	return res, err
+}
+
+// TasksUnassigned трассирует вызов TasksUnassigned.
+func (m *Middleware) TasksUnassigned(ctx context.Context) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "TasksUnassigned")
+	res, err := m.inner.TasksUnassigned(ctx)
+	end(span, err)
+	return res, err
+}
+
+// AssignTask трассирует вызов AssignTask.
+func (m *Middleware) AssignTask(ctx context.Context, taskID, userID int) error {
+	ctx, span := m.start(ctx, "AssignTask", attribute.Int("taskID", taskID), attribute.Int("userID", userID))
+	err := m.inner.AssignTask(ctx, taskID, userID)
+	end(span, err)
+	return err
+}
+
+// TaskByExternalID трассирует вызов TaskByExternalID.
+func (m *Middleware) TaskByExternalID(ctx context.Context, system, externalID string) (*storage.Task, error) {
+	ctx, span := m.start(ctx, "TaskByExternalID")
+	res, err := m.inner.TaskByExternalID(ctx, system, externalID)
+	end(span, err)
+	return res, err
+}
+
+// SetNotificationPreference трассирует вызов SetNotificationPreference.
+func (m *Middleware) SetNotificationPreference(ctx context.Context, p storage.NotificationPreference) error {
+	ctx, span := m.start(ctx, "SetNotificationPreference")
+	err := m.inner.SetNotificationPreference(ctx, p)
+	end(span, err)
+	return err
+}
+
+// GetNotificationPreference трассирует вызов GetNotificationPreference.
+func (m *Middleware) GetNotificationPreference(ctx context.Context, userID int) (*storage.NotificationPreference, error) {
+	ctx, span := m.start(ctx, "GetNotificationPreference", attribute.Int("userID", userID))
+	res, err := m.inner.GetNotificationPreference(ctx, userID)
+	end(span, err)
+	return res, err
+}
+
+// UsersSubscribedToTask трассирует вызов UsersSubscribedToTask.
+func (m *Middleware) UsersSubscribedToTask(ctx context.Context, taskID int, event string) ([]storage.User, error) {
+	ctx, span := m.start(ctx, "UsersSubscribedToTask", attribute.Int("taskID", taskID))
+	res, err := m.inner.UsersSubscribedToTask(ctx, taskID, event)
+	end(span, err)
+	return res, err
+}
+
+// WatchTask трассирует вызов WatchTask.
+func (m *Middleware) WatchTask(ctx context.Context, userID, taskID int) error {
+	ctx, span := m.start(ctx, "WatchTask", attribute.Int("userID", userID), attribute.Int("taskID", taskID))
+	err := m.inner.WatchTask(ctx, userID, taskID)
+	end(span, err)
+	return err
+}
+
+// UnwatchTask трассирует вызов UnwatchTask.
+func (m *Middleware) UnwatchTask(ctx context.Context, userID, taskID int) error {
+	ctx, span := m.start(ctx, "UnwatchTask", attribute.Int("userID", userID), attribute.Int("taskID", taskID))
+	err := m.inner.UnwatchTask(ctx, userID, taskID)
+	end(span, err)
+	return err
+}
+
+// WatchersOfTask трассирует вызов WatchersOfTask.
+func (m *Middleware) WatchersOfTask(ctx context.Context, taskID int) ([]storage.User, error) {
+	ctx, span := m.start(ctx, "WatchersOfTask", attribute.Int("taskID", taskID))
+	res, err := m.inner.WatchersOfTask(ctx, taskID)
+	end(span, err)
+	return res, err
+}
+
+// CreateAPIKey трассирует вызов CreateAPIKey.
+func (m *Middleware) CreateAPIKey(ctx context.Context, k storage.APIKey) (string, error) {
+	ctx, span := m.start(ctx, "CreateAPIKey")
+	res, err := m.inner.CreateAPIKey(ctx, k)
+	end(span, err)
+	return res, err
+}
+
+// APIKeyByKey трассирует вызов APIKeyByKey.
+func (m *Middleware) APIKeyByKey(ctx context.Context, raw string) (*storage.APIKey, error) {
+	ctx, span := m.start(ctx, "APIKeyByKey")
+	res, err := m.inner.APIKeyByKey(ctx, raw)
+	end(span, err)
+	return res, err
+}
+
+// RevokeAPIKey трассирует вызов RevokeAPIKey.
+func (m *Middleware) RevokeAPIKey(ctx context.Context, keyID int) error {
+	ctx, span := m.start(ctx, "RevokeAPIKey", attribute.Int("keyID", keyID))
+	err := m.inner.RevokeAPIKey(ctx, keyID)
+	end(span, err)
+	return err
+}
+
+// APIKeysByUser трассирует вызов APIKeysByUser.
+func (m *Middleware) APIKeysByUser(ctx context.Context, userID int) ([]storage.APIKey, error) {
+	ctx, span := m.start(ctx, "APIKeysByUser", attribute.Int("userID", userID))
+	res, err := m.inner.APIKeysByUser(ctx, userID)
+	end(span, err)
+	return res, err
+}
+
+// AssignRole трассирует вызов AssignRole.
+func (m *Middleware) AssignRole(ctx context.Context, userID int, role storage.Role) error {
+	ctx, span := m.start(ctx, "AssignRole", attribute.Int("userID", userID))
+	err := m.inner.AssignRole(ctx, userID, role)
+	end(span, err)
+	return err
+}
+
+// RoleOfUser трассирует вызов RoleOfUser.
+func (m *Middleware) RoleOfUser(ctx context.Context, userID int) (storage.Role, error) {
+	ctx, span := m.start(ctx, "RoleOfUser", attribute.Int("userID", userID))
+	res, err := m.inner.RoleOfUser(ctx, userID)
+	end(span, err)
+	return res, err
+}
+
+// UsersWithRole трассирует вызов UsersWithRole.
+func (m *Middleware) UsersWithRole(ctx context.Context, role storage.Role) ([]storage.User, error) {
+	ctx, span := m.start(ctx, "UsersWithRole")
+	res, err := m.inner.UsersWithRole(ctx, role)
+	end(span, err)
+	return res, err
+}
+
+// ExportUserData трассирует вызов ExportUserData.
+func (m *Middleware) ExportUserData(ctx context.Context, userID int) (*storage.UserDataExport, error) {
+	ctx, span := m.start(ctx, "ExportUserData", attribute.Int("userID", userID))
+	res, err := m.inner.ExportUserData(ctx, userID)
+	end(span, err)
+	return res, err
+}
+
+// EraseUser трассирует вызов EraseUser.
+func (m *Middleware) EraseUser(ctx context.Context, userID int) error {
+	ctx, span := m.start(ctx, "EraseUser", attribute.Int("userID", userID))
+	err := m.inner.EraseUser(ctx, userID)
+	end(span, err)
+	return err
+}
+
+// StaleTasks трассирует вызов StaleTasks.
+func (m *Middleware) StaleTasks(ctx context.Context, staleAfterSeconds int64, now int64) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "StaleTasks", attribute.Int64("staleAfterSeconds", staleAfterSeconds), attribute.Int64("now", now))
+	res, err := m.inner.StaleTasks(ctx, staleAfterSeconds, now)
+	end(span, err)
+	return res, err
+}
+
+// MarkTaskStale трассирует вызов MarkTaskStale.
+func (m *Middleware) MarkTaskStale(ctx context.Context, taskID int) error {
+	ctx, span := m.start(ctx, "MarkTaskStale", attribute.Int("taskID", taskID))
+	err := m.inner.MarkTaskStale(ctx, taskID)
+	end(span, err)
+	return err
+}
+
+// SimilarTasks трассирует вызов SimilarTasks.
+func (m *Middleware) SimilarTasks(ctx context.Context, title string, threshold float64) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "SimilarTasks")
+	res, err := m.inner.SimilarTasks(ctx, title, threshold)
+	end(span, err)
+	return res, err
+}
+
+// AddTaskLink трассирует вызов AddTaskLink.
+func (m *Middleware) AddTaskLink(ctx context.Context, l storage.TaskLinkRecord) (int, error) {
+	ctx, span := m.start(ctx, "AddTaskLink")
+	res, err := m.inner.AddTaskLink(ctx, l)
+	end(span, err)
+	return res, err
+}
+
+// TaskLinks трассирует вызов TaskLinks.
+func (m *Middleware) TaskLinks(ctx context.Context, taskID int) ([]storage.TaskLinkRecord, error) {
+	ctx, span := m.start(ctx, "TaskLinks", attribute.Int("taskID", taskID))
+	res, err := m.inner.TaskLinks(ctx, taskID)
+	end(span, err)
+	return res, err
+}
+
+// DeleteTaskLink трассирует вызов DeleteTaskLink.
+func (m *Middleware) DeleteTaskLink(ctx context.Context, linkID int) error {
+	ctx, span := m.start(ctx, "DeleteTaskLink", attribute.Int("linkID", linkID))
+	err := m.inner.DeleteTaskLink(ctx, linkID)
+	end(span, err)
+	return err
+}
+
+// RegisterWebhook трассирует вызов RegisterWebhook.
+func (m *Middleware) RegisterWebhook(ctx context.Context, w storage.Webhook) (int, error) {
+	ctx, span := m.start(ctx, "RegisterWebhook")
+	res, err := m.inner.RegisterWebhook(ctx, w)
+	end(span, err)
+	return res, err
+}
+
+// Webhooks трассирует вызов Webhooks.
+func (m *Middleware) Webhooks(ctx context.Context) ([]storage.Webhook, error) {
+	ctx, span := m.start(ctx, "Webhooks")
+	res, err := m.inner.Webhooks(ctx)
+	end(span, err)
+	return res, err
+}
+
+// WebhookByID трассирует вызов WebhookByID.
+func (m *Middleware) WebhookByID(ctx context.Context, webhookID int) (*storage.Webhook, error) {
+	ctx, span := m.start(ctx, "WebhookByID", attribute.Int("webhookID", webhookID))
+	res, err := m.inner.WebhookByID(ctx, webhookID)
+	end(span, err)
+	return res, err
+}
+
+// UpdateWebhook трассирует вызов UpdateWebhook.
+func (m *Middleware) UpdateWebhook(ctx context.Context, w storage.Webhook) error {
+	ctx, span := m.start(ctx, "UpdateWebhook")
+	err := m.inner.UpdateWebhook(ctx, w)
+	end(span, err)
+	return err
+}
+
+// DeleteWebhook трассирует вызов DeleteWebhook.
+func (m *Middleware) DeleteWebhook(ctx context.Context, webhookID int) error {
+	ctx, span := m.start(ctx, "DeleteWebhook", attribute.Int("webhookID", webhookID))
+	err := m.inner.DeleteWebhook(ctx, webhookID)
+	end(span, err)
+	return err
+}
+
+// LockTask трассирует вызов LockTask.
+func (m *Middleware) LockTask(ctx context.Context, taskID int) error {
+	ctx, span := m.start(ctx, "LockTask", attribute.Int("taskID", taskID))
+	err := m.inner.LockTask(ctx, taskID)
+	end(span, err)
+	return err
+}
+
+// UnlockTask трассирует вызов UnlockTask.
+func (m *Middleware) UnlockTask(ctx context.Context, taskID int) error {
+	ctx, span := m.start(ctx, "UnlockTask", attribute.Int("taskID", taskID))
+	err := m.inner.UnlockTask(ctx, taskID)
+	end(span, err)
+	return err
+}
+
+// UpdateTasksAssignee трассирует вызов UpdateTasksAssignee.
+func (m *Middleware) UpdateTasksAssignee(ctx context.Context, taskIDs []int, assigneeID int) (int64, error) {
+	ctx, span := m.start(ctx, "UpdateTasksAssignee", attribute.Int("assigneeID", assigneeID))
+	res, err := m.inner.UpdateTasksAssignee(ctx, taskIDs, assigneeID)
+	end(span, err)
+	return res, err
+}
+
+// UpdateTasksStatus трассирует вызов UpdateTasksStatus.
+func (m *Middleware) UpdateTasksStatus(ctx context.Context, taskIDs []int, status storage.Status) (int64, error) {
+	ctx, span := m.start(ctx, "UpdateTasksStatus")
+	res, err := m.inner.UpdateTasksStatus(ctx, taskIDs, status)
+	end(span, err)
+	return res, err
+}
+
+// TasksByTag трассирует вызов TasksByTag.
+func (m *Middleware) TasksByTag(ctx context.Context, tag string) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "TasksByTag")
+	res, err := m.inner.TasksByTag(ctx, tag)
+	end(span, err)
+	return res, err
+}
+
+// AllTags трассирует вызов AllTags.
+func (m *Middleware) AllTags(ctx context.Context) ([]string, error) {
+	ctx, span := m.start(ctx, "AllTags")
+	res, err := m.inner.AllTags(ctx)
+	end(span, err)
+	return res, err
+}
+
+// TasksByRank трассирует вызов TasksByRank.
+func (m *Middleware) TasksByRank(ctx context.Context) ([]storage.Task, error) {
+	ctx, span := m.start(ctx, "TasksByRank")
+	res, err := m.inner.TasksByRank(ctx)
+	end(span, err)
+	return res, err
+}
+
+// UpdateTaskRank трассирует вызов UpdateTaskRank.
+func (m *Middleware) UpdateTaskRank(ctx context.Context, taskID int, rank float64) error {
+	ctx, span := m.start(ctx, "UpdateTaskRank", attribute.Int("taskID", taskID))
+	err := m.inner.UpdateTaskRank(ctx, taskID, rank)
+	end(span, err)
+	return err
+}
@@ -0,0 +1,88 @@
+package otelmw
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"skillfactory/30.8.1/pkg/storage/mock"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// testTracer запоминает созданные span.
+type testTracer struct {
+	embedded.Tracer
+	spans []*testSpan
+}
+
+func (tr *testTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	cfg := trace.NewSpanStartConfig(opts...)
+	span := &testSpan{
+		name:  name,
+		attrs: cfg.Attributes(),
+	}
+	tr.spans = append(tr.spans, span)
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+// testSpan запоминает имя, атрибуты, статус и завершение span.
+type testSpan struct {
+	noop.Span
+	name   string
+	attrs  []attribute.KeyValue
+	status codes.Code
+	errs   []error
+	ended  bool
+}
+
+func (s *testSpan) SetStatus(code codes.Code, description string)    { s.status = code }
+func (s *testSpan) RecordError(err error, opts ...trace.EventOption) { s.errs = append(s.errs, err) }
+func (s *testSpan) End(opts ...trace.SpanEndOption)                  { s.ended = true }
+
+func TestSpans(t *testing.T) {
+	ctx := context.Background()
+	tracer := &testTracer{}
+	var innerSpan trace.Span
+	m := New(&mock.Mock{
+		TaskByIdFunc: func(ctx context.Context, taskId int) (*storage.Task, error) {
+			innerSpan = trace.SpanFromContext(ctx)
+			return &storage.Task{ID: taskId}, nil
+		},
+		DeleteTaskFunc: func(ctx context.Context, taskId int) error {
+			return storage.ErrNotFound
+		},
+	}, tracer)
+
+	_, err := m.TaskById(ctx, 7)
+	if err != nil {
+		t.Fatalf("TaskById() error = %v", err)
+	}
+	_ = m.DeleteTask(ctx, 8)
+
+	if len(tracer.spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(tracer.spans))
+	}
+	ok, failed := tracer.spans[0], tracer.spans[1]
+
+	if ok.name != "storage.TaskById" || !ok.ended || ok.status != codes.Unset {
+		t.Errorf("TaskById span = %+v, want ended storage.TaskById without error", ok)
+	}
+	if len(ok.attrs) != 1 || ok.attrs[0] != attribute.Int("taskID", 7) {
+		t.Errorf("TaskById span attributes = %v, want taskID=7", ok.attrs)
+	}
+	// хранилище вызывается с контекстом дочернего span
+	if innerSpan != ok {
+		t.Errorf("inner storage got span %v, want TaskById span", innerSpan)
+	}
+
+	if failed.name != "storage.DeleteTask" || !failed.ended || failed.status != codes.Error {
+		t.Errorf("DeleteTask span = %+v, want ended storage.DeleteTask with error status", failed)
+	}
+	if len(failed.errs) != 1 || failed.errs[0] != storage.ErrNotFound {
+		t.Errorf("DeleteTask span errors = %v, want ErrNotFound", failed.errs)
+	}
+}
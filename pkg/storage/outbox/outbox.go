@@ -0,0 +1,81 @@
+package outbox
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"time"
+)
+
+// Publisher отправляет сообщение брокеру.
+type Publisher interface {
+	Publish(ctx context.Context, msg storage.OutboxMessage) error
+}
+
+// Relay периодически читает неотправленные сообщения outbox,
+// отправляет их брокеру и отмечает отправленными.
+//
+// Доставка выполняется "хотя бы один раз": если сообщение отправлено,
+// но не отмечено (например, из-за сбоя БД), оно будет отправлено повторно.
+type Relay struct {
+	store     storage.Interface
+	publisher Publisher
+	interval  time.Duration
+	batchSize int
+}
+
+// Конструктор, принимает хранилище, брокер, интервал опроса
+// и максимальное количество сообщений за один опрос.
+func New(store storage.Interface, publisher Publisher, interval time.Duration, batchSize int) *Relay {
+	r := Relay{
+		store:     store,
+		publisher: publisher,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+	return &r
+}
+
+// Run опрашивает outbox с заданным интервалом до отмены ctx.
+// Ошибки отдельных опросов не прерывают работу: неотправленные
+// сообщения будут отправлены при следующем опросе.
+func (r *Relay) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.Flush(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Flush выполняет один опрос: отправляет не более batchSize сообщений
+// в порядке записи и отмечает отправленные. При ошибке отправки
+// остальные сообщения не отправляются, чтобы сохранить порядок.
+func (r *Relay) Flush(ctx context.Context) error {
+	msgs, err := r.store.PendingOutbox(ctx, r.batchSize)
+	if err != nil {
+		return err
+	}
+
+	var sent []int64
+	for _, m := range msgs {
+		err = r.publisher.Publish(ctx, m)
+		if err != nil {
+			break
+		}
+		sent = append(sent, m.ID)
+	}
+
+	if len(sent) > 0 {
+		markErr := r.store.MarkOutboxSent(ctx, sent)
+		if markErr != nil {
+			return markErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,129 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"skillfactory/30.8.1/pkg/storage/mock"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// publisher запоминает отправленные сообщения и отказывает
+// в отправке сообщения с темой failTopic.
+type publisher struct {
+	mu        sync.Mutex
+	failTopic string
+	published []int64
+}
+
+func (p *publisher) Publish(ctx context.Context, msg storage.OutboxMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if msg.Topic == p.failTopic {
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, msg.ID)
+	return nil
+}
+
+// newStore возвращает заглушку с сообщениями msgs, которая
+// передаёт отмеченные сообщения в канал marked.
+func newStore(msgs []storage.OutboxMessage, marked chan<- []int64) *mock.Mock {
+	return &mock.Mock{
+		PendingOutboxFunc: func(ctx context.Context, limit int) ([]storage.OutboxMessage, error) {
+			return msgs[:min(limit, len(msgs))], nil
+		},
+		MarkOutboxSentFunc: func(ctx context.Context, ids []int64) error {
+			marked <- ids
+			return nil
+		},
+	}
+}
+
+func TestFlush(t *testing.T) {
+	msgs := []storage.OutboxMessage{
+		{ID: 1, Topic: "a"},
+		{ID: 2, Topic: "b"},
+		{ID: 3, Topic: "c"},
+	}
+	tests := []struct {
+		name      string
+		batchSize int
+		failTopic string
+		wantSent  []int64
+		wantErr   bool
+	}{
+		{"all", 10, "", []int64{1, 2, 3}, false},
+		{"batch size", 2, "", []int64{1, 2}, false},
+		// после ошибки отправки остальные сообщения не отправляются
+		{"publish error", 10, "b", []int64{1}, true},
+		{"first fails", 10, "a", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			marked := make(chan []int64, 1)
+			p := &publisher{failTopic: tt.failTopic}
+			r := New(newStore(msgs, marked), p, time.Hour, tt.batchSize)
+
+			err := r.Flush(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Flush() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(p.published, tt.wantSent) {
+				t.Errorf("published = %v, want %v", p.published, tt.wantSent)
+			}
+
+			var got []int64
+			select {
+			case got = <-marked:
+			default:
+			}
+			if !slices.Equal(got, tt.wantSent) {
+				t.Errorf("MarkOutboxSent() ids = %v, want %v", got, tt.wantSent)
+			}
+		})
+	}
+}
+
+func TestFlushStoreError(t *testing.T) {
+	wantErr := errors.New("db unavailable")
+	store := &mock.Mock{
+		PendingOutboxFunc: func(ctx context.Context, limit int) ([]storage.OutboxMessage, error) {
+			return []storage.OutboxMessage{{ID: 1, Topic: "a"}}, nil
+		},
+		MarkOutboxSentFunc: func(ctx context.Context, ids []int64) error {
+			return wantErr
+		},
+	}
+	r := New(store, &publisher{}, time.Hour, 10)
+	if err := r.Flush(context.Background()); !errors.Is(err, wantErr) {
+		t.Errorf("Flush() error = %v, want %v", err, wantErr)
+	}
+}
+
+func TestRun(t *testing.T) {
+	marked := make(chan []int64, 1)
+	r := New(newStore([]storage.OutboxMessage{{ID: 1, Topic: "a"}}, marked), &publisher{}, time.Hour, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- r.Run(ctx) }()
+
+	// первый опрос выполняется сразу, не дожидаясь интервала
+	select {
+	case ids := <-marked:
+		if !slices.Equal(ids, []int64{1}) {
+			t.Errorf("MarkOutboxSent() ids = %v, want [1]", ids)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not flush outbox")
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// RecordActivity добавляет событие в ленту активности.
+// Если время события не задано, используется текущее.
+func (s *Storage) RecordActivity(ctx context.Context, e storage.ActivityEvent) error {
+	ctx, cancel := s.withTimeout(ctx, "RecordActivity")
+	defer cancel()
+
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO task_activity (kind, task_id, user_id, payload, occurred_at)
+		VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, 0), extract(epoch from now())));
+	`,
+		e.Kind,
+		e.TaskID,
+		e.UserID,
+		e.Payload,
+		e.OccurredAt,
+	)
+	return wrapErr(err)
+}
+
+// ActivityFeed возвращает не более limit последних событий задачи,
+// начиная с самых новых.
+func (s *Storage) ActivityFeed(ctx context.Context, taskID int, limit int) ([]storage.ActivityEvent, error) {
+	ctx, cancel := s.withTimeout(ctx, "ActivityFeed")
+	defer cancel()
+
+	if limit <= 0 {
+		return nil, fmt.Errorf("%w: limit must be positive", storage.ErrInvalidArgument)
+	}
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT id, kind, task_id, user_id, payload, occurred_at
+		FROM task_activity
+		WHERE task_id = $1
+		ORDER BY occurred_at DESC, id DESC
+		LIMIT $2;
+	`,
+		taskID,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectActivity(rows)
+}
+
+// GlobalActivityFeed возвращает не более limit последних событий
+// по всем задачам, начиная с самых новых.
+func (s *Storage) GlobalActivityFeed(ctx context.Context, limit int) ([]storage.ActivityEvent, error) {
+	ctx, cancel := s.withTimeout(ctx, "GlobalActivityFeed")
+	defer cancel()
+
+	if limit <= 0 {
+		return nil, fmt.Errorf("%w: limit must be positive", storage.ErrInvalidArgument)
+	}
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT id, kind, task_id, user_id, payload, occurred_at
+		FROM task_activity
+		ORDER BY occurred_at DESC, id DESC
+		LIMIT $1;
+	`,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectActivity(rows)
+}
+
+// collectActivity вычитывает все строки результата запроса в слайс событий.
+func collectActivity(rows pgx.Rows) ([]storage.ActivityEvent, error) {
+	defer rows.Close()
+
+	var events []storage.ActivityEvent
+	for rows.Next() {
+		var e storage.ActivityEvent
+		err := rows.Scan(&e.ID, &e.Kind, &e.TaskID, &e.UserID, &e.Payload, &e.OccurredAt)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
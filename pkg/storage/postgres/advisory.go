@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// advisoryLocks хранит соединения, на которых захвачены
+// рекомендательные блокировки: блокировка уровня сессии
+// освобождается только на том же соединении.
+type advisoryLocks struct {
+	mu    sync.Mutex
+	conns map[int64]*pgxpool.Conn // ключ блокировки -> соединение
+}
+
+func newAdvisoryLocks() *advisoryLocks {
+	l := advisoryLocks{
+		conns: make(map[int64]*pgxpool.Conn),
+	}
+	return &l
+}
+
+// AcquireAdvisoryLock захватывает рекомендательную блокировку key
+// (pg_advisory_lock), ожидая её освобождения другими сессиями.
+// Блокировка удерживает соединение пула до ReleaseAdvisoryLock.
+//
+// Блокировки не реентерабельны: повторный захват того же ключа
+// через одно хранилище ждёт освобождения первого захвата.
+func (s *Storage) AcquireAdvisoryLock(ctx context.Context, key int64) error {
+	conn, err := s.primary.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = conn.Exec(ctx, "SELECT pg_advisory_lock($1);", key)
+	if err != nil {
+		conn.Release()
+		return err
+	}
+	s.locks.add(key, conn)
+	return nil
+}
+
+// TryAdvisoryLock пытается захватить рекомендательную блокировку key
+// без ожидания (pg_try_advisory_lock). Возвращает false, если
+// блокировка захвачена другой сессией.
+func (s *Storage) TryAdvisoryLock(ctx context.Context, key int64) (bool, error) {
+	conn, err := s.primary.Acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	var ok bool
+	err = conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1);", key).Scan(&ok)
+	if err != nil || !ok {
+		conn.Release()
+		return false, err
+	}
+	s.locks.add(key, conn)
+	return true, nil
+}
+
+// ReleaseAdvisoryLock освобождает блокировку key, захваченную
+// AcquireAdvisoryLock или TryAdvisoryLock, и возвращает соединение в пул.
+func (s *Storage) ReleaseAdvisoryLock(ctx context.Context, key int64) error {
+	conn := s.locks.remove(key)
+	if conn == nil {
+		return fmt.Errorf("advisory lock %d is not held", key)
+	}
+
+	var ok bool
+	err := conn.QueryRow(ctx, "SELECT pg_advisory_unlock($1);", key).Scan(&ok)
+	if err != nil {
+		// состояние блокировки неизвестно, соединение закрывается,
+		// чтобы сервер освободил блокировку вместе с сессией
+		conn.Conn().Close(context.Background())
+		conn.Release()
+		return err
+	}
+	conn.Release()
+	if !ok {
+		return fmt.Errorf("advisory lock %d was not held by the session", key)
+	}
+	return nil
+}
+
+// add запоминает соединение, на котором захвачена блокировка.
+func (l *advisoryLocks) add(key int64, conn *pgxpool.Conn) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.conns[key] = conn
+}
+
+// remove возвращает и забывает соединение блокировки, nil - если его нет.
+func (l *advisoryLocks) remove(key int64) *pgxpool.Conn {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	conn := l.conns[key]
+	delete(l.conns, key)
+	return conn
+}
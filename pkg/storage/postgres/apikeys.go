@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+)
+
+// apiKeyColumns перечисляет столбцы ключа API в порядке полей,
+// которые сканирует scanAPIKey.
+const apiKeyColumns = `id, user_id, key_prefix, description, created_at, expires_at, last_used_at`
+
+// scanAPIKey сканирует строку со столбцами apiKeyColumns.
+func scanAPIKey(row interface{ Scan(...any) error }, k *storage.APIKey) error {
+	return row.Scan(&k.ID, &k.UserID, &k.Key, &k.Description, &k.CreatedAt, &k.ExpiresAt, &k.LastUsedAt)
+}
+
+// CreateAPIKey создаёт ключ API пользователя k.UserID и возвращает его.
+// Ключ возвращается только здесь, в БД хранится его хеш.
+func (s *Storage) CreateAPIKey(ctx context.Context, k storage.APIKey) (string, error) {
+	ctx, cancel := s.withTimeout(ctx, "CreateAPIKey")
+	defer cancel()
+
+	raw, prefix, hash, err := storage.GenerateAPIKey()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = s.pool.Exec(ctx, `
+		INSERT INTO api_keys (user_id, key_prefix, key_hash, description, expires_at)
+		VALUES ($1, $2, $3, $4, $5);
+	`,
+		k.UserID,
+		prefix,
+		hash,
+		k.Description,
+		k.ExpiresAt,
+	)
+	if err != nil {
+		return "", wrapErr(err)
+	}
+	return raw, nil
+}
+
+// APIKeyByKey проверяет ключ API и возвращает его описание,
+// отмечая время использования. Для неизвестного, отозванного
+// или истёкшего ключа возвращает ErrNotFound.
+func (s *Storage) APIKeyByKey(ctx context.Context, raw string) (*storage.APIKey, error) {
+	ctx, cancel := s.withTimeout(ctx, "APIKeyByKey")
+	defer cancel()
+
+	prefix, secret, err := storage.ParseAPIKey(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		id   int
+		hash []byte
+	)
+	err = s.pool.QueryRow(ctx, `
+		SELECT id, key_hash
+		FROM api_keys
+		WHERE key_prefix = $1
+			AND revoked_at IS NULL
+			AND (expires_at IS NULL OR expires_at > extract(epoch from now()));
+	`,
+		prefix,
+	).Scan(&id, &hash)
+	if err != nil {
+		return nil, wrapErr(err)
+	}
+	err = storage.CheckAPIKeySecret(hash, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	// условие отзыва проверяется повторно, если ключ отозвали после чтения
+	var k storage.APIKey
+	err = scanAPIKey(s.pool.QueryRow(ctx, `
+		UPDATE api_keys
+		SET last_used_at = extract(epoch from now())
+		WHERE id = $1 AND revoked_at IS NULL
+		RETURNING `+apiKeyColumns+`;
+	`,
+		id,
+	), &k)
+	if err != nil {
+		return nil, wrapErr(err)
+	}
+	return &k, nil
+}
+
+// RevokeAPIKey отзывает ключ API.
+func (s *Storage) RevokeAPIKey(ctx context.Context, keyID int) error {
+	ctx, cancel := s.withTimeout(ctx, "RevokeAPIKey")
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE api_keys
+		SET revoked_at = extract(epoch from now())
+		WHERE id = $1 AND revoked_at IS NULL;
+	`,
+		keyID,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+// APIKeysByUser возвращает неотозванные ключи API пользователя.
+func (s *Storage) APIKeysByUser(ctx context.Context, userID int) ([]storage.APIKey, error) {
+	ctx, cancel := s.withTimeout(ctx, "APIKeysByUser")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+apiKeyColumns+`
+		FROM api_keys
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY id;
+	`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []storage.APIKey
+	for rows.Next() {
+		var k storage.APIKey
+		err = scanAPIKey(rows, &k)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, k)
+	}
+
+	return keys, rows.Err()
+}
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+)
+
+// taskRelation описывает таблицу, записи которой ссылаются на задачу
+// и переносятся в архив вместе с ней.
+type taskRelation struct {
+	table string
+	// условие отбора записей задачи $1 (псевдоним таблицы - r)
+	match string
+	// условие, при котором запись r возвращается из архива: записи,
+	// ссылающиеся на удалённые за это время метки, спринты, пользователей
+	// или задачи, не восстанавливаются, как если бы они были удалены каскадно
+	restore string
+}
+
+// taskRelations перечисляет таблицы со ссылкой на tasks(id).
+var taskRelations = []taskRelation{
+	{"tasks_labels", "r.task_id = $1", "EXISTS (SELECT 1 FROM labels WHERE id = r.label_id)"},
+	{"comments", "r.task_id = $1", "TRUE"},
+	{"time_entries", "r.task_id = $1", "TRUE"},
+	{"checklist_items", "r.task_id = $1", "TRUE"},
+	{"task_dependencies", "r.task_id = $1 OR r.depends_on_id = $1",
+		"EXISTS (SELECT 1 FROM tasks WHERE id = r.task_id) AND EXISTS (SELECT 1 FROM tasks WHERE id = r.depends_on_id)"},
+	{"audit_log", "r.task_id = $1", "TRUE"},
+	{"assignment_events", "r.task_id = $1", "TRUE"},
+	{"recurrence_rules", "r.task_id = $1", "TRUE"},
+	{"task_sprints", "r.task_id = $1", "EXISTS (SELECT 1 FROM sprints WHERE id = r.sprint_id)"},
+	{"task_watchers", "r.task_id = $1", "EXISTS (SELECT 1 FROM users WHERE id = r.user_id)"},
+	{"task_links", "r.task_id = $1 OR r.linked_task_id = $1",
+		"EXISTS (SELECT 1 FROM tasks WHERE id = r.task_id) AND EXISTS (SELECT 1 FROM tasks WHERE id = r.linked_task_id)"},
+}
+
+// ArchiveTask переносит задачу из таблицы tasks в архив.
+// Связанные записи (метки, комментарии, учёт времени, чек-лист и т.п.)
+// сохраняются в tasks_archive_relations и восстанавливаются UnarchiveTask.
+func (s *Storage) ArchiveTask(ctx context.Context, taskID int) error {
+	ctx, cancel := s.withTimeout(ctx, "ArchiveTask")
+	defer cancel()
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO tasks_archive (`+taskColumns+`)
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE id = $1;
+	`,
+		taskID,
+	)
+	if err != nil {
+		return wrapErr(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrNotFound
+	}
+
+	// без копии записи были бы удалены каскадно вместе с задачей
+	for _, rel := range taskRelations {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO tasks_archive_relations (task_id, source, data)
+			SELECT $1, '`+rel.table+`', to_jsonb(r)
+			FROM `+rel.table+` r
+			WHERE `+rel.match+`;
+		`,
+			taskID,
+		)
+		if err != nil {
+			return wrapErr(err)
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM tasks
+		WHERE id = $1;
+	`,
+		taskID,
+	)
+	if err != nil {
+		return wrapErr(err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ArchivedTasks возвращает задачи из архива.
+func (s *Storage) ArchivedTasks(ctx context.Context) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "ArchivedTasks")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks_archive
+		ORDER BY id;
+	`)
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
+}
+
+// UnarchiveTask возвращает задачу из архива в таблицу tasks с прежним ID
+// вместе со связанными записями.
+func (s *Storage) UnarchiveTask(ctx context.Context, taskID int) error {
+	ctx, cancel := s.withTimeout(ctx, "UnarchiveTask")
+	defer cancel()
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO tasks (`+taskColumns+`)
+		SELECT `+taskColumns+`
+		FROM tasks_archive
+		WHERE id = $1;
+	`,
+		taskID,
+	)
+	if err != nil {
+		return wrapErr(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrNotFound
+	}
+
+	for _, rel := range taskRelations {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO `+rel.table+`
+			SELECT r.*
+			FROM tasks_archive_relations a,
+				jsonb_populate_record(NULL::`+rel.table+`, a.data) r
+			WHERE a.task_id = $1 AND a.source = '`+rel.table+`' AND `+rel.restore+`;
+		`,
+			taskID,
+		)
+		if err != nil {
+			return wrapErr(err)
+		}
+	}
+
+	// записи tasks_archive_relations удаляются каскадно
+	_, err = tx.Exec(ctx, `
+		DELETE FROM tasks_archive
+		WHERE id = $1;
+	`,
+		taskID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
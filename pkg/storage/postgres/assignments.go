@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+)
+
+// AssignmentHistory возвращает историю назначения задачи в порядке изменений.
+// Записи при изменении исполнителя добавляет триггер в БД.
+func (s *Storage) AssignmentHistory(ctx context.Context, taskID int) ([]storage.AssignmentEvent, error) {
+	ctx, cancel := s.withTimeout(ctx, "AssignmentHistory")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT id, task_id, from_user_id, to_user_id, changed_by, changed_at
+		FROM assignment_events
+		WHERE task_id = $1
+		ORDER BY id;
+	`,
+		taskID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []storage.AssignmentEvent
+	for rows.Next() {
+		var e storage.AssignmentEvent
+		err = rows.Scan(&e.ID, &e.TaskID, &e.FromUserID, &e.ToUserID, &e.ChangedBy, &e.ChangedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
+
+// RecordAssignment добавляет запись в историю назначения вручную,
+// например при переносе истории из другой системы.
+// Если время изменения не задано, используется текущее.
+func (s *Storage) RecordAssignment(ctx context.Context, e storage.AssignmentEvent) error {
+	ctx, cancel := s.withTimeout(ctx, "RecordAssignment")
+	defer cancel()
+
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO assignment_events (task_id, from_user_id, to_user_id, changed_by, changed_at)
+		VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, 0), extract(epoch from now())));
+	`,
+		e.TaskID,
+		e.FromUserID,
+		e.ToUserID,
+		e.ChangedBy,
+		e.ChangedAt,
+	)
+	return wrapErr(err)
+}
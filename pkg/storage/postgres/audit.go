@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+)
+
+// AuditLog возвращает журнал изменений задачи в порядке записи.
+func (s *Storage) AuditLog(ctx context.Context, taskID int) ([]storage.AuditEntry, error) {
+	ctx, cancel := s.withTimeout(ctx, "AuditLog")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT id, task_id, user_id, field, old_value, new_value, changed_at
+		FROM audit_log
+		WHERE task_id = $1
+		ORDER BY id;
+	`,
+		taskID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []storage.AuditEntry
+	for rows.Next() {
+		var e storage.AuditEntry
+		err = rows.Scan(&e.ID, &e.TaskID, &e.UserID, &e.Field, &e.OldValue, &e.NewValue, &e.ChangedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// RecordChange добавляет запись в журнал изменений.
+// Если время изменения не задано, используется текущее.
+func (s *Storage) RecordChange(ctx context.Context, e storage.AuditEntry) error {
+	ctx, cancel := s.withTimeout(ctx, "RecordChange")
+	defer cancel()
+
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO audit_log (task_id, user_id, field, old_value, new_value, changed_at)
+		VALUES ($1, $2, $3, $4, $5, COALESCE(NULLIF($6, 0), extract(epoch from now())));
+	`,
+		e.TaskID,
+		e.UserID,
+		e.Field,
+		e.OldValue,
+		e.NewValue,
+		e.ChangedAt,
+	)
+	return wrapErr(err)
+}
@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AddChecklistItem добавляет пункт в чек-лист задачи и возвращает его id.
+func (s *Storage) AddChecklistItem(ctx context.Context, item storage.ChecklistItem) (int, error) {
+	ctx, cancel := s.withTimeout(ctx, "AddChecklistItem")
+	defer cancel()
+
+	var id int
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO checklist_items (task_id, body, done, position)
+		VALUES ($1, $2, $3, $4) RETURNING id;
+	`,
+		item.TaskID,
+		item.Body,
+		item.Done,
+		item.Position,
+	).Scan(&id)
+	return id, wrapErr(err)
+}
+
+// ChecklistByTask возвращает пункты чек-листа задачи в порядке их позиций.
+func (s *Storage) ChecklistByTask(ctx context.Context, taskID int) ([]storage.ChecklistItem, error) {
+	ctx, cancel := s.withTimeout(ctx, "ChecklistByTask")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT id, task_id, body, done, position
+		FROM checklist_items
+		WHERE task_id = $1
+		ORDER BY position, id;
+	`,
+		taskID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []storage.ChecklistItem
+	for rows.Next() {
+		var item storage.ChecklistItem
+		err = rows.Scan(&item.ID, &item.TaskID, &item.Body, &item.Done, &item.Position)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// UpdateChecklistItem обновляет текст, отметку о выполнении и позицию пункта.
+func (s *Storage) UpdateChecklistItem(ctx context.Context, item storage.ChecklistItem) error {
+	ctx, cancel := s.withTimeout(ctx, "UpdateChecklistItem")
+	defer cancel()
+
+	_, err := s.pool.Exec(ctx, `
+		UPDATE checklist_items
+		SET (body, done, position) = ($2, $3, $4)
+		WHERE id = $1;
+	`,
+		item.ID,
+		item.Body,
+		item.Done,
+		item.Position,
+	)
+	return err
+}
+
+// DeleteChecklistItem удаляет пункт чек-листа по ID.
+func (s *Storage) DeleteChecklistItem(ctx context.Context, itemID int) error {
+	ctx, cancel := s.withTimeout(ctx, "DeleteChecklistItem")
+	defer cancel()
+
+	_, err := s.pool.Exec(ctx, `
+		DELETE FROM checklist_items
+		WHERE id = $1;
+	`,
+		itemID,
+	)
+	return err
+}
+
+// ReorderChecklist задаёт порядок пунктов чек-листа задачи:
+// позиция пункта равна его индексу в orderedIDs.
+// Все позиции обновляются партией запросов в одной транзакции.
+func (s *Storage) ReorderChecklist(ctx context.Context, taskID int, orderedIDs []int) error {
+	ctx, cancel := s.withTimeout(ctx, "ReorderChecklist")
+	defer cancel()
+
+	batch := pgx.Batch{}
+	for pos, id := range orderedIDs {
+		batch.Queue(`
+			UPDATE checklist_items
+			SET position = $3
+			WHERE id = $1 AND task_id = $2;
+		`,
+			id,
+			taskID,
+			pos,
+		)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.SendBatch(ctx, &batch).Close()
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
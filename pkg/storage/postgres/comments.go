@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+)
+
+// AddComment создаёт комментарий к задаче и возвращает его id.
+// Комментарий удаляется вместе с задачей (ON DELETE CASCADE).
+func (s *Storage) AddComment(ctx context.Context, c storage.Comment) (int, error) {
+	ctx, cancel := s.withTimeout(ctx, "AddComment")
+	defer cancel()
+
+	if c.TaskID == 0 || c.AuthorID == 0 {
+		return 0, fmt.Errorf("%w: comment task and author are required", storage.ErrInvalidArgument)
+	}
+
+	var id int
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO comments (task_id, author_id, body)
+		VALUES ($1, $2, $3) RETURNING id;
+	`,
+		c.TaskID,
+		c.AuthorID,
+		c.Body,
+	).Scan(&id)
+	return id, wrapErr(err)
+}
+
+// CommentsByTask возвращает комментарии к задаче в порядке создания.
+func (s *Storage) CommentsByTask(ctx context.Context, taskID int) ([]storage.Comment, error) {
+	ctx, cancel := s.withTimeout(ctx, "CommentsByTask")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT id, task_id, author_id, body, created_at
+		FROM comments
+		WHERE task_id = $1
+		ORDER BY id;
+	`,
+		taskID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []storage.Comment
+	for rows.Next() {
+		var c storage.Comment
+		err = rows.Scan(&c.ID, &c.TaskID, &c.AuthorID, &c.Body, &c.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		comments = append(comments, c)
+	}
+
+	return comments, rows.Err()
+}
+
+// UpdateComment обновляет текст комментария.
+func (s *Storage) UpdateComment(ctx context.Context, c storage.Comment) error {
+	ctx, cancel := s.withTimeout(ctx, "UpdateComment")
+	defer cancel()
+
+	_, err := s.pool.Exec(ctx, `
+		UPDATE comments
+		SET body = $2
+		WHERE id = $1;
+	`,
+		c.ID,
+		c.Body,
+	)
+	return err
+}
+
+// DeleteComment удаляет комментарий по ID.
+func (s *Storage) DeleteComment(ctx context.Context, commentID int) error {
+	ctx, cancel := s.withTimeout(ctx, "DeleteComment")
+	defer cancel()
+
+	_, err := s.pool.Exec(ctx, `
+		DELETE FROM comments
+		WHERE id = $1;
+	`,
+		commentID,
+	)
+	return err
+}
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+)
+
+// AddDependency отмечает, что задача taskID зависит от задачи dependsOnID.
+// Повторное добавление той же зависимости возвращает storage.ErrConflict.
+// Взаимная зависимость двух задач друг от друга считается циклом
+// и отклоняется с ошибкой storage.ErrInvalidArgument.
+func (s *Storage) AddDependency(ctx context.Context, taskID, dependsOnID int) error {
+	ctx, cancel := s.withTimeout(ctx, "AddDependency")
+	defer cancel()
+
+	if taskID == dependsOnID {
+		return fmt.Errorf("%w: task %d cannot depend on itself", storage.ErrInvalidArgument, taskID)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var cycle bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM task_dependencies
+			WHERE task_id = $1 AND depends_on_id = $2
+		);
+	`,
+		dependsOnID,
+		taskID,
+	).Scan(&cycle)
+	if err != nil {
+		return err
+	}
+	if cycle {
+		return fmt.Errorf("%w: task %d already depends on task %d", storage.ErrInvalidArgument, dependsOnID, taskID)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO task_dependencies (task_id, depends_on_id)
+		VALUES ($1, $2);
+	`,
+		taskID,
+		dependsOnID,
+	)
+	if err != nil {
+		return wrapErr(err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RemoveDependency удаляет зависимость задачи taskID от задачи dependsOnID.
+func (s *Storage) RemoveDependency(ctx context.Context, taskID, dependsOnID int) error {
+	ctx, cancel := s.withTimeout(ctx, "RemoveDependency")
+	defer cancel()
+
+	_, err := s.pool.Exec(ctx, `
+		DELETE FROM task_dependencies
+		WHERE task_id = $1 AND depends_on_id = $2;
+	`,
+		taskID,
+		dependsOnID,
+	)
+	return err
+}
+
+// DependenciesOf возвращает задачи, от которых зависит задача taskID.
+func (s *Storage) DependenciesOf(ctx context.Context, taskID int) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "DependenciesOf")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE id IN (
+			SELECT depends_on_id FROM task_dependencies
+			WHERE task_id = $1
+		) AND deleted_at IS NULL
+		ORDER BY id;
+	`,
+		taskID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
+}
+
+// BlockedBy возвращает задачи, заблокированные задачей taskID,
+// то есть задачи, которые от неё зависят.
+func (s *Storage) BlockedBy(ctx context.Context, taskID int) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "BlockedBy")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE id IN (
+			SELECT task_id FROM task_dependencies
+			WHERE depends_on_id = $1
+		) AND deleted_at IS NULL
+		ORDER BY id;
+	`,
+		taskID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
+}
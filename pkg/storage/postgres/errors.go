@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Коды ошибок PostgreSQL при нарушении ограничений
+// уникальности и CHECK.
+const (
+	uniqueViolation  = "23505"
+	checkViolation   = "23514"
+	lockNotAvailable = "55P03"
+)
+
+// wrapErr приводит ошибки драйвера к ошибкам пакета storage,
+// чтобы вызывающему коду не требовалось импортировать pgx.
+// Исходная ошибка остаётся доступна через errors.Is и errors.As.
+func wrapErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%w: %w", storage.ErrNotFound, err)
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case uniqueViolation:
+			return fmt.Errorf("%w: %w", storage.ErrConflict, err)
+		case checkViolation:
+			return fmt.Errorf("%w: %w", storage.ErrInvalidArgument, err)
+		}
+	}
+	return err
+}
@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestWrapErr(t *testing.T) {
+	other := errors.New("other")
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"no rows", pgx.ErrNoRows, storage.ErrNotFound},
+		{"unique violation", &pgconn.PgError{Code: uniqueViolation}, storage.ErrConflict},
+		{"check violation", &pgconn.PgError{Code: checkViolation}, storage.ErrInvalidArgument},
+		{"other", other, other},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrapErr(tt.err)
+			if !errors.Is(got, tt.want) {
+				t.Errorf("wrapErr() = %v, want %v", got, tt.want)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Errorf("wrapErr() = %v, want original error %v preserved", got, tt.err)
+			}
+		})
+	}
+
+	if err := wrapErr(nil); err != nil {
+		t.Errorf("wrapErr(nil) = %v, want nil", err)
+	}
+}
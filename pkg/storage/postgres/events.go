@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AppendEvents добавляет события в конец потока streamID.
+// expectedSeq - номер последнего события потока, известный вызывающему
+// коду (0 для нового потока). Если поток успел измениться, события
+// не добавляются и возвращается storage.ErrVersionConflict.
+// Поля ID, StreamID и SequenceNo событий заполняются хранилищем.
+func (s *Storage) AppendEvents(ctx context.Context, streamID string, expectedSeq int64, events []storage.Event) error {
+	ctx, cancel := s.withTimeout(ctx, "AppendEvents")
+	defer cancel()
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var seq int64
+	err = tx.QueryRow(ctx, `
+		SELECT COALESCE(MAX(sequence_no), 0)
+		FROM events
+		WHERE stream_id = $1;
+	`,
+		streamID,
+	).Scan(&seq)
+	if err != nil {
+		return err
+	}
+	if seq != expectedSeq {
+		return storage.ErrVersionConflict
+	}
+
+	batch := pgx.Batch{}
+	for i, e := range events {
+		batch.Queue(`
+			INSERT INTO events (stream_id, event_type, payload, occurred_at, sequence_no)
+			VALUES ($1, $2, $3, COALESCE(NULLIF($4, 0), extract(epoch from now())), $5);
+		`,
+			streamID,
+			e.EventType,
+			e.Payload,
+			e.OccurredAt,
+			expectedSeq+int64(i)+1,
+		)
+	}
+	err = tx.SendBatch(ctx, &batch).Close()
+	if err != nil {
+		// параллельная запись в поток с тем же expectedSeq
+		// нарушает уникальность (stream_id, sequence_no)
+		if err = wrapErr(err); errors.Is(err, storage.ErrConflict) {
+			return storage.ErrVersionConflict
+		}
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// LoadEvents возвращает события потока streamID, начиная с номера fromSeq,
+// в порядке номеров.
+func (s *Storage) LoadEvents(ctx context.Context, streamID string, fromSeq int64) ([]storage.Event, error) {
+	ctx, cancel := s.withTimeout(ctx, "LoadEvents")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT id, stream_id, event_type, payload, occurred_at, sequence_no
+		FROM events
+		WHERE stream_id = $1 AND sequence_no >= $2
+		ORDER BY sequence_no;
+	`,
+		streamID,
+		fromSeq,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []storage.Event
+	for rows.Next() {
+		var e storage.Event
+		err = rows.Scan(&e.ID, &e.StreamID, &e.EventType, &e.Payload, &e.OccurredAt, &e.SequenceNo)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
@@ -0,0 +1,214 @@
+package postgres
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"strconv"
+)
+
+// ExportJSON записывает все задачи в w в виде JSON-массива.
+// Задачи записываются по одной по мере чтения из БД.
+func (s *Storage) ExportJSON(ctx context.Context, w io.Writer) error {
+	ctx, cancel := s.withTimeout(ctx, "ExportJSON")
+	defer cancel()
+
+	_, err := io.WriteString(w, "[\n")
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	first := true
+	err = s.TasksIter(ctx, func(t storage.Task) error {
+		if !first {
+			_, err := io.WriteString(w, ",")
+			if err != nil {
+				return err
+			}
+		}
+		first = false
+		return enc.Encode(t)
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "]\n")
+	return err
+}
+
+// ImportJSON читает из r JSON-массив задач, записанный ExportJSON,
+// и создаёт задачи в одной транзакции. При ошибке разбора или
+// создания задачи изменения откатываются целиком.
+// Задачи создаются методом AddTask, поэтому ID назначаются заново,
+// а сохраняются только те поля, которые записывает AddTask.
+func (s *Storage) ImportJSON(ctx context.Context, r io.Reader) error {
+	ctx, cancel := s.withTimeout(ctx, "ImportJSON")
+	defer cancel()
+
+	tx, err := s.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("%w: %v", storage.ErrInvalidArgument, err)
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("%w: expected JSON array", storage.ErrInvalidArgument)
+	}
+
+	for dec.More() {
+		var t storage.Task
+		err = dec.Decode(&t)
+		if err != nil {
+			return fmt.Errorf("%w: %v", storage.ErrInvalidArgument, err)
+		}
+		_, err = tx.AddTask(ctx, t)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = dec.Token()
+	if err != nil {
+		return fmt.Errorf("%w: %v", storage.ErrInvalidArgument, err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// csvHeader - заголовок CSV, который записывает ExportCSV.
+var csvHeader = []string{"id", "opened", "closed", "author_id", "assigned_id", "title", "content"}
+
+// ExportCSV записывает все задачи в w в формате CSV (RFC 4180)
+// со строкой заголовка csvHeader.
+func (s *Storage) ExportCSV(ctx context.Context, w io.Writer) error {
+	ctx, cancel := s.withTimeout(ctx, "ExportCSV")
+	defer cancel()
+
+	cw := csv.NewWriter(w)
+	write := func(record []string) error {
+		err := cw.Write(record)
+		if err != nil {
+			return err
+		}
+		// сбрасываем буфер после каждой строки, чтобы не накапливать данные
+		cw.Flush()
+		return cw.Error()
+	}
+
+	err := write(csvHeader)
+	if err != nil {
+		return err
+	}
+	return s.TasksIter(ctx, func(t storage.Task) error {
+		return write([]string{
+			strconv.Itoa(t.ID),
+			strconv.FormatInt(t.Opened, 10),
+			strconv.FormatInt(t.Closed, 10),
+			strconv.Itoa(t.AuthorID),
+			strconv.Itoa(t.AssignedID),
+			t.Title,
+			t.Content,
+		})
+	})
+}
+
+// Размер партии, которой ImportCSV передаёт задачи в AddTasksBatch.
+const importCSVChunk = 500
+
+// ImportCSV читает задачи в формате ExportCSV и создаёт их партиями
+// через AddTasksBatch. Возвращает количество созданных задач.
+//
+// Строка заголовка, если она есть, пропускается. Каждая строка должна
+// содержать 7 полей, а числовые поля - корректные числа. Столбец id
+// не используется: ID назначаются заново. AddTasksBatch сохраняет
+// только заголовок и текст задачи.
+//
+// Партии не объединены в транзакцию: при ошибке задачи из предыдущих
+// партий остаются созданными.
+func (s *Storage) ImportCSV(ctx context.Context, r io.Reader) (int, error) {
+	ctx, cancel := s.withTimeout(ctx, "ImportCSV")
+	defer cancel()
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(csvHeader)
+
+	var (
+		created int
+		chunk   []storage.Task
+	)
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		ids, err := s.AddTasksBatch(ctx, chunk)
+		created += len(ids)
+		chunk = chunk[:0]
+		return err
+	}
+
+	for line := 1; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return created, fmt.Errorf("%w: %v", storage.ErrInvalidArgument, err)
+		}
+		if line == 1 && slices.Equal(record, csvHeader) {
+			continue
+		}
+
+		t, err := parseCSVTask(record)
+		if err != nil {
+			return created, fmt.Errorf("%w: line %d: %v", storage.ErrInvalidArgument, line, err)
+		}
+		chunk = append(chunk, t)
+
+		if len(chunk) == importCSVChunk {
+			err = flush()
+			if err != nil {
+				return created, err
+			}
+		}
+	}
+
+	return created, flush()
+}
+
+// parseCSVTask разбирает строку CSV в порядке столбцов csvHeader.
+func parseCSVTask(record []string) (storage.Task, error) {
+	var (
+		t   storage.Task
+		err error
+	)
+	t.Opened, err = strconv.ParseInt(record[1], 10, 64)
+	if err != nil {
+		return t, fmt.Errorf("opened: %w", err)
+	}
+	t.Closed, err = strconv.ParseInt(record[2], 10, 64)
+	if err != nil {
+		return t, fmt.Errorf("closed: %w", err)
+	}
+	t.AuthorID, err = strconv.Atoi(record[3])
+	if err != nil {
+		return t, fmt.Errorf("author_id: %w", err)
+	}
+	t.AssignedID, err = strconv.Atoi(record[4])
+	if err != nil {
+		return t, fmt.Errorf("assigned_id: %w", err)
+	}
+	t.Title = record[5]
+	t.Content = record[6]
+	return t, nil
+}
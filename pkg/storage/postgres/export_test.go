@@ -0,0 +1,186 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// taskCount возвращает количество задач в хранилище.
+func taskCount(t *testing.T, s *Storage) int {
+	t.Helper()
+	n, err := s.TaskCount(context.Background())
+	if err != nil {
+		t.Fatalf("TaskCount() error = %v", err)
+	}
+	return n
+}
+
+func TestExportImportJSON(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+
+	f, err := os.Open("testdata/tasks.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	err = s.ImportJSON(ctx, f)
+	if err != nil {
+		t.Fatalf("ImportJSON() error = %v", err)
+	}
+	if n := taskCount(t, s); n != 3 {
+		t.Fatalf("TaskCount() after ImportJSON = %d, want 3", n)
+	}
+
+	var buf bytes.Buffer
+	err = s.ExportJSON(ctx, &buf)
+	if err != nil {
+		t.Fatalf("ExportJSON() error = %v", err)
+	}
+	var exported []storage.Task
+	err = json.Unmarshal(buf.Bytes(), &exported)
+	if err != nil {
+		t.Fatalf("ExportJSON() wrote invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(exported) != 3 || exported[1].Content != `describe the "tasks" schema` {
+		t.Fatalf("ExportJSON() = %+v, want fixture tasks", exported)
+	}
+
+	// повторный импорт выгрузки создаёт те же задачи с новыми ID
+	err = s.ImportJSON(ctx, &buf)
+	if err != nil {
+		t.Fatalf("ImportJSON() of export error = %v", err)
+	}
+	if n := taskCount(t, s); n != 6 {
+		t.Errorf("TaskCount() after second ImportJSON = %d, want 6", n)
+	}
+}
+
+// При ошибке разбора задачи, созданные до неё, не сохраняются.
+func TestImportJSONRollback(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+
+	inputs := []string{
+		`[{"Title": "ok"}, {"Title": 1}]`,
+		`[{"Title": "ok"}`,
+		`{"Title": "not an array"}`,
+	}
+	for _, in := range inputs {
+		err := s.ImportJSON(ctx, strings.NewReader(in))
+		if !errors.Is(err, storage.ErrInvalidArgument) {
+			t.Errorf("ImportJSON(%s) error = %v, want ErrInvalidArgument", in, err)
+		}
+	}
+	if n := taskCount(t, s); n != 0 {
+		t.Errorf("TaskCount() after failed imports = %d, want 0", n)
+	}
+}
+
+// Пустое хранилище выгружается пустым массивом.
+func TestExportJSONEmpty(t *testing.T) {
+	s := newTestStorage(t)
+
+	var buf bytes.Buffer
+	err := s.ExportJSON(context.Background(), &buf)
+	if err != nil {
+		t.Fatalf("ExportJSON() error = %v", err)
+	}
+	var exported []storage.Task
+	err = json.Unmarshal(buf.Bytes(), &exported)
+	if err != nil || len(exported) != 0 {
+		t.Errorf("ExportJSON() = %q, want empty array", buf.String())
+	}
+}
+
+func TestExportImportCSV(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+
+	// 1001 строка - две полные партии и ещё одна задача
+	var in strings.Builder
+	in.WriteString("id,opened,closed,author_id,assigned_id,title,content\n")
+	for i := 1; i <= 1001; i++ {
+		fmt.Fprintf(&in, "%d,%d,0,0,0,\"task, %d\",\"line one\nline \"\"two\"\"\"\n", i, 1700000000+i, i)
+	}
+	n, err := s.ImportCSV(ctx, strings.NewReader(in.String()))
+	if err != nil {
+		t.Fatalf("ImportCSV() error = %v", err)
+	}
+	if n != 1001 {
+		t.Errorf("ImportCSV() = %d, want 1001", n)
+	}
+	if got := taskCount(t, s); got != 1001 {
+		t.Fatalf("TaskCount() after ImportCSV = %d, want 1001", got)
+	}
+
+	var buf bytes.Buffer
+	err = s.ExportCSV(ctx, &buf)
+	if err != nil {
+		t.Fatalf("ExportCSV() error = %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("ExportCSV() wrote invalid CSV: %v", err)
+	}
+	if len(records) != 1002 || !slices.Equal(records[0], csvHeader) {
+		t.Fatalf("ExportCSV() wrote %d records, header %v, want 1002 records with header", len(records), records[0])
+	}
+	if title, content := records[1][5], records[1][6]; title != "task, 1" || content != "line one\nline \"two\"" {
+		t.Errorf("ExportCSV() first task = %q, %q, want quoted fields preserved", title, content)
+	}
+}
+
+// Некорректные строки отклоняются до обращения к БД.
+func TestImportCSVInvalid(t *testing.T) {
+	s, err := New(testConnString)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer s.Close()
+
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"too few fields", "1,0,0,0,0,title\n"},
+		{"too many fields", "1,0,0,0,0,title,content,extra\n"},
+		{"invalid opened", "1,yesterday,0,0,0,title,content\n"},
+		{"invalid closed", "1,0,never,0,0,title,content\n"},
+		{"invalid author", "1,0,0,alice,0,title,content\n"},
+		{"invalid assignee", "1,0,0,0,bob,title,content\n"},
+		{"unterminated quote", "1,0,0,0,0,\"title,content\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := s.ImportCSV(context.Background(), strings.NewReader(tt.in))
+			if !errors.Is(err, storage.ErrInvalidArgument) {
+				t.Errorf("ImportCSV() error = %v, want ErrInvalidArgument", err)
+			}
+			if n != 0 {
+				t.Errorf("ImportCSV() = %d, want 0", n)
+			}
+		})
+	}
+}
+
+func TestParseCSVTask(t *testing.T) {
+	got, err := parseCSVTask([]string{"7", "100", "200", "1", "2", "title", "content"})
+	if err != nil {
+		t.Fatalf("parseCSVTask() error = %v", err)
+	}
+	want := storage.Task{Opened: 100, Closed: 200, AuthorID: 1, AssignedID: 2, Title: "title", Content: "content"}
+	if got.ID != 0 || got.Opened != want.Opened || got.Closed != want.Closed || got.AuthorID != want.AuthorID ||
+		got.AssignedID != want.AssignedID || got.Title != want.Title || got.Content != want.Content {
+		t.Errorf("parseCSVTask() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Имя, которое получает пользователь после EraseUser.
+const erasedUserName = "Deleted User"
+
+// ExportUserData возвращает персональные данные пользователя:
+// его задачи (как автора и как исполнителя), комментарии и учёт времени.
+func (s *Storage) ExportUserData(ctx context.Context, userID int) (*storage.UserDataExport, error) {
+	ctx, cancel := s.withTimeout(ctx, "ExportUserData")
+	defer cancel()
+
+	u, err := s.UserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	export := storage.UserDataExport{User: *u}
+
+	export.AuthoredTasks, err = s.TasksByAuthor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	export.AssignedTasks, err = s.TasksByAssignee(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	export.TimeEntries, err = s.TimeEntriesByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT id, task_id, author_id, body, created_at
+		FROM comments
+		WHERE author_id = $1
+		ORDER BY id;
+	`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c storage.Comment
+		err = rows.Scan(&c.ID, &c.TaskID, &c.AuthorID, &c.Body, &c.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		export.Comments = append(export.Comments, c)
+	}
+
+	return &export, rows.Err()
+}
+
+// EraseUser обезличивает пользователя в одной транзакции:
+// заменяет имя на "Deleted User", передаёт его задачи
+// пользователю по умолчанию (ID 0), удаляет его комментарии,
+// учёт времени, подписки на задачи, настройки уведомлений и ключи API.
+// Схема не хранит email пользователя, поэтому очищать его не нужно.
+func (s *Storage) EraseUser(ctx context.Context, userID int) error {
+	ctx, cancel := s.withTimeout(ctx, "EraseUser")
+	defer cancel()
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE users
+		SET name = $2
+		WHERE id = $1;
+	`,
+		userID,
+		erasedUserName,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrNotFound
+	}
+
+	batch := pgx.Batch{}
+	for _, sql := range []string{
+		"UPDATE tasks SET author_id = 0 WHERE author_id = $1;",
+		"UPDATE tasks SET assigned_id = 0 WHERE assigned_id = $1;",
+		"DELETE FROM comments WHERE author_id = $1;",
+		"DELETE FROM time_entries WHERE user_id = $1;",
+		"DELETE FROM task_watchers WHERE user_id = $1;",
+		"DELETE FROM notification_preferences WHERE user_id = $1;",
+		"DELETE FROM api_keys WHERE user_id = $1;",
+	} {
+		batch.Queue(sql, userID)
+	}
+	err = tx.SendBatch(ctx, &batch).Close()
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
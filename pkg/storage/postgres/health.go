@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Stats возвращает статистику пула соединений основного сервера.
+func (s *Storage) Stats() *pgxpool.Stat {
+	return s.primary.Stat()
+}
+
+// healthResponse - тело ответа ServeHTTP.
+type healthResponse struct {
+	Status     string `json:"status"`
+	TotalConns *int32 `json:"total_conns,omitempty"`
+	IdleConns  *int32 `json:"idle_conns,omitempty"`
+	Detail     string `json:"detail,omitempty"`
+}
+
+// ServeHTTP отвечает на запрос проверки состояния хранилища.
+// Если БД доступна, возвращает 200 и статистику пула,
+// иначе - 503 и описание ошибки.
+func (s *Storage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	resp := healthResponse{Status: "ok"}
+	code := http.StatusOK
+
+	err := s.HealthCheck(r.Context())
+	if err != nil {
+		resp = healthResponse{Status: "error", Detail: err.Error()}
+		code = http.StatusServiceUnavailable
+	} else {
+		stat := s.Stats()
+		total, idle := stat.TotalConns(), stat.IdleConns()
+		resp.TotalConns = &total
+		resp.IdleConns = &idle
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(resp)
+}
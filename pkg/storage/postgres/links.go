@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+)
+
+// AddTaskLink связывает задачу l.TaskID с задачей l.LinkedTaskID
+// и возвращает ID связи. Обратная связь не создаётся.
+func (s *Storage) AddTaskLink(ctx context.Context, l storage.TaskLinkRecord) (int, error) {
+	ctx, cancel := s.withTimeout(ctx, "AddTaskLink")
+	defer cancel()
+
+	var id int
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO task_links (task_id, linked_task_id, type)
+		VALUES ($1, $2, $3) RETURNING id;
+	`,
+		l.TaskID,
+		l.LinkedTaskID,
+		l.Type,
+	).Scan(&id)
+	return id, wrapErr(err)
+}
+
+// TaskLinks возвращает связи задачи с другими задачами.
+func (s *Storage) TaskLinks(ctx context.Context, taskID int) ([]storage.TaskLinkRecord, error) {
+	ctx, cancel := s.withTimeout(ctx, "TaskLinks")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT id, task_id, linked_task_id, type
+		FROM task_links
+		WHERE task_id = $1
+		ORDER BY id;
+	`,
+		taskID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []storage.TaskLinkRecord
+	for rows.Next() {
+		var l storage.TaskLinkRecord
+		err = rows.Scan(&l.ID, &l.TaskID, &l.LinkedTaskID, &l.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		links = append(links, l)
+	}
+
+	return links, rows.Err()
+}
+
+// DeleteTaskLink удаляет связь по ID.
+func (s *Storage) DeleteTaskLink(ctx context.Context, linkID int) error {
+	ctx, cancel := s.withTimeout(ctx, "DeleteTaskLink")
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx, `
+		DELETE FROM task_links
+		WHERE id = $1;
+	`,
+		linkID,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Файлы миграций схемы БД.
+// Применяются в лексикографическом порядке имён файлов.
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Migrate применяет к БД миграции, которые ещё не были применены.
+// Применённые миграции учитываются в таблице schema_migrations,
+// поэтому повторный вызов ничего не делает.
+// Каждая миграция выполняется в отдельной транзакции.
+// БД, созданная ранее по schema.sql, может быть переведена на миграции:
+// начальная миграция 0001_init дополняет существующие таблицы.
+func Migrate(ctx context.Context, constr string) error {
+	conn, err := pgx.Connect(ctx, constr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+
+	_, err = conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version TEXT PRIMARY KEY,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+	`)
+	if err != nil {
+		return err
+	}
+
+	// ReadDir возвращает файлы, отсортированные по имени.
+	entries, err := fs.ReadDir(migrations, "migrations")
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		version := strings.TrimSuffix(e.Name(), ".sql")
+		err = applyMigration(ctx, conn, version, path.Join("migrations", e.Name()))
+		if err != nil {
+			return fmt.Errorf("migration %s: %w", version, err)
+		}
+	}
+	return nil
+}
+
+// applyMigration выполняет файл миграции, если версия ещё не применена.
+func applyMigration(ctx context.Context, conn *pgx.Conn, version, file string) error {
+	sql, err := migrations.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	// после Commit откат ничего не делает
+	defer tx.Rollback(ctx)
+
+	var applied bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM schema_migrations
+			WHERE version = $1
+		);
+	`,
+		version,
+	).Scan(&applied)
+	if err != nil {
+		return err
+	}
+	if applied {
+		return nil
+	}
+
+	// Запрос без параметров отправляется по простому протоколу,
+	// что позволяет выполнить несколько инструкций из файла за раз.
+	_, err = tx.Exec(ctx, string(sql))
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO schema_migrations (version, applied_at)
+		VALUES ($1, NOW());
+	`,
+		version,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
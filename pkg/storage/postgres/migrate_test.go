@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Миграции нумеруются подряд, начиная с 0001.
+func TestMigrationFiles(t *testing.T) {
+	entries, err := fs.ReadDir(migrations, "migrations")
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(entries) == 0 {
+		t.Fatal("no migrations embedded")
+	}
+	name := regexp.MustCompile(`^(\d{4})_[a-z0-9_]+\.sql$`)
+	for i, e := range entries {
+		m := name.FindStringSubmatch(e.Name())
+		if m == nil {
+			t.Errorf("migration %q does not match NNNN_name.sql", e.Name())
+			continue
+		}
+		if want := fmt.Sprintf("%04d", i+1); m[1] != want {
+			t.Errorf("migration %q has number %s, want %s", e.Name(), m[1], want)
+		}
+	}
+}
+
+// withSchema возвращает строку подключения, в которой новые таблицы
+// создаются в схеме schema.
+func withSchema(constr, schema string) string {
+	param := "search_path=" + schema + ",public"
+	switch {
+	case !strings.Contains(constr, "://"):
+		return constr + " " + param
+	case strings.Contains(constr, "?"):
+		return constr + "&" + param
+	default:
+		return constr + "?" + param
+	}
+}
+
+// Миграции применяются к БД, созданной ранее по schema.sql,
+// и повторный запуск ничего не изменяет.
+func TestMigrateLegacySchema(t *testing.T) {
+	constr := os.Getenv("TEST_DATABASE_URL")
+	if constr == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, constr)
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer conn.Close(ctx)
+
+	const schema = "migrate_legacy_test"
+	_, err = conn.Exec(ctx, `
+		DROP SCHEMA IF EXISTS `+schema+` CASCADE;
+		CREATE SCHEMA `+schema+`;
+		SET search_path = `+schema+`;
+
+		CREATE TABLE users (
+			id SERIAL PRIMARY KEY,
+			name TEXT NOT NULL
+		);
+		CREATE TABLE labels (
+			id SERIAL PRIMARY KEY,
+			name TEXT NOT NULL
+		);
+		CREATE TABLE tasks (
+			id SERIAL PRIMARY KEY,
+			opened BIGINT NOT NULL DEFAULT extract(epoch from now()),
+			closed BIGINT DEFAULT 0,
+			author_id INTEGER REFERENCES users(id) DEFAULT 0,
+			assigned_id INTEGER REFERENCES users(id) DEFAULT 0,
+			title TEXT,
+			content TEXT
+		);
+		CREATE TABLE tasks_labels (
+			task_id INTEGER REFERENCES tasks(id),
+			label_id INTEGER REFERENCES labels(id)
+		);
+		INSERT INTO users (id, name) VALUES (0, 'default');
+		INSERT INTO tasks (title) VALUES ('legacy');
+		INSERT INTO labels (name) VALUES ('bug');
+		INSERT INTO tasks_labels VALUES (1, 1), (1, 1);
+
+		RESET search_path;
+	`)
+	if err != nil {
+		t.Fatalf("create legacy schema: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = conn.Exec(context.Background(), `DROP SCHEMA IF EXISTS `+schema+` CASCADE;`)
+	})
+
+	for i := range 2 {
+		err = Migrate(ctx, withSchema(constr, schema))
+		if err != nil {
+			t.Fatalf("Migrate() run %d error = %v", i+1, err)
+		}
+	}
+
+	s, err := New(withSchema(constr, schema))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer s.Close()
+
+	task, err := s.TaskById(ctx, 1)
+	if err != nil {
+		t.Fatalf("TaskById() of legacy task error = %v", err)
+	}
+	if task.Title != "legacy" {
+		t.Errorf("legacy task title = %q, want legacy", task.Title)
+	}
+	// повторяющаяся связь удалена при создании первичного ключа
+	n, err := s.TaskCountByLabel(ctx, 1)
+	if err != nil {
+		t.Fatalf("TaskCountByLabel() error = %v", err)
+	}
+	if n != 1 {
+		t.Errorf("TaskCountByLabel() = %d, want 1", n)
+	}
+	// связи задачи с метками удаляются вместе с задачей
+	_, err = s.pool.Exec(ctx, `DELETE FROM tasks WHERE id = 1;`)
+	if err != nil {
+		t.Errorf("delete legacy task with labels: %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+/*
+    Схема БД для информационной системы
+    отслеживания выполнения задач.
+    Начальная миграция.
+
+    Миграция идемпотентна: БД, созданная ранее по schema.sql,
+    принимается как есть и дополняется до этой схемы.
+*/
+
+CREATE TABLE IF NOT EXISTS users (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS labels (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS tasks (
+    id SERIAL PRIMARY KEY,
+    opened BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    closed BIGINT DEFAULT 0,
+    author_id INTEGER REFERENCES users(id) DEFAULT 0,
+    assigned_id INTEGER REFERENCES users(id) DEFAULT 0,
+    title TEXT,
+    content TEXT,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS tasks_labels (
+    task_id INTEGER REFERENCES tasks(id),
+    label_id INTEGER REFERENCES labels(id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, label_id)
+);
+
+/*
+    Ранние редакции schema.sql не содержали столбца deleted_at,
+    уникальности названий меток, первичного ключа tasks_labels
+    и каскадного удаления связей вместе с меткой.
+*/
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint
+        WHERE conrelid = 'labels'::regclass AND contype = 'u'
+    ) THEN
+        ALTER TABLE labels ADD UNIQUE (name);
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint
+        WHERE conrelid = 'tasks_labels'::regclass AND contype = 'p'
+    ) THEN
+        DELETE FROM tasks_labels WHERE task_id IS NULL OR label_id IS NULL;
+        DELETE FROM tasks_labels a USING tasks_labels b
+        WHERE a.ctid < b.ctid AND a.task_id = b.task_id AND a.label_id = b.label_id;
+        ALTER TABLE tasks_labels ADD PRIMARY KEY (task_id, label_id);
+    END IF;
+END $$;
+
+ALTER TABLE tasks_labels
+    DROP CONSTRAINT IF EXISTS tasks_labels_label_id_fkey,
+    ADD CONSTRAINT tasks_labels_label_id_fkey
+        FOREIGN KEY (label_id) REFERENCES labels(id) ON DELETE CASCADE;
+
+INSERT INTO users (id, name) VALUES (0, 'default')
+ON CONFLICT (id) DO NOTHING;
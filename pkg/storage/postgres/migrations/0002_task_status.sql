@@ -0,0 +1,5 @@
+/*
+    Статус задачи.
+*/
+
+ALTER TABLE tasks ADD COLUMN status INTEGER NOT NULL DEFAULT 0;
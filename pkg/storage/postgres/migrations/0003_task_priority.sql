@@ -0,0 +1,5 @@
+/*
+    Приоритет задачи.
+*/
+
+ALTER TABLE tasks ADD COLUMN priority INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,5 @@
+/*
+    Срок выполнения задачи.
+*/
+
+ALTER TABLE tasks ADD COLUMN due_at BIGINT;
@@ -0,0 +1,13 @@
+/*
+    Комментарии к задачам.
+*/
+
+CREATE TABLE comments (
+    id SERIAL PRIMARY KEY,
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    author_id INTEGER NOT NULL REFERENCES users(id),
+    body TEXT NOT NULL,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now())
+);
+
+CREATE INDEX ON comments (task_id);
@@ -0,0 +1,15 @@
+/*
+    Учёт времени, затраченного на задачи.
+*/
+
+CREATE TABLE time_entries (
+    id SERIAL PRIMARY KEY,
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    minutes INTEGER NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    logged_at BIGINT NOT NULL DEFAULT extract(epoch from now())
+);
+
+CREATE INDEX ON time_entries (task_id);
+CREATE INDEX ON time_entries (user_id);
@@ -0,0 +1,13 @@
+/*
+    Пункты чек-листа (подзадачи) задачи.
+*/
+
+CREATE TABLE checklist_items (
+    id SERIAL PRIMARY KEY,
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    done BOOLEAN NOT NULL DEFAULT FALSE,
+    position INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX ON checklist_items (task_id, position);
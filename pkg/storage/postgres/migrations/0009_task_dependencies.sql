@@ -0,0 +1,13 @@
+/*
+    Зависимости между задачами:
+    задача task_id не может быть выполнена раньше задачи depends_on_id.
+*/
+
+CREATE TABLE task_dependencies (
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    depends_on_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    UNIQUE (task_id, depends_on_id),
+    CHECK (task_id != depends_on_id)
+);
+
+CREATE INDEX ON task_dependencies (depends_on_id);
@@ -0,0 +1,15 @@
+/*
+    Журнал изменений полей задач.
+*/
+
+CREATE TABLE audit_log (
+    id SERIAL PRIMARY KEY,
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) DEFAULT 0,
+    field TEXT NOT NULL,
+    old_value TEXT NOT NULL,
+    new_value TEXT NOT NULL,
+    changed_at BIGINT NOT NULL DEFAULT extract(epoch from now())
+);
+
+CREATE INDEX ON audit_log (task_id);
@@ -0,0 +1,8 @@
+/*
+    Полнотекстовый поиск по заголовку и тексту задачи.
+*/
+
+ALTER TABLE tasks ADD COLUMN search_vector TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('english', coalesce(title, '') || ' ' || coalesce(content, ''))) STORED;
+
+CREATE INDEX ON tasks USING GIN (search_vector);
@@ -0,0 +1,18 @@
+/*
+    Уведомления об изменении задач через канал task_events.
+    Полезная нагрузка: {"op": "INSERT|UPDATE|DELETE", "id": <ID задачи>}.
+*/
+
+CREATE FUNCTION notify_task_event() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('task_events', json_build_object(
+        'op', TG_OP,
+        'id', CASE WHEN TG_OP = 'DELETE' THEN OLD.id ELSE NEW.id END
+    )::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tasks_notify
+    AFTER INSERT OR UPDATE OR DELETE ON tasks
+    FOR EACH ROW EXECUTE FUNCTION notify_task_event();
@@ -0,0 +1,7 @@
+/*
+    Произвольные атрибуты задачи в виде пар ключ-значение.
+*/
+
+ALTER TABLE tasks ADD COLUMN metadata JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX ON tasks USING GIN (metadata jsonb_path_ops);
@@ -0,0 +1,36 @@
+/*
+    История назначения задач исполнителям.
+    Записи добавляет триггер при изменении tasks.assigned_id.
+    Автор изменения берётся из параметра сеанса app.user_id,
+    если он не задан - используется пользователь по умолчанию.
+*/
+
+CREATE TABLE assignment_events (
+    id SERIAL PRIMARY KEY,
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    from_user_id INTEGER REFERENCES users(id),
+    to_user_id INTEGER REFERENCES users(id),
+    changed_by INTEGER NOT NULL REFERENCES users(id) DEFAULT 0,
+    changed_at BIGINT NOT NULL DEFAULT extract(epoch from now())
+);
+
+CREATE INDEX ON assignment_events (task_id);
+
+CREATE FUNCTION record_assignment_event() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO assignment_events (task_id, from_user_id, to_user_id, changed_by)
+    VALUES (
+        NEW.id,
+        OLD.assigned_id,
+        NEW.assigned_id,
+        COALESCE(NULLIF(current_setting('app.user_id', true), '')::INTEGER, 0)
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tasks_assignment_events
+    AFTER UPDATE OF assigned_id ON tasks
+    FOR EACH ROW
+    WHEN (OLD.assigned_id IS DISTINCT FROM NEW.assigned_id)
+    EXECUTE FUNCTION record_assignment_event();
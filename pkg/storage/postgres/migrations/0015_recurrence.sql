@@ -0,0 +1,13 @@
+/*
+    Правила повторения задач. Задача, к которой относится правило,
+    служит шаблоном для создаваемых копий.
+*/
+
+CREATE TABLE recurrence_rules (
+    task_id INTEGER PRIMARY KEY REFERENCES tasks(id) ON DELETE CASCADE,
+    interval_days INTEGER NOT NULL CHECK (interval_days > 0),
+    next_due BIGINT NOT NULL,
+    last_created BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX ON recurrence_rules (next_due);
@@ -0,0 +1,10 @@
+/*
+    Архив задач. Повторяет структуру таблицы tasks,
+    но не участвует в запросах к актуальным задачам.
+*/
+
+CREATE TABLE tasks_archive (
+    LIKE tasks INCLUDING DEFAULTS,
+    archived_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id)
+);
@@ -0,0 +1,6 @@
+/*
+    Индексы для выборки задач по времени открытия и закрытия.
+*/
+
+CREATE INDEX ON tasks (opened);
+CREATE INDEX ON tasks (closed);
@@ -0,0 +1,23 @@
+/*
+    Спринты (итерации) проектов и состав задач спринтов.
+    Задача может входить в несколько спринтов.
+*/
+
+CREATE TABLE sprints (
+    id SERIAL PRIMARY KEY,
+    project_id INTEGER NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    start_at BIGINT NOT NULL,
+    end_at BIGINT NOT NULL,
+    CHECK (end_at >= start_at)
+);
+
+CREATE INDEX ON sprints (project_id);
+
+CREATE TABLE task_sprints (
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    sprint_id INTEGER NOT NULL REFERENCES sprints(id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, sprint_id)
+);
+
+CREATE INDEX ON task_sprints (sprint_id);
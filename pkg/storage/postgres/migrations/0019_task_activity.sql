@@ -0,0 +1,17 @@
+/*
+    Лента событий по задачам и хранилищу в целом.
+    События без задачи записываются с task_id = 0,
+    поэтому внешний ключ на tasks не используется.
+*/
+
+CREATE TABLE task_activity (
+    id SERIAL PRIMARY KEY,
+    kind TEXT NOT NULL,
+    task_id INTEGER NOT NULL DEFAULT 0,
+    user_id INTEGER NOT NULL DEFAULT 0,
+    payload TEXT NOT NULL DEFAULT '',
+    occurred_at BIGINT NOT NULL DEFAULT extract(epoch from now())
+);
+
+CREATE INDEX ON task_activity (task_id, occurred_at DESC);
+CREATE INDEX ON task_activity (occurred_at DESC);
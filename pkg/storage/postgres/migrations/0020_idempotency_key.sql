@@ -0,0 +1,8 @@
+/*
+    Ключ идемпотентности задачи: повторное создание задачи
+    с тем же ключом возвращает уже существующую задачу.
+*/
+
+ALTER TABLE tasks ADD COLUMN idempotency_key TEXT UNIQUE;
+
+ALTER TABLE tasks_archive ADD COLUMN idempotency_key TEXT;
@@ -0,0 +1,19 @@
+/*
+    Версия задачи для оптимистичной блокировки.
+    Увеличивается триггером при каждом изменении строки.
+*/
+
+ALTER TABLE tasks ADD COLUMN version INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE tasks_archive ADD COLUMN version INTEGER NOT NULL DEFAULT 0;
+
+CREATE FUNCTION increment_task_version() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.version := OLD.version + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tasks_version
+    BEFORE UPDATE ON tasks
+    FOR EACH ROW EXECUTE FUNCTION increment_task_version();
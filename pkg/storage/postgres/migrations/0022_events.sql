@@ -0,0 +1,14 @@
+/*
+    Хранилище событий (event sourcing).
+    Номера событий в потоке идут подряд, начиная с 1.
+*/
+
+CREATE TABLE events (
+    id BIGSERIAL PRIMARY KEY,
+    stream_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload BYTEA NOT NULL,
+    occurred_at BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    sequence_no BIGINT NOT NULL,
+    UNIQUE (stream_id, sequence_no)
+);
@@ -0,0 +1,14 @@
+/*
+    Исходящие сообщения (outbox). Записываются в одной транзакции
+    с изменением данных и отправляются брокеру отдельным процессом.
+*/
+
+CREATE TABLE outbox (
+    id BIGSERIAL PRIMARY KEY,
+    topic TEXT NOT NULL,
+    payload BYTEA NOT NULL,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    sent_at BIGINT
+);
+
+CREATE INDEX ON outbox (id) WHERE sent_at IS NULL;
@@ -0,0 +1,14 @@
+/*
+    Ссылка на задачу во внешней системе (GitHub Issues, Jira и т.п.).
+    Пара (система, ID) уникальна, задачи без ссылки не ограничиваются.
+*/
+
+ALTER TABLE tasks
+    ADD COLUMN external_system TEXT,
+    ADD COLUMN external_id TEXT;
+
+ALTER TABLE tasks_archive
+    ADD COLUMN external_system TEXT,
+    ADD COLUMN external_id TEXT;
+
+CREATE UNIQUE INDEX ON tasks (external_system, external_id);
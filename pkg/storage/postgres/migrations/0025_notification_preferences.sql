@@ -0,0 +1,12 @@
+/*
+    Настройки уведомлений пользователя: на какие события
+    по его задачам отправлять уведомления.
+*/
+
+CREATE TABLE notification_preferences (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    task_created BOOLEAN NOT NULL DEFAULT FALSE,
+    task_updated BOOLEAN NOT NULL DEFAULT FALSE,
+    task_assigned BOOLEAN NOT NULL DEFAULT FALSE,
+    comment_added BOOLEAN NOT NULL DEFAULT FALSE
+);
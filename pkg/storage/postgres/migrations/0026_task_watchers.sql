@@ -0,0 +1,12 @@
+/*
+    Наблюдатели задач: пользователи, подписанные на изменения задачи
+    помимо её автора и исполнителя.
+*/
+
+CREATE TABLE task_watchers (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, task_id)
+);
+
+CREATE INDEX ON task_watchers (task_id);
@@ -0,0 +1,18 @@
+/*
+    Ключи API для доступа сервисов от имени пользователя.
+    Хранится открытый префикс ключа и хеш bcrypt его секретной части.
+*/
+
+CREATE TABLE api_keys (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    key_prefix TEXT NOT NULL UNIQUE,
+    key_hash BYTEA NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    expires_at BIGINT,
+    last_used_at BIGINT,
+    revoked_at BIGINT
+);
+
+CREATE INDEX ON api_keys (user_id);
@@ -0,0 +1,8 @@
+/*
+    Роль пользователя: 1 - просмотр, 2 - редактирование, 3 - администратор.
+    Существующие пользователи получают роль просмотра.
+*/
+
+ALTER TABLE users ADD COLUMN role INTEGER NOT NULL DEFAULT 1;
+
+CREATE INDEX ON users (role);
@@ -0,0 +1,8 @@
+/*
+    Отметка о "зависшей" задаче: задача открыта дольше допустимого
+    и не закрыта. Устанавливается MarkTaskStale.
+*/
+
+ALTER TABLE tasks ADD COLUMN is_stale BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE tasks_archive ADD COLUMN is_stale BOOLEAN NOT NULL DEFAULT FALSE;
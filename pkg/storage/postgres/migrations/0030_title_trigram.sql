@@ -0,0 +1,7 @@
+/*
+    Поиск похожих задач по заголовку (SimilarTasks).
+*/
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX ON tasks USING GIN (title gin_trgm_ops);
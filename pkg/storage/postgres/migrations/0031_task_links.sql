@@ -0,0 +1,15 @@
+/*
+    Связи между задачами: задача task_id связана с задачей linked_task_id.
+    Связь направленная, обратная связь не создаётся.
+*/
+
+CREATE TABLE task_links (
+    id SERIAL PRIMARY KEY,
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    linked_task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    type TEXT NOT NULL CHECK (type IN ('related', 'duplicates', 'blocks')),
+    UNIQUE (task_id, linked_task_id, type),
+    CHECK (task_id != linked_task_id)
+);
+
+CREATE INDEX ON task_links (linked_task_id);
@@ -0,0 +1,12 @@
+/*
+    Веб-хуки: URL, на который отправляются события указанных типов.
+    Тело запроса подписывается HMAC-SHA256 с секретом веб-хука.
+*/
+
+CREATE TABLE webhooks (
+    id SERIAL PRIMARY KEY,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    events TEXT[] NOT NULL DEFAULT '{}',
+    active BOOLEAN NOT NULL DEFAULT TRUE
+);
@@ -0,0 +1,9 @@
+/*
+    Теги задачи - произвольные строки без отдельной таблицы, в отличие от меток.
+*/
+
+ALTER TABLE tasks ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+ALTER TABLE tasks_archive ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX ON tasks USING GIN (tags);
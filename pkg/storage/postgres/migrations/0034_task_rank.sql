@@ -0,0 +1,14 @@
+/*
+    Ранг задачи для ручной сортировки (например, на канбан-доске).
+    Новая задача получает текущее время в миллисекундах и оказывается
+    в конце списка. Существующим задачам ранг назначается по времени открытия.
+*/
+
+ALTER TABLE tasks ADD COLUMN rank DOUBLE PRECISION NOT NULL
+    DEFAULT (extract(epoch from clock_timestamp()) * 1000);
+
+UPDATE tasks SET rank = opened * 1000;
+
+ALTER TABLE tasks_archive ADD COLUMN rank DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+CREATE INDEX ON tasks (rank, id);
@@ -0,0 +1,9 @@
+/*
+    Связи задачи с метками удаляются вместе с задачей,
+    так же как они удаляются вместе с меткой.
+*/
+
+ALTER TABLE tasks_labels
+    DROP CONSTRAINT IF EXISTS tasks_labels_task_id_fkey,
+    ADD CONSTRAINT tasks_labels_task_id_fkey
+        FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE;
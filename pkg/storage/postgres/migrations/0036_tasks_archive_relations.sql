@@ -0,0 +1,13 @@
+/*
+    Записи, ссылавшиеся на задачу до её переноса в архив: метки, комментарии,
+    учёт времени, чек-лист и т.п. Хранятся в исходном виде и возвращаются
+    в свои таблицы при восстановлении задачи из архива.
+*/
+
+CREATE TABLE tasks_archive_relations (
+    task_id INTEGER NOT NULL REFERENCES tasks_archive(id) ON DELETE CASCADE,
+    source TEXT NOT NULL,
+    data JSONB NOT NULL
+);
+
+CREATE INDEX ON tasks_archive_relations (task_id);
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+)
+
+// notifyColumns сопоставляет виду события столбец
+// таблицы notification_preferences.
+var notifyColumns = map[string]string{
+	storage.NotifyTaskCreated:  "task_created",
+	storage.NotifyTaskUpdated:  "task_updated",
+	storage.NotifyTaskAssigned: "task_assigned",
+	storage.NotifyCommentAdded: "comment_added",
+}
+
+// SetNotificationPreference создаёт или заменяет настройки
+// уведомлений пользователя.
+func (s *Storage) SetNotificationPreference(ctx context.Context, p storage.NotificationPreference) error {
+	ctx, cancel := s.withTimeout(ctx, "SetNotificationPreference")
+	defer cancel()
+
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO notification_preferences (user_id, task_created, task_updated, task_assigned, comment_added)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE SET
+			task_created = EXCLUDED.task_created,
+			task_updated = EXCLUDED.task_updated,
+			task_assigned = EXCLUDED.task_assigned,
+			comment_added = EXCLUDED.comment_added;
+	`,
+		p.UserID,
+		p.TaskCreated,
+		p.TaskUpdated,
+		p.TaskAssigned,
+		p.CommentAdded,
+	)
+	return err
+}
+
+// GetNotificationPreference возвращает настройки уведомлений пользователя.
+func (s *Storage) GetNotificationPreference(ctx context.Context, userID int) (*storage.NotificationPreference, error) {
+	ctx, cancel := s.withTimeout(ctx, "GetNotificationPreference")
+	defer cancel()
+
+	var p storage.NotificationPreference
+	err := s.readPool.QueryRow(ctx, `
+		SELECT user_id, task_created, task_updated, task_assigned, comment_added
+		FROM notification_preferences
+		WHERE user_id = $1;
+	`,
+		userID,
+	).Scan(&p.UserID, &p.TaskCreated, &p.TaskUpdated, &p.TaskAssigned, &p.CommentAdded)
+	if err != nil {
+		return nil, wrapErr(err)
+	}
+	return &p, nil
+}
+
+// UsersSubscribedToTask возвращает пользователей, связанных с задачей
+// (автора, исполнителя и наблюдателей), у которых включены уведомления о событии event.
+func (s *Storage) UsersSubscribedToTask(ctx context.Context, taskID int, event string) ([]storage.User, error) {
+	ctx, cancel := s.withTimeout(ctx, "UsersSubscribedToTask")
+	defer cancel()
+
+	// имя столбца проверяется Enabled, поэтому его можно подставить в запрос
+	if _, err := (storage.NotificationPreference{}).Enabled(event); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT u.id, u.name, u.role
+		FROM users u
+		JOIN notification_preferences p ON p.user_id = u.id
+		JOIN tasks t ON t.id = $1
+		WHERE p.`+notifyColumns[event]+`
+			AND (
+				u.id = t.author_id
+				OR u.id = t.assigned_id
+				OR EXISTS (
+					SELECT 1 FROM task_watchers w
+					WHERE w.task_id = t.id AND w.user_id = u.id
+				)
+			)
+		ORDER BY u.id;
+	`,
+		taskID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []storage.User
+	for rows.Next() {
+		var u storage.User
+		err = rows.Scan(&u.ID, &u.Name, &u.UserRole)
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, u)
+	}
+
+	return users, rows.Err()
+}
@@ -0,0 +1,265 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"skillfactory/30.8.1/pkg/storage"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// options содержит параметры, собираемые конструктором из Option.
+type options struct {
+	config        *pgxpool.Config
+	readReplica   string
+	slowThreshold time.Duration // 0 - медленные запросы не отслеживаются
+
+	timeouts       map[string]time.Duration // имя метода -> тайм-аут запросов
+	defaultTimeout time.Duration            // 0 - без тайм-аута
+}
+
+// newOptions разбирает строку подключения и применяет к ней параметры.
+func newOptions(constr string, opts []Option) (*options, error) {
+	config, err := pgxpool.ParseConfig(constr)
+	if err != nil {
+		return nil, err
+	}
+	o := options{
+		config: config,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &o, nil
+}
+
+// Option задаёт необязательный параметр хранилища.
+type Option func(*options)
+
+// WithMaxConns задаёт максимальное количество соединений в пуле.
+func WithMaxConns(n int32) Option {
+	return func(o *options) {
+		o.config.MaxConns = n
+	}
+}
+
+// WithMinConns задаёт минимальное количество соединений в пуле.
+func WithMinConns(n int32) Option {
+	return func(o *options) {
+		o.config.MinConns = n
+	}
+}
+
+// WithMaxConnLifetime задаёт время жизни соединения,
+// после которого оно закрывается и заменяется новым.
+func WithMaxConnLifetime(d time.Duration) Option {
+	return func(o *options) {
+		o.config.MaxConnLifetime = d
+	}
+}
+
+// WithMaxConnIdleTime задаёт время простоя,
+// после которого неиспользуемое соединение закрывается.
+func WithMaxConnIdleTime(d time.Duration) Option {
+	return func(o *options) {
+		o.config.MaxConnIdleTime = d
+	}
+}
+
+// WithAfterConnect задаёт функцию, которая вызывается для каждого
+// нового соединения пула, например для установки параметров сессии:
+//
+//	postgres.WithAfterConnect(func(ctx context.Context, conn *pgx.Conn) error {
+//		_, err := conn.Exec(ctx, "SET application_name = 'tasks'")
+//		return err
+//	})
+//
+// Если функция возвращает ошибку, соединение закрывается.
+func WithAfterConnect(fn func(context.Context, *pgx.Conn) error) Option {
+	return func(o *options) {
+		o.config.AfterConnect = fn
+	}
+}
+
+// WithBeforeAcquire задаёт функцию, которая вызывается перед выдачей
+// соединения из пула. Если функция возвращает false,
+// соединение закрывается и выдаётся другое.
+func WithBeforeAcquire(fn func(context.Context, *pgx.Conn) bool) Option {
+	return func(o *options) {
+		o.config.BeforeAcquire = fn
+	}
+}
+
+// WithReadReplica задаёт строку подключения к реплике БД.
+// Запросы, которые только читают данные, выполняются на реплике,
+// остальные - на основном сервере.
+func WithReadReplica(constr string) Option {
+	return func(o *options) {
+		o.readReplica = constr
+	}
+}
+
+// WithSlowQueryThreshold включает запись в журнал (slog.Warn)
+// запросов, которые выполняются дольше d.
+func WithSlowQueryThreshold(d time.Duration) Option {
+	return func(o *options) {
+		o.slowThreshold = d
+	}
+}
+
+// WithQueryTimeout задаёт тайм-аут метода хранилища с именем method
+// (например, "TaskById"): контекст запросов метода отменяется через d.
+func WithQueryTimeout(method string, d time.Duration) Option {
+	return func(o *options) {
+		if o.timeouts == nil {
+			o.timeouts = make(map[string]time.Duration)
+		}
+		o.timeouts[method] = d
+	}
+}
+
+// WithDefaultQueryTimeout задаёт тайм-аут методов хранилища,
+// для которых не задан собственный тайм-аут (см. WithQueryTimeout).
+func WithDefaultQueryTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.defaultTimeout = d
+	}
+}
+
+// WithPreparedStatements включает кэширование подготовленных операторов:
+// каждый запрос подготавливается на сервере один раз для соединения,
+// а затем выполняется без повторного разбора.
+//
+// В pgx этот режим используется по умолчанию, но может быть отключён
+// параметром default_query_exec_mode строки подключения, опция
+// возвращает его явно. Режим несовместим с пулерами соединений,
+// не поддерживающими именованные подготовленные операторы
+// (например, PgBouncer в режиме transaction или statement до версии 1.21):
+// для них следует использовать default_query_exec_mode=exec или simple_protocol.
+func WithPreparedStatements() Option {
+	return func(o *options) {
+		o.config.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeCacheStatement
+	}
+}
+
+// ValidateConnString проверяет строку подключения без установки соединения.
+// Кроме синтаксиса проверяется, что в ней явно заданы хост, порт и имя БД:
+// значения по умолчанию и переменные окружения PGHOST, PGPORT и PGDATABASE,
+// которые подставляет разбор строки, не учитываются.
+// Ошибка перечисляет все отсутствующие параметры.
+func ValidateConnString(constr string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: cannot parse connection string: %v", storage.ErrInvalidArgument, r)
+		}
+	}()
+
+	_, err = pgxpool.ParseConfig(constr)
+	if err != nil {
+		return fmt.Errorf("%w: cannot parse connection string: %v", storage.ErrInvalidArgument, err)
+	}
+
+	var params map[string]string
+	if strings.HasPrefix(constr, "postgres://") || strings.HasPrefix(constr, "postgresql://") {
+		params, err = urlParams(constr)
+	} else {
+		params, err = keywordParams(constr)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: cannot parse connection string: %v", storage.ErrInvalidArgument, err)
+	}
+
+	var missing []string
+	if params["host"] == "" {
+		missing = append(missing, "host")
+	}
+	if params["port"] == "" {
+		missing = append(missing, "port")
+	}
+	if params["dbname"] == "" && params["database"] == "" {
+		missing = append(missing, "database")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: connection string is missing %s", storage.ErrInvalidArgument, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// urlParams возвращает параметры, явно заданные в строке подключения
+// в формате URL. Хост, порт и имя БД могут быть указаны как в самом URL,
+// так и в параметрах запроса.
+func urlParams(constr string) (map[string]string, error) {
+	u, err := url.Parse(constr)
+	if err != nil {
+		return nil, err
+	}
+
+	params := make(map[string]string)
+	for k, v := range u.Query() {
+		params[k] = v[0]
+	}
+	// в URL может быть перечислено несколько хостов через запятую
+	for _, h := range strings.Split(u.Host, ",") {
+		host, port := h, ""
+		if i := strings.LastIndex(h, ":"); i >= 0 && !strings.HasSuffix(h, "]") {
+			host, port = h[:i], h[i+1:]
+		}
+		if host != "" && params["host"] == "" {
+			params["host"] = host
+		}
+		if port != "" && params["port"] == "" {
+			params["port"] = port
+		}
+	}
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" && params["dbname"] == "" {
+		params["dbname"] = db
+	}
+	return params, nil
+}
+
+// keywordParams возвращает параметры, явно заданные в строке подключения
+// в формате "ключ=значение". Значение может быть заключено в одинарные
+// кавычки, внутри которых кавычка и обратная косая черта экранируются \.
+func keywordParams(constr string) (map[string]string, error) {
+	params := make(map[string]string)
+	s := strings.TrimSpace(constr)
+	for s != "" {
+		eq := strings.IndexByte(s, '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("invalid key/value pair %q", s)
+		}
+		key := strings.TrimSpace(s[:eq])
+		s = strings.TrimLeft(s[eq+1:], " \t\n\r")
+
+		var val strings.Builder
+		if strings.HasPrefix(s, "'") {
+			i := 1
+			for ; i < len(s) && s[i] != '\''; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				val.WriteByte(s[i])
+			}
+			if i == len(s) {
+				return nil, fmt.Errorf("unterminated quoted value for %q", key)
+			}
+			s = s[i+1:]
+		} else {
+			i := 0
+			for ; i < len(s) && !strings.ContainsRune(" \t\n\r", rune(s[i])); i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				val.WriteByte(s[i])
+			}
+			s = s[i:]
+		}
+		params[key] = val.String()
+		s = strings.TrimSpace(s)
+	}
+	return params, nil
+}
@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AppendOutbox записывает исходящие сообщения в транзакции tx,
+// поэтому при её откате сообщения тоже отменяются.
+// Если tx равен nil, сообщения записываются без внешней транзакции.
+func (s *Storage) AppendOutbox(ctx context.Context, tx storage.TxStorage, msgs []storage.OutboxMessage) error {
+	ctx, cancel := s.withTimeout(ctx, "AppendOutbox")
+	defer cancel()
+
+	if tx != nil {
+		return tx.AppendOutbox(ctx, nil, msgs)
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	batch := pgx.Batch{}
+	for _, m := range msgs {
+		batch.Queue(`
+			INSERT INTO outbox (topic, payload)
+			VALUES ($1, $2);
+		`,
+			m.Topic,
+			m.Payload,
+		)
+	}
+	return wrapErr(s.pool.SendBatch(ctx, &batch).Close())
+}
+
+// PendingOutbox возвращает не более limit неотправленных сообщений
+// в порядке записи.
+func (s *Storage) PendingOutbox(ctx context.Context, limit int) ([]storage.OutboxMessage, error) {
+	ctx, cancel := s.withTimeout(ctx, "PendingOutbox")
+	defer cancel()
+
+	if limit <= 0 {
+		return nil, fmt.Errorf("%w: limit must be positive", storage.ErrInvalidArgument)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, topic, payload, created_at, sent_at
+		FROM outbox
+		WHERE sent_at IS NULL
+		ORDER BY id
+		LIMIT $1;
+	`,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var msgs []storage.OutboxMessage
+	for rows.Next() {
+		var m storage.OutboxMessage
+		err = rows.Scan(&m.ID, &m.Topic, &m.Payload, &m.CreatedAt, &m.SentAt)
+		if err != nil {
+			return nil, err
+		}
+
+		msgs = append(msgs, m)
+	}
+
+	return msgs, rows.Err()
+}
+
+// MarkOutboxSent отмечает сообщения как отправленные.
+func (s *Storage) MarkOutboxSent(ctx context.Context, ids []int64) error {
+	ctx, cancel := s.withTimeout(ctx, "MarkOutboxSent")
+	defer cancel()
+
+	_, err := s.pool.Exec(ctx, `
+		UPDATE outbox
+		SET sent_at = extract(epoch from now())
+		WHERE id = ANY($1) AND sent_at IS NULL;
+	`,
+		ids,
+	)
+	return err
+}
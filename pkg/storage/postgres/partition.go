@@ -0,0 +1,275 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// MigrateToPartitioned преобразует таблицу tasks в секционированную
+// по году открытия задачи: ключ секционирования - время открытия
+// to_timestamp(opened) типа TIMESTAMPTZ, сам столбец opened остаётся
+// Unix-временем, как и во всей схеме. Создаются секции за текущий и два
+// предыдущих года, а также за более ранние годы, если в таблице есть такие
+// задачи. Данные переносятся в одной транзакции. Для уже секционированной
+// таблицы ничего не делает.
+//
+// PostgreSQL не допускает у секционированной таблицы уникальных ограничений
+// без ключа секционирования, поэтому уникальность ID, ключа идемпотентности
+// и ссылки во внешней системе обеспечивает отдельная таблица tasks_keys,
+// которую поддерживает триггер tasks_keys_sync. Внешние ключи других таблиц
+// на tasks(id) переносятся на tasks_keys(id) с прежними действиями
+// (ON DELETE CASCADE и т.п.), а удаление задачи удаляет её запись
+// в tasks_keys. Индексы, триггеры и внешние ключи самой таблицы tasks
+// воссоздаются по их определениям в БД.
+func (s *Storage) MigrateToPartitioned(ctx context.Context) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var partitioned bool
+	err = tx.QueryRow(ctx, `
+		SELECT relkind = 'p'
+		FROM pg_class
+		WHERE oid = 'tasks'::regclass;
+	`).Scan(&partitioned)
+	if err != nil {
+		return err
+	}
+	if partitioned {
+		return nil
+	}
+
+	_, err = tx.Exec(ctx, `
+		LOCK TABLE tasks IN ACCESS EXCLUSIVE MODE;
+		ALTER TABLE tasks RENAME TO tasks_old;
+
+		CREATE TABLE tasks_keys (
+			id INTEGER PRIMARY KEY,
+			idempotency_key TEXT UNIQUE,
+			external_system TEXT,
+			external_id TEXT,
+			UNIQUE (external_system, external_id)
+		);
+
+		INSERT INTO tasks_keys (id, idempotency_key, external_system, external_id)
+		SELECT id, idempotency_key, external_system, external_id
+		FROM tasks_old;
+	`)
+	if err != nil {
+		return wrapErr(err)
+	}
+
+	// Определения индексов, триггеров и внешних ключей читаются до создания
+	// новой таблицы и применяются к ней после переноса данных.
+	indexes, err := definitions(ctx, tx, `
+		SELECT pg_get_indexdef(indexrelid)
+		FROM pg_index
+		WHERE indrelid = 'tasks_old'::regclass;
+	`)
+	if err != nil {
+		return err
+	}
+	triggers, err := definitions(ctx, tx, `
+		SELECT pg_get_triggerdef(oid)
+		FROM pg_trigger
+		WHERE tgrelid = 'tasks_old'::regclass AND NOT tgisinternal;
+	`)
+	if err != nil {
+		return err
+	}
+	outgoing, err := definitions(ctx, tx, `
+		SELECT format('ALTER TABLE tasks ADD CONSTRAINT %I %s;', conname, pg_get_constraintdef(oid))
+		FROM pg_constraint
+		WHERE contype = 'f' AND conrelid = 'tasks_old'::regclass;
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Внешние ключи других таблиц после переименования ссылаются
+	// на tasks_old и переносятся на tasks_keys с прежними действиями.
+	incoming, err := definitions(ctx, tx, `
+		SELECT format(
+			'ALTER TABLE %s DROP CONSTRAINT %I, ADD CONSTRAINT %I %s;',
+			conrelid::regclass, conname, conname,
+			regexp_replace(pg_get_constraintdef(oid), 'REFERENCES (\S+\.)?tasks_old\(', 'REFERENCES tasks_keys(')
+		)
+		FROM pg_constraint
+		WHERE contype = 'f' AND confrelid = 'tasks_old'::regclass;
+	`)
+	if err != nil {
+		return err
+	}
+	for _, sql := range incoming {
+		_, err = tx.Exec(ctx, sql)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		CREATE TABLE tasks (
+			LIKE tasks_old INCLUDING DEFAULTS INCLUDING GENERATED INCLUDING CONSTRAINTS
+		) PARTITION BY RANGE (to_timestamp(opened));
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Секции за годы, в которых есть задачи, и за последние три года.
+	current := time.Now().UTC().Year()
+	first := current - 2
+	var oldest *int
+	err = tx.QueryRow(ctx, `
+		SELECT extract(year from to_timestamp(MIN(opened)) AT TIME ZONE 'UTC')::INTEGER
+		FROM tasks_old;
+	`).Scan(&oldest)
+	if err != nil {
+		return err
+	}
+	if oldest != nil && *oldest < first {
+		first = *oldest
+	}
+	for year := first; year <= current; year++ {
+		err = createPartition(ctx, tx, year)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO tasks (`+taskColumns+`)
+		SELECT `+taskColumns+`
+		FROM tasks_old;
+
+		ALTER SEQUENCE tasks_id_seq OWNED BY tasks.id;
+		DROP TABLE tasks_old;
+	`)
+	if err != nil {
+		return wrapErr(err)
+	}
+
+	for _, def := range indexes {
+		_, err = tx.Exec(ctx, partitionedIndex(def))
+		if err != nil {
+			return err
+		}
+	}
+	for _, def := range triggers {
+		_, err = tx.Exec(ctx, triggerOnTable.ReplaceAllString(def, "${1}tasks "))
+		if err != nil {
+			return err
+		}
+	}
+	for _, sql := range outgoing {
+		_, err = tx.Exec(ctx, sql)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		CREATE FUNCTION tasks_keys_sync() RETURNS TRIGGER AS $$
+		BEGIN
+			IF TG_OP <> 'INSERT' THEN
+				-- при переносе задачи между секциями строка с тем же id
+				-- к этому моменту уже вставлена в новую секцию
+				DELETE FROM tasks_keys
+				WHERE id = OLD.id AND NOT EXISTS (SELECT 1 FROM tasks WHERE id = OLD.id);
+			END IF;
+			IF TG_OP <> 'DELETE' THEN
+				-- вставки с одним id выполняются по очереди,
+				-- чтобы повтор был виден проверке ниже
+				PERFORM pg_advisory_xact_lock(hashtext('tasks_keys'), NEW.id);
+				IF (SELECT count(*) FROM tasks WHERE id = NEW.id) > 1 THEN
+					RAISE unique_violation USING
+						MESSAGE = format('duplicate task id %s', NEW.id),
+						CONSTRAINT = 'tasks_keys_pkey';
+				END IF;
+				INSERT INTO tasks_keys (id, idempotency_key, external_system, external_id)
+				VALUES (NEW.id, NEW.idempotency_key, NEW.external_system, NEW.external_id)
+				ON CONFLICT (id) DO UPDATE SET
+					idempotency_key = EXCLUDED.idempotency_key,
+					external_system = EXCLUDED.external_system,
+					external_id = EXCLUDED.external_id;
+			END IF;
+			RETURN NULL;
+		END;
+		$$ LANGUAGE plpgsql;
+
+		CREATE TRIGGER tasks_keys_sync
+			AFTER INSERT OR UPDATE OR DELETE ON tasks
+			FOR EACH ROW EXECUTE FUNCTION tasks_keys_sync();
+	`)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Имя индекса и таблица в определении, которое возвращает pg_get_indexdef.
+var indexOnTable = regexp.MustCompile(`^CREATE (UNIQUE )?INDEX \S+ ON (ONLY )?\S+ `)
+
+// partitionedIndex возвращает определение индекса таблицы tasks_old
+// для секционированной таблицы tasks. Уникальные индексы становятся
+// обычными: уникальность обеспечивает tasks_keys. Имя индекса
+// назначается автоматически по имени таблицы и столбцов.
+func partitionedIndex(def string) string {
+	return indexOnTable.ReplaceAllString(def, "CREATE INDEX ON tasks ")
+}
+
+// Таблица в определении, которое возвращает pg_get_triggerdef.
+var triggerOnTable = regexp.MustCompile(`^(CREATE (?:CONSTRAINT )?TRIGGER \S+ .*? ON )\S+ `)
+
+// definitions возвращает результаты запроса, состоящего из одного
+// текстового столбца, например определения индексов.
+func definitions(ctx context.Context, q querier, sql string) ([]string, error) {
+	rows, err := q.Query(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var defs []string
+	for rows.Next() {
+		var def string
+		err = rows.Scan(&def)
+		if err != nil {
+			return nil, err
+		}
+		defs = append(defs, def)
+	}
+	return defs, rows.Err()
+}
+
+// CreatePartition создаёт секцию таблицы tasks для задач,
+// открытых в указанном году. Таблица должна быть секционирована
+// с помощью MigrateToPartitioned.
+func (s *Storage) CreatePartition(ctx context.Context, year int) error {
+	return createPartition(ctx, s.pool, year)
+}
+
+// createPartition создаёт секцию tasks_<год>. Индексы секционированной
+// таблицы создаются в секции автоматически.
+func createPartition(ctx context.Context, q querier, year int) error {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+	name := pgx.Identifier{fmt.Sprintf("tasks_%d", year)}.Sanitize()
+
+	_, err := q.Exec(ctx, fmt.Sprintf(`
+		CREATE TABLE %s PARTITION OF tasks
+			FOR VALUES FROM ('%s') TO ('%s');
+	`,
+		name,
+		from.Format(time.RFC3339),
+		to.Format(time.RFC3339),
+	))
+	return wrapErr(err)
+}
@@ -0,0 +1,197 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func TestPartitionedIndex(t *testing.T) {
+	tests := []struct {
+		def  string
+		want string
+	}{
+		{
+			"CREATE INDEX tasks_opened_idx ON public.tasks_old USING btree (opened)",
+			"CREATE INDEX ON tasks USING btree (opened)",
+		},
+		// уникальность обеспечивает tasks_keys
+		{
+			"CREATE UNIQUE INDEX tasks_pkey ON public.tasks_old USING btree (id)",
+			"CREATE INDEX ON tasks USING btree (id)",
+		},
+		{
+			"CREATE INDEX tasks_tags_idx ON ONLY tasks_old USING gin (tags)",
+			"CREATE INDEX ON tasks USING gin (tags)",
+		},
+	}
+	for _, tt := range tests {
+		if got := partitionedIndex(tt.def); got != tt.want {
+			t.Errorf("partitionedIndex(%q) = %q, want %q", tt.def, got, tt.want)
+		}
+	}
+}
+
+func TestTriggerOnTable(t *testing.T) {
+	tests := []struct {
+		def  string
+		want string
+	}{
+		{
+			"CREATE TRIGGER tasks_notify AFTER INSERT OR UPDATE ON public.tasks_old FOR EACH ROW EXECUTE FUNCTION notify_task()",
+			"CREATE TRIGGER tasks_notify AFTER INSERT OR UPDATE ON tasks FOR EACH ROW EXECUTE FUNCTION notify_task()",
+		},
+		{
+			"CREATE CONSTRAINT TRIGGER tasks_check AFTER DELETE ON tasks_old DEFERRABLE FOR EACH ROW EXECUTE FUNCTION check_task()",
+			"CREATE CONSTRAINT TRIGGER tasks_check AFTER DELETE ON tasks DEFERRABLE FOR EACH ROW EXECUTE FUNCTION check_task()",
+		},
+	}
+	for _, tt := range tests {
+		if got := triggerOnTable.ReplaceAllString(tt.def, "${1}tasks "); got != tt.want {
+			t.Errorf("trigger %q = %q, want %q", tt.def, got, tt.want)
+		}
+	}
+}
+
+// После секционирования все задачи доступны через Tasks(),
+// а ограничения уникальности продолжают действовать.
+func TestMigrateToPartitioned(t *testing.T) {
+	constr := os.Getenv("TEST_DATABASE_URL")
+	if constr == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, constr)
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer conn.Close(ctx)
+
+	// миграция выполняется в отдельной схеме, чтобы не затронуть
+	// таблицы других тестов
+	const schema = "partition_test"
+	_, err = conn.Exec(ctx, `
+		DROP SCHEMA IF EXISTS `+schema+` CASCADE;
+		CREATE SCHEMA `+schema+`;
+	`)
+	if err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = conn.Exec(context.Background(), `DROP SCHEMA IF EXISTS `+schema+` CASCADE;`)
+	})
+
+	err = Migrate(ctx, withSchema(constr, schema))
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	s, err := New(withSchema(constr, schema))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer s.Close()
+
+	_, err = s.pool.Exec(ctx, `INSERT INTO users (id, name) VALUES (0, 'default');`)
+	if err != nil {
+		t.Fatalf("insert default user: %v", err)
+	}
+	current, err := s.AddTask(ctx, storage.Task{Title: "current"})
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	// задача старше трёх последних лет получает свою секцию
+	var old int
+	err = s.pool.QueryRow(ctx, `
+		INSERT INTO tasks (title, opened) VALUES ('old', $1) RETURNING id;
+	`,
+		time.Date(2015, time.June, 1, 0, 0, 0, 0, time.UTC).Unix(),
+	).Scan(&old)
+	if err != nil {
+		t.Fatalf("insert old task: %v", err)
+	}
+	key := "key"
+	keyed, err := s.AddTask(ctx, storage.Task{Title: "keyed", IdempotencyKey: &key})
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	if _, err = s.AddComment(ctx, storage.Comment{TaskID: current, Body: "comment"}); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+
+	// повторный вызов ничего не делает
+	for i := range 2 {
+		err = s.MigrateToPartitioned(ctx)
+		if err != nil {
+			t.Fatalf("MigrateToPartitioned() run %d error = %v", i+1, err)
+		}
+	}
+
+	tasks, err := s.Tasks(ctx)
+	if err != nil {
+		t.Fatalf("Tasks() error = %v", err)
+	}
+	var got []int
+	for _, task := range tasks {
+		got = append(got, task.ID)
+	}
+	if want := []int{current, old, keyed}; !slices.Equal(got, want) {
+		t.Errorf("Tasks() ids = %v, want %v", got, want)
+	}
+
+	partitions, err := definitions(ctx, s.pool, `
+		SELECT relname FROM pg_class
+		WHERE relispartition AND relkind = 'r' AND relname LIKE 'tasks_%'
+		ORDER BY relname;
+	`)
+	if err != nil {
+		t.Fatalf("list partitions: %v", err)
+	}
+	if !slices.Contains(partitions, "tasks_2015") {
+		t.Errorf("partitions = %v, want tasks_2015", partitions)
+	}
+
+	// уникальность ключа идемпотентности сохраняется
+	id, err := s.AddTask(ctx, storage.Task{Title: "keyed again", IdempotencyKey: &key})
+	if err != nil {
+		t.Fatalf("AddTask() with existing key error = %v", err)
+	}
+	if id != keyed {
+		t.Errorf("AddTask() with existing key = %d, want %d", id, keyed)
+	}
+	// новые задачи получают следующие id
+	next, err := s.AddTask(ctx, storage.Task{Title: "next"})
+	if err != nil {
+		t.Fatalf("AddTask() after migration error = %v", err)
+	}
+	if next <= keyed {
+		t.Errorf("AddTask() after migration id = %d, want > %d", next, keyed)
+	}
+
+	// внешние ключи ссылаются на tasks_keys: комментарии удаляются
+	// вместе с задачей
+	_, err = s.pool.Exec(ctx, `DELETE FROM tasks WHERE id = $1;`, current)
+	if err != nil {
+		t.Fatalf("delete task: %v", err)
+	}
+	var comments int
+	err = s.pool.QueryRow(ctx, `SELECT count(*) FROM comments;`).Scan(&comments)
+	if err != nil {
+		t.Fatalf("count comments: %v", err)
+	}
+	if comments != 0 {
+		t.Errorf("comments after task delete = %d, want 0", comments)
+	}
+
+	year := time.Now().UTC().Year() + 1
+	if err = s.CreatePartition(ctx, year); err != nil {
+		t.Fatalf("CreatePartition(%d) error = %v", year, err)
+	}
+	if err = s.CreatePartition(ctx, year); err == nil {
+		t.Errorf("CreatePartition(%d) again error = nil, want error", year)
+	}
+}
@@ -2,61 +2,205 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// Хранилище должно реализовывать storage.Interface.
+var _ storage.Interface = (*Storage)(nil)
+
+// querier - общие методы пула соединений и транзакции,
+// через которые хранилище выполняет запросы.
+type querier interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
 // Хранилище данных.
 type Storage struct {
-	pool *pgxpool.Pool
+	pool     querier
+	readPool querier // для запросов на чтение, по умолчанию совпадает с pool
+
+	primary *pgxpool.Pool
+	replica *pgxpool.Pool // по умолчанию совпадает с primary
+
+	locks     *advisoryLocks // захваченные рекомендательные блокировки
+	taskLocks *taskLocks     // транзакции, удерживающие блокировки задач
+
+	timeouts       map[string]time.Duration // см. WithQueryTimeout
+	defaultTimeout time.Duration            // см. WithDefaultQueryTimeout
 }
 
-// Конструктор, принимает строку подключения к БД.
-func New(constr string) (*Storage, error) {
-	pool, err := pgxpool.New(context.Background(), constr)
+// Конструктор, принимает строку подключения к БД
+// и необязательные параметры хранилища.
+func New(constr string, opts ...Option) (*Storage, error) {
+	o, err := newOptions(constr, opts)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := pgxpool.NewWithConfig(context.Background(), o.config)
 	if err != nil {
 		return nil, err
 	}
 	s := Storage{
-		pool: pool,
+		pool:      pool,
+		readPool:  pool,
+		primary:   pool,
+		replica:   pool,
+		locks:     newAdvisoryLocks(),
+		taskLocks: newTaskLocks(),
+
+		timeouts:       o.timeouts,
+		defaultTimeout: o.defaultTimeout,
+	}
+
+	if o.readReplica != "" {
+		// параметры пула применяются и к пулу реплики
+		ro, err := newOptions(o.readReplica, opts)
+		if err != nil {
+			pool.Close()
+			return nil, err
+		}
+		s.replica, err = pgxpool.NewWithConfig(context.Background(), ro.config)
+		if err != nil {
+			pool.Close()
+			return nil, err
+		}
+		s.readPool = s.replica
+	}
+
+	if o.slowThreshold > 0 {
+		s.pool = &slowQuerier{querier: s.pool, threshold: o.slowThreshold}
+		s.readPool = &slowQuerier{querier: s.readPool, threshold: o.slowThreshold}
 	}
+
 	return &s, nil
 }
 
-// Tasks возвращает список задач из БД.
-func (s *Storage) Tasks() ([]storage.Task, error) {
-	rows, err := s.pool.Query(context.Background(), `
-		SELECT 
+// withTimeout возвращает контекст с тайм-аутом метода method,
+// заданным WithQueryTimeout или WithDefaultQueryTimeout.
+// Если тайм-аут не задан, возвращается исходный контекст.
+func (s *Storage) withTimeout(ctx context.Context, method string) (context.Context, context.CancelFunc) {
+	d, ok := s.timeouts[method]
+	if !ok {
+		d = s.defaultTimeout
+	}
+	if d <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// Close закрывает все соединения пула.
+// Повторный вызов безопасен и ничего не делает.
+func (s *Storage) Close() {
+	s.primary.Close()
+	s.replica.Close()
+}
+
+// HealthCheck проверяет доступность БД и реплики для чтения.
+func (s *Storage) HealthCheck(ctx context.Context) error {
+	ctx, cancel := s.withTimeout(ctx, "HealthCheck")
+	defer cancel()
+
+	err := s.primary.Ping(ctx)
+	if err != nil {
+		return err
+	}
+	return s.replica.Ping(ctx)
+}
+
+// taskColumns перечисляет столбцы задачи в том порядке,
+// в котором их сканирует scanTask. Те же столбцы должны быть
+// в таблице tasks_archive, куда их копирует ArchiveTask.
+const taskColumns = `
 			id,
 			opened,
 			closed,
 			author_id,
 			assigned_id,
 			title,
-			content
-		FROM tasks
-		ORDER BY id;
-	`)
-	if err != nil {
-		return nil, err
+			content,
+			deleted_at,
+			status,
+			priority,
+			due_at,
+			project_id,
+			metadata,
+			idempotency_key,
+			version,
+			external_system,
+			external_id,
+			is_stale,
+			tags,
+			rank`
+
+// taskColumnsOf возвращает столбцы taskColumns, уточнённые
+// псевдонимом таблицы, для запросов с соединением таблиц.
+func taskColumnsOf(alias string) string {
+	cols := strings.Split(taskColumns, ",")
+	for i, col := range cols {
+		cols[i] = alias + "." + strings.TrimSpace(col)
+	}
+	return strings.Join(cols, ", ")
+}
+
+// scanTask сканирует строку результата запроса в задачу.
+// Запрос должен выбирать столбцы из taskColumns.
+func scanTask(row pgx.Row, t *storage.Task) error {
+	return row.Scan(taskFields(t)...)
+}
+
+// taskFields возвращает указатели на поля задачи
+// в порядке столбцов taskColumns.
+func taskFields(t *storage.Task) []any {
+	return []any{
+		&t.ID,
+		&t.Opened,
+		&t.Closed,
+		&t.AuthorID,
+		&t.AssignedID,
+		&t.Title,
+		&t.Content,
+		&t.DeletedAt,
+		&t.Status,
+		&t.Priority,
+		&t.DueAt,
+		&t.ProjectID,
+		&t.Metadata,
+		&t.IdempotencyKey,
+		&t.Version,
+		&t.ExternalSystem,
+		&t.ExternalID,
+		&t.Stale,
+		&t.Tags,
+		&t.Rank,
 	}
+}
+
+// collectTasks вычитывает все строки результата запроса в слайс задач.
+func collectTasks(rows pgx.Rows) ([]storage.Task, error) {
+	defer rows.Close()
+
 	var tasks []storage.Task
 
 	// итерирование по результату выполнения запроса
 	// и сканирование каждой строки в переменную
 	for rows.Next() {
 		var t storage.Task
-		err = rows.Scan(
-			&t.ID,
-			&t.Opened,
-			&t.Closed,
-			&t.AuthorID,
-			&t.AssignedID,
-			&t.Title,
-			&t.Content,
-		)
+		err := scanTask(rows, &t)
 		if err != nil {
 			return nil, err
 		}
@@ -69,52 +213,258 @@ func (s *Storage) Tasks() ([]storage.Task, error) {
 	return tasks, rows.Err()
 }
 
+// Tasks возвращает список задач из БД.
+//
+// Deprecated: используйте TasksList для постраничной выборки.
+func (s *Storage) Tasks(ctx context.Context) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "Tasks")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE deleted_at IS NULL
+		ORDER BY id;
+	`)
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
+}
+
+// TasksIter вызывает fn для каждой неудалённой задачи в порядке ID
+// по мере чтения строк результата, не загружая все задачи в память.
+// Если fn возвращает ошибку, перебор прекращается и ошибка возвращается.
+func (s *Storage) TasksIter(ctx context.Context, fn func(t storage.Task) error) error {
+	ctx, cancel := s.withTimeout(ctx, "TasksIter")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE deleted_at IS NULL
+		ORDER BY id;
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t storage.Task
+		err = scanTask(rows, &t)
+		if err != nil {
+			return err
+		}
+		err = fn(t)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// TasksIncludingDeleted возвращает список задач вместе с удалёнными.
+func (s *Storage) TasksIncludingDeleted(ctx context.Context) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "TasksIncludingDeleted")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		ORDER BY id;
+	`)
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
+}
+
+// orderColumns содержит столбцы, по которым допускается сортировка задач.
+// Имя столбца подставляется в текст запроса, поэтому значения
+// из ListOptions.OrderBy сверяются с этим списком.
+var orderColumns = map[string]bool{
+	"id":          true,
+	"opened":      true,
+	"closed":      true,
+	"author_id":   true,
+	"assigned_id": true,
+	"title":       true,
+	"priority":    true,
+}
+
+// sortColumns сопоставляет полю сортировки столбец таблицы tasks.
+// Поля вне этого списка отклоняются, в запрос подставляется
+// только имя столбца из него.
+var sortColumns = map[storage.SortField]string{
+	storage.SortByID:       "id",
+	storage.SortByOpened:   "opened",
+	storage.SortByPriority: "priority",
+	storage.SortByTitle:    "title",
+}
+
+// TasksList возвращает страницу задач в соответствии с параметрами выборки.
+func (s *Storage) TasksList(ctx context.Context, opts storage.ListOptions) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "TasksList")
+	defer cancel()
+
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	orderBy := opts.OrderBy
+	if orderBy == "" {
+		orderBy = "id"
+	}
+	if opts.Sort.Field != "" {
+		col, ok := sortColumns[opts.Sort.Field]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown sort field %q", storage.ErrInvalidArgument, opts.Sort.Field)
+		}
+		orderBy = col
+	}
+	if !orderColumns[orderBy] {
+		return nil, fmt.Errorf("storage: unknown order column %q", orderBy)
+	}
+	dir := "ASC"
+	if opts.Sort.Dir == storage.SortDesc {
+		dir = "DESC"
+	}
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE deleted_at IS NULL
+		ORDER BY `+orderBy+` `+dir+`, id
+		LIMIT $1 OFFSET $2;
+	`,
+		opts.Limit,
+		opts.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
+}
+
+// TotalCount возвращает общее количество задач.
+func (s *Storage) TotalCount(ctx context.Context) (int, error) {
+	ctx, cancel := s.withTimeout(ctx, "TotalCount")
+	defer cancel()
+
+	var n int
+	err := s.readPool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM tasks
+		WHERE deleted_at IS NULL;
+	`).Scan(&n)
+	return n, err
+}
+
+// TaskCount возвращает количество задач.
+func (s *Storage) TaskCount(ctx context.Context) (int, error) {
+	ctx, cancel := s.withTimeout(ctx, "TaskCount")
+	defer cancel()
+
+	var n int
+	err := s.readPool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM tasks
+		WHERE deleted_at IS NULL;
+	`).Scan(&n)
+	return n, err
+}
+
+// TaskCountByAuthor возвращает количество задач автора.
+func (s *Storage) TaskCountByAuthor(ctx context.Context, authorID int) (int, error) {
+	ctx, cancel := s.withTimeout(ctx, "TaskCountByAuthor")
+	defer cancel()
+
+	var n int
+	err := s.readPool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM tasks
+		WHERE author_id = $1 AND deleted_at IS NULL;
+	`,
+		authorID,
+	).Scan(&n)
+	return n, err
+}
+
+// TaskCountByLabel возвращает количество задач с меткой.
+func (s *Storage) TaskCountByLabel(ctx context.Context, labelID int) (int, error) {
+	ctx, cancel := s.withTimeout(ctx, "TaskCountByLabel")
+	defer cancel()
+
+	var n int
+	err := s.readPool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM tasks
+		WHERE id IN (
+			SELECT task_id FROM tasks_labels
+			WHERE label_id = $1
+		) AND deleted_at IS NULL;
+	`,
+		labelID,
+	).Scan(&n)
+	return n, err
+}
+
+// TasksAfter возвращает не более limit задач с ID больше afterID.
+// Для получения первой страницы передаётся afterID = 0,
+// для следующей - ID последней задачи предыдущей страницы.
+// В отличие от TasksList запрос использует индекс первичного ключа
+// и не замедляется по мере удаления от начала таблицы.
+func (s *Storage) TasksAfter(ctx context.Context, afterID int, limit int) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "TasksAfter")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE id > $1 AND deleted_at IS NULL
+		ORDER BY id
+		LIMIT $2;
+	`,
+		afterID,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
+}
+
 // TaskById возвращает задачу по её ID.
-func (s *Storage) TaskById(taskId int) (*storage.Task, error) {
+// Для удалённой задачи возвращается storage.ErrNotFound.
+func (s *Storage) TaskById(ctx context.Context, taskId int) (*storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "TaskById")
+	defer cancel()
+
 	var t storage.Task
 
-	err := s.pool.QueryRow(context.Background(), `
-		SELECT 
-			id,
-			opened,
-			closed,
-			author_id,
-			assigned_id,
-			title,
-			content
+	err := scanTask(s.readPool.QueryRow(ctx, `
+		SELECT `+taskColumns+`
 		FROM tasks
 		WHERE id = $1;
 	`,
 		taskId,
-	).Scan(
-		&t.ID,
-		&t.Opened,
-		&t.Closed,
-		&t.AuthorID,
-		&t.AssignedID,
-		&t.Title,
-		&t.Content,
-	)
+	), &t)
 	if err != nil {
-		return nil, err
+		return nil, wrapErr(err)
+	}
+	if t.DeletedAt.Valid {
+		return nil, storage.ErrNotFound
 	}
 
-	return &t, err
+	return &t, nil
 }
 
 // TasksByAuthor возвращает слайс задач по ID автора.
-func (s *Storage) TasksByAuthor(authorId int) ([]storage.Task, error) {
-	rows, err := s.pool.Query(context.Background(), `
-		SELECT 
-			id,
-			opened,
-			closed,
-			author_id,
-			assigned_id,
-			title,
-			content
+func (s *Storage) TasksByAuthor(ctx context.Context, authorId int) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "TasksByAuthor")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
 		FROM tasks
-		WHERE author_id = $1
+		WHERE author_id = $1 AND deleted_at IS NULL
 		ORDER BY id;
 	`,
 		authorId,
@@ -122,37 +472,57 @@ func (s *Storage) TasksByAuthor(authorId int) ([]storage.Task, error) {
 	if err != nil {
 		return nil, err
 	}
-	var tasks []storage.Task
+	return collectTasks(rows)
+}
 
-	for rows.Next() {
-		var t storage.Task
-		err = rows.Scan(
-			&t.ID,
-			&t.Opened,
-			&t.Closed,
-			&t.AuthorID,
-			&t.AssignedID,
-			&t.Title,
-			&t.Content,
-		)
-		if err != nil {
-			return nil, err
-		}
+// TasksByAssignee возвращает слайс задач по ID исполнителя.
+func (s *Storage) TasksByAssignee(ctx context.Context, assigneeID int) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "TasksByAssignee")
+	defer cancel()
 
-		tasks = append(tasks, t)
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE assigned_id = $1 AND deleted_at IS NULL
+		ORDER BY id;
+	`,
+		assigneeID,
+	)
+	if err != nil {
+		return nil, err
 	}
+	return collectTasks(rows)
+}
 
-	return tasks, rows.Err()
+// TasksUnassigned возвращает задачи без исполнителя (assigned_id = 0).
+func (s *Storage) TasksUnassigned(ctx context.Context) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "TasksUnassigned")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE assigned_id = 0 AND deleted_at IS NULL
+		ORDER BY id;
+	`)
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
 }
 
 // TasksByLabel возвращает слайс задач по ID метки.
-func (s *Storage) TasksByLabel(labelId int) ([]storage.Task, error) {
-	rows, err := s.pool.Query(context.Background(), `
-		SELECT * FROM tasks
+func (s *Storage) TasksByLabel(ctx context.Context, labelId int) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "TasksByLabel")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
 		WHERE id IN (
 			SELECT task_id FROM tasks_labels
 			WHERE label_id = $1
-		)
+		) AND deleted_at IS NULL
 		ORDER BY id;
 	`,
 		labelId,
@@ -160,130 +530,1554 @@ func (s *Storage) TasksByLabel(labelId int) ([]storage.Task, error) {
 	if err != nil {
 		return nil, err
 	}
-	var tasks []storage.Task
+	return collectTasks(rows)
+}
+
+// TasksByLabels возвращает задачи, имеющие хотя бы одну из меток
+// (storage.LabelFilterAny) или все метки (storage.LabelFilterAll).
+func (s *Storage) TasksByLabels(ctx context.Context, labelIDs []int, mode storage.LabelFilterMode) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "TasksByLabels")
+	defer cancel()
 
+	if len(labelIDs) == 0 {
+		return nil, fmt.Errorf("%w: no labels given", storage.ErrInvalidArgument)
+	}
+	// повторяющиеся ID не должны влиять на подсчёт меток
+	ids := slices.Clone(labelIDs)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	var (
+		rows pgx.Rows
+		err  error
+	)
+	switch mode {
+	case storage.LabelFilterAny:
+		rows, err = s.readPool.Query(ctx, `
+			SELECT `+taskColumns+`
+			FROM tasks
+			WHERE id IN (
+				SELECT task_id FROM tasks_labels
+				WHERE label_id = ANY($1)
+			) AND deleted_at IS NULL
+			ORDER BY id;
+		`,
+			ids,
+		)
+	case storage.LabelFilterAll:
+		rows, err = s.readPool.Query(ctx, `
+			SELECT `+taskColumns+`
+			FROM tasks
+			WHERE id IN (
+				SELECT task_id FROM tasks_labels
+				WHERE label_id = ANY($1)
+				GROUP BY task_id
+				HAVING COUNT(DISTINCT label_id) = $2
+			) AND deleted_at IS NULL
+			ORDER BY id;
+		`,
+			ids,
+			len(ids),
+		)
+	default:
+		return nil, fmt.Errorf("%w: unknown label filter mode %d", storage.ErrInvalidArgument, mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
+}
+
+// TasksWithLabels возвращает список задач вместе с их метками.
+// Задачи и метки выбираются одним запросом, строки одной задачи
+// объединяются в один элемент результата.
+func (s *Storage) TasksWithLabels(ctx context.Context) ([]storage.TaskWithLabels, error) {
+	ctx, cancel := s.withTimeout(ctx, "TasksWithLabels")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumnsOf("t")+`, l.id, l.name
+		FROM tasks t
+		LEFT JOIN tasks_labels tl ON t.id = tl.task_id
+		LEFT JOIN labels l ON tl.label_id = l.id
+		WHERE t.deleted_at IS NULL
+		ORDER BY t.id, l.id;
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []storage.TaskWithLabels
 	for rows.Next() {
-		var t storage.Task
-		err = rows.Scan(
-			&t.ID,
-			&t.Opened,
-			&t.Closed,
-			&t.AuthorID,
-			&t.AssignedID,
-			&t.Title,
-			&t.Content,
+		var (
+			t         storage.Task
+			labelID   *int
+			labelName *string
 		)
+		err = rows.Scan(append(taskFields(&t), &labelID, &labelName)...)
 		if err != nil {
 			return nil, err
 		}
 
-		tasks = append(tasks, t)
+		// строки упорядочены по ID задачи, поэтому строки одной задачи
+		// идут подряд
+		if len(tasks) == 0 || tasks[len(tasks)-1].ID != t.ID {
+			tasks = append(tasks, storage.TaskWithLabels{Task: t})
+		}
+		// у задачи без меток столбцы меток равны NULL
+		if labelID != nil {
+			last := &tasks[len(tasks)-1]
+			last.Labels = append(last.Labels, storage.Label{ID: *labelID, Name: *labelName})
+		}
 	}
 
 	return tasks, rows.Err()
 }
 
-// AddTask создаёт новую задачу и возвращает её id.
-func (s *Storage) AddTask(t storage.Task) (int, error) {
-	var id int
-	err := s.pool.QueryRow(context.Background(), `
-		INSERT INTO tasks (title, content)
-		VALUES ($1, $2) RETURNING id;
-	`,
-		t.Title,
-		t.Content,
-	).Scan(&id)
-	return id, err
-}
-
-// AddTasks создаёт новые задачи и возвращает слайс ID созданых задач.
-// Пример работы с транзакцией.
-func (s *Storage) AddTasks(tasks []storage.Task) ([]int, error) {
-	var ids []int
-
-	// Простой базовый контект без таймаута.
-	ctx := context.Background()
+// TasksWithUsers возвращает список задач вместе с авторами и исполнителями.
+func (s *Storage) TasksWithUsers(ctx context.Context) ([]storage.TaskWithUsers, error) {
+	ctx, cancel := s.withTimeout(ctx, "TasksWithUsers")
+	defer cancel()
 
-	// Начинаем транзакцию с базой данных.
-	tx, err := s.pool.Begin(ctx)
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumnsOf("t")+`, u1.id, u1.name, u1.role, u2.id, u2.name, u2.role
+		FROM tasks t
+		LEFT JOIN users u1 ON t.author_id = u1.id
+		LEFT JOIN users u2 ON t.assigned_id = u2.id
+		WHERE t.deleted_at IS NULL
+		ORDER BY t.id;
+	`)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	// Проходим по слайсу задач и отправляем задачу на создание в базу данных.
-	for _, task := range tasks {
-		var id int
-		err := tx.QueryRow(ctx, `
-			INSERT INTO tasks (title, content)
-			VALUES ($1, $2) RETURNING id;
-		`,
-			task.Title,
-			task.Content,
-		).Scan(&id)
+	var tasks []storage.TaskWithUsers
+	for rows.Next() {
+		var (
+			t                        storage.TaskWithUsers
+			authorID, assigneeID     *int
+			authorName, assigneeName *string
+			authorRole, assigneeRole *storage.Role
+		)
+		err = rows.Scan(append(taskFields(&t.Task),
+			&authorID, &authorName, &authorRole,
+			&assigneeID, &assigneeName, &assigneeRole,
+		)...)
 		if err != nil {
-			// в случае неудачного выполнения запроса откатываем изменения
-			// и возвращаем полученную ошибку
-			tx.Rollback(ctx)
 			return nil, err
 		}
-		ids = append(ids, id)
-	}
+		t.Author = joinedUser(authorID, authorName, authorRole)
+		t.Assignee = joinedUser(assigneeID, assigneeName, assigneeRole)
 
-	// Применяем все изменения в базе данных.
-	err = tx.Commit(ctx)
+		tasks = append(tasks, t)
+	}
 
-	// Возвращаем слайс ID созданных задач.
-	return ids, err
+	return tasks, rows.Err()
 }
 
-// AddTasksBatch создаёт новые задачи и в случае неудачи возвращает ошибку.
+// joinedUser возвращает пользователя из столбцов внешнего соединения
+// или nil, если пользователь не назначен: столбцы равны NULL
+// или задан пользователь по умолчанию с ID 0.
+func joinedUser(id *int, name *string, role *storage.Role) *storage.User {
+	if id == nil || *id == 0 {
+		return nil
+	}
+	return &storage.User{ID: *id, Name: *name, UserRole: *role}
+}
+
+// FilterTasks возвращает слайс задач, удовлетворяющих фильтру.
+// Запрос собирается из условий только для заданных полей фильтра,
+// значения полей передаются параметрами запроса.
+func (s *Storage) FilterTasks(ctx context.Context, f storage.TaskFilter) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "FilterTasks")
+	defer cancel()
+
+	var (
+		conds = []string{"deleted_at IS NULL"}
+		args  []any
+	)
+	// add добавляет условие, подставляя в него номер очередного параметра.
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if f.AuthorID != nil {
+		add("author_id = $%d", *f.AuthorID)
+	}
+	if f.AssignedID != nil {
+		add("assigned_id = $%d", *f.AssignedID)
+	}
+	if f.LabelID != nil {
+		add("id IN (SELECT task_id FROM tasks_labels WHERE label_id = $%d)", *f.LabelID)
+	}
+	if f.OpenedAfter != nil {
+		add("opened > $%d", *f.OpenedAfter)
+	}
+	if f.OpenedBefore != nil {
+		add("opened < $%d", *f.OpenedBefore)
+	}
+	if f.ClosedAfter != nil {
+		add("closed > $%d", *f.ClosedAfter)
+	}
+	if f.ClosedBefore != nil {
+		add("closed < $%d", *f.ClosedBefore)
+	}
+	if f.TitleContains != "" {
+		add("strpos(lower(title), lower($%d)) > 0", f.TitleContains)
+	}
+	if f.Priority != nil {
+		add("priority = $%d", *f.Priority)
+	}
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE `+strings.Join(conds, " AND ")+`
+		ORDER BY id;
+	`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
+}
+
+// TasksByStatus возвращает слайс задач с указанным статусом.
+func (s *Storage) TasksByStatus(ctx context.Context, status storage.Status) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "TasksByStatus")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE status = $1 AND deleted_at IS NULL
+		ORDER BY id;
+	`,
+		status,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
+}
+
+// TasksByPriority возвращает слайс задач с указанным приоритетом.
+func (s *Storage) TasksByPriority(ctx context.Context, p storage.Priority) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "TasksByPriority")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE priority = $1 AND deleted_at IS NULL
+		ORDER BY id;
+	`,
+		p,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
+}
+
+// TasksOrderedByPriority возвращает список задач,
+// начиная с задач с наивысшим приоритетом.
+func (s *Storage) TasksOrderedByPriority(ctx context.Context) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "TasksOrderedByPriority")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE deleted_at IS NULL
+		ORDER BY priority DESC, id ASC;
+	`)
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
+}
+
+// TasksOverdue возвращает незакрытые задачи, срок выполнения которых
+// истёк к моменту now, в порядке наступления срока.
+func (s *Storage) TasksOverdue(ctx context.Context, now int64) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "TasksOverdue")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE due_at IS NOT NULL AND due_at < $1 AND closed = 0
+			AND deleted_at IS NULL
+		ORDER BY due_at;
+	`,
+		now,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
+}
+
+// TasksByTag возвращает задачи с тегом tag.
+func (s *Storage) TasksByTag(ctx context.Context, tag string) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "TasksByTag")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE $1 = ANY(tags) AND deleted_at IS NULL
+		ORDER BY id;
+	`,
+		tag,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
+}
+
+// TasksByRank возвращает задачи в порядке возрастания ранга.
+func (s *Storage) TasksByRank(ctx context.Context) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "TasksByRank")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE deleted_at IS NULL
+		ORDER BY rank ASC, id ASC;
+	`)
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
+}
+
+// UpdateTaskRank изменяет ранг задачи. Чтобы переместить задачу
+// между двумя другими, достаточно назначить ей ранг между их рангами.
+func (s *Storage) UpdateTaskRank(ctx context.Context, taskID int, rank float64) error {
+	ctx, cancel := s.withTimeout(ctx, "UpdateTaskRank")
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE tasks
+		SET rank = $2
+		WHERE id = $1;
+	`,
+		taskID,
+		rank,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+// AllTags возвращает все теги задач по алфавиту без повторов.
+func (s *Storage) AllTags(ctx context.Context) ([]string, error) {
+	ctx, cancel := s.withTimeout(ctx, "AllTags")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT DISTINCT UNNEST(tags)
+		FROM tasks
+		WHERE deleted_at IS NULL
+		ORDER BY 1;
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []string
+	for rows.Next() {
+		var tag string
+		err = rows.Scan(&tag)
+		if err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// StaleTasks возвращает незакрытые задачи, открытые более
+// staleAfterSeconds секунд к моменту now, в порядке открытия.
+func (s *Storage) StaleTasks(ctx context.Context, staleAfterSeconds int64, now int64) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "StaleTasks")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE $2 - opened > $1 AND closed = 0 AND deleted_at IS NULL
+		ORDER BY opened, id;
+	`,
+		staleAfterSeconds,
+		now,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
+}
+
+// MarkTaskStale отмечает задачу как "зависшую".
+func (s *Storage) MarkTaskStale(ctx context.Context, taskID int) error {
+	ctx, cancel := s.withTimeout(ctx, "MarkTaskStale")
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE tasks
+		SET is_stale = TRUE
+		WHERE id = $1;
+	`,
+		taskID,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+// TasksDueBetween возвращает задачи со сроком выполнения
+// в интервале [from, to] в порядке наступления срока.
+func (s *Storage) TasksDueBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "TasksDueBetween")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE due_at >= $1 AND due_at <= $2 AND deleted_at IS NULL
+		ORDER BY due_at, id;
+	`,
+		from,
+		to,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
+}
+
+// TasksByMetadataKey возвращает задачи, у которых атрибут key равен value.
+// Условие записано через оператор @>, чтобы использовать GIN-индекс
+// по столбцу metadata.
+func (s *Storage) TasksByMetadataKey(ctx context.Context, key, value string) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "TasksByMetadataKey")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE metadata @> jsonb_build_object($1::text, $2::text) AND deleted_at IS NULL
+		ORDER BY id;
+	`,
+		key,
+		value,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
+}
+
+// TasksOpenedBetween возвращает задачи, открытые в интервале [from, to].
+func (s *Storage) TasksOpenedBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "TasksOpenedBetween")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE opened >= $1 AND opened <= $2 AND deleted_at IS NULL
+		ORDER BY id;
+	`,
+		from,
+		to,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
+}
+
+// TasksClosedBetween возвращает задачи, закрытые в интервале [from, to].
+func (s *Storage) TasksClosedBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "TasksClosedBetween")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE closed >= $1 AND closed <= $2 AND deleted_at IS NULL
+		ORDER BY id;
+	`,
+		from,
+		to,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
+}
+
+// TaskByExternalID возвращает задачу по ссылке во внешней системе.
+// Удалённые задачи не возвращаются.
+func (s *Storage) TaskByExternalID(ctx context.Context, system, externalID string) (*storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "TaskByExternalID")
+	defer cancel()
+
+	var t storage.Task
+	err := scanTask(s.readPool.QueryRow(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE external_system = $1 AND external_id = $2 AND deleted_at IS NULL;
+	`,
+		system,
+		externalID,
+	), &t)
+	if err != nil {
+		return nil, wrapErr(err)
+	}
+	return &t, nil
+}
+
+// TasksClosedInRange возвращает задачи, закрытые в интервале [from, to],
+// в порядке закрытия. Незакрытые задачи (closed = 0)
+// не возвращаются при любом интервале.
+func (s *Storage) TasksClosedInRange(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "TasksClosedInRange")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE closed != 0 AND closed >= $1 AND closed <= $2 AND deleted_at IS NULL
+		ORDER BY closed, id;
+	`,
+		from,
+		to,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
+}
+
+// insertTaskSQL создаёт задачу и возвращает её id.
+// Параметры запроса формирует insertTaskArgs.
+const insertTaskSQL = `
+		INSERT INTO tasks (title, content, priority, due_at, project_id, metadata, idempotency_key, external_system, external_id, tags)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id;
+	`
+
+// insertTaskColumns перечисляет столбцы, которые заполняет insertTaskSQL,
+// в порядке значений insertTaskArgs.
+var insertTaskColumns = []string{"title", "content", "priority", "due_at", "project_id", "metadata", "idempotency_key", "external_system", "external_id", "tags"}
+
+// insertTaskArgs возвращает параметры запроса insertTaskSQL.
+// Если приоритет не задан, задаче назначается storage.PriorityLow.
+func insertTaskArgs(t storage.Task) []any {
+	if t.Priority == 0 {
+		t.Priority = storage.PriorityLow
+	}
+	return []any{
+		t.Title,
+		t.Content,
+		t.Priority,
+		t.DueAt,
+		t.ProjectID,
+		metadataArg(t.Metadata),
+		t.IdempotencyKey,
+		t.ExternalSystem,
+		t.ExternalID,
+		tagsArg(t.Tags),
+	}
+}
+
+// tagsArg возвращает значение столбца tags:
+// пустой массив вместо nil, так как столбец объявлен NOT NULL.
+func tagsArg(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+// metadataArg возвращает атрибуты задачи для записи в столбец metadata.
+// Вместо nil записывается пустой объект, а не JSON null.
+func metadataArg(m map[string]string) map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+	return m
+}
+
+// AddTask создаёт новую задачу и возвращает её id.
+// Если задан ключ идемпотентности и задача с таким ключом уже есть,
+// новая задача не создаётся и возвращается id существующей.
+func (s *Storage) AddTask(ctx context.Context, t storage.Task) (int, error) {
+	ctx, cancel := s.withTimeout(ctx, "AddTask")
+	defer cancel()
+
+	var id int
+	if t.IdempotencyKey == nil {
+		err := s.pool.QueryRow(ctx, insertTaskSQL, insertTaskArgs(t)...).Scan(&id)
+		return id, wrapErr(err)
+	}
+
+	// Условие конфликта ON CONFLICT (idempotency_key) не используется:
+	// у секционированной таблицы (см. MigrateToPartitioned) нет уникального
+	// индекса по ключу. Поэтому сначала ищется задача с этим ключом, а если
+	// её параллельно создал другой запрос, вставка завершается нарушением
+	// уникальности ключа и задача ищется повторно. Нарушения других
+	// ограничений уникальности возвращаются как ошибка.
+	id, err := s.taskByIdempotencyKey(ctx, *t.IdempotencyKey)
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return id, wrapErr(err)
+	}
+	err = s.pool.QueryRow(ctx, insertTaskSQL, insertTaskArgs(t)...).Scan(&id)
+	if isIdempotencyConflict(err) {
+		id, err = s.taskByIdempotencyKey(ctx, *t.IdempotencyKey)
+	}
+	return id, wrapErr(err)
+}
+
+// isIdempotencyConflict сообщает, что вставка задачи нарушила
+// уникальность ключа идемпотентности.
+func isIdempotencyConflict(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation &&
+		strings.HasSuffix(pgErr.ConstraintName, "idempotency_key_key")
+}
+
+// taskByIdempotencyKey возвращает ID задачи с ключом идемпотентности key.
+func (s *Storage) taskByIdempotencyKey(ctx context.Context, key string) (int, error) {
+	var id int
+	err := s.pool.QueryRow(ctx, `
+		SELECT id FROM tasks
+		WHERE idempotency_key = $1;
+	`,
+		key,
+	).Scan(&id)
+	return id, err
+}
+
+// AddTaskWithLabels создаёт новую задачу, назначает ей метки
+// и возвращает её id. Задача и её метки создаются в одной транзакции,
+// поэтому при ошибке в БД не остаётся ни задачи, ни части меток.
+// Как и AddTask, при повторном ключе идемпотентности возвращает
+// id существующей задачи, не изменяя её метки.
+func (s *Storage) AddTaskWithLabels(ctx context.Context, t storage.Task, labelIDs []int) (int, error) {
+	ctx, cancel := s.withTimeout(ctx, "AddTaskWithLabels")
+	defer cancel()
+
+	if len(labelIDs) == 0 {
+		return s.AddTask(ctx, t)
+	}
+	if t.IdempotencyKey != nil {
+		id, err := s.taskByIdempotencyKey(ctx, *t.IdempotencyKey)
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return id, wrapErr(err)
+		}
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var id int
+	err = tx.QueryRow(ctx, insertTaskSQL, insertTaskArgs(t)...).Scan(&id)
+	if isIdempotencyConflict(err) {
+		// задачу с этим ключом параллельно создал другой запрос
+		tx.Rollback(ctx)
+		id, err = s.taskByIdempotencyKey(ctx, *t.IdempotencyKey)
+		return id, wrapErr(err)
+	}
+	if err != nil {
+		return 0, wrapErr(err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO tasks_labels (task_id, label_id)
+		SELECT $1, unnest($2::INTEGER[])
+		ON CONFLICT DO NOTHING;
+	`,
+		id,
+		labelIDs,
+	)
+	if err != nil {
+		return 0, wrapErr(err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// AddTasks создаёт новые задачи и возвращает слайс ID созданых задач.
+// Пример работы с транзакцией.
+func (s *Storage) AddTasks(ctx context.Context, tasks []storage.Task) ([]int, error) {
+	ctx, cancel := s.withTimeout(ctx, "AddTasks")
+	defer cancel()
+
+	var ids []int
+
+	// Начинаем транзакцию с базой данных.
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Проходим по слайсу задач и отправляем задачу на создание в базу данных.
+	for _, task := range tasks {
+		var id int
+		err := tx.QueryRow(ctx, `
+			INSERT INTO tasks (title, content)
+			VALUES ($1, $2) RETURNING id;
+		`,
+			task.Title,
+			task.Content,
+		).Scan(&id)
+		if err != nil {
+			// в случае неудачного выполнения запроса откатываем изменения
+			// и возвращаем полученную ошибку
+			tx.Rollback(ctx)
+			return nil, wrapErr(err)
+		}
+		ids = append(ids, id)
+	}
+
+	// Применяем все изменения в базе данных.
+	err = tx.Commit(ctx)
+
+	// Возвращаем слайс ID созданных задач.
+	return ids, err
+}
+
+// ImportTasks создаёт задачи с помощью протокола COPY.
+// Предназначен для массовой загрузки: в отличие от AddTasksBatch
+// строки передаются потоком без отдельной инструкции INSERT на каждую.
+// Загрузка выполняется в транзакции и при ошибке откатывается целиком.
+func (s *Storage) ImportTasks(ctx context.Context, tasks []storage.Task) error {
+	ctx, cancel := s.withTimeout(ctx, "ImportTasks")
+	defer cancel()
+
+	rows := make([][]any, len(tasks))
+	for i, t := range tasks {
+		rows[i] = insertTaskArgs(t)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.CopyFrom(ctx,
+		pgx.Identifier{"tasks"},
+		insertTaskColumns,
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		return wrapErr(err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// AddTasksBatch создаёт новые задачи и возвращает слайс ID созданых задач
+// в порядке следования задач во входном слайсе.
 // Пример работы с партией запросов.
-func (s *Storage) AddTasksBatch(tasks []storage.Task) error {
+//
+// Партия отправляется с одной завершающей командой Sync, поэтому сервер
+// выполняет её как одну неявную транзакцию: ошибка любого запроса
+// откатывает все вставки партии. В этом случае возвращаются nil вместо
+// ID и *storage.BatchError с ошибками по каждому элементу: первая из них -
+// причина отката, запросы после неё отклоняются сервером.
+func (s *Storage) AddTasksBatch(ctx context.Context, tasks []storage.Task) ([]int, error) {
+	ctx, cancel := s.withTimeout(ctx, "AddTasksBatch")
+	defer cancel()
+
 	batch := pgx.Batch{}
 
 	for _, task := range tasks {
 		batch.Queue(`
 			INSERT INTO tasks (title, content)
-			VALUES ($1, $2);
+			VALUES ($1, $2) RETURNING id;
+		`,
+			task.Title,
+			task.Content,
+		)
+	}
+
+	results := s.pool.SendBatch(ctx, &batch)
+	defer results.Close()
+
+	// Результаты читаются в том же порядке, в котором запросы
+	// были добавлены в партию.
+	ids := make([]int, 0, len(tasks))
+	var errs []storage.IndexedError
+	for i := range tasks {
+		var id int
+		err := results.QueryRow().Scan(&id)
+		if err != nil {
+			errs = append(errs, storage.IndexedError{Index: i, Err: wrapErr(err)})
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	if len(errs) > 0 {
+		// Транзакция партии откачена, ни одна задача не создана.
+		return nil, &storage.BatchError{Errors: errs, Total: len(tasks)}
+	}
+	return ids, nil
+}
+
+// UpdateTask обновляет задачу принимая в качестве агрумента экземпляр структуры Task.
+// Задача обновляется, только если её версия в БД совпадает с task.Version,
+// иначе возвращается storage.ErrVersionConflict. Версию увеличивает
+// триггер tasks_version.
+func (s *Storage) UpdateTask(ctx context.Context, task storage.Task) error {
+	ctx, cancel := s.withTimeout(ctx, "UpdateTask")
+	defer cancel()
+
+	if task.Priority == 0 {
+		task.Priority = storage.PriorityLow
+	}
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE tasks
+		SET (opened, closed, author_id, assigned_id, title, content, due_at, project_id, metadata, external_system, external_id, tags, priority) =
+			($2, $3, $4, $5, $6, $7, $8, $9, $10, $12, $13, $14, $15)
+		WHERE id = $1 AND version = $11;
+	`,
+		task.ID,
+		task.Opened,
+		task.Closed,
+		task.AuthorID,
+		task.AssignedID,
+		task.Title,
+		task.Content,
+		task.DueAt,
+		task.ProjectID,
+		metadataArg(task.Metadata),
+		task.Version,
+		task.ExternalSystem,
+		task.ExternalID,
+		tagsArg(task.Tags),
+		task.Priority,
+	)
+	if err != nil {
+		return wrapErr(err)
+	}
+	if tag.RowsAffected() > 0 {
+		return nil
+	}
+
+	// задача не обновлена: либо её нет, либо изменилась версия
+	var exists bool
+	err = s.pool.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM tasks
+			WHERE id = $1
+		);
+	`,
+		task.ID,
+	).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return storage.ErrNotFound
+	}
+	return storage.ErrVersionConflict
+}
+
+// PartialUpdateTask обновляет только те поля задачи, которые заданы в patch.
+// Остальные столбцы не перезаписываются, поэтому параллельные изменения
+// других полей не теряются.
+func (s *Storage) PartialUpdateTask(ctx context.Context, taskID int, patch storage.TaskPatch) error {
+	ctx, cancel := s.withTimeout(ctx, "PartialUpdateTask")
+	defer cancel()
+
+	var (
+		sets []string
+		args = []any{taskID}
+	)
+	// set добавляет присваивание столбцу очередного параметра запроса.
+	set := func(col string, arg any) {
+		args = append(args, arg)
+		sets = append(sets, fmt.Sprintf("%s = $%d", col, len(args)))
+	}
+
+	if patch.Opened != nil {
+		set("opened", *patch.Opened)
+	}
+	if patch.Closed != nil {
+		set("closed", *patch.Closed)
+	}
+	if patch.AuthorID != nil {
+		set("author_id", *patch.AuthorID)
+	}
+	if patch.AssignedID != nil {
+		set("assigned_id", *patch.AssignedID)
+	}
+	if patch.Title != nil {
+		set("title", *patch.Title)
+	}
+	if patch.Content != nil {
+		set("content", *patch.Content)
+	}
+	if patch.Priority != nil {
+		set("priority", *patch.Priority)
+	}
+	if len(sets) == 0 {
+		// Пустой patch ничего не меняет, но о несуществующей задаче
+		// вызывающий код должен узнать так же, как и при обычном обновлении.
+		var exists bool
+		err := s.pool.QueryRow(ctx, `
+			SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1);
 		`,
-			task.Title,
-			task.Content,
-		)
+			taskID,
+		).Scan(&exists)
+		if err != nil {
+			return wrapErr(err)
+		}
+		if !exists {
+			return storage.ErrNotFound
+		}
+		return nil
 	}
 
-	results := s.pool.SendBatch(context.Background(), &batch)
-	defer results.Close()
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE tasks
+		SET `+strings.Join(sets, ", ")+`
+		WHERE id = $1;
+	`,
+		args...,
+	)
+	if err != nil {
+		return wrapErr(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
 
-	_, err := results.Query()
+// UpdateTaskStatus изменяет статус задачи.
+func (s *Storage) UpdateTaskStatus(ctx context.Context, taskID int, status storage.Status) error {
+	ctx, cancel := s.withTimeout(ctx, "UpdateTaskStatus")
+	defer cancel()
 
-	return err
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE tasks
+		SET status = $2
+		WHERE id = $1;
+	`,
+		taskID,
+		status,
+	)
+	if err != nil {
+		return wrapErr(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
 }
 
-// UpdateTask обновляет задачу принимая в качестве агрумента экземпляр структуры Task.
-func (s *Storage) UpdateTask(task storage.Task) error {
-	_, err := s.pool.Query(context.Background(), `
+// AssignTask назначает задаче исполнителя.
+func (s *Storage) AssignTask(ctx context.Context, taskID, userID int) error {
+	ctx, cancel := s.withTimeout(ctx, "AssignTask")
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx, `
 		UPDATE tasks
-		SET (opened, closed, author_id, assigned_id, title, content) = ($2, $3, $4, $5, $6, $7)
+		SET assigned_id = $2
 		WHERE id = $1;
 	`,
-		task.ID,
-		task.Opened,
-		task.Closed,
-		task.AuthorID,
-		task.AssignedID,
-		task.Title,
-		task.Content,
+		taskID,
+		userID,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+// UpdateTasksAssignee назначает исполнителя задачам одним запросом
+// и возвращает количество изменённых задач.
+func (s *Storage) UpdateTasksAssignee(ctx context.Context, taskIDs []int, assigneeID int) (int64, error) {
+	ctx, cancel := s.withTimeout(ctx, "UpdateTasksAssignee")
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE tasks
+		SET assigned_id = $1
+		WHERE id = ANY($2);
+	`,
+		assigneeID,
+		taskIDs,
+	)
+	if err != nil {
+		return 0, wrapErr(err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// UpdateTasksStatus изменяет статус задач одним запросом
+// и возвращает количество изменённых задач.
+func (s *Storage) UpdateTasksStatus(ctx context.Context, taskIDs []int, status storage.Status) (int64, error) {
+	ctx, cancel := s.withTimeout(ctx, "UpdateTasksStatus")
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE tasks
+		SET status = $1
+		WHERE id = ANY($2);
+	`,
+		status,
+		taskIDs,
+	)
+	if err != nil {
+		return 0, wrapErr(err)
+	}
+	return tag.RowsAffected(), nil
 }
 
-// DeleteTask удаляет задачу по ID.
-func (s *Storage) DeleteTask(taskId int) error {
-	_, err := s.pool.Query(context.Background(), `
-		DELETE FROM tasks
+// UpsertTask создаёт задачу или обновляет существующую с тем же ID
+// и возвращает ID задачи. Если ID задачи равен нулю,
+// задача создаётся с новым ID, сгенерированным БД.
+// Если приоритет не задан, используется storage.PriorityLow. Нулевой ранг
+// означает ранг по умолчанию для новой задачи и прежний - для существующей.
+func (s *Storage) UpsertTask(ctx context.Context, t storage.Task) (int, error) {
+	ctx, cancel := s.withTimeout(ctx, "UpsertTask")
+	defer cancel()
+
+	if t.Priority == 0 {
+		t.Priority = storage.PriorityLow
+	}
+	var rank *float64
+	if t.Rank != 0 {
+		rank = &t.Rank
+	}
+
+	var id int
+	if t.ID == 0 {
+		err := s.pool.QueryRow(ctx, `
+			INSERT INTO tasks (opened, closed, author_id, assigned_id, title, content,
+				status, priority, due_at, project_id, metadata, tags, rank)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12,
+				COALESCE($13::DOUBLE PRECISION, extract(epoch from clock_timestamp()) * 1000))
+			RETURNING id;
+		`,
+			t.Opened,
+			t.Closed,
+			t.AuthorID,
+			t.AssignedID,
+			t.Title,
+			t.Content,
+			t.Status,
+			t.Priority,
+			t.DueAt,
+			t.ProjectID,
+			metadataArg(t.Metadata),
+			tagsArg(t.Tags),
+			rank,
+		).Scan(&id)
+		return id, wrapErr(err)
+	}
+
+	// Условие конфликта ON CONFLICT (id) не используется: у секционированной
+	// таблицы (см. MigrateToPartitioned) нет уникального индекса по id.
+	// Если задача с этим ID создаётся параллельно, вставка завершается
+	// нарушением уникальности.
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE tasks
+		SET (opened, closed, author_id, assigned_id, title, content,
+			status, priority, due_at, project_id, metadata, tags, rank) =
+			($2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13,
+				COALESCE($14::DOUBLE PRECISION, rank))
 		WHERE id = $1;
+	`,
+		t.ID,
+		t.Opened,
+		t.Closed,
+		t.AuthorID,
+		t.AssignedID,
+		t.Title,
+		t.Content,
+		t.Status,
+		t.Priority,
+		t.DueAt,
+		t.ProjectID,
+		metadataArg(t.Metadata),
+		tagsArg(t.Tags),
+		rank,
+	)
+	if err != nil {
+		return 0, wrapErr(err)
+	}
+	if tag.RowsAffected() > 0 {
+		return t.ID, nil
+	}
+
+	_, err = s.pool.Exec(ctx, `
+		INSERT INTO tasks (id, opened, closed, author_id, assigned_id, title, content,
+			status, priority, due_at, project_id, metadata, tags, rank)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13,
+			COALESCE($14::DOUBLE PRECISION, extract(epoch from clock_timestamp()) * 1000));
+	`,
+		t.ID,
+		t.Opened,
+		t.Closed,
+		t.AuthorID,
+		t.AssignedID,
+		t.Title,
+		t.Content,
+		t.Status,
+		t.Priority,
+		t.DueAt,
+		t.ProjectID,
+		metadataArg(t.Metadata),
+		tagsArg(t.Tags),
+		rank,
+	)
+	if err != nil {
+		return 0, wrapErr(err)
+	}
+
+	// Явно заданный ID не продвигает последовательность tasks_id_seq,
+	// поэтому без этого следующий AddTask может получить уже занятый ID.
+	_, err = s.pool.Exec(ctx, `
+		SELECT setval(pg_get_serial_sequence('tasks', 'id'), GREATEST($1,
+			COALESCE(pg_sequence_last_value(pg_get_serial_sequence('tasks', 'id')::regclass), 1)));
+	`,
+		t.ID,
+	)
+	return t.ID, wrapErr(err)
+}
+
+// DeleteTask помечает задачу как удалённую.
+// Запись остаётся в БД и может быть восстановлена через UndeleteTask.
+// Для отсутствующей или уже удалённой задачи возвращается ErrNotFound,
+// время удаления при этом не перезаписывается.
+func (s *Storage) DeleteTask(ctx context.Context, taskId int) error {
+	ctx, cancel := s.withTimeout(ctx, "DeleteTask")
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE tasks
+		SET deleted_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL;
 	`,
 		taskId,
 	)
+	if err != nil {
+		return wrapErr(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+// DeleteTasks помечает задачи как удалённые одним запросом.
+// Как и DeleteTask, записи остаются в БД, а уже удалённые задачи
+// пропускаются. Если не удалось пометить ни одной задачи,
+// возвращается ErrNotFound.
+func (s *Storage) DeleteTasks(ctx context.Context, taskIDs []int) error {
+	ctx, cancel := s.withTimeout(ctx, "DeleteTasks")
+	defer cancel()
+
+	if len(taskIDs) == 0 {
+		return nil
+	}
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE tasks
+		SET deleted_at = NOW()
+		WHERE id = ANY($1) AND deleted_at IS NULL;
+	`,
+		taskIDs,
+	)
+	if err != nil {
+		return wrapErr(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+// UndeleteTask восстанавливает удалённую задачу.
+// Если задачи нет или она не удалена, возвращается ErrNotFound.
+func (s *Storage) UndeleteTask(ctx context.Context, taskID int) error {
+	ctx, cancel := s.withTimeout(ctx, "UndeleteTask")
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE tasks
+		SET deleted_at = NULL
+		WHERE id = $1 AND deleted_at IS NOT NULL;
+	`,
+		taskID,
+	)
+	if err != nil {
+		return wrapErr(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+// AddUser создаёт нового пользователя и возвращает его id.
+func (s *Storage) AddUser(ctx context.Context, u storage.User) (int, error) {
+	ctx, cancel := s.withTimeout(ctx, "AddUser")
+	defer cancel()
+
+	var id int
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO users (name, role)
+		VALUES ($1, $2) RETURNING id;
+	`,
+		u.Name,
+		roleArg(u.UserRole),
+	).Scan(&id)
+	return id, wrapErr(err)
+}
+
+// Users возвращает список пользователей.
+func (s *Storage) Users(ctx context.Context) ([]storage.User, error) {
+	ctx, cancel := s.withTimeout(ctx, "Users")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT id, name, role
+		FROM users
+		ORDER BY id;
+	`)
+	if err != nil {
+		return nil, err
+	}
+	var users []storage.User
+
+	for rows.Next() {
+		var u storage.User
+		err = rows.Scan(&u.ID, &u.Name, &u.UserRole)
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, u)
+	}
+
+	return users, rows.Err()
+}
+
+// UserByID возвращает пользователя по его ID.
+// Если пользователь не найден, возвращается storage.ErrNotFound.
+func (s *Storage) UserByID(ctx context.Context, userID int) (*storage.User, error) {
+	ctx, cancel := s.withTimeout(ctx, "UserByID")
+	defer cancel()
+
+	var u storage.User
+	err := s.readPool.QueryRow(ctx, `
+		SELECT id, name, role
+		FROM users
+		WHERE id = $1;
+	`,
+		userID,
+	).Scan(&u.ID, &u.Name, &u.UserRole)
+	if err != nil {
+		return nil, wrapErr(err)
+	}
+
+	return &u, nil
+}
+
+// UpdateUser обновляет данные пользователя.
+func (s *Storage) UpdateUser(ctx context.Context, u storage.User) error {
+	ctx, cancel := s.withTimeout(ctx, "UpdateUser")
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE users
+		SET name = $2
+		WHERE id = $1;
+	`,
+		u.ID,
+		u.Name,
+	)
+	if err != nil {
+		return wrapErr(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+// AssignRole назначает пользователю роль.
+func (s *Storage) AssignRole(ctx context.Context, userID int, role storage.Role) error {
+	ctx, cancel := s.withTimeout(ctx, "AssignRole")
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE users
+		SET role = $2
+		WHERE id = $1;
+	`,
+		userID,
+		role,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+// RoleOfUser возвращает роль пользователя.
+func (s *Storage) RoleOfUser(ctx context.Context, userID int) (storage.Role, error) {
+	ctx, cancel := s.withTimeout(ctx, "RoleOfUser")
+	defer cancel()
+
+	var role storage.Role
+	err := s.readPool.QueryRow(ctx, `
+		SELECT role
+		FROM users
+		WHERE id = $1;
+	`,
+		userID,
+	).Scan(&role)
+	return role, wrapErr(err)
+}
+
+// UsersWithRole возвращает пользователей с ролью role.
+func (s *Storage) UsersWithRole(ctx context.Context, role storage.Role) ([]storage.User, error) {
+	ctx, cancel := s.withTimeout(ctx, "UsersWithRole")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT id, name, role
+		FROM users
+		WHERE role = $1
+		ORDER BY id;
+	`,
+		role,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []storage.User
+	for rows.Next() {
+		var u storage.User
+		err = rows.Scan(&u.ID, &u.Name, &u.UserRole)
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, u)
+	}
+
+	return users, rows.Err()
+}
+
+// roleArg возвращает роль для записи в БД.
+// Если роль не задана, назначается storage.RoleViewer.
+func roleArg(r storage.Role) storage.Role {
+	if r == 0 {
+		return storage.RoleViewer
+	}
+	return r
+}
+
+// DeleteUser удаляет пользователя по ID.
+func (s *Storage) DeleteUser(ctx context.Context, userID int) error {
+	ctx, cancel := s.withTimeout(ctx, "DeleteUser")
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx, `
+		DELETE FROM users
+		WHERE id = $1;
+	`,
+		userID,
+	)
+	if err != nil {
+		return wrapErr(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+// AddLabel создаёт новую метку и возвращает её id.
+func (s *Storage) AddLabel(ctx context.Context, l storage.Label) (int, error) {
+	ctx, cancel := s.withTimeout(ctx, "AddLabel")
+	defer cancel()
+
+	var id int
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO labels (name)
+		VALUES ($1) RETURNING id;
+	`,
+		l.Name,
+	).Scan(&id)
+	return id, wrapErr(err)
+}
+
+// Labels возвращает список меток.
+func (s *Storage) Labels(ctx context.Context) ([]storage.Label, error) {
+	ctx, cancel := s.withTimeout(ctx, "Labels")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT id, name
+		FROM labels
+		ORDER BY id;
+	`)
+	if err != nil {
+		return nil, err
+	}
+	var labels []storage.Label
+
+	for rows.Next() {
+		var l storage.Label
+		err = rows.Scan(&l.ID, &l.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		labels = append(labels, l)
+	}
+
+	return labels, rows.Err()
+}
+
+// LabelByID возвращает метку по её ID.
+func (s *Storage) LabelByID(ctx context.Context, labelID int) (*storage.Label, error) {
+	ctx, cancel := s.withTimeout(ctx, "LabelByID")
+	defer cancel()
+
+	var l storage.Label
+	err := s.readPool.QueryRow(ctx, `
+		SELECT id, name
+		FROM labels
+		WHERE id = $1;
+	`,
+		labelID,
+	).Scan(&l.ID, &l.Name)
+	if err != nil {
+		return nil, wrapErr(err)
+	}
+
+	return &l, nil
+}
+
+// UpdateLabel обновляет название метки.
+func (s *Storage) UpdateLabel(ctx context.Context, l storage.Label) error {
+	ctx, cancel := s.withTimeout(ctx, "UpdateLabel")
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE labels
+		SET name = $2
+		WHERE id = $1;
+	`,
+		l.ID,
+		l.Name,
+	)
+	if err != nil {
+		return wrapErr(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+// DeleteLabel удаляет метку по ID.
+// Связи метки с задачами в таблице tasks_labels удаляются каскадно
+// (ON DELETE CASCADE), сами задачи при этом не затрагиваются.
+func (s *Storage) DeleteLabel(ctx context.Context, labelID int) error {
+	ctx, cancel := s.withTimeout(ctx, "DeleteLabel")
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx, `
+		DELETE FROM labels
+		WHERE id = $1;
+	`,
+		labelID,
+	)
+	if err != nil {
+		return wrapErr(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+// AssignLabel назначает метку задаче.
+// Повторное назначение той же метки ошибкой не является.
+func (s *Storage) AssignLabel(ctx context.Context, taskID, labelID int) error {
+	ctx, cancel := s.withTimeout(ctx, "AssignLabel")
+	defer cancel()
+
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO tasks_labels (task_id, label_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING;
+	`,
+		taskID,
+		labelID,
+	)
+	return err
+}
+
+// RemoveLabel снимает метку с задачи.
+// Если метка не была назначена, ошибка не возвращается.
+func (s *Storage) RemoveLabel(ctx context.Context, taskID, labelID int) error {
+	ctx, cancel := s.withTimeout(ctx, "RemoveLabel")
+	defer cancel()
+
+	_, err := s.pool.Exec(ctx, `
+		DELETE FROM tasks_labels
+		WHERE task_id = $1 AND label_id = $2;
+	`,
+		taskID,
+		labelID,
+	)
 	return err
 }
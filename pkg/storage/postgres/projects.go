@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+)
+
+// AddProject создаёт новый проект и возвращает его id.
+func (s *Storage) AddProject(ctx context.Context, p storage.Project) (int, error) {
+	ctx, cancel := s.withTimeout(ctx, "AddProject")
+	defer cancel()
+
+	var id int
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO projects (name, description, owner_id)
+		VALUES ($1, $2, $3) RETURNING id;
+	`,
+		p.Name,
+		p.Description,
+		p.OwnerID,
+	).Scan(&id)
+	return id, wrapErr(err)
+}
+
+// Projects возвращает список проектов.
+func (s *Storage) Projects(ctx context.Context) ([]storage.Project, error) {
+	ctx, cancel := s.withTimeout(ctx, "Projects")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT id, name, description, owner_id
+		FROM projects
+		ORDER BY id;
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []storage.Project
+	for rows.Next() {
+		var p storage.Project
+		err = rows.Scan(&p.ID, &p.Name, &p.Description, &p.OwnerID)
+		if err != nil {
+			return nil, err
+		}
+
+		projects = append(projects, p)
+	}
+
+	return projects, rows.Err()
+}
+
+// ProjectByID возвращает проект по его ID.
+func (s *Storage) ProjectByID(ctx context.Context, projectID int) (*storage.Project, error) {
+	ctx, cancel := s.withTimeout(ctx, "ProjectByID")
+	defer cancel()
+
+	var p storage.Project
+	err := s.readPool.QueryRow(ctx, `
+		SELECT id, name, description, owner_id
+		FROM projects
+		WHERE id = $1;
+	`,
+		projectID,
+	).Scan(&p.ID, &p.Name, &p.Description, &p.OwnerID)
+	if err != nil {
+		return nil, wrapErr(err)
+	}
+
+	return &p, nil
+}
+
+// UpdateProject обновляет данные проекта.
+func (s *Storage) UpdateProject(ctx context.Context, p storage.Project) error {
+	ctx, cancel := s.withTimeout(ctx, "UpdateProject")
+	defer cancel()
+
+	_, err := s.pool.Exec(ctx, `
+		UPDATE projects
+		SET (name, description, owner_id) = ($2, $3, $4)
+		WHERE id = $1;
+	`,
+		p.ID,
+		p.Name,
+		p.Description,
+		p.OwnerID,
+	)
+	return wrapErr(err)
+}
+
+// DeleteProject удаляет проект по ID.
+// Если cascadeDelete равен true, задачи проекта открепляются от него
+// (project_id = NULL), иначе при наличии задач проект не удаляется
+// и возвращается storage.ErrConflict.
+func (s *Storage) DeleteProject(ctx context.Context, projectID int, cascadeDelete bool) error {
+	ctx, cancel := s.withTimeout(ctx, "DeleteProject")
+	defer cancel()
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if cascadeDelete {
+		_, err = tx.Exec(ctx, `
+			UPDATE tasks
+			SET project_id = NULL
+			WHERE project_id = $1;
+		`,
+			projectID,
+		)
+		if err != nil {
+			return err
+		}
+	} else {
+		var hasTasks bool
+		err = tx.QueryRow(ctx, `
+			SELECT EXISTS (
+				SELECT 1 FROM tasks
+				WHERE project_id = $1
+			);
+		`,
+			projectID,
+		).Scan(&hasTasks)
+		if err != nil {
+			return err
+		}
+		if hasTasks {
+			return fmt.Errorf("%w: project %d has tasks", storage.ErrConflict, projectID)
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM projects
+		WHERE id = $1;
+	`,
+		projectID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// TasksByProject возвращает слайс задач проекта.
+func (s *Storage) TasksByProject(ctx context.Context, projectID int) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "TasksByProject")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE project_id = $1 AND deleted_at IS NULL
+		ORDER BY id;
+	`,
+		projectID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
+}
@@ -0,0 +1,177 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+)
+
+// Количество секунд в сутках.
+const secondsPerDay = 24 * 60 * 60
+
+// AddRecurrenceRule создаёт правило повторения задачи.
+// У задачи может быть только одно правило.
+func (s *Storage) AddRecurrenceRule(ctx context.Context, r storage.RecurrenceRule) error {
+	ctx, cancel := s.withTimeout(ctx, "AddRecurrenceRule")
+	defer cancel()
+
+	if r.IntervalDays <= 0 {
+		return fmt.Errorf("%w: recurrence interval must be positive", storage.ErrInvalidArgument)
+	}
+
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO recurrence_rules (task_id, interval_days, next_due, last_created)
+		VALUES ($1, $2, $3, $4);
+	`,
+		r.TaskID,
+		r.IntervalDays,
+		r.NextDue,
+		r.LastCreated,
+	)
+	return wrapErr(err)
+}
+
+// RecurrenceRuleByTask возвращает правило повторения задачи.
+func (s *Storage) RecurrenceRuleByTask(ctx context.Context, taskID int) (*storage.RecurrenceRule, error) {
+	ctx, cancel := s.withTimeout(ctx, "RecurrenceRuleByTask")
+	defer cancel()
+
+	var r storage.RecurrenceRule
+	err := s.readPool.QueryRow(ctx, `
+		SELECT task_id, interval_days, next_due, last_created
+		FROM recurrence_rules
+		WHERE task_id = $1;
+	`,
+		taskID,
+	).Scan(&r.TaskID, &r.IntervalDays, &r.NextDue, &r.LastCreated)
+	if err != nil {
+		return nil, wrapErr(err)
+	}
+	return &r, nil
+}
+
+// UpdateRecurrenceRule обновляет правило повторения задачи.
+func (s *Storage) UpdateRecurrenceRule(ctx context.Context, r storage.RecurrenceRule) error {
+	ctx, cancel := s.withTimeout(ctx, "UpdateRecurrenceRule")
+	defer cancel()
+
+	if r.IntervalDays <= 0 {
+		return fmt.Errorf("%w: recurrence interval must be positive", storage.ErrInvalidArgument)
+	}
+
+	_, err := s.pool.Exec(ctx, `
+		UPDATE recurrence_rules
+		SET (interval_days, next_due, last_created) = ($2, $3, $4)
+		WHERE task_id = $1;
+	`,
+		r.TaskID,
+		r.IntervalDays,
+		r.NextDue,
+		r.LastCreated,
+	)
+	return wrapErr(err)
+}
+
+// DeleteRecurrenceRule удаляет правило повторения задачи.
+func (s *Storage) DeleteRecurrenceRule(ctx context.Context, taskID int) error {
+	ctx, cancel := s.withTimeout(ctx, "DeleteRecurrenceRule")
+	defer cancel()
+
+	_, err := s.pool.Exec(ctx, `
+		DELETE FROM recurrence_rules
+		WHERE task_id = $1;
+	`,
+		taskID,
+	)
+	return err
+}
+
+// DueRecurrences возвращает правила, по которым к моменту now
+// пора создать копию задачи, в порядке наступления срока.
+func (s *Storage) DueRecurrences(ctx context.Context, now int64) ([]storage.RecurrenceRule, error) {
+	ctx, cancel := s.withTimeout(ctx, "DueRecurrences")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT task_id, interval_days, next_due, last_created
+		FROM recurrence_rules
+		WHERE next_due <= $1
+		ORDER BY next_due, task_id;
+	`,
+		now,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []storage.RecurrenceRule
+	for rows.Next() {
+		var r storage.RecurrenceRule
+		err = rows.Scan(&r.TaskID, &r.IntervalDays, &r.NextDue, &r.LastCreated)
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, r)
+	}
+
+	return rules, rows.Err()
+}
+
+// SpawnRecurringTask создаёт копию задачи-шаблона со сроком rule.NextDue
+// и сдвигает срок следующей копии на интервал правила.
+// Обе операции выполняются в одной транзакции.
+//
+// Если правило было изменено после чтения (например, копию уже создал
+// другой процесс), возвращается storage.ErrConflict.
+func (s *Storage) SpawnRecurringTask(ctx context.Context, rule storage.RecurrenceRule) (int, error) {
+	ctx, cancel := s.withTimeout(ctx, "SpawnRecurringTask")
+	defer cancel()
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var t storage.Task
+	err = scanTask(tx.QueryRow(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE id = $1 AND deleted_at IS NULL;
+	`,
+		rule.TaskID,
+	), &t)
+	if err != nil {
+		return 0, wrapErr(err)
+	}
+
+	due := rule.NextDue
+	t.DueAt = &due
+
+	var id int
+	err = tx.QueryRow(ctx, insertTaskSQL, insertTaskArgs(t)...).Scan(&id)
+	if err != nil {
+		return 0, wrapErr(err)
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE recurrence_rules
+		SET next_due = next_due + interval_days * $3::BIGINT,
+			last_created = extract(epoch from now())
+		WHERE task_id = $1 AND next_due = $2;
+	`,
+		rule.TaskID,
+		rule.NextDue,
+		secondsPerDay,
+	)
+	if err != nil {
+		return 0, wrapErr(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return 0, fmt.Errorf("%w: recurrence rule for task %d has changed", storage.ErrConflict, rule.TaskID)
+	}
+
+	return id, tx.Commit(ctx)
+}
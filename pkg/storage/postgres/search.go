@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+	"strings"
+)
+
+// SearchTasks выполняет полнотекстовый поиск по заголовку и тексту задач
+// и возвращает не более limit задач в порядке убывания релевантности.
+func (s *Storage) SearchTasks(ctx context.Context, query string, limit int) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "SearchTasks")
+	defer cancel()
+
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("%w: search query is empty", storage.ErrInvalidArgument)
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("%w: limit must be positive", storage.ErrInvalidArgument)
+	}
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks, plainto_tsquery('english', $1) AS q
+		WHERE search_vector @@ q AND deleted_at IS NULL
+		ORDER BY ts_rank(search_vector, q) DESC, id
+		LIMIT $2;
+	`,
+		query,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
+}
+
+// similarTasksLimit - максимальное количество задач, возвращаемых SimilarTasks.
+const similarTasksLimit = 10
+
+// SimilarTasks возвращает не более 10 задач, сходство заголовка которых
+// с title (функция similarity расширения pg_trgm) больше threshold,
+// в порядке убывания сходства. Используется для предупреждения
+// о возможном дубликате перед созданием задачи.
+func (s *Storage) SimilarTasks(ctx context.Context, title string, threshold float64) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "SimilarTasks")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE similarity(title, $1) > $2 AND deleted_at IS NULL
+		ORDER BY similarity(title, $1) DESC, id
+		LIMIT $3;
+	`,
+		title,
+		threshold,
+		similarTasksLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
+}
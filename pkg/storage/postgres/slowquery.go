@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Максимальная длина текста запроса в журнале медленных запросов.
+const maxLoggedSQL = 500
+
+// slowQuerier оборачивает querier и записывает в журнал запросы,
+// которые выполнялись дольше threshold. Для Query, QueryRow и SendBatch
+// измеряется время до получения первого ответа, а не до чтения всех строк.
+// Запросы внутри транзакций, начатых через Begin, не отслеживаются.
+type slowQuerier struct {
+	querier
+	threshold time.Duration
+}
+
+// logSlow записывает запрос в журнал, если он выполнялся дольше порога.
+func (q *slowQuerier) logSlow(sql string, start time.Time) {
+	elapsed := time.Since(start)
+	if elapsed <= q.threshold {
+		return
+	}
+	if len(sql) > maxLoggedSQL {
+		sql = strings.ToValidUTF8(sql[:maxLoggedSQL], "") + "..."
+	}
+	slog.Warn("slow query", "sql", sql, "duration", elapsed)
+}
+
+func (q *slowQuerier) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	defer q.logSlow(sql, time.Now())
+	return q.querier.Exec(ctx, sql, args...)
+}
+
+func (q *slowQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	defer q.logSlow(sql, time.Now())
+	return q.querier.Query(ctx, sql, args...)
+}
+
+func (q *slowQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	defer q.logSlow(sql, time.Now())
+	return q.querier.QueryRow(ctx, sql, args...)
+}
+
+func (q *slowQuerier) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	sqls := make([]string, 0, len(b.QueuedQueries))
+	for _, qq := range b.QueuedQueries {
+		sqls = append(sqls, strings.TrimSpace(qq.SQL))
+	}
+	defer q.logSlow(strings.Join(sqls, "\n"), time.Now())
+	return q.querier.SendBatch(ctx, b)
+}
@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// sleepQuerier выполняет любой запрос за время delay.
+type sleepQuerier struct {
+	querier
+	delay time.Duration
+}
+
+func (q sleepQuerier) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	time.Sleep(q.delay)
+	return pgconn.CommandTag{}, nil
+}
+
+func (q sleepQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	time.Sleep(q.delay)
+	return nil, nil
+}
+
+func (q sleepQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	time.Sleep(q.delay)
+	return nil
+}
+
+func (q sleepQuerier) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	time.Sleep(q.delay)
+	return nil
+}
+
+// captureLog перенаправляет журнал slog по умолчанию в буфер
+// до завершения теста.
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	return &buf
+}
+
+// slowRecords возвращает записи журнала о медленных запросах.
+func slowRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var r map[string]any
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("decode log record %q: %v", line, err)
+		}
+		if r["msg"] == "slow query" {
+			records = append(records, r)
+		}
+	}
+	return records
+}
+
+func TestSlowQuerier(t *testing.T) {
+	calls := map[string]func(q querier){
+		"Exec":     func(q querier) { q.Exec(context.Background(), "SELECT 1") },
+		"Query":    func(q querier) { q.Query(context.Background(), "SELECT 1") },
+		"QueryRow": func(q querier) { q.QueryRow(context.Background(), "SELECT 1") },
+		"SendBatch": func(q querier) {
+			b := pgx.Batch{}
+			b.Queue(" SELECT 1 ")
+			b.Queue("SELECT 2")
+			q.SendBatch(context.Background(), &b)
+		},
+	}
+	wantSQL := map[string]string{"SendBatch": "SELECT 1\nSELECT 2"}
+
+	for method, call := range calls {
+		t.Run(method, func(t *testing.T) {
+			buf := captureLog(t)
+
+			call(&slowQuerier{querier: sleepQuerier{}, threshold: time.Second})
+			if records := slowRecords(t, buf); len(records) != 0 {
+				t.Fatalf("fast %s logged %v, want nothing", method, records)
+			}
+
+			call(&slowQuerier{querier: sleepQuerier{delay: 20 * time.Millisecond}, threshold: 10 * time.Millisecond})
+			records := slowRecords(t, buf)
+			if len(records) != 1 {
+				t.Fatalf("slow %s logged %d records, want 1", method, len(records))
+			}
+			want, ok := wantSQL[method]
+			if !ok {
+				want = "SELECT 1"
+			}
+			if records[0]["sql"] != want {
+				t.Errorf("logged sql = %q, want %q", records[0]["sql"], want)
+			}
+			if d, _ := records[0]["duration"].(float64); time.Duration(d) < 20*time.Millisecond {
+				t.Errorf("logged duration = %v, want at least 20ms", records[0]["duration"])
+			}
+		})
+	}
+}
+
+// Длинный текст запроса обрезается до maxLoggedSQL байт
+// без разрыва многобайтовых символов.
+func TestSlowQuerierTruncate(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{"short", "SELECT 1", "SELECT 1"},
+		{"ascii", strings.Repeat("x", 600), strings.Repeat("x", maxLoggedSQL) + "..."},
+		{"multibyte", "x" + strings.Repeat("я", 300), "x" + strings.Repeat("я", (maxLoggedSQL-1)/2) + "..."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := captureLog(t)
+			q := &slowQuerier{querier: sleepQuerier{delay: 2 * time.Millisecond}, threshold: time.Millisecond}
+			q.Exec(context.Background(), tt.sql)
+
+			records := slowRecords(t, buf)
+			if len(records) != 1 {
+				t.Fatalf("logged %d records, want 1", len(records))
+			}
+			got, _ := records[0]["sql"].(string)
+			if got != tt.want || !utf8.ValidString(got) {
+				t.Errorf("logged sql = %q (%d bytes), want %d bytes", got, len(got), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestWithSlowQueryThreshold(t *testing.T) {
+	s, err := New(testConnString, WithSlowQueryThreshold(time.Second))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer s.Close()
+
+	for name, q := range map[string]querier{"pool": s.pool, "readPool": s.readPool} {
+		if sq, ok := q.(*slowQuerier); !ok || sq.threshold != time.Second {
+			t.Errorf("%s = %T, want slowQuerier with threshold 1s", name, q)
+		}
+	}
+
+	s, err = New(testConnString)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer s.Close()
+	if _, ok := s.pool.(*slowQuerier); ok {
+		t.Error("pool is slowQuerier without WithSlowQueryThreshold")
+	}
+}
+
+func TestSlowQueryLog(t *testing.T) {
+	s := newTestStorage(t)
+	s.pool = &slowQuerier{querier: s.pool, threshold: 10 * time.Millisecond}
+	buf := captureLog(t)
+
+	if _, err := s.pool.Exec(context.Background(), "SELECT pg_sleep(0.05)"); err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+	records := slowRecords(t, buf)
+	if len(records) != 1 || records[0]["sql"] != "SELECT pg_sleep(0.05)" {
+		t.Errorf("slow query records = %v, want pg_sleep query", records)
+	}
+}
@@ -0,0 +1,244 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+)
+
+// AddSprint создаёт новый спринт и возвращает его id.
+func (s *Storage) AddSprint(ctx context.Context, sp storage.Sprint) (int, error) {
+	ctx, cancel := s.withTimeout(ctx, "AddSprint")
+	defer cancel()
+
+	if sp.EndAt < sp.StartAt {
+		return 0, fmt.Errorf("%w: sprint ends before it starts", storage.ErrInvalidArgument)
+	}
+
+	var id int
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO sprints (project_id, name, start_at, end_at)
+		VALUES ($1, $2, $3, $4) RETURNING id;
+	`,
+		sp.ProjectID,
+		sp.Name,
+		sp.StartAt,
+		sp.EndAt,
+	).Scan(&id)
+	return id, wrapErr(err)
+}
+
+// SprintByID возвращает спринт по его ID.
+func (s *Storage) SprintByID(ctx context.Context, sprintID int) (*storage.Sprint, error) {
+	ctx, cancel := s.withTimeout(ctx, "SprintByID")
+	defer cancel()
+
+	var sp storage.Sprint
+	err := s.readPool.QueryRow(ctx, `
+		SELECT id, project_id, name, start_at, end_at
+		FROM sprints
+		WHERE id = $1;
+	`,
+		sprintID,
+	).Scan(&sp.ID, &sp.ProjectID, &sp.Name, &sp.StartAt, &sp.EndAt)
+	if err != nil {
+		return nil, wrapErr(err)
+	}
+
+	return &sp, nil
+}
+
+// Sprints возвращает список спринтов.
+func (s *Storage) Sprints(ctx context.Context) ([]storage.Sprint, error) {
+	ctx, cancel := s.withTimeout(ctx, "Sprints")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT id, project_id, name, start_at, end_at
+		FROM sprints
+		ORDER BY id;
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sprints []storage.Sprint
+	for rows.Next() {
+		var sp storage.Sprint
+		err = rows.Scan(&sp.ID, &sp.ProjectID, &sp.Name, &sp.StartAt, &sp.EndAt)
+		if err != nil {
+			return nil, err
+		}
+
+		sprints = append(sprints, sp)
+	}
+
+	return sprints, rows.Err()
+}
+
+// UpdateSprint обновляет данные спринта.
+func (s *Storage) UpdateSprint(ctx context.Context, sp storage.Sprint) error {
+	ctx, cancel := s.withTimeout(ctx, "UpdateSprint")
+	defer cancel()
+
+	if sp.EndAt < sp.StartAt {
+		return fmt.Errorf("%w: sprint ends before it starts", storage.ErrInvalidArgument)
+	}
+
+	_, err := s.pool.Exec(ctx, `
+		UPDATE sprints
+		SET (project_id, name, start_at, end_at) = ($2, $3, $4, $5)
+		WHERE id = $1;
+	`,
+		sp.ID,
+		sp.ProjectID,
+		sp.Name,
+		sp.StartAt,
+		sp.EndAt,
+	)
+	return wrapErr(err)
+}
+
+// DeleteSprint удаляет спринт по ID. Задачи спринта не удаляются.
+func (s *Storage) DeleteSprint(ctx context.Context, sprintID int) error {
+	ctx, cancel := s.withTimeout(ctx, "DeleteSprint")
+	defer cancel()
+
+	_, err := s.pool.Exec(ctx, `
+		DELETE FROM sprints
+		WHERE id = $1;
+	`,
+		sprintID,
+	)
+	return err
+}
+
+// AssignTaskToSprint включает задачу в спринт.
+// Если задача уже входит в спринт, ошибка не возвращается.
+func (s *Storage) AssignTaskToSprint(ctx context.Context, taskID, sprintID int) error {
+	ctx, cancel := s.withTimeout(ctx, "AssignTaskToSprint")
+	defer cancel()
+
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO task_sprints (task_id, sprint_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING;
+	`,
+		taskID,
+		sprintID,
+	)
+	return wrapErr(err)
+}
+
+// RemoveTaskFromSprint исключает задачу из спринта.
+// Если задача не входила в спринт, ошибка не возвращается.
+func (s *Storage) RemoveTaskFromSprint(ctx context.Context, taskID, sprintID int) error {
+	ctx, cancel := s.withTimeout(ctx, "RemoveTaskFromSprint")
+	defer cancel()
+
+	_, err := s.pool.Exec(ctx, `
+		DELETE FROM task_sprints
+		WHERE task_id = $1 AND sprint_id = $2;
+	`,
+		taskID,
+		sprintID,
+	)
+	return err
+}
+
+// TasksBySprint возвращает слайс задач спринта.
+func (s *Storage) TasksBySprint(ctx context.Context, sprintID int) ([]storage.Task, error) {
+	ctx, cancel := s.withTimeout(ctx, "TasksBySprint")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE id IN (
+			SELECT task_id FROM task_sprints
+			WHERE sprint_id = $1
+		) AND deleted_at IS NULL
+		ORDER BY id;
+	`,
+		sprintID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectTasks(rows)
+}
+
+// SprintVelocity возвращает количество закрытых задач спринта.
+func (s *Storage) SprintVelocity(ctx context.Context, sprintID int) (int, error) {
+	ctx, cancel := s.withTimeout(ctx, "SprintVelocity")
+	defer cancel()
+
+	var n int
+	err := s.readPool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM tasks
+		JOIN task_sprints ON task_sprints.task_id = tasks.id
+		WHERE task_sprints.sprint_id = $1 AND tasks.closed <> 0 AND tasks.deleted_at IS NULL;
+	`,
+		sprintID,
+	).Scan(&n)
+	return n, err
+}
+
+// BurndownData делит время спринта на buckets равных интервалов и для конца
+// каждого интервала возвращает количество задач спринта, которые к этому
+// моменту были открыты, но ещё не закрыты.
+func (s *Storage) BurndownData(ctx context.Context, sprintID int, buckets int) ([]storage.BurndownPoint, error) {
+	ctx, cancel := s.withTimeout(ctx, "BurndownData")
+	defer cancel()
+
+	if buckets <= 0 {
+		return nil, fmt.Errorf("%w: buckets must be positive", storage.ErrInvalidArgument)
+	}
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT points.at, (
+			SELECT COUNT(*)
+			FROM tasks
+			JOIN task_sprints ON task_sprints.task_id = tasks.id
+			WHERE task_sprints.sprint_id = $1
+				AND tasks.deleted_at IS NULL
+				AND tasks.opened <= points.at
+				AND (tasks.closed = 0 OR tasks.closed > points.at)
+		)
+		FROM (
+			SELECT sprints.start_at + (sprints.end_at - sprints.start_at) * g.i / $2 AS at
+			FROM sprints, generate_series(1, $2::INTEGER) AS g(i)
+			WHERE sprints.id = $1
+		) AS points
+		ORDER BY points.at;
+	`,
+		sprintID,
+		buckets,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []storage.BurndownPoint
+	for rows.Next() {
+		var p storage.BurndownPoint
+		err = rows.Scan(&p.At, &p.Remaining)
+		if err != nil {
+			return nil, err
+		}
+
+		points = append(points, p)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// без спринта подзапрос не возвращает ни одной точки
+	if len(points) == 0 {
+		return nil, storage.ErrNotFound
+	}
+	return points, nil
+}
@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// userWorkloadSQL выбирает пользователей с количеством назначенных
+// им задач. Удалённые задачи не учитываются. Условие отбора
+// пользователей подставляется вместо %s.
+const userWorkloadSQL = `
+		SELECT
+			u.id,
+			u.name,
+			COUNT(CASE WHEN t.closed = 0 THEN 1 END),
+			COUNT(t.id)
+		FROM users u
+		LEFT JOIN tasks t ON t.assigned_id = u.id AND t.deleted_at IS NULL
+		%s
+		GROUP BY u.id, u.name
+		ORDER BY u.id;
+	`
+
+// UserWorkloads возвращает загрузку всех пользователей.
+func (s *Storage) UserWorkloads(ctx context.Context) ([]storage.UserWorkload, error) {
+	ctx, cancel := s.withTimeout(ctx, "UserWorkloads")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, fmt.Sprintf(userWorkloadSQL, ""))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var workloads []storage.UserWorkload
+	for rows.Next() {
+		var w storage.UserWorkload
+		err = rows.Scan(&w.ID, &w.Name, &w.OpenTaskCount, &w.TotalTaskCount)
+		if err != nil {
+			return nil, err
+		}
+
+		workloads = append(workloads, w)
+	}
+
+	return workloads, rows.Err()
+}
+
+// UserWorkload возвращает загрузку пользователя.
+func (s *Storage) UserWorkload(ctx context.Context, userID int) (*storage.UserWorkload, error) {
+	ctx, cancel := s.withTimeout(ctx, "UserWorkload")
+	defer cancel()
+
+	var w storage.UserWorkload
+	err := s.readPool.QueryRow(ctx, fmt.Sprintf(userWorkloadSQL, "WHERE u.id = $1"),
+		userID,
+	).Scan(&w.ID, &w.Name, &w.OpenTaskCount, &w.TotalTaskCount)
+	if err != nil {
+		return nil, wrapErr(err)
+	}
+
+	return &w, nil
+}
+
+// TaskStats возвращает сводную статистику по задачам.
+// Все значения вычисляются одним запросом.
+func (s *Storage) TaskStats(ctx context.Context) (*storage.TaskStats, error) {
+	ctx, cancel := s.withTimeout(ctx, "TaskStats")
+	defer cancel()
+
+	var st storage.TaskStats
+	err := s.readPool.QueryRow(ctx, `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE closed = 0),
+			COUNT(*) FILTER (WHERE closed <> 0),
+			COUNT(*) FILTER (WHERE due_at IS NOT NULL AND due_at < extract(epoch from now()) AND closed = 0),
+			COALESCE(AVG(extract(epoch from now()) - opened) FILTER (WHERE closed = 0), 0)::FLOAT8
+		FROM tasks
+		WHERE deleted_at IS NULL;
+	`).Scan(&st.Total, &st.Open, &st.Closed, &st.Overdue, &st.AvgAgeSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	return &st, nil
+}
+
+// labelStatsSQL выбирает метки с количеством неудалённых задач
+// в порядке убывания количества. Ограничение выборки
+// подставляется вместо %s.
+const labelStatsSQL = `
+		SELECT l.id, l.name, COUNT(t.id)
+		FROM labels l
+		LEFT JOIN tasks_labels tl ON tl.label_id = l.id
+		LEFT JOIN tasks t ON t.id = tl.task_id AND t.deleted_at IS NULL
+		GROUP BY l.id, l.name
+		ORDER BY COUNT(t.id) DESC, l.id
+		%s;
+	`
+
+// LabelStats возвращает все метки с количеством задач
+// в порядке убывания количества.
+func (s *Storage) LabelStats(ctx context.Context) ([]storage.LabelStat, error) {
+	ctx, cancel := s.withTimeout(ctx, "LabelStats")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, fmt.Sprintf(labelStatsSQL, ""))
+	if err != nil {
+		return nil, err
+	}
+	return collectLabelStats(rows)
+}
+
+// TopLabels возвращает n наиболее используемых меток.
+func (s *Storage) TopLabels(ctx context.Context, n int) ([]storage.LabelStat, error) {
+	ctx, cancel := s.withTimeout(ctx, "TopLabels")
+	defer cancel()
+
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: n must be positive", storage.ErrInvalidArgument)
+	}
+
+	rows, err := s.readPool.Query(ctx, fmt.Sprintf(labelStatsSQL, "LIMIT $1"),
+		n,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectLabelStats(rows)
+}
+
+// collectLabelStats вычитывает строки результата labelStatsSQL.
+func collectLabelStats(rows pgx.Rows) ([]storage.LabelStat, error) {
+	defer rows.Close()
+
+	var stats []storage.LabelStat
+	for rows.Next() {
+		var st storage.LabelStat
+		err := rows.Scan(&st.ID, &st.Name, &st.TaskCount)
+		if err != nil {
+			return nil, err
+		}
+
+		stats = append(stats, st)
+	}
+
+	return stats, rows.Err()
+}
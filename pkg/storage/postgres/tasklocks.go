@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// taskLocks хранит транзакции, в которых заблокированы строки задач.
+type taskLocks struct {
+	mu  sync.Mutex
+	txs map[int]pgx.Tx // ID задачи -> транзакция
+}
+
+func newTaskLocks() *taskLocks {
+	l := taskLocks{
+		txs: make(map[int]pgx.Tx),
+	}
+	return &l
+}
+
+// LockTask блокирует строку задачи (SELECT ... FOR UPDATE NOWAIT)
+// в отдельной транзакции, которая удерживает соединение пула
+// до UnlockTask. Пока задача заблокирована, её изменение другими
+// соединениями ожидает снятия блокировки.
+//
+// Возвращает storage.ErrLocked, если задача уже заблокирована,
+// и storage.ErrNotFound, если задачи нет.
+func (s *Storage) LockTask(ctx context.Context, taskID int) error {
+	ctx, cancel := s.withTimeout(ctx, "LockTask")
+	defer cancel()
+
+	s.taskLocks.mu.Lock()
+	defer s.taskLocks.mu.Unlock()
+
+	if _, ok := s.taskLocks.txs[taskID]; ok {
+		return storage.ErrLocked
+	}
+
+	tx, err := s.primary.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	var id int
+	err = tx.QueryRow(ctx, `
+		SELECT id
+		FROM tasks
+		WHERE id = $1
+		FOR UPDATE NOWAIT;
+	`,
+		taskID,
+	).Scan(&id)
+	if err != nil {
+		tx.Rollback(context.Background())
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == lockNotAvailable {
+			return fmt.Errorf("%w: %w", storage.ErrLocked, err)
+		}
+		return wrapErr(err)
+	}
+	s.taskLocks.txs[taskID] = tx
+	return nil
+}
+
+// UnlockTask снимает блокировку, установленную LockTask,
+// завершая удерживающую её транзакцию.
+func (s *Storage) UnlockTask(ctx context.Context, taskID int) error {
+	ctx, cancel := s.withTimeout(ctx, "UnlockTask")
+	defer cancel()
+
+	s.taskLocks.mu.Lock()
+	tx, ok := s.taskLocks.txs[taskID]
+	delete(s.taskLocks.txs, taskID)
+	s.taskLocks.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%w: task %d is not locked", storage.ErrNotFound, taskID)
+	}
+	return wrapErr(tx.Commit(ctx))
+}
@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"testing"
+)
+
+// Блокировка видна другим экземплярам хранилища: строка
+// заблокирована в БД, а не только в памяти процесса.
+func TestLockTaskAcrossStorages(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+	other, err := New(s.primary.Config().ConnString())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer other.Close()
+
+	id, err := s.AddTask(ctx, storage.Task{Title: "task"})
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	err = s.LockTask(ctx, id)
+	if err != nil {
+		t.Fatalf("LockTask() error = %v", err)
+	}
+
+	err = other.LockTask(ctx, id)
+	if !errors.Is(err, storage.ErrLocked) {
+		t.Errorf("LockTask() from another storage error = %v, want ErrLocked", err)
+	}
+
+	err = s.UnlockTask(ctx, id)
+	if err != nil {
+		t.Fatalf("UnlockTask() error = %v", err)
+	}
+	err = other.LockTask(ctx, id)
+	if err != nil {
+		t.Fatalf("LockTask() after unlock error = %v", err)
+	}
+	err = other.UnlockTask(ctx, id)
+	if err != nil {
+		t.Fatalf("UnlockTask() error = %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// LogTime сохраняет запись о затраченном времени и возвращает её id.
+// Записи удаляются вместе с задачей (ON DELETE CASCADE).
+func (s *Storage) LogTime(ctx context.Context, e storage.TimeEntry) (int, error) {
+	ctx, cancel := s.withTimeout(ctx, "LogTime")
+	defer cancel()
+
+	var id int
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO time_entries (task_id, user_id, minutes, note)
+		VALUES ($1, $2, $3, $4) RETURNING id;
+	`,
+		e.TaskID,
+		e.UserID,
+		e.Minutes,
+		e.Note,
+	).Scan(&id)
+	return id, wrapErr(err)
+}
+
+// collectTimeEntries вычитывает все строки результата запроса
+// в слайс записей о затраченном времени.
+func collectTimeEntries(rows pgx.Rows) ([]storage.TimeEntry, error) {
+	defer rows.Close()
+
+	var entries []storage.TimeEntry
+	for rows.Next() {
+		var e storage.TimeEntry
+		err := rows.Scan(&e.ID, &e.TaskID, &e.UserID, &e.Minutes, &e.Note, &e.LoggedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// TimeEntriesByTask возвращает записи о затраченном времени по задаче.
+func (s *Storage) TimeEntriesByTask(ctx context.Context, taskID int) ([]storage.TimeEntry, error) {
+	ctx, cancel := s.withTimeout(ctx, "TimeEntriesByTask")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT id, task_id, user_id, minutes, note, logged_at
+		FROM time_entries
+		WHERE task_id = $1
+		ORDER BY id;
+	`,
+		taskID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectTimeEntries(rows)
+}
+
+// TimeEntriesByUser возвращает записи о затраченном времени пользователя.
+func (s *Storage) TimeEntriesByUser(ctx context.Context, userID int) ([]storage.TimeEntry, error) {
+	ctx, cancel := s.withTimeout(ctx, "TimeEntriesByUser")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT id, task_id, user_id, minutes, note, logged_at
+		FROM time_entries
+		WHERE user_id = $1
+		ORDER BY id;
+	`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectTimeEntries(rows)
+}
+
+// TotalMinutesByTask возвращает суммарное время в минутах,
+// затраченное на задачу.
+func (s *Storage) TotalMinutesByTask(ctx context.Context, taskID int) (int, error) {
+	ctx, cancel := s.withTimeout(ctx, "TotalMinutesByTask")
+	defer cancel()
+
+	var n int
+	err := s.readPool.QueryRow(ctx, `
+		SELECT COALESCE(SUM(minutes), 0)
+		FROM time_entries
+		WHERE task_id = $1;
+	`,
+		taskID,
+	).Scan(&n)
+	return n, err
+}
@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Tx - хранилище, выполняющее все запросы в транзакции.
+// Как и pgx.Tx, не предназначено для параллельного использования.
+//
+// Методы, которые сами открывают транзакцию (например, AddTasks),
+// внутри Tx создают точку сохранения.
+type Tx struct {
+	*Storage
+	conn *txConn
+}
+
+// BeginTx начинает транзакцию. Вызов BeginTx у Tx
+// создаёт вложенную транзакцию на основе точки сохранения.
+func (s *Storage) BeginTx(ctx context.Context) (storage.TxStorage, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, wrapErr(err)
+	}
+	conn := txConn{tx: tx}
+	t := Tx{
+		Storage: &Storage{
+			pool:      &conn,
+			readPool:  &conn,
+			primary:   s.primary,
+			replica:   s.replica,
+			locks:     s.locks,
+			taskLocks: s.taskLocks,
+
+			timeouts:       s.timeouts,
+			defaultTimeout: s.defaultTimeout,
+		},
+		conn: &conn,
+	}
+	return &t, nil
+}
+
+// Commit применяет изменения транзакции.
+func (t *Tx) Commit(ctx context.Context) error {
+	if t.conn.closed {
+		return storage.ErrTxClosed
+	}
+	t.conn.closed = true
+	return wrapErr(t.conn.tx.Commit(ctx))
+}
+
+// Rollback отменяет изменения транзакции.
+func (t *Tx) Rollback(ctx context.Context) error {
+	if t.conn.closed {
+		return storage.ErrTxClosed
+	}
+	t.conn.closed = true
+	return wrapErr(t.conn.tx.Rollback(ctx))
+}
+
+// Savepoint создаёт точку сохранения с указанным именем.
+func (t *Tx) Savepoint(ctx context.Context, name string) error {
+	_, err := t.conn.Exec(ctx, "SAVEPOINT "+pgx.Identifier{name}.Sanitize())
+	return wrapErr(err)
+}
+
+// RollbackToSavepoint отменяет изменения, сделанные после
+// создания точки сохранения. Сама точка сохранения остаётся.
+func (t *Tx) RollbackToSavepoint(ctx context.Context, name string) error {
+	_, err := t.conn.Exec(ctx, "ROLLBACK TO SAVEPOINT "+pgx.Identifier{name}.Sanitize())
+	return wrapErr(err)
+}
+
+// ReleaseSavepoint удаляет точку сохранения, сохраняя изменения.
+func (t *Tx) ReleaseSavepoint(ctx context.Context, name string) error {
+	_, err := t.conn.Exec(ctx, "RELEASE SAVEPOINT "+pgx.Identifier{name}.Sanitize())
+	return wrapErr(err)
+}
+
+// Close ничего не делает: пул соединений принадлежит
+// хранилищу, из которого начата транзакция.
+func (t *Tx) Close() {}
+
+// txConn выполняет запросы в транзакции и после её завершения
+// возвращает storage.ErrTxClosed, не обращаясь к pgx.Tx.
+type txConn struct {
+	tx     pgx.Tx
+	closed bool
+}
+
+func (c *txConn) Begin(ctx context.Context) (pgx.Tx, error) {
+	if c.closed {
+		return nil, storage.ErrTxClosed
+	}
+	return c.tx.Begin(ctx)
+}
+
+func (c *txConn) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	if c.closed {
+		return pgconn.CommandTag{}, storage.ErrTxClosed
+	}
+	return c.tx.Exec(ctx, sql, args...)
+}
+
+func (c *txConn) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if c.closed {
+		return nil, storage.ErrTxClosed
+	}
+	return c.tx.Query(ctx, sql, args...)
+}
+
+func (c *txConn) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	if c.closed {
+		return errRow{storage.ErrTxClosed}
+	}
+	return c.tx.QueryRow(ctx, sql, args...)
+}
+
+func (c *txConn) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	if c.closed {
+		return errBatchResults{storage.ErrTxClosed}
+	}
+	return c.tx.SendBatch(ctx, b)
+}
+
+func (c *txConn) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	if c.closed {
+		return 0, storage.ErrTxClosed
+	}
+	return c.tx.CopyFrom(ctx, tableName, columnNames, rowSrc)
+}
+
+// errRow - строка результата, сканирование которой возвращает ошибку.
+type errRow struct{ err error }
+
+func (r errRow) Scan(...any) error { return r.err }
+
+// errBatchResults - результаты партии, каждый из которых возвращает ошибку.
+type errBatchResults struct{ err error }
+
+func (r errBatchResults) Exec() (pgconn.CommandTag, error) { return pgconn.CommandTag{}, r.err }
+func (r errBatchResults) Query() (pgx.Rows, error)         { return nil, r.err }
+func (r errBatchResults) QueryRow() pgx.Row                { return errRow{r.err} }
+func (r errBatchResults) Close() error                     { return r.err }
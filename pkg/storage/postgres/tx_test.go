@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"testing"
+)
+
+// Изменения транзакции не видны другим соединениям до Commit,
+// а после завершения транзакции её методы возвращают storage.ErrTxClosed.
+func TestTxIsolation(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+
+	tx, err := s.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx() error = %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	id, err := tx.AddTask(ctx, storage.Task{Title: "task"})
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	_, err = s.TaskById(ctx, id)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("TaskById() before Commit error = %v, want ErrNotFound", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	_, err = s.TaskById(ctx, id)
+	if err != nil {
+		t.Errorf("TaskById() after Commit error = %v", err)
+	}
+
+	_, err = tx.TaskById(ctx, id)
+	if !errors.Is(err, storage.ErrTxClosed) {
+		t.Errorf("tx.TaskById() after Commit error = %v, want ErrTxClosed", err)
+	}
+	_, err = tx.AddTask(ctx, storage.Task{Title: "late"})
+	if !errors.Is(err, storage.ErrTxClosed) {
+		t.Errorf("tx.AddTask() after Commit error = %v, want ErrTxClosed", err)
+	}
+}
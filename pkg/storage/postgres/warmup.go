@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Warmup заранее открывает targetConns соединений пула основного сервера,
+// чтобы первые запросы не ждали подключения к БД. Соединения захватываются
+// одновременно, проверяются и возвращаются в пул только после того,
+// как захвачены все, иначе пул повторно выдал бы уже открытые.
+// Возвращает первую ошибку, ожидание ограничено ctx.
+func (s *Storage) Warmup(ctx context.Context, targetConns int) error {
+	if targetConns <= 0 || targetConns > int(s.primary.Config().MaxConns) {
+		return fmt.Errorf("%w: targetConns must be in 1..MaxConns", storage.ErrInvalidArgument)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		conns    []*pgxpool.Conn
+		firstErr error
+	)
+	for range targetConns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			conn, err := s.primary.Acquire(ctx)
+			if err == nil {
+				err = conn.Ping(ctx)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if conn != nil {
+				conns = append(conns, conn)
+			}
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, conn := range conns {
+		conn.Release()
+	}
+	return firstErr
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+)
+
+// WatchTask подписывает пользователя на изменения задачи.
+// Если пользователь уже наблюдает за задачей, ошибка не возвращается.
+func (s *Storage) WatchTask(ctx context.Context, userID, taskID int) error {
+	ctx, cancel := s.withTimeout(ctx, "WatchTask")
+	defer cancel()
+
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO task_watchers (user_id, task_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING;
+	`,
+		userID,
+		taskID,
+	)
+	return err
+}
+
+// UnwatchTask отменяет подписку пользователя на изменения задачи.
+// Если пользователь не наблюдает за задачей, ошибка не возвращается.
+func (s *Storage) UnwatchTask(ctx context.Context, userID, taskID int) error {
+	ctx, cancel := s.withTimeout(ctx, "UnwatchTask")
+	defer cancel()
+
+	_, err := s.pool.Exec(ctx, `
+		DELETE FROM task_watchers
+		WHERE user_id = $1 AND task_id = $2;
+	`,
+		userID,
+		taskID,
+	)
+	return err
+}
+
+// WatchersOfTask возвращает наблюдателей задачи.
+func (s *Storage) WatchersOfTask(ctx context.Context, taskID int) ([]storage.User, error) {
+	ctx, cancel := s.withTimeout(ctx, "WatchersOfTask")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT u.id, u.name, u.role
+		FROM users u
+		JOIN task_watchers w ON w.user_id = u.id
+		WHERE w.task_id = $1
+		ORDER BY u.id;
+	`,
+		taskID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []storage.User
+	for rows.Next() {
+		var u storage.User
+		err = rows.Scan(&u.ID, &u.Name, &u.UserRole)
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, u)
+	}
+
+	return users, rows.Err()
+}
@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+)
+
+// RegisterWebhook сохраняет веб-хук и возвращает его ID.
+func (s *Storage) RegisterWebhook(ctx context.Context, w storage.Webhook) (int, error) {
+	ctx, cancel := s.withTimeout(ctx, "RegisterWebhook")
+	defer cancel()
+
+	err := w.Validate()
+	if err != nil {
+		return 0, err
+	}
+
+	var id int
+	err = s.pool.QueryRow(ctx, `
+		INSERT INTO webhooks (url, secret, events, active)
+		VALUES ($1, $2, $3, $4) RETURNING id;
+	`,
+		w.URL,
+		w.Secret,
+		eventsArg(w.Events),
+		w.Active,
+	).Scan(&id)
+	return id, wrapErr(err)
+}
+
+// Webhooks возвращает все веб-хуки.
+func (s *Storage) Webhooks(ctx context.Context) ([]storage.Webhook, error) {
+	ctx, cancel := s.withTimeout(ctx, "Webhooks")
+	defer cancel()
+
+	rows, err := s.readPool.Query(ctx, `
+		SELECT id, url, secret, events, active
+		FROM webhooks
+		ORDER BY id;
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []storage.Webhook
+	for rows.Next() {
+		var w storage.Webhook
+		err = rows.Scan(&w.ID, &w.URL, &w.Secret, &w.Events, &w.Active)
+		if err != nil {
+			return nil, err
+		}
+
+		webhooks = append(webhooks, w)
+	}
+
+	return webhooks, rows.Err()
+}
+
+// WebhookByID возвращает веб-хук по ID.
+func (s *Storage) WebhookByID(ctx context.Context, webhookID int) (*storage.Webhook, error) {
+	ctx, cancel := s.withTimeout(ctx, "WebhookByID")
+	defer cancel()
+
+	var w storage.Webhook
+	err := s.readPool.QueryRow(ctx, `
+		SELECT id, url, secret, events, active
+		FROM webhooks
+		WHERE id = $1;
+	`,
+		webhookID,
+	).Scan(&w.ID, &w.URL, &w.Secret, &w.Events, &w.Active)
+	if err != nil {
+		return nil, wrapErr(err)
+	}
+	return &w, nil
+}
+
+// UpdateWebhook изменяет веб-хук.
+func (s *Storage) UpdateWebhook(ctx context.Context, w storage.Webhook) error {
+	ctx, cancel := s.withTimeout(ctx, "UpdateWebhook")
+	defer cancel()
+
+	err := w.Validate()
+	if err != nil {
+		return err
+	}
+
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE webhooks
+		SET (url, secret, events, active) = ($2, $3, $4, $5)
+		WHERE id = $1;
+	`,
+		w.ID,
+		w.URL,
+		w.Secret,
+		eventsArg(w.Events),
+		w.Active,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+// DeleteWebhook удаляет веб-хук.
+func (s *Storage) DeleteWebhook(ctx context.Context, webhookID int) error {
+	ctx, cancel := s.withTimeout(ctx, "DeleteWebhook")
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx, `
+		DELETE FROM webhooks
+		WHERE id = $1;
+	`,
+		webhookID,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+// eventsArg возвращает значение столбца events:
+// пустой массив вместо nil, так как столбец объявлен NOT NULL.
+func eventsArg(events []string) []string {
+	if events == nil {
+		return []string{}
+	}
+	return events
+}
@@ -0,0 +1,1235 @@
+package prommw
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Middleware оборачивает storage.Interface и собирает метрики Prometheus:
+// гистограмму длительности запросов storage_query_duration_seconds
+// и счётчик ошибок storage_query_errors_total с меткой method.
+type Middleware struct {
+	inner    storage.Interface
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+}
+
+// Конструктор, принимает оборачиваемое хранилище и реестр метрик.
+// Возвращает ошибку, если метрики не удалось зарегистрировать.
+func New(inner storage.Interface, reg prometheus.Registerer) (*Middleware, error) {
+	m := Middleware{
+		inner: inner,
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "storage_query_duration_seconds",
+			Help:    "Длительность вызовов хранилища в секундах.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "storage_query_errors_total",
+			Help: "Количество вызовов хранилища, завершившихся ошибкой.",
+		}, []string{"method"}),
+	}
+	if err := reg.Register(m.duration); err != nil {
+		return nil, err
+	}
+	if err := reg.Register(m.errors); err != nil {
+		reg.Unregister(m.duration)
+		return nil, err
+	}
+	return &m, nil
+}
+
+// observe записывает длительность вызова метода и учитывает ошибку.
+func (m *Middleware) observe(method string, start time.Time, err error) {
+	m.duration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		m.errors.WithLabelValues(method).Inc()
+	}
+}
+
+// Tasks измеряет вызов Tasks.
+func (m *Middleware) Tasks(ctx context.Context) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.Tasks(ctx)
+	m.observe("Tasks", start, err)
+	return res, err
+}
+
+// TasksIter измеряет вызов TasksIter.
+func (m *Middleware) TasksIter(ctx context.Context, fn func(t storage.Task) error) error {
+	start := time.Now()
+	err := m.inner.TasksIter(ctx, fn)
+	m.observe("TasksIter", start, err)
+	return err
+}
+
+// TasksList измеряет вызов TasksList.
+func (m *Middleware) TasksList(ctx context.Context, opts storage.ListOptions) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksList(ctx, opts)
+	m.observe("TasksList", start, err)
+	return res, err
+}
+
+// TotalCount измеряет вызов TotalCount.
+func (m *Middleware) TotalCount(ctx context.Context) (int, error) {
+	start := time.Now()
+	res, err := m.inner.TotalCount(ctx)
+	m.observe("TotalCount", start, err)
+	return res, err
+}
+
+// TasksAfter измеряет вызов TasksAfter.
+func (m *Middleware) TasksAfter(ctx context.Context, afterID int, limit int) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksAfter(ctx, afterID, limit)
+	m.observe("TasksAfter", start, err)
+	return res, err
+}
+
+// TaskCount измеряет вызов TaskCount.
+func (m *Middleware) TaskCount(ctx context.Context) (int, error) {
+	start := time.Now()
+	res, err := m.inner.TaskCount(ctx)
+	m.observe("TaskCount", start, err)
+	return res, err
+}
+
+// TaskCountByAuthor измеряет вызов TaskCountByAuthor.
+func (m *Middleware) TaskCountByAuthor(ctx context.Context, authorID int) (int, error) {
+	start := time.Now()
+	res, err := m.inner.TaskCountByAuthor(ctx, authorID)
+	m.observe("TaskCountByAuthor", start, err)
+	return res, err
+}
+
+// TaskCountByLabel измеряет вызов TaskCountByLabel.
+func (m *Middleware) TaskCountByLabel(ctx context.Context, labelID int) (int, error) {
+	start := time.Now()
+	res, err := m.inner.TaskCountByLabel(ctx, labelID)
+	m.observe("TaskCountByLabel", start, err)
+	return res, err
+}
+
+// TaskById измеряет вызов TaskById.
+func (m *Middleware) TaskById(ctx context.Context, taskId int) (*storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TaskById(ctx, taskId)
+	m.observe("TaskById", start, err)
+	return res, err
+}
+
+// TasksByAuthor измеряет вызов TasksByAuthor.
+func (m *Middleware) TasksByAuthor(ctx context.Context, authorId int) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksByAuthor(ctx, authorId)
+	m.observe("TasksByAuthor", start, err)
+	return res, err
+}
+
+// TasksByAssignee измеряет вызов TasksByAssignee.
+func (m *Middleware) TasksByAssignee(ctx context.Context, assigneeID int) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksByAssignee(ctx, assigneeID)
+	m.observe("TasksByAssignee", start, err)
+	return res, err
+}
+
+// TasksByLabel измеряет вызов TasksByLabel.
+func (m *Middleware) TasksByLabel(ctx context.Context, labelId int) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksByLabel(ctx, labelId)
+	m.observe("TasksByLabel", start, err)
+	return res, err
+}
+
+// FilterTasks измеряет вызов FilterTasks.
+func (m *Middleware) FilterTasks(ctx context.Context, f storage.TaskFilter) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.FilterTasks(ctx, f)
+	m.observe("FilterTasks", start, err)
+	return res, err
+}
+
+// AddTask измеряет вызов AddTask.
+func (m *Middleware) AddTask(ctx context.Context, task storage.Task) (int, error) {
+	start := time.Now()
+	res, err := m.inner.AddTask(ctx, task)
+	m.observe("AddTask", start, err)
+	return res, err
+}
+
+// AddTasks измеряет вызов AddTasks.
+func (m *Middleware) AddTasks(ctx context.Context, tasks []storage.Task) ([]int, error) {
+	start := time.Now()
+	res, err := m.inner.AddTasks(ctx, tasks)
+	m.observe("AddTasks", start, err)
+	return res, err
+}
+
+// AddTasksBatch измеряет вызов AddTasksBatch.
+func (m *Middleware) AddTasksBatch(ctx context.Context, tasks []storage.Task) ([]int, error) {
+	start := time.Now()
+	res, err := m.inner.AddTasksBatch(ctx, tasks)
+	m.observe("AddTasksBatch", start, err)
+	return res, err
+}
+
+// UpdateTask измеряет вызов UpdateTask.
+func (m *Middleware) UpdateTask(ctx context.Context, task storage.Task) error {
+	start := time.Now()
+	err := m.inner.UpdateTask(ctx, task)
+	m.observe("UpdateTask", start, err)
+	return err
+}
+
+// UpsertTask измеряет вызов UpsertTask.
+func (m *Middleware) UpsertTask(ctx context.Context, t storage.Task) (int, error) {
+	start := time.Now()
+	res, err := m.inner.UpsertTask(ctx, t)
+	m.observe("UpsertTask", start, err)
+	return res, err
+}
+
+// PartialUpdateTask измеряет вызов PartialUpdateTask.
+func (m *Middleware) PartialUpdateTask(ctx context.Context, taskID int, patch storage.TaskPatch) error {
+	start := time.Now()
+	err := m.inner.PartialUpdateTask(ctx, taskID, patch)
+	m.observe("PartialUpdateTask", start, err)
+	return err
+}
+
+// DeleteTask измеряет вызов DeleteTask.
+func (m *Middleware) DeleteTask(ctx context.Context, taskId int) error {
+	start := time.Now()
+	err := m.inner.DeleteTask(ctx, taskId)
+	m.observe("DeleteTask", start, err)
+	return err
+}
+
+// DeleteTasks измеряет вызов DeleteTasks.
+func (m *Middleware) DeleteTasks(ctx context.Context, taskIDs []int) error {
+	start := time.Now()
+	err := m.inner.DeleteTasks(ctx, taskIDs)
+	m.observe("DeleteTasks", start, err)
+	return err
+}
+
+// TasksIncludingDeleted измеряет вызов TasksIncludingDeleted.
+func (m *Middleware) TasksIncludingDeleted(ctx context.Context) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksIncludingDeleted(ctx)
+	m.observe("TasksIncludingDeleted", start, err)
+	return res, err
+}
+
+// UndeleteTask измеряет вызов UndeleteTask.
+func (m *Middleware) UndeleteTask(ctx context.Context, taskID int) error {
+	start := time.Now()
+	err := m.inner.UndeleteTask(ctx, taskID)
+	m.observe("UndeleteTask", start, err)
+	return err
+}
+
+// AddUser измеряет вызов AddUser.
+func (m *Middleware) AddUser(ctx context.Context, u storage.User) (int, error) {
+	start := time.Now()
+	res, err := m.inner.AddUser(ctx, u)
+	m.observe("AddUser", start, err)
+	return res, err
+}
+
+// Users измеряет вызов Users.
+func (m *Middleware) Users(ctx context.Context) ([]storage.User, error) {
+	start := time.Now()
+	res, err := m.inner.Users(ctx)
+	m.observe("Users", start, err)
+	return res, err
+}
+
+// UserByID измеряет вызов UserByID.
+func (m *Middleware) UserByID(ctx context.Context, userID int) (*storage.User, error) {
+	start := time.Now()
+	res, err := m.inner.UserByID(ctx, userID)
+	m.observe("UserByID", start, err)
+	return res, err
+}
+
+// UpdateUser измеряет вызов UpdateUser.
+func (m *Middleware) UpdateUser(ctx context.Context, u storage.User) error {
+	start := time.Now()
+	err := m.inner.UpdateUser(ctx, u)
+	m.observe("UpdateUser", start, err)
+	return err
+}
+
+// DeleteUser измеряет вызов DeleteUser.
+func (m *Middleware) DeleteUser(ctx context.Context, userID int) error {
+	start := time.Now()
+	err := m.inner.DeleteUser(ctx, userID)
+	m.observe("DeleteUser", start, err)
+	return err
+}
+
+// AddLabel измеряет вызов AddLabel.
+func (m *Middleware) AddLabel(ctx context.Context, l storage.Label) (int, error) {
+	start := time.Now()
+	res, err := m.inner.AddLabel(ctx, l)
+	m.observe("AddLabel", start, err)
+	return res, err
+}
+
+// Labels измеряет вызов Labels.
+func (m *Middleware) Labels(ctx context.Context) ([]storage.Label, error) {
+	start := time.Now()
+	res, err := m.inner.Labels(ctx)
+	m.observe("Labels", start, err)
+	return res, err
+}
+
+// LabelByID измеряет вызов LabelByID.
+func (m *Middleware) LabelByID(ctx context.Context, labelID int) (*storage.Label, error) {
+	start := time.Now()
+	res, err := m.inner.LabelByID(ctx, labelID)
+	m.observe("LabelByID", start, err)
+	return res, err
+}
+
+// UpdateLabel измеряет вызов UpdateLabel.
+func (m *Middleware) UpdateLabel(ctx context.Context, l storage.Label) error {
+	start := time.Now()
+	err := m.inner.UpdateLabel(ctx, l)
+	m.observe("UpdateLabel", start, err)
+	return err
+}
+
+// DeleteLabel измеряет вызов DeleteLabel.
+func (m *Middleware) DeleteLabel(ctx context.Context, labelID int) error {
+	start := time.Now()
+	err := m.inner.DeleteLabel(ctx, labelID)
+	m.observe("DeleteLabel", start, err)
+	return err
+}
+
+// AssignLabel измеряет вызов AssignLabel.
+func (m *Middleware) AssignLabel(ctx context.Context, taskID, labelID int) error {
+	start := time.Now()
+	err := m.inner.AssignLabel(ctx, taskID, labelID)
+	m.observe("AssignLabel", start, err)
+	return err
+}
+
+// RemoveLabel измеряет вызов RemoveLabel.
+func (m *Middleware) RemoveLabel(ctx context.Context, taskID, labelID int) error {
+	start := time.Now()
+	err := m.inner.RemoveLabel(ctx, taskID, labelID)
+	m.observe("RemoveLabel", start, err)
+	return err
+}
+
+// TasksByStatus измеряет вызов TasksByStatus.
+func (m *Middleware) TasksByStatus(ctx context.Context, s storage.Status) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksByStatus(ctx, s)
+	m.observe("TasksByStatus", start, err)
+	return res, err
+}
+
+// UpdateTaskStatus измеряет вызов UpdateTaskStatus.
+func (m *Middleware) UpdateTaskStatus(ctx context.Context, taskID int, s storage.Status) error {
+	start := time.Now()
+	err := m.inner.UpdateTaskStatus(ctx, taskID, s)
+	m.observe("UpdateTaskStatus", start, err)
+	return err
+}
+
+// TasksByPriority измеряет вызов TasksByPriority.
+func (m *Middleware) TasksByPriority(ctx context.Context, p storage.Priority) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksByPriority(ctx, p)
+	m.observe("TasksByPriority", start, err)
+	return res, err
+}
+
+// TasksOrderedByPriority измеряет вызов TasksOrderedByPriority.
+func (m *Middleware) TasksOrderedByPriority(ctx context.Context) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksOrderedByPriority(ctx)
+	m.observe("TasksOrderedByPriority", start, err)
+	return res, err
+}
+
+// TasksOverdue измеряет вызов TasksOverdue.
+func (m *Middleware) TasksOverdue(ctx context.Context, now int64) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksOverdue(ctx, now)
+	m.observe("TasksOverdue", start, err)
+	return res, err
+}
+
+// TasksDueBetween измеряет вызов TasksDueBetween.
+func (m *Middleware) TasksDueBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksDueBetween(ctx, from, to)
+	m.observe("TasksDueBetween", start, err)
+	return res, err
+}
+
+// AddComment измеряет вызов AddComment.
+func (m *Middleware) AddComment(ctx context.Context, c storage.Comment) (int, error) {
+	start := time.Now()
+	res, err := m.inner.AddComment(ctx, c)
+	m.observe("AddComment", start, err)
+	return res, err
+}
+
+// CommentsByTask измеряет вызов CommentsByTask.
+func (m *Middleware) CommentsByTask(ctx context.Context, taskID int) ([]storage.Comment, error) {
+	start := time.Now()
+	res, err := m.inner.CommentsByTask(ctx, taskID)
+	m.observe("CommentsByTask", start, err)
+	return res, err
+}
+
+// UpdateComment измеряет вызов UpdateComment.
+func (m *Middleware) UpdateComment(ctx context.Context, c storage.Comment) error {
+	start := time.Now()
+	err := m.inner.UpdateComment(ctx, c)
+	m.observe("UpdateComment", start, err)
+	return err
+}
+
+// DeleteComment измеряет вызов DeleteComment.
+func (m *Middleware) DeleteComment(ctx context.Context, commentID int) error {
+	start := time.Now()
+	err := m.inner.DeleteComment(ctx, commentID)
+	m.observe("DeleteComment", start, err)
+	return err
+}
+
+// LogTime измеряет вызов LogTime.
+func (m *Middleware) LogTime(ctx context.Context, e storage.TimeEntry) (int, error) {
+	start := time.Now()
+	res, err := m.inner.LogTime(ctx, e)
+	m.observe("LogTime", start, err)
+	return res, err
+}
+
+// TimeEntriesByTask измеряет вызов TimeEntriesByTask.
+func (m *Middleware) TimeEntriesByTask(ctx context.Context, taskID int) ([]storage.TimeEntry, error) {
+	start := time.Now()
+	res, err := m.inner.TimeEntriesByTask(ctx, taskID)
+	m.observe("TimeEntriesByTask", start, err)
+	return res, err
+}
+
+// TimeEntriesByUser измеряет вызов TimeEntriesByUser.
+func (m *Middleware) TimeEntriesByUser(ctx context.Context, userID int) ([]storage.TimeEntry, error) {
+	start := time.Now()
+	res, err := m.inner.TimeEntriesByUser(ctx, userID)
+	m.observe("TimeEntriesByUser", start, err)
+	return res, err
+}
+
+// TotalMinutesByTask измеряет вызов TotalMinutesByTask.
+func (m *Middleware) TotalMinutesByTask(ctx context.Context, taskID int) (int, error) {
+	start := time.Now()
+	res, err := m.inner.TotalMinutesByTask(ctx, taskID)
+	m.observe("TotalMinutesByTask", start, err)
+	return res, err
+}
+
+// AddChecklistItem измеряет вызов AddChecklistItem.
+func (m *Middleware) AddChecklistItem(ctx context.Context, item storage.ChecklistItem) (int, error) {
+	start := time.Now()
+	res, err := m.inner.AddChecklistItem(ctx, item)
+	m.observe("AddChecklistItem", start, err)
+	return res, err
+}
+
+// ChecklistByTask измеряет вызов ChecklistByTask.
+func (m *Middleware) ChecklistByTask(ctx context.Context, taskID int) ([]storage.ChecklistItem, error) {
+	start := time.Now()
+	res, err := m.inner.ChecklistByTask(ctx, taskID)
+	m.observe("ChecklistByTask", start, err)
+	return res, err
+}
+
+// UpdateChecklistItem измеряет вызов UpdateChecklistItem.
+func (m *Middleware) UpdateChecklistItem(ctx context.Context, item storage.ChecklistItem) error {
+	start := time.Now()
+	err := m.inner.UpdateChecklistItem(ctx, item)
+	m.observe("UpdateChecklistItem", start, err)
+	return err
+}
+
+// DeleteChecklistItem измеряет вызов DeleteChecklistItem.
+func (m *Middleware) DeleteChecklistItem(ctx context.Context, itemID int) error {
+	start := time.Now()
+	err := m.inner.DeleteChecklistItem(ctx, itemID)
+	m.observe("DeleteChecklistItem", start, err)
+	return err
+}
+
+// ReorderChecklist измеряет вызов ReorderChecklist.
+func (m *Middleware) ReorderChecklist(ctx context.Context, taskID int, orderedIDs []int) error {
+	start := time.Now()
+	err := m.inner.ReorderChecklist(ctx, taskID, orderedIDs)
+	m.observe("ReorderChecklist", start, err)
+	return err
+}
+
+// AddProject измеряет вызов AddProject.
+func (m *Middleware) AddProject(ctx context.Context, p storage.Project) (int, error) {
+	start := time.Now()
+	res, err := m.inner.AddProject(ctx, p)
+	m.observe("AddProject", start, err)
+	return res, err
+}
+
+// Projects измеряет вызов Projects.
+func (m *Middleware) Projects(ctx context.Context) ([]storage.Project, error) {
+	start := time.Now()
+	res, err := m.inner.Projects(ctx)
+	m.observe("Projects", start, err)
+	return res, err
+}
+
+// ProjectByID измеряет вызов ProjectByID.
+func (m *Middleware) ProjectByID(ctx context.Context, projectID int) (*storage.Project, error) {
+	start := time.Now()
+	res, err := m.inner.ProjectByID(ctx, projectID)
+	m.observe("ProjectByID", start, err)
+	return res, err
+}
+
+// UpdateProject измеряет вызов UpdateProject.
+func (m *Middleware) UpdateProject(ctx context.Context, p storage.Project) error {
+	start := time.Now()
+	err := m.inner.UpdateProject(ctx, p)
+	m.observe("UpdateProject", start, err)
+	return err
+}
+
+// DeleteProject измеряет вызов DeleteProject.
+func (m *Middleware) DeleteProject(ctx context.Context, projectID int, cascadeDelete bool) error {
+	start := time.Now()
+	err := m.inner.DeleteProject(ctx, projectID, cascadeDelete)
+	m.observe("DeleteProject", start, err)
+	return err
+}
+
+// TasksByProject измеряет вызов TasksByProject.
+func (m *Middleware) TasksByProject(ctx context.Context, projectID int) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksByProject(ctx, projectID)
+	m.observe("TasksByProject", start, err)
+	return res, err
+}
+
+// AddDependency измеряет вызов AddDependency.
+func (m *Middleware) AddDependency(ctx context.Context, taskID, dependsOnID int) error {
+	start := time.Now()
+	err := m.inner.AddDependency(ctx, taskID, dependsOnID)
+	m.observe("AddDependency", start, err)
+	return err
+}
+
+// RemoveDependency измеряет вызов RemoveDependency.
+func (m *Middleware) RemoveDependency(ctx context.Context, taskID, dependsOnID int) error {
+	start := time.Now()
+	err := m.inner.RemoveDependency(ctx, taskID, dependsOnID)
+	m.observe("RemoveDependency", start, err)
+	return err
+}
+
+// DependenciesOf измеряет вызов DependenciesOf.
+func (m *Middleware) DependenciesOf(ctx context.Context, taskID int) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.DependenciesOf(ctx, taskID)
+	m.observe("DependenciesOf", start, err)
+	return res, err
+}
+
+// BlockedBy измеряет вызов BlockedBy.
+func (m *Middleware) BlockedBy(ctx context.Context, taskID int) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.BlockedBy(ctx, taskID)
+	m.observe("BlockedBy", start, err)
+	return res, err
+}
+
+// AddTaskWithLabels измеряет вызов AddTaskWithLabels.
+func (m *Middleware) AddTaskWithLabels(ctx context.Context, t storage.Task, labelIDs []int) (int, error) {
+	start := time.Now()
+	res, err := m.inner.AddTaskWithLabels(ctx, t, labelIDs)
+	m.observe("AddTaskWithLabels", start, err)
+	return res, err
+}
+
+// ImportTasks измеряет вызов ImportTasks.
+func (m *Middleware) ImportTasks(ctx context.Context, tasks []storage.Task) error {
+	start := time.Now()
+	err := m.inner.ImportTasks(ctx, tasks)
+	m.observe("ImportTasks", start, err)
+	return err
+}
+
+// TasksWithLabels измеряет вызов TasksWithLabels.
+func (m *Middleware) TasksWithLabels(ctx context.Context) ([]storage.TaskWithLabels, error) {
+	start := time.Now()
+	res, err := m.inner.TasksWithLabels(ctx)
+	m.observe("TasksWithLabels", start, err)
+	return res, err
+}
+
+// TasksWithUsers измеряет вызов TasksWithUsers.
+func (m *Middleware) TasksWithUsers(ctx context.Context) ([]storage.TaskWithUsers, error) {
+	start := time.Now()
+	res, err := m.inner.TasksWithUsers(ctx)
+	m.observe("TasksWithUsers", start, err)
+	return res, err
+}
+
+// BeginTx измеряет вызов BeginTx.
+func (m *Middleware) BeginTx(ctx context.Context) (storage.TxStorage, error) {
+	start := time.Now()
+	res, err := m.inner.BeginTx(ctx)
+	m.observe("BeginTx", start, err)
+	return res, err
+}
+
+// AuditLog измеряет вызов AuditLog.
+func (m *Middleware) AuditLog(ctx context.Context, taskID int) ([]storage.AuditEntry, error) {
+	start := time.Now()
+	res, err := m.inner.AuditLog(ctx, taskID)
+	m.observe("AuditLog", start, err)
+	return res, err
+}
+
+// RecordChange измеряет вызов RecordChange.
+func (m *Middleware) RecordChange(ctx context.Context, e storage.AuditEntry) error {
+	start := time.Now()
+	err := m.inner.RecordChange(ctx, e)
+	m.observe("RecordChange", start, err)
+	return err
+}
+
+// SearchTasks измеряет вызов SearchTasks.
+func (m *Middleware) SearchTasks(ctx context.Context, query string, limit int) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.SearchTasks(ctx, query, limit)
+	m.observe("SearchTasks", start, err)
+	return res, err
+}
+
+// TasksByMetadataKey измеряет вызов TasksByMetadataKey.
+func (m *Middleware) TasksByMetadataKey(ctx context.Context, key, value string) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksByMetadataKey(ctx, key, value)
+	m.observe("TasksByMetadataKey", start, err)
+	return res, err
+}
+
+// AssignmentHistory измеряет вызов AssignmentHistory.
+func (m *Middleware) AssignmentHistory(ctx context.Context, taskID int) ([]storage.AssignmentEvent, error) {
+	start := time.Now()
+	res, err := m.inner.AssignmentHistory(ctx, taskID)
+	m.observe("AssignmentHistory", start, err)
+	return res, err
+}
+
+// RecordAssignment измеряет вызов RecordAssignment.
+func (m *Middleware) RecordAssignment(ctx context.Context, e storage.AssignmentEvent) error {
+	start := time.Now()
+	err := m.inner.RecordAssignment(ctx, e)
+	m.observe("RecordAssignment", start, err)
+	return err
+}
+
+// AddRecurrenceRule измеряет вызов AddRecurrenceRule.
+func (m *Middleware) AddRecurrenceRule(ctx context.Context, rule storage.RecurrenceRule) error {
+	start := time.Now()
+	err := m.inner.AddRecurrenceRule(ctx, rule)
+	m.observe("AddRecurrenceRule", start, err)
+	return err
+}
+
+// RecurrenceRuleByTask измеряет вызов RecurrenceRuleByTask.
+func (m *Middleware) RecurrenceRuleByTask(ctx context.Context, taskID int) (*storage.RecurrenceRule, error) {
+	start := time.Now()
+	res, err := m.inner.RecurrenceRuleByTask(ctx, taskID)
+	m.observe("RecurrenceRuleByTask", start, err)
+	return res, err
+}
+
+// UpdateRecurrenceRule измеряет вызов UpdateRecurrenceRule.
+func (m *Middleware) UpdateRecurrenceRule(ctx context.Context, rule storage.RecurrenceRule) error {
+	start := time.Now()
+	err := m.inner.UpdateRecurrenceRule(ctx, rule)
+	m.observe("UpdateRecurrenceRule", start, err)
+	return err
+}
+
+// DeleteRecurrenceRule измеряет вызов DeleteRecurrenceRule.
+func (m *Middleware) DeleteRecurrenceRule(ctx context.Context, taskID int) error {
+	start := time.Now()
+	err := m.inner.DeleteRecurrenceRule(ctx, taskID)
+	m.observe("DeleteRecurrenceRule", start, err)
+	return err
+}
+
+// DueRecurrences измеряет вызов DueRecurrences.
+func (m *Middleware) DueRecurrences(ctx context.Context, now int64) ([]storage.RecurrenceRule, error) {
+	start := time.Now()
+	res, err := m.inner.DueRecurrences(ctx, now)
+	m.observe("DueRecurrences", start, err)
+	return res, err
+}
+
+// SpawnRecurringTask измеряет вызов SpawnRecurringTask.
+func (m *Middleware) SpawnRecurringTask(ctx context.Context, rule storage.RecurrenceRule) (int, error) {
+	start := time.Now()
+	res, err := m.inner.SpawnRecurringTask(ctx, rule)
+	m.observe("SpawnRecurringTask", start, err)
+	return res, err
+}
+
+// ArchiveTask измеряет вызов ArchiveTask.
+func (m *Middleware) ArchiveTask(ctx context.Context, taskID int) error {
+	start := time.Now()
+	err := m.inner.ArchiveTask(ctx, taskID)
+	m.observe("ArchiveTask", start, err)
+	return err
+}
+
+// ArchivedTasks измеряет вызов ArchivedTasks.
+func (m *Middleware) ArchivedTasks(ctx context.Context) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.ArchivedTasks(ctx)
+	m.observe("ArchivedTasks", start, err)
+	return res, err
+}
+
+// UnarchiveTask измеряет вызов UnarchiveTask.
+func (m *Middleware) UnarchiveTask(ctx context.Context, taskID int) error {
+	start := time.Now()
+	err := m.inner.UnarchiveTask(ctx, taskID)
+	m.observe("UnarchiveTask", start, err)
+	return err
+}
+
+// TasksByLabels измеряет вызов TasksByLabels.
+func (m *Middleware) TasksByLabels(ctx context.Context, labelIDs []int, mode storage.LabelFilterMode) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksByLabels(ctx, labelIDs, mode)
+	m.observe("TasksByLabels", start, err)
+	return res, err
+}
+
+// TasksOpenedBetween измеряет вызов TasksOpenedBetween.
+func (m *Middleware) TasksOpenedBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksOpenedBetween(ctx, from, to)
+	m.observe("TasksOpenedBetween", start, err)
+	return res, err
+}
+
+// TasksClosedBetween измеряет вызов TasksClosedBetween.
+func (m *Middleware) TasksClosedBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksClosedBetween(ctx, from, to)
+	m.observe("TasksClosedBetween", start, err)
+	return res, err
+}
+
+// AddSprint измеряет вызов AddSprint.
+func (m *Middleware) AddSprint(ctx context.Context, sp storage.Sprint) (int, error) {
+	start := time.Now()
+	res, err := m.inner.AddSprint(ctx, sp)
+	m.observe("AddSprint", start, err)
+	return res, err
+}
+
+// SprintByID измеряет вызов SprintByID.
+func (m *Middleware) SprintByID(ctx context.Context, sprintID int) (*storage.Sprint, error) {
+	start := time.Now()
+	res, err := m.inner.SprintByID(ctx, sprintID)
+	m.observe("SprintByID", start, err)
+	return res, err
+}
+
+// Sprints измеряет вызов Sprints.
+func (m *Middleware) Sprints(ctx context.Context) ([]storage.Sprint, error) {
+	start := time.Now()
+	res, err := m.inner.Sprints(ctx)
+	m.observe("Sprints", start, err)
+	return res, err
+}
+
+// UpdateSprint измеряет вызов UpdateSprint.
+func (m *Middleware) UpdateSprint(ctx context.Context, sp storage.Sprint) error {
+	start := time.Now()
+	err := m.inner.UpdateSprint(ctx, sp)
+	m.observe("UpdateSprint", start, err)
+	return err
+}
+
+// DeleteSprint измеряет вызов DeleteSprint.
+func (m *Middleware) DeleteSprint(ctx context.Context, sprintID int) error {
+	start := time.Now()
+	err := m.inner.DeleteSprint(ctx, sprintID)
+	m.observe("DeleteSprint", start, err)
+	return err
+}
+
+// AssignTaskToSprint измеряет вызов AssignTaskToSprint.
+func (m *Middleware) AssignTaskToSprint(ctx context.Context, taskID, sprintID int) error {
+	start := time.Now()
+	err := m.inner.AssignTaskToSprint(ctx, taskID, sprintID)
+	m.observe("AssignTaskToSprint", start, err)
+	return err
+}
+
+// RemoveTaskFromSprint измеряет вызов RemoveTaskFromSprint.
+func (m *Middleware) RemoveTaskFromSprint(ctx context.Context, taskID, sprintID int) error {
+	start := time.Now()
+	err := m.inner.RemoveTaskFromSprint(ctx, taskID, sprintID)
+	m.observe("RemoveTaskFromSprint", start, err)
+	return err
+}
+
+// TasksBySprint измеряет вызов TasksBySprint.
+func (m *Middleware) TasksBySprint(ctx context.Context, sprintID int) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksBySprint(ctx, sprintID)
+	m.observe("TasksBySprint", start, err)
+	return res, err
+}
+
+// SprintVelocity измеряет вызов SprintVelocity.
+func (m *Middleware) SprintVelocity(ctx context.Context, sprintID int) (int, error) {
+	start := time.Now()
+	res, err := m.inner.SprintVelocity(ctx, sprintID)
+	m.observe("SprintVelocity", start, err)
+	return res, err
+}
+
+// BurndownData измеряет вызов BurndownData.
+func (m *Middleware) BurndownData(ctx context.Context, sprintID int, buckets int) ([]storage.BurndownPoint, error) {
+	start := time.Now()
+	res, err := m.inner.BurndownData(ctx, sprintID, buckets)
+	m.observe("BurndownData", start, err)
+	return res, err
+}
+
+// UserWorkloads измеряет вызов UserWorkloads.
+func (m *Middleware) UserWorkloads(ctx context.Context) ([]storage.UserWorkload, error) {
+	start := time.Now()
+	res, err := m.inner.UserWorkloads(ctx)
+	m.observe("UserWorkloads", start, err)
+	return res, err
+}
+
+// UserWorkload измеряет вызов UserWorkload.
+func (m *Middleware) UserWorkload(ctx context.Context, userID int) (*storage.UserWorkload, error) {
+	start := time.Now()
+	res, err := m.inner.UserWorkload(ctx, userID)
+	m.observe("UserWorkload", start, err)
+	return res, err
+}
+
+// TaskStats измеряет вызов TaskStats.
+func (m *Middleware) TaskStats(ctx context.Context) (*storage.TaskStats, error) {
+	start := time.Now()
+	res, err := m.inner.TaskStats(ctx)
+	m.observe("TaskStats", start, err)
+	return res, err
+}
+
+// LabelStats измеряет вызов LabelStats.
+func (m *Middleware) LabelStats(ctx context.Context) ([]storage.LabelStat, error) {
+	start := time.Now()
+	res, err := m.inner.LabelStats(ctx)
+	m.observe("LabelStats", start, err)
+	return res, err
+}
+
+// TopLabels измеряет вызов TopLabels.
+func (m *Middleware) TopLabels(ctx context.Context, n int) ([]storage.LabelStat, error) {
+	start := time.Now()
+	res, err := m.inner.TopLabels(ctx, n)
+	m.observe("TopLabels", start, err)
+	return res, err
+}
+
+// RecordActivity измеряет вызов RecordActivity.
+func (m *Middleware) RecordActivity(ctx context.Context, e storage.ActivityEvent) error {
+	start := time.Now()
+	err := m.inner.RecordActivity(ctx, e)
+	m.observe("RecordActivity", start, err)
+	return err
+}
+
+// ActivityFeed измеряет вызов ActivityFeed.
+func (m *Middleware) ActivityFeed(ctx context.Context, taskID int, limit int) ([]storage.ActivityEvent, error) {
+	start := time.Now()
+	res, err := m.inner.ActivityFeed(ctx, taskID, limit)
+	m.observe("ActivityFeed", start, err)
+	return res, err
+}
+
+// GlobalActivityFeed измеряет вызов GlobalActivityFeed.
+func (m *Middleware) GlobalActivityFeed(ctx context.Context, limit int) ([]storage.ActivityEvent, error) {
+	start := time.Now()
+	res, err := m.inner.GlobalActivityFeed(ctx, limit)
+	m.observe("GlobalActivityFeed", start, err)
+	return res, err
+}
+
+// AppendEvents измеряет вызов AppendEvents.
+func (m *Middleware) AppendEvents(ctx context.Context, streamID string, expectedSeq int64, events []storage.Event) error {
+	start := time.Now()
+	err := m.inner.AppendEvents(ctx, streamID, expectedSeq, events)
+	m.observe("AppendEvents", start, err)
+	return err
+}
+
+// LoadEvents измеряет вызов LoadEvents.
+func (m *Middleware) LoadEvents(ctx context.Context, streamID string, fromSeq int64) ([]storage.Event, error) {
+	start := time.Now()
+	res, err := m.inner.LoadEvents(ctx, streamID, fromSeq)
+	m.observe("LoadEvents", start, err)
+	return res, err
+}
+
+// AppendOutbox измеряет вызов AppendOutbox.
+func (m *Middleware) AppendOutbox(ctx context.Context, tx storage.TxStorage, msgs []storage.OutboxMessage) error {
+	start := time.Now()
+	err := m.inner.AppendOutbox(ctx, tx, msgs)
+	m.observe("AppendOutbox", start, err)
+	return err
+}
+
+// PendingOutbox измеряет вызов PendingOutbox.
+func (m *Middleware) PendingOutbox(ctx context.Context, limit int) ([]storage.OutboxMessage, error) {
+	start := time.Now()
+	res, err := m.inner.PendingOutbox(ctx, limit)
+	m.observe("PendingOutbox", start, err)
+	return res, err
+}
+
+// MarkOutboxSent измеряет вызов MarkOutboxSent.
+func (m *Middleware) MarkOutboxSent(ctx context.Context, ids []int64) error {
+	start := time.Now()
+	err := m.inner.MarkOutboxSent(ctx, ids)
+	m.observe("MarkOutboxSent", start, err)
+	return err
+}
+
+// TasksClosedInRange измеряет вызов TasksClosedInRange.
+func (m *Middleware) TasksClosedInRange(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksClosedInRange(ctx, from, to)
+	m.observe("TasksClosedInRange", start, err)
+	return res, err
+}
+
+// TasksUnassigned измеряет вызов TasksUnassigned.
+func (m *Middleware) TasksUnassigned(ctx context.Context) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksUnassigned(ctx)
+	m.observe("TasksUnassigned", start, err)
+	return res, err
+}
+
+// AssignTask измеряет вызов AssignTask.
+func (m *Middleware) AssignTask(ctx context.Context, taskID, userID int) error {
+	start := time.Now()
+	err := m.inner.AssignTask(ctx, taskID, userID)
+	m.observe("AssignTask", start, err)
+	return err
+}
+
+// TaskByExternalID измеряет вызов TaskByExternalID.
+func (m *Middleware) TaskByExternalID(ctx context.Context, system, externalID string) (*storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TaskByExternalID(ctx, system, externalID)
+	m.observe("TaskByExternalID", start, err)
+	return res, err
+}
+
+// SetNotificationPreference измеряет вызов SetNotificationPreference.
+func (m *Middleware) SetNotificationPreference(ctx context.Context, p storage.NotificationPreference) error {
+	start := time.Now()
+	err := m.inner.SetNotificationPreference(ctx, p)
+	m.observe("SetNotificationPreference", start, err)
+	return err
+}
+
+// GetNotificationPreference измеряет вызов GetNotificationPreference.
+func (m *Middleware) GetNotificationPreference(ctx context.Context, userID int) (*storage.NotificationPreference, error) {
+	start := time.Now()
+	res, err := m.inner.GetNotificationPreference(ctx, userID)
+	m.observe("GetNotificationPreference", start, err)
+	return res, err
+}
+
+// UsersSubscribedToTask измеряет вызов UsersSubscribedToTask.
+func (m *Middleware) UsersSubscribedToTask(ctx context.Context, taskID int, event string) ([]storage.User, error) {
+	start := time.Now()
+	res, err := m.inner.UsersSubscribedToTask(ctx, taskID, event)
+	m.observe("UsersSubscribedToTask", start, err)
+	return res, err
+}
+
+// WatchTask измеряет вызов WatchTask.
+func (m *Middleware) WatchTask(ctx context.Context, userID, taskID int) error {
+	start := time.Now()
+	err := m.inner.WatchTask(ctx, userID, taskID)
+	m.observe("WatchTask", start, err)
+	return err
+}
+
+// UnwatchTask измеряет вызов UnwatchTask.
+func (m *Middleware) UnwatchTask(ctx context.Context, userID, taskID int) error {
+	start := time.Now()
+	err := m.inner.UnwatchTask(ctx, userID, taskID)
+	m.observe("UnwatchTask", start, err)
+	return err
+}
+
+// WatchersOfTask измеряет вызов WatchersOfTask.
+func (m *Middleware) WatchersOfTask(ctx context.Context, taskID int) ([]storage.User, error) {
+	start := time.Now()
+	res, err := m.inner.WatchersOfTask(ctx, taskID)
+	m.observe("WatchersOfTask", start, err)
+	return res, err
+}
+
+// CreateAPIKey измеряет вызов CreateAPIKey.
+func (m *Middleware) CreateAPIKey(ctx context.Context, k storage.APIKey) (string, error) {
+	start := time.Now()
+	res, err := m.inner.CreateAPIKey(ctx, k)
+	m.observe("CreateAPIKey", start, err)
+	return res, err
+}
+
+// APIKeyByKey измеряет вызов APIKeyByKey.
+func (m *Middleware) APIKeyByKey(ctx context.Context, raw string) (*storage.APIKey, error) {
+	start := time.Now()
+	res, err := m.inner.APIKeyByKey(ctx, raw)
+	m.observe("APIKeyByKey", start, err)
+	return res, err
+}
+
+// RevokeAPIKey измеряет вызов RevokeAPIKey.
+func (m *Middleware) RevokeAPIKey(ctx context.Context, keyID int) error {
+	start := time.Now()
+	err := m.inner.RevokeAPIKey(ctx, keyID)
+	m.observe("RevokeAPIKey", start, err)
+	return err
+}
+
+// APIKeysByUser измеряет вызов APIKeysByUser.
+func (m *Middleware) APIKeysByUser(ctx context.Context, userID int) ([]storage.APIKey, error) {
+	start := time.Now()
+	res, err := m.inner.APIKeysByUser(ctx, userID)
+	m.observe("APIKeysByUser", start, err)
+	return res, err
+}
+
+// AssignRole измеряет вызов AssignRole.
+func (m *Middleware) AssignRole(ctx context.Context, userID int, role storage.Role) error {
+	start := time.Now()
+	err := m.inner.AssignRole(ctx, userID, role)
+	m.observe("AssignRole", start, err)
+	return err
+}
+
+// RoleOfUser измеряет вызов RoleOfUser.
+func (m *Middleware) RoleOfUser(ctx context.Context, userID int) (storage.Role, error) {
+	start := time.Now()
+	res, err := m.inner.RoleOfUser(ctx, userID)
+	m.observe("RoleOfUser", start, err)
+	return res, err
+}
+
+// UsersWithRole измеряет вызов UsersWithRole.
+func (m *Middleware) UsersWithRole(ctx context.Context, role storage.Role) ([]storage.User, error) {
+	start := time.Now()
+	res, err := m.inner.UsersWithRole(ctx, role)
+	m.observe("UsersWithRole", start, err)
+	return res, err
+}
+
+// ExportUserData измеряет вызов ExportUserData.
+func (m *Middleware) ExportUserData(ctx context.Context, userID int) (*storage.UserDataExport, error) {
+	start := time.Now()
+	res, err := m.inner.ExportUserData(ctx, userID)
+	m.observe("ExportUserData", start, err)
+	return res, err
+}
+
+// EraseUser измеряет вызов EraseUser.
+func (m *Middleware) EraseUser(ctx context.Context, userID int) error {
+	start := time.Now()
+	err := m.inner.EraseUser(ctx, userID)
+	m.observe("EraseUser", start, err)
+	return err
+}
+
+// StaleTasks измеряет вызов StaleTasks.
+func (m *Middleware) StaleTasks(ctx context.Context, staleAfterSeconds int64, now int64) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.StaleTasks(ctx, staleAfterSeconds, now)
+	m.observe("StaleTasks", start, err)
+	return res, err
+}
+
+// MarkTaskStale измеряет вызов MarkTaskStale.
+func (m *Middleware) MarkTaskStale(ctx context.Context, taskID int) error {
+	start := time.Now()
+	err := m.inner.MarkTaskStale(ctx, taskID)
+	m.observe("MarkTaskStale", start, err)
+	return err
+}
+
+// SimilarTasks измеряет вызов SimilarTasks.
+func (m *Middleware) SimilarTasks(ctx context.Context, title string, threshold float64) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.SimilarTasks(ctx, title, threshold)
+	m.observe("SimilarTasks", start, err)
+	return res, err
+}
+
+// AddTaskLink измеряет вызов AddTaskLink.
+func (m *Middleware) AddTaskLink(ctx context.Context, l storage.TaskLinkRecord) (int, error) {
+	start := time.Now()
+	res, err := m.inner.AddTaskLink(ctx, l)
+	m.observe("AddTaskLink", start, err)
+	return res, err
+}
+
+// TaskLinks измеряет вызов TaskLinks.
+func (m *Middleware) TaskLinks(ctx context.Context, taskID int) ([]storage.TaskLinkRecord, error) {
+	start := time.Now()
+	res, err := m.inner.TaskLinks(ctx, taskID)
+	m.observe("TaskLinks", start, err)
+	return res, err
+}
+
+// DeleteTaskLink измеряет вызов DeleteTaskLink.
+func (m *Middleware) DeleteTaskLink(ctx context.Context, linkID int) error {
+	start := time.Now()
+	err := m.inner.DeleteTaskLink(ctx, linkID)
+	m.observe("DeleteTaskLink", start, err)
+	return err
+}
+
+// RegisterWebhook измеряет вызов RegisterWebhook.
+func (m *Middleware) RegisterWebhook(ctx context.Context, w storage.Webhook) (int, error) {
+	start := time.Now()
+	res, err := m.inner.RegisterWebhook(ctx, w)
+	m.observe("RegisterWebhook", start, err)
+	return res, err
+}
+
+// Webhooks измеряет вызов Webhooks.
+func (m *Middleware) Webhooks(ctx context.Context) ([]storage.Webhook, error) {
+	start := time.Now()
+	res, err := m.inner.Webhooks(ctx)
+	m.observe("Webhooks", start, err)
+	return res, err
+}
+
+// WebhookByID измеряет вызов WebhookByID.
+func (m *Middleware) WebhookByID(ctx context.Context, webhookID int) (*storage.Webhook, error) {
+	start := time.Now()
+	res, err := m.inner.WebhookByID(ctx, webhookID)
+	m.observe("WebhookByID", start, err)
+	return res, err
+}
+
+// UpdateWebhook измеряет вызов UpdateWebhook.
+func (m *Middleware) UpdateWebhook(ctx context.Context, w storage.Webhook) error {
+	start := time.Now()
+	err := m.inner.UpdateWebhook(ctx, w)
+	m.observe("UpdateWebhook", start, err)
+	return err
+}
+
+// DeleteWebhook измеряет вызов DeleteWebhook.
+func (m *Middleware) DeleteWebhook(ctx context.Context, webhookID int) error {
+	start := time.Now()
+	err := m.inner.DeleteWebhook(ctx, webhookID)
+	m.observe("DeleteWebhook", start, err)
+	return err
+}
+
+// LockTask измеряет вызов LockTask.
+func (m *Middleware) LockTask(ctx context.Context, taskID int) error {
+	start := time.Now()
+	err := m.inner.LockTask(ctx, taskID)
+	m.observe("LockTask", start, err)
+	return err
+}
+
+// UnlockTask измеряет вызов UnlockTask.
+func (m *Middleware) UnlockTask(ctx context.Context, taskID int) error {
+	start := time.Now()
+	err := m.inner.UnlockTask(ctx, taskID)
+	m.observe("UnlockTask", start, err)
+	return err
+}
+
+// UpdateTasksAssignee измеряет вызов UpdateTasksAssignee.
+func (m *Middleware) UpdateTasksAssignee(ctx context.Context, taskIDs []int, assigneeID int) (int64, error) {
+	start := time.Now()
+	res, err := m.inner.UpdateTasksAssignee(ctx, taskIDs, assigneeID)
+	m.observe("UpdateTasksAssignee", start, err)
+	return res, err
+}
+
+// UpdateTasksStatus измеряет вызов UpdateTasksStatus.
+func (m *Middleware) UpdateTasksStatus(ctx context.Context, taskIDs []int, status storage.Status) (int64, error) {
+	start := time.Now()
+	res, err := m.inner.UpdateTasksStatus(ctx, taskIDs, status)
+	m.observe("UpdateTasksStatus", start, err)
+	return res, err
+}
+
+// TasksByTag измеряет вызов TasksByTag.
+func (m *Middleware) TasksByTag(ctx context.Context, tag string) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksByTag(ctx, tag)
+	m.observe("TasksByTag", start, err)
+	return res, err
+}
+
+// AllTags измеряет вызов AllTags.
+func (m *Middleware) AllTags(ctx context.Context) ([]string, error) {
+	start := time.Now()
+	res, err := m.inner.AllTags(ctx)
+	m.observe("AllTags", start, err)
+	return res, err
+}
+
+// TasksByRank измеряет вызов TasksByRank.
+func (m *Middleware) TasksByRank(ctx context.Context) ([]storage.Task, error) {
+	start := time.Now()
+	res, err := m.inner.TasksByRank(ctx)
+	m.observe("TasksByRank", start, err)
+	return res, err
+}
+
+// UpdateTaskRank измеряет вызов UpdateTaskRank.
+func (m *Middleware) UpdateTaskRank(ctx context.Context, taskID int, rank float64) error {
+	start := time.Now()
+	err := m.inner.UpdateTaskRank(ctx, taskID, rank)
+	m.observe("UpdateTaskRank", start, err)
+	return err
+}
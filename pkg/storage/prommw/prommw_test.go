@@ -0,0 +1,51 @@
+package prommw
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"skillfactory/30.8.1/pkg/storage/mock"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	ctx := context.Background()
+	m, err := New(&mock.Mock{
+		DeleteTaskFunc: func(ctx context.Context, taskId int) error {
+			return storage.ErrNotFound
+		},
+	}, prometheus.NewRegistry())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for range 2 {
+		_, _ = m.TaskById(ctx, 1)
+	}
+	_ = m.DeleteTask(ctx, 1)
+
+	if n := testutil.CollectAndCount(m.duration); n != 2 {
+		t.Errorf("duration series = %d, want 2 (TaskById and DeleteTask)", n)
+	}
+	if n := testutil.ToFloat64(m.errors.WithLabelValues("DeleteTask")); n != 1 {
+		t.Errorf("DeleteTask errors = %v, want 1", n)
+	}
+	if n := testutil.ToFloat64(m.errors.WithLabelValues("TaskById")); n != 0 {
+		t.Errorf("TaskById errors = %v, want 0", n)
+	}
+}
+
+func TestNewRegisterError(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	_, err := New(&mock.Mock{}, reg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	// метрики с теми же именами уже зарегистрированы
+	_, err = New(&mock.Mock{}, reg)
+	if err == nil {
+		t.Error("second New() with the same registry error = nil, want error")
+	}
+}
@@ -0,0 +1,73 @@
+package rbac
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+)
+
+// PermissionMiddleware оборачивает storage.Interface и проверяет
+// роль пользователя из контекста (см. WithRole) перед удалением
+// задач, пользователей и меток: эти операции доступны только
+// администраторам. Остальные методы передаются обёрнутому хранилищу
+// без изменений.
+type PermissionMiddleware struct {
+	storage.Interface
+}
+
+// Конструктор, принимает оборачиваемое хранилище.
+func New(inner storage.Interface) *PermissionMiddleware {
+	m := PermissionMiddleware{
+		Interface: inner,
+	}
+	return &m
+}
+
+type roleKey struct{}
+
+// WithRole возвращает контекст с ролью текущего пользователя.
+// Роль записывает в контекст HTTP-слой после аутентификации.
+// Без неё запрещены все проверяемые операции.
+func WithRole(ctx context.Context, role storage.Role) context.Context {
+	return context.WithValue(ctx, roleKey{}, role)
+}
+
+func roleFrom(ctx context.Context) storage.Role {
+	role, _ := ctx.Value(roleKey{}).(storage.Role)
+	return role
+}
+
+// requireAdmin возвращает storage.ErrForbidden,
+// если текущий пользователь не администратор.
+func requireAdmin(ctx context.Context) error {
+	if roleFrom(ctx) != storage.RoleAdmin {
+		return storage.ErrForbidden
+	}
+	return nil
+}
+
+// DeleteTask удаляет задачу, если пользователь - администратор.
+func (m *PermissionMiddleware) DeleteTask(ctx context.Context, taskId int) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	return m.Interface.DeleteTask(ctx, taskId)
+}
+
+// DeleteUser удаляет пользователя, если пользователь - администратор.
+func (m *PermissionMiddleware) DeleteUser(ctx context.Context, userID int) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	return m.Interface.DeleteUser(ctx, userID)
+}
+
+// DeleteLabel удаляет метку, если пользователь - администратор.
+func (m *PermissionMiddleware) DeleteLabel(ctx context.Context, labelID int) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	return m.Interface.DeleteLabel(ctx, labelID)
+}
@@ -0,0 +1,71 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"skillfactory/30.8.1/pkg/storage/mock"
+	"testing"
+)
+
+func TestPermissionMiddleware(t *testing.T) {
+	methods := map[string]func(ctx context.Context, m *PermissionMiddleware) error{
+		"DeleteTask":  func(ctx context.Context, m *PermissionMiddleware) error { return m.DeleteTask(ctx, 1) },
+		"DeleteUser":  func(ctx context.Context, m *PermissionMiddleware) error { return m.DeleteUser(ctx, 1) },
+		"DeleteLabel": func(ctx context.Context, m *PermissionMiddleware) error { return m.DeleteLabel(ctx, 1) },
+	}
+	roles := []struct {
+		name    string
+		ctx     context.Context
+		allowed bool
+	}{
+		{"admin", WithRole(context.Background(), storage.RoleAdmin), true},
+		{"editor", WithRole(context.Background(), storage.RoleEditor), false},
+		{"viewer", WithRole(context.Background(), storage.RoleViewer), false},
+		{"no role", context.Background(), false},
+	}
+	for method, call := range methods {
+		for _, r := range roles {
+			t.Run(method+"/"+r.name, func(t *testing.T) {
+				inner := &mock.Mock{}
+				err := call(r.ctx, New(inner))
+
+				calls := len(inner.CallsTo(method))
+				if r.allowed {
+					if err != nil || calls != 1 {
+						t.Errorf("%s() = %v with %d inner calls, want nil and 1 call", method, err, calls)
+					}
+					return
+				}
+				if !errors.Is(err, storage.ErrForbidden) || calls != 0 {
+					t.Errorf("%s() = %v with %d inner calls, want ErrForbidden and no calls", method, err, calls)
+				}
+			})
+		}
+	}
+}
+
+// Ошибки хранилища возвращаются администратору без изменений.
+func TestPermissionMiddlewareInnerError(t *testing.T) {
+	inner := &mock.Mock{
+		DeleteTaskFunc: func(ctx context.Context, taskId int) error {
+			return storage.ErrNotFound
+		},
+	}
+	err := New(inner).DeleteTask(WithRole(context.Background(), storage.RoleAdmin), 1)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("DeleteTask() error = %v, want ErrNotFound", err)
+	}
+}
+
+// Непроверяемые методы доступны без роли.
+func TestPermissionMiddlewarePassThrough(t *testing.T) {
+	inner := &mock.Mock{}
+	_, err := New(inner).AddTask(context.Background(), storage.Task{Title: "task"})
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	if n := len(inner.CallsTo("AddTask")); n != 1 {
+		t.Errorf("inner AddTask() called %d times, want 1", n)
+	}
+}
@@ -0,0 +1,1393 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Коды ошибок PostgreSQL, после которых транзакцию можно повторить.
+const (
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+)
+
+// Retrier оборачивает storage.Interface и повторяет вызовы,
+// завершившиеся временной ошибкой БД, с экспоненциальной задержкой.
+// Прочие ошибки возвращаются без повторов.
+type Retrier struct {
+	inner       storage.Interface
+	maxAttempts int
+	base        time.Duration
+}
+
+// Конструктор, принимает оборачиваемое хранилище, максимальное
+// количество попыток и задержку перед первым повтором.
+// Каждая следующая задержка вдвое больше предыдущей.
+func New(inner storage.Interface, maxAttempts int, base time.Duration) *Retrier {
+	r := Retrier{
+		inner:       inner,
+		maxAttempts: maxAttempts,
+		base:        base,
+	}
+	return &r
+}
+
+// retryable сообщает, является ли ошибка временной.
+func retryable(err error) bool {
+	if pgconn.SafeToRetry(err) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == serializationFailure || pgErr.Code == deadlockDetected
+	}
+	return false
+}
+
+// do выполняет fn, повторяя её при временных ошибках,
+// пока не исчерпаны попытки или не отменён контекст.
+func (r *Retrier) do(ctx context.Context, fn func() error) error {
+	delay := r.base
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= r.maxAttempts || !retryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// Tasks повторяет вызов Tasks при временных ошибках.
+func (r *Retrier) Tasks(ctx context.Context) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.Tasks(ctx)
+		return err
+	})
+	return res, err
+}
+
+// TasksIter не повторяется: при повторе fn была бы вызвана
+// для уже обработанных задач.
+func (r *Retrier) TasksIter(ctx context.Context, fn func(t storage.Task) error) error {
+	return r.inner.TasksIter(ctx, fn)
+}
+
+// TasksList повторяет вызов TasksList при временных ошибках.
+func (r *Retrier) TasksList(ctx context.Context, opts storage.ListOptions) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TasksList(ctx, opts)
+		return err
+	})
+	return res, err
+}
+
+// TotalCount повторяет вызов TotalCount при временных ошибках.
+func (r *Retrier) TotalCount(ctx context.Context) (int, error) {
+	var res int
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TotalCount(ctx)
+		return err
+	})
+	return res, err
+}
+
+// TasksAfter повторяет вызов TasksAfter при временных ошибках.
+func (r *Retrier) TasksAfter(ctx context.Context, afterID int, limit int) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TasksAfter(ctx, afterID, limit)
+		return err
+	})
+	return res, err
+}
+
+// TaskCount повторяет вызов TaskCount при временных ошибках.
+func (r *Retrier) TaskCount(ctx context.Context) (int, error) {
+	var res int
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TaskCount(ctx)
+		return err
+	})
+	return res, err
+}
+
+// TaskCountByAuthor повторяет вызов TaskCountByAuthor при временных ошибках.
+func (r *Retrier) TaskCountByAuthor(ctx context.Context, authorID int) (int, error) {
+	var res int
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TaskCountByAuthor(ctx, authorID)
+		return err
+	})
+	return res, err
+}
+
+// TaskCountByLabel повторяет вызов TaskCountByLabel при временных ошибках.
+func (r *Retrier) TaskCountByLabel(ctx context.Context, labelID int) (int, error) {
+	var res int
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TaskCountByLabel(ctx, labelID)
+		return err
+	})
+	return res, err
+}
+
+// TaskById повторяет вызов TaskById при временных ошибках.
+func (r *Retrier) TaskById(ctx context.Context, taskId int) (*storage.Task, error) {
+	var res *storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TaskById(ctx, taskId)
+		return err
+	})
+	return res, err
+}
+
+// TasksByAuthor повторяет вызов TasksByAuthor при временных ошибках.
+func (r *Retrier) TasksByAuthor(ctx context.Context, authorId int) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TasksByAuthor(ctx, authorId)
+		return err
+	})
+	return res, err
+}
+
+// TasksByAssignee повторяет вызов TasksByAssignee при временных ошибках.
+func (r *Retrier) TasksByAssignee(ctx context.Context, assigneeID int) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TasksByAssignee(ctx, assigneeID)
+		return err
+	})
+	return res, err
+}
+
+// TasksByLabel повторяет вызов TasksByLabel при временных ошибках.
+func (r *Retrier) TasksByLabel(ctx context.Context, labelId int) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TasksByLabel(ctx, labelId)
+		return err
+	})
+	return res, err
+}
+
+// TasksWithLabels повторяет вызов TasksWithLabels при временных ошибках.
+func (r *Retrier) TasksWithLabels(ctx context.Context) ([]storage.TaskWithLabels, error) {
+	var res []storage.TaskWithLabels
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TasksWithLabels(ctx)
+		return err
+	})
+	return res, err
+}
+
+// TasksWithUsers повторяет вызов TasksWithUsers при временных ошибках.
+func (r *Retrier) TasksWithUsers(ctx context.Context) ([]storage.TaskWithUsers, error) {
+	var res []storage.TaskWithUsers
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TasksWithUsers(ctx)
+		return err
+	})
+	return res, err
+}
+
+// FilterTasks повторяет вызов FilterTasks при временных ошибках.
+func (r *Retrier) FilterTasks(ctx context.Context, f storage.TaskFilter) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.FilterTasks(ctx, f)
+		return err
+	})
+	return res, err
+}
+
+// TasksByStatus повторяет вызов TasksByStatus при временных ошибках.
+func (r *Retrier) TasksByStatus(ctx context.Context, s storage.Status) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TasksByStatus(ctx, s)
+		return err
+	})
+	return res, err
+}
+
+// TasksByPriority повторяет вызов TasksByPriority при временных ошибках.
+func (r *Retrier) TasksByPriority(ctx context.Context, p storage.Priority) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TasksByPriority(ctx, p)
+		return err
+	})
+	return res, err
+}
+
+// TasksOrderedByPriority повторяет вызов TasksOrderedByPriority при временных ошибках.
+func (r *Retrier) TasksOrderedByPriority(ctx context.Context) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TasksOrderedByPriority(ctx)
+		return err
+	})
+	return res, err
+}
+
+// TasksOverdue повторяет вызов TasksOverdue при временных ошибках.
+func (r *Retrier) TasksOverdue(ctx context.Context, now int64) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TasksOverdue(ctx, now)
+		return err
+	})
+	return res, err
+}
+
+// TasksDueBetween повторяет вызов TasksDueBetween при временных ошибках.
+func (r *Retrier) TasksDueBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TasksDueBetween(ctx, from, to)
+		return err
+	})
+	return res, err
+}
+
+// AddTask повторяет вызов AddTask при временных ошибках.
+func (r *Retrier) AddTask(ctx context.Context, task storage.Task) (int, error) {
+	var res int
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.AddTask(ctx, task)
+		return err
+	})
+	return res, err
+}
+
+// AddTaskWithLabels повторяет вызов AddTaskWithLabels при временных ошибках.
+func (r *Retrier) AddTaskWithLabels(ctx context.Context, t storage.Task, labelIDs []int) (int, error) {
+	var res int
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.AddTaskWithLabels(ctx, t, labelIDs)
+		return err
+	})
+	return res, err
+}
+
+// AddTasks повторяет вызов AddTasks при временных ошибках.
+func (r *Retrier) AddTasks(ctx context.Context, tasks []storage.Task) ([]int, error) {
+	var res []int
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.AddTasks(ctx, tasks)
+		return err
+	})
+	return res, err
+}
+
+// AddTasksBatch повторяет вызов AddTasksBatch при временных ошибках.
+func (r *Retrier) AddTasksBatch(ctx context.Context, tasks []storage.Task) ([]int, error) {
+	var res []int
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.AddTasksBatch(ctx, tasks)
+		return err
+	})
+	return res, err
+}
+
+// ImportTasks повторяет вызов ImportTasks при временных ошибках.
+func (r *Retrier) ImportTasks(ctx context.Context, tasks []storage.Task) error {
+	return r.do(ctx, func() error {
+		return r.inner.ImportTasks(ctx, tasks)
+	})
+}
+
+// UpdateTask повторяет вызов UpdateTask при временных ошибках.
+func (r *Retrier) UpdateTask(ctx context.Context, task storage.Task) error {
+	return r.do(ctx, func() error {
+		return r.inner.UpdateTask(ctx, task)
+	})
+}
+
+// UpsertTask повторяет вызов UpsertTask при временных ошибках.
+func (r *Retrier) UpsertTask(ctx context.Context, t storage.Task) (int, error) {
+	var res int
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.UpsertTask(ctx, t)
+		return err
+	})
+	return res, err
+}
+
+// PartialUpdateTask повторяет вызов PartialUpdateTask при временных ошибках.
+func (r *Retrier) PartialUpdateTask(ctx context.Context, taskID int, patch storage.TaskPatch) error {
+	return r.do(ctx, func() error {
+		return r.inner.PartialUpdateTask(ctx, taskID, patch)
+	})
+}
+
+// UpdateTaskStatus повторяет вызов UpdateTaskStatus при временных ошибках.
+func (r *Retrier) UpdateTaskStatus(ctx context.Context, taskID int, s storage.Status) error {
+	return r.do(ctx, func() error {
+		return r.inner.UpdateTaskStatus(ctx, taskID, s)
+	})
+}
+
+// DeleteTask повторяет вызов DeleteTask при временных ошибках.
+func (r *Retrier) DeleteTask(ctx context.Context, taskId int) error {
+	return r.do(ctx, func() error {
+		return r.inner.DeleteTask(ctx, taskId)
+	})
+}
+
+// DeleteTasks повторяет вызов DeleteTasks при временных ошибках.
+func (r *Retrier) DeleteTasks(ctx context.Context, taskIDs []int) error {
+	return r.do(ctx, func() error {
+		return r.inner.DeleteTasks(ctx, taskIDs)
+	})
+}
+
+// TasksIncludingDeleted повторяет вызов TasksIncludingDeleted при временных ошибках.
+func (r *Retrier) TasksIncludingDeleted(ctx context.Context) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TasksIncludingDeleted(ctx)
+		return err
+	})
+	return res, err
+}
+
+// UndeleteTask повторяет вызов UndeleteTask при временных ошибках.
+func (r *Retrier) UndeleteTask(ctx context.Context, taskID int) error {
+	return r.do(ctx, func() error {
+		return r.inner.UndeleteTask(ctx, taskID)
+	})
+}
+
+// AddUser повторяет вызов AddUser при временных ошибках.
+func (r *Retrier) AddUser(ctx context.Context, u storage.User) (int, error) {
+	var res int
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.AddUser(ctx, u)
+		return err
+	})
+	return res, err
+}
+
+// Users повторяет вызов Users при временных ошибках.
+func (r *Retrier) Users(ctx context.Context) ([]storage.User, error) {
+	var res []storage.User
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.Users(ctx)
+		return err
+	})
+	return res, err
+}
+
+// UserByID повторяет вызов UserByID при временных ошибках.
+func (r *Retrier) UserByID(ctx context.Context, userID int) (*storage.User, error) {
+	var res *storage.User
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.UserByID(ctx, userID)
+		return err
+	})
+	return res, err
+}
+
+// UpdateUser повторяет вызов UpdateUser при временных ошибках.
+func (r *Retrier) UpdateUser(ctx context.Context, u storage.User) error {
+	return r.do(ctx, func() error {
+		return r.inner.UpdateUser(ctx, u)
+	})
+}
+
+// DeleteUser повторяет вызов DeleteUser при временных ошибках.
+func (r *Retrier) DeleteUser(ctx context.Context, userID int) error {
+	return r.do(ctx, func() error {
+		return r.inner.DeleteUser(ctx, userID)
+	})
+}
+
+// AddLabel повторяет вызов AddLabel при временных ошибках.
+func (r *Retrier) AddLabel(ctx context.Context, l storage.Label) (int, error) {
+	var res int
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.AddLabel(ctx, l)
+		return err
+	})
+	return res, err
+}
+
+// Labels повторяет вызов Labels при временных ошибках.
+func (r *Retrier) Labels(ctx context.Context) ([]storage.Label, error) {
+	var res []storage.Label
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.Labels(ctx)
+		return err
+	})
+	return res, err
+}
+
+// LabelByID повторяет вызов LabelByID при временных ошибках.
+func (r *Retrier) LabelByID(ctx context.Context, labelID int) (*storage.Label, error) {
+	var res *storage.Label
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.LabelByID(ctx, labelID)
+		return err
+	})
+	return res, err
+}
+
+// UpdateLabel повторяет вызов UpdateLabel при временных ошибках.
+func (r *Retrier) UpdateLabel(ctx context.Context, l storage.Label) error {
+	return r.do(ctx, func() error {
+		return r.inner.UpdateLabel(ctx, l)
+	})
+}
+
+// DeleteLabel повторяет вызов DeleteLabel при временных ошибках.
+func (r *Retrier) DeleteLabel(ctx context.Context, labelID int) error {
+	return r.do(ctx, func() error {
+		return r.inner.DeleteLabel(ctx, labelID)
+	})
+}
+
+// AssignLabel повторяет вызов AssignLabel при временных ошибках.
+func (r *Retrier) AssignLabel(ctx context.Context, taskID, labelID int) error {
+	return r.do(ctx, func() error {
+		return r.inner.AssignLabel(ctx, taskID, labelID)
+	})
+}
+
+// RemoveLabel повторяет вызов RemoveLabel при временных ошибках.
+func (r *Retrier) RemoveLabel(ctx context.Context, taskID, labelID int) error {
+	return r.do(ctx, func() error {
+		return r.inner.RemoveLabel(ctx, taskID, labelID)
+	})
+}
+
+// AddComment повторяет вызов AddComment при временных ошибках.
+func (r *Retrier) AddComment(ctx context.Context, c storage.Comment) (int, error) {
+	var res int
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.AddComment(ctx, c)
+		return err
+	})
+	return res, err
+}
+
+// CommentsByTask повторяет вызов CommentsByTask при временных ошибках.
+func (r *Retrier) CommentsByTask(ctx context.Context, taskID int) ([]storage.Comment, error) {
+	var res []storage.Comment
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.CommentsByTask(ctx, taskID)
+		return err
+	})
+	return res, err
+}
+
+// UpdateComment повторяет вызов UpdateComment при временных ошибках.
+func (r *Retrier) UpdateComment(ctx context.Context, c storage.Comment) error {
+	return r.do(ctx, func() error {
+		return r.inner.UpdateComment(ctx, c)
+	})
+}
+
+// DeleteComment повторяет вызов DeleteComment при временных ошибках.
+func (r *Retrier) DeleteComment(ctx context.Context, commentID int) error {
+	return r.do(ctx, func() error {
+		return r.inner.DeleteComment(ctx, commentID)
+	})
+}
+
+// LogTime повторяет вызов LogTime при временных ошибках.
+func (r *Retrier) LogTime(ctx context.Context, e storage.TimeEntry) (int, error) {
+	var res int
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.LogTime(ctx, e)
+		return err
+	})
+	return res, err
+}
+
+// TimeEntriesByTask повторяет вызов TimeEntriesByTask при временных ошибках.
+func (r *Retrier) TimeEntriesByTask(ctx context.Context, taskID int) ([]storage.TimeEntry, error) {
+	var res []storage.TimeEntry
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TimeEntriesByTask(ctx, taskID)
+		return err
+	})
+	return res, err
+}
+
+// TimeEntriesByUser повторяет вызов TimeEntriesByUser при временных ошибках.
+func (r *Retrier) TimeEntriesByUser(ctx context.Context, userID int) ([]storage.TimeEntry, error) {
+	var res []storage.TimeEntry
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TimeEntriesByUser(ctx, userID)
+		return err
+	})
+	return res, err
+}
+
+// TotalMinutesByTask повторяет вызов TotalMinutesByTask при временных ошибках.
+func (r *Retrier) TotalMinutesByTask(ctx context.Context, taskID int) (int, error) {
+	var res int
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TotalMinutesByTask(ctx, taskID)
+		return err
+	})
+	return res, err
+}
+
+// AddChecklistItem повторяет вызов AddChecklistItem при временных ошибках.
+func (r *Retrier) AddChecklistItem(ctx context.Context, item storage.ChecklistItem) (int, error) {
+	var res int
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.AddChecklistItem(ctx, item)
+		return err
+	})
+	return res, err
+}
+
+// ChecklistByTask повторяет вызов ChecklistByTask при временных ошибках.
+func (r *Retrier) ChecklistByTask(ctx context.Context, taskID int) ([]storage.ChecklistItem, error) {
+	var res []storage.ChecklistItem
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.ChecklistByTask(ctx, taskID)
+		return err
+	})
+	return res, err
+}
+
+// UpdateChecklistItem повторяет вызов UpdateChecklistItem при временных ошибках.
+func (r *Retrier) UpdateChecklistItem(ctx context.Context, item storage.ChecklistItem) error {
+	return r.do(ctx, func() error {
+		return r.inner.UpdateChecklistItem(ctx, item)
+	})
+}
+
+// DeleteChecklistItem повторяет вызов DeleteChecklistItem при временных ошибках.
+func (r *Retrier) DeleteChecklistItem(ctx context.Context, itemID int) error {
+	return r.do(ctx, func() error {
+		return r.inner.DeleteChecklistItem(ctx, itemID)
+	})
+}
+
+// ReorderChecklist повторяет вызов ReorderChecklist при временных ошибках.
+func (r *Retrier) ReorderChecklist(ctx context.Context, taskID int, orderedIDs []int) error {
+	return r.do(ctx, func() error {
+		return r.inner.ReorderChecklist(ctx, taskID, orderedIDs)
+	})
+}
+
+// AddProject повторяет вызов AddProject при временных ошибках.
+func (r *Retrier) AddProject(ctx context.Context, p storage.Project) (int, error) {
+	var res int
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.AddProject(ctx, p)
+		return err
+	})
+	return res, err
+}
+
+// Projects повторяет вызов Projects при временных ошибках.
+func (r *Retrier) Projects(ctx context.Context) ([]storage.Project, error) {
+	var res []storage.Project
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.Projects(ctx)
+		return err
+	})
+	return res, err
+}
+
+// ProjectByID повторяет вызов ProjectByID при временных ошибках.
+func (r *Retrier) ProjectByID(ctx context.Context, projectID int) (*storage.Project, error) {
+	var res *storage.Project
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.ProjectByID(ctx, projectID)
+		return err
+	})
+	return res, err
+}
+
+// UpdateProject повторяет вызов UpdateProject при временных ошибках.
+func (r *Retrier) UpdateProject(ctx context.Context, p storage.Project) error {
+	return r.do(ctx, func() error {
+		return r.inner.UpdateProject(ctx, p)
+	})
+}
+
+// DeleteProject повторяет вызов DeleteProject при временных ошибках.
+func (r *Retrier) DeleteProject(ctx context.Context, projectID int, cascadeDelete bool) error {
+	return r.do(ctx, func() error {
+		return r.inner.DeleteProject(ctx, projectID, cascadeDelete)
+	})
+}
+
+// TasksByProject повторяет вызов TasksByProject при временных ошибках.
+func (r *Retrier) TasksByProject(ctx context.Context, projectID int) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TasksByProject(ctx, projectID)
+		return err
+	})
+	return res, err
+}
+
+// AddDependency повторяет вызов AddDependency при временных ошибках.
+func (r *Retrier) AddDependency(ctx context.Context, taskID, dependsOnID int) error {
+	return r.do(ctx, func() error {
+		return r.inner.AddDependency(ctx, taskID, dependsOnID)
+	})
+}
+
+// RemoveDependency повторяет вызов RemoveDependency при временных ошибках.
+func (r *Retrier) RemoveDependency(ctx context.Context, taskID, dependsOnID int) error {
+	return r.do(ctx, func() error {
+		return r.inner.RemoveDependency(ctx, taskID, dependsOnID)
+	})
+}
+
+// DependenciesOf повторяет вызов DependenciesOf при временных ошибках.
+func (r *Retrier) DependenciesOf(ctx context.Context, taskID int) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.DependenciesOf(ctx, taskID)
+		return err
+	})
+	return res, err
+}
+
+// BlockedBy повторяет вызов BlockedBy при временных ошибках.
+func (r *Retrier) BlockedBy(ctx context.Context, taskID int) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.BlockedBy(ctx, taskID)
+		return err
+	})
+	return res, err
+}
+
+// BeginTx повторяет вызов BeginTx при временных ошибках.
+func (r *Retrier) BeginTx(ctx context.Context) (storage.TxStorage, error) {
+	var res storage.TxStorage
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.BeginTx(ctx)
+		return err
+	})
+	return res, err
+}
+
+// AuditLog повторяет вызов AuditLog при временных ошибках.
+func (r *Retrier) AuditLog(ctx context.Context, taskID int) ([]storage.AuditEntry, error) {
+	var res []storage.AuditEntry
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.AuditLog(ctx, taskID)
+		return err
+	})
+	return res, err
+}
+
+// RecordChange повторяет вызов RecordChange при временных ошибках.
+func (r *Retrier) RecordChange(ctx context.Context, e storage.AuditEntry) error {
+	return r.do(ctx, func() error {
+		return r.inner.RecordChange(ctx, e)
+	})
+}
+
+// SearchTasks повторяет вызов SearchTasks при временных ошибках.
+func (r *Retrier) SearchTasks(ctx context.Context, query string, limit int) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.SearchTasks(ctx, query, limit)
+		return err
+	})
+	return res, err
+}
+
+// TasksByMetadataKey повторяет вызов TasksByMetadataKey при временных ошибках.
+func (r *Retrier) TasksByMetadataKey(ctx context.Context, key, value string) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TasksByMetadataKey(ctx, key, value)
+		return err
+	})
+	return res, err
+}
+
+// AssignmentHistory повторяет вызов AssignmentHistory при временных ошибках.
+func (r *Retrier) AssignmentHistory(ctx context.Context, taskID int) ([]storage.AssignmentEvent, error) {
+	var res []storage.AssignmentEvent
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.AssignmentHistory(ctx, taskID)
+		return err
+	})
+	return res, err
+}
+
+// RecordAssignment повторяет вызов RecordAssignment при временных ошибках.
+func (r *Retrier) RecordAssignment(ctx context.Context, e storage.AssignmentEvent) error {
+	return r.do(ctx, func() error {
+		return r.inner.RecordAssignment(ctx, e)
+	})
+}
+
+// AddRecurrenceRule повторяет вызов AddRecurrenceRule при временных ошибках.
+func (r *Retrier) AddRecurrenceRule(ctx context.Context, rule storage.RecurrenceRule) error {
+	return r.do(ctx, func() error {
+		return r.inner.AddRecurrenceRule(ctx, rule)
+	})
+}
+
+// RecurrenceRuleByTask повторяет вызов RecurrenceRuleByTask при временных ошибках.
+func (r *Retrier) RecurrenceRuleByTask(ctx context.Context, taskID int) (*storage.RecurrenceRule, error) {
+	var res *storage.RecurrenceRule
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.RecurrenceRuleByTask(ctx, taskID)
+		return err
+	})
+	return res, err
+}
+
+// UpdateRecurrenceRule повторяет вызов UpdateRecurrenceRule при временных ошибках.
+func (r *Retrier) UpdateRecurrenceRule(ctx context.Context, rule storage.RecurrenceRule) error {
+	return r.do(ctx, func() error {
+		return r.inner.UpdateRecurrenceRule(ctx, rule)
+	})
+}
+
+// DeleteRecurrenceRule повторяет вызов DeleteRecurrenceRule при временных ошибках.
+func (r *Retrier) DeleteRecurrenceRule(ctx context.Context, taskID int) error {
+	return r.do(ctx, func() error {
+		return r.inner.DeleteRecurrenceRule(ctx, taskID)
+	})
+}
+
+// DueRecurrences повторяет вызов DueRecurrences при временных ошибках.
+func (r *Retrier) DueRecurrences(ctx context.Context, now int64) ([]storage.RecurrenceRule, error) {
+	var res []storage.RecurrenceRule
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.DueRecurrences(ctx, now)
+		return err
+	})
+	return res, err
+}
+
+// SpawnRecurringTask повторяет вызов SpawnRecurringTask при временных ошибках.
+func (r *Retrier) SpawnRecurringTask(ctx context.Context, rule storage.RecurrenceRule) (int, error) {
+	var res int
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.SpawnRecurringTask(ctx, rule)
+		return err
+	})
+	return res, err
+}
+
+// ArchiveTask повторяет вызов ArchiveTask при временных ошибках.
+func (r *Retrier) ArchiveTask(ctx context.Context, taskID int) error {
+	return r.do(ctx, func() error {
+		return r.inner.ArchiveTask(ctx, taskID)
+	})
+}
+
+// ArchivedTasks повторяет вызов ArchivedTasks при временных ошибках.
+func (r *Retrier) ArchivedTasks(ctx context.Context) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.ArchivedTasks(ctx)
+		return err
+	})
+	return res, err
+}
+
+// UnarchiveTask повторяет вызов UnarchiveTask при временных ошибках.
+func (r *Retrier) UnarchiveTask(ctx context.Context, taskID int) error {
+	return r.do(ctx, func() error {
+		return r.inner.UnarchiveTask(ctx, taskID)
+	})
+}
+
+// TasksByLabels повторяет вызов TasksByLabels при временных ошибках.
+func (r *Retrier) TasksByLabels(ctx context.Context, labelIDs []int, mode storage.LabelFilterMode) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TasksByLabels(ctx, labelIDs, mode)
+		return err
+	})
+	return res, err
+}
+
+// TasksOpenedBetween повторяет вызов TasksOpenedBetween при временных ошибках.
+func (r *Retrier) TasksOpenedBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TasksOpenedBetween(ctx, from, to)
+		return err
+	})
+	return res, err
+}
+
+// TasksClosedBetween повторяет вызов TasksClosedBetween при временных ошибках.
+func (r *Retrier) TasksClosedBetween(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TasksClosedBetween(ctx, from, to)
+		return err
+	})
+	return res, err
+}
+
+// AddSprint повторяет вызов AddSprint при временных ошибках.
+func (r *Retrier) AddSprint(ctx context.Context, sp storage.Sprint) (int, error) {
+	var res int
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.AddSprint(ctx, sp)
+		return err
+	})
+	return res, err
+}
+
+// SprintByID повторяет вызов SprintByID при временных ошибках.
+func (r *Retrier) SprintByID(ctx context.Context, sprintID int) (*storage.Sprint, error) {
+	var res *storage.Sprint
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.SprintByID(ctx, sprintID)
+		return err
+	})
+	return res, err
+}
+
+// Sprints повторяет вызов Sprints при временных ошибках.
+func (r *Retrier) Sprints(ctx context.Context) ([]storage.Sprint, error) {
+	var res []storage.Sprint
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.Sprints(ctx)
+		return err
+	})
+	return res, err
+}
+
+// UpdateSprint повторяет вызов UpdateSprint при временных ошибках.
+func (r *Retrier) UpdateSprint(ctx context.Context, sp storage.Sprint) error {
+	return r.do(ctx, func() error {
+		return r.inner.UpdateSprint(ctx, sp)
+	})
+}
+
+// DeleteSprint повторяет вызов DeleteSprint при временных ошибках.
+func (r *Retrier) DeleteSprint(ctx context.Context, sprintID int) error {
+	return r.do(ctx, func() error {
+		return r.inner.DeleteSprint(ctx, sprintID)
+	})
+}
+
+// AssignTaskToSprint повторяет вызов AssignTaskToSprint при временных ошибках.
+func (r *Retrier) AssignTaskToSprint(ctx context.Context, taskID, sprintID int) error {
+	return r.do(ctx, func() error {
+		return r.inner.AssignTaskToSprint(ctx, taskID, sprintID)
+	})
+}
+
+// RemoveTaskFromSprint повторяет вызов RemoveTaskFromSprint при временных ошибках.
+func (r *Retrier) RemoveTaskFromSprint(ctx context.Context, taskID, sprintID int) error {
+	return r.do(ctx, func() error {
+		return r.inner.RemoveTaskFromSprint(ctx, taskID, sprintID)
+	})
+}
+
+// TasksBySprint повторяет вызов TasksBySprint при временных ошибках.
+func (r *Retrier) TasksBySprint(ctx context.Context, sprintID int) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TasksBySprint(ctx, sprintID)
+		return err
+	})
+	return res, err
+}
+
+// SprintVelocity повторяет вызов SprintVelocity при временных ошибках.
+func (r *Retrier) SprintVelocity(ctx context.Context, sprintID int) (int, error) {
+	var res int
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.SprintVelocity(ctx, sprintID)
+		return err
+	})
+	return res, err
+}
+
+// BurndownData повторяет вызов BurndownData при временных ошибках.
+func (r *Retrier) BurndownData(ctx context.Context, sprintID int, buckets int) ([]storage.BurndownPoint, error) {
+	var res []storage.BurndownPoint
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.BurndownData(ctx, sprintID, buckets)
+		return err
+	})
+	return res, err
+}
+
+// UserWorkloads повторяет вызов UserWorkloads при временных ошибках.
+func (r *Retrier) UserWorkloads(ctx context.Context) ([]storage.UserWorkload, error) {
+	var res []storage.UserWorkload
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.UserWorkloads(ctx)
+		return err
+	})
+	return res, err
+}
+
+// UserWorkload повторяет вызов UserWorkload при временных ошибках.
+func (r *Retrier) UserWorkload(ctx context.Context, userID int) (*storage.UserWorkload, error) {
+	var res *storage.UserWorkload
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.UserWorkload(ctx, userID)
+		return err
+	})
+	return res, err
+}
+
+// TaskStats повторяет вызов TaskStats при временных ошибках.
+func (r *Retrier) TaskStats(ctx context.Context) (*storage.TaskStats, error) {
+	var res *storage.TaskStats
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TaskStats(ctx)
+		return err
+	})
+	return res, err
+}
+
+// LabelStats повторяет вызов LabelStats при временных ошибках.
+func (r *Retrier) LabelStats(ctx context.Context) ([]storage.LabelStat, error) {
+	var res []storage.LabelStat
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.LabelStats(ctx)
+		return err
+	})
+	return res, err
+}
+
+// TopLabels повторяет вызов TopLabels при временных ошибках.
+func (r *Retrier) TopLabels(ctx context.Context, n int) ([]storage.LabelStat, error) {
+	var res []storage.LabelStat
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TopLabels(ctx, n)
+		return err
+	})
+	return res, err
+}
+
+// RecordActivity повторяет вызов RecordActivity при временных ошибках.
+func (r *Retrier) RecordActivity(ctx context.Context, e storage.ActivityEvent) error {
+	return r.do(ctx, func() error {
+		return r.inner.RecordActivity(ctx, e)
+	})
+}
+
+// ActivityFeed повторяет вызов ActivityFeed при временных ошибках.
+func (r *Retrier) ActivityFeed(ctx context.Context, taskID int, limit int) ([]storage.ActivityEvent, error) {
+	var res []storage.ActivityEvent
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.ActivityFeed(ctx, taskID, limit)
+		return err
+	})
+	return res, err
+}
+
+// GlobalActivityFeed повторяет вызов GlobalActivityFeed при временных ошибках.
+func (r *Retrier) GlobalActivityFeed(ctx context.Context, limit int) ([]storage.ActivityEvent, error) {
+	var res []storage.ActivityEvent
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.GlobalActivityFeed(ctx, limit)
+		return err
+	})
+	return res, err
+}
+
+// AppendEvents повторяет вызов AppendEvents при временных ошибках.
+func (r *Retrier) AppendEvents(ctx context.Context, streamID string, expectedSeq int64, events []storage.Event) error {
+	return r.do(ctx, func() error {
+		return r.inner.AppendEvents(ctx, streamID, expectedSeq, events)
+	})
+}
+
+// LoadEvents повторяет вызов LoadEvents при временных ошибках.
+func (r *Retrier) LoadEvents(ctx context.Context, streamID string, fromSeq int64) ([]storage.Event, error) {
+	var res []storage.Event
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.LoadEvents(ctx, streamID, fromSeq)
+		return err
+	})
+	return res, err
+}
+
+// AppendOutbox повторяет вызов AppendOutbox при временных ошибках.
+func (r *Retrier) AppendOutbox(ctx context.Context, tx storage.TxStorage, msgs []storage.OutboxMessage) error {
+	return r.do(ctx, func() error {
+		return r.inner.AppendOutbox(ctx, tx, msgs)
+	})
+}
+
+// PendingOutbox повторяет вызов PendingOutbox при временных ошибках.
+func (r *Retrier) PendingOutbox(ctx context.Context, limit int) ([]storage.OutboxMessage, error) {
+	var res []storage.OutboxMessage
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.PendingOutbox(ctx, limit)
+		return err
+	})
+	return res, err
+}
+
+// MarkOutboxSent повторяет вызов MarkOutboxSent при временных ошибках.
+func (r *Retrier) MarkOutboxSent(ctx context.Context, ids []int64) error {
+	return r.do(ctx, func() error {
+		return r.inner.MarkOutboxSent(ctx, ids)
+	})
+}
+
+// TasksClosedInRange повторяет вызов TasksClosedInRange при временных ошибках.
+func (r *Retrier) TasksClosedInRange(ctx context.Context, from, to int64) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TasksClosedInRange(ctx, from, to)
+		return err
+	})
+	return res, err
+}
+
+// TasksUnassigned повторяет вызов TasksUnassigned при временных ошибках.
+func (r *Retrier) TasksUnassigned(ctx context.Context) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TasksUnassigned(ctx)
+		return err
+	})
+	return res, err
+}
+
+// AssignTask повторяет вызов AssignTask при временных ошибках.
+func (r *Retrier) AssignTask(ctx context.Context, taskID, userID int) error {
+	return r.do(ctx, func() error {
+		return r.inner.AssignTask(ctx, taskID, userID)
+	})
+}
+
+// TaskByExternalID повторяет вызов TaskByExternalID при временных ошибках.
+func (r *Retrier) TaskByExternalID(ctx context.Context, system, externalID string) (*storage.Task, error) {
+	var res *storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TaskByExternalID(ctx, system, externalID)
+		return err
+	})
+	return res, err
+}
+
+// SetNotificationPreference повторяет вызов SetNotificationPreference при временных ошибках.
+func (r *Retrier) SetNotificationPreference(ctx context.Context, p storage.NotificationPreference) error {
+	return r.do(ctx, func() error {
+		return r.inner.SetNotificationPreference(ctx, p)
+	})
+}
+
+// GetNotificationPreference повторяет вызов GetNotificationPreference при временных ошибках.
+func (r *Retrier) GetNotificationPreference(ctx context.Context, userID int) (*storage.NotificationPreference, error) {
+	var res *storage.NotificationPreference
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.GetNotificationPreference(ctx, userID)
+		return err
+	})
+	return res, err
+}
+
+// UsersSubscribedToTask повторяет вызов UsersSubscribedToTask при временных ошибках.
+func (r *Retrier) UsersSubscribedToTask(ctx context.Context, taskID int, event string) ([]storage.User, error) {
+	var res []storage.User
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.UsersSubscribedToTask(ctx, taskID, event)
+		return err
+	})
+	return res, err
+}
+
+// WatchTask повторяет вызов WatchTask при временных ошибках.
+func (r *Retrier) WatchTask(ctx context.Context, userID, taskID int) error {
+	return r.do(ctx, func() error {
+		return r.inner.WatchTask(ctx, userID, taskID)
+	})
+}
+
+// UnwatchTask повторяет вызов UnwatchTask при временных ошибках.
+func (r *Retrier) UnwatchTask(ctx context.Context, userID, taskID int) error {
+	return r.do(ctx, func() error {
+		return r.inner.UnwatchTask(ctx, userID, taskID)
+	})
+}
+
+// WatchersOfTask повторяет вызов WatchersOfTask при временных ошибках.
+func (r *Retrier) WatchersOfTask(ctx context.Context, taskID int) ([]storage.User, error) {
+	var res []storage.User
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.WatchersOfTask(ctx, taskID)
+		return err
+	})
+	return res, err
+}
+
+// CreateAPIKey повторяет вызов CreateAPIKey при временных ошибках.
+func (r *Retrier) CreateAPIKey(ctx context.Context, k storage.APIKey) (string, error) {
+	var res string
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.CreateAPIKey(ctx, k)
+		return err
+	})
+	return res, err
+}
+
+// APIKeyByKey повторяет вызов APIKeyByKey при временных ошибках.
+func (r *Retrier) APIKeyByKey(ctx context.Context, raw string) (*storage.APIKey, error) {
+	var res *storage.APIKey
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.APIKeyByKey(ctx, raw)
+		return err
+	})
+	return res, err
+}
+
+// RevokeAPIKey повторяет вызов RevokeAPIKey при временных ошибках.
+func (r *Retrier) RevokeAPIKey(ctx context.Context, keyID int) error {
+	return r.do(ctx, func() error {
+		return r.inner.RevokeAPIKey(ctx, keyID)
+	})
+}
+
+// APIKeysByUser повторяет вызов APIKeysByUser при временных ошибках.
+func (r *Retrier) APIKeysByUser(ctx context.Context, userID int) ([]storage.APIKey, error) {
+	var res []storage.APIKey
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.APIKeysByUser(ctx, userID)
+		return err
+	})
+	return res, err
+}
+
+// AssignRole повторяет вызов AssignRole при временных ошибках.
+func (r *Retrier) AssignRole(ctx context.Context, userID int, role storage.Role) error {
+	return r.do(ctx, func() error {
+		return r.inner.AssignRole(ctx, userID, role)
+	})
+}
+
+// RoleOfUser повторяет вызов RoleOfUser при временных ошибках.
+func (r *Retrier) RoleOfUser(ctx context.Context, userID int) (storage.Role, error) {
+	var res storage.Role
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.RoleOfUser(ctx, userID)
+		return err
+	})
+	return res, err
+}
+
+// UsersWithRole повторяет вызов UsersWithRole при временных ошибках.
+func (r *Retrier) UsersWithRole(ctx context.Context, role storage.Role) ([]storage.User, error) {
+	var res []storage.User
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.UsersWithRole(ctx, role)
+		return err
+	})
+	return res, err
+}
+
+// ExportUserData повторяет вызов ExportUserData при временных ошибках.
+func (r *Retrier) ExportUserData(ctx context.Context, userID int) (*storage.UserDataExport, error) {
+	var res *storage.UserDataExport
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.ExportUserData(ctx, userID)
+		return err
+	})
+	return res, err
+}
+
+// EraseUser повторяет вызов EraseUser при временных ошибках.
+func (r *Retrier) EraseUser(ctx context.Context, userID int) error {
+	return r.do(ctx, func() error {
+		return r.inner.EraseUser(ctx, userID)
+	})
+}
+
+// StaleTasks повторяет вызов StaleTasks при временных ошибках.
+func (r *Retrier) StaleTasks(ctx context.Context, staleAfterSeconds int64, now int64) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.StaleTasks(ctx, staleAfterSeconds, now)
+		return err
+	})
+	return res, err
+}
+
+// MarkTaskStale повторяет вызов MarkTaskStale при временных ошибках.
+func (r *Retrier) MarkTaskStale(ctx context.Context, taskID int) error {
+	return r.do(ctx, func() error {
+		return r.inner.MarkTaskStale(ctx, taskID)
+	})
+}
+
+// SimilarTasks повторяет вызов SimilarTasks при временных ошибках.
+func (r *Retrier) SimilarTasks(ctx context.Context, title string, threshold float64) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.SimilarTasks(ctx, title, threshold)
+		return err
+	})
+	return res, err
+}
+
+// AddTaskLink повторяет вызов AddTaskLink при временных ошибках.
+func (r *Retrier) AddTaskLink(ctx context.Context, l storage.TaskLinkRecord) (int, error) {
+	var res int
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.AddTaskLink(ctx, l)
+		return err
+	})
+	return res, err
+}
+
+// TaskLinks повторяет вызов TaskLinks при временных ошибках.
+func (r *Retrier) TaskLinks(ctx context.Context, taskID int) ([]storage.TaskLinkRecord, error) {
+	var res []storage.TaskLinkRecord
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TaskLinks(ctx, taskID)
+		return err
+	})
+	return res, err
+}
+
+// DeleteTaskLink повторяет вызов DeleteTaskLink при временных ошибках.
+func (r *Retrier) DeleteTaskLink(ctx context.Context, linkID int) error {
+	return r.do(ctx, func() error {
+		return r.inner.DeleteTaskLink(ctx, linkID)
+	})
+}
+
+// RegisterWebhook повторяет вызов RegisterWebhook при временных ошибках.
+func (r *Retrier) RegisterWebhook(ctx context.Context, w storage.Webhook) (int, error) {
+	var res int
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.RegisterWebhook(ctx, w)
+		return err
+	})
+	return res, err
+}
+
+// Webhooks повторяет вызов Webhooks при временных ошибках.
+func (r *Retrier) Webhooks(ctx context.Context) ([]storage.Webhook, error) {
+	var res []storage.Webhook
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.Webhooks(ctx)
+		return err
+	})
+	return res, err
+}
+
+// WebhookByID повторяет вызов WebhookByID при временных ошибках.
+func (r *Retrier) WebhookByID(ctx context.Context, webhookID int) (*storage.Webhook, error) {
+	var res *storage.Webhook
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.WebhookByID(ctx, webhookID)
+		return err
+	})
+	return res, err
+}
+
+// UpdateWebhook повторяет вызов UpdateWebhook при временных ошибках.
+func (r *Retrier) UpdateWebhook(ctx context.Context, w storage.Webhook) error {
+	return r.do(ctx, func() error {
+		return r.inner.UpdateWebhook(ctx, w)
+	})
+}
+
+// DeleteWebhook повторяет вызов DeleteWebhook при временных ошибках.
+func (r *Retrier) DeleteWebhook(ctx context.Context, webhookID int) error {
+	return r.do(ctx, func() error {
+		return r.inner.DeleteWebhook(ctx, webhookID)
+	})
+}
+
+// LockTask повторяет вызов LockTask при временных ошибках.
+func (r *Retrier) LockTask(ctx context.Context, taskID int) error {
+	return r.do(ctx, func() error {
+		return r.inner.LockTask(ctx, taskID)
+	})
+}
+
+// UnlockTask повторяет вызов UnlockTask при временных ошибках.
+func (r *Retrier) UnlockTask(ctx context.Context, taskID int) error {
+	return r.do(ctx, func() error {
+		return r.inner.UnlockTask(ctx, taskID)
+	})
+}
+
+// UpdateTasksAssignee повторяет вызов UpdateTasksAssignee при временных ошибках.
+func (r *Retrier) UpdateTasksAssignee(ctx context.Context, taskIDs []int, assigneeID int) (int64, error) {
+	var res int64
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.UpdateTasksAssignee(ctx, taskIDs, assigneeID)
+		return err
+	})
+	return res, err
+}
+
+// UpdateTasksStatus повторяет вызов UpdateTasksStatus при временных ошибках.
+func (r *Retrier) UpdateTasksStatus(ctx context.Context, taskIDs []int, status storage.Status) (int64, error) {
+	var res int64
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.UpdateTasksStatus(ctx, taskIDs, status)
+		return err
+	})
+	return res, err
+}
+
+// TasksByTag повторяет вызов TasksByTag при временных ошибках.
+func (r *Retrier) TasksByTag(ctx context.Context, tag string) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TasksByTag(ctx, tag)
+		return err
+	})
+	return res, err
+}
+
+// AllTags повторяет вызов AllTags при временных ошибках.
+func (r *Retrier) AllTags(ctx context.Context) ([]string, error) {
+	var res []string
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.AllTags(ctx)
+		return err
+	})
+	return res, err
+}
+
+// TasksByRank повторяет вызов TasksByRank при временных ошибках.
+func (r *Retrier) TasksByRank(ctx context.Context) ([]storage.Task, error) {
+	var res []storage.Task
+	err := r.do(ctx, func() (err error) {
+		res, err = r.inner.TasksByRank(ctx)
+		return err
+	})
+	return res, err
+}
+
+// UpdateTaskRank повторяет вызов UpdateTaskRank при временных ошибках.
+func (r *Retrier) UpdateTaskRank(ctx context.Context, taskID int, rank float64) error {
+	return r.do(ctx, func() error {
+		return r.inner.UpdateTaskRank(ctx, taskID, rank)
+	})
+}
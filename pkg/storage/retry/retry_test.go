@@ -0,0 +1,98 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"skillfactory/30.8.1/pkg/storage/mock"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"serialization failure", &pgconn.PgError{Code: serializationFailure}, true},
+		{"deadlock", &pgconn.PgError{Code: deadlockDetected}, true},
+		{"unique violation", &pgconn.PgError{Code: "23505"}, false},
+		{"not found", storage.ErrNotFound, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryable(tt.err); got != tt.want {
+				t.Errorf("retryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newFailing возвращает заглушку, TaskById которой завершается ошибками
+// errs по очереди, а затем успешно, и счётчик вызовов.
+func newFailing(errs ...error) (*mock.Mock, *int) {
+	calls := 0
+	return &mock.Mock{
+		TaskByIdFunc: func(ctx context.Context, taskId int) (*storage.Task, error) {
+			calls++
+			if calls <= len(errs) {
+				return nil, errs[calls-1]
+			}
+			return &storage.Task{ID: taskId}, nil
+		},
+	}, &calls
+}
+
+func TestRetrier(t *testing.T) {
+	ctx := context.Background()
+	deadlock := &pgconn.PgError{Code: deadlockDetected}
+
+	tests := []struct {
+		name      string
+		errs      []error
+		wantErr   error
+		wantCalls int
+	}{
+		{"success", nil, nil, 1},
+		{"transient error", []error{deadlock, deadlock}, nil, 3},
+		{"attempts exhausted", []error{deadlock, deadlock, deadlock}, deadlock, 3},
+		{"permanent error", []error{storage.ErrNotFound}, storage.ErrNotFound, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner, calls := newFailing(tt.errs...)
+			r := New(inner, 3, time.Millisecond)
+
+			task, err := r.TaskById(ctx, 7)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("TaskById() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && task.ID != 7 {
+				t.Errorf("TaskById() = %+v, want task 7", task)
+			}
+			if *calls != tt.wantCalls {
+				t.Errorf("TaskById() made %d calls, want %d", *calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+// Отмена контекста прерывает ожидание перед повтором.
+func TestRetrierCanceled(t *testing.T) {
+	deadlock := &pgconn.PgError{Code: deadlockDetected}
+	inner, calls := newFailing(deadlock, deadlock)
+	r := New(inner, 3, time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := r.TaskById(ctx, 7)
+	if !errors.Is(err, deadlock) {
+		t.Errorf("TaskById() error = %v, want last error", err)
+	}
+	if *calls != 1 {
+		t.Errorf("TaskById() made %d calls, want 1", *calls)
+	}
+}
@@ -0,0 +1,211 @@
+// Пакет seed заполняет хранилище тестовыми данными для разработки.
+package seed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"skillfactory/30.8.1/pkg/storage"
+)
+
+// Атрибут, которым помечаются созданные задачи.
+// По нему повторное заполнение определяет, что данные уже есть.
+const (
+	markerKey   = "seed"
+	markerValue = "1"
+)
+
+var (
+	verbs   = []string{"Исправить", "Добавить", "Проверить", "Обновить", "Описать", "Оптимизировать"}
+	objects = []string{"авторизацию", "отчёт", "миграцию", "кэш", "документацию", "поиск", "экспорт", "уведомления"}
+	names   = []string{"Иван", "Мария", "Пётр", "Анна", "Олег", "Елена", "Сергей", "Ольга"}
+	labels  = []string{"bug", "feature", "backend", "frontend", "urgent", "docs", "tech-debt"}
+)
+
+// Seed создаёт n задач, n/3 пользователей и n/5 меток и назначает
+// задачам случайные метки, авторов и исполнителей. Данные псевдослучайны,
+// но зависят только от n. Если хранилище уже заполнено, ничего не делает.
+func Seed(ctx context.Context, db storage.Interface, n int) error {
+	if n < 0 {
+		return fmt.Errorf("%w: n must not be negative", storage.ErrInvalidArgument)
+	}
+
+	seeded, err := isSeeded(ctx, db)
+	if err != nil || seeded {
+		return err
+	}
+
+	rnd := rand.New(rand.NewSource(int64(n)))
+
+	userIDs := make([]int, 0, n/3)
+	for i := range n / 3 {
+		id, err := db.AddUser(ctx, storage.User{
+			Name: fmt.Sprintf("%s %d", names[rnd.Intn(len(names))], i+1),
+		})
+		if err != nil {
+			return err
+		}
+		userIDs = append(userIDs, id)
+	}
+
+	labelIDs := make([]int, 0, n/5)
+	for i := range n / 5 {
+		id, err := db.AddLabel(ctx, storage.Label{
+			Name: fmt.Sprintf("%s-%d", labels[i%len(labels)], i+1),
+		})
+		if err != nil {
+			return err
+		}
+		labelIDs = append(labelIDs, id)
+	}
+
+	for i := range n {
+		t := Task{
+			Title:   fmt.Sprintf("%s %s #%d", verbs[rnd.Intn(len(verbs))], objects[rnd.Intn(len(objects))], i+1),
+			Content: fmt.Sprintf("Тестовая задача %d.", i+1),
+		}
+		if len(userIDs) > 0 {
+			t.authorID = userIDs[rnd.Intn(len(userIDs))]
+			t.assignedID = userIDs[rnd.Intn(len(userIDs))]
+		}
+		// до двух разных меток на задачу
+		for _, j := range rnd.Perm(len(labelIDs))[:min(rnd.Intn(3), len(labelIDs))] {
+			t.labelIDs = append(t.labelIDs, labelIDs[j])
+		}
+
+		err = addTask(ctx, db, t)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Config - описание данных для SeedFrom.
+//
+//	{
+//	    "users": ["Иван", "Мария"],
+//	    "labels": ["bug", "docs"],
+//	    "tasks": [
+//	        {"title": "Задача", "content": "Текст", "author": "Иван", "labels": ["bug"]}
+//	    ]
+//	}
+type Config struct {
+	Users  []string `json:"users"`
+	Labels []string `json:"labels"`
+	Tasks  []Task   `json:"tasks"`
+}
+
+// Task - задача из описания данных.
+type Task struct {
+	Title    string   `json:"title"`
+	Content  string   `json:"content"`
+	Author   string   `json:"author"`   // имя пользователя из Config.Users
+	Assignee string   `json:"assignee"` // имя пользователя из Config.Users
+	Labels   []string `json:"labels"`   // имена меток из Config.Labels
+
+	authorID, assignedID int
+	labelIDs             []int
+}
+
+// SeedFrom заполняет хранилище данными, описанными в JSON (см. Config).
+// Если хранилище уже заполнено, ничего не делает.
+func SeedFrom(ctx context.Context, db storage.Interface, r io.Reader) error {
+	var cfg Config
+	err := json.NewDecoder(r).Decode(&cfg)
+	if err != nil {
+		return fmt.Errorf("%w: %v", storage.ErrInvalidArgument, err)
+	}
+
+	seeded, err := isSeeded(ctx, db)
+	if err != nil || seeded {
+		return err
+	}
+
+	users := make(map[string]int, len(cfg.Users))
+	for _, name := range cfg.Users {
+		users[name], err = db.AddUser(ctx, storage.User{Name: name})
+		if err != nil {
+			return err
+		}
+	}
+	labels := make(map[string]int, len(cfg.Labels))
+	for _, name := range cfg.Labels {
+		labels[name], err = db.AddLabel(ctx, storage.Label{Name: name})
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, t := range cfg.Tasks {
+		t.authorID, err = lookup(users, t.Author, "user")
+		if err != nil {
+			return fmt.Errorf("task %d: %w", i, err)
+		}
+		t.assignedID, err = lookup(users, t.Assignee, "user")
+		if err != nil {
+			return fmt.Errorf("task %d: %w", i, err)
+		}
+		for _, name := range t.Labels {
+			id, err := lookup(labels, name, "label")
+			if err != nil {
+				return fmt.Errorf("task %d: %w", i, err)
+			}
+			t.labelIDs = append(t.labelIDs, id)
+		}
+
+		err = addTask(ctx, db, t)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookup возвращает ID по имени. Пустое имя соответствует ID 0.
+func lookup(ids map[string]int, name, kind string) (int, error) {
+	if name == "" {
+		return 0, nil
+	}
+	id, ok := ids[name]
+	if !ok {
+		return 0, fmt.Errorf("%w: unknown %s %q", storage.ErrInvalidArgument, kind, name)
+	}
+	return id, nil
+}
+
+// isSeeded проверяет, есть ли в хранилище задачи с атрибутом-маркером.
+func isSeeded(ctx context.Context, db storage.Interface) (bool, error) {
+	tasks, err := db.TasksByMetadataKey(ctx, markerKey, markerValue)
+	if err != nil {
+		return false, err
+	}
+	return len(tasks) > 0, nil
+}
+
+// addTask создаёт задачу с меткой-маркером и назначает ей метки,
+// автора и исполнителя.
+func addTask(ctx context.Context, db storage.Interface, t Task) error {
+	id, err := db.AddTaskWithLabels(ctx, storage.Task{
+		Title:    t.Title,
+		Content:  t.Content,
+		Metadata: map[string]string{markerKey: markerValue},
+	}, t.labelIDs)
+	if err != nil {
+		return err
+	}
+	if t.authorID == 0 && t.assignedID == 0 {
+		return nil
+	}
+
+	// AddTask не записывает автора и исполнителя, поэтому они назначаются отдельно.
+	created, err := db.TaskById(ctx, id)
+	if err != nil {
+		return err
+	}
+	created.AuthorID = t.authorID
+	created.AssignedID = t.assignedID
+	return db.UpdateTask(ctx, *created)
+}
@@ -0,0 +1,152 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"skillfactory/30.8.1/pkg/storage/memory"
+	"strings"
+	"testing"
+)
+
+// counts возвращает количество задач, пользователей и меток в хранилище.
+func counts(t *testing.T, db storage.Interface) (tasks, users, labels int) {
+	t.Helper()
+	ctx := context.Background()
+	tt, err := db.Tasks(ctx)
+	if err != nil {
+		t.Fatalf("Tasks() error = %v", err)
+	}
+	uu, err := db.Users(ctx)
+	if err != nil {
+		t.Fatalf("Users() error = %v", err)
+	}
+	ll, err := db.Labels(ctx)
+	if err != nil {
+		t.Fatalf("Labels() error = %v", err)
+	}
+	return len(tt), len(uu), len(ll)
+}
+
+func TestSeed(t *testing.T) {
+	tests := []struct {
+		n                                int
+		wantTasks, wantUsers, wantLabels int
+	}{
+		{0, 0, 0, 0},
+		{2, 2, 0, 0},
+		{30, 30, 10, 6},
+		{100, 100, 33, 20},
+	}
+	for _, tt := range tests {
+		db := memory.New()
+		// повторный вызов не создаёт данные заново
+		for range 2 {
+			if err := Seed(context.Background(), db, tt.n); err != nil {
+				t.Fatalf("Seed(%d) error = %v", tt.n, err)
+			}
+		}
+		tasks, users, labels := counts(t, db)
+		if tasks != tt.wantTasks || users != tt.wantUsers || labels != tt.wantLabels {
+			t.Errorf("Seed(%d) created %d tasks, %d users, %d labels, want %d, %d, %d",
+				tt.n, tasks, users, labels, tt.wantTasks, tt.wantUsers, tt.wantLabels)
+		}
+	}
+}
+
+// Данные зависят только от n.
+func TestSeedDeterministic(t *testing.T) {
+	ctx := context.Background()
+	var titles [2][]string
+	for i := range titles {
+		db := memory.New()
+		if err := Seed(ctx, db, 20); err != nil {
+			t.Fatalf("Seed() error = %v", err)
+		}
+		tasks, err := db.Tasks(ctx)
+		if err != nil {
+			t.Fatalf("Tasks() error = %v", err)
+		}
+		for _, task := range tasks {
+			titles[i] = append(titles[i], task.Title)
+		}
+	}
+	if strings.Join(titles[0], "\n") != strings.Join(titles[1], "\n") {
+		t.Errorf("Seed() titles differ between runs:\n%v\n%v", titles[0], titles[1])
+	}
+}
+
+func TestSeedNegative(t *testing.T) {
+	if err := Seed(context.Background(), memory.New(), -1); !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("Seed(-1) error = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestSeedFrom(t *testing.T) {
+	ctx := context.Background()
+	const cfg = `{
+		"users": ["Иван", "Мария"],
+		"labels": ["bug", "docs"],
+		"tasks": [
+			{"title": "Первая", "content": "Текст", "author": "Иван", "assignee": "Мария", "labels": ["bug", "docs"]},
+			{"title": "Вторая", "labels": ["docs"]}
+		]
+	}`
+	db := memory.New()
+	for range 2 {
+		if err := SeedFrom(ctx, db, strings.NewReader(cfg)); err != nil {
+			t.Fatalf("SeedFrom() error = %v", err)
+		}
+	}
+	tasks, users, labels := counts(t, db)
+	if tasks != 2 || users != 2 || labels != 2 {
+		t.Fatalf("SeedFrom() created %d tasks, %d users, %d labels, want 2, 2, 2", tasks, users, labels)
+	}
+
+	all, err := db.Tasks(ctx)
+	if err != nil {
+		t.Fatalf("Tasks() error = %v", err)
+	}
+	first := all[0]
+	if first.Title != "Первая" || first.Content != "Текст" || first.AuthorID == 0 ||
+		first.AssignedID == 0 || first.AuthorID == first.AssignedID {
+		t.Errorf("first task = %+v, want author and assignee from config", first)
+	}
+	if all[1].AuthorID != 0 || all[1].AssignedID != 0 {
+		t.Errorf("second task = %+v, want no author and assignee", all[1])
+	}
+
+	ll, err := db.Labels(ctx)
+	if err != nil {
+		t.Fatalf("Labels() error = %v", err)
+	}
+	for _, l := range ll {
+		labeled, err := db.TasksByLabel(ctx, l.ID)
+		if err != nil {
+			t.Fatalf("TasksByLabel() error = %v", err)
+		}
+		want := map[string]int{"bug": 1, "docs": 2}[l.Name]
+		if len(labeled) != want {
+			t.Errorf("TasksByLabel(%s) = %d tasks, want %d", l.Name, len(labeled), want)
+		}
+	}
+}
+
+func TestSeedFromInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  string
+	}{
+		{"invalid json", `{`},
+		{"unknown author", `{"tasks": [{"title": "t", "author": "Иван"}]}`},
+		{"unknown label", `{"labels": ["bug"], "tasks": [{"title": "t", "labels": ["docs"]}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SeedFrom(context.Background(), memory.New(), strings.NewReader(tt.cfg))
+			if !errors.Is(err, storage.ErrInvalidArgument) {
+				t.Errorf("SeedFrom() error = %v, want ErrInvalidArgument", err)
+			}
+		})
+	}
+}
@@ -1,5 +1,55 @@
 package storage
 
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// Status - статус задачи.
+type Status int
+
+// Статусы задачи.
+const (
+	StatusOpen       Status = 0
+	StatusInProgress Status = 1
+	StatusDone       Status = 2
+	StatusCancelled  Status = 3
+)
+
+// Priority - приоритет задачи.
+type Priority int
+
+// Приоритеты задачи.
+const (
+	PriorityLow      Priority = 1
+	PriorityMedium   Priority = 2
+	PriorityHigh     Priority = 3
+	PriorityCritical Priority = 4
+)
+
+// LinkType - тип связи между задачами.
+type LinkType string
+
+// Типы связей между задачами.
+const (
+	LinkRelated    LinkType = "related"    // задачи связаны
+	LinkDuplicates LinkType = "duplicates" // задача дублирует связанную
+	LinkBlocks     LinkType = "blocks"     // задача блокирует связанную
+)
+
+// Role - роль пользователя, определяющая его права.
+type Role int
+
+// Роли пользователя.
+const (
+	RoleViewer Role = 1
+	RoleEditor Role = 2
+	RoleAdmin  Role = 3
+)
+
 // "Модель" задачи.
 type Task struct {
 	ID         int
@@ -9,12 +59,74 @@ type Task struct {
 	AssignedID int
 	Title      string
 	Content    string
+	DeletedAt  sql.NullTime
+	Status     Status
+	Priority   Priority
+	DueAt      *int64            // срок выполнения, nil - без срока
+	ProjectID  *int              // проект задачи, nil - вне проектов
+	Metadata   map[string]string // произвольные атрибуты задачи
+
+	// Ключ идемпотентности: AddTask с уже использованным ключом
+	// не создаёт новую задачу, а возвращает ID существующей.
+	IdempotencyKey *string
+
+	// Версия задачи, увеличивается при каждом изменении.
+	// UpdateTask изменяет задачу, только если версия совпадает.
+	Version int
+
+	// Ссылка на задачу во внешней системе, например "jira" и "PRJ-42".
+	// Пара (ExternalSystem, ExternalID) уникальна.
+	ExternalSystem *string
+	ExternalID     *string
+
+	// Задача "зависла": открыта слишком долго (см. StaleTasks).
+	// Устанавливается только MarkTaskStale.
+	Stale bool
+
+	// Теги - произвольные строки, в отличие от меток (Label)
+	// не требующие предварительного создания.
+	Tags []string
+
+	// Ранг для ручной сортировки (см. TasksByRank). При создании задачи
+	// равен текущему времени в миллисекундах, изменяется UpdateTaskRank.
+	Rank float64
+}
+
+// TaskWithLabels - задача вместе с назначенными ей метками.
+type TaskWithLabels struct {
+	Task
+	Labels []Label
+}
+
+// TaskWithUsers - задача вместе с автором и исполнителем.
+// Author и Assignee равны nil, если они не назначены.
+type TaskWithUsers struct {
+	Task
+	Author   *User
+	Assignee *User
 }
 
 // "Модель" пользователя.
 type User struct {
-	ID   int
-	Name string
+	ID       int
+	Name     string
+	UserRole Role // по умолчанию RoleViewer
+}
+
+// TaskStats - сводная статистика по неудалённым задачам.
+type TaskStats struct {
+	Total         int
+	Open          int
+	Closed        int
+	Overdue       int     // незакрытые задачи с истёкшим сроком
+	AvgAgeSeconds float64 // средний возраст незакрытых задач
+}
+
+// UserWorkload - пользователь и количество назначенных ему задач.
+type UserWorkload struct {
+	User
+	OpenTaskCount  int // незакрытые задачи (closed = 0)
+	TotalTaskCount int
 }
 
 // "Модель" метки.
@@ -23,15 +135,483 @@ type Label struct {
 	Name string
 }
 
+// LabelStat - метка и количество задач с ней.
+type LabelStat struct {
+	Label
+	TaskCount int
+}
+
+// "Модель" проекта.
+type Project struct {
+	ID          int
+	Name        string
+	Description string
+	OwnerID     int
+}
+
+// TaskLink - зависимость задачи TaskID от задачи DependsOnID.
+type TaskLink struct {
+	TaskID      int
+	DependsOnID int
+}
+
+// TaskLinkRecord - связь задачи TaskID с задачей LinkedTaskID.
+// Связь направленная: TaskLinks(LinkedTaskID) её не возвращает.
+type TaskLinkRecord struct {
+	ID           int
+	TaskID       int
+	LinkedTaskID int
+	Type         LinkType
+}
+
+// "Модель" комментария к задаче.
+type Comment struct {
+	ID        int
+	TaskID    int
+	AuthorID  int
+	Body      string
+	CreatedAt int64
+}
+
+// TimeEntry - запись о времени, затраченном пользователем на задачу.
+type TimeEntry struct {
+	ID       int
+	TaskID   int
+	UserID   int
+	Minutes  int
+	Note     string
+	LoggedAt int64
+}
+
+// ChecklistItem - пункт чек-листа (подзадача) задачи.
+type ChecklistItem struct {
+	ID       int
+	TaskID   int
+	Body     string
+	Done     bool
+	Position int
+}
+
+// Sprint - спринт (итерация) проекта.
+type Sprint struct {
+	ID        int
+	ProjectID int
+	Name      string
+	StartAt   int64
+	EndAt     int64
+}
+
+// BurndownPoint - количество незакрытых задач спринта на момент At.
+type BurndownPoint struct {
+	At        int64
+	Remaining int
+}
+
+// RecurrenceRule - правило повторения задачи.
+// Задача TaskID служит шаблоном для создаваемых копий.
+type RecurrenceRule struct {
+	TaskID       int
+	IntervalDays int   // интервал повторения в днях
+	NextDue      int64 // время создания следующей копии
+	LastCreated  int64 // время создания последней копии, 0 - копий не было
+}
+
+// AssignmentEvent - запись истории назначения задачи исполнителю.
+type AssignmentEvent struct {
+	ID         int
+	TaskID     int
+	FromUserID *int // прежний исполнитель, nil - не был назначен
+	ToUserID   *int // новый исполнитель, nil - назначение снято
+	ChangedBy  int
+	ChangedAt  int64
+}
+
+// ActivityEvent - событие ленты активности.
+// Kind определяет вид события, например имя метода хранилища,
+// а Payload - его произвольные данные.
+type ActivityEvent struct {
+	ID         int
+	Kind       string
+	TaskID     int // 0 - событие не относится к задаче
+	UserID     int
+	Payload    string
+	OccurredAt int64
+}
+
+// NotificationPreference - настройки уведомлений пользователя.
+// Каждое поле включает уведомления о событии соответствующего вида.
+type NotificationPreference struct {
+	UserID       int
+	TaskCreated  bool
+	TaskUpdated  bool
+	TaskAssigned bool
+	CommentAdded bool
+}
+
+// Виды событий для UsersSubscribedToTask.
+const (
+	NotifyTaskCreated  = "task_created"
+	NotifyTaskUpdated  = "task_updated"
+	NotifyTaskAssigned = "task_assigned"
+	NotifyCommentAdded = "comment_added"
+)
+
+// Enabled сообщает, включены ли уведомления о событии event.
+func (p NotificationPreference) Enabled(event string) (bool, error) {
+	switch event {
+	case NotifyTaskCreated:
+		return p.TaskCreated, nil
+	case NotifyTaskUpdated:
+		return p.TaskUpdated, nil
+	case NotifyTaskAssigned:
+		return p.TaskAssigned, nil
+	case NotifyCommentAdded:
+		return p.CommentAdded, nil
+	}
+	return false, fmt.Errorf("%w: unknown event %q", ErrInvalidArgument, event)
+}
+
+// UserDataExport - все персональные данные пользователя
+// для выгрузки по запросу пользователя.
+type UserDataExport struct {
+	User          User
+	AuthoredTasks []Task
+	AssignedTasks []Task
+	Comments      []Comment
+	TimeEntries   []TimeEntry
+}
+
+// APIKey - ключ API пользователя. Сам ключ хранится только в виде хеша
+// и возвращается один раз при создании, а в Key записывается его
+// открытый префикс, по которому ключ можно опознать.
+type APIKey struct {
+	ID          int
+	UserID      int
+	Key         string
+	Description string
+	CreatedAt   int64
+	ExpiresAt   *int64 // nil - бессрочный ключ
+	LastUsedAt  *int64 // nil - ключ не использовался
+}
+
+// Webhook - веб-хук: события типов Events (см. пакет events)
+// отправляются POST-запросом на URL с подписью по Secret.
+type Webhook struct {
+	ID     int
+	URL    string
+	Secret string
+	Events []string
+	Active bool
+}
+
+// Validate проверяет корректность веб-хука.
+func (w Webhook) Validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: invalid webhook URL %q", ErrInvalidArgument, w.URL)
+	}
+	return nil
+}
+
+// Event - событие потока в хранилище событий.
+// Номера событий (SequenceNo) в потоке идут подряд, начиная с 1.
+type Event struct {
+	ID         int64
+	StreamID   string
+	EventType  string
+	Payload    []byte
+	OccurredAt int64
+	SequenceNo int64
+}
+
+// OutboxMessage - исходящее сообщение для брокера.
+type OutboxMessage struct {
+	ID        int64
+	Topic     string
+	Payload   []byte
+	CreatedAt int64
+	SentAt    *int64 // nil - сообщение ещё не отправлено
+}
+
+// AuditEntry - запись журнала об изменении поля задачи.
+type AuditEntry struct {
+	ID        int
+	TaskID    int
+	UserID    int
+	Field     string
+	OldValue  string
+	NewValue  string
+	ChangedAt int64
+}
+
+// TaskPatch описывает частичное изменение задачи.
+// Изменяются только поля с ненулевыми указателями.
+type TaskPatch struct {
+	Opened     *int64
+	Closed     *int64
+	AuthorID   *int
+	AssignedID *int
+	Title      *string
+	Content    *string
+	Priority   *Priority
+}
+
+// SortField - поле сортировки задач.
+type SortField string
+
+// Поля сортировки задач.
+const (
+	SortByID       SortField = "id"
+	SortByOpened   SortField = "opened"
+	SortByPriority SortField = "priority"
+	SortByTitle    SortField = "title"
+)
+
+// SortDir - направление сортировки.
+type SortDir string
+
+// Направления сортировки.
+const (
+	SortAsc  SortDir = "asc"
+	SortDesc SortDir = "desc"
+)
+
+// SortOptions задаёт порядок задач в выборке. Задачи с равными
+// значениями поля упорядочиваются по возрастанию ID.
+type SortOptions struct {
+	Field SortField // по умолчанию SortByID
+	Dir   SortDir   // по умолчанию SortAsc
+}
+
+// ListOptions задаёт параметры постраничной выборки задач.
+type ListOptions struct {
+	Limit   int         // количество задач на странице, должно быть больше нуля
+	Offset  int         // смещение от начала выборки, не может быть отрицательным
+	OrderBy string      // имя столбца для сортировки, по умолчанию id
+	Sort    SortOptions // если задано Sort.Field, OrderBy не учитывается
+}
+
+// Validate проверяет корректность параметров выборки.
+func (o ListOptions) Validate() error {
+	if o.Limit <= 0 {
+		return errors.New("storage: limit must be positive")
+	}
+	if o.Offset < 0 {
+		return errors.New("storage: offset must not be negative")
+	}
+	if o.Sort.Dir != "" && o.Sort.Dir != SortAsc && o.Sort.Dir != SortDesc {
+		return fmt.Errorf("%w: unknown sort direction %q", ErrInvalidArgument, o.Sort.Dir)
+	}
+	return nil
+}
+
+// TaskFilter задаёт условия отбора задач.
+// Нулевые значения полей не участвуют в отборе,
+// поэтому пустой фильтр соответствует всем задачам.
+type TaskFilter struct {
+	AuthorID      *int
+	AssignedID    *int
+	LabelID       *int
+	OpenedAfter   *int64
+	OpenedBefore  *int64
+	ClosedAfter   *int64
+	ClosedBefore  *int64
+	TitleContains string
+	Priority      *Priority
+}
+
+// LabelFilterMode задаёт способ отбора задач по нескольким меткам.
+type LabelFilterMode int
+
+// Способы отбора задач по меткам.
+const (
+	LabelFilterAny LabelFilterMode = iota // задача имеет хотя бы одну из меток
+	LabelFilterAll                        // задача имеет все метки
+)
+
 // Interface задаёт контракт на работу с БД.
 type Interface interface {
-	Tasks() ([]Task, error)
-	TaskById(taskId int) (*Task, error)
-	TasksByAuthor(authorId int) ([]Task, error)
-	TasksByLabel(labelId int) ([]Task, error)
-	AddTask(task Task) (int, error)
-	AddTasks(tasks []Task) ([]int, error)
-	AddTasksBatch(tasks []Task) error
-	UpdateTask(task Task) error
-	DeleteTask(taskId int) error
+	// Deprecated: используйте TasksList для постраничной выборки.
+	Tasks(ctx context.Context) ([]Task, error)
+	TasksIter(ctx context.Context, fn func(t Task) error) error
+	TasksList(ctx context.Context, opts ListOptions) ([]Task, error)
+	TotalCount(ctx context.Context) (int, error)
+	TasksAfter(ctx context.Context, afterID int, limit int) ([]Task, error)
+	TaskCount(ctx context.Context) (int, error)
+	TaskCountByAuthor(ctx context.Context, authorID int) (int, error)
+	TaskCountByLabel(ctx context.Context, labelID int) (int, error)
+	TaskById(ctx context.Context, taskId int) (*Task, error)
+	TasksByAuthor(ctx context.Context, authorId int) ([]Task, error)
+	TasksByAssignee(ctx context.Context, assigneeID int) ([]Task, error)
+	TasksUnassigned(ctx context.Context) ([]Task, error)
+	TasksByLabel(ctx context.Context, labelId int) ([]Task, error)
+	TasksByLabels(ctx context.Context, labelIDs []int, mode LabelFilterMode) ([]Task, error)
+	TasksWithLabels(ctx context.Context) ([]TaskWithLabels, error)
+	TasksWithUsers(ctx context.Context) ([]TaskWithUsers, error)
+	FilterTasks(ctx context.Context, f TaskFilter) ([]Task, error)
+	TasksByStatus(ctx context.Context, s Status) ([]Task, error)
+	TasksByPriority(ctx context.Context, p Priority) ([]Task, error)
+	TasksOrderedByPriority(ctx context.Context) ([]Task, error)
+	TasksOverdue(ctx context.Context, now int64) ([]Task, error)
+	StaleTasks(ctx context.Context, staleAfterSeconds int64, now int64) ([]Task, error)
+	MarkTaskStale(ctx context.Context, taskID int) error
+	TasksDueBetween(ctx context.Context, from, to int64) ([]Task, error)
+	TasksOpenedBetween(ctx context.Context, from, to int64) ([]Task, error)
+	TasksClosedBetween(ctx context.Context, from, to int64) ([]Task, error)
+	TasksClosedInRange(ctx context.Context, from, to int64) ([]Task, error)
+	SearchTasks(ctx context.Context, query string, limit int) ([]Task, error)
+	SimilarTasks(ctx context.Context, title string, threshold float64) ([]Task, error)
+	TasksByMetadataKey(ctx context.Context, key, value string) ([]Task, error)
+	TaskByExternalID(ctx context.Context, system, externalID string) (*Task, error)
+	TasksByTag(ctx context.Context, tag string) ([]Task, error)
+	TasksByRank(ctx context.Context) ([]Task, error)
+	UpdateTaskRank(ctx context.Context, taskID int, rank float64) error
+	AllTags(ctx context.Context) ([]string, error)
+	AddTask(ctx context.Context, task Task) (int, error)
+	AddTaskWithLabels(ctx context.Context, t Task, labelIDs []int) (int, error)
+	AddTasks(ctx context.Context, tasks []Task) ([]int, error)
+	AddTasksBatch(ctx context.Context, tasks []Task) ([]int, error)
+	ImportTasks(ctx context.Context, tasks []Task) error
+	UpdateTask(ctx context.Context, task Task) error
+	AssignTask(ctx context.Context, taskID, userID int) error
+	UpdateTasksAssignee(ctx context.Context, taskIDs []int, assigneeID int) (int64, error)
+	UpdateTasksStatus(ctx context.Context, taskIDs []int, status Status) (int64, error)
+	UpsertTask(ctx context.Context, t Task) (int, error)
+	PartialUpdateTask(ctx context.Context, taskID int, patch TaskPatch) error
+	UpdateTaskStatus(ctx context.Context, taskID int, s Status) error
+	DeleteTask(ctx context.Context, taskId int) error
+	DeleteTasks(ctx context.Context, taskIDs []int) error
+	TasksIncludingDeleted(ctx context.Context) ([]Task, error)
+	UndeleteTask(ctx context.Context, taskID int) error
+	ArchiveTask(ctx context.Context, taskID int) error
+	ArchivedTasks(ctx context.Context) ([]Task, error)
+	UnarchiveTask(ctx context.Context, taskID int) error
+	LockTask(ctx context.Context, taskID int) error
+	UnlockTask(ctx context.Context, taskID int) error
+
+	AddUser(ctx context.Context, u User) (int, error)
+	Users(ctx context.Context) ([]User, error)
+	UserByID(ctx context.Context, userID int) (*User, error)
+	UpdateUser(ctx context.Context, u User) error
+	DeleteUser(ctx context.Context, userID int) error
+	AssignRole(ctx context.Context, userID int, role Role) error
+	RoleOfUser(ctx context.Context, userID int) (Role, error)
+	UsersWithRole(ctx context.Context, role Role) ([]User, error)
+	ExportUserData(ctx context.Context, userID int) (*UserDataExport, error)
+	EraseUser(ctx context.Context, userID int) error
+	UserWorkloads(ctx context.Context) ([]UserWorkload, error)
+	UserWorkload(ctx context.Context, userID int) (*UserWorkload, error)
+
+	TaskStats(ctx context.Context) (*TaskStats, error)
+	LabelStats(ctx context.Context) ([]LabelStat, error)
+	TopLabels(ctx context.Context, n int) ([]LabelStat, error)
+
+	AddLabel(ctx context.Context, l Label) (int, error)
+	Labels(ctx context.Context) ([]Label, error)
+	LabelByID(ctx context.Context, labelID int) (*Label, error)
+	UpdateLabel(ctx context.Context, l Label) error
+	DeleteLabel(ctx context.Context, labelID int) error
+	AssignLabel(ctx context.Context, taskID, labelID int) error
+	RemoveLabel(ctx context.Context, taskID, labelID int) error
+
+	AddComment(ctx context.Context, c Comment) (int, error)
+	CommentsByTask(ctx context.Context, taskID int) ([]Comment, error)
+	UpdateComment(ctx context.Context, c Comment) error
+	DeleteComment(ctx context.Context, commentID int) error
+
+	LogTime(ctx context.Context, e TimeEntry) (int, error)
+	TimeEntriesByTask(ctx context.Context, taskID int) ([]TimeEntry, error)
+	TimeEntriesByUser(ctx context.Context, userID int) ([]TimeEntry, error)
+	TotalMinutesByTask(ctx context.Context, taskID int) (int, error)
+
+	AddChecklistItem(ctx context.Context, item ChecklistItem) (int, error)
+	ChecklistByTask(ctx context.Context, taskID int) ([]ChecklistItem, error)
+	UpdateChecklistItem(ctx context.Context, item ChecklistItem) error
+	DeleteChecklistItem(ctx context.Context, itemID int) error
+	ReorderChecklist(ctx context.Context, taskID int, orderedIDs []int) error
+
+	AddProject(ctx context.Context, p Project) (int, error)
+	Projects(ctx context.Context) ([]Project, error)
+	ProjectByID(ctx context.Context, projectID int) (*Project, error)
+	UpdateProject(ctx context.Context, p Project) error
+	DeleteProject(ctx context.Context, projectID int, cascadeDelete bool) error
+	TasksByProject(ctx context.Context, projectID int) ([]Task, error)
+
+	AddSprint(ctx context.Context, sp Sprint) (int, error)
+	SprintByID(ctx context.Context, sprintID int) (*Sprint, error)
+	Sprints(ctx context.Context) ([]Sprint, error)
+	UpdateSprint(ctx context.Context, sp Sprint) error
+	DeleteSprint(ctx context.Context, sprintID int) error
+	AssignTaskToSprint(ctx context.Context, taskID, sprintID int) error
+	RemoveTaskFromSprint(ctx context.Context, taskID, sprintID int) error
+	TasksBySprint(ctx context.Context, sprintID int) ([]Task, error)
+	SprintVelocity(ctx context.Context, sprintID int) (int, error)
+	BurndownData(ctx context.Context, sprintID int, buckets int) ([]BurndownPoint, error)
+
+	AddDependency(ctx context.Context, taskID, dependsOnID int) error
+	RemoveDependency(ctx context.Context, taskID, dependsOnID int) error
+	DependenciesOf(ctx context.Context, taskID int) ([]Task, error)
+	BlockedBy(ctx context.Context, taskID int) ([]Task, error)
+
+	AddTaskLink(ctx context.Context, l TaskLinkRecord) (int, error)
+	TaskLinks(ctx context.Context, taskID int) ([]TaskLinkRecord, error)
+	DeleteTaskLink(ctx context.Context, linkID int) error
+
+	AuditLog(ctx context.Context, taskID int) ([]AuditEntry, error)
+	RecordChange(ctx context.Context, e AuditEntry) error
+
+	AssignmentHistory(ctx context.Context, taskID int) ([]AssignmentEvent, error)
+	RecordAssignment(ctx context.Context, e AssignmentEvent) error
+
+	RecordActivity(ctx context.Context, e ActivityEvent) error
+	ActivityFeed(ctx context.Context, taskID int, limit int) ([]ActivityEvent, error)
+	GlobalActivityFeed(ctx context.Context, limit int) ([]ActivityEvent, error)
+
+	SetNotificationPreference(ctx context.Context, p NotificationPreference) error
+	GetNotificationPreference(ctx context.Context, userID int) (*NotificationPreference, error)
+	UsersSubscribedToTask(ctx context.Context, taskID int, event string) ([]User, error)
+
+	CreateAPIKey(ctx context.Context, k APIKey) (string, error)
+	APIKeyByKey(ctx context.Context, raw string) (*APIKey, error)
+	RevokeAPIKey(ctx context.Context, keyID int) error
+	APIKeysByUser(ctx context.Context, userID int) ([]APIKey, error)
+
+	RegisterWebhook(ctx context.Context, w Webhook) (int, error)
+	Webhooks(ctx context.Context) ([]Webhook, error)
+	WebhookByID(ctx context.Context, webhookID int) (*Webhook, error)
+	UpdateWebhook(ctx context.Context, w Webhook) error
+	DeleteWebhook(ctx context.Context, webhookID int) error
+
+	WatchTask(ctx context.Context, userID, taskID int) error
+	UnwatchTask(ctx context.Context, userID, taskID int) error
+	WatchersOfTask(ctx context.Context, taskID int) ([]User, error)
+
+	AppendEvents(ctx context.Context, streamID string, expectedSeq int64, events []Event) error
+	LoadEvents(ctx context.Context, streamID string, fromSeq int64) ([]Event, error)
+
+	AppendOutbox(ctx context.Context, tx TxStorage, msgs []OutboxMessage) error
+	PendingOutbox(ctx context.Context, limit int) ([]OutboxMessage, error)
+	MarkOutboxSent(ctx context.Context, ids []int64) error
+
+	AddRecurrenceRule(ctx context.Context, rule RecurrenceRule) error
+	RecurrenceRuleByTask(ctx context.Context, taskID int) (*RecurrenceRule, error)
+	UpdateRecurrenceRule(ctx context.Context, rule RecurrenceRule) error
+	DeleteRecurrenceRule(ctx context.Context, taskID int) error
+	DueRecurrences(ctx context.Context, now int64) ([]RecurrenceRule, error)
+	SpawnRecurringTask(ctx context.Context, rule RecurrenceRule) (int, error)
+
+	BeginTx(ctx context.Context) (TxStorage, error)
+}
+
+// TxStorage - хранилище, все операции которого выполняются
+// в одной транзакции. После Commit или Rollback методы
+// возвращают ErrTxClosed.
+type TxStorage interface {
+	Interface
+	Commit(ctx context.Context) error
+	Rollback(ctx context.Context) error
+
+	Savepoint(ctx context.Context, name string) error
+	RollbackToSavepoint(ctx context.Context, name string) error
+	ReleaseSavepoint(ctx context.Context, name string) error
 }
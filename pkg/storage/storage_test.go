@@ -0,0 +1,48 @@
+package storage
+
+import "testing"
+
+func TestListOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    ListOptions
+		wantErr bool
+	}{
+		{"first page", ListOptions{Limit: 10}, false},
+		{"offset", ListOptions{Limit: 10, Offset: 20}, false},
+		{"zero limit", ListOptions{}, true},
+		{"negative limit", ListOptions{Limit: -1}, true},
+		{"negative offset", ListOptions{Limit: 10, Offset: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNotificationPreferenceEnabled(t *testing.T) {
+	p := NotificationPreference{TaskCreated: true, CommentAdded: true}
+	tests := []struct {
+		event   string
+		want    bool
+		wantErr bool
+	}{
+		{NotifyTaskCreated, true, false},
+		{NotifyTaskUpdated, false, false},
+		{NotifyTaskAssigned, false, false},
+		{NotifyCommentAdded, true, false},
+		{"unknown", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.event, func(t *testing.T) {
+			got, err := p.Enabled(tt.event)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("Enabled() = %v, %v, want %v, wantErr %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
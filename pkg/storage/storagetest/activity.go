@@ -0,0 +1,92 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"skillfactory/30.8.1/pkg/storage"
+	"skillfactory/30.8.1/pkg/storage/logmw"
+	"testing"
+)
+
+// kinds возвращает виды событий.
+func kinds(events []storage.ActivityEvent) []string {
+	var res []string
+	for _, e := range events {
+		res = append(res, e.Kind)
+	}
+	return res
+}
+
+func testActivityFeed(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	m := logmw.New(s, slog.New(slog.NewTextHandler(io.Discard, nil)), logmw.WithActivity(true))
+
+	id, err := m.AddTask(ctx, storage.Task{Title: "task"})
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	task := taskByID(t, s, id)
+	task.Title = "updated"
+	err = m.UpdateTask(ctx, task)
+	if err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	// неудачная запись и чтение не попадают в ленту
+	m.UpdateTask(ctx, task)
+	m.TaskById(ctx, id)
+
+	events, err := s.ActivityFeed(ctx, id, 10)
+	if err != nil {
+		t.Fatalf("ActivityFeed() error = %v", err)
+	}
+	if got := kinds(events); len(got) != 2 || got[0] != "UpdateTask" || got[1] != "AddTask" {
+		t.Fatalf("ActivityFeed() = %+v, want UpdateTask and AddTask", events)
+	}
+	for _, e := range events {
+		if e.TaskID != id || e.OccurredAt == 0 {
+			t.Errorf("ActivityFeed() event = %+v, want task %d with time", e, id)
+		}
+	}
+
+	// события упорядочены по времени, начиная с самых новых
+	other := addTask(t, s, storage.Task{Title: "other"})
+	for _, e := range []storage.ActivityEvent{
+		{Kind: "old", TaskID: other, OccurredAt: 100},
+		{Kind: "new", TaskID: other, OccurredAt: 300, Payload: `{"x":1}`},
+		{Kind: "middle", TaskID: other, OccurredAt: 200},
+	} {
+		err = s.RecordActivity(ctx, e)
+		if err != nil {
+			t.Fatalf("RecordActivity() error = %v", err)
+		}
+	}
+	events, err = s.ActivityFeed(ctx, other, 2)
+	if err != nil {
+		t.Fatalf("ActivityFeed() error = %v", err)
+	}
+	if got := kinds(events); len(got) != 2 || got[0] != "new" || got[1] != "middle" {
+		t.Errorf("ActivityFeed() with limit 2 = %v, want [new middle]", got)
+	}
+	if events[0].Payload != `{"x":1}` {
+		t.Errorf("ActivityFeed() payload = %q, want {\"x\":1}", events[0].Payload)
+	}
+
+	events, err = s.GlobalActivityFeed(ctx, 10)
+	if err != nil {
+		t.Fatalf("GlobalActivityFeed() error = %v", err)
+	}
+	if len(events) != 5 || events[0].TaskID != id || events[4].Kind != "old" {
+		t.Errorf("GlobalActivityFeed() = %+v, want 5 events from newest to oldest", events)
+	}
+
+	_, err = s.ActivityFeed(ctx, id, 0)
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("ActivityFeed() with zero limit error = %v, want ErrInvalidArgument", err)
+	}
+	_, err = s.GlobalActivityFeed(ctx, -1)
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("GlobalActivityFeed() with negative limit error = %v, want ErrInvalidArgument", err)
+	}
+}
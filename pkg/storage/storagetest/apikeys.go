@@ -0,0 +1,97 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testAPIKeys(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	alice := addUser(t, s, "alice")
+	expires := time.Now().Add(time.Hour).Unix()
+
+	raw, err := s.CreateAPIKey(ctx, storage.APIKey{UserID: alice, Description: "ci", ExpiresAt: &expires})
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+
+	keys, err := s.APIKeysByUser(ctx, alice)
+	if err != nil {
+		t.Fatalf("APIKeysByUser() error = %v", err)
+	}
+	if len(keys) != 1 {
+		t.Fatalf("APIKeysByUser() = %+v, want 1 key", keys)
+	}
+	// хранится только префикс ключа, но не секрет
+	k := keys[0]
+	if k.Key == "" || k.Key == raw || !strings.HasPrefix(raw, k.Key+".") {
+		t.Errorf("stored Key = %q, want prefix of %q", k.Key, raw)
+	}
+	if k.UserID != alice || k.Description != "ci" || k.CreatedAt == 0 || k.LastUsedAt != nil ||
+		k.ExpiresAt == nil || *k.ExpiresAt != expires {
+		t.Errorf("APIKeysByUser()[0] = %+v, want unused key of alice", k)
+	}
+
+	got, err := s.APIKeyByKey(ctx, raw)
+	if err != nil {
+		t.Fatalf("APIKeyByKey() error = %v", err)
+	}
+	if got.ID != k.ID || got.UserID != alice || got.LastUsedAt == nil {
+		t.Errorf("APIKeyByKey() = %+v, want used key %d", got, k.ID)
+	}
+	keys, err = s.APIKeysByUser(ctx, alice)
+	if err != nil {
+		t.Fatalf("APIKeysByUser() error = %v", err)
+	}
+	if keys[0].LastUsedAt == nil {
+		t.Errorf("LastUsedAt after APIKeyByKey = nil, want time of use")
+	}
+
+	prefix, _, _ := strings.Cut(raw, ".")
+	tests := []struct {
+		name string
+		raw  string
+		want error
+	}{
+		{"wrong secret", prefix + ".secret", storage.ErrNotFound},
+		{"unknown prefix", strings.Repeat("0", len(prefix)) + ".secret", storage.ErrNotFound},
+		{"malformed", "key", storage.ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		if _, err := s.APIKeyByKey(ctx, tt.raw); !errors.Is(err, tt.want) {
+			t.Errorf("APIKeyByKey() with %s error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	expired := time.Now().Add(-time.Hour).Unix()
+	expiredRaw, err := s.CreateAPIKey(ctx, storage.APIKey{UserID: alice, ExpiresAt: &expired})
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+	if _, err := s.APIKeyByKey(ctx, expiredRaw); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("APIKeyByKey() of expired key error = %v, want ErrNotFound", err)
+	}
+
+	// отозванный ключ не принимается и не возвращается в списке
+	err = s.RevokeAPIKey(ctx, k.ID)
+	if err != nil {
+		t.Fatalf("RevokeAPIKey() error = %v", err)
+	}
+	if _, err := s.APIKeyByKey(ctx, raw); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("APIKeyByKey() of revoked key error = %v, want ErrNotFound", err)
+	}
+	if err := s.RevokeAPIKey(ctx, k.ID); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("RevokeAPIKey() again error = %v, want ErrNotFound", err)
+	}
+	keys, err = s.APIKeysByUser(ctx, alice)
+	if err != nil {
+		t.Fatalf("APIKeysByUser() error = %v", err)
+	}
+	if len(keys) != 1 || keys[0].ID == k.ID {
+		t.Errorf("APIKeysByUser() after revoke = %+v, want only expired key", keys)
+	}
+}
@@ -0,0 +1,105 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"testing"
+)
+
+func testArchive(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	author := addUser(t, s, "author")
+	bug := addLabel(t, s, "bug")
+	id := addTask(t, s, storage.Task{Title: "archived", Content: "content"})
+	kept := addTask(t, s, storage.Task{Title: "kept"})
+
+	err := s.AssignLabel(ctx, id, bug)
+	if err != nil {
+		t.Fatalf("AssignLabel() error = %v", err)
+	}
+	comment := addComment(t, s, storage.Comment{TaskID: id, AuthorID: author, Body: "comment"})
+	_, err = s.AddChecklistItem(ctx, storage.ChecklistItem{TaskID: id, Body: "step"})
+	if err != nil {
+		t.Fatalf("AddChecklistItem() error = %v", err)
+	}
+	before := taskByID(t, s, id)
+
+	err = s.ArchiveTask(ctx, id)
+	if err != nil {
+		t.Fatalf("ArchiveTask() error = %v", err)
+	}
+	tasks, err := s.Tasks(ctx)
+	if err != nil {
+		t.Fatalf("Tasks() error = %v", err)
+	}
+	if !slices.Equal(ids(tasks), []int{kept}) {
+		t.Errorf("Tasks() after ArchiveTask = %v, want [%d]", ids(tasks), kept)
+	}
+	_, err = s.TaskById(ctx, id)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("TaskById() of archived task error = %v, want ErrNotFound", err)
+	}
+	archived, err := s.ArchivedTasks(ctx)
+	if err != nil {
+		t.Fatalf("ArchivedTasks() error = %v", err)
+	}
+	if len(archived) != 1 || archived[0].ID != id || archived[0].Title != "archived" || archived[0].Content != "content" {
+		t.Fatalf("ArchivedTasks() = %+v, want task %d", archived, id)
+	}
+	byLabel, err := s.TasksByLabel(ctx, bug)
+	if err != nil {
+		t.Fatalf("TasksByLabel() error = %v", err)
+	}
+	if len(byLabel) != 0 {
+		t.Errorf("TasksByLabel() after ArchiveTask = %v, want none", ids(byLabel))
+	}
+
+	err = s.UnarchiveTask(ctx, id)
+	if err != nil {
+		t.Fatalf("UnarchiveTask() error = %v", err)
+	}
+	after := taskByID(t, s, id)
+	if after.Title != before.Title || after.Content != before.Content || after.Opened != before.Opened {
+		t.Errorf("TaskById() after UnarchiveTask = %+v, want %+v", after, before)
+	}
+	archived, err = s.ArchivedTasks(ctx)
+	if err != nil {
+		t.Fatalf("ArchivedTasks() error = %v", err)
+	}
+	if len(archived) != 0 {
+		t.Errorf("ArchivedTasks() after UnarchiveTask = %v, want none", ids(archived))
+	}
+
+	// связанные записи восстанавливаются вместе с задачей
+	byLabel, err = s.TasksByLabel(ctx, bug)
+	if err != nil {
+		t.Fatalf("TasksByLabel() error = %v", err)
+	}
+	if !slices.Equal(ids(byLabel), []int{id}) {
+		t.Errorf("TasksByLabel() after UnarchiveTask = %v, want [%d]", ids(byLabel), id)
+	}
+	comments, err := s.CommentsByTask(ctx, id)
+	if err != nil {
+		t.Fatalf("CommentsByTask() error = %v", err)
+	}
+	if len(comments) != 1 || comments[0].ID != comment || comments[0].Body != "comment" {
+		t.Errorf("CommentsByTask() after UnarchiveTask = %+v, want comment %d", comments, comment)
+	}
+	items, err := s.ChecklistByTask(ctx, id)
+	if err != nil {
+		t.Fatalf("ChecklistByTask() error = %v", err)
+	}
+	if len(items) != 1 || items[0].Body != "step" {
+		t.Errorf("ChecklistByTask() after UnarchiveTask = %+v, want one step", items)
+	}
+
+	const missing = 1000
+	if err := s.ArchiveTask(ctx, missing); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("ArchiveTask() of missing task error = %v, want ErrNotFound", err)
+	}
+	if err := s.UnarchiveTask(ctx, kept); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("UnarchiveTask() of task not in archive error = %v, want ErrNotFound", err)
+	}
+}
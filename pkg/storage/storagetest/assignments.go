@@ -0,0 +1,80 @@
+package storagetest
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"testing"
+)
+
+// intValue возвращает значение указателя или -1 для nil.
+func intValue(p *int) int {
+	if p == nil {
+		return -1
+	}
+	return *p
+}
+
+func testAssignmentHistory(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	alice := addUser(t, s, "alice")
+	bob := addUser(t, s, "bob")
+	carol := addUser(t, s, "carol")
+	id := addTask(t, s, storage.Task{Title: "task"})
+
+	// исполнитель меняется тремя разными методами
+	err := s.AssignTask(ctx, id, alice)
+	if err != nil {
+		t.Fatalf("AssignTask() error = %v", err)
+	}
+	patchTask(t, s, id, storage.TaskPatch{AssignedID: &bob})
+	task := taskByID(t, s, id)
+	task.AssignedID = carol
+	err = s.UpdateTask(ctx, task)
+	if err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	// обновление без смены исполнителя не записывается
+	patchTask(t, s, id, storage.TaskPatch{AssignedID: &carol})
+
+	events, err := s.AssignmentHistory(ctx, id)
+	if err != nil {
+		t.Fatalf("AssignmentHistory() error = %v", err)
+	}
+	want := [][2]int{{0, alice}, {alice, bob}, {bob, carol}}
+	if len(events) != len(want) {
+		t.Fatalf("AssignmentHistory() = %+v, want %d events", events, len(want))
+	}
+	for i, e := range events {
+		got := [2]int{intValue(e.FromUserID), intValue(e.ToUserID)}
+		if e.TaskID != id || got != want[i] || e.ChangedAt == 0 {
+			t.Errorf("AssignmentHistory()[%d] = %+v, want %d -> %d", i, e, want[i][0], want[i][1])
+		}
+		if i > 0 && (e.ID <= events[i-1].ID || e.ChangedAt < events[i-1].ChangedAt) {
+			t.Errorf("AssignmentHistory() is not in chronological order: %+v", events)
+		}
+	}
+
+	err = s.RecordAssignment(ctx, storage.AssignmentEvent{TaskID: id, ToUserID: &alice, ChangedBy: bob, ChangedAt: 1000})
+	if err != nil {
+		t.Fatalf("RecordAssignment() error = %v", err)
+	}
+	events, err = s.AssignmentHistory(ctx, id)
+	if err != nil {
+		t.Fatalf("AssignmentHistory() error = %v", err)
+	}
+	if len(events) != 4 {
+		t.Fatalf("AssignmentHistory() = %+v, want 4 events", events)
+	}
+	if e := events[3]; e.FromUserID != nil || intValue(e.ToUserID) != alice || e.ChangedBy != bob || e.ChangedAt != 1000 {
+		t.Errorf("recorded event = %+v, want nil -> %d by %d at 1000", e, alice, bob)
+	}
+
+	other := addTask(t, s, storage.Task{Title: "other"})
+	events, err = s.AssignmentHistory(ctx, other)
+	if err != nil {
+		t.Fatalf("AssignmentHistory() error = %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("AssignmentHistory() of unassigned task = %+v, want none", events)
+	}
+}
@@ -0,0 +1,61 @@
+package storagetest
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"skillfactory/30.8.1/pkg/storage/audit"
+	"testing"
+)
+
+func testAuditLog(t *testing.T, s storage.Interface) {
+	userID := addUser(t, s, "alice")
+	ctx := audit.WithUser(context.Background(), userID)
+	a := audit.New(s)
+
+	id := addTask(t, s, storage.Task{Title: "old title", Content: "content"})
+	task := taskByID(t, s, id)
+	task.Title = "new title"
+	err := a.UpdateTask(ctx, task)
+	if err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+
+	entries, err := s.AuditLog(ctx, id)
+	if err != nil {
+		t.Fatalf("AuditLog() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("AuditLog() = %+v, want 1 entry", entries)
+	}
+	e := entries[0]
+	if e.TaskID != id || e.UserID != userID || e.Field != "title" ||
+		e.OldValue != "old title" || e.NewValue != "new title" || e.ChangedAt == 0 {
+		t.Errorf("AuditLog() = %+v, want title change by user %d", e, userID)
+	}
+
+	// обновление без изменений не записывается в журнал
+	err = a.UpdateTask(ctx, taskByID(t, s, id))
+	if err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	entries, err = s.AuditLog(ctx, id)
+	if err != nil {
+		t.Fatalf("AuditLog() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("AuditLog() after no-op update = %+v, want 1 entry", entries)
+	}
+
+	// время изменения, заданное явно, сохраняется
+	err = s.RecordChange(ctx, storage.AuditEntry{TaskID: id, UserID: userID, Field: "content", OldValue: "a", NewValue: "b", ChangedAt: 1000})
+	if err != nil {
+		t.Fatalf("RecordChange() error = %v", err)
+	}
+	entries, err = s.AuditLog(ctx, id)
+	if err != nil {
+		t.Fatalf("AuditLog() error = %v", err)
+	}
+	if len(entries) != 2 || entries[1].Field != "content" || entries[1].ChangedAt != 1000 {
+		t.Errorf("AuditLog() = %+v, want recorded content change at 1000", entries)
+	}
+}
@@ -0,0 +1,63 @@
+package storagetest
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"testing"
+)
+
+func testChecklist(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	task := addTask(t, s, storage.Task{Title: "task"})
+
+	var items []int
+	for i, body := range []string{"first", "second", "third"} {
+		id, err := s.AddChecklistItem(ctx, storage.ChecklistItem{TaskID: task, Body: body, Position: i})
+		if err != nil {
+			t.Fatalf("AddChecklistItem() error = %v", err)
+		}
+		items = append(items, id)
+	}
+	itemIDs := func() []int {
+		t.Helper()
+		got, err := s.ChecklistByTask(ctx, task)
+		if err != nil {
+			t.Fatalf("ChecklistByTask() error = %v", err)
+		}
+		var ids []int
+		for _, item := range got {
+			ids = append(ids, item.ID)
+		}
+		return ids
+	}
+
+	err := s.ReorderChecklist(ctx, task, []int{items[2], items[0], items[1]})
+	if err != nil {
+		t.Fatalf("ReorderChecklist() error = %v", err)
+	}
+	if got, want := itemIDs(), []int{items[2], items[0], items[1]}; !slices.Equal(got, want) {
+		t.Errorf("ChecklistByTask() after ReorderChecklist = %v, want %v", got, want)
+	}
+
+	err = s.UpdateChecklistItem(ctx, storage.ChecklistItem{ID: items[0], Body: "first done", Done: true, Position: 1})
+	if err != nil {
+		t.Fatalf("UpdateChecklistItem() error = %v", err)
+	}
+	got, err := s.ChecklistByTask(ctx, task)
+	if err != nil {
+		t.Fatalf("ChecklistByTask() error = %v", err)
+	}
+	if i := slices.IndexFunc(got, func(item storage.ChecklistItem) bool { return item.ID == items[0] }); i < 0 ||
+		got[i] != (storage.ChecklistItem{ID: items[0], TaskID: task, Body: "first done", Done: true, Position: 1}) {
+		t.Errorf("ChecklistByTask() = %+v, want updated item %d", got, items[0])
+	}
+
+	err = s.DeleteChecklistItem(ctx, items[1])
+	if err != nil {
+		t.Fatalf("DeleteChecklistItem() error = %v", err)
+	}
+	if got, want := itemIDs(), []int{items[2], items[0]}; !slices.Equal(got, want) {
+		t.Errorf("ChecklistByTask() after DeleteChecklistItem = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,61 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"testing"
+)
+
+// addComment создаёт комментарий и возвращает его ID.
+func addComment(t *testing.T, s storage.Interface, c storage.Comment) int {
+	t.Helper()
+	id, err := s.AddComment(context.Background(), c)
+	if err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+	return id
+}
+
+func testComments(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	author := addUser(t, s, "author")
+	task := addTask(t, s, storage.Task{Title: "task"})
+	other := addTask(t, s, storage.Task{Title: "other"})
+
+	first := addComment(t, s, storage.Comment{TaskID: task, AuthorID: author, Body: "first"})
+	second := addComment(t, s, storage.Comment{TaskID: task, AuthorID: author, Body: "second"})
+	addComment(t, s, storage.Comment{TaskID: other, AuthorID: author, Body: "other"})
+
+	err := s.UpdateComment(ctx, storage.Comment{ID: first, Body: "edited"})
+	if err != nil {
+		t.Fatalf("UpdateComment() error = %v", err)
+	}
+	comments, err := s.CommentsByTask(ctx, task)
+	if err != nil {
+		t.Fatalf("CommentsByTask() error = %v", err)
+	}
+	if len(comments) != 2 || comments[0].ID != first || comments[1].ID != second {
+		t.Fatalf("CommentsByTask() = %+v, want comments %d and %d", comments, first, second)
+	}
+	if c := comments[0]; c.Body != "edited" || c.TaskID != task || c.AuthorID != author || c.CreatedAt == 0 {
+		t.Errorf("edited comment = %+v", c)
+	}
+
+	err = s.DeleteComment(ctx, second)
+	if err != nil {
+		t.Fatalf("DeleteComment() error = %v", err)
+	}
+	comments, err = s.CommentsByTask(ctx, task)
+	if err != nil {
+		t.Fatalf("CommentsByTask() error = %v", err)
+	}
+	if len(comments) != 1 || comments[0].ID != first {
+		t.Errorf("CommentsByTask() after DeleteComment = %+v, want comment %d", comments, first)
+	}
+
+	_, err = s.AddComment(ctx, storage.Comment{TaskID: task, Body: "anonymous"})
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("AddComment() without author error = %v, want ErrInvalidArgument", err)
+	}
+}
@@ -0,0 +1,142 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"testing"
+)
+
+func testTasksBetween(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+
+	// ID задач по времени открытия и закрытия
+	times := [][2]int64{{100, 0}, {200, 250}, {300, 400}, {400, 500}}
+	var tasks []int
+	for _, tm := range times {
+		id := addTask(t, s, storage.Task{Title: "task"})
+		opened, closed := tm[0], tm[1]
+		patchTask(t, s, id, storage.TaskPatch{Opened: &opened, Closed: &closed})
+		tasks = append(tasks, id)
+	}
+
+	tests := []struct {
+		name     string
+		from, to int64
+		opened   []int
+		closed   []int
+	}{
+		{"empty window", 1, 99, nil, nil},
+		{"inclusive bounds", 200, 400, tasks[1:4], tasks[1:3]},
+		{"single point", 400, 400, tasks[3:4], tasks[2:3]},
+		{"everything", 1, 1000, tasks, tasks[1:]},
+		{"reversed bounds", 400, 200, nil, nil},
+	}
+	for _, tt := range tests {
+		got, err := s.TasksOpenedBetween(ctx, tt.from, tt.to)
+		if err != nil {
+			t.Fatalf("TasksOpenedBetween() error = %v", err)
+		}
+		if !slices.Equal(ids(got), tt.opened) {
+			t.Errorf("TasksOpenedBetween(%d, %d) = %v, want %v", tt.from, tt.to, ids(got), tt.opened)
+		}
+
+		got, err = s.TasksClosedBetween(ctx, tt.from, tt.to)
+		if err != nil {
+			t.Fatalf("TasksClosedBetween() error = %v", err)
+		}
+		if !slices.Equal(ids(got), tt.closed) {
+			t.Errorf("TasksClosedBetween(%d, %d) = %v, want %v", tt.from, tt.to, ids(got), tt.closed)
+		}
+	}
+}
+
+func testTasksClosedInRange(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	const from, to = 1000, 2000
+
+	// задачи создаются не в порядке закрытия
+	tasks := map[string]int{}
+	for _, tc := range []struct {
+		name   string
+		closed int64
+	}{
+		{"to", to},
+		{"open", 0},
+		{"before", from - 1},
+		{"from", from},
+		{"after", to + 1},
+	} {
+		id := addTask(t, s, storage.Task{Title: tc.name})
+		patchTask(t, s, id, storage.TaskPatch{Closed: &tc.closed})
+		tasks[tc.name] = id
+	}
+
+	got, err := s.TasksClosedInRange(ctx, from, to)
+	if err != nil {
+		t.Fatalf("TasksClosedInRange() error = %v", err)
+	}
+	// задачи упорядочены по времени закрытия
+	if want := []int{tasks["from"], tasks["to"]}; !slices.Equal(ids(got), want) {
+		t.Errorf("TasksClosedInRange(%d, %d) = %v, want %v", from, to, ids(got), want)
+	}
+
+	// незакрытая задача не возвращается, даже если интервал включает 0
+	got, err = s.TasksClosedInRange(ctx, 0, from-1)
+	if err != nil {
+		t.Fatalf("TasksClosedInRange() error = %v", err)
+	}
+	if want := []int{tasks["before"]}; !slices.Equal(ids(got), want) {
+		t.Errorf("TasksClosedInRange(0, %d) = %v, want %v", from-1, ids(got), want)
+	}
+}
+
+func testStaleTasks(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+
+	open := func(opened, closed int64) int {
+		id := addTask(t, s, storage.Task{Title: "task"})
+		patchTask(t, s, id, storage.TaskPatch{Opened: &opened, Closed: &closed})
+		return id
+	}
+	newer := open(200, 0)
+	older := open(100, 0)
+	open(100, 150) // закрыта
+	open(900, 0)   // открыта недавно
+
+	// now - opened > staleAfter: граница не включается
+	got, err := s.StaleTasks(ctx, 700, 1000)
+	if err != nil {
+		t.Fatalf("StaleTasks() error = %v", err)
+	}
+	if want := []int{older, newer}; !slices.Equal(ids(got), want) {
+		t.Errorf("StaleTasks(700, 1000) = %v, want %v", ids(got), want)
+	}
+	got, err = s.StaleTasks(ctx, 900, 1000)
+	if err != nil {
+		t.Fatalf("StaleTasks() error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("StaleTasks(900, 1000) = %v, want none", ids(got))
+	}
+
+	if taskByID(t, s, older).Stale {
+		t.Error("new task is stale")
+	}
+	err = s.MarkTaskStale(ctx, older)
+	if err != nil {
+		t.Fatalf("MarkTaskStale() error = %v", err)
+	}
+	if !taskByID(t, s, older).Stale {
+		t.Error("MarkTaskStale() did not mark the task")
+	}
+	if taskByID(t, s, newer).Stale {
+		t.Error("MarkTaskStale() marked another task")
+	}
+
+	err = s.MarkTaskStale(ctx, 1_000_000)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("MarkTaskStale(missing) error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,66 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"testing"
+)
+
+func testDependencies(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	design := addTask(t, s, storage.Task{Title: "design"})
+	build := addTask(t, s, storage.Task{Title: "build"})
+	test := addTask(t, s, storage.Task{Title: "test"})
+
+	for _, dep := range [][2]int{{build, design}, {test, design}, {test, build}} {
+		err := s.AddDependency(ctx, dep[0], dep[1])
+		if err != nil {
+			t.Fatalf("AddDependency(%d, %d) error = %v", dep[0], dep[1], err)
+		}
+	}
+
+	got, err := s.DependenciesOf(ctx, test)
+	if err != nil {
+		t.Fatalf("DependenciesOf() error = %v", err)
+	}
+	if want := []int{design, build}; !slices.Equal(ids(got), want) {
+		t.Errorf("DependenciesOf(test) = %v, want %v", ids(got), want)
+	}
+	got, err = s.BlockedBy(ctx, design)
+	if err != nil {
+		t.Fatalf("BlockedBy() error = %v", err)
+	}
+	if want := []int{build, test}; !slices.Equal(ids(got), want) {
+		t.Errorf("BlockedBy(design) = %v, want %v", ids(got), want)
+	}
+
+	tests := []struct {
+		name      string
+		task, dep int
+		want      error
+	}{
+		{"self", design, design, storage.ErrInvalidArgument},
+		{"reverse", design, build, storage.ErrInvalidArgument},
+		{"duplicate", build, design, storage.ErrConflict},
+	}
+	for _, tt := range tests {
+		err := s.AddDependency(ctx, tt.task, tt.dep)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("AddDependency() %s error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	err = s.RemoveDependency(ctx, test, design)
+	if err != nil {
+		t.Fatalf("RemoveDependency() error = %v", err)
+	}
+	got, err = s.DependenciesOf(ctx, test)
+	if err != nil {
+		t.Fatalf("DependenciesOf() error = %v", err)
+	}
+	if want := []int{build}; !slices.Equal(ids(got), want) {
+		t.Errorf("DependenciesOf(test) after RemoveDependency = %v, want %v", ids(got), want)
+	}
+}
@@ -0,0 +1,41 @@
+package storagetest
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"testing"
+)
+
+func testTasksOverdue(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	due := func(v int64) *int64 { return &v }
+
+	late := addTask(t, s, storage.Task{Title: "late", DueAt: due(200)})
+	later := addTask(t, s, storage.Task{Title: "later", DueAt: due(100)})
+	future := addTask(t, s, storage.Task{Title: "future", DueAt: due(400)})
+	addTask(t, s, storage.Task{Title: "no due date"})
+	closed := addTask(t, s, storage.Task{Title: "closed", DueAt: due(50)})
+	patchTask(t, s, closed, storage.TaskPatch{Closed: due(60)})
+
+	if task := taskByID(t, s, late); task.DueAt == nil || *task.DueAt != 200 {
+		t.Errorf("TaskById() DueAt = %v, want 200", task.DueAt)
+	}
+
+	// незакрытые задачи в порядке наступления срока
+	got, err := s.TasksOverdue(ctx, 300)
+	if err != nil {
+		t.Fatalf("TasksOverdue() error = %v", err)
+	}
+	if want := []int{later, late}; !slices.Equal(ids(got), want) {
+		t.Errorf("TasksOverdue() = %v, want %v", ids(got), want)
+	}
+
+	got, err = s.TasksDueBetween(ctx, 100, 400)
+	if err != nil {
+		t.Fatalf("TasksDueBetween() error = %v", err)
+	}
+	if want := []int{later, late, future}; !slices.Equal(ids(got), want) {
+		t.Errorf("TasksDueBetween() = %v, want %v", ids(got), want)
+	}
+}
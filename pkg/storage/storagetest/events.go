@@ -0,0 +1,113 @@
+package storagetest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"sync"
+	"testing"
+)
+
+// loadEvents возвращает события потока, начиная с номера fromSeq.
+func loadEvents(t *testing.T, s storage.Interface, streamID string, fromSeq int64) []storage.Event {
+	t.Helper()
+	events, err := s.LoadEvents(context.Background(), streamID, fromSeq)
+	if err != nil {
+		t.Fatalf("LoadEvents(%q, %d) error = %v", streamID, fromSeq, err)
+	}
+	return events
+}
+
+func testEvents(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	const stream = "task-1"
+
+	err := s.AppendEvents(ctx, stream, 0, []storage.Event{
+		{EventType: "created", Payload: []byte(`{"title":"task"}`)},
+		{EventType: "renamed", Payload: []byte(`{"title":"new"}`), OccurredAt: 100},
+	})
+	if err != nil {
+		t.Fatalf("AppendEvents() error = %v", err)
+	}
+	err = s.AppendEvents(ctx, stream, 2, []storage.Event{{EventType: "closed"}})
+	if err != nil {
+		t.Fatalf("AppendEvents() at seq 2 error = %v", err)
+	}
+	// в другой поток события добавляются независимо
+	err = s.AppendEvents(ctx, "task-2", 0, []storage.Event{{EventType: "created"}})
+	if err != nil {
+		t.Fatalf("AppendEvents() to another stream error = %v", err)
+	}
+
+	events := loadEvents(t, s, stream, 0)
+	wantTypes := []string{"created", "renamed", "closed"}
+	if len(events) != len(wantTypes) {
+		t.Fatalf("LoadEvents() = %+v, want %d events", events, len(wantTypes))
+	}
+	for i, e := range events {
+		if e.StreamID != stream || e.EventType != wantTypes[i] || e.SequenceNo != int64(i+1) || e.ID == 0 {
+			t.Errorf("LoadEvents()[%d] = %+v, want %s with sequence %d", i, e, wantTypes[i], i+1)
+		}
+	}
+	if !bytes.Equal(events[0].Payload, []byte(`{"title":"task"}`)) || events[0].OccurredAt == 0 {
+		t.Errorf("LoadEvents()[0] = %+v, want payload and time", events[0])
+	}
+	if events[1].OccurredAt != 100 {
+		t.Errorf("LoadEvents()[1].OccurredAt = %d, want 100", events[1].OccurredAt)
+	}
+
+	if got := loadEvents(t, s, stream, 2); len(got) != 2 || got[0].SequenceNo != 2 {
+		t.Errorf("LoadEvents() from 2 = %+v, want events 2 and 3", got)
+	}
+	if got := loadEvents(t, s, "missing", 0); len(got) != 0 {
+		t.Errorf("LoadEvents() of missing stream = %+v, want none", got)
+	}
+
+	// устаревший или опережающий номер отклоняется, поток не меняется
+	for _, seq := range []int64{0, 2, 4} {
+		err = s.AppendEvents(ctx, stream, seq, []storage.Event{{EventType: "stale"}})
+		if !errors.Is(err, storage.ErrVersionConflict) {
+			t.Errorf("AppendEvents() at seq %d error = %v, want ErrVersionConflict", seq, err)
+		}
+	}
+	if got := loadEvents(t, s, stream, 0); len(got) != 3 {
+		t.Errorf("LoadEvents() after conflicts = %d events, want 3", len(got))
+	}
+}
+
+// Из параллельных добавлений с одним номером проходит только одно.
+func testEventsConcurrent(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	const (
+		stream  = "stream"
+		workers = 10
+	)
+
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = s.AppendEvents(ctx, stream, 0, []storage.Event{{EventType: "created"}, {EventType: "updated"}})
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, storage.ErrVersionConflict):
+			t.Errorf("AppendEvents() error = %v, want nil or ErrVersionConflict", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("%d concurrent AppendEvents() succeeded, want 1", succeeded)
+	}
+	if got := loadEvents(t, s, stream, 0); len(got) != 2 {
+		t.Errorf("LoadEvents() = %+v, want 2 events", got)
+	}
+}
@@ -0,0 +1,64 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"testing"
+)
+
+func testTaskByExternalID(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	github, jira, issue := "github", "jira", "42"
+
+	id := addTask(t, s, storage.Task{Title: "github", ExternalSystem: &github, ExternalID: &issue})
+	// тот же ID в другой системе и задачи без ссылки не конфликтуют
+	other := addTask(t, s, storage.Task{Title: "jira", ExternalSystem: &jira, ExternalID: &issue})
+	plain := addTask(t, s, storage.Task{Title: "plain"})
+	addTask(t, s, storage.Task{Title: "plain"})
+
+	got, err := s.TaskByExternalID(ctx, github, issue)
+	if err != nil {
+		t.Fatalf("TaskByExternalID() error = %v", err)
+	}
+	if got.ID != id || got.ExternalSystem == nil || *got.ExternalSystem != github ||
+		got.ExternalID == nil || *got.ExternalID != issue {
+		t.Errorf("TaskByExternalID(%s, %s) = %+v, want task %d", github, issue, got, id)
+	}
+	got, err = s.TaskByExternalID(ctx, jira, issue)
+	if err != nil {
+		t.Fatalf("TaskByExternalID() error = %v", err)
+	}
+	if got.ID != other {
+		t.Errorf("TaskByExternalID(%s, %s) = task %d, want %d", jira, issue, got.ID, other)
+	}
+	if task := taskByID(t, s, plain); task.ExternalSystem != nil || task.ExternalID != nil {
+		t.Errorf("task without external reference = %+v, want nil reference", task)
+	}
+
+	for _, ref := range [][2]string{{github, "43"}, {"gitlab", issue}, {"", ""}} {
+		_, err = s.TaskByExternalID(ctx, ref[0], ref[1])
+		if !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("TaskByExternalID(%q, %q) error = %v, want ErrNotFound", ref[0], ref[1], err)
+		}
+	}
+
+	// пара (система, ID) уникальна
+	_, err = s.AddTask(ctx, storage.Task{Title: "duplicate", ExternalSystem: &github, ExternalID: &issue})
+	if !errors.Is(err, storage.ErrConflict) {
+		t.Errorf("AddTask() with existing external reference error = %v, want ErrConflict", err)
+	}
+	label := addLabel(t, s, "label")
+	_, err = s.AddTaskWithLabels(ctx, storage.Task{Title: "duplicate", ExternalSystem: &github, ExternalID: &issue}, []int{label})
+	if !errors.Is(err, storage.ErrConflict) {
+		t.Errorf("AddTaskWithLabels() with existing external reference error = %v, want ErrConflict", err)
+	}
+
+	err = s.DeleteTask(ctx, id)
+	if err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	if _, err = s.TaskByExternalID(ctx, github, issue); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("TaskByExternalID() of deleted task error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,56 @@
+package storagetest
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"testing"
+)
+
+func testFilterTasks(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	alice := addUser(t, s, "alice")
+	bob := addUser(t, s, "bob")
+	bug := addLabel(t, s, "bug")
+
+	newTask := func(title string, author int, opened, closed int64) int {
+		id := addTask(t, s, storage.Task{Title: title})
+		patchTask(t, s, id, storage.TaskPatch{AuthorID: &author, Opened: &opened, Closed: &closed})
+		return id
+	}
+	fix := newTask("Fix login", alice, 100, 0)
+	docs := newTask("Write docs", bob, 200, 250)
+	crash := newTask("Crash on LOGIN page", bob, 300, 0)
+	err := s.AssignLabel(ctx, crash, bug)
+	if err != nil {
+		t.Fatalf("AssignLabel() error = %v", err)
+	}
+
+	ptr := func(v int64) *int64 { return &v }
+	tests := []struct {
+		name   string
+		filter storage.TaskFilter
+		want   []int
+	}{
+		{"empty", storage.TaskFilter{}, []int{fix, docs, crash}},
+		{"author", storage.TaskFilter{AuthorID: &bob}, []int{docs, crash}},
+		{"label", storage.TaskFilter{LabelID: &bug}, []int{crash}},
+		{"opened after", storage.TaskFilter{OpenedAfter: ptr(100)}, []int{docs, crash}},
+		{"opened before", storage.TaskFilter{OpenedBefore: ptr(300)}, []int{fix, docs}},
+		{"closed after", storage.TaskFilter{ClosedAfter: ptr(0)}, []int{docs}},
+		{"title case insensitive", storage.TaskFilter{TitleContains: "login"}, []int{fix, crash}},
+		{"combined", storage.TaskFilter{AuthorID: &bob, TitleContains: "login"}, []int{crash}},
+		{"no match", storage.TaskFilter{AuthorID: &alice, LabelID: &bug}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.FilterTasks(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("FilterTasks() error = %v", err)
+			}
+			if !slices.Equal(ids(got), tt.want) {
+				t.Errorf("FilterTasks() = %v, want %v", ids(got), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package storagetest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"testing"
+)
+
+// gdprFixture - пользователи и задачи для тестов выгрузки и удаления
+// персональных данных.
+type gdprFixture struct {
+	alice, bob                    int
+	authored, assigned, unrelated int
+}
+
+// newGDPRFixture создаёт пользователей alice и bob, задачи, в которых
+// alice - автор и исполнитель, их комментарии и учёт времени.
+func newGDPRFixture(t *testing.T, s storage.Interface) gdprFixture {
+	t.Helper()
+	ctx := context.Background()
+	f := gdprFixture{
+		alice:     addUser(t, s, "alice"),
+		bob:       addUser(t, s, "bob"),
+		authored:  addTask(t, s, storage.Task{Title: "authored"}),
+		assigned:  addTask(t, s, storage.Task{Title: "assigned"}),
+		unrelated: addTask(t, s, storage.Task{Title: "unrelated"}),
+	}
+	for _, a := range [][3]int{{f.authored, f.alice, f.bob}, {f.assigned, f.bob, f.alice}} {
+		task := taskByID(t, s, a[0])
+		task.AuthorID, task.AssignedID = a[1], a[2]
+		err := s.UpdateTask(ctx, task)
+		if err != nil {
+			t.Fatalf("UpdateTask() error = %v", err)
+		}
+	}
+	addComment(t, s, storage.Comment{TaskID: f.authored, AuthorID: f.alice, Body: "alice"})
+	addComment(t, s, storage.Comment{TaskID: f.authored, AuthorID: f.bob, Body: "bob"})
+	for _, e := range []storage.TimeEntry{
+		{TaskID: f.assigned, UserID: f.alice, Minutes: 30},
+		{TaskID: f.authored, UserID: f.bob, Minutes: 15},
+	} {
+		_, err := s.LogTime(ctx, e)
+		if err != nil {
+			t.Fatalf("LogTime() error = %v", err)
+		}
+	}
+	return f
+}
+
+func testExportUserData(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	f := newGDPRFixture(t, s)
+
+	export, err := s.ExportUserData(ctx, f.alice)
+	if err != nil {
+		t.Fatalf("ExportUserData() error = %v", err)
+	}
+	if export.User.ID != f.alice || export.User.Name != "alice" {
+		t.Errorf("ExportUserData().User = %+v, want alice", export.User)
+	}
+	if got := ids(export.AuthoredTasks); !slices.Equal(got, []int{f.authored}) {
+		t.Errorf("ExportUserData().AuthoredTasks = %v, want [%d]", got, f.authored)
+	}
+	if got := ids(export.AssignedTasks); !slices.Equal(got, []int{f.assigned}) {
+		t.Errorf("ExportUserData().AssignedTasks = %v, want [%d]", got, f.assigned)
+	}
+	if len(export.Comments) != 1 || export.Comments[0].Body != "alice" {
+		t.Errorf("ExportUserData().Comments = %+v, want alice's comment", export.Comments)
+	}
+	if len(export.TimeEntries) != 1 || export.TimeEntries[0].Minutes != 30 {
+		t.Errorf("ExportUserData().TimeEntries = %+v, want alice's entry", export.TimeEntries)
+	}
+
+	// выгрузка сериализуется в JSON без потерь
+	b, err := json.Marshal(export)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var decoded storage.UserDataExport
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if decoded.User != export.User || len(decoded.AuthoredTasks) != 1 || len(decoded.Comments) != 1 {
+		t.Errorf("decoded export = %+v, want %+v", decoded, *export)
+	}
+
+	if _, err := s.ExportUserData(ctx, 1000); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("ExportUserData() of missing user error = %v, want ErrNotFound", err)
+	}
+}
+
+func testEraseUser(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	f := newGDPRFixture(t, s)
+	err := s.WatchTask(ctx, f.alice, f.unrelated)
+	if err != nil {
+		t.Fatalf("WatchTask() error = %v", err)
+	}
+	err = s.SetNotificationPreference(ctx, storage.NotificationPreference{UserID: f.alice, TaskUpdated: true})
+	if err != nil {
+		t.Fatalf("SetNotificationPreference() error = %v", err)
+	}
+	_, err = s.CreateAPIKey(ctx, storage.APIKey{UserID: f.alice})
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+
+	err = s.EraseUser(ctx, f.alice)
+	if err != nil {
+		t.Fatalf("EraseUser() error = %v", err)
+	}
+
+	export, err := s.ExportUserData(ctx, f.alice)
+	if err != nil {
+		t.Fatalf("ExportUserData() error = %v", err)
+	}
+	if export.User.Name != "Deleted User" {
+		t.Errorf("user name after EraseUser = %q, want Deleted User", export.User.Name)
+	}
+	if len(export.AuthoredTasks)+len(export.AssignedTasks)+len(export.Comments)+len(export.TimeEntries) != 0 {
+		t.Errorf("ExportUserData() after EraseUser = %+v, want no personal data", export)
+	}
+
+	// задачи остаются, но без пользователя; данные других пользователей не затрагиваются
+	if task := taskByID(t, s, f.authored); task.AuthorID != 0 || task.AssignedID != f.bob {
+		t.Errorf("authored task after EraseUser = %+v, want author 0 and assignee bob", task)
+	}
+	if task := taskByID(t, s, f.assigned); task.AuthorID != f.bob || task.AssignedID != 0 {
+		t.Errorf("assigned task after EraseUser = %+v, want author bob and assignee 0", task)
+	}
+	comments, err := s.CommentsByTask(ctx, f.authored)
+	if err != nil {
+		t.Fatalf("CommentsByTask() error = %v", err)
+	}
+	if len(comments) != 1 || comments[0].AuthorID != f.bob {
+		t.Errorf("CommentsByTask() after EraseUser = %+v, want only bob's comment", comments)
+	}
+	entries, err := s.TimeEntriesByUser(ctx, f.bob)
+	if err != nil {
+		t.Fatalf("TimeEntriesByUser() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("TimeEntriesByUser(bob) after EraseUser = %+v, want 1 entry", entries)
+	}
+
+	watchers, err := s.WatchersOfTask(ctx, f.unrelated)
+	if err != nil {
+		t.Fatalf("WatchersOfTask() error = %v", err)
+	}
+	if len(watchers) != 0 {
+		t.Errorf("WatchersOfTask() after EraseUser = %v, want none", userIDs(watchers))
+	}
+	if _, err := s.GetNotificationPreference(ctx, f.alice); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("GetNotificationPreference() after EraseUser error = %v, want ErrNotFound", err)
+	}
+	keys, err := s.APIKeysByUser(ctx, f.alice)
+	if err != nil {
+		t.Fatalf("APIKeysByUser() error = %v", err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("APIKeysByUser() after EraseUser = %+v, want none", keys)
+	}
+
+	if err := s.EraseUser(ctx, 1000); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("EraseUser() of missing user error = %v, want ErrNotFound", err)
+	}
+}
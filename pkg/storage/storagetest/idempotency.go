@@ -0,0 +1,92 @@
+package storagetest
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"sync"
+	"testing"
+)
+
+func testIdempotencyKey(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	key, otherKey, labelKey := "request-1", "request-2", "request-3"
+
+	first := addTask(t, s, storage.Task{Title: "first", IdempotencyKey: &key})
+	retry := addTask(t, s, storage.Task{Title: "retry", IdempotencyKey: &key})
+	if retry != first {
+		t.Errorf("AddTask() with repeated key = %d, want %d", retry, first)
+	}
+	if got := taskByID(t, s, first); got.Title != "first" {
+		t.Errorf("TaskById() = %+v, want the first task unchanged", got)
+	}
+
+	other := addTask(t, s, storage.Task{Title: "other", IdempotencyKey: &otherKey})
+	// задачи без ключа создаются каждый раз заново
+	a := addTask(t, s, storage.Task{Title: "no key"})
+	b := addTask(t, s, storage.Task{Title: "no key"})
+	if other == first || a == b {
+		t.Errorf("AddTask() ids = %d, %d, %d, want distinct tasks", other, a, b)
+	}
+
+	// повтор с метками возвращает ту же задачу, что и без них
+	label := addLabel(t, s, "label")
+	for _, labelIDs := range [][]int{nil, {label}} {
+		id, err := s.AddTaskWithLabels(ctx, storage.Task{Title: "retry", IdempotencyKey: &key}, labelIDs)
+		if err != nil {
+			t.Fatalf("AddTaskWithLabels(%v) error = %v", labelIDs, err)
+		}
+		if id != first {
+			t.Errorf("AddTaskWithLabels(%v) with repeated key = %d, want %d", labelIDs, id, first)
+		}
+	}
+	labeled, err := s.AddTaskWithLabels(ctx, storage.Task{Title: "labeled", IdempotencyKey: &labelKey}, []int{label})
+	if err != nil {
+		t.Fatalf("AddTaskWithLabels() error = %v", err)
+	}
+	if retry := addTask(t, s, storage.Task{Title: "retry", IdempotencyKey: &labelKey}); retry != labeled {
+		t.Errorf("AddTask() with key of AddTaskWithLabels = %d, want %d", retry, labeled)
+	}
+
+	n, err := s.TaskCount(ctx)
+	if err != nil {
+		t.Fatalf("TaskCount() error = %v", err)
+	}
+	if n != 5 {
+		t.Errorf("TaskCount() = %d, want 5", n)
+	}
+}
+
+// Параллельные вызовы с одним ключом создают одну задачу.
+func testIdempotencyKeyConcurrent(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	key := "concurrent"
+
+	const workers = 10
+	got := make([]int, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			got[i], errs[i] = s.AddTask(ctx, storage.Task{Title: "task", IdempotencyKey: &key})
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range got {
+		if errs[i] != nil {
+			t.Fatalf("AddTask() error = %v", errs[i])
+		}
+		if got[i] != got[0] {
+			t.Fatalf("AddTask() ids = %v, want the same id", got)
+		}
+	}
+	n, err := s.TaskCount(ctx)
+	if err != nil {
+		t.Fatalf("TaskCount() error = %v", err)
+	}
+	if n != 1 {
+		t.Errorf("TaskCount() = %d, want 1", n)
+	}
+}
@@ -0,0 +1,219 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"testing"
+)
+
+// addLabel создаёт метку и возвращает её ID.
+func addLabel(t *testing.T, s storage.Interface, name string) int {
+	t.Helper()
+	id, err := s.AddLabel(context.Background(), storage.Label{Name: name})
+	if err != nil {
+		t.Fatalf("AddLabel() error = %v", err)
+	}
+	return id
+}
+
+func testLabels(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	bug := addLabel(t, s, "bug")
+	feature := addLabel(t, s, "feature")
+
+	l, err := s.LabelByID(ctx, bug)
+	if err != nil {
+		t.Fatalf("LabelByID() error = %v", err)
+	}
+	if *l != (storage.Label{ID: bug, Name: "bug"}) {
+		t.Errorf("LabelByID() = %+v, want label %d bug", l, bug)
+	}
+
+	err = s.UpdateLabel(ctx, storage.Label{ID: feature, Name: "enhancement"})
+	if err != nil {
+		t.Fatalf("UpdateLabel() error = %v", err)
+	}
+	labels, err := s.Labels(ctx)
+	if err != nil {
+		t.Fatalf("Labels() error = %v", err)
+	}
+	want := []storage.Label{{ID: bug, Name: "bug"}, {ID: feature, Name: "enhancement"}}
+	if !slices.Equal(labels, want) {
+		t.Errorf("Labels() = %+v, want %+v", labels, want)
+	}
+
+	err = s.DeleteLabel(ctx, bug)
+	if err != nil {
+		t.Fatalf("DeleteLabel() error = %v", err)
+	}
+	_, err = s.LabelByID(ctx, bug)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("LabelByID() after DeleteLabel error = %v, want ErrNotFound", err)
+	}
+	err = s.UpdateLabel(ctx, storage.Label{ID: bug, Name: "defect"})
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("UpdateLabel() of deleted label error = %v, want ErrNotFound", err)
+	}
+	err = s.DeleteLabel(ctx, bug)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("DeleteLabel() of deleted label error = %v, want ErrNotFound", err)
+	}
+}
+
+func testAssignLabel(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	bug := addLabel(t, s, "bug")
+	first := addTask(t, s, storage.Task{Title: "first"})
+	second := addTask(t, s, storage.Task{Title: "second"})
+
+	for _, id := range []int{first, second, first} {
+		err := s.AssignLabel(ctx, id, bug)
+		if err != nil {
+			t.Fatalf("AssignLabel(%d) error = %v", id, err)
+		}
+	}
+	got, err := s.TasksByLabel(ctx, bug)
+	if err != nil {
+		t.Fatalf("TasksByLabel() error = %v", err)
+	}
+	if !slices.Equal(ids(got), []int{first, second}) {
+		t.Errorf("TasksByLabel() = %v, want [%d %d]", ids(got), first, second)
+	}
+
+	err = s.RemoveLabel(ctx, first, bug)
+	if err != nil {
+		t.Fatalf("RemoveLabel() error = %v", err)
+	}
+	got, err = s.TasksByLabel(ctx, bug)
+	if err != nil {
+		t.Fatalf("TasksByLabel() error = %v", err)
+	}
+	if !slices.Equal(ids(got), []int{second}) {
+		t.Errorf("TasksByLabel() after RemoveLabel = %v, want [%d]", ids(got), second)
+	}
+}
+
+func testLabelConflict(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	addLabel(t, s, "bug")
+	feature := addLabel(t, s, "feature")
+
+	_, err := s.AddLabel(ctx, storage.Label{Name: "bug"})
+	if !errors.Is(err, storage.ErrConflict) {
+		t.Errorf("AddLabel() with taken name error = %v, want ErrConflict", err)
+	}
+	err = s.UpdateLabel(ctx, storage.Label{ID: feature, Name: "bug"})
+	if !errors.Is(err, storage.ErrConflict) {
+		t.Errorf("UpdateLabel() with taken name error = %v, want ErrConflict", err)
+	}
+}
+
+func testAddTaskWithLabels(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	bug := addLabel(t, s, "bug")
+	urgent := addLabel(t, s, "urgent")
+
+	id, err := s.AddTaskWithLabels(ctx, storage.Task{Title: "task"}, []int{bug, urgent})
+	if err != nil {
+		t.Fatalf("AddTaskWithLabels() error = %v", err)
+	}
+	if task := taskByID(t, s, id); task.Title != "task" {
+		t.Errorf("TaskById() title = %q, want task", task.Title)
+	}
+	for _, label := range []int{bug, urgent} {
+		got, err := s.TasksByLabel(ctx, label)
+		if err != nil {
+			t.Fatalf("TasksByLabel() error = %v", err)
+		}
+		if !slices.Equal(ids(got), []int{id}) {
+			t.Errorf("TasksByLabel(%d) = %v, want [%d]", label, ids(got), id)
+		}
+	}
+
+	noLabels, err := s.AddTaskWithLabels(ctx, storage.Task{Title: "no labels"}, nil)
+	if err != nil {
+		t.Fatalf("AddTaskWithLabels() without labels error = %v", err)
+	}
+	taskByID(t, s, noLabels)
+}
+
+func testTasksWithLabels(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	bug := addLabel(t, s, "bug")
+	urgent := addLabel(t, s, "urgent")
+	labeled := addTask(t, s, storage.Task{Title: "labeled"})
+	plain := addTask(t, s, storage.Task{Title: "plain"})
+	for _, label := range []int{urgent, bug} {
+		err := s.AssignLabel(ctx, labeled, label)
+		if err != nil {
+			t.Fatalf("AssignLabel() error = %v", err)
+		}
+	}
+
+	got, err := s.TasksWithLabels(ctx)
+	if err != nil {
+		t.Fatalf("TasksWithLabels() error = %v", err)
+	}
+	if len(got) != 2 || got[0].ID != labeled || got[1].ID != plain {
+		t.Fatalf("TasksWithLabels() = %+v, want tasks %d and %d", got, labeled, plain)
+	}
+	want := []storage.Label{{ID: bug, Name: "bug"}, {ID: urgent, Name: "urgent"}}
+	if !slices.Equal(got[0].Labels, want) {
+		t.Errorf("labels of task %d = %+v, want %+v", labeled, got[0].Labels, want)
+	}
+	if len(got[1].Labels) != 0 {
+		t.Errorf("labels of task %d = %+v, want none", plain, got[1].Labels)
+	}
+}
+
+func testTasksByLabels(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	bug := addLabel(t, s, "bug")
+	urgent := addLabel(t, s, "urgent")
+	docs := addLabel(t, s, "docs")
+	both := addTask(t, s, storage.Task{Title: "both"})
+	onlyBug := addTask(t, s, storage.Task{Title: "only bug"})
+	addTask(t, s, storage.Task{Title: "none"})
+	for _, l := range [][2]int{{both, bug}, {both, urgent}, {onlyBug, bug}} {
+		err := s.AssignLabel(ctx, l[0], l[1])
+		if err != nil {
+			t.Fatalf("AssignLabel() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		labels []int
+		mode   storage.LabelFilterMode
+		want   []int
+	}{
+		{"any bug", []int{bug}, storage.LabelFilterAny, []int{both, onlyBug}},
+		{"any urgent", []int{urgent}, storage.LabelFilterAny, []int{both}},
+		{"any of two", []int{urgent, bug}, storage.LabelFilterAny, []int{both, onlyBug}},
+		{"any unused", []int{docs}, storage.LabelFilterAny, nil},
+		{"all bug", []int{bug}, storage.LabelFilterAll, []int{both, onlyBug}},
+		{"all of two", []int{bug, urgent}, storage.LabelFilterAll, []int{both}},
+		{"all with duplicates", []int{bug, urgent, bug}, storage.LabelFilterAll, []int{both}},
+		{"all of three", []int{bug, urgent, docs}, storage.LabelFilterAll, nil},
+	}
+	for _, tt := range tests {
+		got, err := s.TasksByLabels(ctx, tt.labels, tt.mode)
+		if err != nil {
+			t.Fatalf("TasksByLabels(%s) error = %v", tt.name, err)
+		}
+		if !slices.Equal(ids(got), tt.want) {
+			t.Errorf("TasksByLabels(%s) = %v, want %v", tt.name, ids(got), tt.want)
+		}
+	}
+
+	_, err := s.TasksByLabels(ctx, nil, storage.LabelFilterAny)
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("TasksByLabels() without labels error = %v, want ErrInvalidArgument", err)
+	}
+	_, err = s.TasksByLabels(ctx, []int{bug}, storage.LabelFilterMode(100))
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("TasksByLabels() with unknown mode error = %v, want ErrInvalidArgument", err)
+	}
+}
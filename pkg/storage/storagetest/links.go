@@ -0,0 +1,85 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"testing"
+)
+
+func testTaskLinks(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	a := addTask(t, s, storage.Task{Title: "a"})
+	b := addTask(t, s, storage.Task{Title: "b"})
+	c := addTask(t, s, storage.Task{Title: "c"})
+
+	ab, err := s.AddTaskLink(ctx, storage.TaskLinkRecord{TaskID: a, LinkedTaskID: b, Type: storage.LinkBlocks})
+	if err != nil {
+		t.Fatalf("AddTaskLink() error = %v", err)
+	}
+	ac, err := s.AddTaskLink(ctx, storage.TaskLinkRecord{TaskID: a, LinkedTaskID: c, Type: storage.LinkRelated})
+	if err != nil {
+		t.Fatalf("AddTaskLink() error = %v", err)
+	}
+
+	got, err := s.TaskLinks(ctx, a)
+	if err != nil {
+		t.Fatalf("TaskLinks() error = %v", err)
+	}
+	want := []storage.TaskLinkRecord{
+		{ID: ab, TaskID: a, LinkedTaskID: b, Type: storage.LinkBlocks},
+		{ID: ac, TaskID: a, LinkedTaskID: c, Type: storage.LinkRelated},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("TaskLinks(a) = %v, want %v", got, want)
+	}
+
+	// связь направленная: обратная не создаётся
+	got, err = s.TaskLinks(ctx, b)
+	if err != nil {
+		t.Fatalf("TaskLinks() error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("TaskLinks(b) = %v, want none", got)
+	}
+
+	// та же связь другого типа допустима, повтор - нет
+	_, err = s.AddTaskLink(ctx, storage.TaskLinkRecord{TaskID: a, LinkedTaskID: b, Type: storage.LinkDuplicates})
+	if err != nil {
+		t.Errorf("AddTaskLink() with another type error = %v", err)
+	}
+	_, err = s.AddTaskLink(ctx, storage.TaskLinkRecord{TaskID: a, LinkedTaskID: b, Type: storage.LinkBlocks})
+	if !errors.Is(err, storage.ErrConflict) {
+		t.Errorf("AddTaskLink() duplicate error = %v, want ErrConflict", err)
+	}
+
+	invalid := []storage.TaskLinkRecord{
+		{TaskID: a, LinkedTaskID: a, Type: storage.LinkRelated},
+		{TaskID: b, LinkedTaskID: a, Type: "parent"},
+	}
+	for _, l := range invalid {
+		_, err = s.AddTaskLink(ctx, l)
+		if !errors.Is(err, storage.ErrInvalidArgument) {
+			t.Errorf("AddTaskLink(%v) error = %v, want ErrInvalidArgument", l, err)
+		}
+	}
+
+	err = s.DeleteTaskLink(ctx, ab)
+	if err != nil {
+		t.Fatalf("DeleteTaskLink() error = %v", err)
+	}
+	got, err = s.TaskLinks(ctx, a)
+	if err != nil {
+		t.Fatalf("TaskLinks() error = %v", err)
+	}
+	for _, l := range got {
+		if l.ID == ab {
+			t.Errorf("TaskLinks() after delete = %v, still contains %d", got, ab)
+		}
+	}
+	err = s.DeleteTaskLink(ctx, ab)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("DeleteTaskLink() twice error = %v, want ErrNotFound", err)
+	}
+}
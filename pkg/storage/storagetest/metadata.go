@@ -0,0 +1,78 @@
+package storagetest
+
+import (
+	"context"
+	"maps"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"testing"
+)
+
+func testMetadata(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	meta := map[string]string{
+		"env":     "prod",
+		"team":    "core",
+		"empty":   "",
+		"unicode": "задача \"1\"",
+	}
+	prod := addTask(t, s, storage.Task{Title: "prod", Metadata: meta})
+	staging := addTask(t, s, storage.Task{Title: "staging", Metadata: map[string]string{"env": "staging"}})
+	plain := addTask(t, s, storage.Task{Title: "plain"})
+
+	// изменение исходного словаря не влияет на сохранённую задачу
+	want := maps.Clone(meta)
+	meta["env"] = "changed"
+
+	if got := taskByID(t, s, prod).Metadata; !maps.Equal(got, want) {
+		t.Errorf("Metadata = %v, want %v", got, want)
+	}
+	if got := taskByID(t, s, plain).Metadata; len(got) != 0 {
+		t.Errorf("Metadata of task without attributes = %v, want empty", got)
+	}
+
+	tests := []struct {
+		key, value string
+		want       []int
+	}{
+		{"env", "prod", []int{prod}},
+		{"env", "staging", []int{staging}},
+		{"empty", "", []int{prod}},
+		{"env", "dev", nil},
+		{"missing", "", nil},
+	}
+	for _, tt := range tests {
+		got, err := s.TasksByMetadataKey(ctx, tt.key, tt.value)
+		if err != nil {
+			t.Fatalf("TasksByMetadataKey(%q, %q) error = %v", tt.key, tt.value, err)
+		}
+		if !slices.Equal(ids(got), tt.want) {
+			t.Errorf("TasksByMetadataKey(%q, %q) = %v, want %v", tt.key, tt.value, ids(got), tt.want)
+		}
+	}
+
+	task := taskByID(t, s, staging)
+	task.Metadata = map[string]string{"env": "prod", "owner": "alice"}
+	err := s.UpdateTask(ctx, task)
+	if err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if got := taskByID(t, s, staging).Metadata; !maps.Equal(got, task.Metadata) {
+		t.Errorf("Metadata after UpdateTask = %v, want %v", got, task.Metadata)
+	}
+	got, err := s.TasksByMetadataKey(ctx, "env", "prod")
+	if err != nil {
+		t.Fatalf("TasksByMetadataKey() error = %v", err)
+	}
+	if !slices.Equal(ids(got), []int{prod, staging}) {
+		t.Errorf("TasksByMetadataKey() after UpdateTask = %v, want %v", ids(got), []int{prod, staging})
+	}
+
+	upserted, err := s.UpsertTask(ctx, storage.Task{Title: "upserted", Metadata: map[string]string{"env": "qa"}})
+	if err != nil {
+		t.Fatalf("UpsertTask() error = %v", err)
+	}
+	if got := taskByID(t, s, upserted).Metadata; got["env"] != "qa" {
+		t.Errorf("Metadata after UpsertTask = %v, want env qa", got)
+	}
+}
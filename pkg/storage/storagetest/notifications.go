@@ -0,0 +1,107 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"testing"
+)
+
+func testNotificationPreferences(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	alice := addUser(t, s, "alice")
+
+	if _, err := s.GetNotificationPreference(ctx, alice); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("GetNotificationPreference() without preference error = %v, want ErrNotFound", err)
+	}
+
+	// повторная запись заменяет настройки
+	for _, p := range []storage.NotificationPreference{
+		{UserID: alice, TaskCreated: true, CommentAdded: true},
+		{UserID: alice, TaskUpdated: true, TaskAssigned: true},
+	} {
+		err := s.SetNotificationPreference(ctx, p)
+		if err != nil {
+			t.Fatalf("SetNotificationPreference() error = %v", err)
+		}
+		got, err := s.GetNotificationPreference(ctx, alice)
+		if err != nil {
+			t.Fatalf("GetNotificationPreference() error = %v", err)
+		}
+		if *got != p {
+			t.Errorf("GetNotificationPreference() = %+v, want %+v", *got, p)
+		}
+	}
+}
+
+func testUsersSubscribedToTask(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	author := addUser(t, s, "author")
+	assignee := addUser(t, s, "assignee")
+	watcher := addUser(t, s, "watcher")
+	muted := addUser(t, s, "muted")       // наблюдает, но уведомления выключены
+	silent := addUser(t, s, "silent")     // наблюдает без настроек
+	stranger := addUser(t, s, "stranger") // не связан с задачей
+
+	id := addTask(t, s, storage.Task{Title: "task"})
+	task := taskByID(t, s, id)
+	task.AuthorID = author
+	task.AssignedID = assignee
+	err := s.UpdateTask(ctx, task)
+	if err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	for _, u := range []int{watcher, muted, silent} {
+		err = s.WatchTask(ctx, u, id)
+		if err != nil {
+			t.Fatalf("WatchTask() error = %v", err)
+		}
+	}
+
+	for _, u := range []int{author, assignee, watcher, stranger} {
+		err = s.SetNotificationPreference(ctx, storage.NotificationPreference{UserID: u, TaskUpdated: true})
+		if err != nil {
+			t.Fatalf("SetNotificationPreference() error = %v", err)
+		}
+	}
+	err = s.SetNotificationPreference(ctx, storage.NotificationPreference{UserID: muted, CommentAdded: true})
+	if err != nil {
+		t.Fatalf("SetNotificationPreference() error = %v", err)
+	}
+
+	tests := []struct {
+		event string
+		want  []int
+	}{
+		{storage.NotifyTaskUpdated, []int{author, assignee, watcher}},
+		{storage.NotifyCommentAdded, []int{muted}},
+		{storage.NotifyTaskCreated, nil},
+	}
+	for _, tt := range tests {
+		got, err := s.UsersSubscribedToTask(ctx, id, tt.event)
+		if err != nil {
+			t.Fatalf("UsersSubscribedToTask(%s) error = %v", tt.event, err)
+		}
+		if !slices.Equal(userIDs(got), tt.want) {
+			t.Errorf("UsersSubscribedToTask(%s) = %v, want %v", tt.event, userIDs(got), tt.want)
+		}
+	}
+
+	// после отказа от наблюдения уведомления не приходят
+	err = s.UnwatchTask(ctx, watcher, id)
+	if err != nil {
+		t.Fatalf("UnwatchTask() error = %v", err)
+	}
+	got, err := s.UsersSubscribedToTask(ctx, id, storage.NotifyTaskUpdated)
+	if err != nil {
+		t.Fatalf("UsersSubscribedToTask() error = %v", err)
+	}
+	if want := []int{author, assignee}; !slices.Equal(userIDs(got), want) {
+		t.Errorf("UsersSubscribedToTask() after UnwatchTask = %v, want %v", userIDs(got), want)
+	}
+
+	if _, err = s.UsersSubscribedToTask(ctx, id, "unknown"); !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("UsersSubscribedToTask(unknown) error = %v, want ErrInvalidArgument", err)
+	}
+}
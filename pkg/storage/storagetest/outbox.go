@@ -0,0 +1,82 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"testing"
+)
+
+// topics возвращает темы сообщений.
+func topics(msgs []storage.OutboxMessage) []string {
+	var got []string
+	for _, m := range msgs {
+		got = append(got, m.Topic)
+	}
+	return got
+}
+
+func testOutbox(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+
+	// при откате транзакции сообщения тоже отменяются
+	tx := beginTx(t, s)
+	addTask(t, tx, storage.Task{Title: "discarded"})
+	err := s.AppendOutbox(ctx, tx, []storage.OutboxMessage{{Topic: "discarded", Payload: []byte("1")}})
+	if err != nil {
+		t.Fatalf("AppendOutbox() error = %v", err)
+	}
+	if err := tx.Rollback(ctx); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	pending, err := s.PendingOutbox(ctx, 10)
+	if err != nil {
+		t.Fatalf("PendingOutbox() error = %v", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("PendingOutbox() after Rollback = %v, want empty", topics(pending))
+	}
+
+	tx = beginTx(t, s)
+	addTask(t, tx, storage.Task{Title: "committed"})
+	err = s.AppendOutbox(ctx, tx, []storage.OutboxMessage{
+		{Topic: "first", Payload: []byte("1")},
+		{Topic: "second", Payload: []byte("2")},
+	})
+	if err != nil {
+		t.Fatalf("AppendOutbox() error = %v", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	err = s.AppendOutbox(ctx, nil, []storage.OutboxMessage{{Topic: "third", Payload: []byte("3")}})
+	if err != nil {
+		t.Fatalf("AppendOutbox() without tx error = %v", err)
+	}
+
+	pending, err = s.PendingOutbox(ctx, 2)
+	if err != nil {
+		t.Fatalf("PendingOutbox() error = %v", err)
+	}
+	if got := topics(pending); len(got) != 2 || got[0] != "first" || got[1] != "second" {
+		t.Fatalf("PendingOutbox(2) = %v, want [first second]", got)
+	}
+	if string(pending[1].Payload) != "2" || pending[1].SentAt != nil || pending[1].CreatedAt == 0 {
+		t.Errorf("PendingOutbox()[1] = %+v, want unsent message with payload 2", pending[1])
+	}
+
+	if err := s.MarkOutboxSent(ctx, []int64{pending[0].ID}); err != nil {
+		t.Fatalf("MarkOutboxSent() error = %v", err)
+	}
+	pending, err = s.PendingOutbox(ctx, 10)
+	if err != nil {
+		t.Fatalf("PendingOutbox() error = %v", err)
+	}
+	if got := topics(pending); len(got) != 2 || got[0] != "second" || got[1] != "third" {
+		t.Errorf("PendingOutbox() after MarkOutboxSent = %v, want [second third]", got)
+	}
+
+	if _, err := s.PendingOutbox(ctx, 0); !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("PendingOutbox(0) error = %v, want ErrInvalidArgument", err)
+	}
+}
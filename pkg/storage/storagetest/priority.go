@@ -0,0 +1,54 @@
+package storagetest
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"testing"
+)
+
+func testTaskPriority(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	low := addTask(t, s, storage.Task{Title: "low"})
+	high := addTask(t, s, storage.Task{Title: "high", Priority: storage.PriorityHigh})
+	critical := addTask(t, s, storage.Task{Title: "critical"})
+
+	if task := taskByID(t, s, low); task.Priority != storage.PriorityLow {
+		t.Errorf("task without priority has priority %v, want PriorityLow", task.Priority)
+	}
+
+	// приоритет изменяется и полным, и частичным обновлением
+	task := taskByID(t, s, critical)
+	task.Priority = storage.PriorityCritical
+	err := s.UpdateTask(ctx, task)
+	if err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	medium := addTask(t, s, storage.Task{Title: "medium"})
+	p := storage.PriorityMedium
+	patchTask(t, s, medium, storage.TaskPatch{Priority: &p})
+
+	got, err := s.TasksOrderedByPriority(ctx)
+	if err != nil {
+		t.Fatalf("TasksOrderedByPriority() error = %v", err)
+	}
+	if want := []int{critical, high, medium, low}; !slices.Equal(ids(got), want) {
+		t.Errorf("TasksOrderedByPriority() = %v, want %v", ids(got), want)
+	}
+
+	got, err = s.TasksByPriority(ctx, storage.PriorityMedium)
+	if err != nil {
+		t.Fatalf("TasksByPriority() error = %v", err)
+	}
+	if !slices.Equal(ids(got), []int{medium}) {
+		t.Errorf("TasksByPriority(PriorityMedium) = %v, want [%d]", ids(got), medium)
+	}
+
+	got, err = s.FilterTasks(ctx, storage.TaskFilter{Priority: &p})
+	if err != nil {
+		t.Fatalf("FilterTasks() error = %v", err)
+	}
+	if !slices.Equal(ids(got), []int{medium}) {
+		t.Errorf("FilterTasks(Priority: PriorityMedium) = %v, want [%d]", ids(got), medium)
+	}
+}
@@ -0,0 +1,69 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"testing"
+)
+
+// addProject создаёт проект и возвращает его ID.
+func addProject(t *testing.T, s storage.Interface, name string) int {
+	t.Helper()
+	owner := addUser(t, s, "owner")
+	id, err := s.AddProject(context.Background(), storage.Project{Name: name, OwnerID: owner})
+	if err != nil {
+		t.Fatalf("AddProject() error = %v", err)
+	}
+	return id
+}
+
+func testProjects(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	id := addProject(t, s, "project")
+
+	p, err := s.ProjectByID(ctx, id)
+	if err != nil {
+		t.Fatalf("ProjectByID() error = %v", err)
+	}
+	p.Description = "description"
+	err = s.UpdateProject(ctx, *p)
+	if err != nil {
+		t.Fatalf("UpdateProject() error = %v", err)
+	}
+	projects, err := s.Projects(ctx)
+	if err != nil {
+		t.Fatalf("Projects() error = %v", err)
+	}
+	if len(projects) != 1 || projects[0] != *p {
+		t.Errorf("Projects() = %+v, want [%+v]", projects, *p)
+	}
+
+	inProject := addTask(t, s, storage.Task{Title: "in project", ProjectID: &id})
+	addTask(t, s, storage.Task{Title: "outside"})
+	tasks, err := s.TasksByProject(ctx, id)
+	if err != nil {
+		t.Fatalf("TasksByProject() error = %v", err)
+	}
+	if !slices.Equal(ids(tasks), []int{inProject}) {
+		t.Errorf("TasksByProject() = %v, want [%d]", ids(tasks), inProject)
+	}
+
+	// проект с задачами удаляется только с cascadeDelete
+	err = s.DeleteProject(ctx, id, false)
+	if !errors.Is(err, storage.ErrConflict) {
+		t.Errorf("DeleteProject() with tasks error = %v, want ErrConflict", err)
+	}
+	err = s.DeleteProject(ctx, id, true)
+	if err != nil {
+		t.Fatalf("DeleteProject() with cascade error = %v", err)
+	}
+	_, err = s.ProjectByID(ctx, id)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("ProjectByID() after DeleteProject error = %v, want ErrNotFound", err)
+	}
+	if task := taskByID(t, s, inProject); task.ProjectID != nil {
+		t.Errorf("task ProjectID after DeleteProject = %v, want nil", *task.ProjectID)
+	}
+}
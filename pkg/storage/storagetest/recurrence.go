@@ -0,0 +1,111 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"testing"
+)
+
+// recurrenceRule возвращает правило повторения задачи.
+func recurrenceRule(t *testing.T, s storage.Interface, taskID int) storage.RecurrenceRule {
+	t.Helper()
+	r, err := s.RecurrenceRuleByTask(context.Background(), taskID)
+	if err != nil {
+		t.Fatalf("RecurrenceRuleByTask(%d) error = %v", taskID, err)
+	}
+	return *r
+}
+
+func testRecurrence(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	const day = 24 * 60 * 60
+	weekly := addTask(t, s, storage.Task{Title: "weekly report", Content: "send it", Priority: storage.PriorityHigh})
+	daily := addTask(t, s, storage.Task{Title: "daily standup"})
+
+	err := s.AddRecurrenceRule(ctx, storage.RecurrenceRule{TaskID: weekly, IntervalDays: 7, NextDue: 1000})
+	if err != nil {
+		t.Fatalf("AddRecurrenceRule() error = %v", err)
+	}
+	err = s.AddRecurrenceRule(ctx, storage.RecurrenceRule{TaskID: daily, IntervalDays: 1, NextDue: 500})
+	if err != nil {
+		t.Fatalf("AddRecurrenceRule() error = %v", err)
+	}
+	err = s.AddRecurrenceRule(ctx, storage.RecurrenceRule{TaskID: weekly, IntervalDays: 1})
+	if !errors.Is(err, storage.ErrConflict) {
+		t.Errorf("second AddRecurrenceRule() error = %v, want ErrConflict", err)
+	}
+	for _, days := range []int{0, -1} {
+		err = s.AddRecurrenceRule(ctx, storage.RecurrenceRule{TaskID: daily, IntervalDays: days})
+		if !errors.Is(err, storage.ErrInvalidArgument) {
+			t.Errorf("AddRecurrenceRule() with %d days error = %v, want ErrInvalidArgument", days, err)
+		}
+	}
+
+	tests := []struct {
+		now  int64
+		want []int
+	}{
+		{499, nil},
+		{500, []int{daily}},
+		{1000, []int{daily, weekly}},
+	}
+	for _, tt := range tests {
+		rules, err := s.DueRecurrences(ctx, tt.now)
+		if err != nil {
+			t.Fatalf("DueRecurrences(%d) error = %v", tt.now, err)
+		}
+		var got []int
+		for _, r := range rules {
+			got = append(got, r.TaskID)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("DueRecurrences(%d) = %v, want %v", tt.now, got, tt.want)
+		}
+	}
+
+	rule := recurrenceRule(t, s, weekly)
+	id, err := s.SpawnRecurringTask(ctx, rule)
+	if err != nil {
+		t.Fatalf("SpawnRecurringTask() error = %v", err)
+	}
+	spawned := taskByID(t, s, id)
+	if id == weekly || spawned.Title != "weekly report" || spawned.Content != "send it" ||
+		spawned.Priority != storage.PriorityHigh || spawned.DueAt == nil || *spawned.DueAt != 1000 {
+		t.Errorf("spawned task = %+v, want copy of %d due at 1000", spawned, weekly)
+	}
+	next := recurrenceRule(t, s, weekly)
+	if next.NextDue != 1000+7*day || next.LastCreated == 0 {
+		t.Errorf("rule after SpawnRecurringTask = %+v, want NextDue %d", next, 1000+7*day)
+	}
+
+	// повторное создание по устаревшему правилу отклоняется
+	_, err = s.SpawnRecurringTask(ctx, rule)
+	if !errors.Is(err, storage.ErrConflict) {
+		t.Errorf("SpawnRecurringTask() with stale rule error = %v, want ErrConflict", err)
+	}
+
+	next.IntervalDays = 14
+	err = s.UpdateRecurrenceRule(ctx, next)
+	if err != nil {
+		t.Fatalf("UpdateRecurrenceRule() error = %v", err)
+	}
+	if got := recurrenceRule(t, s, weekly); got != next {
+		t.Errorf("RecurrenceRuleByTask() = %+v, want %+v", got, next)
+	}
+	next.IntervalDays = 0
+	err = s.UpdateRecurrenceRule(ctx, next)
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("UpdateRecurrenceRule() with zero interval error = %v, want ErrInvalidArgument", err)
+	}
+
+	err = s.DeleteRecurrenceRule(ctx, weekly)
+	if err != nil {
+		t.Fatalf("DeleteRecurrenceRule() error = %v", err)
+	}
+	_, err = s.RecurrenceRuleByTask(ctx, weekly)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("RecurrenceRuleByTask() after delete error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,103 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"testing"
+)
+
+func testSearchTasks(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	login := addTask(t, s, storage.Task{Title: "Fix login", Content: "users cannot sign in"})
+	billing := addTask(t, s, storage.Task{Title: "Refactor billing", Content: "split invoice generation"})
+	page := addTask(t, s, storage.Task{Title: "Design login page", Content: "new layout"})
+	deleted := addTask(t, s, storage.Task{Title: "Old login form", Content: "remove"})
+	addTask(t, s, storage.Task{Title: "Unrelated", Content: "nothing to see"})
+	if err := s.DeleteTask(ctx, deleted); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"login", []int{login, page}},
+		{"invoice", []int{billing}},
+		{"login page", []int{page}},
+		{"kubernetes", nil},
+	}
+	for _, tt := range tests {
+		got, err := s.SearchTasks(ctx, tt.query, 10)
+		if err != nil {
+			t.Fatalf("SearchTasks(%q) error = %v", tt.query, err)
+		}
+		// порядок результатов зависит от ранжирования
+		gotIDs := ids(got)
+		slices.Sort(gotIDs)
+		if !slices.Equal(gotIDs, tt.want) {
+			t.Errorf("SearchTasks(%q) = %v, want %v", tt.query, gotIDs, tt.want)
+		}
+	}
+
+	got, err := s.SearchTasks(ctx, "login", 1)
+	if err != nil {
+		t.Fatalf("SearchTasks() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("SearchTasks() with limit 1 = %v, want 1 task", ids(got))
+	}
+
+	for _, query := range []string{"", "   "} {
+		_, err = s.SearchTasks(ctx, query, 10)
+		if !errors.Is(err, storage.ErrInvalidArgument) {
+			t.Errorf("SearchTasks(%q) error = %v, want ErrInvalidArgument", query, err)
+		}
+	}
+	_, err = s.SearchTasks(ctx, "login", 0)
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("SearchTasks() with zero limit error = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func testSimilarTasks(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	exact := addTask(t, s, storage.Task{Title: "Release notes"})
+	typo := addTask(t, s, storage.Task{Title: "Release note"})
+	addTask(t, s, storage.Task{Title: "Fix login"})
+	deleted := addTask(t, s, storage.Task{Title: "Release notes"})
+	if err := s.DeleteTask(ctx, deleted); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+
+	// заголовок, отличающийся на один символ, проходит порог 0.5;
+	// более похожие задачи идут первыми
+	got, err := s.SimilarTasks(ctx, "Release notes", 0.5)
+	if err != nil {
+		t.Fatalf("SimilarTasks() error = %v", err)
+	}
+	if want := []int{exact, typo}; !slices.Equal(ids(got), want) {
+		t.Errorf("SimilarTasks() = %v, want %v", ids(got), want)
+	}
+
+	got, err = s.SimilarTasks(ctx, "Release notes", 0.99)
+	if err != nil {
+		t.Fatalf("SimilarTasks() error = %v", err)
+	}
+	if want := []int{exact}; !slices.Equal(ids(got), want) {
+		t.Errorf("SimilarTasks(0.99) = %v, want %v", ids(got), want)
+	}
+
+	// не больше 10 задач
+	for range 12 {
+		addTask(t, s, storage.Task{Title: "Release notes"})
+	}
+	got, err = s.SimilarTasks(ctx, "Release notes", 0.5)
+	if err != nil {
+		t.Fatalf("SimilarTasks() error = %v", err)
+	}
+	if len(got) != 10 {
+		t.Errorf("SimilarTasks() returned %d tasks, want 10", len(got))
+	}
+}
@@ -0,0 +1,178 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"testing"
+)
+
+// addSprint создаёт спринт и возвращает его ID.
+func addSprint(t *testing.T, s storage.Interface, sp storage.Sprint) int {
+	t.Helper()
+	id, err := s.AddSprint(context.Background(), sp)
+	if err != nil {
+		t.Fatalf("AddSprint() error = %v", err)
+	}
+	return id
+}
+
+// assignToSprint включает задачи в спринт.
+func assignToSprint(t *testing.T, s storage.Interface, sprintID int, taskIDs ...int) {
+	t.Helper()
+	for _, id := range taskIDs {
+		err := s.AssignTaskToSprint(context.Background(), id, sprintID)
+		if err != nil {
+			t.Fatalf("AssignTaskToSprint(%d, %d) error = %v", id, sprintID, err)
+		}
+	}
+}
+
+// sprintTasks возвращает ID задач спринта.
+func sprintTasks(t *testing.T, s storage.Interface, sprintID int) []int {
+	t.Helper()
+	tasks, err := s.TasksBySprint(context.Background(), sprintID)
+	if err != nil {
+		t.Fatalf("TasksBySprint(%d) error = %v", sprintID, err)
+	}
+	return ids(tasks)
+}
+
+func testSprints(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	project := addProject(t, s, "project")
+	first := addSprint(t, s, storage.Sprint{ProjectID: project, Name: "sprint 1", StartAt: 100, EndAt: 200})
+	second := addSprint(t, s, storage.Sprint{ProjectID: project, Name: "sprint 2", StartAt: 200, EndAt: 300})
+
+	_, err := s.AddSprint(ctx, storage.Sprint{ProjectID: project, Name: "backwards", StartAt: 300, EndAt: 200})
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("AddSprint() ending before start error = %v, want ErrInvalidArgument", err)
+	}
+
+	sp, err := s.SprintByID(ctx, first)
+	if err != nil {
+		t.Fatalf("SprintByID() error = %v", err)
+	}
+	want := storage.Sprint{ID: first, ProjectID: project, Name: "sprint 1", StartAt: 100, EndAt: 200}
+	if *sp != want {
+		t.Errorf("SprintByID() = %+v, want %+v", *sp, want)
+	}
+
+	want.Name, want.EndAt = "sprint one", 150
+	err = s.UpdateSprint(ctx, want)
+	if err != nil {
+		t.Fatalf("UpdateSprint() error = %v", err)
+	}
+	sprints, err := s.Sprints(ctx)
+	if err != nil {
+		t.Fatalf("Sprints() error = %v", err)
+	}
+	if len(sprints) != 2 || sprints[0] != want || sprints[1].ID != second {
+		t.Errorf("Sprints() = %+v, want updated %d and %d", sprints, first, second)
+	}
+
+	// задача может входить в несколько спринтов
+	shared := addTask(t, s, storage.Task{Title: "shared"})
+	own := addTask(t, s, storage.Task{Title: "own"})
+	assignToSprint(t, s, first, shared, own, shared)
+	assignToSprint(t, s, second, shared)
+	if got := sprintTasks(t, s, first); !slices.Equal(got, []int{shared, own}) {
+		t.Errorf("TasksBySprint(first) = %v, want [%d %d]", got, shared, own)
+	}
+	if got := sprintTasks(t, s, second); !slices.Equal(got, []int{shared}) {
+		t.Errorf("TasksBySprint(second) = %v, want [%d]", got, shared)
+	}
+
+	err = s.RemoveTaskFromSprint(ctx, shared, first)
+	if err != nil {
+		t.Fatalf("RemoveTaskFromSprint() error = %v", err)
+	}
+	if got := sprintTasks(t, s, first); !slices.Equal(got, []int{own}) {
+		t.Errorf("TasksBySprint(first) after removal = %v, want [%d]", got, own)
+	}
+	if got := sprintTasks(t, s, second); !slices.Equal(got, []int{shared}) {
+		t.Errorf("TasksBySprint(second) after removal from first = %v, want [%d]", got, shared)
+	}
+
+	err = s.DeleteSprint(ctx, second)
+	if err != nil {
+		t.Fatalf("DeleteSprint() error = %v", err)
+	}
+	_, err = s.SprintByID(ctx, second)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("SprintByID() of deleted sprint error = %v, want ErrNotFound", err)
+	}
+	// задачи удалённого спринта остаются
+	taskByID(t, s, shared)
+}
+
+func testBurndown(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	project := addProject(t, s, "project")
+	sprint := addSprint(t, s, storage.Sprint{ProjectID: project, Name: "sprint", StartAt: 1000, EndAt: 2000})
+
+	// 10 задач спринта открыты до его начала; первые 8 закрываются
+	// через каждые 100 секунд, последние 2 остаются открытыми
+	opened := int64(900)
+	for i := 0; i < 10; i++ {
+		id := addTask(t, s, storage.Task{Title: "task"})
+		patch := storage.TaskPatch{Opened: &opened}
+		if i < 8 {
+			closed := int64(1100 + 100*i)
+			patch.Closed = &closed
+		}
+		patchTask(t, s, id, patch)
+		assignToSprint(t, s, sprint, id)
+	}
+	// задача вне спринта не учитывается
+	other := addTask(t, s, storage.Task{Title: "other"})
+	closed := int64(1500)
+	patchTask(t, s, other, storage.TaskPatch{Opened: &opened, Closed: &closed})
+
+	velocity, err := s.SprintVelocity(ctx, sprint)
+	if err != nil {
+		t.Fatalf("SprintVelocity() error = %v", err)
+	}
+	if velocity != 8 {
+		t.Errorf("SprintVelocity() = %d, want 8", velocity)
+	}
+
+	points, err := s.BurndownData(ctx, sprint, 10)
+	if err != nil {
+		t.Fatalf("BurndownData() error = %v", err)
+	}
+	want := []int{9, 8, 7, 6, 5, 4, 3, 2, 2, 2}
+	if len(points) != len(want) {
+		t.Fatalf("BurndownData() = %+v, want %d points", points, len(want))
+	}
+	for i, p := range points {
+		at := int64(1100 + 100*i)
+		if p.At != at || p.Remaining != want[i] {
+			t.Errorf("BurndownData()[%d] = %+v, want {At:%d Remaining:%d}", i, p, at, want[i])
+		}
+	}
+
+	// задача, открытая во время спринта, учитывается с момента открытия
+	late := addTask(t, s, storage.Task{Title: "late"})
+	lateOpened := int64(1550)
+	patchTask(t, s, late, storage.TaskPatch{Opened: &lateOpened})
+	assignToSprint(t, s, sprint, late)
+	points, err = s.BurndownData(ctx, sprint, 2)
+	if err != nil {
+		t.Fatalf("BurndownData() error = %v", err)
+	}
+	wantPoints := []storage.BurndownPoint{{At: 1500, Remaining: 5}, {At: 2000, Remaining: 3}}
+	if !slices.Equal(points, wantPoints) {
+		t.Errorf("BurndownData() with 2 buckets = %+v, want %+v", points, wantPoints)
+	}
+
+	_, err = s.BurndownData(ctx, sprint, 0)
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("BurndownData() with zero buckets error = %v, want ErrInvalidArgument", err)
+	}
+	_, err = s.BurndownData(ctx, 1000, 10)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("BurndownData() of missing sprint error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,189 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"testing"
+	"time"
+)
+
+func testUserWorkloads(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	alice := addUser(t, s, "alice")
+	bob := addUser(t, s, "bob")
+	idle := addUser(t, s, "idle")
+
+	closed := int64(100)
+	assignments := []struct {
+		user   int
+		closed bool
+	}{
+		{alice, false},
+		{alice, false},
+		{alice, true},
+		{bob, true},
+	}
+	for _, a := range assignments {
+		id := addTask(t, s, storage.Task{Title: "task"})
+		patch := storage.TaskPatch{AssignedID: &a.user}
+		if a.closed {
+			patch.Closed = &closed
+		}
+		patchTask(t, s, id, patch)
+	}
+	// удалённая задача не учитывается
+	deleted := addTask(t, s, storage.Task{Title: "deleted"})
+	patchTask(t, s, deleted, storage.TaskPatch{AssignedID: &alice})
+	if err := s.DeleteTask(ctx, deleted); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+
+	want := map[int][2]int{
+		alice: {2, 3},
+		bob:   {0, 1},
+		idle:  {0, 0},
+	}
+	workloads, err := s.UserWorkloads(ctx)
+	if err != nil {
+		t.Fatalf("UserWorkloads() error = %v", err)
+	}
+	found := 0
+	for i, w := range workloads {
+		if i > 0 && w.ID <= workloads[i-1].ID {
+			t.Errorf("UserWorkloads() is not ordered by ID: %+v", workloads)
+		}
+		counts, ok := want[w.ID]
+		if !ok {
+			continue
+		}
+		found++
+		if got := [2]int{w.OpenTaskCount, w.TotalTaskCount}; got != counts {
+			t.Errorf("UserWorkloads() for %s = %v, want %v", w.Name, got, counts)
+		}
+	}
+	if found != len(want) {
+		t.Errorf("UserWorkloads() = %+v, want users %d, %d and %d", workloads, alice, bob, idle)
+	}
+
+	w, err := s.UserWorkload(ctx, alice)
+	if err != nil {
+		t.Fatalf("UserWorkload() error = %v", err)
+	}
+	if w.ID != alice || w.Name != "alice" || w.OpenTaskCount != 2 || w.TotalTaskCount != 3 {
+		t.Errorf("UserWorkload() = %+v, want alice with 2 of 3 open", w)
+	}
+	_, err = s.UserWorkload(ctx, 1000)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("UserWorkload() of missing user error = %v, want ErrNotFound", err)
+	}
+}
+
+func testTaskStats(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	now := time.Now().Unix()
+	past, future := now-1000, now+1000
+
+	fixture := []struct {
+		opened int64
+		closed int64
+		due    *int64
+	}{
+		{now - 100, 0, nil},       // открыта
+		{now - 300, 0, &past},     // открыта, просрочена
+		{now - 500, 0, &future},   // открыта, срок не наступил
+		{now - 900, now, &past},   // закрыта после срока
+		{now - 900, now - 1, nil}, // закрыта
+	}
+	for _, f := range fixture {
+		id := addTask(t, s, storage.Task{Title: "task", DueAt: f.due})
+		opened, closed := f.opened, f.closed
+		patchTask(t, s, id, storage.TaskPatch{Opened: &opened, Closed: &closed})
+	}
+	deleted := addTask(t, s, storage.Task{Title: "deleted", DueAt: &past})
+	if err := s.DeleteTask(ctx, deleted); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+
+	st, err := s.TaskStats(ctx)
+	if err != nil {
+		t.Fatalf("TaskStats() error = %v", err)
+	}
+	if st.Total != 5 || st.Open != 3 || st.Closed != 2 || st.Overdue != 1 {
+		t.Errorf("TaskStats() = %+v, want Total 5, Open 3, Closed 2, Overdue 1", st)
+	}
+	// средний возраст открытых задач (100+300+500)/3 = 300 секунд
+	// плюс время, прошедшее с начала теста
+	if st.AvgAgeSeconds < 300 || st.AvgAgeSeconds > 310 {
+		t.Errorf("TaskStats().AvgAgeSeconds = %v, want about 300", st.AvgAgeSeconds)
+	}
+}
+
+// В пустом хранилище статистика нулевая.
+func testTaskStatsEmpty(t *testing.T, s storage.Interface) {
+	st, err := s.TaskStats(context.Background())
+	if err != nil {
+		t.Fatalf("TaskStats() error = %v", err)
+	}
+	if *st != (storage.TaskStats{}) {
+		t.Errorf("TaskStats() = %+v, want zero", st)
+	}
+}
+
+func testLabelStats(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	unused := addLabel(t, s, "unused")
+	bug := addLabel(t, s, "bug")
+	feature := addLabel(t, s, "feature")
+
+	usage := map[int]int{bug: 3, feature: 2}
+	for label, n := range usage {
+		for i := 0; i < n; i++ {
+			id := addTask(t, s, storage.Task{Title: "task"})
+			if err := s.AssignLabel(ctx, id, label); err != nil {
+				t.Fatalf("AssignLabel() error = %v", err)
+			}
+		}
+	}
+	// метка удалённой задачи не учитывается
+	deleted := addTask(t, s, storage.Task{Title: "deleted"})
+	if err := s.AssignLabel(ctx, deleted, unused); err != nil {
+		t.Fatalf("AssignLabel() error = %v", err)
+	}
+	if err := s.DeleteTask(ctx, deleted); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+
+	want := []storage.LabelStat{
+		{Label: storage.Label{ID: bug, Name: "bug"}, TaskCount: 3},
+		{Label: storage.Label{ID: feature, Name: "feature"}, TaskCount: 2},
+		{Label: storage.Label{ID: unused, Name: "unused"}, TaskCount: 0},
+	}
+	stats, err := s.LabelStats(ctx)
+	if err != nil {
+		t.Fatalf("LabelStats() error = %v", err)
+	}
+	if !slices.Equal(stats, want) {
+		t.Errorf("LabelStats() = %+v, want %+v", stats, want)
+	}
+
+	top, err := s.TopLabels(ctx, 2)
+	if err != nil {
+		t.Fatalf("TopLabels() error = %v", err)
+	}
+	if !slices.Equal(top, want[:2]) {
+		t.Errorf("TopLabels(2) = %+v, want %+v", top, want[:2])
+	}
+	top, err = s.TopLabels(ctx, 10)
+	if err != nil {
+		t.Fatalf("TopLabels() error = %v", err)
+	}
+	if len(top) != 3 {
+		t.Errorf("TopLabels(10) = %+v, want all 3 labels", top)
+	}
+	_, err = s.TopLabels(ctx, 0)
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("TopLabels(0) error = %v, want ErrInvalidArgument", err)
+	}
+}
@@ -0,0 +1,40 @@
+package storagetest
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"testing"
+)
+
+func testTaskStatus(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	open := addTask(t, s, storage.Task{Title: "open"})
+	done := addTask(t, s, storage.Task{Title: "done"})
+
+	if task := taskByID(t, s, open); task.Status != storage.StatusOpen {
+		t.Errorf("new task status = %v, want StatusOpen", task.Status)
+	}
+	err := s.UpdateTaskStatus(ctx, done, storage.StatusDone)
+	if err != nil {
+		t.Fatalf("UpdateTaskStatus() error = %v", err)
+	}
+
+	tests := []struct {
+		status storage.Status
+		want   []int
+	}{
+		{storage.StatusOpen, []int{open}},
+		{storage.StatusDone, []int{done}},
+		{storage.StatusCancelled, nil},
+	}
+	for _, tt := range tests {
+		got, err := s.TasksByStatus(ctx, tt.status)
+		if err != nil {
+			t.Fatalf("TasksByStatus(%v) error = %v", tt.status, err)
+		}
+		if !slices.Equal(ids(got), tt.want) {
+			t.Errorf("TasksByStatus(%v) = %v, want %v", tt.status, ids(got), tt.want)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+// Пакет storagetest содержит общие тесты реализаций storage.Interface.
+// Реализация запускает их из своих тестов с помощью Run, передавая
+// конструктор пустого хранилища, поэтому все реализации проверяются
+// одними и теми же тестами.
+package storagetest
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"testing"
+)
+
+// Factory возвращает пустое хранилище для одного теста.
+// Освобождение ресурсов хранилища регистрируется через t.Cleanup.
+type Factory func(t *testing.T) storage.Interface
+
+// tests - тесты, которые запускает Run.
+var tests = []struct {
+	name string
+	fn   func(t *testing.T, s storage.Interface)
+}{
+	{"Tasks", testTasks},
+	{"TasksList", testTasksList},
+	{"TasksSort", testTasksSort},
+	{"TasksByRank", testTasksByRank},
+	{"TasksAfter", testTasksAfter},
+	{"TasksIter", testTasksIter},
+	{"TaskCount", testTaskCount},
+	{"TasksByAssignee", testTasksByAssignee},
+	{"TasksUnassigned", testTasksUnassigned},
+	{"Users", testUsers},
+	{"TasksWithUsers", testTasksWithUsers},
+	{"Roles", testRoles},
+	{"Labels", testLabels},
+	{"AssignLabel", testAssignLabel},
+	{"LabelConflict", testLabelConflict},
+	{"AddTaskWithLabels", testAddTaskWithLabels},
+	{"TasksWithLabels", testTasksWithLabels},
+	{"TasksByLabels", testTasksByLabels},
+	{"Tags", testTags},
+	{"FilterTasks", testFilterTasks},
+	{"TaskStatus", testTaskStatus},
+	{"TaskPriority", testTaskPriority},
+	{"TasksOverdue", testTasksOverdue},
+	{"StaleTasks", testStaleTasks},
+	{"TasksBetween", testTasksBetween},
+	{"TasksClosedInRange", testTasksClosedInRange},
+	{"Comments", testComments},
+	{"TimeEntries", testTimeEntries},
+	{"Checklist", testChecklist},
+	{"Projects", testProjects},
+	{"Sprints", testSprints},
+	{"Burndown", testBurndown},
+	{"UserWorkloads", testUserWorkloads},
+	{"TaskStats", testTaskStats},
+	{"TaskStatsEmpty", testTaskStatsEmpty},
+	{"LabelStats", testLabelStats},
+	{"ActivityFeed", testActivityFeed},
+	{"NotificationPreferences", testNotificationPreferences},
+	{"UsersSubscribedToTask", testUsersSubscribedToTask},
+	{"Watchers", testWatchers},
+	{"APIKeys", testAPIKeys},
+	{"Webhooks", testWebhooks},
+	{"ExportUserData", testExportUserData},
+	{"EraseUser", testEraseUser},
+	{"IdempotencyKey", testIdempotencyKey},
+	{"IdempotencyKeyConcurrent", testIdempotencyKeyConcurrent},
+	{"Events", testEvents},
+	{"EventsConcurrent", testEventsConcurrent},
+	{"Outbox", testOutbox},
+	{"Dependencies", testDependencies},
+	{"TaskLinks", testTaskLinks},
+	{"SoftDelete", testSoftDelete},
+	{"TaskNotFound", testTaskNotFound},
+	{"DeleteTasks", testDeleteTasks},
+	{"BulkUpdates", testBulkUpdates},
+	{"AddTasksBatch", testAddTasksBatch},
+	{"UpsertTask", testUpsertTask},
+	{"ImportTasks", testImportTasks},
+	{"PartialUpdateTask", testPartialUpdateTask},
+	{"VersionConflict", testVersionConflict},
+	{"TaskLocks", testTaskLocks},
+	{"Transactions", testTransactions},
+	{"Savepoints", testSavepoints},
+	{"AddTasksWithPartialRetry", testAddTasksWithPartialRetry},
+	{"AuditLog", testAuditLog},
+	{"SearchTasks", testSearchTasks},
+	{"SimilarTasks", testSimilarTasks},
+	{"Metadata", testMetadata},
+	{"TaskByExternalID", testTaskByExternalID},
+	{"AssignmentHistory", testAssignmentHistory},
+	{"Recurrence", testRecurrence},
+	{"Archive", testArchive},
+}
+
+// Run запускает все тесты пакета, каждый на новом хранилище.
+func Run(t *testing.T, newStorage Factory) {
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newStorage(t))
+		})
+	}
+}
+
+// addTask создаёт задачу и возвращает её ID.
+func addTask(t *testing.T, s storage.Interface, task storage.Task) int {
+	t.Helper()
+	id, err := s.AddTask(context.Background(), task)
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	return id
+}
+
+// taskByID возвращает задачу по ID.
+func taskByID(t *testing.T, s storage.Interface, id int) storage.Task {
+	t.Helper()
+	task, err := s.TaskById(context.Background(), id)
+	if err != nil {
+		t.Fatalf("TaskById(%d) error = %v", id, err)
+	}
+	return *task
+}
+
+// patchTask изменяет поля задачи, заданные в patch.
+func patchTask(t *testing.T, s storage.Interface, id int, patch storage.TaskPatch) {
+	t.Helper()
+	err := s.PartialUpdateTask(context.Background(), id, patch)
+	if err != nil {
+		t.Fatalf("PartialUpdateTask(%d) error = %v", id, err)
+	}
+}
+
+// addUser создаёт пользователя и возвращает его ID.
+func addUser(t *testing.T, s storage.Interface, name string) int {
+	t.Helper()
+	id, err := s.AddUser(context.Background(), storage.User{Name: name})
+	if err != nil {
+		t.Fatalf("AddUser() error = %v", err)
+	}
+	return id
+}
+
+// ids возвращает ID задач в порядке следования.
+func ids(tasks []storage.Task) []int {
+	var ids []int
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+	return ids
+}
+
+// userIDs возвращает ID пользователей в порядке следования.
+func userIDs(users []storage.User) []int {
+	var ids []int
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	return ids
+}
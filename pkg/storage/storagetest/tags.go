@@ -0,0 +1,76 @@
+package storagetest
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"testing"
+)
+
+func testTags(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	tags := []string{"backend", "urgent", "with space", "юникод"}
+	tagged := addTask(t, s, storage.Task{Title: "tagged", Tags: tags})
+	urgent := addTask(t, s, storage.Task{Title: "urgent", Tags: []string{"urgent"}})
+	plain := addTask(t, s, storage.Task{Title: "plain"})
+	deleted := addTask(t, s, storage.Task{Title: "deleted", Tags: []string{"deleted"}})
+	if err := s.DeleteTask(ctx, deleted); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+
+	// массив сохраняется без изменений и в том же порядке
+	if got := taskByID(t, s, tagged).Tags; !slices.Equal(got, tags) {
+		t.Errorf("TaskById() Tags = %q, want %q", got, tags)
+	}
+	if got := taskByID(t, s, plain).Tags; len(got) != 0 {
+		t.Errorf("TaskById() Tags = %q, want none", got)
+	}
+	// изменение исходного слайса не влияет на сохранённую задачу
+	tags[0] = "changed"
+	if got := taskByID(t, s, tagged).Tags; got[0] != "backend" {
+		t.Errorf("TaskById() Tags = %q after changing the argument", got)
+	}
+
+	got, err := s.TasksByTag(ctx, "urgent")
+	if err != nil {
+		t.Fatalf("TasksByTag() error = %v", err)
+	}
+	if want := []int{tagged, urgent}; !slices.Equal(ids(got), want) {
+		t.Errorf("TasksByTag(urgent) = %v, want %v", ids(got), want)
+	}
+	for _, tag := range []string{"urg", "deleted", ""} {
+		got, err = s.TasksByTag(ctx, tag)
+		if err != nil {
+			t.Fatalf("TasksByTag() error = %v", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("TasksByTag(%q) = %v, want none", tag, ids(got))
+		}
+	}
+
+	all, err := s.AllTags(ctx)
+	if err != nil {
+		t.Fatalf("AllTags() error = %v", err)
+	}
+	if want := []string{"backend", "urgent", "with space", "юникод"}; !slices.Equal(all, want) {
+		t.Errorf("AllTags() = %q, want %q", all, want)
+	}
+
+	// UpdateTask заменяет теги целиком
+	task := taskByID(t, s, tagged)
+	task.Tags = []string{"frontend"}
+	err = s.UpdateTask(ctx, task)
+	if err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if got := taskByID(t, s, tagged).Tags; !slices.Equal(got, task.Tags) {
+		t.Errorf("TaskById() Tags after update = %q, want %q", got, task.Tags)
+	}
+	got, err = s.TasksByTag(ctx, "urgent")
+	if err != nil {
+		t.Fatalf("TasksByTag() error = %v", err)
+	}
+	if want := []int{urgent}; !slices.Equal(ids(got), want) {
+		t.Errorf("TasksByTag(urgent) after update = %v, want %v", ids(got), want)
+	}
+}
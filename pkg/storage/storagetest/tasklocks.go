@@ -0,0 +1,71 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"sync"
+	"testing"
+)
+
+func testTaskLocks(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	id := addTask(t, s, storage.Task{Title: "task"})
+
+	// из двух одновременных блокировок успешна только одна
+	errs := make([]error, 2)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = s.LockTask(ctx, id)
+		}()
+	}
+	wg.Wait()
+	locked := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			locked++
+		case !errors.Is(err, storage.ErrLocked):
+			t.Errorf("LockTask() error = %v, want nil or ErrLocked", err)
+		}
+	}
+	if locked != 1 {
+		t.Fatalf("LockTask() succeeded %d times, want 1 (errors %v)", locked, errs)
+	}
+
+	// блокировка одной задачи не мешает блокировать другую
+	other := addTask(t, s, storage.Task{Title: "other"})
+	if err := s.LockTask(ctx, other); err != nil {
+		t.Errorf("LockTask(other) error = %v", err)
+	}
+	if err := s.UnlockTask(ctx, other); err != nil {
+		t.Errorf("UnlockTask(other) error = %v", err)
+	}
+
+	err := s.UnlockTask(ctx, id)
+	if err != nil {
+		t.Fatalf("UnlockTask() error = %v", err)
+	}
+	err = s.UnlockTask(ctx, id)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("UnlockTask() twice error = %v, want ErrNotFound", err)
+	}
+
+	// после снятия задачу можно заблокировать снова
+	err = s.LockTask(ctx, id)
+	if err != nil {
+		t.Fatalf("LockTask() after unlock error = %v", err)
+	}
+	err = s.UnlockTask(ctx, id)
+	if err != nil {
+		t.Fatalf("UnlockTask() error = %v", err)
+	}
+
+	err = s.LockTask(ctx, 1_000_000)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("LockTask(missing) error = %v, want ErrNotFound", err)
+	}
+}
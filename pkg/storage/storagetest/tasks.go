@@ -0,0 +1,775 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"testing"
+	"time"
+)
+
+func testTasks(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	author := addUser(t, s, "author")
+
+	id := addTask(t, s, storage.Task{Title: "title", Content: "content"})
+	got := taskByID(t, s, id)
+	if got.ID != id || got.Title != "title" || got.Content != "content" {
+		t.Fatalf("TaskById() = %+v, want task %d with title and content", got, id)
+	}
+
+	got.Title = "new title"
+	got.AuthorID = author
+	err := s.UpdateTask(ctx, got)
+	if err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	got = taskByID(t, s, id)
+	if got.Title != "new title" || got.AuthorID != author {
+		t.Errorf("after UpdateTask got %+v", got)
+	}
+
+	byAuthor, err := s.TasksByAuthor(ctx, author)
+	if err != nil {
+		t.Fatalf("TasksByAuthor() error = %v", err)
+	}
+	if !slices.Equal(ids(byAuthor), []int{id}) {
+		t.Errorf("TasksByAuthor() = %v, want [%d]", ids(byAuthor), id)
+	}
+
+	other := addTask(t, s, storage.Task{Title: "other"})
+	tasks, err := s.Tasks(ctx)
+	if err != nil {
+		t.Fatalf("Tasks() error = %v", err)
+	}
+	if !slices.Equal(ids(tasks), []int{id, other}) {
+		t.Errorf("Tasks() = %v, want [%d %d]", ids(tasks), id, other)
+	}
+
+	err = s.DeleteTask(ctx, id)
+	if err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	tasks, err = s.Tasks(ctx)
+	if err != nil {
+		t.Fatalf("Tasks() error = %v", err)
+	}
+	if !slices.Equal(ids(tasks), []int{other}) {
+		t.Errorf("Tasks() after DeleteTask = %v, want [%d]", ids(tasks), other)
+	}
+}
+
+func testTasksList(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	var all []int
+	for _, title := range []string{"c", "a", "d", "b", "e"} {
+		all = append(all, addTask(t, s, storage.Task{Title: title}))
+	}
+
+	total, err := s.TotalCount(ctx)
+	if err != nil {
+		t.Fatalf("TotalCount() error = %v", err)
+	}
+	if total != len(all) {
+		t.Errorf("TotalCount() = %d, want %d", total, len(all))
+	}
+
+	tests := []struct {
+		name string
+		opts storage.ListOptions
+		want []int
+	}{
+		{"first page", storage.ListOptions{Limit: 2}, all[:2]},
+		{"second page", storage.ListOptions{Limit: 2, Offset: 2}, all[2:4]},
+		{"last page", storage.ListOptions{Limit: 2, Offset: 4}, all[4:]},
+		{"past the end", storage.ListOptions{Limit: 2, Offset: 5}, nil},
+		{"order by title", storage.ListOptions{Limit: 3, OrderBy: "title"}, []int{all[1], all[3], all[0]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.TasksList(ctx, tt.opts)
+			if err != nil {
+				t.Fatalf("TasksList() error = %v", err)
+			}
+			if !slices.Equal(ids(got), tt.want) {
+				t.Errorf("TasksList() = %v, want %v", ids(got), tt.want)
+			}
+		})
+	}
+
+	for _, opts := range []storage.ListOptions{
+		{Limit: 0},
+		{Limit: 1, Offset: -1},
+		{Limit: 1, OrderBy: "title; DROP TABLE tasks"},
+	} {
+		_, err := s.TasksList(ctx, opts)
+		if err == nil {
+			t.Errorf("TasksList(%+v) error = nil, want error", opts)
+		}
+	}
+}
+
+func testTasksSort(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+
+	// a, b, c, d в порядке создания
+	tasks := []struct {
+		title    string
+		priority storage.Priority
+		opened   int64
+	}{
+		{"banana", storage.PriorityHigh, 300},
+		{"apple", storage.PriorityLow, 100},
+		{"cherry", storage.PriorityHigh, 200},
+		{"date", storage.PriorityMedium, 400},
+	}
+	var id []int
+	for _, task := range tasks {
+		n := addTask(t, s, storage.Task{Title: task.title, Priority: task.priority})
+		opened := task.opened
+		patchTask(t, s, n, storage.TaskPatch{Opened: &opened})
+		id = append(id, n)
+	}
+	a, b, c, d := id[0], id[1], id[2], id[3]
+
+	tests := []struct {
+		sort storage.SortOptions
+		want []int
+	}{
+		{storage.SortOptions{}, []int{a, b, c, d}},
+		{storage.SortOptions{Field: storage.SortByID}, []int{a, b, c, d}},
+		{storage.SortOptions{Field: storage.SortByID, Dir: storage.SortDesc}, []int{d, c, b, a}},
+		{storage.SortOptions{Field: storage.SortByOpened, Dir: storage.SortAsc}, []int{b, c, a, d}},
+		{storage.SortOptions{Field: storage.SortByOpened, Dir: storage.SortDesc}, []int{d, a, c, b}},
+		{storage.SortOptions{Field: storage.SortByTitle}, []int{b, a, c, d}},
+		{storage.SortOptions{Field: storage.SortByTitle, Dir: storage.SortDesc}, []int{d, c, a, b}},
+		// равные значения - по возрастанию ID
+		{storage.SortOptions{Field: storage.SortByPriority}, []int{b, d, a, c}},
+		{storage.SortOptions{Field: storage.SortByPriority, Dir: storage.SortDesc}, []int{a, c, d, b}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %s", tt.sort.Field, tt.sort.Dir), func(t *testing.T) {
+			got, err := s.TasksList(ctx, storage.ListOptions{Limit: 10, Sort: tt.sort})
+			if err != nil {
+				t.Fatalf("TasksList() error = %v", err)
+			}
+			if !slices.Equal(ids(got), tt.want) {
+				t.Errorf("TasksList(%+v) = %v, want %v", tt.sort, ids(got), tt.want)
+			}
+		})
+	}
+
+	// Sort.Field имеет приоритет над OrderBy
+	got, err := s.TasksList(ctx, storage.ListOptions{Limit: 10, OrderBy: "title", Sort: storage.SortOptions{Field: storage.SortByOpened}})
+	if err != nil {
+		t.Fatalf("TasksList() error = %v", err)
+	}
+	if want := []int{b, c, a, d}; !slices.Equal(ids(got), want) {
+		t.Errorf("TasksList() with OrderBy and Sort = %v, want %v", ids(got), want)
+	}
+
+	for _, sort := range []storage.SortOptions{
+		{Field: "content"},
+		{Field: "id; DROP TABLE tasks"},
+		{Field: storage.SortByID, Dir: "up"},
+	} {
+		_, err := s.TasksList(ctx, storage.ListOptions{Limit: 10, Sort: sort})
+		if !errors.Is(err, storage.ErrInvalidArgument) {
+			t.Errorf("TasksList(%+v) error = %v, want ErrInvalidArgument", sort, err)
+		}
+	}
+}
+
+func testTasksByAssignee(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	alice := addUser(t, s, "alice")
+	bob := addUser(t, s, "bob")
+
+	var aliceTasks []int
+	for _, assignee := range []int{alice, bob, alice} {
+		id := addTask(t, s, storage.Task{Title: "task"})
+		task := taskByID(t, s, id)
+		task.AssignedID = assignee
+		err := s.UpdateTask(ctx, task)
+		if err != nil {
+			t.Fatalf("UpdateTask() error = %v", err)
+		}
+		if assignee == alice {
+			aliceTasks = append(aliceTasks, id)
+		}
+	}
+
+	got, err := s.TasksByAssignee(ctx, alice)
+	if err != nil {
+		t.Fatalf("TasksByAssignee() error = %v", err)
+	}
+	if !slices.Equal(ids(got), aliceTasks) {
+		t.Errorf("TasksByAssignee() = %v, want %v", ids(got), aliceTasks)
+	}
+
+	got, err = s.TasksByAssignee(ctx, addUser(t, s, "carol"))
+	if err != nil {
+		t.Fatalf("TasksByAssignee() error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("TasksByAssignee() for user without tasks = %v, want none", ids(got))
+	}
+}
+
+func testTasksUnassigned(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	alice := addUser(t, s, "alice")
+	var tasks []int
+	for range 3 {
+		tasks = append(tasks, addTask(t, s, storage.Task{Title: "task"}))
+	}
+	for _, id := range tasks[:2] {
+		err := s.AssignTask(ctx, id, alice)
+		if err != nil {
+			t.Fatalf("AssignTask() error = %v", err)
+		}
+	}
+	if got := taskByID(t, s, tasks[0]); got.AssignedID != alice {
+		t.Errorf("AssignedID after AssignTask = %d, want %d", got.AssignedID, alice)
+	}
+
+	got, err := s.TasksUnassigned(ctx)
+	if err != nil {
+		t.Fatalf("TasksUnassigned() error = %v", err)
+	}
+	if want := tasks[2:]; !slices.Equal(ids(got), want) {
+		t.Errorf("TasksUnassigned() = %v, want %v", ids(got), want)
+	}
+
+	// снятие исполнителя возвращает задачу в очередь, удалённые задачи не возвращаются
+	err = s.AssignTask(ctx, tasks[0], 0)
+	if err != nil {
+		t.Fatalf("AssignTask() error = %v", err)
+	}
+	err = s.DeleteTask(ctx, tasks[2])
+	if err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	got, err = s.TasksUnassigned(ctx)
+	if err != nil {
+		t.Fatalf("TasksUnassigned() error = %v", err)
+	}
+	if want := tasks[:1]; !slices.Equal(ids(got), want) {
+		t.Errorf("TasksUnassigned() after unassign = %v, want %v", ids(got), want)
+	}
+
+	if err := s.AssignTask(ctx, 1000, alice); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("AssignTask() of missing task error = %v, want ErrNotFound", err)
+	}
+}
+
+func testSoftDelete(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	id := addTask(t, s, storage.Task{Title: "task"})
+
+	err := s.DeleteTask(ctx, id)
+	if err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	_, err = s.TaskById(ctx, id)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("TaskById() of deleted task error = %v, want ErrNotFound", err)
+	}
+	err = s.DeleteTask(ctx, id)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("DeleteTask() of deleted task error = %v, want ErrNotFound", err)
+	}
+
+	all, err := s.TasksIncludingDeleted(ctx)
+	if err != nil {
+		t.Fatalf("TasksIncludingDeleted() error = %v", err)
+	}
+	if len(all) != 1 || all[0].ID != id || !all[0].DeletedAt.Valid {
+		t.Errorf("TasksIncludingDeleted() = %+v, want deleted task %d", all, id)
+	}
+
+	err = s.UndeleteTask(ctx, id)
+	if err != nil {
+		t.Fatalf("UndeleteTask() error = %v", err)
+	}
+	if task := taskByID(t, s, id); task.DeletedAt.Valid {
+		t.Errorf("TaskById() after UndeleteTask = %+v, want DeletedAt unset", task)
+	}
+	err = s.UndeleteTask(ctx, id)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("UndeleteTask() of task that is not deleted error = %v, want ErrNotFound", err)
+	}
+}
+
+// Методы, изменяющие задачу, возвращают ErrNotFound для отсутствующей задачи.
+func testTaskNotFound(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	const missing = 1000
+	title := "title"
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"TaskById", func() error { _, err := s.TaskById(ctx, missing); return err }},
+		{"UpdateTask", func() error { return s.UpdateTask(ctx, storage.Task{ID: missing}) }},
+		{"PartialUpdateTask", func() error {
+			return s.PartialUpdateTask(ctx, missing, storage.TaskPatch{Title: &title})
+		}},
+		{"PartialUpdateTask empty patch", func() error {
+			return s.PartialUpdateTask(ctx, missing, storage.TaskPatch{})
+		}},
+		{"UpdateTaskStatus", func() error { return s.UpdateTaskStatus(ctx, missing, storage.StatusDone) }},
+		{"DeleteTask", func() error { return s.DeleteTask(ctx, missing) }},
+		{"DeleteTasks", func() error { return s.DeleteTasks(ctx, []int{missing}) }},
+		{"UndeleteTask", func() error { return s.UndeleteTask(ctx, missing) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if !errors.Is(err, storage.ErrNotFound) {
+				t.Errorf("%s() error = %v, want ErrNotFound", tt.name, err)
+			}
+		})
+	}
+}
+
+func testTasksAfter(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	var want []int
+	for range 5 {
+		want = append(want, addTask(t, s, storage.Task{Title: "task"}))
+	}
+	err := s.DeleteTask(ctx, want[2])
+	if err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	want = slices.Delete(want, 2, 3)
+
+	// постраничный обход по курсору возвращает все неудалённые задачи
+	var got []int
+	after := 0
+	for {
+		page, err := s.TasksAfter(ctx, after, 2)
+		if err != nil {
+			t.Fatalf("TasksAfter(%d) error = %v", after, err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		if len(page) > 2 {
+			t.Fatalf("TasksAfter(%d) returned %d tasks, want at most 2", after, len(page))
+		}
+		got = append(got, ids(page)...)
+		after = page[len(page)-1].ID
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("TasksAfter() pages = %v, want %v", got, want)
+	}
+}
+
+func testTasksIter(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	var want []int
+	for range 5 {
+		want = append(want, addTask(t, s, storage.Task{Title: "task"}))
+	}
+	err := s.DeleteTask(ctx, want[4])
+	if err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	want = want[:4]
+
+	// обход возвращает те же задачи, что и Tasks
+	var got []int
+	err = s.TasksIter(ctx, func(task storage.Task) error {
+		got = append(got, task.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("TasksIter() error = %v", err)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("TasksIter() = %v, want %v", got, want)
+	}
+
+	// ошибка fn останавливает обход и возвращается без изменений
+	stop := errors.New("stop")
+	calls := 0
+	err = s.TasksIter(ctx, func(task storage.Task) error {
+		calls++
+		if calls == 3 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Errorf("TasksIter() error = %v, want %v", err, stop)
+	}
+	if calls != 3 {
+		t.Errorf("TasksIter() called fn %d times, want 3", calls)
+	}
+}
+
+func testTaskCount(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	alice := addUser(t, s, "alice")
+	bug := addLabel(t, s, "bug")
+	for i := range 3 {
+		id := addTask(t, s, storage.Task{Title: "task"})
+		if i > 0 {
+			patchTask(t, s, id, storage.TaskPatch{AuthorID: &alice})
+			err := s.AssignLabel(ctx, id, bug)
+			if err != nil {
+				t.Fatalf("AssignLabel() error = %v", err)
+			}
+		}
+		if i == 2 {
+			err := s.DeleteTask(ctx, id)
+			if err != nil {
+				t.Fatalf("DeleteTask() error = %v", err)
+			}
+		}
+	}
+
+	tests := []struct {
+		name  string
+		count func() (int, error)
+		want  int
+	}{
+		{"TaskCount", func() (int, error) { return s.TaskCount(ctx) }, 2},
+		{"TaskCountByAuthor", func() (int, error) { return s.TaskCountByAuthor(ctx, alice) }, 1},
+		{"TaskCountByLabel", func() (int, error) { return s.TaskCountByLabel(ctx, bug) }, 1},
+		{"TaskCountByAuthor without tasks", func() (int, error) { return s.TaskCountByAuthor(ctx, alice+1) }, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.count()
+			if err != nil {
+				t.Fatalf("%s() error = %v", tt.name, err)
+			}
+			if got != tt.want {
+				t.Errorf("%s() = %d, want %d", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func testDeleteTasks(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	var all []int
+	for range 4 {
+		all = append(all, addTask(t, s, storage.Task{Title: "task"}))
+	}
+
+	err := s.DeleteTasks(ctx, nil)
+	if err != nil {
+		t.Errorf("DeleteTasks(nil) error = %v", err)
+	}
+	// отсутствующие задачи пропускаются
+	err = s.DeleteTasks(ctx, []int{all[0], all[2], 1000})
+	if err != nil {
+		t.Fatalf("DeleteTasks() error = %v", err)
+	}
+	tasks, err := s.Tasks(ctx)
+	if err != nil {
+		t.Fatalf("Tasks() error = %v", err)
+	}
+	if want := []int{all[1], all[3]}; !slices.Equal(ids(tasks), want) {
+		t.Errorf("Tasks() after DeleteTasks = %v, want %v", ids(tasks), want)
+	}
+
+	err = s.DeleteTasks(ctx, []int{all[0], 1000})
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("DeleteTasks() of deleted and missing tasks error = %v, want ErrNotFound", err)
+	}
+}
+
+func testAddTasksBatch(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	input := []storage.Task{{Title: "first"}, {Title: "second"}, {Title: "third"}}
+
+	for name, add := range map[string]func(context.Context, []storage.Task) ([]int, error){
+		"AddTasks":      s.AddTasks,
+		"AddTasksBatch": s.AddTasksBatch,
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := add(ctx, input)
+			if err != nil {
+				t.Fatalf("%s() error = %v", name, err)
+			}
+			if len(got) != len(input) {
+				t.Fatalf("%s() returned %d IDs, want %d", name, len(got), len(input))
+			}
+			// ID возвращаются в порядке задач во входном слайсе
+			for i, id := range got {
+				if task := taskByID(t, s, id); task.Title != input[i].Title {
+					t.Errorf("task %d title = %q, want %q", id, task.Title, input[i].Title)
+				}
+			}
+		})
+	}
+}
+
+func testUpsertTask(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+
+	created, err := s.UpsertTask(ctx, storage.Task{Title: "created"})
+	if err != nil {
+		t.Fatalf("UpsertTask() without ID error = %v", err)
+	}
+	if task := taskByID(t, s, created); task.Title != "created" || task.Priority != storage.PriorityLow {
+		t.Errorf("TaskById() = %+v, want title created and low priority", task)
+	}
+
+	// задача с явным ID не должна мешать последующему созданию задач
+	const explicit = 100
+	id, err := s.UpsertTask(ctx, storage.Task{ID: explicit, Title: "explicit", Tags: []string{"tag"}})
+	if err != nil {
+		t.Fatalf("UpsertTask() with new ID error = %v", err)
+	}
+	if id != explicit {
+		t.Errorf("UpsertTask() = %d, want %d", id, explicit)
+	}
+	if next := addTask(t, s, storage.Task{Title: "next"}); next <= explicit {
+		t.Errorf("AddTask() after UpsertTask = %d, want ID greater than %d", next, explicit)
+	}
+
+	old := taskByID(t, s, explicit)
+	_, err = s.UpsertTask(ctx, storage.Task{
+		ID:       explicit,
+		Title:    "updated",
+		Content:  "content",
+		Status:   storage.StatusInProgress,
+		Priority: storage.PriorityHigh,
+		Metadata: map[string]string{"key": "value"},
+	})
+	if err != nil {
+		t.Fatalf("UpsertTask() of existing task error = %v", err)
+	}
+	got := taskByID(t, s, explicit)
+	if got.Title != "updated" || got.Content != "content" || got.Status != storage.StatusInProgress ||
+		got.Priority != storage.PriorityHigh || got.Metadata["key"] != "value" || len(got.Tags) != 0 {
+		t.Errorf("TaskById() after UpsertTask = %+v, want all fields replaced", got)
+	}
+	if got.Rank != old.Rank {
+		t.Errorf("Rank after UpsertTask = %v, want unchanged %v", got.Rank, old.Rank)
+	}
+}
+
+func testPartialUpdateTask(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	author := addUser(t, s, "author")
+	id := addTask(t, s, storage.Task{Title: "title", Content: "content"})
+
+	title := "new title"
+	patchTask(t, s, id, storage.TaskPatch{Title: &title, AuthorID: &author})
+	got := taskByID(t, s, id)
+	if got.Title != title || got.AuthorID != author || got.Content != "content" {
+		t.Errorf("TaskById() after PartialUpdateTask = %+v, want only title and author changed", got)
+	}
+
+	err := s.PartialUpdateTask(ctx, id, storage.TaskPatch{})
+	if err != nil {
+		t.Fatalf("PartialUpdateTask() with empty patch error = %v", err)
+	}
+	if again := taskByID(t, s, id); again.Title != got.Title || again.Content != got.Content {
+		t.Errorf("TaskById() after empty patch = %+v, want %+v", again, got)
+	}
+}
+
+func testImportTasks(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	var input []storage.Task
+	for i := range 100 {
+		input = append(input, storage.Task{
+			Title:    fmt.Sprintf("task %d", i),
+			Priority: storage.PriorityHigh,
+			Metadata: map[string]string{"n": fmt.Sprint(i)},
+			Tags:     []string{"imported"},
+		})
+	}
+
+	err := s.ImportTasks(ctx, input)
+	if err != nil {
+		t.Fatalf("ImportTasks() error = %v", err)
+	}
+	got, err := s.Tasks(ctx)
+	if err != nil {
+		t.Fatalf("Tasks() error = %v", err)
+	}
+	if len(got) != len(input) {
+		t.Fatalf("Tasks() returned %d tasks, want %d", len(got), len(input))
+	}
+	for i, task := range got {
+		want := input[i]
+		if task.Title != want.Title || task.Priority != want.Priority ||
+			task.Metadata["n"] != want.Metadata["n"] || !slices.Equal(task.Tags, want.Tags) {
+			t.Errorf("imported task %d = %+v, want %+v", i, task, want)
+		}
+	}
+}
+
+func testVersionConflict(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	id := addTask(t, s, storage.Task{Title: "title"})
+
+	// два клиента читают одну версию задачи
+	first := taskByID(t, s, id)
+	second := taskByID(t, s, id)
+
+	first.Title = "first"
+	err := s.UpdateTask(ctx, first)
+	if err != nil {
+		t.Fatalf("first UpdateTask() error = %v", err)
+	}
+	second.Title = "second"
+	err = s.UpdateTask(ctx, second)
+	if !errors.Is(err, storage.ErrVersionConflict) {
+		t.Fatalf("second UpdateTask() error = %v, want ErrVersionConflict", err)
+	}
+
+	got := taskByID(t, s, id)
+	if got.Title != "first" || got.Version != first.Version+1 {
+		t.Errorf("TaskById() = %+v, want first update with version %d", got, first.Version+1)
+	}
+
+	// после повторного чтения обновление проходит
+	got.Title = "second"
+	err = s.UpdateTask(ctx, got)
+	if err != nil {
+		t.Fatalf("UpdateTask() with fresh version error = %v", err)
+	}
+
+	// частичное обновление тоже увеличивает версию
+	before := taskByID(t, s, id)
+	content := "content"
+	patchTask(t, s, id, storage.TaskPatch{Content: &content})
+	after := taskByID(t, s, id)
+	if after.Version != before.Version+1 {
+		t.Errorf("Version after PartialUpdateTask = %d, want %d", after.Version, before.Version+1)
+	}
+	err = s.UpdateTask(ctx, before)
+	if !errors.Is(err, storage.ErrVersionConflict) {
+		t.Errorf("UpdateTask() after PartialUpdateTask error = %v, want ErrVersionConflict", err)
+	}
+
+	err = s.UpdateTask(ctx, storage.Task{ID: 1000, Title: "missing"})
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("UpdateTask() of missing task error = %v, want ErrNotFound", err)
+	}
+}
+
+func testBulkUpdates(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	user := addUser(t, s, "user")
+	var tasks []int
+	for i := range 4 {
+		tasks = append(tasks, addTask(t, s, storage.Task{Title: fmt.Sprintf("task %d", i)}))
+	}
+
+	// несуществующие и повторяющиеся ID не учитываются
+	input := []int{tasks[0], tasks[2], 1_000_000, tasks[2]}
+	n, err := s.UpdateTasksAssignee(ctx, input, user)
+	if err != nil {
+		t.Fatalf("UpdateTasksAssignee() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("UpdateTasksAssignee() = %d, want 2", n)
+	}
+	n, err = s.UpdateTasksStatus(ctx, input, storage.StatusDone)
+	if err != nil {
+		t.Fatalf("UpdateTasksStatus() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("UpdateTasksStatus() = %d, want 2", n)
+	}
+
+	for _, id := range tasks {
+		task := taskByID(t, s, id)
+		updated := id == tasks[0] || id == tasks[2]
+		if (task.AssignedID == user) != updated {
+			t.Errorf("task %d AssignedID = %d, updated %v", id, task.AssignedID, updated)
+		}
+		if (task.Status == storage.StatusDone) != updated {
+			t.Errorf("task %d Status = %v, updated %v", id, task.Status, updated)
+		}
+	}
+
+	n, err = s.UpdateTasksAssignee(ctx, nil, user)
+	if err != nil {
+		t.Fatalf("UpdateTasksAssignee(nil) error = %v", err)
+	}
+	if n != 0 {
+		t.Errorf("UpdateTasksAssignee(nil) = %d, want 0", n)
+	}
+}
+
+func testTasksByRank(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	before := time.Now().Add(-time.Minute).UnixMilli()
+	a := addTask(t, s, storage.Task{Title: "a"})
+	b := addTask(t, s, storage.Task{Title: "b"})
+	c := addTask(t, s, storage.Task{Title: "c"})
+	after := time.Now().Add(time.Minute).UnixMilli()
+
+	// новые задачи получают ранг по времени создания и идут в конце
+	if rank := taskByID(t, s, a).Rank; rank < float64(before) || rank > float64(after) {
+		t.Errorf("new task Rank = %v, want current Unix time in milliseconds", rank)
+	}
+	got, err := s.TasksByRank(ctx)
+	if err != nil {
+		t.Fatalf("TasksByRank() error = %v", err)
+	}
+	if want := []int{a, b, c}; !slices.Equal(ids(got), want) {
+		t.Errorf("TasksByRank() = %v, want %v", ids(got), want)
+	}
+
+	for id, rank := range map[int]float64{a: 1, b: 2, c: 3} {
+		if err := s.UpdateTaskRank(ctx, id, rank); err != nil {
+			t.Fatalf("UpdateTaskRank() error = %v", err)
+		}
+	}
+	// ранг между рангами a и b ставит c между ними
+	err = s.UpdateTaskRank(ctx, c, 1.5)
+	if err != nil {
+		t.Fatalf("UpdateTaskRank() error = %v", err)
+	}
+	got, err = s.TasksByRank(ctx)
+	if err != nil {
+		t.Fatalf("TasksByRank() error = %v", err)
+	}
+	if want := []int{a, c, b}; !slices.Equal(ids(got), want) {
+		t.Errorf("TasksByRank() after move = %v, want %v", ids(got), want)
+	}
+
+	// UpdateTask не изменяет ранг; равные ранги - по возрастанию ID
+	task := taskByID(t, s, b)
+	task.Title = "b updated"
+	task.Rank = 0
+	err = s.UpdateTask(ctx, task)
+	if err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if rank := taskByID(t, s, b).Rank; rank != 2 {
+		t.Errorf("Rank after UpdateTask = %v, want 2", rank)
+	}
+	err = s.UpdateTaskRank(ctx, a, 2)
+	if err != nil {
+		t.Fatalf("UpdateTaskRank() error = %v", err)
+	}
+	got, err = s.TasksByRank(ctx)
+	if err != nil {
+		t.Fatalf("TasksByRank() error = %v", err)
+	}
+	if want := []int{c, a, b}; !slices.Equal(ids(got), want) {
+		t.Errorf("TasksByRank() with equal ranks = %v, want %v", ids(got), want)
+	}
+
+	err = s.UpdateTaskRank(ctx, 1_000_000, 1)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("UpdateTaskRank(missing) error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,57 @@
+package storagetest
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"testing"
+)
+
+func testTimeEntries(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	alice := addUser(t, s, "alice")
+	bob := addUser(t, s, "bob")
+	task := addTask(t, s, storage.Task{Title: "task"})
+	other := addTask(t, s, storage.Task{Title: "other"})
+
+	for _, e := range []storage.TimeEntry{
+		{TaskID: task, UserID: alice, Minutes: 30, Note: "review"},
+		{TaskID: task, UserID: bob, Minutes: 45},
+		{TaskID: other, UserID: alice, Minutes: 15},
+	} {
+		_, err := s.LogTime(ctx, e)
+		if err != nil {
+			t.Fatalf("LogTime() error = %v", err)
+		}
+	}
+
+	total, err := s.TotalMinutesByTask(ctx, task)
+	if err != nil {
+		t.Fatalf("TotalMinutesByTask() error = %v", err)
+	}
+	if total != 75 {
+		t.Errorf("TotalMinutesByTask() = %d, want 75", total)
+	}
+	total, err = s.TotalMinutesByTask(ctx, addTask(t, s, storage.Task{Title: "untracked"}))
+	if err != nil {
+		t.Fatalf("TotalMinutesByTask() error = %v", err)
+	}
+	if total != 0 {
+		t.Errorf("TotalMinutesByTask() of untracked task = %d, want 0", total)
+	}
+
+	byTask, err := s.TimeEntriesByTask(ctx, task)
+	if err != nil {
+		t.Fatalf("TimeEntriesByTask() error = %v", err)
+	}
+	if len(byTask) != 2 || byTask[0].Note != "review" || byTask[0].LoggedAt == 0 || byTask[1].UserID != bob {
+		t.Errorf("TimeEntriesByTask() = %+v", byTask)
+	}
+
+	byUser, err := s.TimeEntriesByUser(ctx, alice)
+	if err != nil {
+		t.Fatalf("TimeEntriesByUser() error = %v", err)
+	}
+	if len(byUser) != 2 || byUser[0].TaskID != task || byUser[1].TaskID != other {
+		t.Errorf("TimeEntriesByUser() = %+v, want entries for tasks %d and %d", byUser, task, other)
+	}
+}
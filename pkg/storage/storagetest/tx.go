@@ -0,0 +1,116 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"testing"
+)
+
+// beginTx начинает транзакцию.
+func beginTx(t *testing.T, s storage.Interface) storage.TxStorage {
+	t.Helper()
+	tx, err := s.BeginTx(context.Background())
+	if err != nil {
+		t.Fatalf("BeginTx() error = %v", err)
+	}
+	return tx
+}
+
+func testTransactions(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+
+	tx := beginTx(t, s)
+	discarded := addTask(t, tx, storage.Task{Title: "discarded"})
+	if err := tx.Rollback(ctx); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	if _, err := s.TaskById(ctx, discarded); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("TaskById() after Rollback error = %v, want ErrNotFound", err)
+	}
+
+	tx = beginTx(t, s)
+	id := addTask(t, tx, storage.Task{Title: "committed"})
+	// внутри транзакции её изменения видны
+	if got := taskByID(t, tx, id); got.Title != "committed" {
+		t.Errorf("TaskById() in tx = %+v, want committed task", got)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if got := taskByID(t, s, id); got.Title != "committed" {
+		t.Errorf("TaskById() after Commit = %+v, want committed task", got)
+	}
+
+	// завершённую транзакцию нельзя завершить повторно
+	if err := tx.Commit(ctx); !errors.Is(err, storage.ErrTxClosed) {
+		t.Errorf("second Commit() error = %v, want ErrTxClosed", err)
+	}
+	if err := tx.Rollback(ctx); !errors.Is(err, storage.ErrTxClosed) {
+		t.Errorf("Rollback() after Commit error = %v, want ErrTxClosed", err)
+	}
+	if err := tx.Savepoint(ctx, "sp"); !errors.Is(err, storage.ErrTxClosed) {
+		t.Errorf("Savepoint() after Commit error = %v, want ErrTxClosed", err)
+	}
+}
+
+func testSavepoints(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+
+	tx := beginTx(t, s)
+	defer tx.Rollback(ctx)
+
+	kept := addTask(t, tx, storage.Task{Title: "kept"})
+	if err := tx.Savepoint(ctx, "sp"); err != nil {
+		t.Fatalf("Savepoint() error = %v", err)
+	}
+	undone := addTask(t, tx, storage.Task{Title: "undone"})
+	if err := tx.RollbackToSavepoint(ctx, "sp"); err != nil {
+		t.Fatalf("RollbackToSavepoint() error = %v", err)
+	}
+	if _, err := tx.TaskById(ctx, undone); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("TaskById() after RollbackToSavepoint error = %v, want ErrNotFound", err)
+	}
+	taskByID(t, tx, kept)
+
+	if err := tx.ReleaseSavepoint(ctx, "sp"); err != nil {
+		t.Fatalf("ReleaseSavepoint() error = %v", err)
+	}
+	if err := tx.RollbackToSavepoint(ctx, "sp"); err == nil {
+		t.Error("RollbackToSavepoint() after ReleaseSavepoint error = nil, want error")
+	}
+}
+
+func testAddTasksWithPartialRetry(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	system, extID := "jira", "TASK-1"
+
+	// вторая задача нарушает уникальность ссылки во внешней системе
+	tasks := []storage.Task{
+		{Title: "first", ExternalSystem: &system, ExternalID: &extID},
+		{Title: "duplicate", ExternalSystem: &system, ExternalID: &extID},
+		{Title: "third"},
+	}
+	tx := beginTx(t, s)
+	gotIDs, failed, err := storage.AddTasksWithPartialRetry(ctx, tx, tasks)
+	if err != nil {
+		tx.Rollback(ctx)
+		t.Fatalf("AddTasksWithPartialRetry() error = %v", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	if len(gotIDs) != 2 {
+		t.Fatalf("AddTasksWithPartialRetry() ids = %v, want 2 ids", gotIDs)
+	}
+	if len(failed) != 1 || failed[0].Index != 1 || !errors.Is(failed[0].Err, storage.ErrConflict) {
+		t.Errorf("AddTasksWithPartialRetry() failed = %v, want item 1 with ErrConflict", failed)
+	}
+	if got := taskByID(t, s, gotIDs[0]); got.Title != "first" {
+		t.Errorf("TaskById(%d) = %+v, want first", gotIDs[0], got)
+	}
+	if got := taskByID(t, s, gotIDs[1]); got.Title != "third" {
+		t.Errorf("TaskById(%d) = %+v, want third", gotIDs[1], got)
+	}
+}
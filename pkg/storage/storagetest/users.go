@@ -0,0 +1,140 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"testing"
+)
+
+func testUsers(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	id := addUser(t, s, "alice")
+
+	u, err := s.UserByID(ctx, id)
+	if err != nil {
+		t.Fatalf("UserByID() error = %v", err)
+	}
+	if u.ID != id || u.Name != "alice" {
+		t.Errorf("UserByID() = %+v, want user %d alice", u, id)
+	}
+
+	err = s.UpdateUser(ctx, storage.User{ID: id, Name: "alice smith"})
+	if err != nil {
+		t.Fatalf("UpdateUser() error = %v", err)
+	}
+	users, err := s.Users(ctx)
+	if err != nil {
+		t.Fatalf("Users() error = %v", err)
+	}
+	if !slices.ContainsFunc(users, func(u storage.User) bool { return u.ID == id && u.Name == "alice smith" }) {
+		t.Errorf("Users() = %+v, want updated user %d", users, id)
+	}
+
+	err = s.DeleteUser(ctx, id)
+	if err != nil {
+		t.Fatalf("DeleteUser() error = %v", err)
+	}
+	_, err = s.UserByID(ctx, id)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("UserByID() after DeleteUser error = %v, want ErrNotFound", err)
+	}
+	err = s.UpdateUser(ctx, storage.User{ID: id, Name: "bob"})
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("UpdateUser() of deleted user error = %v, want ErrNotFound", err)
+	}
+	err = s.DeleteUser(ctx, id)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("DeleteUser() of deleted user error = %v, want ErrNotFound", err)
+	}
+}
+
+func testTasksWithUsers(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	alice := addUser(t, s, "alice")
+	bob := addUser(t, s, "bob")
+	assigned := addTask(t, s, storage.Task{Title: "assigned"})
+	patchTask(t, s, assigned, storage.TaskPatch{AuthorID: &alice, AssignedID: &bob})
+	unassigned := addTask(t, s, storage.Task{Title: "unassigned"})
+
+	got, err := s.TasksWithUsers(ctx)
+	if err != nil {
+		t.Fatalf("TasksWithUsers() error = %v", err)
+	}
+	if len(got) != 2 || got[0].ID != assigned || got[1].ID != unassigned {
+		t.Fatalf("TasksWithUsers() = %+v, want tasks %d and %d", got, assigned, unassigned)
+	}
+	if a := got[0].Author; a == nil || a.ID != alice || a.Name != "alice" {
+		t.Errorf("author of task %d = %+v, want alice", assigned, a)
+	}
+	if a := got[0].Assignee; a == nil || a.ID != bob || a.Name != "bob" {
+		t.Errorf("assignee of task %d = %+v, want bob", assigned, a)
+	}
+	// пользователь по умолчанию не считается назначенным
+	if got[1].Author != nil || got[1].Assignee != nil {
+		t.Errorf("users of task %d = %+v, %+v, want nil", unassigned, got[1].Author, got[1].Assignee)
+	}
+}
+
+func testRoles(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	alice := addUser(t, s, "alice")
+	bob := addUser(t, s, "bob")
+	carol := addUser(t, s, "carol")
+
+	// новый пользователь получает роль RoleViewer
+	role, err := s.RoleOfUser(ctx, alice)
+	if err != nil {
+		t.Fatalf("RoleOfUser() error = %v", err)
+	}
+	if role != storage.RoleViewer {
+		t.Errorf("RoleOfUser() of new user = %v, want RoleViewer", role)
+	}
+
+	for _, a := range []struct {
+		user int
+		role storage.Role
+	}{
+		{alice, storage.RoleAdmin},
+		{bob, storage.RoleEditor},
+		{carol, storage.RoleAdmin},
+	} {
+		err = s.AssignRole(ctx, a.user, a.role)
+		if err != nil {
+			t.Fatalf("AssignRole() error = %v", err)
+		}
+	}
+	if role, err = s.RoleOfUser(ctx, bob); err != nil || role != storage.RoleEditor {
+		t.Errorf("RoleOfUser() = %v, %v, want RoleEditor", role, err)
+	}
+
+	tests := []struct {
+		role storage.Role
+		want []int
+	}{
+		{storage.RoleAdmin, []int{alice, carol}},
+		{storage.RoleEditor, []int{bob}},
+	}
+	for _, tt := range tests {
+		users, err := s.UsersWithRole(ctx, tt.role)
+		if err != nil {
+			t.Fatalf("UsersWithRole() error = %v", err)
+		}
+		if !slices.Equal(userIDs(users), tt.want) {
+			t.Errorf("UsersWithRole(%v) = %v, want %v", tt.role, userIDs(users), tt.want)
+		}
+		for _, u := range users {
+			if u.UserRole != tt.role {
+				t.Errorf("UsersWithRole(%v) returned %+v", tt.role, u)
+			}
+		}
+	}
+
+	if err := s.AssignRole(ctx, 1000, storage.RoleAdmin); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("AssignRole() of missing user error = %v, want ErrNotFound", err)
+	}
+	if _, err := s.RoleOfUser(ctx, 1000); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("RoleOfUser() of missing user error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,62 @@
+package storagetest
+
+import (
+	"context"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"testing"
+)
+
+func testWatchers(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	alice := addUser(t, s, "alice")
+	bob := addUser(t, s, "bob")
+	id := addTask(t, s, storage.Task{Title: "task"})
+	other := addTask(t, s, storage.Task{Title: "other"})
+
+	// повторная подписка не является ошибкой
+	for _, w := range [][2]int{{bob, id}, {alice, id}, {alice, id}, {bob, other}} {
+		err := s.WatchTask(ctx, w[0], w[1])
+		if err != nil {
+			t.Fatalf("WatchTask(%d, %d) error = %v", w[0], w[1], err)
+		}
+	}
+
+	watchers, err := s.WatchersOfTask(ctx, id)
+	if err != nil {
+		t.Fatalf("WatchersOfTask() error = %v", err)
+	}
+	if want := []int{alice, bob}; !slices.Equal(userIDs(watchers), want) {
+		t.Fatalf("WatchersOfTask() = %v, want %v", userIDs(watchers), want)
+	}
+	if watchers[0].Name != "alice" || watchers[1].Name != "bob" {
+		t.Errorf("WatchersOfTask() = %+v, want full users", watchers)
+	}
+
+	err = s.UnwatchTask(ctx, alice, id)
+	if err != nil {
+		t.Fatalf("UnwatchTask() error = %v", err)
+	}
+	// отмена несуществующей подписки не является ошибкой
+	err = s.UnwatchTask(ctx, alice, other)
+	if err != nil {
+		t.Errorf("UnwatchTask() of not watched task error = %v, want nil", err)
+	}
+
+	for _, tt := range []struct {
+		taskID int
+		want   []int
+	}{
+		{id, []int{bob}},
+		{other, []int{bob}},
+		{addTask(t, s, storage.Task{Title: "unwatched"}), nil},
+	} {
+		got, err := s.WatchersOfTask(ctx, tt.taskID)
+		if err != nil {
+			t.Fatalf("WatchersOfTask() error = %v", err)
+		}
+		if !slices.Equal(userIDs(got), tt.want) {
+			t.Errorf("WatchersOfTask(%d) = %v, want %v", tt.taskID, userIDs(got), tt.want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"testing"
+)
+
+func testWebhooks(t *testing.T, s storage.Interface) {
+	ctx := context.Background()
+	w := storage.Webhook{
+		URL:    "https://example.com/hook",
+		Secret: "secret",
+		Events: []string{"task.created", "task.deleted"},
+		Active: true,
+	}
+	id, err := s.RegisterWebhook(ctx, w)
+	if err != nil {
+		t.Fatalf("RegisterWebhook() error = %v", err)
+	}
+	other, err := s.RegisterWebhook(ctx, storage.Webhook{URL: "http://localhost:8080/", Events: []string{"task.updated"}})
+	if err != nil {
+		t.Fatalf("RegisterWebhook() error = %v", err)
+	}
+
+	got, err := s.WebhookByID(ctx, id)
+	if err != nil {
+		t.Fatalf("WebhookByID() error = %v", err)
+	}
+	w.ID = id
+	if got.ID != w.ID || got.URL != w.URL || got.Secret != w.Secret || got.Active != w.Active || !slices.Equal(got.Events, w.Events) {
+		t.Errorf("WebhookByID() = %+v, want %+v", *got, w)
+	}
+
+	all, err := s.Webhooks(ctx)
+	if err != nil {
+		t.Fatalf("Webhooks() error = %v", err)
+	}
+	var webhookIDs []int
+	for _, w := range all {
+		webhookIDs = append(webhookIDs, w.ID)
+	}
+	if want := []int{id, other}; !slices.Equal(webhookIDs, want) {
+		t.Errorf("Webhooks() IDs = %v, want %v", webhookIDs, want)
+	}
+
+	w.Events = []string{"comment.added"}
+	w.Active = false
+	err = s.UpdateWebhook(ctx, w)
+	if err != nil {
+		t.Fatalf("UpdateWebhook() error = %v", err)
+	}
+	got, err = s.WebhookByID(ctx, id)
+	if err != nil {
+		t.Fatalf("WebhookByID() error = %v", err)
+	}
+	if got.Active || !slices.Equal(got.Events, w.Events) {
+		t.Errorf("WebhookByID() after update = %+v, want %+v", *got, w)
+	}
+
+	for _, url := range []string{"", "ftp://example.com", "example.com/hook", "http://"} {
+		_, err = s.RegisterWebhook(ctx, storage.Webhook{URL: url})
+		if !errors.Is(err, storage.ErrInvalidArgument) {
+			t.Errorf("RegisterWebhook(%q) error = %v, want ErrInvalidArgument", url, err)
+		}
+	}
+	err = s.UpdateWebhook(ctx, storage.Webhook{ID: id, URL: "invalid"})
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("UpdateWebhook(invalid URL) error = %v, want ErrInvalidArgument", err)
+	}
+	err = s.UpdateWebhook(ctx, storage.Webhook{ID: 1_000_000, URL: w.URL})
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("UpdateWebhook(missing) error = %v, want ErrNotFound", err)
+	}
+
+	err = s.DeleteWebhook(ctx, id)
+	if err != nil {
+		t.Fatalf("DeleteWebhook() error = %v", err)
+	}
+	_, err = s.WebhookByID(ctx, id)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("WebhookByID() after delete error = %v, want ErrNotFound", err)
+	}
+	err = s.DeleteWebhook(ctx, id)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("DeleteWebhook() twice error = %v, want ErrNotFound", err)
+	}
+}
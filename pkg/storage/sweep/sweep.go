@@ -0,0 +1,66 @@
+package sweep
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"time"
+)
+
+// StaleTaskSweeper периодически находит "зависшие" задачи
+// (см. storage.Interface.StaleTasks) и отмечает их MarkTaskStale.
+type StaleTaskSweeper struct {
+	store      storage.Interface
+	staleAfter time.Duration
+	now        func() time.Time
+}
+
+// Конструктор, принимает хранилище и время, после которого
+// незакрытая задача считается "зависшей".
+func New(store storage.Interface, staleAfter time.Duration) *StaleTaskSweeper {
+	s := StaleTaskSweeper{
+		store:      store,
+		staleAfter: staleAfter,
+		now:        time.Now,
+	}
+	return &s
+}
+
+// Run выполняет Sweep с заданным интервалом до отмены ctx.
+// Ошибки отдельных проходов не прерывают работу: задачи
+// будут отмечены при следующем проходе.
+func (s *StaleTaskSweeper) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.Sweep(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep выполняет один проход: отмечает все "зависшие" задачи,
+// ещё не отмеченные ранее. Возвращает объединённые ошибки отметки.
+func (s *StaleTaskSweeper) Sweep(ctx context.Context) error {
+	tasks, err := s.store.StaleTasks(ctx, int64(s.staleAfter/time.Second), s.now().Unix())
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, t := range tasks {
+		if t.Stale {
+			continue
+		}
+		err = s.store.MarkTaskStale(ctx, t.ID)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,123 @@
+package sweep
+
+import (
+	"context"
+	"errors"
+	"skillfactory/30.8.1/pkg/storage"
+	"skillfactory/30.8.1/pkg/storage/mock"
+	"testing"
+	"time"
+)
+
+// newStore возвращает заглушку с "зависшими" задачами 1 и 2;
+// задача 2 уже отмечена.
+func newStore() *mock.Mock {
+	return &mock.Mock{
+		StaleTasksFunc: func(ctx context.Context, staleAfterSeconds int64, now int64) ([]storage.Task, error) {
+			return []storage.Task{{ID: 1}, {ID: 2, Stale: true}}, nil
+		},
+	}
+}
+
+func TestSweep(t *testing.T) {
+	store := newStore()
+	s := New(store, time.Hour)
+	s.now = func() time.Time { return time.Unix(10_000, 0) }
+
+	err := s.Sweep(context.Background())
+	if err != nil {
+		t.Fatalf("Sweep() error = %v", err)
+	}
+
+	calls := store.CallsTo("StaleTasks")
+	if len(calls) != 1 {
+		t.Fatalf("StaleTasks calls = %d, want 1", len(calls))
+	}
+	if after, now := calls[0].Args[0], calls[0].Args[1]; after != int64(3600) || now != int64(10_000) {
+		t.Errorf("StaleTasks(%v, %v), want (3600, 10000)", after, now)
+	}
+	// уже отмеченная задача пропускается
+	calls = store.CallsTo("MarkTaskStale")
+	if len(calls) != 1 || calls[0].Args[0] != 1 {
+		t.Errorf("MarkTaskStale calls = %v, want one for task 1", calls)
+	}
+}
+
+func TestSweepErrors(t *testing.T) {
+	errDB := errors.New("db error")
+
+	store := newStore()
+	store.StaleTasksFunc = func(ctx context.Context, staleAfterSeconds int64, now int64) ([]storage.Task, error) {
+		return nil, errDB
+	}
+	if err := New(store, time.Hour).Sweep(context.Background()); !errors.Is(err, errDB) {
+		t.Errorf("Sweep() error = %v, want %v", err, errDB)
+	}
+
+	// задача удалена между запросами: это не ошибка
+	store = newStore()
+	store.MarkTaskStaleFunc = func(ctx context.Context, taskID int) error {
+		return storage.ErrNotFound
+	}
+	if err := New(store, time.Hour).Sweep(context.Background()); err != nil {
+		t.Errorf("Sweep() error = %v, want nil for ErrNotFound", err)
+	}
+
+	store = newStore()
+	store.MarkTaskStaleFunc = func(ctx context.Context, taskID int) error {
+		return errDB
+	}
+	if err := New(store, time.Hour).Sweep(context.Background()); !errors.Is(err, errDB) {
+		t.Errorf("Sweep() error = %v, want %v", err, errDB)
+	}
+}
+
+func TestRun(t *testing.T) {
+	store := newStore()
+	s := New(store, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx, 10*time.Millisecond) }()
+
+	// проход на каждом тике
+	deadline := time.Now().Add(5 * time.Second)
+	for len(store.CallsTo("MarkTaskStale")) < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("MarkTaskStale calls = %d, want at least 3", len(store.CallsTo("MarkTaskStale")))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Run() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not stop after cancel")
+	}
+	if n, m := len(store.CallsTo("StaleTasks")), len(store.CallsTo("MarkTaskStale")); n != m {
+		t.Errorf("StaleTasks calls = %d, MarkTaskStale calls = %d, want equal", n, m)
+	}
+}
+
+// Ошибка прохода не останавливает Run.
+func TestRunContinuesAfterError(t *testing.T) {
+	store := newStore()
+	store.StaleTasksFunc = func(ctx context.Context, staleAfterSeconds int64, now int64) ([]storage.Task, error) {
+		return nil, errors.New("db error")
+	}
+	s := New(store, time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := s.Run(ctx, 10*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Run() error = %v, want context.DeadlineExceeded", err)
+	}
+	if n := len(store.CallsTo("StaleTasks")); n < 2 {
+		t.Errorf("StaleTasks calls = %d, want at least 2", n)
+	}
+}
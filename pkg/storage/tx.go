@@ -0,0 +1,39 @@
+package storage
+
+import "context"
+
+// AddTasksWithPartialRetry создаёт задачи в транзакции tx, оборачивая
+// создание каждой задачи в точку сохранения. Если задачу создать
+// не удалось, отменяются только её изменения, и обработка продолжается.
+//
+// Возвращает ID созданных задач и ошибки задач, которые создать
+// не удалось. Ошибка err возвращается, если не удалось выполнить
+// команду точки сохранения; транзакцию в этом случае следует откатить.
+func AddTasksWithPartialRetry(ctx context.Context, tx TxStorage, tasks []Task) (ids []int, failed []IndexedError, err error) {
+	const savepoint = "add_task"
+
+	for i, t := range tasks {
+		err = tx.Savepoint(ctx, savepoint)
+		if err != nil {
+			return ids, failed, err
+		}
+
+		id, addErr := tx.AddTask(ctx, t)
+		if addErr != nil {
+			failed = append(failed, IndexedError{Index: i, Err: addErr})
+			err = tx.RollbackToSavepoint(ctx, savepoint)
+			if err != nil {
+				return ids, failed, err
+			}
+		} else {
+			ids = append(ids, id)
+		}
+
+		err = tx.ReleaseSavepoint(ctx, savepoint)
+		if err != nil {
+			return ids, failed, err
+		}
+	}
+
+	return ids, failed, nil
+}
@@ -0,0 +1,167 @@
+// Пакет webhook доставляет события шины (пакет events)
+// на зарегистрированные веб-хуки.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"skillfactory/30.8.1/pkg/events"
+	"skillfactory/30.8.1/pkg/storage"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Заголовок с подписью тела запроса.
+const SignatureHeader = "X-Signature-256"
+
+// Количество повторных попыток доставки после неудачной.
+const maxRetries = 3
+
+// payload - тело запроса веб-хука.
+type payload struct {
+	Type    string      `json:"type"`
+	TaskID  int         `json:"task_id"`
+	UserID  int         `json:"user_id"`
+	Payload interface{} `json:"payload"`
+}
+
+// Dispatcher получает события из шины и отправляет их POST-запросом
+// на активные веб-хуки, подписанные на тип события. Неудачная доставка
+// (ошибка сети или ответ не 2xx) повторяется до трёх раз с удвоением
+// паузы. Доставка выполняется в отдельных горутинах и не задерживает
+// публикацию события.
+type Dispatcher struct {
+	store   storage.Interface
+	bus     *events.Bus
+	client  *http.Client
+	backoff time.Duration // пауза перед первой повторной попыткой
+
+	mu  sync.Mutex // защищает ctx и запуск доставок после отмены ctx
+	ctx context.Context
+	wg  sync.WaitGroup
+}
+
+// Конструктор, принимает хранилище веб-хуков, шину событий,
+// HTTP-клиент и паузу перед первой повторной попыткой доставки.
+func New(store storage.Interface, bus *events.Bus, client *http.Client, backoff time.Duration) *Dispatcher {
+	d := Dispatcher{
+		store:   store,
+		bus:     bus,
+		client:  client,
+		backoff: backoff,
+	}
+	return &d
+}
+
+// Run доставляет события до отмены ctx. После отмены дожидается
+// завершения начатых доставок (их запросы также отменяются).
+func (d *Dispatcher) Run(ctx context.Context) error {
+	d.mu.Lock()
+	d.ctx = ctx
+	d.mu.Unlock()
+	handler := func(e events.Event) { d.dispatch(e) }
+	d.bus.Subscribe(events.AllEvents, handler)
+
+	<-ctx.Done()
+	d.bus.Unsubscribe(events.AllEvents, handler)
+	// dispatch, получивший блокировку после отмены ctx, не запускает
+	// доставок, поэтому после неё счётчик wg больше не растёт
+	d.mu.Lock()
+	d.mu.Unlock()
+	d.wg.Wait()
+	return ctx.Err()
+}
+
+// dispatch запускает доставку события на подходящие веб-хуки.
+// Если список веб-хуков не удалось получить, событие не доставляется.
+func (d *Dispatcher) dispatch(e events.Event) {
+	d.mu.Lock()
+	ctx := d.ctx
+	d.mu.Unlock()
+
+	webhooks, err := d.store.Webhooks(ctx)
+	if err != nil {
+		return
+	}
+	body, err := json.Marshal(payload{
+		Type:    e.Type,
+		TaskID:  e.TaskID,
+		UserID:  e.UserID,
+		Payload: e.Payload,
+	})
+	if err != nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if ctx.Err() != nil {
+		return
+	}
+	for _, w := range webhooks {
+		if !w.Active || !slices.Contains(w.Events, e.Type) {
+			continue
+		}
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			d.deliver(w, body)
+		}()
+	}
+}
+
+// deliver отправляет тело на веб-хук, повторяя попытки при неудаче.
+func (d *Dispatcher) deliver(w storage.Webhook, body []byte) error {
+	delay := d.backoff
+	for i := 0; ; i++ {
+		err := d.send(w, body)
+		if err == nil || i == maxRetries {
+			return err
+		}
+
+		select {
+		case <-d.ctx.Done():
+			return d.ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// send выполняет одну попытку доставки.
+func (d *Dispatcher) send(w storage.Webhook, body []byte) error {
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(w.Secret, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %d: unexpected status %s", w.ID, resp.Status)
+	}
+	return nil
+}
+
+// Sign возвращает подпись тела запроса для заголовка X-Signature-256:
+// "sha256=" и HMAC-SHA256 тела с секретом в шестнадцатеричном виде.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,288 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"skillfactory/30.8.1/pkg/events"
+	"skillfactory/30.8.1/pkg/storage"
+	"skillfactory/30.8.1/pkg/storage/memory"
+	"skillfactory/30.8.1/pkg/storage/mock"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// request - запрос, полученный тестовым сервером.
+type request struct {
+	signature string
+	body      []byte
+}
+
+// newServer возвращает сервер, который сохраняет запросы и отвечает
+// статусом из status для каждой попытки (затем 200).
+func newServer(t *testing.T, status ...int) (*httptest.Server, func() []request) {
+	var mu sync.Mutex
+	var reqs []request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("request %s with Content-Type %q, want POST application/json", r.Method, r.Header.Get("Content-Type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		defer mu.Unlock()
+		reqs = append(reqs, request{signature: r.Header.Get(SignatureHeader), body: body})
+		if n := len(reqs); n <= len(status) {
+			w.WriteHeader(status[n-1])
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, func() []request {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]request(nil), reqs...)
+	}
+}
+
+// newDispatcher возвращает Dispatcher, готовый к вызову dispatch,
+// и хранилище для регистрации веб-хуков.
+func newDispatcher(t *testing.T) (*Dispatcher, *memory.Storage) {
+	store := memory.New()
+	d := New(store, events.NewBus(), http.DefaultClient, time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	d.ctx = ctx
+	return d, store
+}
+
+func register(t *testing.T, store storage.Interface, w storage.Webhook) int {
+	t.Helper()
+	id, err := store.RegisterWebhook(context.Background(), w)
+	if err != nil {
+		t.Fatalf("RegisterWebhook() error = %v", err)
+	}
+	return id
+}
+
+func TestDispatch(t *testing.T) {
+	srv, requests := newServer(t)
+	d, store := newDispatcher(t)
+	register(t, store, storage.Webhook{URL: srv.URL, Secret: "secret", Events: []string{events.TaskCreated}, Active: true})
+	// неактивный и подписанный на другие события веб-хуки
+	register(t, store, storage.Webhook{URL: srv.URL, Events: []string{events.TaskCreated}})
+	register(t, store, storage.Webhook{URL: srv.URL, Events: []string{events.TaskDeleted}, Active: true})
+
+	d.dispatch(events.Event{Type: events.TaskCreated, TaskID: 7, UserID: 3, Payload: map[string]string{"title": "task"}})
+	d.wg.Wait()
+
+	reqs := requests()
+	if len(reqs) != 1 {
+		t.Fatalf("delivered %d requests, want 1", len(reqs))
+	}
+	var got struct {
+		Type    string            `json:"type"`
+		TaskID  int               `json:"task_id"`
+		UserID  int               `json:"user_id"`
+		Payload map[string]string `json:"payload"`
+	}
+	err := json.Unmarshal(reqs[0].body, &got)
+	if err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if got.Type != events.TaskCreated || got.TaskID != 7 || got.UserID != 3 || got.Payload["title"] != "task" {
+		t.Errorf("payload = %+v, want task.created for task 7 by user 3", got)
+	}
+	if want := Sign("secret", reqs[0].body); reqs[0].signature != want {
+		t.Errorf("%s = %q, want %q", SignatureHeader, reqs[0].signature, want)
+	}
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '{}' | openssl dgst -sha256 -hmac secret
+	want := "sha256=77325902caca812dc259733aacd046b73817372c777b8d95b402647474516e13"
+	if got := Sign("secret", []byte("{}")); got != want {
+		t.Errorf("Sign() = %q, want %q", got, want)
+	}
+	if Sign("other", []byte("{}")) == want {
+		t.Error("Sign() does not depend on secret")
+	}
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name   string
+		status []int
+		want   int
+	}{
+		{"success", nil, 1},
+		{"two failures", []int{500, 502}, 3},
+		{"three retries", []int{500, 500, 500, 500, 500}, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, requests := newServer(t, tt.status...)
+			d, _ := newDispatcher(t)
+
+			start := time.Now()
+			err := d.deliver(storage.Webhook{ID: 1, URL: srv.URL}, []byte("{}"))
+			if failed := tt.want > len(tt.status); failed == (err != nil) {
+				t.Errorf("deliver() error = %v", err)
+			}
+			if n := len(requests()); n != tt.want {
+				t.Errorf("delivered %d requests, want %d", n, tt.want)
+			}
+			// паузы 1, 2 и 4 мс
+			if tt.want == 4 && time.Since(start) < 7*time.Millisecond {
+				t.Errorf("retries took %v, want at least 7ms", time.Since(start))
+			}
+		})
+	}
+}
+
+// roundTripFunc - http.RoundTripper из функции.
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// Событие, опубликованное одновременно с остановкой Run, не доставляется
+// после возврата из Run (проверяется с -race).
+func TestRunCancelConcurrent(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var once sync.Once
+	listed := make(chan struct{})
+	store := &mock.Mock{
+		// список веб-хуков возвращается уже после остановки Run
+		WebhooksFunc: func(context.Context) ([]storage.Webhook, error) {
+			once.Do(func() { close(listed) })
+			<-ctx.Done()
+			time.Sleep(20 * time.Millisecond)
+			return []storage.Webhook{{URL: "http://example.com", Events: []string{events.TaskCreated}, Active: true}}, nil
+		},
+	}
+	var stopped, late atomic.Bool
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if stopped.Load() {
+			late.Store(true)
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})}
+	bus := events.NewBus()
+	d := New(store, bus, client, time.Millisecond)
+
+	done := make(chan error, 1)
+	go func() {
+		err := d.Run(ctx)
+		stopped.Store(true)
+		done <- err
+	}()
+	// Run подписывается на шину асинхронно, поэтому события
+	// публикуются, пока одно из них не дойдёт до dispatch
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-listed:
+				return
+			default:
+			}
+			go bus.Publish(context.Background(), events.Event{Type: events.TaskCreated})
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	<-listed
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Run() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not stop after cancel")
+	}
+	wg.Wait()
+	time.Sleep(50 * time.Millisecond)
+	if late.Load() {
+		t.Error("delivery started after Run() returned")
+	}
+}
+
+// Отмена контекста прерывает повторные попытки.
+func TestRetryCanceled(t *testing.T) {
+	srv, requests := newServer(t, 500, 500, 500, 500)
+	d := New(memory.New(), events.NewBus(), http.DefaultClient, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	d.ctx = ctx
+
+	done := make(chan error, 1)
+	go func() { done <- d.deliver(storage.Webhook{URL: srv.URL}, []byte("{}")) }()
+	for len(requests()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("deliver() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("deliver() did not stop after cancel")
+	}
+}
+
+func TestRun(t *testing.T) {
+	var delivered atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered.Add(1)
+	}))
+	defer srv.Close()
+
+	store := memory.New()
+	register(t, store, storage.Webhook{URL: srv.URL, Events: []string{events.TaskCreated}, Active: true})
+	bus := events.NewBus()
+	d := New(store, bus, http.DefaultClient, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- d.Run(ctx) }()
+
+	// Run подписывается на шину асинхронно, поэтому событие
+	// публикуется, пока не будет доставлено
+	deadline := time.Now().Add(5 * time.Second)
+	for delivered.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("event was not delivered")
+		}
+		_ = bus.Publish(context.Background(), events.Event{Type: events.TaskCreated, TaskID: 1})
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Run() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not stop after cancel")
+	}
+
+	// после остановки события не доставляются
+	n := delivered.Load()
+	_ = bus.Publish(context.Background(), events.Event{Type: events.TaskCreated, TaskID: 2})
+	time.Sleep(20 * time.Millisecond)
+	if delivered.Load() != n {
+		t.Error("event delivered after Run() returned")
+	}
+}